@@ -1251,7 +1251,10 @@ type ProvisionCdmSinkResult {
 
   enum SignalInstanceStatus {
     OPEN
+    ACKNOWLEDGED
+    SNOOZED
     RESOLVED
+    REOPENED
     SUPPRESSED
   }
 
@@ -8828,7 +8831,7 @@ function mapConfluenceSpaceOption(record: MetadataRecord<unknown>) {
 
 const SIGNAL_STATUS_VALUES: SignalStatus[] = ["ACTIVE", "DISABLED", "DRAFT"];
 const SIGNAL_IMPL_MODE_VALUES: SignalImplMode[] = ["DSL", "CODE"];
-const SIGNAL_INSTANCE_STATUS_VALUES: SignalInstanceStatus[] = ["OPEN", "RESOLVED", "SUPPRESSED"];
+const SIGNAL_INSTANCE_STATUS_VALUES: SignalInstanceStatus[] = ["OPEN", "ACKNOWLEDGED", "SNOOZED", "RESOLVED", "REOPENED", "SUPPRESSED"];
 const SIGNAL_SEVERITY_VALUES: SignalSeverity[] = ["INFO", "WARNING", "ERROR", "CRITICAL"];
 
 function coerceSignalStatus(values?: string[] | null): SignalStatus[] | undefined {
@@ -13,7 +13,7 @@ const __dirname = path.dirname(fileURLToPath(import.meta.url));
 
 // Signal types (moved from deleted signals/types.ts)
 export type SignalStatus = "ACTIVE" | "DISABLED" | "DRAFT";
-export type SignalInstanceStatus = "OPEN" | "RESOLVED" | "SUPPRESSED";
+export type SignalInstanceStatus = "OPEN" | "ACKNOWLEDGED" | "SNOOZED" | "RESOLVED" | "REOPENED" | "SUPPRESSED";
 export type SignalSeverity = "INFO" | "WARNING" | "ERROR" | "CRITICAL";
 export type SignalImplMode = "DSL" | "CODE";
 
@@ -2,7 +2,7 @@
  * Signal types - TypeScript types for SignalService
  */
 export type SignalStatus = "ACTIVE" | "DISABLED" | "DRAFT";
-export type SignalInstanceStatus = "OPEN" | "RESOLVED" | "SUPPRESSED";
+export type SignalInstanceStatus = "OPEN" | "ACKNOWLEDGED" | "SNOOZED" | "RESOLVED" | "REOPENED" | "SUPPRESSED";
 export type SignalSeverity = "INFO" | "WARNING" | "ERROR" | "CRITICAL";
 export type SignalImplMode = "DSL" | "CODE";
 
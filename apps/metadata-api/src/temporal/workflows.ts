@@ -160,6 +160,18 @@ type GoBrainActivities = {
     stagingProviderId?: string | null;
     checkpoint?: Record<string, unknown> | null;
   }): Promise<void>;
+  ClassifyDocuments(input: {
+    artifactId: string;
+    sinkEndpointId: string;
+    datasetSlug: string;
+    runId?: string | null;
+    sourceFamily?: string | null;
+    tenantId?: string | null;
+    endpointConfig?: Record<string, unknown> | null;
+    stageRef?: string | null;
+    batchRefs?: string[];
+    stagingProviderId?: string | null;
+  }): Promise<void>;
 };
 
 const goIngestionActivities = proxyActivities<GoIngestionActivities>({
@@ -411,6 +423,26 @@ export async function postIngestionWorkflow(args: {
       error: error instanceof Error ? error.message : String(error),
     });
   }
+  try {
+    await goBrainActivities.ClassifyDocuments({
+      artifactId: args.artifactId,
+      sinkEndpointId: args.sinkEndpointId,
+      datasetSlug: args.datasetSlug,
+      runId: args.runId,
+      sourceFamily: args.sourceFamily ?? null,
+      tenantId: args.tenantId ?? null,
+      endpointConfig: args.sinkEndpointConfig ?? null,
+      stageRef: args.stageRef ?? null,
+      batchRefs: args.batchRefs ?? undefined,
+      stagingProviderId: args.stagingProviderId ?? null,
+    });
+    log.info("post-ingestion-epp-finished", { artifactId: args.artifactId });
+  } catch (error) {
+    log.warn("post-ingestion-epp-failed", {
+      artifactId: args.artifactId,
+      error: error instanceof Error ? error.message : String(error),
+    });
+  }
 }
 
 function trimSlashes(value: string): string {
@@ -40,8 +40,10 @@ func main() {
 	w.RegisterActivity(acts.ExtractSignals)
 	w.RegisterActivity(acts.ExtractInsights)
 	w.RegisterActivity(acts.BuildClusters)
+	w.RegisterActivity(acts.ReembedVectors)
+	w.RegisterActivity(acts.ClassifyDocuments)
 
-	log.Printf("Registered brain activities: IndexArtifact, ExtractSignals, ExtractInsights, BuildClusters")
+	log.Printf("Registered brain activities: IndexArtifact, ExtractSignals, ExtractInsights, BuildClusters, ReembedVectors, ClassifyDocuments")
 
 	if err := w.Run(worker.InterruptCh()); err != nil {
 		log.Fatalf("Worker failed: %v", err)
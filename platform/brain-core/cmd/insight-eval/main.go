@@ -0,0 +1,66 @@
+// Command insight-eval runs two versions of an insight skill over a fixture
+// set of records and writes a JSON report scoring schema validity, repairs,
+// sentiment, signal types and escalation against the fixtures' expectations.
+// The LLM is configured as for the worker (INSIGHT_* and LLM_* variables).
+//
+//	insight-eval -skill issue-insight.v1 -a 1.0.0 -b 1.1.0 -fixtures insights/fixtures/issue-insight.yaml -out report.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/nucleus/brain-core/internal/activities"
+)
+
+func main() {
+	dir := flag.String("dir", getEnv("INSIGHT_SKILL_DIR", "insights"), "skill directory")
+	skill := flag.String("skill", "", "skill ID (required)")
+	versionA := flag.String("a", "", "baseline skill version (required)")
+	versionB := flag.String("b", "", "candidate skill version (required)")
+	fixturesPath := flag.String("fixtures", "", "fixture records YAML (required)")
+	outPath := flag.String("out", "", "report path (default stdout)")
+	flag.Parse()
+	if *skill == "" || *versionA == "" || *versionB == "" || *fixturesPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fixtures, err := activities.LoadInsightFixtures(*fixturesPath)
+	if err != nil {
+		log.Fatalf("fixtures: %v", err)
+	}
+	report, err := activities.CompareInsightSkillVersions(context.Background(), *dir, *skill, *versionA, *versionB, fixtures)
+	if err != nil {
+		log.Fatalf("compare: %v", err)
+	}
+
+	out := os.Stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("report: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := activities.WriteInsightComparison(out, report); err != nil {
+		log.Fatalf("report: %v", err)
+	}
+
+	for _, s := range []activities.InsightVersionScore{report.A, report.B} {
+		fmt.Fprintf(os.Stderr, "%-12s score=%.3f valid=%d/%d firstPass=%d deadLettered=%d sentiment=%.3f signalF1=%.3f escalation=%.3f calls=%d\n",
+			s.Version, s.Score, s.Valid, s.Records, s.FirstPass, s.DeadLettered, s.SentimentAccuracy, s.SignalF1, s.EscalationAccuracy, s.LLMCalls)
+	}
+	fmt.Fprintf(os.Stderr, "winner: %s\n", report.Winner)
+}
+
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
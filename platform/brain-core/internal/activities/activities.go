@@ -18,6 +18,7 @@ import (
 	"github.com/nucleus/ucl-core/pkg/endpoint"
 	"github.com/nucleus/ucl-core/pkg/orchestration"
 	"github.com/nucleus/ucl-core/pkg/staging"
+	"github.com/nucleus/store-core/pkg/entity"
 	"github.com/nucleus/store-core/pkg/vectorstore"
 )
 
@@ -962,31 +963,7 @@ func (a *Activities) IndexArtifact(ctx context.Context, req IndexArtifactRequest
 		}
 	}
 	// If no checkpoint provided, try to load persisted checkpoint keyed by profile+dataset.
-	profileID := req.ProfileID
-	if profileID == "" {
-		// Prefer CDM profile when cdmModelId is present.
-		if req.CdmModelID != "" {
-			profileID = fmt.Sprintf("cdm.%s.v1", strings.TrimPrefix(req.CdmModelID, "cdm."))
-		} else {
-			// Fallback by source family if available
-			switch strings.ToLower(req.SourceFamily) {
-			case "github":
-				if strings.Contains(strings.ToLower(req.DatasetSlug), "issue") {
-					profileID = "source.github.issues.v1"
-				} else {
-					profileID = "source.github.code.v1"
-				}
-			case "jira":
-				profileID = "source.jira.issues.v1"
-			case "confluence":
-				profileID = "source.confluence.pages.v1"
-			case "onedrive":
-				profileID = "source.onedrive.docs.v1"
-			default:
-				profileID = "source.generic.v1"
-			}
-		}
-	}
+	profileID := resolveProfileID(req)
 	if len(cp) == 0 {
 		key := makeCheckpointKey(profileID, req.DatasetSlug)
 		if persisted, err := loadCheckpointKV(ctx, tenantID, projectID, key); err == nil && persisted != nil {
@@ -1045,9 +1022,14 @@ func (a *Activities) IndexArtifact(ctx context.Context, req IndexArtifactRequest
 	var contents []string
 	var kbEvents []kbEvent
 	var kbSeq int64
+	var accounts []entity.Account
+	resolveAccounts := getenv("ENTITY_DATABASE_URL", "") != ""
 	for iter.Next() {
 		rec := iter.Value()
 		recordsRead++
+		if resolveAccounts {
+			accounts = append(accounts, entity.AccountsFromRecord(req.SourceFamily, identityPayload(rec))...)
+		}
 		if key, ok := rec["objectKey"].(string); ok {
 			lastKey = key
 		}
@@ -1136,6 +1118,16 @@ func (a *Activities) IndexArtifact(ctx context.Context, req IndexArtifactRequest
 
 	// Embed and upsert
 	if len(normalized) > 0 {
+		embedder, err := getEmbeddingProvider()
+		if err != nil {
+			logger.Error("[IndexArtifact-debug] getEmbeddingProvider failed", "error", err.Error())
+			if regClient != nil {
+				regClient.markIndexFailed(ctx, req.ArtifactID, err.Error())
+			}
+			return nil, err
+		}
+		model := embedder.ModelName()
+
 		// Content hash check: skip entries with unchanged content to save API tokens
 		var needsEmbedding []vectorstore.Entry
 		var needsEmbeddingContents []string
@@ -1144,9 +1136,10 @@ func (a *Activities) IndexArtifact(ctx context.Context, req IndexArtifactRequest
 
 		for i, entry := range normalized {
 			currentHash := hashContent(contents[i])
-			existingHash, _ := loadEmbeddingHash(ctx, tenantID, projectID, profileID, entry.NodeID)
+			existingHash, existingModel, _ := loadEmbeddingHash(ctx, tenantID, projectID, profileID, entry.NodeID)
 
-			if existingHash != "" && existingHash == currentHash {
+			// Checkpoints saved before models were recorded count as current
+			if existingHash != "" && existingHash == currentHash && (existingModel == "" || existingModel == model) {
 				logger.Debug("[IndexArtifact] skipping unchanged content", "nodeId", entry.NodeID)
 				skippedCount++
 				continue
@@ -1169,16 +1162,22 @@ func (a *Activities) IndexArtifact(ctx context.Context, req IndexArtifactRequest
 			logger.Info("[IndexArtifact] all entries unchanged, skipping embedding API call")
 		} else {
 			logger.Info("[IndexArtifact-debug] starting embedding", "normalizedCount", len(needsEmbedding), "contentsCount", len(needsEmbeddingContents))
-			embedder, err := getEmbeddingProvider()
-			if err != nil {
-				logger.Error("[IndexArtifact-debug] getEmbeddingProvider failed", "error", err.Error())
+			storeModel, storeDim := vectorStoreModel(ctx)
+			if err := checkEmbeddingModel(embedder, storeModel, storeDim); err != nil {
 				if regClient != nil {
 					regClient.markIndexFailed(ctx, req.ArtifactID, err.Error())
 				}
 				return nil, err
 			}
 			logger.Info("[IndexArtifact-debug] calling EmbedText", "contentsCount", len(needsEmbeddingContents))
-			embeddings, err := embedder.EmbedText("", needsEmbeddingContents) // use default model
+			dim := storeDim
+			if dim == 0 {
+				dim = embedder.Dimension()
+			}
+			cache := kvEmbeddingCache{tenantID: tenantID, projectID: projectID}
+			cache.maybeSweep()
+			emb := newEmbedder(embedder, dim, cache)
+			embeddings, stats, err := emb.Embed(ctx, needsEmbeddingContents)
 			if err != nil {
 				logger.Error("[IndexArtifact-debug] EmbedText failed", "error", err.Error())
 				if regClient != nil {
@@ -1186,13 +1185,14 @@ func (a *Activities) IndexArtifact(ctx context.Context, req IndexArtifactRequest
 				}
 				return nil, err
 			}
-			logger.Info("[IndexArtifact-debug] EmbedText succeeded", "embeddingsCount", len(embeddings), "model", embedder.ModelName())
+			logger.Info("[IndexArtifact-debug] EmbedText succeeded", "embeddingsCount", len(embeddings), "model", model,
+				"cached", stats.Cached, "requests", stats.Requests, "retries", stats.Retries, "truncated", stats.Truncated)
 			for i := range needsEmbedding {
 				needsEmbedding[i].Embedding = embeddings[i]
 				if needsEmbedding[i].Metadata == nil {
 					needsEmbedding[i].Metadata = make(map[string]any)
 				}
-				needsEmbedding[i].Metadata["embeddingModel"] = embedder.ModelName()
+				needsEmbedding[i].Metadata["embeddingModel"] = model
 			}
 			logger.Info("[IndexArtifact-debug] getting vector store")
 			client, err := getVectorStore()
@@ -1215,7 +1215,7 @@ func (a *Activities) IndexArtifact(ctx context.Context, req IndexArtifactRequest
 
 			// Save content hashes for successfully embedded entries
 			for i, entry := range needsEmbedding {
-				saveEmbeddingHash(ctx, tenantID, projectID, profileID, entry.NodeID, contentHashes[i])
+				saveEmbeddingHash(ctx, tenantID, projectID, profileID, entry.NodeID, contentHashes[i], model, len(entry.Embedding))
 			}
 			logger.Info("[IndexArtifact] saved content hashes", "count", len(needsEmbedding))
 		}
@@ -1241,6 +1241,16 @@ func (a *Activities) IndexArtifact(ctx context.Context, req IndexArtifactRequest
 	if err := saveCheckpointKV(ctx, tenantID, projectID, key, newCheckpoint); err != nil {
 		logger.Warn("checkpoint-save-failed", "err", err)
 	}
+	if recordsRead > 0 {
+		if err := invalidateGraphRAGCache(ctx, tenantID, req.DatasetSlug, req.RunID); err != nil {
+			logger.Warn("graphrag-cache-invalidate-failed", "err", err)
+		}
+	}
+	events, err := resolveIdentities(ctx, tenantID, projectID, req, accounts, &kbSeq)
+	if err != nil {
+		logger.Warn("identity-resolution-failed", "err", err)
+	}
+	kbEvents = append(kbEvents, events...)
 	eventsPath, snapPath := saveKBEvents(ctx, tenantID, projectID, req.DatasetSlug, req.RunID, kbEvents, kbSeq)
 	if regClient != nil {
 		regClient.markIndexed(ctx, req.ArtifactID, map[string]any{
@@ -1268,6 +1278,33 @@ func (a *Activities) IndexArtifact(ctx context.Context, req IndexArtifactRequest
 	}, nil
 }
 
+// resolveProfileID returns the vector profile for the request's records.
+func resolveProfileID(req IndexArtifactRequest) string {
+	if req.ProfileID != "" {
+		return req.ProfileID
+	}
+	// Prefer CDM profile when cdmModelId is present.
+	if req.CdmModelID != "" {
+		return fmt.Sprintf("cdm.%s.v1", strings.TrimPrefix(req.CdmModelID, "cdm."))
+	}
+	// Fallback by source family if available
+	switch strings.ToLower(req.SourceFamily) {
+	case "github":
+		if strings.Contains(strings.ToLower(req.DatasetSlug), "issue") {
+			return "source.github.issues.v1"
+		}
+		return "source.github.code.v1"
+	case "jira":
+		return "source.jira.issues.v1"
+	case "confluence":
+		return "source.confluence.pages.v1"
+	case "onedrive":
+		return "source.onedrive.docs.v1"
+	default:
+		return "source.generic.v1"
+	}
+}
+
 // mergeCheckpoints merges incoming checkpoint with updates, ensuring flat structure.
 // It specifically handles recursive 'cursor' nesting which was a bug.
 // This function recursively flattens any depth of nested cursors (e.g., cursor.cursor.cursor...).
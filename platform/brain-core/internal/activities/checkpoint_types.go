@@ -23,12 +23,20 @@ type IndexerCheckpoint struct {
 }
 
 // EmbeddingHashCheckpoint stores content hash for an embedding entry.
-// Used to skip re-embedding unchanged content.
+// Used to skip re-embedding unchanged content. Stored per content hash with
+// the vector itself, it is also the embedding cache entry.
 type EmbeddingHashCheckpoint struct {
 	// ContentHash is the SHA256 of the embedded content text
 	ContentHash string `json:"contentHash"`
 	// SavedAt is when this hash was saved (RFC3339)
 	SavedAt string `json:"savedAt"`
+	// Model is the embedding model the content was embedded with (empty on
+	// checkpoints written before models were recorded)
+	Model string `json:"model,omitempty"`
+	// Dim is the embedding length
+	Dim int `json:"dim,omitempty"`
+	// Embedding is the vector, on cache entries only
+	Embedding []float32 `json:"embedding,omitempty"`
 }
 
 // InsightSignatureCheckpoint stores insight caching signature.
@@ -67,9 +75,11 @@ func NewIndexerCheckpoint() *IndexerCheckpoint {
 }
 
 // NewEmbeddingHashCheckpoint creates a new embedding hash checkpoint.
-func NewEmbeddingHashCheckpoint(contentHash string) *EmbeddingHashCheckpoint {
+func NewEmbeddingHashCheckpoint(contentHash, model string, dim int) *EmbeddingHashCheckpoint {
 	return &EmbeddingHashCheckpoint{
 		ContentHash: contentHash,
 		SavedAt:     time.Now().UTC().Format(time.RFC3339),
+		Model:       model,
+		Dim:         dim,
 	}
 }
@@ -0,0 +1,415 @@
+package activities
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// clusterPoint is a vector entry reduced to what clustering needs. vec is
+// unit length, so cosine similarity is a dot product.
+type clusterPoint struct {
+	nodeID    string
+	vec       []float32
+	updatedAt time.Time
+}
+
+// seedCentroid is a centroid carried over from the previous run; clusters
+// grown from it keep its ID. weight (the cluster's previous size) damps how
+// far the first mini-batches can move it.
+type seedCentroid struct {
+	id     string
+	vec    []float32
+	weight int
+}
+
+// kmeansConfig tunes miniBatchKMeans.
+type kmeansConfig struct {
+	k          int     // target cluster count; raised to the number of seeds
+	batchSize  int     // points per mini-batch
+	iterations int     // maximum mini-batches
+	tolerance  float64 // stop once no centroid moves further than this
+	seed       int64
+}
+
+// kmeansCluster is one cluster of a miniBatchKMeans result. id is the seed's
+// ID, or empty for a cluster that did not exist in the previous run.
+type kmeansCluster struct {
+	id       string
+	centroid []float32
+	members  []int // indexes into the points
+}
+
+// miniBatchKMeans clusters points with spherical mini-batch k-means (Sculley,
+// 2010): centroids start from the seeds, the rest are picked k-means++ style
+// from a sample, then each mini-batch pulls its points' centroids towards
+// them with a per-centroid learning rate. A final pass assigns every point
+// to its nearest centroid; clusters left empty are dropped.
+func miniBatchKMeans(points []clusterPoint, seeds []seedCentroid, cfg kmeansConfig) []*kmeansCluster {
+	if len(points) == 0 {
+		return nil
+	}
+	dim := len(points[0].vec)
+	rng := rand.New(rand.NewSource(cfg.seed))
+
+	var clusters []*kmeansCluster
+	var counts []int
+	for _, s := range seeds {
+		if len(s.vec) == dim {
+			clusters = append(clusters, &kmeansCluster{id: s.id, centroid: normalized(s.vec)})
+			counts = append(counts, s.weight)
+		}
+	}
+	k := cfg.k
+	if k < len(clusters) {
+		k = len(clusters)
+	}
+	if k > len(points) {
+		k = len(points)
+	}
+	clusters = append(clusters, kmeansPlusPlus(points, clusters, k-len(clusters), rng)...)
+	if len(clusters) == 0 {
+		return nil
+	}
+
+	batchSize := cfg.batchSize
+	if batchSize <= 0 || batchSize > len(points) {
+		batchSize = len(points)
+	}
+	counts = append(counts, make([]int, len(clusters)-len(counts))...)
+	batch := make([]int, batchSize)
+	nearestOf := make([]int, batchSize)
+	for iter := 0; iter < cfg.iterations; iter++ {
+		for i := range batch {
+			batch[i] = rng.Intn(len(points))
+			nearestOf[i], _ = nearestCentroid(points[batch[i]].vec, clusters)
+		}
+		before := make([][]float32, len(clusters))
+		for c := range clusters {
+			before[c] = append([]float32(nil), clusters[c].centroid...)
+		}
+		for i, p := range batch {
+			c := nearestOf[i]
+			counts[c]++
+			eta := float32(1) / float32(counts[c])
+			for d, x := range points[p].vec {
+				clusters[c].centroid[d] = (1-eta)*clusters[c].centroid[d] + eta*x
+			}
+		}
+		var shift float64
+		for c := range clusters {
+			clusters[c].centroid = normalized(clusters[c].centroid)
+			if s := 1 - float64(dot(before[c], clusters[c].centroid)); s > shift {
+				shift = s
+			}
+		}
+		if shift < cfg.tolerance {
+			break
+		}
+	}
+
+	for i, p := range points {
+		c, _ := nearestCentroid(p.vec, clusters)
+		clusters[c].members = append(clusters[c].members, i)
+	}
+	out := clusters[:0]
+	for _, c := range clusters {
+		if len(c.members) > 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// kmeansPlusPlus picks n more centroids from a sample of the points, each
+// with probability proportional to its squared distance from the nearest
+// centroid chosen so far.
+func kmeansPlusPlus(points []clusterPoint, existing []*kmeansCluster, n int, rng *rand.Rand) []*kmeansCluster {
+	if n <= 0 {
+		return nil
+	}
+	sample := points
+	if limit := 20 * (len(existing) + n); len(points) > limit {
+		sample = make([]clusterPoint, limit)
+		for i, j := range rng.Perm(len(points))[:limit] {
+			sample[i] = points[j]
+		}
+	}
+	chosen := append([]*kmeansCluster(nil), existing...)
+	var added []*kmeansCluster
+	dist := make([]float64, len(sample))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	for len(added) < n {
+		var total float64
+		for i, p := range sample {
+			if len(chosen) > 0 {
+				d := 1 - float64(dot(p.vec, chosen[len(chosen)-1].centroid))
+				if d < dist[i] {
+					dist[i] = d
+				}
+			}
+			if math.IsInf(dist[i], 1) {
+				total += 1
+			} else {
+				total += dist[i] * dist[i]
+			}
+		}
+		pick := rng.Intn(len(sample))
+		if total > 0 && len(chosen) > 0 {
+			r := rng.Float64() * total
+			for i := range sample {
+				r -= dist[i] * dist[i]
+				if r <= 0 {
+					pick = i
+					break
+				}
+			}
+		}
+		c := &kmeansCluster{centroid: append([]float32(nil), sample[pick].vec...)}
+		dist[pick] = 0
+		chosen = append(chosen, c)
+		added = append(added, c)
+	}
+	return added
+}
+
+func nearestCentroid(v []float32, clusters []*kmeansCluster) (int, float32) {
+	best, bestSim := 0, float32(-2)
+	for c, cl := range clusters {
+		if s := dot(v, cl.centroid); s > bestSim {
+			best, bestSim = c, s
+		}
+	}
+	return best, bestSim
+}
+
+// neighbourEdge is a similarity edge between two points, a < b.
+type neighbourEdge struct {
+	a, b  int
+	score float32
+}
+
+// neighbourGraph finds each point's nearest neighbours above threshold,
+// searching only the members of its nprobe nearest clusters (an IVF-style
+// approximate search), so the cost grows with cluster size rather than
+// with the square of the dataset. Points are searched in parallel. Edges are
+// deduplicated and sorted by score.
+func neighbourGraph(points []clusterPoint, clusters []*kmeansCluster, nprobe, perPoint int, threshold float32) []neighbourEdge {
+	if nprobe < 1 {
+		nprobe = 1
+	}
+	if nprobe > len(clusters) {
+		nprobe = len(clusters)
+	}
+	clusterOf := make([]int, len(points))
+	for c, cl := range clusters {
+		for _, i := range cl.members {
+			clusterOf[i] = c
+		}
+	}
+
+	found := make([][]neighbourEdge, len(points))
+	workers := runtime.GOMAXPROCS(0)
+	chunk := (len(points) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := 0; lo < len(points); lo += chunk {
+		hi := lo + chunk
+		if hi > len(points) {
+			hi = len(points)
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			order := make([]int, len(clusters))
+			sims := make([]float32, len(clusters))
+			for i := lo; i < hi; i++ {
+				p := points[i]
+				for c := range clusters {
+					order[c] = c
+					sims[c] = dot(p.vec, clusters[c].centroid)
+				}
+				sort.Slice(order, func(x, y int) bool { return sims[order[x]] > sims[order[y]] })
+
+				var cands []neighbourEdge
+				for _, c := range order[:nprobe] {
+					for _, j := range clusters[c].members {
+						// A pair within one cluster is found from its lower index
+						if j == i || c == clusterOf[i] && j < i {
+							continue
+						}
+						if s := dot(p.vec, points[j].vec); s >= threshold {
+							cands = append(cands, neighbourEdge{a: i, b: j, score: s})
+						}
+					}
+				}
+				sort.Slice(cands, func(x, y int) bool { return cands[x].score > cands[y].score })
+				if perPoint > 0 && len(cands) > perPoint {
+					cands = cands[:perPoint]
+				}
+				found[i] = cands
+			}
+		}(lo, hi)
+	}
+	wg.Wait()
+
+	seen := make(map[[2]int]bool)
+	var edges []neighbourEdge
+	for _, cands := range found {
+		for _, e := range cands {
+			if e.a > e.b {
+				e.a, e.b = e.b, e.a
+			}
+			if !seen[[2]int{e.a, e.b}] {
+				seen[[2]int{e.a, e.b}] = true
+				edges = append(edges, e)
+			}
+		}
+	}
+	sort.Slice(edges, func(x, y int) bool {
+		if edges[x].score != edges[y].score {
+			return edges[x].score > edges[y].score
+		}
+		if edges[x].a != edges[y].a {
+			return edges[x].a < edges[y].a
+		}
+		return edges[x].b < edges[y].b
+	})
+	return edges
+}
+
+// clusterQuality summarises a clustering run.
+type clusterQuality struct {
+	Silhouette        float64            `json:"silhouette"`        // mean over the sample, -1..1
+	SilhouetteSample  int                `json:"silhouetteSample"`  // points the silhouette was computed over
+	ClusterSilhouette map[string]float64 `json:"clusterSilhouette"` // mean per cluster ID, sampled clusters only
+	Sizes             sizeDistribution   `json:"sizes"`
+}
+
+// sizeDistribution describes cluster sizes.
+type sizeDistribution struct {
+	Clusters   int     `json:"clusters"`
+	Points     int     `json:"points"`
+	Min        int     `json:"min"`
+	Max        int     `json:"max"`
+	Mean       float64 `json:"mean"`
+	P50        int     `json:"p50"`
+	P90        int     `json:"p90"`
+	Singletons int     `json:"singletons"`
+}
+
+// measureClusters computes the size distribution and the silhouette
+// coefficient (cosine distance) over a random sample of at most sampleSize
+// points; the exact silhouette is quadratic in the dataset. ids holds the
+// final ID of each cluster.
+func measureClusters(points []clusterPoint, clusters []*kmeansCluster, ids []string, sampleSize int, seed int64) clusterQuality {
+	q := clusterQuality{ClusterSilhouette: map[string]float64{}}
+	sizes := make([]int, len(clusters))
+	for c, cl := range clusters {
+		sizes[c] = len(cl.members)
+		q.Sizes.Points += sizes[c]
+		if sizes[c] == 1 {
+			q.Sizes.Singletons++
+		}
+	}
+	sort.Ints(sizes)
+	if n := len(sizes); n > 0 {
+		q.Sizes.Clusters = n
+		q.Sizes.Min = sizes[0]
+		q.Sizes.Max = sizes[n-1]
+		q.Sizes.Mean = float64(q.Sizes.Points) / float64(n)
+		q.Sizes.P50 = sizes[(n-1)/2]
+		q.Sizes.P90 = sizes[(n-1)*9/10]
+	}
+	if len(clusters) < 2 {
+		return q
+	}
+
+	// Sample points, remembering each one's cluster.
+	type sampled struct{ point, cluster int }
+	var sample []sampled
+	for c, cl := range clusters {
+		for _, i := range cl.members {
+			sample = append(sample, sampled{i, c})
+		}
+	}
+	if sampleSize > 0 && len(sample) > sampleSize {
+		rng := rand.New(rand.NewSource(seed))
+		rng.Shuffle(len(sample), func(i, j int) { sample[i], sample[j] = sample[j], sample[i] })
+		sample = sample[:sampleSize]
+	}
+	perCluster := make([]int, len(clusters))
+	for _, s := range sample {
+		perCluster[s.cluster]++
+	}
+
+	var total float64
+	sums := make([]float64, len(clusters))
+	clusterTotal := make([]float64, len(clusters))
+	for _, s := range sample {
+		for c := range sums {
+			sums[c] = 0
+		}
+		for _, o := range sample {
+			if o.point != s.point {
+				sums[o.cluster] += 1 - float64(dot(points[s.point].vec, points[o.point].vec))
+			}
+		}
+		var sil float64
+		if own := perCluster[s.cluster] - 1; own > 0 {
+			a := sums[s.cluster] / float64(own)
+			b := math.Inf(1)
+			for c, n := range perCluster {
+				if c != s.cluster && n > 0 {
+					b = math.Min(b, sums[c]/float64(n))
+				}
+			}
+			if m := math.Max(a, b); m > 0 && !math.IsInf(b, 1) {
+				sil = (b - a) / m
+			}
+		}
+		total += sil
+		clusterTotal[s.cluster] += sil
+	}
+	q.SilhouetteSample = len(sample)
+	if len(sample) > 0 {
+		q.Silhouette = total / float64(len(sample))
+	}
+	for c, n := range perCluster {
+		if n > 0 {
+			q.ClusterSilhouette[ids[c]] = clusterTotal[c] / float64(n)
+		}
+	}
+	return q
+}
+
+func dot(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var s float32
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
+
+func normalized(v []float32) []float32 {
+	var n float64
+	for _, x := range v {
+		n += float64(x) * float64(x)
+	}
+	out := make([]float32, len(v))
+	if n == 0 {
+		return out
+	}
+	inv := float32(1 / math.Sqrt(n))
+	for i, x := range v {
+		out[i] = x * inv
+	}
+	return out
+}
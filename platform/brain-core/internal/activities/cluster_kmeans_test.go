@@ -0,0 +1,115 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/nucleus/store-core/pkg/vectorstore"
+)
+
+// topicEntries returns n entries per topic, each a noisy copy of the topic's
+// axis in 8 dimensions.
+func topicEntries(topics, n int, rng *rand.Rand) []vectorstore.Entry {
+	var out []vectorstore.Entry
+	for t := 0; t < topics; t++ {
+		for i := 0; i < n; i++ {
+			emb := make([]float32, 8)
+			for d := range emb {
+				emb[d] = float32(rng.NormFloat64() * 0.1)
+			}
+			emb[t] += 1
+			out = append(out, vectorstore.Entry{
+				TenantID: "t1", ProjectID: "p1", ProfileID: "issues", DatasetSlug: "jira",
+				NodeID: fmt.Sprintf("issue:%d-%03d", t, i), Embedding: emb,
+			})
+		}
+	}
+	return out
+}
+
+func TestClusteringStreamsAndKeepsIDsStable(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	entries := topicEntries(3, 120, rng)
+	// A near-verbatim copy of issue 0-000
+	dup := entries[0]
+	dup.NodeID = "issue:dup"
+	dup.Embedding = append([]float32(nil), dup.Embedding...)
+	dup.Embedding[7] += 0.01
+	entries = append(entries, dup)
+
+	store := vectorstore.NewMemoryStore(8)
+	if err := store.UpsertEntries(entries); err != nil {
+		t.Fatal(err)
+	}
+	pages := 0
+	points, _, err := collectClusterPoints(context.Background(), store, vectorstore.QueryFilter{TenantID: "t1", DatasetSlug: "jira"}, 50, func(int) { pages++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != len(entries) || pages != 8 {
+		t.Fatalf("expected all %d entries over 8 pages, got %d over %d", len(entries), len(points), pages)
+	}
+
+	cfg := kmeansConfig{k: 3, batchSize: 64, iterations: 50, tolerance: 1e-4, seed: clusterSeed("jira")}
+	clusters := miniBatchKMeans(points, nil, cfg)
+	if len(clusters) != 3 {
+		t.Fatalf("expected 3 clusters, got %d", len(clusters))
+	}
+	for _, c := range clusters {
+		topic := points[c.members[0]].nodeID[:len("issue:0")]
+		for _, m := range c.members {
+			if id := points[m].nodeID; id != "issue:dup" && id[:len(topic)] != topic {
+				t.Fatalf("cluster mixes topics: %s and %s", topic, id)
+			}
+		}
+	}
+
+	ids := []string{"c0", "c1", "c2"}
+	q := measureClusters(points, clusters, ids, 200, cfg.seed)
+	if q.Silhouette < 0.5 || q.SilhouetteSample != 200 || len(q.ClusterSilhouette) != 3 {
+		t.Errorf("expected well separated clusters, got %+v", q)
+	}
+	if q.Sizes.Clusters != 3 || q.Sizes.Points != len(points) || q.Sizes.Min < 120 || q.Sizes.Max > 121 {
+		t.Errorf("unexpected size distribution: %+v", q.Sizes)
+	}
+
+	edges := neighbourGraph(points, clusters, 1, 3, 0.99)
+	if len(edges) == 0 {
+		t.Fatal("expected the duplicate pair in the neighbour graph")
+	}
+	if top := edges[0]; points[top.a].nodeID != "issue:0-000" || points[top.b].nodeID != "issue:dup" {
+		t.Errorf("expected the duplicate pair to score highest, got %s-%s %.4f", points[top.a].nodeID, points[top.b].nodeID, top.score)
+	}
+
+	// The next run, with a few new issues, starts from the cached centroids
+	// and keeps their IDs.
+	var seeds []seedCentroid
+	for i, c := range clusters {
+		seeds = append(seeds, seedCentroid{id: ids[i], vec: c.centroid, weight: len(c.members)})
+	}
+	more := topicEntries(3, 130, rand.New(rand.NewSource(2)))
+	if err := store.UpsertEntries(more[120:130]); err != nil {
+		t.Fatal(err)
+	}
+	points, _, err = collectClusterPoints(context.Background(), store, vectorstore.QueryFilter{TenantID: "t1"}, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := miniBatchKMeans(points, seeds, cfg)
+	if len(next) != 3 {
+		t.Fatalf("expected 3 clusters on the second run, got %d", len(next))
+	}
+	for _, c := range next {
+		var before *kmeansCluster
+		for i, s := range seeds {
+			if s.id == c.id {
+				before = clusters[i]
+			}
+		}
+		if before == nil || dot(before.centroid, c.centroid) < 0.99 {
+			t.Fatalf("cluster %q did not keep its ID", c.id)
+		}
+	}
+}
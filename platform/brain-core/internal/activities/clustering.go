@@ -3,6 +3,7 @@ package activities
 import (
 	"context"
 	"crypto/sha1"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -29,6 +30,7 @@ type clusterStats struct {
 	edgeDegree  int
 	memberHash  string
 	topRelated  []edgeSummary
+	silhouette  float64
 }
 
 type edgeSummary struct {
@@ -54,14 +56,21 @@ type kbEvent struct {
 	Seq         int64  `json:"seq"`
 	RunID       string `json:"runId"`
 	DatasetSlug string `json:"datasetSlug"`
-	Op          string `json:"op"` // upsert_node / upsert_edge
+	Op          string `json:"op"` // upsert_node / upsert_edge / delete_node / delete_edge
 	Kind        string `json:"kind"`
 	ID          string `json:"id"`
 	Hash        string `json:"hash"`
 	At          string `json:"at"`
 }
 
-// BuildClusters groups entities using vector embeddings (greedy centroid assignment) and writes cluster nodes/edges to KG.
+// BuildClusters groups a dataset's entities by embedding and writes cluster
+// nodes/edges to KG. Every entry of the dataset is streamed from the vector
+// store and clustered with mini-batch k-means, seeded from the previous
+// run's centroid cache so surviving clusters keep their IDs. Nearest
+// neighbours found within the closest clusters become RELATED edges (those
+// above CLUSTER_DUPLICATE_THRESHOLD are flagged as likely duplicates), and
+// the run's silhouette and cluster size distribution are recorded with the
+// artifact's counters and in KV.
 func (a *Activities) BuildClusters(ctx context.Context, req IndexArtifactRequest) error {
 	logger := activity.GetLogger(ctx)
 	if strings.TrimSpace(req.SinkEndpointID) == "" {
@@ -77,341 +86,288 @@ func (a *Activities) BuildClusters(ctx context.Context, req IndexArtifactRequest
 		project = getenv("METADATA_DEFAULT_PROJECT", "global")
 	}
 
-	var since *time.Time
-	if ts, ok := req.Checkpoint["lastUpdatedAt"].(string); ok && ts != "" {
-		if t, err := time.Parse(time.RFC3339, ts); err == nil {
-			since = &t
-		}
-	}
-
-	client, err := getVectorStore()
-	if err != nil {
-		return err
-	}
-	entries, err := client.ListEntries(vectorstore.QueryFilter{
+	points, latestUpdated, err := loadClusterPoints(ctx, vectorstore.QueryFilter{
 		TenantID:       tenant,
 		ProjectID:      project,
 		DatasetSlug:    req.DatasetSlug,
 		SourceFamily:   req.SourceFamily,
 		SinkEndpointID: req.SinkEndpointID,
-		SinceUpdatedAt: since,
-		Limit:          300,
-	}, 300)
+	})
 	if err != nil {
 		return err
 	}
-	if len(entries) == 0 {
+	if len(points) == 0 {
 		logger.Info("cluster-skip-no-entries", "dataset", req.DatasetSlug)
 		return nil
 	}
+	dim := len(points[0].vec)
 
-	var latestUpdated time.Time
-	for _, e := range entries {
-		if e.UpdatedAt != nil && e.UpdatedAt.After(latestUpdated) {
-			latestUpdated = *e.UpdatedAt
-		}
-	}
-
-	simThreshold := getEnvFloat("CLUSTER_SIM_THRESHOLD", 0.35)
 	graphThreshold := getEnvFloat("CLUSTER_GRAPH_THRESHOLD", 0.45)
-	maxClusterSize := getEnvInt("CLUSTER_MAX_SIZE", 5)
-	if maxClusterSize < 2 {
-		maxClusterSize = 5
+	dupThreshold := getEnvFloat("CLUSTER_DUPLICATE_THRESHOLD", 0.92)
+	cfg := kmeansConfig{
+		k:          getEnvInt("CLUSTER_K", 0),
+		batchSize:  getEnvInt("CLUSTER_BATCH_SIZE", 1024),
+		iterations: getEnvInt("CLUSTER_ITERATIONS", 100),
+		tolerance:  1e-4,
+		seed:       clusterSeed(req.DatasetSlug),
 	}
-
-	type centroid struct {
-		vec []float32
-		n   int
-		id  string
+	if cfg.k <= 0 {
+		// Rule-of-thumb k; the neighbour search scans about nprobe*n/k points each
+		cfg.k = int(math.Ceil(math.Sqrt(float64(len(points)) / 2)))
 	}
-	var clusters []centroid
-	assignments := make(map[string]string)
-	clusterSeq := 0
 
-	for _, e := range entries {
-		if len(e.Embedding) == 0 {
-			continue
-		}
-		bestIdx := -1
-		bestSim := float32(-1)
-		for idx, c := range clusters {
-			if c.n >= maxClusterSize {
-				continue
-			}
-			sim := cosineSim(e.Embedding, c.vec)
-			if sim > bestSim {
-				bestSim = sim
-				bestIdx = idx
-			}
-		}
-		if bestIdx >= 0 && bestSim >= simThreshold {
-			assignments[e.NodeID] = clusters[bestIdx].id
-			clusters[bestIdx].vec = avgVec(clusters[bestIdx].vec, e.Embedding, clusters[bestIdx].n+1)
-			clusters[bestIdx].n++
-		} else {
-			clusterSeq++
-			cid := fmt.Sprintf("cluster:%s:%d", req.DatasetSlug, clusterSeq)
-			clusters = append(clusters, centroid{vec: e.Embedding, n: 1, id: cid})
-			assignments[e.NodeID] = cid
+	// Seed from the previous run so clusters that survive keep their IDs
+	cacheEntries, cacheVersion, _ := loadCentroidCache(ctx, tenant, project, req.DatasetSlug)
+	var seeds []seedCentroid
+	for id, entry := range cacheEntries {
+		if len(entry.Centroid) == dim {
+			seeds = append(seeds, seedCentroid{id: id, vec: entry.Centroid, weight: entry.Size})
 		}
 	}
+	sort.Slice(seeds, func(i, j int) bool { return seeds[i].id < seeds[j].id })
+	clusters := miniBatchKMeans(points, seeds, cfg)
 
-	if len(assignments) == 0 {
-		logger.Info("cluster-skip-no-assignments", "dataset", req.DatasetSlug)
-		return nil
-	}
-
-	// Graph-based refinement: merge items that are mutually similar above graphThreshold.
-	compAssignments := make(map[string]string)
-	components, edgeMap := buildComponents(entries, graphThreshold)
-	compSeq := 0
-	for _, comp := range components {
-		if len(comp) < 2 {
-			continue
+	ids := make([]string, len(clusters))
+	clusterOf := make([]int, len(points))
+	finalClusters := make(map[string]*clusterStats)
+	cacheHits := 0
+	for c, cl := range clusters {
+		members := make([]string, len(cl.members))
+		for i, p := range cl.members {
+			members[i] = points[p].nodeID
+			clusterOf[p] = c
 		}
-		compSeq++
-		cid := fmt.Sprintf("cluster:%s:cc%d", req.DatasetSlug, compSeq)
-		for _, nodeID := range comp {
-			compAssignments[nodeID] = cid
+		cs := &clusterStats{
+			centroid:   cl.centroid,
+			size:       len(members),
+			memberIDs:  members,
+			memberHash: makeStableClusterID(req.DatasetSlug, req.SourceFamily, members),
 		}
-	}
-
-	// If components found, prefer those assignments (merging clusters).
-	if len(compAssignments) > 0 {
-		assignments = compAssignments
-		clusters = clusters[:0]
-		seenClusters := make(map[string]struct{})
-		for _, cid := range compAssignments {
-			if _, ok := seenClusters[cid]; !ok {
-				seenClusters[cid] = struct{}{}
-				clusters = append(clusters, centroid{id: cid, n: 0})
-			}
+		sid := cl.id
+		if sid == "" {
+			sid = cs.memberHash
+		} else {
+			cs.cachedAtStr = cacheEntries[sid].UpdatedAt
+			cacheHits++
 		}
-		for _, cid := range compAssignments {
-			for i := range clusters {
-				if clusters[i].id == cid {
-					clusters[i].n++
-				}
+		ids[c] = sid
+		var sumSim float32
+		for _, p := range cl.members {
+			s := dot(points[p].vec, cl.centroid)
+			sumSim += s
+			if s > cs.maxSim {
+				cs.maxSim = s
 			}
 		}
+		cs.avgSim = sumSim / float32(len(cl.members))
+		finalClusters[sid] = cs
 	}
 
-	// Rebuild stable IDs using member sets to keep clusters consistent across runs.
-	clusterMembers := make(map[string][]string)
-	for node, cid := range assignments {
-		clusterMembers[cid] = append(clusterMembers[cid], node)
-	}
-	stableIDs := make(map[string]string)
-	for cid, members := range clusterMembers {
-		stableIDs[cid] = makeStableClusterID(req.DatasetSlug, req.SourceFamily, members)
-	}
-
-	finalClusters := make(map[string]*clusterStats)
-
-	cacheEntries, cacheVersion, _ := loadCentroidCache(ctx, tenant, project, req.DatasetSlug)
-	cacheHits := 0
-
-	for cid, members := range clusterMembers {
-		sid := stableIDs[cid]
-		cs := finalClusters[sid]
-		if cs == nil {
-			cs = &clusterStats{}
-			finalClusters[sid] = cs
-		}
-		cs.size += len(members)
-		cs.memberIDs = append(cs.memberIDs, members...)
-		cs.memberHash = makeStableClusterID(req.DatasetSlug, req.SourceFamily, members)
-
-		// reuse cached centroid if membership unchanged and cache is fresh enough
-		if entry, ok := cacheEntries[sid]; ok && len(entry.Centroid) > 0 && len(entry.Centroid) == len(entries[0].Embedding) && entry.MemberHash == cs.memberHash && entry.Dim == len(entries[0].Embedding) {
-			// if we have no newer updates, trust cache
-			if latestUpdated.IsZero() || entry.updatedAt.After(latestUpdated) || entry.updatedAt.Equal(latestUpdated) {
-				cs.centroid = entry.Centroid
-				cs.avgSim = entry.AvgSim
-				cs.maxSim = entry.MaxSim
-				cs.cachedAtStr = entry.updatedAt.Format(time.RFC3339)
-				cs.edgeDegree = entry.EdgeDegree
-				cs.topRelated = entry.TopRelated
-				cs.memberHash = entry.MemberHash
-				cacheHits++
-				continue
-			}
+	edges := neighbourGraph(points, clusters, getEnvInt("CLUSTER_NPROBE", 2), getEnvInt("CLUSTER_NEIGHBORS", 5), graphThreshold)
+	duplicates := 0
+	topEdges := make(map[string][]edgeSummary)
+	for _, e := range edges {
+		if e.score >= dupThreshold {
+			duplicates++
 		}
-
-		// recompute centroid from members
-		count := 0
-		for _, m := range members {
-			for _, e := range entries {
-				if e.NodeID == m && len(e.Embedding) > 0 {
-					if cs.centroid == nil {
-						cs.centroid = make([]float32, len(e.Embedding))
-					}
-					cs.centroid = avgVec(cs.centroid, e.Embedding, count+1)
-					count++
-				}
-			}
+		summary := edgeSummary{Src: points[e.a].nodeID, Dst: points[e.b].nodeID, Score: e.score}
+		touched := []int{clusterOf[e.a]}
+		if clusterOf[e.b] != clusterOf[e.a] {
+			touched = append(touched, clusterOf[e.b])
 		}
-	}
-
-	// compute similarity stats to centroid
-	for _, cs := range finalClusters {
-		var sumSim float32
-		var count int
-		if len(cs.centroid) == 0 {
-			continue
-		}
-		for _, m := range cs.memberIDs {
-			for _, e := range entries {
-				if e.NodeID == m && len(e.Embedding) > 0 && len(cs.centroid) > 0 {
-					s := cosineSim(e.Embedding, cs.centroid)
-					sumSim += s
-					if s > cs.maxSim {
-						cs.maxSim = s
-					}
-					count++
-				}
-			}
+		for _, c := range touched {
+			finalClusters[ids[c]].edgeDegree++
+			topEdges[ids[c]] = append(topEdges[ids[c]], summary)
 		}
-		if count > 0 {
-			cs.avgSim = sumSim / float32(count)
+	}
+	for cid, edges := range topEdges {
+		// edges arrive sorted by score
+		if len(edges) > 5 {
+			edges = edges[:5]
 		}
+		finalClusters[cid].topRelated = edges
 	}
 
+	quality := measureClusters(points, clusters, ids, getEnvInt("CLUSTER_SILHOUETTE_SAMPLE", 1000), cfg.seed)
+	for cid, s := range quality.ClusterSilhouette {
+		finalClusters[cid].silhouette = s
+	}
+
+	logger.Info("cluster-built", "clusters", len(finalClusters), "entries", len(points), "relatedEdges", len(edges), "duplicates", duplicates,
+		"cacheHits", cacheHits, "silhouette", quality.Silhouette, "p50Size", quality.Sizes.P50, "maxSize", quality.Sizes.Max, "dataset", req.DatasetSlug)
+
 	clusterKind := "episode"
 	if req.SourceFamily != "" {
 		clusterKind = strings.ToLower(req.SourceFamily)
 	}
 
-	kgc := newKgGRPCClient()
-	defer kgc.Close()
-	if kgc == nil {
-		return nil
-	}
-
-	topEdges := make(map[string][]edgeSummary)
-	nodesTouched := len(finalClusters)
-	edgesTouched := len(assignments)
+	now := time.Now().UTC().Format(time.RFC3339)
 	var kbEvents []kbEvent
 	seq := int64(0)
-
-	now := time.Now().UTC().Format(time.RFC3339)
-	for cid, c := range finalClusters {
-		if edges := topEdges[cid]; len(edges) > 0 {
-			sort.Slice(edges, func(i, j int) bool { return edges[i].Score > edges[j].Score })
-			if len(edges) > 5 {
-				edges = edges[:5]
-			}
-			c.topRelated = edges
-		}
-		seq++
-		nodesTouched++
-		nodeHash := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%s|%s|%s", cid, c.size, c.memberHash, c.cachedAtStr, req.RunID)))
-		kbEvents = append(kbEvents, kbEvent{
-			Seq:         seq,
-			RunID:       req.RunID,
-			DatasetSlug: req.DatasetSlug,
-			Op:          "upsert_node",
-			Kind:        "kg.cluster",
-			ID:          cid,
-			Hash:        fmt.Sprintf("%x", nodeHash[:6]),
-			At:          now,
-		})
-		_, _ = kgc.client.UpsertNode(ctx, &kgpb.UpsertNodeRequest{
-			TenantId:  tenant,
-			ProjectId: project,
-			Node: &kgpb.Node{
-				Id:   cid,
-				Type: "kg.cluster",
-				Properties: map[string]string{
-					"clusterKind":    clusterKind,
-					"dataset":        req.DatasetSlug,
-					"artifactId":     req.ArtifactID,
-					"runId":          req.RunID,
-					"sinkEndpointId": req.SinkEndpointID,
-					"sourceFamily":   req.SourceFamily,
-					"updatedAt":      now,
-					"size":           fmt.Sprintf("%d", c.size),
-					"avgSim":         fmt.Sprintf("%.4f", c.avgSim),
-					"maxSim":         fmt.Sprintf("%.4f", c.maxSim),
-					"edgeDegree":     fmt.Sprintf("%d", c.edgeDegree),
-					"cacheAt":        c.cachedAtStr,
-					"memberHash":     c.memberHash,
+	nodesTouched, edgesTouched := 0, 0
+	kgc := newKgGRPCClient()
+	defer kgc.Close()
+	if kgc != nil {
+		for _, cid := range sortedKeys(finalClusters) {
+			c := finalClusters[cid]
+			seq++
+			nodesTouched++
+			nodeHash := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%s|%s|%s", cid, c.size, c.memberHash, c.cachedAtStr, req.RunID)))
+			kbEvents = append(kbEvents, kbEvent{
+				Seq:         seq,
+				RunID:       req.RunID,
+				DatasetSlug: req.DatasetSlug,
+				Op:          "upsert_node",
+				Kind:        "kg.cluster",
+				ID:          cid,
+				Hash:        fmt.Sprintf("%x", nodeHash[:6]),
+				At:          now,
+			})
+			_, _ = kgc.client.UpsertNode(ctx, &kgpb.UpsertNodeRequest{
+				TenantId:  tenant,
+				ProjectId: project,
+				Node: &kgpb.Node{
+					Id:   cid,
+					Type: "kg.cluster",
+					Properties: map[string]string{
+						"clusterKind":    clusterKind,
+						"dataset":        req.DatasetSlug,
+						"artifactId":     req.ArtifactID,
+						"runId":          req.RunID,
+						"sinkEndpointId": req.SinkEndpointID,
+						"sourceFamily":   req.SourceFamily,
+						"updatedAt":      now,
+						"size":           fmt.Sprintf("%d", c.size),
+						"avgSim":         fmt.Sprintf("%.4f", c.avgSim),
+						"maxSim":         fmt.Sprintf("%.4f", c.maxSim),
+						"silhouette":     fmt.Sprintf("%.4f", c.silhouette),
+						"edgeDegree":     fmt.Sprintf("%d", c.edgeDegree),
+						"cacheAt":        c.cachedAtStr,
+						"memberHash":     c.memberHash,
+					},
 				},
-			},
-		})
-	}
+			})
+		}
 
-	for nodeID, cid := range assignments {
-		sid := stableIDs[cid]
-		edgeID := fmt.Sprintf("in_cluster:%s:%s", sid, nodeID)
-		edgesTouched++
-		seq++
-		edgeHash := sha1.Sum([]byte(edgeID + req.RunID))
-		kbEvents = append(kbEvents, kbEvent{
-			Seq:         seq,
-			RunID:       req.RunID,
-			DatasetSlug: req.DatasetSlug,
-			Op:          "upsert_edge",
-			Kind:        "IN_CLUSTER",
-			ID:          edgeID,
-			Hash:        fmt.Sprintf("%x", edgeHash[:6]),
-			At:          now,
-		})
-		_, _ = kgc.client.UpsertEdge(ctx, &kgpb.UpsertEdgeRequest{
-			TenantId:  tenant,
-			ProjectId: project,
-			Edge: &kgpb.Edge{
-				Id:     edgeID,
-				Type:   "IN_CLUSTER",
-				FromId: sid,
-				ToId:   nodeID,
-			},
-		})
-	}
+		for i, p := range points {
+			sid := ids[clusterOf[i]]
+			edgeID := fmt.Sprintf("in_cluster:%s:%s", sid, p.nodeID)
+			edgesTouched++
+			seq++
+			edgeHash := sha1.Sum([]byte(edgeID + req.RunID))
+			kbEvents = append(kbEvents, kbEvent{
+				Seq:         seq,
+				RunID:       req.RunID,
+				DatasetSlug: req.DatasetSlug,
+				Op:          "upsert_edge",
+				Kind:        "IN_CLUSTER",
+				ID:          edgeID,
+				Hash:        fmt.Sprintf("%x", edgeHash[:6]),
+				At:          now,
+			})
+			_, _ = kgc.client.UpsertEdge(ctx, &kgpb.UpsertEdgeRequest{
+				TenantId:  tenant,
+				ProjectId: project,
+				Edge: &kgpb.Edge{
+					Id:     edgeID,
+					Type:   "IN_CLUSTER",
+					FromId: sid,
+					ToId:   p.nodeID,
+				},
+			})
+		}
 
-	// Optional related edges from similarity graph (only for component edges)
-	relatedSeen := make(map[string]struct{})
-	relatedCount := 0
-	for src, neighbors := range edgeMap {
-		for _, dst := range neighbors {
-			key := src + "->" + dst
-			if _, ok := relatedSeen[key]; ok {
+		// Drop what earlier runs wrote and this run no longer backs: IN_CLUSTER
+		// edges of members that moved or left the dataset, and the nodes of
+		// clusters that went empty (their edges go with them).
+		deleted := func(op, kind, id string) {
+			seq++
+			h := sha1.Sum([]byte(id + req.RunID))
+			kbEvents = append(kbEvents, kbEvent{
+				Seq:         seq,
+				RunID:       req.RunID,
+				DatasetSlug: req.DatasetSlug,
+				Op:          op,
+				Kind:        kind,
+				ID:          id,
+				Hash:        fmt.Sprintf("%x", h[:6]),
+				At:          now,
+			})
+		}
+		staleEdges, retired := 0, 0
+		for _, cid := range sortedKeys(finalClusters) {
+			c := finalClusters[cid]
+			members := make(map[string]bool, len(c.memberIDs))
+			for _, m := range c.memberIDs {
+				members[m] = true
+			}
+			// Stale edges point at members of the previous run, so the
+			// previous size bounds how many there can be.
+			resp, err := kgc.client.ListEdges(ctx, &kgpb.ListEdgesRequest{
+				TenantId:  tenant,
+				ProjectId: project,
+				EdgeTypes: []string{"IN_CLUSTER"},
+				SourceId:  cid,
+				Limit:     int32(c.size + cacheEntries[cid].Size + 1),
+			})
+			if err != nil {
+				logger.Warn("cluster-list-members-failed", "cluster", cid, "err", err)
 				continue
 			}
-			relatedSeen[key] = struct{}{}
-			relatedCount++
-			// bump edge degree per cluster
-			if cid := findClusterID(assignments, src); cid != "" {
-				if cs, ok := finalClusters[stableIDs[cid]]; ok {
-					cs.edgeDegree++
-					topEdges[cid] = append(topEdges[cid], edgeSummary{Src: src, Dst: dst, Score: cosineSim(findEmb(entries, src), findEmb(entries, dst))})
+			for _, e := range resp.Edges {
+				if members[e.ToId] {
+					continue
 				}
-			}
-			if cid := findClusterID(assignments, dst); cid != "" {
-				if cs, ok := finalClusters[stableIDs[cid]]; ok {
-					cs.edgeDegree++
-					topEdges[cid] = append(topEdges[cid], edgeSummary{Src: src, Dst: dst, Score: cosineSim(findEmb(entries, src), findEmb(entries, dst))})
+				if _, err := kgc.client.DeleteEdge(ctx, &kgpb.DeleteEdgeRequest{TenantId: tenant, ProjectId: project, EdgeId: e.Id}); err != nil {
+					logger.Warn("cluster-delete-edge-failed", "edge", e.Id, "err", err)
+					continue
 				}
+				deleted("delete_edge", "IN_CLUSTER", e.Id)
+				staleEdges++
+			}
+		}
+		for _, cid := range sortedKeys(cacheEntries) {
+			if _, ok := finalClusters[cid]; ok {
+				continue
+			}
+			if _, err := kgc.client.DeleteNode(ctx, &kgpb.DeleteNodeRequest{TenantId: tenant, ProjectId: project, NodeId: cid}); err != nil {
+				logger.Warn("cluster-delete-node-failed", "cluster", cid, "err", err)
+				continue
 			}
+			deleted("delete_node", "kg.cluster", cid)
+			retired++
+		}
+		if staleEdges > 0 || retired > 0 {
+			logger.Info("cluster-pruned", "staleMemberships", staleEdges, "retiredClusters", retired, "dataset", req.DatasetSlug)
+		}
+
+		// Related edges from the neighbour graph
+		for _, e := range edges {
+			src, dst := points[e.a].nodeID, points[e.b].nodeID
+			edgeID := fmt.Sprintf("related:%s:%s", src, dst)
 			_, _ = kgc.client.UpsertEdge(ctx, &kgpb.UpsertEdgeRequest{
 				TenantId:  tenant,
 				ProjectId: project,
 				Edge: &kgpb.Edge{
-					Id:     fmt.Sprintf("related:%s:%s", src, dst),
+					Id:     edgeID,
 					Type:   "RELATED",
 					FromId: src,
 					ToId:   dst,
+					Properties: map[string]string{
+						"score":     fmt.Sprintf("%.4f", e.score),
+						"duplicate": strconv.FormatBool(e.score >= dupThreshold),
+					},
 				},
 			})
 			seq++
-			edgeHash := sha1.Sum([]byte(key + req.RunID))
+			edgeHash := sha1.Sum([]byte(src + "->" + dst + req.RunID))
 			kbEvents = append(kbEvents, kbEvent{
 				Seq:         seq,
 				RunID:       req.RunID,
 				DatasetSlug: req.DatasetSlug,
 				Op:          "upsert_edge",
 				Kind:        "RELATED",
-				ID:          key,
+				ID:          src + "->" + dst,
 				Hash:        fmt.Sprintf("%x", edgeHash[:6]),
 				At:          now,
 			})
@@ -419,9 +375,7 @@ func (a *Activities) BuildClusters(ctx context.Context, req IndexArtifactRequest
 		}
 	}
 
-	logger.Info("cluster-built", "clusters", len(finalClusters), "assignments", len(assignments), "relatedEdges", relatedCount, "cacheHits", cacheHits, "dataset", req.DatasetSlug)
-
-	// Save checkpoint for incremental runs
+	// Record the newest entry clustered; every run still reads the whole dataset
 	cpTime := now
 	if !latestUpdated.IsZero() {
 		cpTime = latestUpdated.UTC().Format(time.RFC3339)
@@ -429,6 +383,15 @@ func (a *Activities) BuildClusters(ctx context.Context, req IndexArtifactRequest
 	saveCheckpointKV(ctx, tenant, project, fmt.Sprintf("cluster:%s", req.DatasetSlug), map[string]any{
 		"lastUpdatedAt": cpTime,
 	})
+	saveCheckpointKV(ctx, tenant, project, fmt.Sprintf("cluster:quality:%s", req.DatasetSlug), map[string]any{
+		"runId":            req.RunID,
+		"silhouette":       quality.Silhouette,
+		"silhouetteSample": quality.SilhouetteSample,
+		"sizes":            quality.Sizes,
+		"relatedEdges":     len(edges),
+		"duplicateEdges":   duplicates,
+		"measuredAt":       now,
+	})
 
 	if regClient, _ := newRegistryClient(); regClient != nil {
 		defer regClient.Close()
@@ -436,8 +399,9 @@ func (a *Activities) BuildClusters(ctx context.Context, req IndexArtifactRequest
 		eventsPath, snapPath := saveKBEvents(ctx, tenant, project, req.DatasetSlug, req.RunID, kbEvents, seq)
 		regClient.markClustered(ctx, req.ArtifactID, map[string]any{
 			"clustersCreated": len(finalClusters),
-			"membersLinked":   len(assignments),
-			"relatedEdges":    relatedCount,
+			"membersLinked":   len(points),
+			"relatedEdges":    len(edges),
+			"duplicateEdges":  duplicates,
 			"cacheHits":       cacheHits,
 			"cacheAt":         now,
 			"clusterKind":     clusterKind,
@@ -446,6 +410,8 @@ func (a *Activities) BuildClusters(ctx context.Context, req IndexArtifactRequest
 			"versionHash":     fmt.Sprintf("%x", versionHash[:6]),
 			"nodesTouched":    nodesTouched,
 			"edgesTouched":    edgesTouched,
+			"silhouette":      quality.Silhouette,
+			"clusterSizes":    quality.Sizes,
 			"logEventsPath":   eventsPath,
 			"logSnapshotPath": snapPath,
 		})
@@ -458,6 +424,110 @@ func (a *Activities) BuildClusters(ctx context.Context, req IndexArtifactRequest
 	return nil
 }
 
+// loadClusterPoints streams every vector entry matching the filter, a page
+// at a time, keeping node IDs and normalized embeddings. Paging needs the
+// vector database itself (VECTOR_DATABASE_URL or DATABASE_URL); without it
+// the VectorService list API is used, capped at CLUSTER_MAX_ENTRIES.
+func loadClusterPoints(ctx context.Context, filter vectorstore.QueryFilter) ([]clusterPoint, time.Time, error) {
+	dsn := getenv("VECTOR_DATABASE_URL", getenv("DATABASE_URL", ""))
+	if dsn == "" {
+		client, err := getVectorStore()
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		limit := getEnvInt("CLUSTER_MAX_ENTRIES", 300)
+		entries, err := client.ListEntries(filter, limit)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if len(entries) >= limit {
+			activity.GetLogger(ctx).Warn("cluster: entries capped, set VECTOR_DATABASE_URL to cluster the whole dataset", "limit", limit, "dataset", filter.DatasetSlug)
+		}
+		var pc pointCollector
+		for _, e := range entries {
+			pc.add(e)
+		}
+		return pc.points, pc.latest, nil
+	}
+
+	store, err := vectorstore.NewPgVectorStore(dsn, 0)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer store.Close()
+	return collectClusterPoints(ctx, store, filter, getEnvInt("CLUSTER_PAGE_SIZE", 1000), func(n int) {
+		activity.RecordHeartbeat(ctx, n)
+	})
+}
+
+// collectClusterPoints pages through a store, calling progress after each
+// page with the number of entries read so far.
+func collectClusterPoints(ctx context.Context, pager vectorstore.EntryPager, filter vectorstore.QueryFilter, pageSize int, progress func(int)) ([]clusterPoint, time.Time, error) {
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+	var pc pointCollector
+	var after *vectorstore.EntryKey
+	read := 0
+	for {
+		page, err := pager.PageEntries(ctx, filter, after, pageSize)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		for _, e := range page {
+			pc.add(e)
+		}
+		read += len(page)
+		if progress != nil {
+			progress(read)
+		}
+		if len(page) < pageSize {
+			return pc.points, pc.latest, nil
+		}
+		key := vectorstore.KeyOf(page[len(page)-1])
+		after = &key
+	}
+}
+
+// pointCollector gathers cluster points. A node embedded under several
+// profiles is kept once, with its most recent embedding; embeddings whose
+// dimension differs from the first one seen are skipped.
+type pointCollector struct {
+	points []clusterPoint
+	index  map[string]int
+	latest time.Time
+}
+
+func (pc *pointCollector) add(e vectorstore.Entry) {
+	if len(e.Embedding) == 0 || len(pc.points) > 0 && len(e.Embedding) != len(pc.points[0].vec) {
+		return
+	}
+	p := clusterPoint{nodeID: e.NodeID, vec: normalized(e.Embedding)}
+	if e.UpdatedAt != nil {
+		p.updatedAt = *e.UpdatedAt
+		if p.updatedAt.After(pc.latest) {
+			pc.latest = p.updatedAt
+		}
+	}
+	if pc.index == nil {
+		pc.index = make(map[string]int)
+	}
+	if i, ok := pc.index[e.NodeID]; ok {
+		if p.updatedAt.After(pc.points[i].updatedAt) {
+			pc.points[i] = p
+		}
+		return
+	}
+	pc.index[e.NodeID] = len(pc.points)
+	pc.points = append(pc.points, p)
+}
+
+// clusterSeed makes k-means deterministic per dataset.
+func clusterSeed(dataset string) int64 {
+	sum := sha1.Sum([]byte(dataset))
+	return int64(binary.BigEndian.Uint64(sum[:8]) >> 1)
+}
+
 func saveKBEvents(ctx context.Context, tenant, project, dataset, runID string, events []kbEvent, seq int64) (string, string) {
 	store, err := logstore.NewGatewayStoreFromEnv()
 	if err != nil {
@@ -496,97 +566,15 @@ func saveKBEvents(ctx context.Context, tenant, project, dataset, runID string, e
 	return eventsPath, snapPath
 }
 
-func sortedKeys(clusters map[string]*clusterStats) []string {
-	keys := make([]string, 0, len(clusters))
-	for k := range clusters {
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 	return keys
 }
 
-func cosineSim(a, b []float32) float32 {
-	if len(a) == 0 || len(b) == 0 || len(a) != len(b) {
-		return 0
-	}
-	var dot, na, nb float64
-	for i := range a {
-		dot += float64(a[i]) * float64(b[i])
-		na += float64(a[i]) * float64(a[i])
-		nb += float64(b[i]) * float64(b[i])
-	}
-	if na == 0 || nb == 0 {
-		return 0
-	}
-	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb)))
-}
-
-func avgVec(a, b []float32, total int) []float32 {
-	if len(a) == 0 {
-		return b
-	}
-	out := make([]float32, len(a))
-	for i := range a {
-		out[i] = (a[i]*float32(total-1) + b[i]) / float32(total)
-	}
-	return out
-}
-
-// buildComponents builds connected components over entries using a similarity threshold and returns component membership plus the underlying edge map for RELATED edges.
-func buildComponents(entries []vectorstore.Entry, threshold float32) ([][]string, map[string][]string) {
-	ids := make([]string, 0, len(entries))
-	emb := make([][]float32, 0, len(entries))
-	for _, e := range entries {
-		if len(e.Embedding) == 0 {
-			continue
-		}
-		ids = append(ids, e.NodeID)
-		emb = append(emb, e.Embedding)
-	}
-	n := len(ids)
-	graph := make([][]int, n)
-	edgeMap := make(map[string][]string)
-	for i := 0; i < n; i++ {
-		for j := i + 1; j < n; j++ {
-			if cosineSim(emb[i], emb[j]) >= threshold {
-				graph[i] = append(graph[i], j)
-				graph[j] = append(graph[j], i)
-				edgeMap[ids[i]] = append(edgeMap[ids[i]], ids[j])
-				edgeMap[ids[j]] = append(edgeMap[ids[j]], ids[i])
-			}
-		}
-	}
-	visited := make([]bool, n)
-	var comps [][]string
-	var stack []int
-	for i := 0; i < n; i++ {
-		if visited[i] {
-			continue
-		}
-		stack = stack[:0]
-		stack = append(stack, i)
-		var comp []string
-		for len(stack) > 0 {
-			k := stack[len(stack)-1]
-			stack = stack[:len(stack)-1]
-			if visited[k] {
-				continue
-			}
-			visited[k] = true
-			comp = append(comp, ids[k])
-			for _, nei := range graph[k] {
-				if !visited[nei] {
-					stack = append(stack, nei)
-				}
-			}
-		}
-		if len(comp) > 0 {
-			comps = append(comps, comp)
-		}
-	}
-	return comps, edgeMap
-}
-
 func getEnvFloat(key string, defaultVal float64) float32 {
 	if val := os.Getenv(key); val != "" {
 		if f, err := strconv.ParseFloat(val, 64); err == nil {
@@ -612,22 +600,6 @@ func makeStableClusterID(dataset, sourceFamily string, members []string) string
 	return fmt.Sprintf("cluster:%s:%x", dataset, sum[:6])
 }
 
-func findClusterID(assignments map[string]string, nodeID string) string {
-	if cid, ok := assignments[nodeID]; ok {
-		return cid
-	}
-	return ""
-}
-
-func findEmb(entries []vectorstore.Entry, nodeID string) []float32 {
-	for _, e := range entries {
-		if e.NodeID == nodeID {
-			return e.Embedding
-		}
-	}
-	return nil
-}
-
 func loadCentroidCache(ctx context.Context, tenant, project, dataset string) (map[string]centroidCacheEntry, int64, error) {
 	store, err := kvstore.NewPostgresStore()
 	if err != nil {
@@ -671,6 +643,7 @@ func saveCentroidCache(ctx context.Context, tenant, project, dataset string, clu
 			EdgeDegree: cs.edgeDegree,
 			MemberHash: cs.memberHash,
 			TopRelated: cs.topRelated,
+			Dim:        len(cs.centroid),
 		}
 	}
 	b, err := json.Marshal(payload)
@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"hash/fnv"
 	"io"
+	"log"
 	"net/http"
 	"os"
 	"strconv"
@@ -19,6 +19,7 @@ import (
 type EmbeddingProvider interface {
 	EmbedText(model string, texts []string) ([][]float32, error)
 	ModelName() string // Returns the active model name for metadata
+	Dimension() int    // Returns the length of the vectors the provider produces
 }
 
 // zeroProvider returns zero vectors (placeholder until real provider is wired).
@@ -41,12 +42,24 @@ func (p *zeroProvider) ModelName() string {
 	return "zero-vector"
 }
 
+func (p *zeroProvider) Dimension() int {
+	return p.dim
+}
+
+// defaultEmbeddingProvider is used when EMBEDDING_PROVIDER is not set, for
+// indexing and re-embedding alike.
+const defaultEmbeddingProvider = "local"
+
 var (
 	embedOnce sync.Once
 	embedProv EmbeddingProvider
 	embedErr  error
 )
 
+// getEmbeddingProvider returns the provider named by EMBEDDING_PROVIDER
+// (openai, local or zero; default local) with EMBEDDING_MODEL and EMBED_DIM.
+// A provider named explicitly that cannot be built is an error rather than a
+// silent switch to another provider's vectors.
 func getEmbeddingProvider() (EmbeddingProvider, error) {
 	embedOnce.Do(func() {
 		dim := 1536
@@ -55,49 +68,90 @@ func getEmbeddingProvider() (EmbeddingProvider, error) {
 				dim = parsed
 			}
 		}
-		switch strings.ToLower(os.Getenv("EMBEDDING_PROVIDER")) {
-		case "openai":
-			apiKey := os.Getenv("OPENAI_API_KEY")
-			model := os.Getenv("EMBEDDING_MODEL")
-			if model == "" {
-				model = "text-embedding-3-small"
-			}
-			if apiKey != "" {
-				embedProv = &openAIProvider{apiKey: apiKey, model: model, dim: dim}
-				return
-			}
-		case "local":
-			embedProv = &localProvider{dim: dim}
-			return
+		kind := embeddingProviderKind()
+		embedProv, embedErr = newEmbeddingProvider(kind, os.Getenv("EMBEDDING_MODEL"), dim)
+		if embedErr != nil {
+			embedErr = fmt.Errorf("embedding provider %q: %w", kind, embedErr)
 		}
-		embedProv = &zeroProvider{dim: dim} // fallback
 	})
 	return embedProv, embedErr
 }
 
+// embeddingProviderKind returns EMBEDDING_PROVIDER, or the default with a log
+// line saying so, since the default (local) differs from the zero vectors
+// unconfigured deployments used to get.
+func embeddingProviderKind() string {
+	if kind := getenv("EMBEDDING_PROVIDER", ""); kind != "" {
+		return kind
+	}
+	log.Printf("embedding: EMBEDDING_PROVIDER is not set, using the %s provider", defaultEmbeddingProvider)
+	return defaultEmbeddingProvider
+}
+
+// newEmbeddingProvider builds a provider of the given kind (openai, local or
+// zero).
+func newEmbeddingProvider(kind, model string, dim int) (EmbeddingProvider, error) {
+	switch strings.ToLower(kind) {
+	case "openai":
+		apiKey := os.Getenv("OPENAI_API_KEY")
+		if model == "" {
+			model = "text-embedding-3-small"
+		}
+		if apiKey == "" {
+			return nil, errors.New("OPENAI_API_KEY is not set")
+		}
+		return &openAIProvider{apiKey: apiKey, model: model, dim: dim, client: &http.Client{Timeout: 30 * time.Second}}, nil
+	case "local":
+		return newLocalProvider(dim), nil
+	case "zero":
+		return &zeroProvider{dim: dim}, nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q", kind)
+	}
+}
+
 // Minimal OpenAI embeddings client (no extra deps).
 type openAIProvider struct {
 	apiKey string
 	model  string
 	dim    int
+	client *http.Client
 }
 
 type openAIRequest struct {
-	Model string   `json:"model"`
-	Input []string `json:"input"`
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
 }
 
 type openAIResponse struct {
 	Data []struct {
+		Index     int       `json:"index"`
 		Embedding []float64 `json:"embedding"`
 	} `json:"data"`
 }
 
+// embeddingHTTPError is a non-2xx answer from an embedding API.
+type embeddingHTTPError struct {
+	status     int
+	body       string
+	retryAfter time.Duration
+}
+
+func (e *embeddingHTTPError) Error() string {
+	return fmt.Sprintf("embedding request failed: status=%d body=%s", e.status, e.body)
+}
+
 func (p *openAIProvider) EmbedText(model string, texts []string) ([][]float32, error) {
 	if model == "" {
 		model = p.model
 	}
-	reqBody, err := json.Marshal(openAIRequest{Model: model, Input: texts})
+	req := openAIRequest{Model: model, Input: texts}
+	if strings.HasPrefix(model, "text-embedding-3") {
+		// v3 models can shorten their vectors to the store's dimension
+		req.Dimensions = p.dim
+	}
+	reqBody, err := json.Marshal(req)
 	if err != nil {
 		return nil, err
 	}
@@ -108,15 +162,18 @@ func (p *openAIProvider) EmbedText(model string, texts []string) ([][]float32, e
 	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(httpReq)
+	resp, err := p.client.Do(httpReq)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("embedding request failed: status=%d body=%s", resp.StatusCode, string(body))
+		herr := &embeddingHTTPError{status: resp.StatusCode, body: string(body)}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			herr.retryAfter = time.Duration(secs) * time.Second
+		}
+		return nil, herr
 	}
 	var decoded openAIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
@@ -127,11 +184,15 @@ func (p *openAIProvider) EmbedText(model string, texts []string) ([][]float32, e
 	}
 	out := make([][]float32, len(texts))
 	for i, d := range decoded.Data {
+		idx := d.Index
+		if idx < 0 || idx >= len(out) || out[idx] != nil {
+			idx = i
+		}
 		vec := make([]float32, len(d.Embedding))
 		for j, v := range d.Embedding {
 			vec[j] = float32(v)
 		}
-		out[i] = vec
+		out[idx] = vec
 	}
 	return out, nil
 }
@@ -140,51 +201,6 @@ func (p *openAIProvider) ModelName() string {
 	return p.model
 }
 
-// localProvider produces deterministic hashed embeddings without external services.
-type localProvider struct {
-	dim int
-}
-
-func (p *localProvider) EmbedText(_ string, texts []string) ([][]float32, error) {
-	if p.dim <= 0 {
-		return nil, errors.New("invalid embedding dimension")
-	}
-	out := make([][]float32, len(texts))
-	for i, t := range texts {
-		out[i] = p.embedOne(t)
-	}
-	return out, nil
-}
-
-func (p *localProvider) embedOne(text string) []float32 {
-	vec := make([]float32, p.dim)
-	words := strings.Fields(text)
-	if len(words) == 0 {
-		return vec
-	}
-	for _, w := range words {
-		h := fnv.New32a()
-		_, _ = h.Write([]byte(w))
-		idx := int(h.Sum32()) % p.dim
-		if idx < 0 {
-			idx = -idx
-		}
-		vec[idx] += 1.0
-	}
-	// simple L2 norm
-	var norm float32
-	for _, v := range vec {
-		norm += v * v
-	}
-	if norm > 0 {
-		n := float32(1.0) / norm
-		for i := range vec {
-			vec[i] = vec[i] * n
-		}
-	}
-	return vec
-}
-
-func (p *localProvider) ModelName() string {
-	return "local-fnv-hash"
+func (p *openAIProvider) Dimension() int {
+	return p.dim
 }
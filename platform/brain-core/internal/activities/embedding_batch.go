@@ -0,0 +1,264 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nucleus/store-core/pkg/vectorstore"
+)
+
+// embeddingCache stores vectors by model and content hash.
+type embeddingCache interface {
+	get(ctx context.Context, model, contentHash string) ([]float32, bool)
+	put(ctx context.Context, model, contentHash string, vec []float32)
+}
+
+// embedder is the layer callers embed through. It serves cached vectors,
+// packs the rest into requests by estimated token budget, retries transient
+// failures with exponential backoff and checks every vector has the
+// dimension the vector store expects.
+type embedder struct {
+	provider EmbeddingProvider
+	model    string // empty uses the provider's model
+	dim      int    // expected vector length; 0 skips the check
+	cache    embeddingCache
+
+	maxTokens      int // estimated tokens per request
+	maxInputs      int // texts per request
+	maxInputTokens int // longer texts are truncated
+	retries        int
+	backoff        time.Duration // first retry delay, doubled per attempt
+	maxBackoff     time.Duration
+	sleep          func(context.Context, time.Duration) error
+}
+
+// embedStats reports what an Embed call did.
+type embedStats struct {
+	Cached    int // served from the cache
+	Embedded  int // sent to the provider
+	Requests  int // provider calls, retries included
+	Retries   int
+	Truncated int // texts cut to the per-input token limit
+}
+
+// newEmbedder wraps provider with the batching and retry settings from
+// EMBED_BATCH_TOKENS, EMBED_BATCH_SIZE, EMBED_MAX_INPUT_TOKENS,
+// EMBED_RETRIES and EMBED_RETRY_BACKOFF_MS. cache may be nil.
+func newEmbedder(provider EmbeddingProvider, dim int, cache embeddingCache) *embedder {
+	return &embedder{
+		provider:       provider,
+		dim:            dim,
+		cache:          cache,
+		maxTokens:      getEnvInt("EMBED_BATCH_TOKENS", 100000),
+		maxInputs:      getEnvInt("EMBED_BATCH_SIZE", 256),
+		maxInputTokens: getEnvInt("EMBED_MAX_INPUT_TOKENS", 8000),
+		retries:        getEnvInt("EMBED_RETRIES", 4),
+		backoff:        time.Duration(getEnvInt("EMBED_RETRY_BACKOFF_MS", 500)) * time.Millisecond,
+		maxBackoff:     30 * time.Second,
+		sleep:          sleepContext,
+	}
+}
+
+func (e *embedder) modelName() string {
+	if e.model != "" {
+		return e.model
+	}
+	return e.provider.ModelName()
+}
+
+// Embed returns one vector per text, in order.
+func (e *embedder) Embed(ctx context.Context, texts []string) ([][]float32, embedStats, error) {
+	var stats embedStats
+	model := e.modelName()
+	out := make([][]float32, len(texts))
+
+	// Identical texts are embedded once
+	hashes := make([]string, len(texts))
+	first := make(map[string]int)
+	var pending []int
+	for i, t := range texts {
+		hashes[i] = hashContent(t)
+		if _, dup := first[hashes[i]]; dup {
+			continue
+		}
+		first[hashes[i]] = i
+		if e.cache != nil {
+			if vec, ok := e.cache.get(ctx, model, hashes[i]); ok && (e.dim == 0 || len(vec) == e.dim) {
+				out[i] = vec
+				stats.Cached++
+				continue
+			}
+		}
+		pending = append(pending, i)
+	}
+
+	inputs := make([]string, len(texts))
+	for _, i := range pending {
+		inputs[i] = texts[i]
+		if limit := e.maxInputTokens; limit > 0 && estimateTokens(texts[i]) > limit {
+			inputs[i] = truncateUTF8(texts[i], limit*bytesPerToken)
+			stats.Truncated++
+		}
+	}
+	for _, batch := range e.batches(pending, inputs) {
+		batchTexts := make([]string, len(batch))
+		for j, i := range batch {
+			batchTexts[j] = inputs[i]
+		}
+		vecs, err := e.embedBatch(ctx, batchTexts, &stats)
+		if err != nil {
+			return nil, stats, err
+		}
+		for j, i := range batch {
+			out[i] = vecs[j]
+			if e.cache != nil {
+				e.cache.put(ctx, model, hashes[i], vecs[j])
+			}
+		}
+		stats.Embedded += len(batch)
+	}
+
+	for i := range texts {
+		if out[i] == nil {
+			out[i] = out[first[hashes[i]]]
+		}
+	}
+	return out, stats, nil
+}
+
+// batches packs the pending texts into requests of at most maxInputs texts
+// and maxTokens estimated tokens.
+func (e *embedder) batches(pending []int, inputs []string) [][]int {
+	var out [][]int
+	var cur []int
+	tokens := 0
+	for _, i := range pending {
+		t := estimateTokens(inputs[i])
+		if len(cur) > 0 && (e.maxInputs > 0 && len(cur) >= e.maxInputs || e.maxTokens > 0 && tokens+t > e.maxTokens) {
+			out = append(out, cur)
+			cur, tokens = nil, 0
+		}
+		cur = append(cur, i)
+		tokens += t
+	}
+	if len(cur) > 0 {
+		out = append(out, cur)
+	}
+	return out
+}
+
+func (e *embedder) embedBatch(ctx context.Context, texts []string, stats *embedStats) ([][]float32, error) {
+	for attempt := 0; ; attempt++ {
+		stats.Requests++
+		vecs, err := e.provider.EmbedText(e.model, texts)
+		if err == nil {
+			if len(vecs) != len(texts) {
+				return nil, fmt.Errorf("embedding provider returned %d vectors for %d texts", len(vecs), len(texts))
+			}
+			for _, v := range vecs {
+				if e.dim > 0 && len(v) != e.dim {
+					return nil, fmt.Errorf("embedding provider %s returned %d-dimensional vectors, expected %d", e.modelName(), len(v), e.dim)
+				}
+			}
+			return vecs, nil
+		}
+		if attempt >= e.retries || !retryableEmbeddingError(err) {
+			return nil, err
+		}
+		delay := e.backoff << attempt
+		if delay > e.maxBackoff || delay <= 0 {
+			delay = e.maxBackoff
+		}
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)) // jitter
+		var herr *embeddingHTTPError
+		if errors.As(err, &herr) && herr.retryAfter > delay {
+			delay = herr.retryAfter
+		}
+		stats.Retries++
+		if err := e.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryableEmbeddingError reports whether a failed request may succeed when
+// repeated: rate limits, server errors and transport failures.
+func retryableEmbeddingError(err error) bool {
+	var herr *embeddingHTTPError
+	if errors.As(err, &herr) {
+		return herr.status == 429 || herr.status >= 500
+	}
+	var uerr *url.Error
+	return errors.As(err, &uerr)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// bytesPerToken is a conservative bytes-per-token ratio for estimating
+// request sizes without a tokenizer (English prose averages about four).
+const bytesPerToken = 3
+
+func estimateTokens(s string) int {
+	return (len(s) + bytesPerToken - 1) / bytesPerToken
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// vectorStoreModel returns the vector store's active model and dimension,
+// or zero values when the vector database is not reachable from the worker
+// (VECTOR_DATABASE_URL or DATABASE_URL).
+func vectorStoreModel(ctx context.Context) (string, int) {
+	dsn := getenv("VECTOR_DATABASE_URL", getenv("DATABASE_URL", ""))
+	if dsn == "" {
+		return "", 0
+	}
+	store, err := vectorstore.NewPgVectorStore(dsn, 0)
+	if err != nil {
+		return "", 0
+	}
+	defer store.Close()
+	info, err := store.ActiveModel(ctx)
+	if err != nil {
+		return "", 0
+	}
+	return info.Name, info.Dimension
+}
+
+// checkEmbeddingModel fails when the provider's vectors cannot be stored in
+// the vector store's active model, before any tokens are spent. Besides the
+// dimension the model name must match, since vectors of different models are
+// not comparable; the unnamed default model accepts any provider.
+func checkEmbeddingModel(provider EmbeddingProvider, storeModel string, storeDim int) error {
+	if storeDim > 0 && provider.Dimension() > 0 && provider.Dimension() != storeDim {
+		return fmt.Errorf("embedding provider %s produces %d-dimensional vectors but the vector store's active model %s expects %d (set EMBED_DIM)",
+			provider.ModelName(), provider.Dimension(), storeModel, storeDim)
+	}
+	if storeModel != "" && storeModel != vectorstore.DefaultModel && provider.ModelName() != storeModel {
+		return fmt.Errorf("embedding provider produces %s vectors but the vector store's active model is %s (set EMBEDDING_PROVIDER and EMBEDDING_MODEL, or re-embed)",
+			provider.ModelName(), storeModel)
+	}
+	return nil
+}
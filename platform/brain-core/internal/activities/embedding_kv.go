@@ -5,6 +5,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"log"
+	"sync"
+	"time"
 )
 
 // makeEmbeddingHashKey creates a KV key for storing content hash of a vector entry.
@@ -13,28 +16,145 @@ func makeEmbeddingHashKey(profileID, nodeID string) string {
 	return fmt.Sprintf("embed:%s:%s", profileID, nodeID)
 }
 
+// makeEmbeddingCacheKey creates a KV key for a cached embedding.
+// Format: embedcache:<model>:<contentHash>
+func makeEmbeddingCacheKey(model, contentHash string) string {
+	return fmt.Sprintf("%s%s:%s", embeddingCachePrefix, model, contentHash)
+}
+
 // hashContent computes SHA256 hash of the content text.
 func hashContent(content string) string {
 	h := sha256.Sum256([]byte(content))
 	return hex.EncodeToString(h[:])
 }
 
-// loadEmbeddingHash retrieves the stored content hash for a vector entry.
-// Returns empty string if not found.
-func loadEmbeddingHash(ctx context.Context, tenantID, projectID, profileID, nodeID string) (string, error) {
+// loadEmbeddingHash retrieves the stored content hash for a vector entry and
+// the model it was embedded with. Returns empty strings if not found.
+func loadEmbeddingHash(ctx context.Context, tenantID, projectID, profileID, nodeID string) (string, string, error) {
 	m, err := loadCheckpointKV(ctx, tenantID, projectID, makeEmbeddingHashKey(profileID, nodeID))
 	if err != nil || m == nil {
-		return "", err
+		return "", "", err
 	}
-	if hash, ok := m["contentHash"].(string); ok {
-		return hash, nil
+	cp, err := FromMap[EmbeddingHashCheckpoint](m)
+	if err != nil {
+		return "", "", err
 	}
-	return "", nil
+	return cp.ContentHash, cp.Model, nil
 }
 
 // saveEmbeddingHash stores the content hash for a vector entry.
 // Used to skip re-embedding unchanged content on subsequent runs.
-func saveEmbeddingHash(ctx context.Context, tenantID, projectID, profileID, nodeID, contentHash string) {
-	cp := NewEmbeddingHashCheckpoint(contentHash)
+func saveEmbeddingHash(ctx context.Context, tenantID, projectID, profileID, nodeID, contentHash, model string, dim int) {
+	cp := NewEmbeddingHashCheckpoint(contentHash, model, dim)
 	_ = saveCheckpointKV(ctx, tenantID, projectID, makeEmbeddingHashKey(profileID, nodeID), ToMap(cp))
 }
+
+// embeddingCachePrefix is the key prefix of cached embeddings.
+const embeddingCachePrefix = "embedcache:"
+
+// embeddingCacheTTL is how long a cached embedding is served. The KV store
+// has no expiry of its own, so expired entries are deleted when read and by
+// sweepEmbeddingCache.
+func embeddingCacheTTL() time.Duration {
+	return time.Duration(getEnvInt("EMBED_CACHE_TTL_HOURS", 30*24)) * time.Hour
+}
+
+// kvEmbeddingCache caches embeddings by model and content hash in the KV
+// store, so identical content (a template shared by many pages, a node
+// re-ingested under another profile) is embedded once per tenant.
+type kvEmbeddingCache struct {
+	tenantID  string
+	projectID string
+}
+
+func (c kvEmbeddingCache) get(ctx context.Context, model, contentHash string) ([]float32, bool) {
+	key := makeEmbeddingCacheKey(model, contentHash)
+	m, err := loadCheckpointKV(ctx, c.tenantID, c.projectID, key)
+	if err != nil || m == nil {
+		return nil, false
+	}
+	cp, err := FromMap[EmbeddingHashCheckpoint](m)
+	if err != nil || len(cp.Embedding) == 0 || cp.ContentHash != contentHash {
+		return nil, false
+	}
+	if embeddingCacheExpired(&cp, time.Now()) {
+		_ = deleteCheckpointKV(ctx, c.tenantID, c.projectID, key)
+		return nil, false
+	}
+	return cp.Embedding, true
+}
+
+func (c kvEmbeddingCache) put(ctx context.Context, model, contentHash string, vec []float32) {
+	cp := NewEmbeddingHashCheckpoint(contentHash, model, len(vec))
+	cp.Embedding = vec
+	_ = saveCheckpointKV(ctx, c.tenantID, c.projectID, makeEmbeddingCacheKey(model, contentHash), ToMap(cp))
+}
+
+// embeddingCacheExpired reports whether a cache entry is past the TTL.
+// Entries without a parsable save time count as expired.
+func embeddingCacheExpired(cp *EmbeddingHashCheckpoint, now time.Time) bool {
+	saved, err := time.Parse(time.RFC3339, cp.SavedAt)
+	return err != nil || now.Sub(saved) >= embeddingCacheTTL()
+}
+
+var (
+	embedSweepMu   sync.Mutex
+	embedSweptAt   = map[string]time.Time{}
+	embedSweepPage = 200
+)
+
+// maybeSweep starts a background sweep of the scope's cached embeddings
+// unless this process swept it within the last day.
+func (c kvEmbeddingCache) maybeSweep() {
+	scope := c.tenantID + "/" + c.projectID
+	embedSweepMu.Lock()
+	if time.Since(embedSweptAt[scope]) < 24*time.Hour {
+		embedSweepMu.Unlock()
+		return
+	}
+	embedSweptAt[scope] = time.Now()
+	embedSweepMu.Unlock()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		if n, err := sweepEmbeddingCache(ctx, c.tenantID, c.projectID); err != nil {
+			log.Printf("embedding cache: sweep %s: %v", scope, err)
+		} else if n > 0 {
+			log.Printf("embedding cache: swept %d expired entries in %s", n, scope)
+		}
+	}()
+}
+
+// sweepEmbeddingCache deletes the scope's expired cached embeddings and
+// returns how many it deleted.
+func sweepEmbeddingCache(ctx context.Context, tenantID, projectID string) (int, error) {
+	now := time.Now()
+	deleted := 0
+	token := ""
+	for {
+		keys, next, err := listKeysKV(ctx, tenantID, projectID, embeddingCachePrefix, token, embedSweepPage)
+		if err != nil {
+			return deleted, err
+		}
+		for _, key := range keys {
+			m, err := loadCheckpointKV(ctx, tenantID, projectID, key)
+			if err != nil {
+				return deleted, err
+			}
+			if m == nil {
+				continue
+			}
+			if cp, err := FromMap[EmbeddingHashCheckpoint](m); err == nil && !embeddingCacheExpired(&cp, now) {
+				continue
+			}
+			if err := deleteCheckpointKV(ctx, tenantID, projectID, key); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+		if next == "" {
+			return deleted, nil
+		}
+		token = next
+	}
+}
@@ -0,0 +1,214 @@
+package activities
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// localProjectionNNZ is how many output dimensions each feature touches.
+const localProjectionNNZ = 4
+
+// Per-kind feature weights, multiplied by the feature's IDF. Whole words
+// carry more than their n-grams, and longer n-grams more than shorter ones.
+const (
+	localWordWeight  = 1.0
+	localNgramWeight = 0.2 // per character above 2: 0.2, 0.4, 0.6 for 3- to 5-grams
+)
+
+// localReferenceWords are common English and workplace words, most frequent
+// first. They stand in for a reference corpus: a word at rank r is taken to
+// occur in 1/r of all documents (Zipf), and an n-gram in the summed share of
+// the reference words containing it. IDFs derived this way do not depend on
+// what else was embedded, so a text embeds to the same vector in every
+// process and batch.
+const localReferenceWords = `the be to of and a in that have i it for not on with he as you do at
+	this but his by from they we say her she or an will my one all would there their what so up out if
+	about who get which go me when make can like time no just him know take people into year your good
+	some could them see other than then now look only come its over think also back after use two how
+	our work first well way even new want because any these give day most us is are was were been has
+	had did said made find here thing many very through long down still own should must before same
+	right too those both each few more such where why while tell great little life world house part
+	place number man woman child old high last between under never again another around small large
+	help point home school state family group problem fact hand week company system question
+	government program country night area end report service team project data page issue user file
+	code test release build change update document meeting policy process customer product`
+
+// localStopwords are the most frequent reference words. They say little
+// about a text's topic and contribute only their (low IDF) word feature.
+var localStopwords = map[string]bool{}
+
+// localIDF maps feature hashes to their IDF; features outside the reference
+// vocabulary get localMaxIDF.
+var (
+	localIDF    = map[uint64]float64{}
+	localMaxIDF float64
+)
+
+func init() {
+	for _, w := range strings.Fields(`a an and are as at be but by for from had has have he her his i if in
+		into is it its me my no not of on or our she so than that the their them then there these they
+		this to too up us was we were what when where which who will with you your`) {
+		localStopwords[w] = true
+	}
+	words := strings.Fields(localReferenceWords)
+	for w := range localStopwords {
+		if !slices.Contains(words, w) {
+			words = append(words, w)
+		}
+	}
+	floor := 1 / float64(len(words)+1)
+	localMaxIDF = math.Log(1 + 1/floor)
+	df := map[string]float64{}
+	for i, w := range words {
+		share := 1 / float64(i+1)
+		df["w:"+w] += share
+		grams := map[string]bool{}
+		padded := []rune(" " + w + " ")
+		for n := 3; n <= 5; n++ {
+			for j := 0; j+n <= len(padded); j++ {
+				grams[string(padded[j:j+n])] = true
+			}
+		}
+		for g := range grams {
+			df[g] += share
+		}
+	}
+	for feature, d := range df {
+		localIDF[featureHash(feature)] = math.Log(1 + 1/min(1, max(d, floor)))
+	}
+}
+
+// featureIDF returns the IDF of a hashed feature.
+func featureIDF(h uint64) float64 {
+	if idf, ok := localIDF[h]; ok {
+		return idf
+	}
+	return localMaxIDF
+}
+
+// localProvider embeds text without external services. Whole words and the
+// character n-grams (3 to 5 characters of each space-padded word) are
+// weighted by sublinear TF times IDF times a per-kind weight, then randomly
+// projected to dim: each feature adds its weight, with a pseudo-random sign,
+// to a few pseudo-random dimensions (a sparse Achlioptas projection). Texts
+// sharing vocabulary, including inflections and typos, end up close, so dev
+// and CI clusters mean something; it is no substitute for a trained model.
+// The provider is stateless and deterministic.
+type localProvider struct {
+	dim int
+}
+
+func newLocalProvider(dim int) *localProvider {
+	return &localProvider{dim: dim}
+}
+
+func (p *localProvider) EmbedText(_ string, texts []string) ([][]float32, error) {
+	if p.dim <= 0 {
+		return nil, errors.New("invalid embedding dimension")
+	}
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i] = p.project(textFeatures(t))
+	}
+	return out, nil
+}
+
+func (p *localProvider) project(features map[uint64]localFeature) []float32 {
+	// Sum in a fixed order so equal texts give bit-identical vectors.
+	keys := make([]uint64, 0, len(features))
+	for f := range features {
+		keys = append(keys, f)
+	}
+	slices.Sort(keys)
+	vec := make([]float32, p.dim)
+	for _, f := range keys {
+		lf := features[f]
+		w := (1 + math.Log(float64(lf.tf))) * lf.weight
+		h := f
+		for k := 0; k < localProjectionNNZ; k++ {
+			h = splitmix64(h)
+			idx := int(h % uint64(p.dim))
+			if h>>63 == 1 {
+				vec[idx] -= float32(w)
+			} else {
+				vec[idx] += float32(w)
+			}
+		}
+	}
+	// L2 norm
+	var norm float64
+	for _, v := range vec {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		n := float32(1 / math.Sqrt(norm))
+		for i := range vec {
+			vec[i] *= n
+		}
+	}
+	return vec
+}
+
+func (p *localProvider) ModelName() string {
+	return "local-ngram-idf-v3"
+}
+
+func (p *localProvider) Dimension() int {
+	return p.dim
+}
+
+// localFeature is a feature's count in a text and its weight (per-kind
+// weight times IDF).
+type localFeature struct {
+	tf     int
+	weight float64
+}
+
+// textFeatures returns hashed features: the lowercased words and the 3- to
+// 5-character n-grams of each word padded with spaces. Stopwords contribute
+// only their word feature.
+func textFeatures(text string) map[uint64]localFeature {
+	out := make(map[uint64]localFeature)
+	add := func(feature string, weight float64) {
+		h := featureHash(feature)
+		f := out[h]
+		f.tf++
+		f.weight = weight * featureIDF(h)
+		out[h] = f
+	}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		add("w:"+w, localWordWeight)
+		if localStopwords[w] {
+			continue
+		}
+		padded := []rune(" " + w + " ")
+		for n := 3; n <= 5; n++ {
+			for i := 0; i+n <= len(padded); i++ {
+				add(string(padded[i:i+n]), localNgramWeight*float64(n-2))
+			}
+		}
+	}
+	return out
+}
+
+func featureHash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// splitmix64 scrambles h; iterating it yields the projection's indexes and
+// signs for a feature.
+func splitmix64(h uint64) uint64 {
+	h += 0x9e3779b97f4a7c15
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
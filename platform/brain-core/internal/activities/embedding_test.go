@@ -0,0 +1,165 @@
+package activities
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nucleus/store-core/pkg/vectorstore"
+)
+
+// fakeProvider records its batches and fails the first calls with errs.
+type fakeProvider struct {
+	dim     int
+	errs    []error
+	batches [][]string
+}
+
+func (p *fakeProvider) EmbedText(_ string, texts []string) ([][]float32, error) {
+	p.batches = append(p.batches, texts)
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+		return nil, err
+	}
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i] = make([]float32, p.dim)
+		out[i][0] = float32(len(t))
+	}
+	return out, nil
+}
+
+func (p *fakeProvider) ModelName() string { return "fake" }
+func (p *fakeProvider) Dimension() int    { return p.dim }
+
+type mapEmbeddingCache map[string][]float32
+
+func (c mapEmbeddingCache) get(_ context.Context, model, hash string) ([]float32, bool) {
+	v, ok := c[model+":"+hash]
+	return v, ok
+}
+
+func (c mapEmbeddingCache) put(_ context.Context, model, hash string, vec []float32) {
+	c[model+":"+hash] = vec
+}
+
+func TestEmbedderBatchesRetriesAndCaches(t *testing.T) {
+	provider := &fakeProvider{dim: 4, errs: []error{&embeddingHTTPError{status: 429, retryAfter: 2 * time.Second}}}
+	cache := mapEmbeddingCache{}
+	var slept []time.Duration
+	e := newEmbedder(provider, 4, cache)
+	e.maxTokens, e.maxInputs, e.maxInputTokens = 10, 3, 8
+	e.sleep = func(_ context.Context, d time.Duration) error { slept = append(slept, d); return nil }
+
+	long := strings.Repeat("é", 20) // 40 bytes, over the 8-token input limit
+	texts := []string{"aaaaaa", "bbbbbb", "aaaaaa", "cccccccccccc", long, "d", "e", "f"}
+	vecs, stats, err := e.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vecs) != len(texts) || vecs[2][0] != vecs[0][0] {
+		t.Fatalf("expected one vector per text, duplicates shared: %v", vecs)
+	}
+	// 6-byte texts are 2 tokens, the 12-byte one 4, the truncated one 8
+	// (24 bytes), the single letters 1: batches close at 10 tokens or 3
+	// texts. The first batch is rate limited once.
+	first := []string{"aaaaaa", "bbbbbb", "cccccccccccc"}
+	want := [][]string{first, first, {strings.Repeat("é", 12), "d", "e"}, {"f"}}
+	if len(provider.batches) != len(want) {
+		t.Fatalf("batches: got %q", provider.batches)
+	}
+	for i := range want {
+		if strings.Join(provider.batches[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("batch %d: got %q want %q", i, provider.batches[i], want[i])
+		}
+	}
+	if stats.Retries != 1 || stats.Requests != 4 || stats.Embedded != 7 || stats.Truncated != 1 || len(slept) != 1 || slept[0] != 2*time.Second {
+		t.Errorf("expected one retry honouring Retry-After, got %+v slept=%v", stats, slept)
+	}
+
+	// A second call is served from the cache
+	provider.batches = nil
+	if _, stats, err = e.Embed(context.Background(), texts[:2]); err != nil || stats.Cached != 2 || len(provider.batches) != 0 {
+		t.Errorf("expected cache hits, got %+v batches=%q err=%v", stats, provider.batches, err)
+	}
+
+	// Client errors are not retried; wrong dimensions are rejected
+	provider.errs = []error{&embeddingHTTPError{status: 400}}
+	if _, stats, err = e.Embed(context.Background(), []string{"new"}); err == nil || stats.Retries != 0 {
+		t.Errorf("expected a 400 to fail without retries, got %+v err=%v", stats, err)
+	}
+	e.dim = 8
+	if _, _, err = e.Embed(context.Background(), []string{"other"}); err == nil {
+		t.Error("expected a dimension mismatch error")
+	}
+	if err := checkEmbeddingModel(provider, "text-embedding-3-large", 3072); err == nil {
+		t.Error("expected the provider to be rejected for the store's model")
+	}
+	if err := checkEmbeddingModel(provider, "text-embedding-3-small", 4); err == nil {
+		t.Error("expected a provider of another model with the same dimension to be rejected")
+	}
+	if err := checkEmbeddingModel(provider, vectorstore.DefaultModel, 4); err != nil {
+		t.Errorf("expected the default model to accept the provider, got %v", err)
+	}
+}
+
+func TestLocalProviderClustersSimilarTexts(t *testing.T) {
+	p := newLocalProvider(256)
+	texts := []string{
+		"Login page returns a 500 error after password reset",
+		"Error 500 on the login page after resetting the password",
+		"Quarterly revenue forecast for the EMEA sales region",
+		"EMEA sales revenue forecast, quarterly update",
+	}
+	vecs, err := p.EmbedText("", texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range vecs {
+		var norm float64
+		for _, x := range v {
+			norm += float64(x) * float64(x)
+		}
+		if math.Abs(norm-1) > 1e-4 {
+			t.Errorf("vector %d not unit length: |v|^2=%f", i, norm)
+		}
+	}
+	// The same text embeds identically alone, in another batch or provider.
+	alone, err := newLocalProvider(256).EmbedText("", texts[2:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(alone[0], vecs[2]) {
+		t.Error("expected the local embedding not to depend on the batch")
+	}
+	same1, same2 := dot(vecs[0], vecs[1]), dot(vecs[2], vecs[3])
+	cross := dot(vecs[0], vecs[2])
+	if same1 < 0.3 || same2 < 0.3 || cross > same1/2 || cross > same2/2 {
+		t.Errorf("expected topics to separate: login=%.3f revenue=%.3f cross=%.3f", same1, same2, cross)
+	}
+
+	// IDF comes from the reference vocabulary: frequent words weigh less.
+	the, team, kubernetes := featureIDF(featureHash("w:the")), featureIDF(featureHash("w:team")), featureIDF(featureHash("w:kubernetes"))
+	if !(the < team && team < kubernetes && kubernetes == localMaxIDF) {
+		t.Errorf("IDF the=%.2f team=%.2f kubernetes=%.2f", the, team, kubernetes)
+	}
+}
+
+func TestEmbeddingCacheExpiry(t *testing.T) {
+	now := time.Now()
+	cp := NewEmbeddingHashCheckpoint("h", "m", 2)
+	if embeddingCacheExpired(cp, now) {
+		t.Error("fresh entry expired")
+	}
+	if !embeddingCacheExpired(cp, now.Add(embeddingCacheTTL())) {
+		t.Error("entry past the TTL not expired")
+	}
+	cp.SavedAt = ""
+	if !embeddingCacheExpired(cp, now) {
+		t.Error("entry without a save time not expired")
+	}
+}
@@ -0,0 +1,431 @@
+package activities
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nucleus/store-core/pkg/entity"
+	"github.com/nucleus/store-core/pkg/llm"
+	"github.com/nucleus/store-core/pkg/ner"
+	"github.com/nucleus/store-core/pkg/vectorstore"
+	"github.com/nucleus/ucl-core/pkg/endpoint"
+	"github.com/nucleus/ucl-core/pkg/kgpb"
+	"go.temporal.io/sdk/activity"
+)
+
+// ClassifyDocuments runs EPP (entity/policy/process) classification over the
+// doc-domain records of a dataset (Confluence pages, OneDrive files). Policies
+// and processes are stored as canonical entities with their rules and steps,
+// and written to the KG: APPLIES_TO edges from a policy to its scopes, and
+// STEP_OF edges from each step to its process. Documents whose content hash
+// is unchanged since the last run are skipped.
+//
+// The entity registry comes from ENTITY_DATABASE_URL and the LLM from the
+// EPP_* / LLM_* variables (see llm.ConfigFromEnv).
+func (a *Activities) ClassifyDocuments(ctx context.Context, req IndexArtifactRequest) error {
+	logger := activity.GetLogger(ctx)
+	if strings.TrimSpace(req.SinkEndpointID) == "" {
+		return fmt.Errorf("sinkEndpointId is required")
+	}
+	er, err := openEntityRegistry()
+	if err != nil {
+		return fmt.Errorf("open entity registry: %w", err)
+	}
+	if er == nil {
+		logger.Info("epp: ENTITY_DATABASE_URL not set, skipping classification")
+		return nil
+	}
+
+	cfg := llm.ConfigFromEnv("EPP_")
+	if cfg.Provider == "" {
+		cfg.Provider = llm.ProviderOpenAI
+	}
+	client, err := llm.New(cfg)
+	if err != nil {
+		return fmt.Errorf("init llm: %w", err)
+	}
+	catalog := ner.NewEPPCatalog(ner.NewEPPClassifier(llm.NewNERProvider(client), cfg.Model), er.registry)
+
+	useStaging := strings.TrimSpace(req.StageRef) != "" && len(req.BatchRefs) > 0
+	var (
+		iter    endpoint.Iterator[endpoint.Record]
+		closeFn func()
+	)
+	if useStaging {
+		iter, closeFn, err = streamFromStaging(ctx, req.StagingProviderID, req.StageRef, "", req.DatasetSlug, req.Checkpoint, 0)
+	} else {
+		iter, closeFn, err = streamDataset(ctx, req.SinkEndpointID, req.EndpointConfig, req.DatasetSlug, req.Checkpoint, 0)
+	}
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	tenant := getenv("TENANT_ID", "dev")
+	if req.TenantID != "" {
+		tenant = req.TenantID
+	}
+	project := getenv("METADATA_DEFAULT_PROJECT", "global")
+	if req.ProjectID != "" {
+		project = req.ProjectID
+	}
+	profileID := resolveProfileID(req)
+	kgc := newKgGRPCClient()
+	defer kgc.Close()
+
+	var kbEvents []kbEvent
+	var kbSeq int64
+	var docs, skipped, policies, processes, failed int64
+	for iter.Next() {
+		doc, ok := eppDocument(iter.Value(), req, profileID, tenant, project)
+		if !ok {
+			continue
+		}
+		docs++
+		hash := hashContent(doc.Title + "\n" + doc.Content)
+		if prev, _ := loadEPPHash(ctx, tenant, project, doc.NodeID); prev == hash {
+			skipped++
+			continue
+		}
+
+		res, err := catalog.Classify(ctx, tenant, doc)
+		if err != nil {
+			// One bad document (LLM error, unparsable answer) must not fail the run
+			logger.Warn("epp: classification failed", "nodeId", doc.NodeID, "error", err)
+			failed++
+			continue
+		}
+		// A failed KG write leaves the hash unsaved, so the next run
+		// classifies the document again and retries the writes.
+		written := true
+		if res.RemovedID != "" {
+			logger.Info("epp: document changed type, entity removed", "nodeId", doc.NodeID, "entityId", res.RemovedID)
+			if kgc != nil {
+				events, err := kgc.removeEPP(ctx, tenant, project, req, res.RemovedID, &kbSeq)
+				kbEvents = append(kbEvents, events...)
+				if err != nil {
+					logger.Warn("epp: remove entity from KG failed", "entityId", res.RemovedID, "error", err)
+					written = false
+				}
+			}
+		}
+		if res.Entity != nil {
+			switch res.Entity.Type {
+			case string(entity.EntityTypePolicy):
+				policies++
+			case string(entity.EntityTypeProcess):
+				processes++
+			}
+			if kgc != nil {
+				events, err := kgc.upsertEPP(ctx, tenant, project, req, res.Entity, &kbSeq)
+				kbEvents = append(kbEvents, events...)
+				if err != nil {
+					logger.Warn("epp: write entity to KG failed", "entityId", res.Entity.ID, "error", err)
+					written = false
+				}
+			}
+		}
+		if !written {
+			failed++
+			continue
+		}
+		saveEPPHash(ctx, tenant, project, doc.NodeID, hash)
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf("epp: docs=%d skipped=%d policies=%d processes=%d failed=%d", docs, skipped, policies, processes, failed))
+	if len(kbEvents) > 0 {
+		saveKBEvents(ctx, tenant, project, req.DatasetSlug, req.RunID, kbEvents, kbSeq)
+	}
+	return nil
+}
+
+// eppDocument extracts a classifiable document from a record: pre-normalized
+// vector payloads from staging first, then the profile normalizer. Only
+// doc-domain records qualify: doc.page, doc.file and document body records.
+func eppDocument(rec map[string]any, req IndexArtifactRequest, profileID, tenant, project string) (ner.EPPDocument, bool) {
+	var entry vectorstore.Entry
+	if vp, ok := rec["vectorPayload"].(map[string]any); ok && vp != nil {
+		entry = vectorstore.Entry{
+			NodeID:       asString(vp["nodeId"]),
+			SourceFamily: asString(vp["sourceFamily"]),
+			EntityKind:   asString(vp["entityKind"]),
+			ContentText:  asString(vp["text"]),
+		}
+		entry.Metadata, _ = vp["metadata"].(map[string]any)
+	} else {
+		var ok bool
+		if entry, _, ok = normalizeVectorRecord(rec, profileID, tenant, project, req.DatasetSlug, req.SinkEndpointID); !ok {
+			return ner.EPPDocument{}, false
+		}
+	}
+	if !isDocKind(entry.EntityKind) || entry.NodeID == "" || strings.TrimSpace(entry.ContentText) == "" {
+		return ner.EPPDocument{}, false
+	}
+	source := entry.SourceFamily
+	if source == "" {
+		source = strings.ToLower(req.SourceFamily)
+	}
+	title := asString(entry.Metadata["title"])
+	if title == "" {
+		title = asString(entry.Metadata["name"])
+	}
+	return ner.EPPDocument{
+		Source:  source,
+		ID:      entry.NodeID,
+		NodeID:  entry.NodeID,
+		URL:     asString(entry.Metadata["url"]),
+		Title:   title,
+		Content: entry.ContentText,
+	}, true
+}
+
+func isDocKind(kind string) bool {
+	return strings.HasPrefix(kind, "doc.") || strings.HasPrefix(kind, "document.") && strings.HasSuffix(kind, ".body")
+}
+
+// makeEPPHashKey creates a KV key for the content hash a document was last
+// classified at. Format: epp:<nodeID>
+func makeEPPHashKey(nodeID string) string {
+	return "epp:" + nodeID
+}
+
+func loadEPPHash(ctx context.Context, tenantID, projectID, nodeID string) (string, error) {
+	m, err := loadCheckpointKV(ctx, tenantID, projectID, makeEPPHashKey(nodeID))
+	if err != nil || m == nil {
+		return "", err
+	}
+	hash, _ := m["contentHash"].(string)
+	return hash, nil
+}
+
+func saveEPPHash(ctx context.Context, tenantID, projectID, nodeID, contentHash string) {
+	_ = saveCheckpointKV(ctx, tenantID, projectID, makeEPPHashKey(nodeID), map[string]any{
+		"contentHash":  contentHash,
+		"classifiedAt": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+var scopeSlugRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// eppListLimit bounds the steps of a process and the scopes of a policy
+// read back from the KG when pruning.
+const eppListLimit = 1000
+
+// eppRecorder returns a func appending a KB event for req to events.
+func eppRecorder(req IndexArtifactRequest, seq *int64, events *[]kbEvent) func(op, kind, id string) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return func(op, kind, id string) {
+		*seq++
+		h := sha1.Sum([]byte(id + req.RunID))
+		*events = append(*events, kbEvent{
+			Seq:         *seq,
+			RunID:       req.RunID,
+			DatasetSlug: req.DatasetSlug,
+			Op:          op,
+			Kind:        kind,
+			ID:          id,
+			Hash:        fmt.Sprintf("%x", h[:6]),
+			At:          now,
+		})
+	}
+}
+
+// upsertEPP writes a policy or process node, its scope or step nodes, and
+// the APPLIES_TO / STEP_OF edges between them. Steps and scopes written by
+// an earlier classification of the same entity that are no longer part of
+// it are removed. Writing stops at the first failed write, whose error is
+// returned; events are only recorded for the writes that succeeded.
+func (c *kgClient) upsertEPP(ctx context.Context, tenant, project string, req IndexArtifactRequest, e *entity.CanonicalEntity, seq *int64) ([]kbEvent, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	var events []kbEvent
+	var writeErr error
+	record := eppRecorder(req, seq, &events)
+	node := func(id, typ string, props map[string]string) {
+		if writeErr != nil {
+			return
+		}
+		if _, err := c.client.UpsertNode(ctx, &kgpb.UpsertNodeRequest{
+			TenantId:  tenant,
+			ProjectId: project,
+			Node:      &kgpb.Node{Id: id, Type: typ, Properties: props},
+		}); err != nil {
+			writeErr = fmt.Errorf("upsert node %s: %w", id, err)
+			return
+		}
+		record("upsert_node", typ, id)
+	}
+	edge := func(typ, from, to string, props map[string]string) {
+		if writeErr != nil {
+			return
+		}
+		id := fmt.Sprintf("%s:%s:%s", strings.ToLower(typ), from, to)
+		if _, err := c.client.UpsertEdge(ctx, &kgpb.UpsertEdgeRequest{
+			TenantId:  tenant,
+			ProjectId: project,
+			Edge:      &kgpb.Edge{Id: id, Type: typ, FromId: from, ToId: to, Properties: props},
+		}); err != nil {
+			writeErr = fmt.Errorf("upsert edge %s: %w", id, err)
+			return
+		}
+		record("upsert_edge", typ, id)
+	}
+
+	docNode := ""
+	if len(e.SourceRefs) > 0 {
+		docNode = e.SourceRefs[0].NodeID
+	}
+	switch e.Type {
+	case string(entity.EntityTypePolicy):
+		p, err := entity.PolicyFromCanonical(e)
+		if err != nil {
+			return events, nil
+		}
+		node(p.ID, "entity.policy", map[string]string{
+			"name":        p.Name,
+			"enforcement": p.Enforcement,
+			"rules":       fmt.Sprintf("%d", len(p.Rules)),
+			"docNodeId":   docNode,
+			"updatedAt":   now,
+		})
+		scopes := map[string]bool{}
+		for _, scope := range p.AppliesTo {
+			slug := strings.Trim(scopeSlugRe.ReplaceAllString(strings.ToLower(scope), "-"), "-")
+			if slug == "" {
+				continue
+			}
+			scopeID := "policy.scope:" + slug
+			scopes[scopeID] = true
+			node(scopeID, "policy.scope", map[string]string{"name": scope})
+			edge("APPLIES_TO", p.ID, scopeID, map[string]string{"enforcement": p.Enforcement})
+		}
+		if writeErr != nil {
+			return events, writeErr
+		}
+		return events, c.pruneEPPScopes(ctx, tenant, project, p.ID, scopes, record)
+	case string(entity.EntityTypeProcess):
+		p, err := entity.ProcessFromCanonical(e)
+		if err != nil {
+			return events, nil
+		}
+		node(p.ID, "entity.process", map[string]string{
+			"name":      p.Name,
+			"owner":     p.Owner,
+			"steps":     fmt.Sprintf("%d", len(p.Steps)),
+			"docNodeId": docNode,
+			"updatedAt": now,
+		})
+		steps := map[string]bool{}
+		for _, step := range p.Steps {
+			stepID := fmt.Sprintf("%s:step:%s", p.ID, step.ID)
+			steps[stepID] = true
+			node(stepID, "process.step", map[string]string{
+				"name":   step.Name,
+				"actor":  step.Actor,
+				"order":  fmt.Sprintf("%d", step.Order),
+				"detail": step.Description,
+			})
+			edge("STEP_OF", stepID, p.ID, map[string]string{"order": fmt.Sprintf("%d", step.Order)})
+		}
+		if writeErr != nil {
+			return events, writeErr
+		}
+		return events, c.pruneEPPSteps(ctx, tenant, project, p.ID, steps, record)
+	}
+	return events, nil
+}
+
+// removeEPP deletes the KG node of a policy or process that is no longer
+// classified as such, together with its steps and the scopes no other
+// policy applies to.
+func (c *kgClient) removeEPP(ctx context.Context, tenant, project string, req IndexArtifactRequest, id string, seq *int64) ([]kbEvent, error) {
+	var events []kbEvent
+	record := eppRecorder(req, seq, &events)
+	if err := c.pruneEPPSteps(ctx, tenant, project, id, nil, record); err != nil {
+		return events, err
+	}
+	if err := c.pruneEPPScopes(ctx, tenant, project, id, nil, record); err != nil {
+		return events, err
+	}
+	res, err := c.client.DeleteNode(ctx, &kgpb.DeleteNodeRequest{TenantId: tenant, ProjectId: project, NodeId: id})
+	if err != nil {
+		return events, fmt.Errorf("delete node %s: %w", id, err)
+	}
+	if res.Deleted {
+		record("delete_node", "entity", id)
+	}
+	return events, nil
+}
+
+// pruneEPPSteps deletes the step nodes of processID that are not in keep.
+func (c *kgClient) pruneEPPSteps(ctx context.Context, tenant, project, processID string, keep map[string]bool, record func(op, kind, id string)) error {
+	resp, err := c.client.ListEdges(ctx, &kgpb.ListEdgesRequest{
+		TenantId:  tenant,
+		ProjectId: project,
+		EdgeTypes: []string{"STEP_OF"},
+		TargetId:  processID,
+		Limit:     eppListLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("list steps of %s: %w", processID, err)
+	}
+	for _, e := range resp.Edges {
+		if keep[e.FromId] {
+			continue
+		}
+		if _, err := c.client.DeleteNode(ctx, &kgpb.DeleteNodeRequest{TenantId: tenant, ProjectId: project, NodeId: e.FromId}); err != nil {
+			return fmt.Errorf("delete step %s: %w", e.FromId, err)
+		}
+		record("delete_node", "process.step", e.FromId)
+	}
+	return nil
+}
+
+// pruneEPPScopes deletes the APPLIES_TO edges of policyID to scopes not in
+// keep. Scope nodes are shared between policies, so a scope node is only
+// deleted once no policy applies to it any more.
+func (c *kgClient) pruneEPPScopes(ctx context.Context, tenant, project, policyID string, keep map[string]bool, record func(op, kind, id string)) error {
+	resp, err := c.client.ListEdges(ctx, &kgpb.ListEdgesRequest{
+		TenantId:  tenant,
+		ProjectId: project,
+		EdgeTypes: []string{"APPLIES_TO"},
+		SourceId:  policyID,
+		Limit:     eppListLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("list scopes of %s: %w", policyID, err)
+	}
+	for _, e := range resp.Edges {
+		if keep[e.ToId] {
+			continue
+		}
+		if _, err := c.client.DeleteEdge(ctx, &kgpb.DeleteEdgeRequest{TenantId: tenant, ProjectId: project, EdgeId: e.Id}); err != nil {
+			return fmt.Errorf("delete edge %s: %w", e.Id, err)
+		}
+		record("delete_edge", "APPLIES_TO", e.Id)
+		rest, err := c.client.ListEdges(ctx, &kgpb.ListEdgesRequest{
+			TenantId:  tenant,
+			ProjectId: project,
+			EdgeTypes: []string{"APPLIES_TO"},
+			TargetId:  e.ToId,
+			Limit:     1,
+		})
+		if err != nil {
+			return fmt.Errorf("list policies of %s: %w", e.ToId, err)
+		}
+		if len(rest.Edges) > 0 {
+			continue
+		}
+		if _, err := c.client.DeleteNode(ctx, &kgpb.DeleteNodeRequest{TenantId: tenant, ProjectId: project, NodeId: e.ToId}); err != nil {
+			return fmt.Errorf("delete scope %s: %w", e.ToId, err)
+		}
+		record("delete_node", "policy.scope", e.ToId)
+	}
+	return nil
+}
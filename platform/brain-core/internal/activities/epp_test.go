@@ -0,0 +1,143 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/nucleus/store-core/pkg/entity"
+	"github.com/nucleus/ucl-core/pkg/kgpb"
+	"google.golang.org/grpc"
+)
+
+// fakeKg keeps nodes and edges in maps; only the calls EPP makes are
+// implemented.
+type fakeKg struct {
+	kgpb.KgServiceClient
+	nodes map[string]*kgpb.Node
+	edges map[string]*kgpb.Edge
+	// failEdges makes UpsertEdge fail.
+	failEdges bool
+}
+
+func newFakeKg() *fakeKg {
+	return &fakeKg{nodes: map[string]*kgpb.Node{}, edges: map[string]*kgpb.Edge{}}
+}
+
+func (f *fakeKg) UpsertNode(_ context.Context, in *kgpb.UpsertNodeRequest, _ ...grpc.CallOption) (*kgpb.UpsertNodeResponse, error) {
+	f.nodes[in.Node.Id] = in.Node
+	return &kgpb.UpsertNodeResponse{Node: in.Node}, nil
+}
+
+func (f *fakeKg) UpsertEdge(_ context.Context, in *kgpb.UpsertEdgeRequest, _ ...grpc.CallOption) (*kgpb.UpsertEdgeResponse, error) {
+	if f.failEdges {
+		return nil, errors.New("kg unavailable")
+	}
+	f.edges[in.Edge.Id] = in.Edge
+	return &kgpb.UpsertEdgeResponse{Edge: in.Edge}, nil
+}
+
+func (f *fakeKg) ListEdges(_ context.Context, in *kgpb.ListEdgesRequest, _ ...grpc.CallOption) (*kgpb.ListEdgesResponse, error) {
+	var out []*kgpb.Edge
+	for _, e := range f.edges {
+		if (len(in.EdgeTypes) == 0 || e.Type == in.EdgeTypes[0]) &&
+			(in.SourceId == "" || e.FromId == in.SourceId) && (in.TargetId == "" || e.ToId == in.TargetId) {
+			out = append(out, e)
+		}
+	}
+	return &kgpb.ListEdgesResponse{Edges: out}, nil
+}
+
+func (f *fakeKg) DeleteNode(_ context.Context, in *kgpb.DeleteNodeRequest, _ ...grpc.CallOption) (*kgpb.DeleteNodeResponse, error) {
+	_, ok := f.nodes[in.NodeId]
+	delete(f.nodes, in.NodeId)
+	for id, e := range f.edges {
+		if e.FromId == in.NodeId || e.ToId == in.NodeId {
+			delete(f.edges, id)
+		}
+	}
+	return &kgpb.DeleteNodeResponse{Deleted: ok}, nil
+}
+
+func (f *fakeKg) DeleteEdge(_ context.Context, in *kgpb.DeleteEdgeRequest, _ ...grpc.CallOption) (*kgpb.DeleteEdgeResponse, error) {
+	_, ok := f.edges[in.EdgeId]
+	delete(f.edges, in.EdgeId)
+	return &kgpb.DeleteEdgeResponse{Deleted: ok}, nil
+}
+
+func (f *fakeKg) nodeIDs() []string {
+	var out []string
+	for id := range f.nodes {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestUpsertEPPPrunesStaleStepsAndScopes(t *testing.T) {
+	ctx := context.Background()
+	kg := newFakeKg()
+	c := &kgClient{client: kg}
+	var seq int64
+
+	process := func(steps ...string) *entity.CanonicalEntity {
+		p := &entity.ProcessEntity{CanonicalEntity: entity.CanonicalEntity{ID: "proc"}}
+		for i, s := range steps {
+			p.Steps = append(p.Steps, entity.ProcessStep{ID: s, Order: i + 1, Name: s})
+		}
+		return p.Canonical()
+	}
+	policy := func(id string, scopes ...string) *entity.CanonicalEntity {
+		p := &entity.PolicyEntity{CanonicalEntity: entity.CanonicalEntity{ID: id}, AppliesTo: scopes}
+		return p.Canonical()
+	}
+	for _, e := range []*entity.CanonicalEntity{
+		process("a", "b", "c"), process("a"),
+		policy("pol1", "Laptops", "Servers"), policy("pol2", "Servers"), policy("pol1", "Laptops"),
+	} {
+		if _, err := c.upsertEPP(ctx, "t", "p", IndexArtifactRequest{}, e, &seq); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := "[pol1 pol2 policy.scope:laptops policy.scope:servers proc proc:step:a]"
+	if got := kg.nodeIDs(); fmt.Sprint(got) != want {
+		t.Fatalf("nodes after re-classification = %v, want %s", got, want)
+	}
+	if _, ok := kg.edges["applies_to:pol1:policy.scope:servers"]; ok {
+		t.Fatal("expected the dropped APPLIES_TO edge to be deleted")
+	}
+
+	for _, id := range []string{"proc", "pol2"} {
+		if _, err := c.removeEPP(ctx, "t", "p", IndexArtifactRequest{}, id, &seq); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want = "[pol1 policy.scope:laptops]"
+	if got := kg.nodeIDs(); fmt.Sprint(got) != want {
+		t.Fatalf("nodes after removal = %v, want %s", got, want)
+	}
+	if len(kg.edges) != 1 {
+		t.Fatalf("expected only pol1's edge to remain, got %v", kg.edges)
+	}
+}
+
+func TestUpsertEPPReportsFailedWrites(t *testing.T) {
+	kg := newFakeKg()
+	kg.failEdges = true
+	c := &kgClient{client: kg}
+	var seq int64
+	p := &entity.PolicyEntity{CanonicalEntity: entity.CanonicalEntity{ID: "pol"}, AppliesTo: []string{"Laptops", "Servers"}}
+	events, err := c.upsertEPP(context.Background(), "t", "p", IndexArtifactRequest{}, p.Canonical(), &seq)
+	if err == nil {
+		t.Fatal("expected the failed APPLIES_TO write to be returned")
+	}
+	// The policy and first scope node were written before the edge failed.
+	if len(events) != 2 || events[0].ID != "pol" || events[1].ID != "policy.scope:laptops" {
+		t.Fatalf("expected events for the successful writes only, got %+v", events)
+	}
+	if _, ok := kg.nodes["policy.scope:servers"]; ok {
+		t.Fatal("expected writing to stop at the first failure")
+	}
+}
@@ -0,0 +1,20 @@
+package activities
+
+import (
+	"context"
+	"time"
+
+	"github.com/nucleus/store-core/pkg/graphrag"
+)
+
+// invalidateGraphRAGCache bumps the tenant's GraphRAG cache generation so
+// context caches stop serving contexts built before this dataset was indexed.
+// The generation is the version of a tenant-scoped KV key.
+func invalidateGraphRAGCache(ctx context.Context, tenantID, datasetSlug, runID string) error {
+	return saveCheckpointKV(ctx, tenantID, "", graphrag.CacheGenerationKey, map[string]any{
+		"reason":        "reindex",
+		"datasetSlug":   datasetSlug,
+		"runId":         runID,
+		"invalidatedAt": time.Now().UTC().Format(time.RFC3339),
+	})
+}
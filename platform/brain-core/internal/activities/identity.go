@@ -0,0 +1,175 @@
+package activities
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nucleus/store-core/pkg/entity"
+	"github.com/nucleus/ucl-core/pkg/kgpb"
+)
+
+// entityRegistry is the store-core entity registry, opened from
+// ENTITY_DATABASE_URL.
+type entityRegistry struct {
+	db       *sql.DB
+	registry *entity.PostgresEntityRegistry
+}
+
+var (
+	entityRegMu   sync.Mutex
+	entityRegInst *entityRegistry
+)
+
+// openEntityRegistry returns the worker's shared registry, opening it (and
+// running its schema DDL) on first use. A failed open is retried on the
+// next call. It returns nil when ENTITY_DATABASE_URL is not set.
+func openEntityRegistry() (*entityRegistry, error) {
+	entityRegMu.Lock()
+	defer entityRegMu.Unlock()
+	if entityRegInst != nil {
+		return entityRegInst, nil
+	}
+	dsn := getenv("ENTITY_DATABASE_URL", "")
+	if dsn == "" {
+		return nil, nil
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	registry, err := entity.NewPostgresEntityRegistry(db)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	entityRegInst = &entityRegistry{db: db, registry: registry}
+	return entityRegInst, nil
+}
+
+// identityOrgDomains reads IDENTITY_ORG_DOMAINS, the organization's email
+// domains (comma-separated, primary first).
+func identityOrgDomains() []string {
+	var domains []string
+	for _, d := range strings.Split(getenv("IDENTITY_ORG_DOMAINS", ""), ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+// identityPayload unwraps the connector record from a staging envelope.
+func identityPayload(rec map[string]any) map[string]any {
+	for _, key := range []string{"payload", "rawPayload"} {
+		if payload, ok := rec[key].(map[string]any); ok {
+			if inner, ok := payload["payload"].(map[string]any); ok {
+				return inner
+			}
+			return payload
+		}
+	}
+	return rec
+}
+
+// resolveIdentities links the accounts seen in an artifact to persons and
+// writes a person node plus one SAME_AS edge per account node. Accounts of
+// one person therefore meet at the person node, whichever source they
+// came from. KG writes that fail are skipped and reported in the returned
+// error; the events of the writes that succeeded are still returned.
+func resolveIdentities(ctx context.Context, tenant, project string, req IndexArtifactRequest, accounts []entity.Account, seq *int64) ([]kbEvent, error) {
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+	er, err := openEntityRegistry()
+	if err != nil || er == nil {
+		return nil, err
+	}
+
+	res, err := entity.NewIdentityResolver(er.registry, identityOrgDomains()...).Resolve(ctx, tenant, accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	kgc := newKgGRPCClient()
+	defer kgc.Close()
+	if kgc == nil {
+		return nil, nil
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	var events []kbEvent
+	var failed int
+	var firstErr error
+	fail := func(err error) {
+		failed++
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	seen := map[string]bool{}
+	for _, link := range res.Links {
+		if !seen[link.PersonID] {
+			seen[link.PersonID] = true
+			_, err := kgc.client.UpsertNode(ctx, &kgpb.UpsertNodeRequest{
+				TenantId:  tenant,
+				ProjectId: project,
+				Node: &kgpb.Node{
+					Id:   link.PersonID,
+					Type: "entity.person",
+					Properties: map[string]string{
+						"name":      link.PersonName,
+						"updatedAt": now,
+					},
+				},
+			})
+			if err != nil {
+				fail(fmt.Errorf("upsert person %s: %w", link.PersonID, err))
+			}
+		}
+
+		accountNode := link.Account.NodeID
+		if accountNode == "" {
+			accountNode = entity.AccountNodeID(link.Account.Source, link.Account.AccountID)
+		}
+		edgeID := fmt.Sprintf("same_as:%s:%s", accountNode, link.PersonID)
+		_, err := kgc.client.UpsertEdge(ctx, &kgpb.UpsertEdgeRequest{
+			TenantId:  tenant,
+			ProjectId: project,
+			Edge: &kgpb.Edge{
+				Id:     edgeID,
+				Type:   "SAME_AS",
+				FromId: accountNode,
+				ToId:   link.PersonID,
+				Properties: map[string]string{
+					"source":    link.Account.Source,
+					"accountId": link.Account.AccountID,
+					"runId":     req.RunID,
+				},
+			},
+		})
+		if err != nil {
+			fail(fmt.Errorf("upsert edge %s: %w", edgeID, err))
+			continue
+		}
+		*seq++
+		h := sha1.Sum([]byte(edgeID + req.RunID))
+		events = append(events, kbEvent{
+			Seq:         *seq,
+			RunID:       req.RunID,
+			DatasetSlug: req.DatasetSlug,
+			Op:          "upsert_edge",
+			Kind:        "SAME_AS",
+			ID:          edgeID,
+			Hash:        fmt.Sprintf("%x", h[:6]),
+			At:          now,
+		})
+	}
+	if failed > 0 {
+		return events, fmt.Errorf("identity: %d KG writes failed, first: %w", failed, firstErr)
+	}
+	return events, nil
+}
@@ -3,13 +3,20 @@ package activities
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
+
+	"github.com/nucleus/store-core/pkg/llm"
 )
 
 // insightClient is a placeholder for LLM-backed insight generation.
 // If INSIGHT_PROVIDER is not set, calls are skipped.
 type insightClient struct {
 	provider string
+	// repairAttempts bounds the re-prompts sent after a response fails the
+	// skill's output schema (INSIGHT_REPAIR_ATTEMPTS).
+	repairAttempts int
+	chat           func(ctx context.Context, skill InsightSkill, messages []llm.Message) (string, error)
 }
 
 func newInsightClient() *insightClient {
@@ -17,13 +24,29 @@ func newInsightClient() *insightClient {
 	if provider == "" {
 		return nil
 	}
-	return &insightClient{provider: provider}
+	return &insightClient{provider: provider, repairAttempts: getEnvInt("INSIGHT_REPAIR_ATTEMPTS", 2), chat: chatLLM}
+}
+
+// insightValidationError is returned when a response still fails the
+// skill's output schema after the repair attempts. It carries the raw
+// responses for the dead-letter record.
+type insightValidationError struct {
+	SkillID      string
+	SkillVersion string
+	Problems     []string // violations in the last response
+	Responses    []string // raw output, one per attempt
+}
+
+func (e *insightValidationError) Error() string {
+	return fmt.Sprintf("insight skill %s: response failed its output schema after %d attempts: %s",
+		e.SkillID, len(e.Responses), strings.Join(e.Problems, "; "))
 }
 
 // Insight represents a structured insight result.
 type Insight struct {
 	Provider        string           `json:"provider,omitempty"`
 	PromptID        string           `json:"promptId,omitempty"`
+	SkillVersion    string           `json:"skillVersion,omitempty"`
 	EntityRef       string           `json:"entityRef,omitempty"`
 	WorkspaceID     string           `json:"workspaceId,omitempty"`
 	EntityType      string           `json:"entityType,omitempty"`
@@ -60,17 +83,68 @@ type InsightSignal struct {
 }
 
 // Summarize returns zero or more insights for a record. If no provider is configured, returns nil.
+// When the skill declares an output schema, responses that fail it are sent
+// back with the violations until they pass or the repair attempts run out,
+// in which case the error is an *insightValidationError.
 func (c *insightClient) Summarize(ctx context.Context, skill InsightSkill, params map[string]string) ([]Insight, error) {
-	_ = ctx
+	list, _, err := c.summarize(ctx, skill, params)
+	return list, err
+}
+
+// summarize is Summarize that also returns the number of LLM calls made.
+func (c *insightClient) summarize(ctx context.Context, skill InsightSkill, params map[string]string) ([]Insight, int, error) {
 	if c == nil {
-		return nil, nil
+		return nil, 0, nil
+	}
+	chat := c.chat
+	if chat == nil {
+		chat = chatLLM
 	}
-	prompt := buildInsightPrompt(skill, params)
-	resp, err := callLLM(ctx, skill, prompt)
-	if err != nil || strings.TrimSpace(resp) == "" {
-		return nil, err
+	messages := []llm.Message{{Role: "user", Content: buildInsightPrompt(skill, params)}}
+	var responses []string
+	for attempt := 0; ; attempt++ {
+		resp, err := chat(ctx, skill, messages)
+		if err != nil {
+			return nil, attempt + 1, err
+		}
+		if skill.OutputSchema == nil {
+			if strings.TrimSpace(resp) == "" {
+				return nil, attempt + 1, nil
+			}
+			list, err := parseInsightJSON(resp, skill.MaxInsights)
+			return stampSkillVersion(list, skill), attempt + 1, err
+		}
+		responses = append(responses, resp)
+		problems := validateInsightOutput(skill.OutputSchema, resp)
+		if len(problems) == 0 {
+			list, err := parseInsightJSON(strings.TrimSpace(resp), skill.MaxInsights)
+			return stampSkillVersion(list, skill), attempt + 1, err
+		}
+		if attempt >= c.repairAttempts {
+			return nil, attempt + 1, &insightValidationError{SkillID: skill.ID, SkillVersion: skill.Version, Problems: problems, Responses: responses}
+		}
+		messages = append(messages,
+			llm.Message{Role: "assistant", Content: resp},
+			llm.Message{Role: "user", Content: buildRepairPrompt(problems)})
+	}
+}
+
+func stampSkillVersion(list []Insight, skill InsightSkill) []Insight {
+	for i := range list {
+		list[i].SkillVersion = skill.Version
+	}
+	return list
+}
+
+// buildRepairPrompt asks the model to correct its previous answer.
+func buildRepairPrompt(problems []string) string {
+	var b strings.Builder
+	b.WriteString("Your response does not match the required JSON schema:\n")
+	for _, p := range problems {
+		b.WriteString("- " + p + "\n")
 	}
-	return parseInsightJSON(resp, skill.MaxInsights)
+	b.WriteString("Return the corrected JSON only, with no commentary. Use only the allowed values and ranges.")
+	return b.String()
 }
 
 // buildInsightPrompt crafts a structured prompt for an LLM.
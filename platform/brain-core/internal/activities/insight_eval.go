@@ -0,0 +1,326 @@
+package activities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InsightFixture is one record of an offline comparison set, with the
+// outcome a reviewer expects from the skill.
+type InsightFixture struct {
+	ID         string             `yaml:"id" json:"id"`
+	EntityKind string             `yaml:"entityKind" json:"entityKind,omitempty"`
+	Payload    map[string]any     `yaml:"payload" json:"payload"`
+	Expect     InsightExpectation `yaml:"expect" json:"expect"`
+}
+
+// InsightExpectation holds the graded parts of a fixture. Empty fields are
+// not scored; an empty (not absent) signal list expects no signals.
+type InsightExpectation struct {
+	Sentiment  string    `yaml:"sentiment" json:"sentiment,omitempty"`
+	Signals    []string  `yaml:"signals" json:"signals,omitempty"`
+	Escalation []float64 `yaml:"escalation" json:"escalation,omitempty"` // [min, max]
+}
+
+// InsightComparison scores two versions of a skill on the same fixtures.
+type InsightComparison struct {
+	SkillID     string                    `json:"skillId"`
+	GeneratedAt string                    `json:"generatedAt"`
+	A           InsightVersionScore       `json:"a"`
+	B           InsightVersionScore       `json:"b"`
+	Winner      string                    `json:"winner"` // the better version, or "tie"
+	Records     []InsightRecordComparison `json:"records"`
+}
+
+// InsightVersionScore aggregates one version's results.
+type InsightVersionScore struct {
+	Version            string  `json:"version"`
+	Records            int     `json:"records"`
+	Valid              int     `json:"valid"`        // passed the output schema
+	FirstPass          int     `json:"firstPass"`    // passed without a repair prompt
+	DeadLettered       int     `json:"deadLettered"` // still invalid after the repairs
+	Errors             int     `json:"errors"`       // LLM or input errors
+	LLMCalls           int     `json:"llmCalls"`
+	SentimentAccuracy  float64 `json:"sentimentAccuracy"`
+	SignalPrecision    float64 `json:"signalPrecision"`
+	SignalRecall       float64 `json:"signalRecall"`
+	SignalF1           float64 `json:"signalF1"`
+	EscalationAccuracy float64 `json:"escalationAccuracy"`
+	Score              float64 `json:"score"` // mean record score
+	MeanLatencyMs      float64 `json:"meanLatencyMs"`
+}
+
+// InsightRecordComparison holds both versions' results for one fixture.
+type InsightRecordComparison struct {
+	ID     string              `json:"id"`
+	A      InsightRecordResult `json:"a"`
+	B      InsightRecordResult `json:"b"`
+	Winner string              `json:"winner"`
+}
+
+// InsightRecordResult is one version's output for a fixture and its score
+// between 0 and 1.
+type InsightRecordResult struct {
+	Valid           bool     `json:"valid"`
+	DeadLettered    bool     `json:"deadLettered,omitempty"`
+	Calls           int      `json:"calls"`
+	Error           string   `json:"error,omitempty"`
+	Summary         string   `json:"summary,omitempty"`
+	Sentiment       string   `json:"sentiment,omitempty"`
+	Signals         []string `json:"signals,omitempty"`
+	EscalationScore float64  `json:"escalationScore"`
+	Score           float64  `json:"score"`
+	LatencyMs       int64    `json:"latencyMs"`
+}
+
+// LoadInsightFixtures reads a YAML fixture set with a top-level records list.
+func LoadInsightFixtures(path string) ([]InsightFixture, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		Records []InsightFixture `yaml:"records"`
+	}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(raw.Records) == 0 {
+		return nil, fmt.Errorf("%s: no records", path)
+	}
+	return raw.Records, nil
+}
+
+// CompareInsightSkillVersions runs versions a and b of a skill from dir over
+// the fixtures with the INSIGHT_* configured LLM.
+func CompareInsightSkillVersions(ctx context.Context, dir, skillID, versionA, versionB string, fixtures []InsightFixture) (*InsightComparison, error) {
+	reg := newInsightSkillRegistry(dir, -1)
+	reg.reload()
+	a, ok := reg.version(skillID, versionA)
+	if !ok {
+		return nil, fmt.Errorf("skill %s version %s not found in %s", skillID, versionA, dir)
+	}
+	b, ok := reg.version(skillID, versionB)
+	if !ok {
+		return nil, fmt.Errorf("skill %s version %s not found in %s", skillID, versionB, dir)
+	}
+	client := &insightClient{provider: "eval", repairAttempts: getEnvInt("INSIGHT_REPAIR_ATTEMPTS", 2), chat: chatLLM}
+	return compareInsightSkills(ctx, client, a, b, fixtures), nil
+}
+
+// WriteInsightComparison writes the report as indented JSON.
+func WriteInsightComparison(w io.Writer, report *InsightComparison) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func compareInsightSkills(ctx context.Context, client *insightClient, a, b InsightSkill, fixtures []InsightFixture) *InsightComparison {
+	report := &InsightComparison{
+		SkillID:     a.ID,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	accA, accB := newInsightScoreAcc(a.Version), newInsightScoreAcc(b.Version)
+	for _, f := range fixtures {
+		ra := runInsightFixture(ctx, client, a, f)
+		rb := runInsightFixture(ctx, client, b, f)
+		accA.add(ra, f.Expect)
+		accB.add(rb, f.Expect)
+		report.Records = append(report.Records, InsightRecordComparison{
+			ID: f.ID, A: ra, B: rb, Winner: insightWinner(ra.Score, rb.Score, a.Version, b.Version),
+		})
+	}
+	report.A, report.B = accA.score(), accB.score()
+	report.Winner = insightWinner(report.A.Score, report.B.Score, a.Version, b.Version)
+	return report
+}
+
+func runInsightFixture(ctx context.Context, client *insightClient, skill InsightSkill, f InsightFixture) InsightRecordResult {
+	var res InsightRecordResult
+	payload := f.Payload
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	params, ok := buildInsightParams(skill, payload, f.EntityKind)
+	if !ok {
+		res.Error = "missing required fields"
+		return res
+	}
+	start := time.Now()
+	list, calls, err := client.summarize(ctx, skill, params)
+	res.LatencyMs = time.Since(start).Milliseconds()
+	res.Calls = calls
+	if err != nil {
+		var verr *insightValidationError
+		res.DeadLettered = errors.As(err, &verr)
+		res.Error = err.Error()
+		return res
+	}
+	if len(list) == 0 {
+		res.Error = "empty response"
+		return res
+	}
+	ins := list[0]
+	res.Valid = true
+	res.Summary = ins.Summary.Text
+	res.Sentiment = ins.Sentiment.Label
+	res.EscalationScore = ins.EscalationScore
+	for _, s := range ins.Signals {
+		res.Signals = append(res.Signals, s.Type)
+	}
+	res.Score = scoreInsight(res, f.Expect)
+	return res
+}
+
+// scoreInsight averages the graded parts of the expectation: the sentiment
+// label, the F1 of the signal types and the escalation range. A valid
+// response to a fixture without expectations scores 1.
+func scoreInsight(res InsightRecordResult, exp InsightExpectation) float64 {
+	var sum float64
+	n := 0
+	if exp.Sentiment != "" {
+		n++
+		if res.Sentiment == exp.Sentiment {
+			sum++
+		}
+	}
+	if exp.Signals != nil {
+		n++
+		tp, fp, fn := signalOverlap(res.Signals, exp.Signals)
+		if tp+fp+fn == 0 {
+			sum++
+		} else {
+			sum += 2 * float64(tp) / float64(2*tp+fp+fn)
+		}
+	}
+	if len(exp.Escalation) == 2 {
+		n++
+		if inInsightRange(res.EscalationScore, exp.Escalation) {
+			sum++
+		}
+	}
+	if n == 0 {
+		return 1
+	}
+	return sum / float64(n)
+}
+
+func signalOverlap(got, want []string) (tp, fp, fn int) {
+	wantSet := map[string]bool{}
+	for _, w := range want {
+		wantSet[w] = true
+	}
+	gotSet := map[string]bool{}
+	for _, g := range got {
+		if gotSet[g] {
+			continue
+		}
+		gotSet[g] = true
+		if wantSet[g] {
+			tp++
+		} else {
+			fp++
+		}
+	}
+	for w := range wantSet {
+		if !gotSet[w] {
+			fn++
+		}
+	}
+	return tp, fp, fn
+}
+
+func inInsightRange(v float64, r []float64) bool {
+	return v >= r[0] && v <= r[1]
+}
+
+func insightWinner(a, b float64, versionA, versionB string) string {
+	switch {
+	case math.Abs(a-b) < 1e-9:
+		return "tie"
+	case a > b:
+		return versionA
+	default:
+		return versionB
+	}
+}
+
+// insightScoreAcc accumulates one version's results across fixtures.
+type insightScoreAcc struct {
+	s                         InsightVersionScore
+	sentimentN, sentimentOK   int
+	tp, fp, fn                int
+	escalationN, escalationOK int
+	scoreSum                  float64
+	latencySum                int64
+}
+
+func newInsightScoreAcc(version string) *insightScoreAcc {
+	return &insightScoreAcc{s: InsightVersionScore{Version: version}}
+}
+
+func (a *insightScoreAcc) add(res InsightRecordResult, exp InsightExpectation) {
+	a.s.Records++
+	a.s.LLMCalls += res.Calls
+	a.latencySum += res.LatencyMs
+	a.scoreSum += res.Score
+	switch {
+	case res.Valid:
+		a.s.Valid++
+		if res.Calls == 1 {
+			a.s.FirstPass++
+		}
+	case res.DeadLettered:
+		a.s.DeadLettered++
+	default:
+		a.s.Errors++
+	}
+	// Failed records count against every graded metric.
+	if exp.Sentiment != "" {
+		a.sentimentN++
+		if res.Valid && res.Sentiment == exp.Sentiment {
+			a.sentimentOK++
+		}
+	}
+	if exp.Signals != nil {
+		tp, fp, fn := signalOverlap(res.Signals, exp.Signals)
+		a.tp, a.fp, a.fn = a.tp+tp, a.fp+fp, a.fn+fn
+	}
+	if len(exp.Escalation) == 2 {
+		a.escalationN++
+		if res.Valid && inInsightRange(res.EscalationScore, exp.Escalation) {
+			a.escalationOK++
+		}
+	}
+}
+
+func (a *insightScoreAcc) score() InsightVersionScore {
+	s := a.s
+	s.SentimentAccuracy = ratio(a.sentimentOK, a.sentimentN)
+	s.SignalPrecision = ratio(a.tp, a.tp+a.fp)
+	s.SignalRecall = ratio(a.tp, a.tp+a.fn)
+	if s.SignalPrecision+s.SignalRecall > 0 {
+		s.SignalF1 = 2 * s.SignalPrecision * s.SignalRecall / (s.SignalPrecision + s.SignalRecall)
+	}
+	s.EscalationAccuracy = ratio(a.escalationOK, a.escalationN)
+	if s.Records > 0 {
+		s.Score = a.scoreSum / float64(s.Records)
+		s.MeanLatencyMs = float64(a.latencySum) / float64(s.Records)
+	}
+	return s
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
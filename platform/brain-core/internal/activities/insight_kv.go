@@ -18,9 +18,11 @@ func makeInsightKey(skillID, entityRef string) string {
 	return fmt.Sprintf("insight:%s:%s", skillID, entityRef)
 }
 
-func hashInsight(skillID, entityRef string, params map[string]string) string {
+// hashInsight includes the skill version, so a record is summarized again
+// when it moves to another version.
+func hashInsight(skillID, skillVersion, entityRef string, params map[string]string) string {
 	b, _ := json.Marshal(params)
-	h := sha256.Sum256(append([]byte(skillID+"|"+entityRef+"|"), b...))
+	h := sha256.Sum256(append([]byte(skillID+"@"+skillVersion+"|"+entityRef+"|"), b...))
 	return hex.EncodeToString(h[:])
 }
 
@@ -41,3 +43,34 @@ func saveInsightSignature(ctx context.Context, tenantID, projectID, skillID, ent
 		"generatedAt": time.Now().UTC().Format(time.RFC3339),
 	})
 }
+
+func makeInsightDeadLetterKey(skillID, entityRef string) string {
+	return fmt.Sprintf("insight:deadletter:%s:%s", skillID, entityRef)
+}
+
+// saveInsightDeadLetter records a response that never met the skill's output
+// schema, with the raw output of every attempt, so it can be inspected and
+// the prompt or schema fixed. The latest failure per entity is kept.
+func saveInsightDeadLetter(ctx context.Context, tenantID, projectID, runID, entityRef string, verr *insightValidationError) {
+	raw := ""
+	if n := len(verr.Responses); n > 0 {
+		raw = verr.Responses[n-1]
+	}
+	_ = saveCheckpointKV(ctx, tenantID, projectID, makeInsightDeadLetterKey(verr.SkillID, entityRef), map[string]any{
+		"skillId":      verr.SkillID,
+		"skillVersion": verr.SkillVersion,
+		"entityRef":    entityRef,
+		"runId":        runID,
+		"errors":       verr.Problems,
+		"raw":          raw,
+		"responses":    verr.Responses,
+		"attempts":     len(verr.Responses),
+		"failedAt":     time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// clearInsightDeadLetter deletes the entity's dead letter once the skill
+// summarizes it, so the remaining dead letters are the open failures.
+func clearInsightDeadLetter(ctx context.Context, tenantID, projectID, skillID, entityRef string) {
+	_ = deleteCheckpointKV(ctx, tenantID, projectID, makeInsightDeadLetterKey(skillID, entityRef))
+}
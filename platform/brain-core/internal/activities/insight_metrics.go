@@ -8,16 +8,19 @@ type insightCounters struct {
 	skippedCache   uint64
 	llmErrors      uint64
 	parsed         uint64
+	deadLettered   uint64
 }
 
 func (c *insightCounters) incMissing() { atomic.AddUint64(&c.skippedMissing, 1) }
 func (c *insightCounters) incCache()   { atomic.AddUint64(&c.skippedCache, 1) }
 func (c *insightCounters) incErr()     { atomic.AddUint64(&c.llmErrors, 1) }
 func (c *insightCounters) incParsed()  { atomic.AddUint64(&c.parsed, 1) }
+func (c *insightCounters) incDead()    { atomic.AddUint64(&c.deadLettered, 1) }
 
-func (c *insightCounters) snapshot() (missing, cache, errs, parsed uint64) {
+func (c *insightCounters) snapshot() (missing, cache, errs, parsed, dead uint64) {
 	return atomic.LoadUint64(&c.skippedMissing),
 		atomic.LoadUint64(&c.skippedCache),
 		atomic.LoadUint64(&c.llmErrors),
-		atomic.LoadUint64(&c.parsed)
+		atomic.LoadUint64(&c.parsed),
+		atomic.LoadUint64(&c.deadLettered)
 }
@@ -1,9 +1,20 @@
 package activities
 
 import (
+	"cmp"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,6 +22,7 @@ import (
 // InsightSkill represents a YAML-defined skill (Anthropic/OpenAI style) with input schema and template.
 type InsightSkill struct {
 	ID              string
+	Version         string // the YAML version, or a digest of the file when it has none
 	Template        string
 	RequiredFields  []string
 	CacheTTLSeconds int
@@ -19,34 +31,253 @@ type InsightSkill struct {
 	ModelTemp       float64
 	MaxInsights     int
 	PreferCDM       bool
+	// OutputSchema is the JSON contract responses must meet; nil accepts
+	// any response parseInsightJSON can read.
+	OutputSchema *insightSchema
 }
 
-// skillRegistry holds skills keyed by ID.
-var skillRegistry = map[string]InsightSkill{}
+// skillRegistry holds every loaded version of each skill, keyed by ID and
+// version, with the per-tenant traffic splits between versions. It reloads
+// when the files in INSIGHT_SKILL_DIR change, so a prompt edit reaches the
+// workers without a restart.
+var skillRegistry = newInsightSkillRegistry(insightSkillDir(), time.Duration(getEnvInt("INSIGHT_SKILL_RELOAD_SECONDS", 30))*time.Second)
 
 func init() {
-	loadInsightSkills()
+	skillRegistry.reload()
 }
 
-func loadInsightSkills() {
+func insightSkillDir() string {
 	dir := strings.TrimSpace(os.Getenv("INSIGHT_SKILL_DIR"))
 	if dir == "" {
 		// Default to bundled insights folder if not provided.
 		dir = filepath.Join("insights")
 	}
+	return dir
+}
+
+// insightRolloutFile in the skill directory splits traffic between the
+// loaded versions of a skill, by default and per tenant. Weights are
+// relative; versions without weight get no traffic. Without a rollout a
+// skill serves its latest version.
+//
+//	rollouts:
+//	  issue-insight.v1:
+//	    default: { "1.0.0": 100 }
+//	    tenants:
+//	      acme: { "1.0.0": 50, "1.1.0": 50 }
+const insightRolloutFile = "rollout.yaml"
+
+type skillRollout struct {
+	Default map[string]int            `yaml:"default"`
+	Tenants map[string]map[string]int `yaml:"tenants"`
+}
+
+type insightSkillRegistry struct {
+	dir      string
+	interval time.Duration // minimum time between directory checks; <0 disables reloads
+
+	mu       sync.RWMutex
+	skills   map[string]map[string]InsightSkill // id -> version -> skill
+	files    map[string]InsightSkill            // file name -> skill last loaded from it
+	rollouts map[string]skillRollout
+	stamp    string // fingerprint of the directory when last loaded
+	checked  time.Time
+}
+
+func newInsightSkillRegistry(dir string, interval time.Duration) *insightSkillRegistry {
+	return &insightSkillRegistry{dir: dir, interval: interval, skills: map[string]map[string]InsightSkill{}}
+}
+
+// maybeReload reloads the skills when the interval has passed since the
+// last check and the directory changed.
+func (r *insightSkillRegistry) maybeReload() {
+	if r.interval < 0 {
+		return
+	}
+	r.mu.RLock()
+	due := time.Since(r.checked) >= r.interval
+	r.mu.RUnlock()
+	if due {
+		r.reload()
+	}
+}
+
+// reload reads the skill directory when its fingerprint differs from the
+// loaded one. A directory that cannot be read keeps the current skills, and
+// a file that fails to parse keeps what was last loaded from it, so a bad
+// edit never takes a serving version or rollout away.
+func (r *insightSkillRegistry) reload() {
+	stamp, ok := skillDirStamp(r.dir)
+	r.mu.Lock()
+	r.checked = time.Now()
+	unchanged := !ok || stamp == r.stamp
+	prevFiles, prevRollouts := r.files, r.rollouts
+	r.mu.Unlock()
+	if unchanged {
+		return
+	}
+
+	skills := map[string]map[string]InsightSkill{}
+	files := map[string]InsightSkill{}
+	rollouts := map[string]skillRollout{}
+	entries, _ := os.ReadDir(r.dir)
+	for _, ent := range entries {
+		if ent.IsDir() || !strings.HasSuffix(ent.Name(), ".yaml") {
+			continue
+		}
+		path := filepath.Join(r.dir, ent.Name())
+		if ent.Name() == insightRolloutFile {
+			parsed, err := parseSkillRollouts(path)
+			if err != nil {
+				log.Printf("insight skills: %s: %v; keeping the previous rollouts", path, err)
+				parsed = prevRollouts
+			}
+			rollouts = parsed
+			continue
+		}
+		skill, err := parseInsightSkill(path)
+		if err == nil && skill.ID == "" {
+			err = fmt.Errorf("missing id")
+		}
+		if err != nil {
+			prev, ok := prevFiles[ent.Name()]
+			if !ok {
+				log.Printf("insight skills: %s: %v; skipping", path, err)
+				continue
+			}
+			log.Printf("insight skills: %s: %v; keeping %s %s", path, err, prev.ID, prev.Version)
+			skill = prev
+		}
+		files[ent.Name()] = skill
+		if skills[skill.ID] == nil {
+			skills[skill.ID] = map[string]InsightSkill{}
+		}
+		skills[skill.ID][skill.Version] = skill
+	}
+
+	r.mu.Lock()
+	r.skills, r.files, r.rollouts, r.stamp = skills, files, rollouts, stamp
+	r.mu.Unlock()
+}
+
+// skillDirStamp fingerprints the YAML files in dir by name, size and
+// modification time.
+func skillDirStamp(dir string) (string, bool) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return
+		return "", false
 	}
+	h := sha1.New()
 	for _, ent := range entries {
 		if ent.IsDir() || !strings.HasSuffix(ent.Name(), ".yaml") {
 			continue
 		}
-		path := filepath.Join(dir, ent.Name())
-		if skill, err := parseInsightSkill(path); err == nil && skill.ID != "" {
-			skillRegistry[skill.ID] = skill
+		info, err := ent.Info()
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(h, "%s|%d|%d\n", ent.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+func parseSkillRollouts(path string) (map[string]skillRollout, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		Rollouts map[string]skillRollout `yaml:"rollouts"`
+	}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	return raw.Rollouts, nil
+}
+
+// count returns the number of loaded skill versions.
+func (r *insightSkillRegistry) count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n := 0
+	for _, versions := range r.skills {
+		n += len(versions)
+	}
+	return n
+}
+
+// version returns one loaded version of a skill.
+func (r *insightSkillRegistry) version(id, version string) (InsightSkill, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.skills[id][version]
+	return s, ok
+}
+
+// resolve picks the version of skill id that serves entityRef for tenant.
+// Entities are assigned to versions by a hash of tenant, skill and entity,
+// so an entity stays on one version while the split is unchanged.
+func (r *insightSkillRegistry) resolve(id, tenantID, entityRef string) (InsightSkill, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	versions := r.skills[id]
+	if len(versions) == 0 {
+		return InsightSkill{}, false
+	}
+	rollout := r.rollouts[id]
+	weights := rollout.Default
+	if w, ok := rollout.Tenants[tenantID]; ok {
+		weights = w
+	}
+	var names []string
+	total := 0
+	for v, w := range weights {
+		if _, loaded := versions[v]; loaded && w > 0 {
+			names = append(names, v)
+			total += w
+		}
+	}
+	if total == 0 {
+		return versions[latestSkillVersion(versions)], true
+	}
+	sort.Slice(names, func(i, j int) bool { return compareSkillVersions(names[i], names[j]) < 0 })
+	h := fnv.New32a()
+	h.Write([]byte(tenantID + "|" + id + "|" + entityRef))
+	bucket := int(h.Sum32() % uint32(total))
+	for _, v := range names {
+		if bucket < weights[v] {
+			return versions[v], true
+		}
+		bucket -= weights[v]
+	}
+	return versions[names[len(names)-1]], true
+}
+
+func latestSkillVersion(versions map[string]InsightSkill) string {
+	latest := ""
+	for v := range versions {
+		if latest == "" || compareSkillVersions(v, latest) > 0 {
+			latest = v
 		}
 	}
+	return latest
+}
+
+// compareSkillVersions orders dotted versions numerically where both parts
+// are numbers ("1.10.0" after "1.9.2") and lexically otherwise.
+func compareSkillVersions(a, b string) int {
+	pa, pb := strings.Split(strings.TrimPrefix(a, "v"), "."), strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		switch {
+		case errA == nil && errB == nil && na != nb:
+			return cmp.Compare(na, nb)
+		case (errA != nil || errB != nil) && pa[i] != pb[i]:
+			return strings.Compare(pa[i], pb[i])
+		}
+	}
+	return cmp.Compare(len(pa), len(pb))
 }
 
 func parseInsightSkill(path string) (InsightSkill, error) {
@@ -56,6 +287,7 @@ func parseInsightSkill(path string) (InsightSkill, error) {
 	}
 	var raw struct {
 		ID          string `yaml:"id"`
+		Version     string `yaml:"version"`
 		Template    string `yaml:"template"`
 		InputSchema struct {
 			Required []string `yaml:"required"`
@@ -69,13 +301,15 @@ func parseInsightSkill(path string) (InsightSkill, error) {
 			Enabled    bool `yaml:"enabled"`
 			TTLSeconds int  `yaml:"ttlSeconds"`
 		} `yaml:"cache"`
-		PreferCDM bool `yaml:"preferCdm"`
+		PreferCDM    bool           `yaml:"preferCdm"`
+		OutputSchema *insightSchema `yaml:"outputSchema"`
 	}
 	if err := yaml.Unmarshal(b, &raw); err != nil {
 		return InsightSkill{}, err
 	}
 	skill := InsightSkill{
 		ID:              strings.TrimSpace(raw.ID),
+		Version:         strings.TrimSpace(raw.Version),
 		Template:        raw.Template,
 		RequiredFields:  raw.InputSchema.Required,
 		CacheTTLSeconds: raw.Cache.TTLSeconds,
@@ -84,16 +318,30 @@ func parseInsightSkill(path string) (InsightSkill, error) {
 		ModelTemp:       0.2,
 		MaxInsights:     3,
 		PreferCDM:       raw.PreferCDM,
+		OutputSchema:    raw.OutputSchema,
 	}
 	if raw.Model.Temperature != nil {
 		skill.ModelTemp = *raw.Model.Temperature
 	}
+	if skill.Version == "" {
+		sum := sha256.Sum256(b)
+		skill.Version = "sha-" + hex.EncodeToString(sum[:4])
+	}
+	if skill.OutputSchema != nil {
+		if skill.OutputSchema.Type != "object" {
+			return InsightSkill{}, fmt.Errorf("%s: outputSchema must describe an object, got type %q", path, skill.OutputSchema.Type)
+		}
+		if err := skill.OutputSchema.check(""); err != nil {
+			return InsightSkill{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
 	return skill, nil
 }
 
-// getInsightSkill returns a skill by ID, otherwise empty skill.
-func getInsightSkill(profileID string) InsightSkill {
-	if s, ok := skillRegistry[profileID]; ok {
+// getInsightSkill returns the version of a skill serving entityRef for the
+// tenant, otherwise an empty skill.
+func getInsightSkill(profileID, tenantID, entityRef string) InsightSkill {
+	if s, ok := skillRegistry.resolve(profileID, tenantID, entityRef); ok {
 		return s
 	}
 	return InsightSkill{ID: profileID, Template: "", RequiredFields: nil, ModelTemp: 0.2, MaxInsights: 3}
@@ -0,0 +1,205 @@
+package activities
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// insightSchema is the subset of JSON Schema a skill's outputSchema may use:
+// types, required properties, enums, numeric ranges, string length and
+// array size.
+type insightSchema struct {
+	Type       string                    `yaml:"type"`
+	Properties map[string]*insightSchema `yaml:"properties"`
+	Required   []string                  `yaml:"required"`
+	Items      *insightSchema            `yaml:"items"`
+	Enum       []string                  `yaml:"enum"`
+	Minimum    *float64                  `yaml:"minimum"`
+	Maximum    *float64                  `yaml:"maximum"`
+	MinLength  *int                      `yaml:"minLength"`
+	MaxItems   *int                      `yaml:"maxItems"`
+	Nullable   bool                      `yaml:"nullable"`
+}
+
+// maxSchemaErrors caps the errors reported for one response, so a repair
+// prompt stays short when the model returned something unrelated.
+const maxSchemaErrors = 20
+
+// check reports schema mistakes at load time rather than on every response.
+func (s *insightSchema) check(path string) error {
+	if s == nil {
+		return nil
+	}
+	switch s.Type {
+	case "object", "array", "string", "number", "integer", "boolean", "":
+	default:
+		return fmt.Errorf("outputSchema %s: unknown type %q", schemaPath(path), s.Type)
+	}
+	if s.Minimum != nil && s.Maximum != nil && *s.Minimum > *s.Maximum {
+		return fmt.Errorf("outputSchema %s: minimum %v exceeds maximum %v", schemaPath(path), *s.Minimum, *s.Maximum)
+	}
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; !ok && s.Properties != nil {
+			return fmt.Errorf("outputSchema %s: required property %q is not declared", schemaPath(path), name)
+		}
+	}
+	for name, prop := range s.Properties {
+		if err := prop.check(joinSchemaPath(path, name)); err != nil {
+			return err
+		}
+	}
+	return s.Items.check(path + "[]")
+}
+
+// validate appends a message per violation in v, which is a value decoded
+// by encoding/json.
+func (s *insightSchema) validate(path string, v any, errs *[]string) {
+	if s == nil || len(*errs) >= maxSchemaErrors {
+		return
+	}
+	fail := func(format string, args ...any) {
+		if len(*errs) < maxSchemaErrors {
+			*errs = append(*errs, schemaPath(path)+": "+fmt.Sprintf(format, args...))
+		}
+	}
+	if v == nil {
+		if !s.Nullable {
+			fail("must not be null")
+		}
+		return
+	}
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			fail("expected an object, got %s", jsonKind(v))
+			return
+		}
+		for _, name := range s.Required {
+			val, present := obj[name]
+			if !present || (val == nil && !s.Properties[name].nullable()) {
+				fail("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			val, present := obj[name]
+			if !present || val == nil {
+				continue // reported above when required
+			}
+			s.Properties[name].validate(joinSchemaPath(path, name), val, errs)
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			fail("expected an array, got %s", jsonKind(v))
+			return
+		}
+		if s.MaxItems != nil && len(arr) > *s.MaxItems {
+			fail("has %d items, at most %d allowed", len(arr), *s.MaxItems)
+		}
+		for i, item := range arr {
+			s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			fail("expected a string, got %s", jsonKind(v))
+			return
+		}
+		if s.MinLength != nil && len(strings.TrimSpace(str)) < *s.MinLength {
+			fail("must be at least %d characters", *s.MinLength)
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			fail("%q is not one of %s", str, strings.Join(s.Enum, ", "))
+		}
+	case "number", "integer":
+		num, ok := v.(float64)
+		if !ok {
+			fail("expected a number, got %s", jsonKind(v))
+			return
+		}
+		if s.Type == "integer" && num != math.Trunc(num) {
+			fail("expected an integer, got %v", num)
+		}
+		if s.Minimum != nil && num < *s.Minimum {
+			fail("%v is below the minimum %v", num, *s.Minimum)
+		}
+		if s.Maximum != nil && num > *s.Maximum {
+			fail("%v is above the maximum %v", num, *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("expected a boolean, got %s", jsonKind(v))
+		}
+	}
+}
+
+func (s *insightSchema) nullable() bool {
+	return s != nil && s.Nullable
+}
+
+// validateInsightOutput checks an LLM response against the skill's output
+// schema. The response may be one insight or an array of them. It returns
+// the violations; none means the response is safe to parse.
+func validateInsightOutput(schema *insightSchema, resp string) []string {
+	resp = strings.TrimSpace(resp)
+	if resp == "" {
+		return []string{"response is empty"}
+	}
+	var v any
+	if err := json.Unmarshal([]byte(resp), &v); err != nil {
+		return []string{fmt.Sprintf("response is not valid JSON: %v", err)}
+	}
+	var errs []string
+	if list, ok := v.([]any); ok && schema.Type != "array" {
+		if len(list) == 0 {
+			return []string{"response is an empty array"}
+		}
+		for i, item := range list {
+			schema.validate(fmt.Sprintf("[%d]", i), item, &errs)
+		}
+		return errs
+	}
+	schema.validate("", v, &errs)
+	return errs
+}
+
+func schemaPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func jsonKind(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,197 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nucleus/store-core/pkg/llm"
+)
+
+func TestInsightOutputSchemaRepairLoop(t *testing.T) {
+	if doc, err := parseInsightSkill(filepath.Join("..", "..", "insights", "doc-insight-anthropic.yaml")); err != nil || doc.OutputSchema == nil {
+		t.Fatalf("expected the doc skill to load with an output schema: %v", err)
+	}
+	skill, err := parseInsightSkill(filepath.Join("..", "..", "insights", "issue-insight-anthropic.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skill.OutputSchema == nil {
+		t.Fatal("expected the issue skill to declare an output schema")
+	}
+
+	invalid := `{"summary":{"text":"Blocked on infra"},"sentiment":{"label":"angry","score":3},
+		"signals":[{"type":"vibes","detail":"bad"}],"escalationScore":0.4}`
+	valid := `{"summary":{"text":"Blocked on infra","confidence":0.8},"sentiment":{"label":"negative","score":-0.6},
+		"signals":[{"type":"blocker","severity":"high","detail":"waiting on infra"}],"escalationScore":0.7,"expiresAt":null}`
+	problems := validateInsightOutput(skill.OutputSchema, invalid)
+	want := []string{
+		`sentiment.label: "angry" is not one of positive, neutral, negative`,
+		`sentiment.score: 3 is above the maximum 1`,
+		`signals[0]: missing required property "severity"`,
+		`signals[0].type: "vibes" is not one of`,
+	}
+	for _, w := range want {
+		found := false
+		for _, p := range problems {
+			found = found || strings.HasPrefix(p, w)
+		}
+		if !found {
+			t.Errorf("expected %q among %q", w, problems)
+		}
+	}
+	if p := validateInsightOutput(skill.OutputSchema, "Sure! Here is the JSON"); len(p) != 1 || !strings.HasPrefix(p[0], "response is not valid JSON") {
+		t.Errorf("expected a JSON error, got %q", p)
+	}
+
+	// The first answer is sent back with its violations; the second passes.
+	var calls [][]llm.Message
+	replies := []string{invalid, valid}
+	c := &insightClient{provider: "test", repairAttempts: 2, chat: func(_ context.Context, _ InsightSkill, msgs []llm.Message) (string, error) {
+		calls = append(calls, msgs)
+		r := replies[0]
+		if len(replies) > 1 {
+			replies = replies[1:]
+		}
+		return r, nil
+	}}
+	list, err := c.Summarize(context.Background(), skill, map[string]string{"issueKey": "ENG-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Sentiment.Score != -0.6 || list[0].Signals[0].Type != "blocker" {
+		t.Fatalf("expected the repaired insight, got %+v", list)
+	}
+	if len(calls) != 2 || len(calls[1]) != 3 || calls[1][1].Content != invalid || !strings.Contains(calls[1][2].Content, "sentiment.score: 3 is above the maximum 1") {
+		t.Fatalf("expected a repair prompt listing the violations, got %+v", calls)
+	}
+
+	// A model that never complies ends in a validation error with every raw answer.
+	calls, replies = nil, []string{invalid}
+	_, err = c.Summarize(context.Background(), skill, nil)
+	var verr *insightValidationError
+	if !errors.As(err, &verr) || len(verr.Responses) != 3 || len(calls) != 3 || verr.Responses[2] != invalid {
+		t.Fatalf("expected a validation error after 3 attempts, got %v (%d calls)", err, len(calls))
+	}
+}
+
+func TestInsightSkillRejectsBadOutputSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.yaml")
+	yaml := "id: bad.v1\noutputSchema:\n  type: object\n  required: [score]\n  properties:\n    score: { type: number, minimum: 1, maximum: 0 }\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseInsightSkill(path); err == nil || !strings.Contains(err.Error(), "minimum 1 exceeds maximum 0") {
+		t.Errorf("expected the inverted range to be rejected, got %v", err)
+	}
+}
+
+func TestInsightSkillVersionsReloadAndSplit(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("issue-v1.yaml", "id: issue.v1\nversion: 1.9.0\ntemplate: first\n")
+	reg := newInsightSkillRegistry(dir, 0)
+	reg.reload()
+	if s, ok := reg.resolve("issue.v1", "t1", "issue:1"); !ok || s.Version != "1.9.0" {
+		t.Fatalf("expected version 1.9.0, got %+v", s)
+	}
+
+	// A new version is picked up without a restart and, without a rollout,
+	// serves all traffic.
+	write("issue-v2.yaml", "id: issue.v1\nversion: 1.10.0\ntemplate: second\n")
+	reg.maybeReload()
+	if s, _ := reg.resolve("issue.v1", "t1", "issue:1"); s.Version != "1.10.0" || s.Template != "second" || reg.count() != 2 {
+		t.Fatalf("expected the reload to serve 1.10.0, got %+v (%d versions)", s, reg.count())
+	}
+
+	// A rollout pins the default and splits one tenant's traffic.
+	write("rollout.yaml", "rollouts:\n  issue.v1:\n    default: { \"1.9.0\": 100 }\n    tenants:\n      acme: { \"1.9.0\": 50, \"1.10.0\": 50 }\n")
+	reg.maybeReload()
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		ref := fmt.Sprintf("issue:%d", i)
+		if s, _ := reg.resolve("issue.v1", "other", ref); s.Version != "1.9.0" {
+			t.Fatalf("expected the default split to pin 1.9.0, got %s", s.Version)
+		}
+		s, _ := reg.resolve("issue.v1", "acme", ref)
+		if again, _ := reg.resolve("issue.v1", "acme", ref); again.Version != s.Version {
+			t.Fatalf("expected %s to stay on one version", ref)
+		}
+		counts[s.Version]++
+	}
+	if counts["1.9.0"] < 400 || counts["1.10.0"] < 400 {
+		t.Errorf("expected an even split for acme, got %v", counts)
+	}
+
+	// A version without a YAML version is identified by its content.
+	write("issue-draft.yaml", "id: issue.v1\ntemplate: draft\n")
+	reg.maybeReload()
+	if reg.count() != 3 {
+		t.Errorf("expected the unversioned draft to load as its own version, got %d", reg.count())
+	}
+
+	// A broken edit keeps the version and the rollout last loaded from the file.
+	write("issue-v2.yaml", "id: issue.v1\nversion: [1.11.0\n")
+	write("rollout.yaml", "rollouts: [\n")
+	reg.maybeReload()
+	if s, ok := reg.version("issue.v1", "1.10.0"); !ok || s.Template != "second" || reg.count() != 3 {
+		t.Fatalf("expected 1.10.0 to survive the broken file, got %+v (%d versions)", s, reg.count())
+	}
+	if s, _ := reg.resolve("issue.v1", "other", "issue:1"); s.Version != "1.9.0" {
+		t.Errorf("expected the previous rollout to stay in force, got %s", s.Version)
+	}
+}
+
+func TestCompareInsightSkillVersions(t *testing.T) {
+	a, err := parseInsightSkill(filepath.Join("..", "..", "insights", "issue-insight-anthropic.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := a
+	b.Version = "2.0.0"
+	b.Template = "v2\n" + a.Template
+	fixtures, err := LoadInsightFixtures(filepath.Join("..", "..", "insights", "fixtures", "issue-insight.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The baseline never produces a valid signal type; the candidate
+	// answers every record alike.
+	client := &insightClient{provider: "test", repairAttempts: 1, chat: func(_ context.Context, skill InsightSkill, _ []llm.Message) (string, error) {
+		if skill.Version == a.Version {
+			return `{"summary":{"text":"x"},"sentiment":{"label":"negative","score":-0.5},"signals":[{"type":"vibes","severity":"low","detail":"x"}],"escalationScore":0.7}`, nil
+		}
+		return `{"summary":{"text":"x"},"sentiment":{"label":"negative","score":-0.5},"signals":[{"type":"blocker","severity":"high","detail":"x"}],"escalationScore":0.7}`, nil
+	}}
+	report := compareInsightSkills(context.Background(), client, a, b, fixtures)
+	if report.Winner != "2.0.0" || report.A.DeadLettered != len(fixtures) || report.A.LLMCalls != 2*len(fixtures) || report.A.Score != 0 {
+		t.Fatalf("expected the baseline to dead-letter every record, got %+v", report.A)
+	}
+	if report.B.Valid != len(fixtures) || report.B.FirstPass != len(fixtures) {
+		t.Fatalf("expected the candidate to pass first time, got %+v", report.B)
+	}
+	// ENG-101: sentiment right, one of two signals, escalation in range.
+	if got := report.Records[0].B.Score; math.Abs(got-(1+2.0/3+1)/3) > 1e-9 {
+		t.Errorf("unexpected ENG-101 score %.4f", got)
+	}
+	// ENG-102 expects no signals, so the blocker is a false positive.
+	if got := report.Records[1].B.Score; got != 0 {
+		t.Errorf("expected ENG-102 to score 0, got %.4f", got)
+	}
+	if report.B.SignalPrecision != 0.25 || math.Abs(report.B.SignalRecall-1.0/7) > 1e-9 || report.B.SentimentAccuracy != 1.0/3 {
+		t.Errorf("unexpected candidate metrics: %+v", report.B)
+	}
+	if list, _ := client.Summarize(context.Background(), b, nil); len(list) != 1 || list[0].SkillVersion != "2.0.0" {
+		t.Errorf("expected insights stamped with the skill version, got %+v", list)
+	}
+}
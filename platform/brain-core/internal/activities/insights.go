@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha1"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -50,6 +51,8 @@ func (a *Activities) ExtractInsights(ctx context.Context, req IndexArtifactReque
 		project = getenv("METADATA_DEFAULT_PROJECT", "global")
 	}
 
+	skillRegistry.maybeReload()
+
 	var idx int
 	var skippedMissing, skippedCache, llmErrors int
 	counters := &insightCounters{}
@@ -60,7 +63,7 @@ func (a *Activities) ExtractInsights(ctx context.Context, req IndexArtifactReque
 			continue
 		}
 		profileID := selectInsightProfile(req.SourceFamily)
-		skill := getInsightSkill(profileID)
+		skill := getInsightSkill(profileID, tenant, entityRef)
 
 		payload, _ := rec["payload"].(map[string]any)
 		// Optionally apply CDM mapper when preferred.
@@ -95,7 +98,7 @@ func (a *Activities) ExtractInsights(ctx context.Context, req IndexArtifactReque
 		}
 
 		// Dedup: skip if signature unchanged
-		sig := hashInsight(skill.ID, entityRef, params)
+		sig := hashInsight(skill.ID, skill.Version, entityRef, params)
 		if prev, _ := loadInsightSignature(ctx, tenant, project, skill.ID, entityRef); prev != "" && prev == sig {
 			skippedCache++
 			counters.incCache()
@@ -106,6 +109,14 @@ func (a *Activities) ExtractInsights(ctx context.Context, req IndexArtifactReque
 		if client != nil {
 			if list, llmErr := client.Summarize(ctx, skill, params); len(list) > 0 {
 				insights = list
+				clearInsightDeadLetter(ctx, tenant, project, skill.ID, entityRef)
+			} else if verr := (*insightValidationError)(nil); errors.As(llmErr, &verr) {
+				counters.incDead()
+				saveInsightDeadLetter(ctx, tenant, project, req.RunID, entityRef, verr)
+				logger.Warn("insight-dead-letter", "skill", skill.ID, "version", skill.Version, "entity", entityRef, "attempts", len(verr.Responses), "errors", verr.Problems)
+				// Dead-lettered: no fallback insight and no signature, so the
+				// next run retries the entity.
+				continue
 			} else if llmErr != nil {
 				llmErrors++
 				counters.incErr()
@@ -131,6 +142,7 @@ func (a *Activities) ExtractInsights(ctx context.Context, req IndexArtifactReque
 			insights = []Insight{{
 				Provider:        skill.ID,
 				PromptID:        skill.ID,
+				SkillVersion:    skill.Version,
 				EntityRef:       entityRef,
 				GeneratedAt:     time.Now().UTC().Format(time.RFC3339),
 				Summary:         InsightSummary{Text: summary, Confidence: 0.0},
@@ -157,6 +169,7 @@ func (a *Activities) ExtractInsights(ctx context.Context, req IndexArtifactReque
 					"sourceFamily":       req.SourceFamily,
 					"provider":           pick(ins.Provider, skill.ID),
 					"promptId":           pick(ins.PromptID, skill.ID),
+					"skillVersion":       skill.Version,
 					"generatedAt":        pick(ins.GeneratedAt, time.Now().UTC().Format(time.RFC3339)),
 					"summary.text":       ins.Summary.Text,
 					"summary.confidence": fmt.Sprintf("%f", ins.Summary.Confidence),
@@ -224,8 +237,8 @@ func (a *Activities) ExtractInsights(ctx context.Context, req IndexArtifactReque
 	if err := iter.Err(); err != nil {
 		return err
 	}
-	miss, cache, errs, seen := counters.snapshot()
-	logger.Info("insight-summary", "skillsUsed", len(skillRegistry), "skippedMissing", miss, "skippedCache", cache, "llmErrors", errs, "parsed", seen)
+	miss, cache, errs, seen, dead := counters.snapshot()
+	logger.Info("insight-summary", "skillsUsed", skillRegistry.count(), "skippedMissing", miss, "skippedCache", cache, "llmErrors", errs, "parsed", seen, "deadLettered", dead)
 	if kbSeq > 0 {
 		saveKBEvents(ctx, tenant, project, req.DatasetSlug, req.RunID, kbEvents, kbSeq)
 	}
@@ -85,6 +85,37 @@ func saveCheckpointKV(ctx context.Context, tenantID, projectID, key string, chec
 	return err
 }
 
+func deleteCheckpointKV(ctx context.Context, tenantID, projectID, key string) error {
+	client, err := getKVClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.Delete(ctx, &kvpb.DeleteRequest{
+		Scope: &kvpb.Scope{TenantId: tenantID, ProjectId: projectID},
+		Key:   key,
+	})
+	return err
+}
+
+// listKeysKV returns a page of keys with the prefix and the token of the next
+// page, empty after the last one.
+func listKeysKV(ctx context.Context, tenantID, projectID, prefix, pageToken string, limit int) ([]string, string, error) {
+	client, err := getKVClient()
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := client.ListKeys(ctx, &kvpb.ListKeysRequest{
+		Scope:     &kvpb.Scope{TenantId: tenantID, ProjectId: projectID},
+		Prefix:    prefix,
+		Limit:     int32(limit),
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.GetKeys(), resp.GetNextPageToken(), nil
+}
+
 func makeCheckpointKey(profileID, datasetSlug string) string {
 	return fmt.Sprintf("indexer:%s:%s", profileID, datasetSlug)
 }
@@ -0,0 +1,119 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nucleus/store-core/pkg/vectorstore"
+	"go.temporal.io/sdk/activity"
+)
+
+// ReembedRequest starts a re-embedding migration to a new model.
+type ReembedRequest struct {
+	TargetModel    string `json:"targetModel"`
+	Provider       string `json:"provider,omitempty"` // openai or local; default EMBEDDING_PROVIDER
+	Dimension      int    `json:"dimension"`
+	IndexType      string `json:"indexType,omitempty"` // hnsw, ivfflat (default) or none
+	HNSWM          int    `json:"hnswM,omitempty"`
+	EfConstruction int    `json:"efConstruction,omitempty"`
+	Lists          int    `json:"lists,omitempty"` // ivfflat; 0 scales with rows
+	BatchSize      int    `json:"batchSize,omitempty"`
+	Activate       bool   `json:"activate"`
+}
+
+// ReembedResult reports a finished migration.
+type ReembedResult struct {
+	SourceModel string `json:"sourceModel"`
+	TargetModel string `json:"targetModel"`
+	Copied      int    `json:"copied"`
+	CaughtUp    int    `json:"caughtUp"`
+	Activated   bool   `json:"activated"`
+}
+
+// ReembedVectors backfills a new embedding model's table from the active
+// one and, when requested, swaps it in atomically. Progress is heartbeated,
+// so a retried attempt resumes the backfill where the last one stopped; run
+// it with a heartbeat timeout.
+func (a *Activities) ReembedVectors(ctx context.Context, req ReembedRequest) (*ReembedResult, error) {
+	logger := activity.GetLogger(ctx)
+	if strings.TrimSpace(req.TargetModel) == "" {
+		return nil, fmt.Errorf("targetModel is required")
+	}
+	if req.Dimension <= 0 {
+		return nil, fmt.Errorf("dimension is required")
+	}
+	indexType, err := vectorstore.ParseIndexType(req.IndexType)
+	if err != nil {
+		return nil, err
+	}
+	kind := req.Provider
+	if kind == "" {
+		kind = embeddingProviderKind()
+	}
+	provider, err := newEmbeddingProvider(kind, req.TargetModel, req.Dimension)
+	if err != nil {
+		return nil, err
+	}
+	// Indexing checks the active model's name against the provider's, so the
+	// target must carry the name the provider embeds under.
+	if provider.ModelName() != req.TargetModel {
+		return nil, fmt.Errorf("embedding provider %s produces %s vectors, not %s", kind, provider.ModelName(), req.TargetModel)
+	}
+
+	emb := newEmbedder(provider, req.Dimension, nil)
+	emb.model = req.TargetModel
+
+	dsn := getenv("VECTOR_DATABASE_URL", getenv("DATABASE_URL", ""))
+	if dsn == "" {
+		return nil, fmt.Errorf("VECTOR_DATABASE_URL or DATABASE_URL required for re-embedding")
+	}
+	store, err := vectorstore.NewPgVectorStore(dsn, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	var resume *vectorstore.ReembedCheckpoint
+	if activity.HasHeartbeatDetails(ctx) {
+		var cp vectorstore.ReembedCheckpoint
+		if err := activity.GetHeartbeatDetails(ctx, &cp); err == nil {
+			resume = &cp
+			logger.Info("reembed-resume", "target", cp.Target, "copied", cp.Copied)
+		}
+	}
+
+	res, err := store.Reembed(ctx, vectorstore.ReembedOptions{
+		Target: vectorstore.ModelSpec{
+			Name:      req.TargetModel,
+			Dimension: req.Dimension,
+			Index: vectorstore.IndexConfig{
+				Type:           indexType,
+				M:              req.HNSWM,
+				EfConstruction: req.EfConstruction,
+				Lists:          req.Lists,
+			},
+		},
+		Embed: func(ctx context.Context, texts []string) ([][]float32, error) {
+			vecs, _, err := emb.Embed(ctx, texts)
+			return vecs, err
+		},
+		BatchSize: req.BatchSize,
+		Activate:  req.Activate,
+		Resume:    resume,
+		Progress: func(cp vectorstore.ReembedCheckpoint) {
+			activity.RecordHeartbeat(ctx, cp)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("reembed-complete", "source", res.Source, "target", res.Target, "copied", res.Copied, "caughtUp", res.CaughtUp, "activated", res.Activated)
+	return &ReembedResult{
+		SourceModel: res.Source,
+		TargetModel: res.Target,
+		Copied:      res.Copied,
+		CaughtUp:    res.CaughtUp,
+		Activated:   res.Activated,
+	}, nil
+}
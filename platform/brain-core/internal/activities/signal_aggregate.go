@@ -0,0 +1,270 @@
+package activities
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	signalpb "github.com/nucleus/store-core/gen/go/signalpb"
+	"github.com/nucleus/store-core/pkg/signalspec"
+)
+
+// cdm.generic.aggregate groups records, aggregates each group (optionally
+// within a time window) and emits one instance per group that meets the
+// condition, keyed on the group entity. It runs as a second pass: records are
+// accumulated while the per-record definitions are evaluated, and the groups
+// are evaluated once the stream is exhausted.
+
+// aggregatePass accumulates one aggregate definition over the stream.
+type aggregatePass struct {
+	def  *signalpb.Definition
+	cfg  GenericAggregateConfig
+	now  time.Time
+	cur  [2]time.Time // [start, end)
+	prev [2]time.Time
+
+	groups map[string]*aggregateGroup
+	order  []string
+}
+
+type aggregateGroup struct {
+	ref      string
+	keys     map[string]any
+	current  []aggState
+	previous []aggState
+}
+
+type aggState struct {
+	count    int
+	sum      float64
+	n        int
+	distinct map[string]struct{}
+}
+
+func newAggregatePass(def *signalpb.Definition, cfg GenericAggregateConfig, now time.Time) *aggregatePass {
+	p := &aggregatePass{def: def, cfg: cfg, now: now, groups: map[string]*aggregateGroup{}}
+	if w := cfg.Window; w != nil {
+		size := w.Size.Duration()
+		end := now
+		if w.Kind == signalspec.WindowTumbling {
+			end = now.UTC().Truncate(size)
+		}
+		p.cur = [2]time.Time{end.Add(-size), end}
+		p.prev = [2]time.Time{end.Add(-2 * size), end.Add(-size)}
+	}
+	return p
+}
+
+// observe folds a record into its group. Groups are registered even when the
+// record fails the where filter or falls outside the window, so "no activity"
+// conditions can fire and a group that no longer matches is evaluated (and
+// its open instance resolved).
+func (p *aggregatePass) observe(rec map[string]any) {
+	if p.cfg.CdmModelID != "" && !strings.EqualFold(p.cfg.CdmModelID, normalizeSignalShape(rec).CdmModelID) {
+		return
+	}
+	keys := make(map[string]any, len(p.cfg.GroupBy))
+	parts := make([]string, 0, len(p.cfg.GroupBy))
+	for _, field := range p.cfg.GroupBy {
+		v := rec[field]
+		if v == nil {
+			return // cannot be attributed to a group
+		}
+		keys[field] = v
+		parts = append(parts, fmt.Sprint(v))
+	}
+	ref := strings.Join(parts, "/")
+	g, ok := p.groups[ref]
+	if !ok {
+		g = &aggregateGroup{
+			ref:      ref,
+			keys:     keys,
+			current:  make([]aggState, len(p.cfg.Aggregates)),
+			previous: make([]aggState, len(p.cfg.Aggregates)),
+		}
+		p.groups[ref] = g
+		p.order = append(p.order, ref)
+	}
+	if !p.cfg.Where.MatchesAt(rec, p.now) {
+		return
+	}
+
+	slot := g.current
+	if p.cfg.Window != nil {
+		t := p.recordTime(rec)
+		switch {
+		case t.IsZero():
+			return
+		case inWindow(t, p.cur):
+			slot = g.current
+		case inWindow(t, p.prev):
+			slot = g.previous
+		default:
+			return
+		}
+	}
+	for i, spec := range p.cfg.Aggregates {
+		var val any
+		if spec.Field != "" {
+			val = rec[spec.Field]
+		}
+		st := &slot[i]
+		switch spec.Fn {
+		case signalspec.AggCount:
+			if spec.Field == "" || val != nil {
+				st.count++
+			}
+		case signalspec.AggSum, signalspec.AggAvg:
+			if f, ok := toFloatMaybe(val); ok {
+				st.sum += f
+				st.n++
+			}
+		case signalspec.AggDistinct:
+			if val != nil {
+				if st.distinct == nil {
+					st.distinct = map[string]struct{}{}
+				}
+				st.distinct[fmt.Sprint(val)] = struct{}{}
+			}
+		}
+	}
+}
+
+func (p *aggregatePass) recordTime(rec map[string]any) time.Time {
+	if f := p.cfg.Window.TimeField; f != "" {
+		return toTime(rec[f])
+	}
+	for _, f := range []string{"updatedAt", "updated_at", "createdAt", "created_at"} {
+		if t := toTime(rec[f]); !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func inWindow(t time.Time, w [2]time.Time) bool {
+	return !t.Before(w[0]) && t.Before(w[1])
+}
+
+func (st aggState) value(fn signalspec.AggregateFn) float64 {
+	switch fn {
+	case signalspec.AggCount:
+		return float64(st.count)
+	case signalspec.AggSum:
+		return st.sum
+	case signalspec.AggAvg:
+		if st.n == 0 {
+			return 0
+		}
+		return st.sum / float64(st.n)
+	case signalspec.AggDistinct:
+		return float64(len(st.distinct))
+	}
+	return 0
+}
+
+// flush evaluates every group and returns instances for those meeting the
+// condition, in first-seen order.
+func (p *aggregatePass) flush(req IndexArtifactRequest) []*signalpb.Instance {
+	var out []*signalpb.Instance
+	condIdx := 0
+	for i, spec := range p.cfg.Aggregates {
+		if spec.Name == p.cfg.Condition.Aggregate {
+			condIdx = i
+		}
+	}
+	for _, ref := range p.order {
+		g := p.groups[ref]
+		current := map[string]any{}
+		previous := map[string]any{}
+		for i, spec := range p.cfg.Aggregates {
+			current[spec.Name] = g.current[i].value(spec.Fn)
+			previous[spec.Name] = g.previous[i].value(spec.Fn)
+		}
+
+		metric := g.current[condIdx].value(p.cfg.Aggregates[condIdx].Fn)
+		var ratio *float64
+		if p.cfg.Condition.Ratio {
+			prev := g.previous[condIdx].value(p.cfg.Aggregates[condIdx].Fn)
+			switch {
+			case prev != 0:
+				r := metric / prev
+				ratio = &r
+				metric = r
+			case metric > 0:
+				metric = math.Inf(1) // growth from nothing
+			default:
+				continue // nothing in either window
+			}
+		}
+		if !signalspec.Compare(metric, p.cfg.Condition.Value, p.cfg.Condition.Op.Symbol()) {
+			continue
+		}
+
+		// Flat view used by severity rules and the summary template.
+		flat := map[string]any{}
+		for k, v := range g.keys {
+			flat[k] = v
+		}
+		for k, v := range current {
+			flat[k] = v
+		}
+		if ratio != nil {
+			flat["ratio"] = *ratio
+		}
+
+		kind := p.cfg.GroupEntityKind
+		if kind == "" {
+			kind = p.cfg.GroupBy[0]
+		}
+		details := map[string]any{
+			"entityRef":  g.ref,
+			"entityKind": kind,
+			"groupBy":    g.keys,
+			"aggregates": current,
+		}
+		if p.cfg.Window != nil {
+			details["previous"] = previous
+			details["window"] = map[string]any{
+				"kind":          string(p.cfg.Window.Kind),
+				"start":         p.cur[0].UTC().Format(time.RFC3339),
+				"end":           p.cur[1].UTC().Format(time.RFC3339),
+				"previousStart": p.prev[0].UTC().Format(time.RFC3339),
+			}
+		}
+		if ratio != nil {
+			details["ratio"] = *ratio
+		}
+		inst, ok := buildInstance(p.def, details, req)
+		if !ok {
+			continue
+		}
+		inst.EntityKind = kind
+		for _, rule := range p.cfg.SeverityRules {
+			if rule.When.MatchesAt(flat, p.now) {
+				inst.Severity = strings.ToUpper(rule.Severity)
+				break
+			}
+		}
+		if p.cfg.SummaryTemplate != "" {
+			inst.Summary = renderSummaryTemplate(p.cfg.SummaryTemplate, flat)
+		} else if inst.Summary == "" {
+			inst.Summary = fmt.Sprintf("%s %s: %s=%v", kind, g.ref, p.cfg.Condition.Aggregate, current[p.cfg.Condition.Aggregate])
+		}
+		out = append(out, inst)
+	}
+	return out
+}
+
+// renderSummaryTemplate substitutes {{name}} placeholders from vals.
+func renderSummaryTemplate(tmpl string, vals map[string]any) string {
+	pairs := make([]string, 0, len(vals)*2)
+	for k, v := range vals {
+		if f, ok := v.(float64); ok && f == math.Trunc(f) && !math.IsInf(f, 0) {
+			v = int64(f)
+		}
+		pairs = append(pairs, "{{"+k+"}}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
@@ -0,0 +1,163 @@
+package activities
+
+import (
+	"testing"
+	"time"
+
+	signalpb "github.com/nucleus/store-core/gen/go/signalpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func aggregateDef(t *testing.T, id string, cfg map[string]any) *signalpb.Definition {
+	t.Helper()
+	spec, err := structpb.NewStruct(map[string]any{
+		"version": 1,
+		"type":    string(TypeGenericAggregate),
+		"config":  cfg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &signalpb.Definition{Id: id, Title: id, Severity: "info", DefinitionSpec: spec}
+}
+
+func runAggregates(t *testing.T, defs []*signalpb.Definition, now time.Time, recs []map[string]any) map[string]*signalpb.Instance {
+	t.Helper()
+	e := &signalEngine{defs: defs}
+	e.prepare(now)
+	req := IndexArtifactRequest{RunID: "run-1"}
+	for _, rec := range recs {
+		if got := e.eval(rec, req, ""); len(got) != 0 {
+			t.Fatalf("aggregate definitions must not emit per record, got %v", got)
+		}
+	}
+	out := map[string]*signalpb.Instance{}
+	for _, inst := range e.flushAggregates(req, "") {
+		out[inst.GetDefinitionId()+"|"+inst.GetEntityRef()] = inst
+	}
+	return out
+}
+
+func TestAggregateSignals(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC) // a Wednesday
+	ago := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339) }
+	day := 24 * time.Hour
+
+	openP1 := aggregateDef(t, "open-p1", map[string]any{
+		"where":           []any{map[string]any{"field": "status", "op": "EQ", "value": "open"}, map[string]any{"field": "priority", "op": "EQ", "value": "P1"}},
+		"groupBy":         []any{"projectId"},
+		"groupEntityKind": "project",
+		"aggregates":      []any{map[string]any{"name": "issues", "fn": "count"}},
+		"condition":       map[string]any{"aggregate": "issues", "op": "GT", "value": 2},
+		"severityRules":   []any{map[string]any{"when": []any{map[string]any{"field": "issues", "op": "GTE", "value": 4}}, "severity": "error"}},
+		"summaryTemplate": "{{issues}} open P1 issues in {{projectId}}",
+	})
+	quietRepo := aggregateDef(t, "quiet-repo", map[string]any{
+		"groupBy":    []any{"repo"},
+		"aggregates": []any{map[string]any{"name": "commits", "fn": "count"}},
+		"window":     map[string]any{"kind": "sliding", "size": map[string]any{"unit": "days", "value": 30}, "timeField": "committedAt"},
+		"condition":  map[string]any{"aggregate": "commits", "op": "EQ", "value": 0},
+	})
+	commentSpike := aggregateDef(t, "comment-spike", map[string]any{
+		"groupBy":    []any{"projectId"},
+		"aggregates": []any{map[string]any{"name": "comments", "fn": "sum", "field": "comments"}},
+		"window":     map[string]any{"kind": "tumbling", "size": map[string]any{"unit": "days", "value": 7}},
+		"condition":  map[string]any{"aggregate": "comments", "op": "GTE", "value": 3, "ratio": true},
+	})
+
+	var recs []map[string]any
+	for i := 0; i < 4; i++ {
+		recs = append(recs, map[string]any{"id": "a", "status": "open", "priority": "P1", "projectId": "ALPHA"})
+	}
+	recs = append(recs,
+		map[string]any{"status": "open", "priority": "P1", "projectId": "BETA"},
+		map[string]any{"status": "closed", "priority": "P1", "projectId": "BETA"},
+		map[string]any{"repo": "api", "committedAt": ago(40 * day)},
+		map[string]any{"repo": "web", "committedAt": ago(2 * day)},
+		// Last complete week is Mon 6 May - Sun 12 May; the one before starts 29 Apr.
+		map[string]any{"projectId": "ALPHA", "comments": 2.0, "updatedAt": ago(12 * day)},
+		map[string]any{"projectId": "ALPHA", "comments": 7.0, "updatedAt": ago(5 * day)},
+		map[string]any{"projectId": "BETA", "comments": 2.0, "updatedAt": ago(12 * day)},
+		map[string]any{"projectId": "BETA", "comments": 3.0, "updatedAt": ago(5 * day)},
+		map[string]any{"projectId": "BETA", "comments": 50.0, "updatedAt": ago(time.Hour)}, // current, incomplete week
+	)
+
+	got := runAggregates(t, []*signalpb.Definition{openP1, quietRepo, commentSpike}, now, recs)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 instances, got %d: %v", len(got), got)
+	}
+	p1 := got["open-p1|ALPHA"]
+	if p1 == nil || p1.GetEntityKind() != "project" || p1.GetSeverity() != "ERROR" || p1.GetSummary() != "4 open P1 issues in ALPHA" {
+		t.Fatalf("unexpected open-p1 instance: %v", p1)
+	}
+	if inst := got["quiet-repo|api"]; inst == nil || inst.GetSeverity() != "INFO" {
+		t.Fatalf("expected quiet repo api to be flagged: %v", got)
+	}
+	spike := got["comment-spike|ALPHA"]
+	if spike == nil {
+		t.Fatalf("expected ALPHA comment spike: %v", got)
+	}
+	if ratio := spike.GetDetails().GetFields()["ratio"].GetNumberValue(); ratio != 3.5 {
+		t.Fatalf("ratio = %v, want 3.5", ratio)
+	}
+}
+
+func TestAggregateGroupThatStopsMatchingIsEvaluated(t *testing.T) {
+	def := aggregateDef(t, "open-p1", map[string]any{
+		"where":      []any{map[string]any{"field": "status", "op": "EQ", "value": "open"}},
+		"groupBy":    []any{"projectId"},
+		"aggregates": []any{map[string]any{"name": "issues", "fn": "count"}},
+		"condition":  map[string]any{"aggregate": "issues", "op": "GTE", "value": 1},
+	})
+	now := time.Now()
+	req := IndexArtifactRequest{RunID: "run-3"}
+	run := func(status string) (*signalEngine, []*signalpb.Instance) {
+		e := &signalEngine{defs: []*signalpb.Definition{def}}
+		e.prepare(now)
+		e.eval(map[string]any{"projectId": "ALPHA", "status": status}, req, "")
+		return e, e.flushAggregates(req, "")
+	}
+
+	if _, got := run("open"); len(got) != 1 || got[0].GetEntityRef() != "ALPHA" {
+		t.Fatalf("expected ALPHA to match while its issue is open, got %v", got)
+	}
+	// Once the issue is closed the group is empty: no instance, but it was
+	// evaluated, so reconciliation resolves the open one.
+	e, got := run("closed")
+	if len(got) != 0 {
+		t.Fatalf("expected no instance for the emptied group, got %v", got)
+	}
+	if !e.wasEvaluated("open-p1", "ALPHA") {
+		t.Fatal("expected the emptied group to be evaluated")
+	}
+}
+
+func TestEngineTracksEvaluatedEntities(t *testing.T) {
+	filter, err := structpb.NewStruct(map[string]any{
+		"version": 1,
+		"type":    string(TypeGenericFilter),
+		"config": map[string]any{
+			"cdmModelId":      "cdm.work.item",
+			"where":           []any{map[string]any{"field": "status", "op": "EQ", "value": "blocked"}},
+			"summaryTemplate": "blocked",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := &signalpb.Definition{Id: "blocked", Title: "blocked", DefinitionSpec: filter}
+	e := &signalEngine{defs: []*signalpb.Definition{def}}
+	e.prepare(time.Now())
+	req := IndexArtifactRequest{RunID: "run-2"}
+	e.eval(map[string]any{"id": "W-1", "cdmModelId": "cdm.work.item", "status": "done"}, req, "")
+	e.eval(map[string]any{"id": "W-2", "cdmModelId": "cdm.work.item", "status": "blocked"}, req, "")
+
+	// W-1 was seen and no longer matches, so it may be resolved; W-3 was not
+	// part of this run and must be left alone.
+	if !e.wasEvaluated("blocked", "W-1") || !e.wasEvaluated("blocked", "W-2") {
+		t.Fatal("expected W-1 and W-2 to be evaluated")
+	}
+	if e.wasEvaluated("blocked", "W-3") {
+		t.Fatal("W-3 was not in the run")
+	}
+}
@@ -67,11 +67,15 @@ func (c *signalClient) upsertInstance(ctx context.Context, inst signalpb.Instanc
 	return err
 }
 
-func (c *signalClient) updateInstanceStatus(ctx context.Context, definitionID, entityRef, status string) error {
-	_, err := c.client.UpdateInstanceStatus(ctx, &signalpb.UpdateInstanceStatusRequest{
+// resolveInstance auto-resolves an instance that stopped matching in a run.
+func (c *signalClient) resolveInstance(ctx context.Context, definitionID, entityRef, runID string) error {
+	_, err := c.client.TransitionInstance(ctx, &signalpb.TransitionInstanceRequest{
 		DefinitionId: definitionID,
 		EntityRef:    entityRef,
-		Status:       status,
+		Status:       "RESOLVED",
+		Actor:        "system",
+		Reason:       "no longer matches",
+		SourceRunId:  runID,
 	})
 	return err
 }
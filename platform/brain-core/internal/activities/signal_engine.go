@@ -13,11 +13,69 @@ import (
 type signalEngine struct {
 	defs     []*signalpb.Definition
 	insights *insightClient
+	// specs caches each definition's parsed definitionSpec.
+	specs map[*signalpb.Definition]parseResult
+	// aggregates holds the group state of cdm.generic.aggregate definitions.
+	aggregates map[*signalpb.Definition]*aggregatePass
+	// evaluated tracks, per definition id, the entity refs a definition was
+	// evaluated against this run whether or not they matched. Only these may
+	// be auto-resolved; entities absent from an incremental run are unknown.
+	evaluated map[string]map[string]bool
 }
 
 // newSignalEngine constructs an engine for applicable definitions.
 func newSignalEngine(defs []*signalpb.Definition) *signalEngine {
-	return &signalEngine{defs: defs, insights: newInsightClient()}
+	e := &signalEngine{defs: defs, insights: newInsightClient()}
+	e.prepare(time.Now())
+	return e
+}
+
+// prepare parses definition specs once and sets up aggregate passes.
+func (e *signalEngine) prepare(now time.Time) {
+	e.specs = map[*signalpb.Definition]parseResult{}
+	e.aggregates = map[*signalpb.Definition]*aggregatePass{}
+	e.evaluated = map[string]map[string]bool{}
+	for _, def := range e.defs {
+		if def.GetDefinitionSpec() == nil {
+			continue
+		}
+		specRes := parseSignalSpec(def.GetDefinitionSpec().AsMap())
+		e.specs[def] = specRes
+		if specRes.Valid && specRes.Spec.Type == TypeGenericAggregate {
+			e.aggregates[def] = newAggregatePass(def, specRes.Spec.Config.(GenericAggregateConfig), now)
+		}
+	}
+}
+
+// flushAggregates evaluates the accumulated groups once the stream is done.
+func (e *signalEngine) flushAggregates(req IndexArtifactRequest, defID string) []*signalpb.Instance {
+	var out []*signalpb.Instance
+	for _, def := range e.defs {
+		pass, ok := e.aggregates[def]
+		if !ok || (defID != "" && def.GetId() != defID) {
+			continue
+		}
+		for _, ref := range pass.order {
+			e.markEvaluated(def.GetId(), ref)
+		}
+		out = append(out, pass.flush(req)...)
+	}
+	return out
+}
+
+func (e *signalEngine) markEvaluated(defID, ref string) {
+	if ref == "" {
+		return
+	}
+	if e.evaluated[defID] == nil {
+		e.evaluated[defID] = map[string]bool{}
+	}
+	e.evaluated[defID][ref] = true
+}
+
+// wasEvaluated reports whether the definition saw the entity this run.
+func (e *signalEngine) wasEvaluated(defID, ref string) bool {
+	return e.evaluated[defID][ref]
 }
 
 // eval emits instances for a single record.
@@ -36,6 +94,11 @@ func (e *signalEngine) eval(rec map[string]any, req IndexArtifactRequest, defID
 				continue
 			}
 		}
+		if pass, ok := e.aggregates[def]; ok {
+			pass.observe(rec)
+			continue
+		}
+		e.markEvaluated(def.GetId(), deriveEntityRef(rec))
 		instances := e.evalDefinition(def, rec, req)
 		out = append(out, instances...)
 	}
@@ -50,7 +113,10 @@ func (e *signalEngine) evalDefinition(def *signalpb.Definition, rec map[string]a
 		}
 		return nil
 	}
-	specRes := parseSignalSpec(def.GetDefinitionSpec().AsMap())
+	specRes, ok := e.specs[def]
+	if !ok {
+		specRes = parseSignalSpec(def.GetDefinitionSpec().AsMap())
+	}
 	if !specRes.Valid {
 		if inst, ok := buildInstance(def, rec, req); ok {
 			return []*signalpb.Instance{inst}
@@ -64,6 +130,8 @@ func (e *signalEngine) evalDefinition(def *signalpb.Definition, rec map[string]a
 		return evalDocOrphan(def, specRes.Spec.Config.(DocOrphanConfig), rec, req)
 	case TypeGenericFilter:
 		return evalGenericFilter(def, specRes.Spec.Config.(GenericFilterConfig), rec, req)
+	case TypeGenericAggregate:
+		return nil // evaluated per group by flushAggregates
 	default:
 		if inst, ok := buildInstance(def, rec, req); ok {
 			return []*signalpb.Instance{inst}
@@ -171,13 +239,13 @@ func evalGenericFilter(def *signalpb.Definition, cfg GenericFilterConfig, rec ma
 	if cfg.CdmModelID != "" && !strings.EqualFold(cfg.CdmModelID, norm.CdmModelID) {
 		return nil
 	}
-	if len(cfg.Where) > 0 && !evalGenericConditions(cfg.Where, rec) {
+	if !cfg.Where.Matches(rec) {
 		return nil
 	}
 	severity := strings.ToUpper(def.GetSeverity())
 	if len(cfg.SeverityRules) > 0 {
 		for _, rule := range cfg.SeverityRules {
-			if evalGenericConditions(rule.When, rec) {
+			if rule.When.Matches(rec) {
 				severity = strings.ToUpper(rule.Severity)
 				break
 			}
@@ -193,58 +261,6 @@ func evalGenericFilter(def *signalpb.Definition, cfg GenericFilterConfig, rec ma
 	return nil
 }
 
-func evalGenericConditions(conds []GenericCondition, rec map[string]any) bool {
-	for _, cond := range conds {
-		field := cond.Field
-		val := rec[field]
-		switch cond.Op {
-		case OpLT:
-			if !compare(val, cond.Value, "<") {
-				return false
-			}
-		case OpLTE:
-			if !compare(val, cond.Value, "<=") {
-				return false
-			}
-		case OpGT:
-			if !compare(val, cond.Value, ">") {
-				return false
-			}
-		case OpGTE:
-			if !compare(val, cond.Value, ">=") {
-				return false
-			}
-		case OpEQ:
-			if !compare(val, cond.Value, "==") {
-				return false
-			}
-		case OpNEQ:
-			if !compare(val, cond.Value, "!=") {
-				return false
-			}
-		case OpIN:
-			if !contains(cond.Value, val) {
-				return false
-			}
-		case OpNOTIN:
-			if contains(cond.Value, val) {
-				return false
-			}
-		case OpISNULL:
-			if val != nil {
-				return false
-			}
-		case OpISNOTNULL:
-			if val == nil {
-				return false
-			}
-		default:
-			return false
-		}
-	}
-	return true
-}
-
 type normalizedSignal struct {
 	AgeMs      float64
 	Status     string
@@ -296,14 +312,7 @@ func normalizeSignalShape(rec map[string]any) normalizedSignal {
 }
 
 func intervalToMs(iv IntervalConfig) int64 {
-	switch iv.Unit {
-	case IntervalHours:
-		return int64(iv.Value) * int64(time.Hour/time.Millisecond)
-	case IntervalDays:
-		return int64(iv.Value) * 24 * int64(time.Hour/time.Millisecond)
-	default:
-		return 0
-	}
+	return iv.Duration().Milliseconds()
 }
 
 func containsFold(list []string, val string) bool {
@@ -328,35 +337,6 @@ func toTime(v any) time.Time {
 	return time.Time{}
 }
 
-func compare(a any, b any, op string) bool {
-	af, aok := toFloatMaybe(a)
-	bf, bok := toFloatMaybe(b)
-	if aok && bok {
-		switch op {
-		case "<":
-			return af < bf
-		case "<=":
-			return af <= bf
-		case ">":
-			return af > bf
-		case ">=":
-			return af >= bf
-		case "==":
-			return af == bf
-		case "!=":
-			return af != bf
-		}
-	}
-	as, bs := fmt.Sprint(a), fmt.Sprint(b)
-	switch op {
-	case "==":
-		return as == bs
-	case "!=":
-		return as != bs
-	}
-	return false
-}
-
 func toFloatMaybe(v any) (float64, bool) {
 	switch t := v.(type) {
 	case float32:
@@ -379,16 +359,3 @@ func toFloat(v any) float64 {
 	}
 	return 0
 }
-
-func contains(hay any, needle any) bool {
-	arr, ok := hay.([]any)
-	if !ok {
-		return false
-	}
-	for _, item := range arr {
-		if fmt.Sprint(item) == fmt.Sprint(needle) {
-			return true
-		}
-	}
-	return false
-}
@@ -90,47 +90,62 @@ func (a *Activities) ExtractSignals(ctx context.Context, req IndexArtifactReques
 	var kbSeq int64
 	kgc := newKgGRPCClient()
 	defer kgc.Close()
+	emit := func(inst *signalpb.Instance) error {
+		if _, ok := seen[inst.GetDefinitionId()]; !ok {
+			seen[inst.GetDefinitionId()] = map[string]bool{}
+		}
+		if _, exists := existing[inst.GetDefinitionId()][inst.GetEntityRef()]; exists {
+			updated++
+		} else {
+			created++
+		}
+		seen[inst.GetDefinitionId()][inst.GetEntityRef()] = true
+		// Scope the instance so notification rules can route by tenant/project.
+		inst.TenantId, inst.ProjectId = req.TenantID, req.ProjectID
+		if err := sc.upsertInstance(ctx, *inst); err != nil {
+			return fmt.Errorf("upsert signal instance: %w", err)
+		}
+		if kgc != nil {
+			if err := kgc.upsertSignal(ctx, inst, req, defs); err != nil {
+				return fmt.Errorf("kg upsert: %w", err)
+			}
+		}
+		kbSeq++
+		h := sha1.Sum([]byte(inst.GetDefinitionId() + inst.GetEntityRef() + req.RunID))
+		kbEvents = append(kbEvents, kbEvent{
+			Seq:         kbSeq,
+			RunID:       req.RunID,
+			DatasetSlug: req.DatasetSlug,
+			Op:          "upsert_node",
+			Kind:        "signal",
+			ID:          fmt.Sprintf("signal:%s:%s", inst.GetDefinitionId(), inst.GetEntityRef()),
+			Hash:        fmt.Sprintf("%x", h[:6]),
+			At:          time.Now().UTC().Format(time.RFC3339),
+		})
+		return nil
+	}
 	for iter.Next() {
 		count++
 		rec := iter.Value()
-		instances := engine.eval(rec, req, "")
-		for _, inst := range instances {
-			if _, ok := seen[inst.GetDefinitionId()]; !ok {
-				seen[inst.GetDefinitionId()] = map[string]bool{}
+		for _, inst := range engine.eval(rec, req, "") {
+			if err := emit(inst); err != nil {
+				return err
 			}
-			if _, exists := existing[inst.GetDefinitionId()][inst.GetEntityRef()]; exists {
-				updated++
-			} else {
-				created++
-			}
-			seen[inst.GetDefinitionId()][inst.GetEntityRef()] = true
-			if err := sc.upsertInstance(ctx, *inst); err != nil {
-				return fmt.Errorf("upsert signal instance: %w", err)
-			}
-			if kgc != nil {
-				if err := kgc.upsertSignal(ctx, inst, req, defs); err != nil {
-					return fmt.Errorf("kg upsert: %w", err)
-				}
-			}
-			kbSeq++
-			h := sha1.Sum([]byte(inst.GetDefinitionId() + inst.GetEntityRef() + req.RunID))
-			kbEvents = append(kbEvents, kbEvent{
-				Seq:         kbSeq,
-				RunID:       req.RunID,
-				DatasetSlug: req.DatasetSlug,
-				Op:          "upsert_node",
-				Kind:        "signal",
-				ID:          fmt.Sprintf("signal:%s:%s", inst.GetDefinitionId(), inst.GetEntityRef()),
-				Hash:        fmt.Sprintf("%x", h[:6]),
-				At:          time.Now().UTC().Format(time.RFC3339),
-			})
 		}
 	}
 	if err := iter.Err(); err != nil {
 		return err
 	}
+	// Second pass: aggregate definitions emit one instance per qualifying group.
+	for _, inst := range engine.flushAggregates(req, "") {
+		if err := emit(inst); err != nil {
+			return err
+		}
+	}
 
-	// Reconciliation: resolve instances not seen in this run (no longer match criteria)
+	// Reconciliation: resolve instances whose entity was evaluated this run but
+	// no longer matches. Entities the run did not see (incremental runs, other
+	// datasets) keep their status.
 	var resolved int64
 	for defID, defExisting := range existing {
 		defSeen := seen[defID]
@@ -138,11 +153,14 @@ func (a *Activities) ExtractSignals(ctx context.Context, req IndexArtifactReques
 			if defSeen != nil && defSeen[entityRef] {
 				continue // Still active
 			}
-			if inst.GetStatus() == "RESOLVED" {
-				continue // Already resolved
+			if !engine.wasEvaluated(defID, entityRef) {
+				continue
+			}
+			switch inst.GetStatus() {
+			case "RESOLVED", "SUPPRESSED":
+				continue
 			}
-			// Mark as resolved
-			if err := sc.updateInstanceStatus(ctx, defID, entityRef, "RESOLVED"); err != nil {
+			if err := sc.resolveInstance(ctx, defID, entityRef, req.RunID); err != nil {
 				logger.Warn("failed to resolve instance", "id", inst.GetId(), "error", err)
 				continue
 			}
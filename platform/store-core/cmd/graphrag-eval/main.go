@@ -0,0 +1,92 @@
+// Command graphrag-eval scores GraphRAG retrieval against a golden question
+// set and writes a JSON report. With -baseline it prints metric changes and
+// exits 1 when any summary metric regressed, for use in CI.
+//
+//	graphrag-eval -set golden.yaml -fixture graph.yaml -out report.json -baseline main.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/nucleus/store-core/pkg/graphrag"
+	"github.com/nucleus/store-core/pkg/graphrag/eval"
+	"github.com/nucleus/store-core/pkg/llm"
+)
+
+func main() {
+	setPath := flag.String("set", "", "golden set YAML (required)")
+	fixturePath := flag.String("fixture", "", "fixture graph YAML to evaluate against (required)")
+	outPath := flag.String("out", "", "report path (default stdout)")
+	baselinePath := flag.String("baseline", "", "earlier report to compare against")
+	tolerance := flag.Float64("tolerance", 0.001, "ignore metric changes smaller than this")
+	useLLM := flag.Bool("llm", false, "generate answers with the LLM_* configured model instead of mock answers")
+	flag.Parse()
+	if *setPath == "" || *fixturePath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	set, err := eval.LoadGoldenSet(*setPath)
+	if err != nil {
+		log.Fatalf("golden set: %v", err)
+	}
+	fixture, err := eval.LoadFixture(*fixturePath)
+	if err != nil {
+		log.Fatalf("fixture: %v", err)
+	}
+	var provider graphrag.LLMProvider
+	if *useLLM {
+		client, err := llm.New(llm.ConfigFromEnv(""))
+		if err != nil {
+			log.Fatalf("llm: %v", err)
+		}
+		provider = llm.NewGraphRAGProvider(client)
+	}
+
+	report, err := eval.Run(context.Background(), fixture.Service(provider), set)
+	if err != nil {
+		log.Fatalf("run: %v", err)
+	}
+
+	out := os.Stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("report: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := eval.WriteReport(out, report); err != nil {
+		log.Fatalf("report: %v", err)
+	}
+
+	if *baselinePath == "" {
+		return
+	}
+	baseline, err := eval.ReadReport(*baselinePath)
+	if err != nil {
+		log.Fatalf("baseline: %v", err)
+	}
+	regressed := false
+	for _, d := range eval.Compare(baseline, report, *tolerance) {
+		scope := "summary"
+		if d.Question != "" {
+			scope = d.Question
+		}
+		fmt.Fprintf(os.Stderr, "%-24s %-18s %.4f -> %.4f\n", scope, d.Metric, d.Before, d.After)
+		if d.Question == "" && d.Regression() {
+			regressed = true
+		}
+	}
+	if regressed {
+		if out != os.Stdout {
+			out.Close()
+		}
+		os.Exit(1)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -21,6 +22,7 @@ import (
 	"github.com/nucleus/store-core/gen/go/vectorpb"
 	"github.com/nucleus/store-core/pkg/kvstore"
 	"github.com/nucleus/store-core/pkg/logstore"
+	"github.com/nucleus/store-core/pkg/signalnotify"
 	"github.com/nucleus/store-core/pkg/signalstore"
 	"github.com/nucleus/store-core/pkg/vectorstore"
 )
@@ -135,6 +137,11 @@ func main() {
 	}
 	if ss != nil {
 		signalpb.RegisterSignalServiceServer(grpcServer, signalstore.NewGRPCServer(ss))
+		// Deliver signal notifications queued by instance transitions.
+		if getEnv("SIGNAL_NOTIFY_ENABLED", "true") != "false" {
+			dispatcher := signalnotify.NewDispatcher(ss.Outbox(), signalnotify.DefaultSinks())
+			go dispatcher.Run(context.Background(), time.Duration(getEnvInt("SIGNAL_NOTIFY_INTERVAL_SECONDS", 15))*time.Second)
+		}
 	}
 	healthSrv := health.NewServer()
 	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
@@ -172,13 +179,33 @@ func initVectorStore() (vectorstore.Store, error) {
 	if dsn == "" {
 		return nil, fmt.Errorf("VECTOR_DATABASE_URL or DATABASE_URL required for vector store")
 	}
-	dim := 1536
-	if v := getEnv("VECTOR_DIMENSION", ""); v != "" {
+	indexType, err := vectorstore.ParseIndexType(getEnv("VECTOR_INDEX_TYPE", ""))
+	if err != nil {
+		return nil, err
+	}
+	// VECTOR_MODEL pins this server to one embedding model; by default it
+	// follows the active model so re-embedding migrations swap it in place.
+	return vectorstore.OpenPgVectorStore(dsn, vectorstore.Options{
+		Dimension: getEnvInt("VECTOR_DIMENSION", 1536),
+		Model:     getEnv("VECTOR_MODEL", ""),
+		Index: vectorstore.IndexConfig{
+			Type:           indexType,
+			M:              getEnvInt("VECTOR_HNSW_M", 0),
+			EfConstruction: getEnvInt("VECTOR_HNSW_EF_CONSTRUCTION", 0),
+			EfSearch:       getEnvInt("VECTOR_HNSW_EF_SEARCH", 0),
+			Lists:          getEnvInt("VECTOR_IVFFLAT_LISTS", 0),
+			Probes:         getEnvInt("VECTOR_IVFFLAT_PROBES", 0),
+		},
+	})
+}
+
+func getEnvInt(key string, def int) int {
+	if v := getEnv(key, ""); v != "" {
 		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
-			dim = parsed
+			return parsed
 		}
 	}
-	return vectorstore.NewPgVectorStore(dsn, dim)
+	return def
 }
 
 func getEnv(key, def string) string {
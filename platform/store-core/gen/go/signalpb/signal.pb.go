@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -186,19 +187,29 @@ func (x *Definition) GetDefinitionSpec() *structpb.Struct {
 	return nil
 }
 
+// Instance status is one of OPEN, ACKNOWLEDGED, SNOOZED, RESOLVED, REOPENED or
+// SUPPRESSED. Upserts from signal runs never override a manual status except to
+// reopen a resolved instance or wake an expired snooze.
 type Instance struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	DefinitionId  string                 `protobuf:"bytes,2,opt,name=definition_id,json=definitionId,proto3" json:"definition_id,omitempty"`
-	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
-	EntityRef     string                 `protobuf:"bytes,4,opt,name=entity_ref,json=entityRef,proto3" json:"entity_ref,omitempty"`
-	EntityKind    string                 `protobuf:"bytes,5,opt,name=entity_kind,json=entityKind,proto3" json:"entity_kind,omitempty"`
-	Severity      string                 `protobuf:"bytes,6,opt,name=severity,proto3" json:"severity,omitempty"`
-	Summary       string                 `protobuf:"bytes,7,opt,name=summary,proto3" json:"summary,omitempty"`
-	Details       *structpb.Struct       `protobuf:"bytes,8,opt,name=details,proto3" json:"details,omitempty"`
-	SourceRunId   string                 `protobuf:"bytes,9,opt,name=source_run_id,json=sourceRunId,proto3" json:"source_run_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DefinitionId   string                 `protobuf:"bytes,2,opt,name=definition_id,json=definitionId,proto3" json:"definition_id,omitempty"`
+	Status         string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	EntityRef      string                 `protobuf:"bytes,4,opt,name=entity_ref,json=entityRef,proto3" json:"entity_ref,omitempty"`
+	EntityKind     string                 `protobuf:"bytes,5,opt,name=entity_kind,json=entityKind,proto3" json:"entity_kind,omitempty"`
+	Severity       string                 `protobuf:"bytes,6,opt,name=severity,proto3" json:"severity,omitempty"`
+	Summary        string                 `protobuf:"bytes,7,opt,name=summary,proto3" json:"summary,omitempty"`
+	Details        *structpb.Struct       `protobuf:"bytes,8,opt,name=details,proto3" json:"details,omitempty"`
+	SourceRunId    string                 `protobuf:"bytes,9,opt,name=source_run_id,json=sourceRunId,proto3" json:"source_run_id,omitempty"`
+	SnoozedUntil   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=snoozed_until,json=snoozedUntil,proto3" json:"snoozed_until,omitempty"`
+	FirstSeenAt    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=first_seen_at,json=firstSeenAt,proto3" json:"first_seen_at,omitempty"`
+	LastSeenAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	ResolvedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"`
+	AcknowledgedBy string                 `protobuf:"bytes,14,opt,name=acknowledged_by,json=acknowledgedBy,proto3" json:"acknowledged_by,omitempty"`
+	TenantId       string                 `protobuf:"bytes,15,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	ProjectId      string                 `protobuf:"bytes,16,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Instance) Reset() {
@@ -294,6 +305,180 @@ func (x *Instance) GetSourceRunId() string {
 	return ""
 }
 
+func (x *Instance) GetSnoozedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SnoozedUntil
+	}
+	return nil
+}
+
+func (x *Instance) GetFirstSeenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstSeenAt
+	}
+	return nil
+}
+
+func (x *Instance) GetLastSeenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return nil
+}
+
+func (x *Instance) GetResolvedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return nil
+}
+
+func (x *Instance) GetAcknowledgedBy() string {
+	if x != nil {
+		return x.AcknowledgedBy
+	}
+	return ""
+}
+
+func (x *Instance) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Instance) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+// InstanceTransition is one row of the append-only instance history.
+type InstanceTransition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	InstanceId    string                 `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	DefinitionId  string                 `protobuf:"bytes,3,opt,name=definition_id,json=definitionId,proto3" json:"definition_id,omitempty"`
+	EntityRef     string                 `protobuf:"bytes,4,opt,name=entity_ref,json=entityRef,proto3" json:"entity_ref,omitempty"`
+	FromStatus    string                 `protobuf:"bytes,5,opt,name=from_status,json=fromStatus,proto3" json:"from_status,omitempty"` // Empty when the instance was created
+	ToStatus      string                 `protobuf:"bytes,6,opt,name=to_status,json=toStatus,proto3" json:"to_status,omitempty"`
+	Actor         string                 `protobuf:"bytes,7,opt,name=actor,proto3" json:"actor,omitempty"` // User id, or "system" for run-driven changes
+	Reason        string                 `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	SourceRunId   string                 `protobuf:"bytes,9,opt,name=source_run_id,json=sourceRunId,proto3" json:"source_run_id,omitempty"`
+	SnoozedUntil  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=snoozed_until,json=snoozedUntil,proto3" json:"snoozed_until,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstanceTransition) Reset() {
+	*x = InstanceTransition{}
+	mi := &file_signal_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstanceTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceTransition) ProtoMessage() {}
+
+func (x *InstanceTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_signal_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceTransition.ProtoReflect.Descriptor instead.
+func (*InstanceTransition) Descriptor() ([]byte, []int) {
+	return file_signal_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *InstanceTransition) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *InstanceTransition) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *InstanceTransition) GetDefinitionId() string {
+	if x != nil {
+		return x.DefinitionId
+	}
+	return ""
+}
+
+func (x *InstanceTransition) GetEntityRef() string {
+	if x != nil {
+		return x.EntityRef
+	}
+	return ""
+}
+
+func (x *InstanceTransition) GetFromStatus() string {
+	if x != nil {
+		return x.FromStatus
+	}
+	return ""
+}
+
+func (x *InstanceTransition) GetToStatus() string {
+	if x != nil {
+		return x.ToStatus
+	}
+	return ""
+}
+
+func (x *InstanceTransition) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *InstanceTransition) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *InstanceTransition) GetSourceRunId() string {
+	if x != nil {
+		return x.SourceRunId
+	}
+	return ""
+}
+
+func (x *InstanceTransition) GetSnoozedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SnoozedUntil
+	}
+	return nil
+}
+
+func (x *InstanceTransition) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 type UpsertDefinitionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Definition    *Definition            `protobuf:"bytes,1,opt,name=definition,proto3" json:"definition,omitempty"`
@@ -303,7 +488,7 @@ type UpsertDefinitionRequest struct {
 
 func (x *UpsertDefinitionRequest) Reset() {
 	*x = UpsertDefinitionRequest{}
-	mi := &file_signal_proto_msgTypes[2]
+	mi := &file_signal_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -315,7 +500,7 @@ func (x *UpsertDefinitionRequest) String() string {
 func (*UpsertDefinitionRequest) ProtoMessage() {}
 
 func (x *UpsertDefinitionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_signal_proto_msgTypes[2]
+	mi := &file_signal_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -328,7 +513,7 @@ func (x *UpsertDefinitionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertDefinitionRequest.ProtoReflect.Descriptor instead.
 func (*UpsertDefinitionRequest) Descriptor() ([]byte, []int) {
-	return file_signal_proto_rawDescGZIP(), []int{2}
+	return file_signal_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *UpsertDefinitionRequest) GetDefinition() *Definition {
@@ -347,7 +532,7 @@ type UpsertDefinitionResponse struct {
 
 func (x *UpsertDefinitionResponse) Reset() {
 	*x = UpsertDefinitionResponse{}
-	mi := &file_signal_proto_msgTypes[3]
+	mi := &file_signal_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -359,7 +544,7 @@ func (x *UpsertDefinitionResponse) String() string {
 func (*UpsertDefinitionResponse) ProtoMessage() {}
 
 func (x *UpsertDefinitionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_signal_proto_msgTypes[3]
+	mi := &file_signal_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -372,7 +557,7 @@ func (x *UpsertDefinitionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertDefinitionResponse.ProtoReflect.Descriptor instead.
 func (*UpsertDefinitionResponse) Descriptor() ([]byte, []int) {
-	return file_signal_proto_rawDescGZIP(), []int{3}
+	return file_signal_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *UpsertDefinitionResponse) GetId() string {
@@ -391,7 +576,7 @@ type UpsertInstanceRequest struct {
 
 func (x *UpsertInstanceRequest) Reset() {
 	*x = UpsertInstanceRequest{}
-	mi := &file_signal_proto_msgTypes[4]
+	mi := &file_signal_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -403,7 +588,7 @@ func (x *UpsertInstanceRequest) String() string {
 func (*UpsertInstanceRequest) ProtoMessage() {}
 
 func (x *UpsertInstanceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_signal_proto_msgTypes[4]
+	mi := &file_signal_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -416,7 +601,7 @@ func (x *UpsertInstanceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertInstanceRequest.ProtoReflect.Descriptor instead.
 func (*UpsertInstanceRequest) Descriptor() ([]byte, []int) {
-	return file_signal_proto_rawDescGZIP(), []int{4}
+	return file_signal_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *UpsertInstanceRequest) GetInstance() *Instance {
@@ -434,7 +619,7 @@ type UpsertInstanceResponse struct {
 
 func (x *UpsertInstanceResponse) Reset() {
 	*x = UpsertInstanceResponse{}
-	mi := &file_signal_proto_msgTypes[5]
+	mi := &file_signal_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -446,7 +631,7 @@ func (x *UpsertInstanceResponse) String() string {
 func (*UpsertInstanceResponse) ProtoMessage() {}
 
 func (x *UpsertInstanceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_signal_proto_msgTypes[5]
+	mi := &file_signal_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -459,7 +644,7 @@ func (x *UpsertInstanceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertInstanceResponse.ProtoReflect.Descriptor instead.
 func (*UpsertInstanceResponse) Descriptor() ([]byte, []int) {
-	return file_signal_proto_rawDescGZIP(), []int{5}
+	return file_signal_proto_rawDescGZIP(), []int{6}
 }
 
 type ListDefinitionsRequest struct {
@@ -471,7 +656,7 @@ type ListDefinitionsRequest struct {
 
 func (x *ListDefinitionsRequest) Reset() {
 	*x = ListDefinitionsRequest{}
-	mi := &file_signal_proto_msgTypes[6]
+	mi := &file_signal_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -483,7 +668,7 @@ func (x *ListDefinitionsRequest) String() string {
 func (*ListDefinitionsRequest) ProtoMessage() {}
 
 func (x *ListDefinitionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_signal_proto_msgTypes[6]
+	mi := &file_signal_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -496,7 +681,7 @@ func (x *ListDefinitionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDefinitionsRequest.ProtoReflect.Descriptor instead.
 func (*ListDefinitionsRequest) Descriptor() ([]byte, []int) {
-	return file_signal_proto_rawDescGZIP(), []int{6}
+	return file_signal_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ListDefinitionsRequest) GetSourceFamily() string {
@@ -515,7 +700,7 @@ type ListDefinitionsResponse struct {
 
 func (x *ListDefinitionsResponse) Reset() {
 	*x = ListDefinitionsResponse{}
-	mi := &file_signal_proto_msgTypes[7]
+	mi := &file_signal_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -527,7 +712,7 @@ func (x *ListDefinitionsResponse) String() string {
 func (*ListDefinitionsResponse) ProtoMessage() {}
 
 func (x *ListDefinitionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_signal_proto_msgTypes[7]
+	mi := &file_signal_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -540,7 +725,7 @@ func (x *ListDefinitionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDefinitionsResponse.ProtoReflect.Descriptor instead.
 func (*ListDefinitionsResponse) Descriptor() ([]byte, []int) {
-	return file_signal_proto_rawDescGZIP(), []int{7}
+	return file_signal_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ListDefinitionsResponse) GetDefinitions() []*Definition {
@@ -559,7 +744,7 @@ type ListInstancesForDefinitionRequest struct {
 
 func (x *ListInstancesForDefinitionRequest) Reset() {
 	*x = ListInstancesForDefinitionRequest{}
-	mi := &file_signal_proto_msgTypes[8]
+	mi := &file_signal_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -571,7 +756,7 @@ func (x *ListInstancesForDefinitionRequest) String() string {
 func (*ListInstancesForDefinitionRequest) ProtoMessage() {}
 
 func (x *ListInstancesForDefinitionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_signal_proto_msgTypes[8]
+	mi := &file_signal_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -584,7 +769,7 @@ func (x *ListInstancesForDefinitionRequest) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use ListInstancesForDefinitionRequest.ProtoReflect.Descriptor instead.
 func (*ListInstancesForDefinitionRequest) Descriptor() ([]byte, []int) {
-	return file_signal_proto_rawDescGZIP(), []int{8}
+	return file_signal_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ListInstancesForDefinitionRequest) GetDefinitionId() string {
@@ -603,7 +788,7 @@ type ListInstancesForDefinitionResponse struct {
 
 func (x *ListInstancesForDefinitionResponse) Reset() {
 	*x = ListInstancesForDefinitionResponse{}
-	mi := &file_signal_proto_msgTypes[9]
+	mi := &file_signal_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -615,7 +800,7 @@ func (x *ListInstancesForDefinitionResponse) String() string {
 func (*ListInstancesForDefinitionResponse) ProtoMessage() {}
 
 func (x *ListInstancesForDefinitionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_signal_proto_msgTypes[9]
+	mi := &file_signal_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -628,7 +813,7 @@ func (x *ListInstancesForDefinitionResponse) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use ListInstancesForDefinitionResponse.ProtoReflect.Descriptor instead.
 func (*ListInstancesForDefinitionResponse) Descriptor() ([]byte, []int) {
-	return file_signal_proto_rawDescGZIP(), []int{9}
+	return file_signal_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ListInstancesForDefinitionResponse) GetInstances() []*Instance {
@@ -649,7 +834,7 @@ type UpdateInstanceStatusRequest struct {
 
 func (x *UpdateInstanceStatusRequest) Reset() {
 	*x = UpdateInstanceStatusRequest{}
-	mi := &file_signal_proto_msgTypes[10]
+	mi := &file_signal_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -661,7 +846,7 @@ func (x *UpdateInstanceStatusRequest) String() string {
 func (*UpdateInstanceStatusRequest) ProtoMessage() {}
 
 func (x *UpdateInstanceStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_signal_proto_msgTypes[10]
+	mi := &file_signal_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -674,7 +859,7 @@ func (x *UpdateInstanceStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateInstanceStatusRequest.ProtoReflect.Descriptor instead.
 func (*UpdateInstanceStatusRequest) Descriptor() ([]byte, []int) {
-	return file_signal_proto_rawDescGZIP(), []int{10}
+	return file_signal_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *UpdateInstanceStatusRequest) GetDefinitionId() string {
@@ -706,7 +891,7 @@ type UpdateInstanceStatusResponse struct {
 
 func (x *UpdateInstanceStatusResponse) Reset() {
 	*x = UpdateInstanceStatusResponse{}
-	mi := &file_signal_proto_msgTypes[11]
+	mi := &file_signal_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -718,7 +903,7 @@ func (x *UpdateInstanceStatusResponse) String() string {
 func (*UpdateInstanceStatusResponse) ProtoMessage() {}
 
 func (x *UpdateInstanceStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_signal_proto_msgTypes[11]
+	mi := &file_signal_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -731,14 +916,288 @@ func (x *UpdateInstanceStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateInstanceStatusResponse.ProtoReflect.Descriptor instead.
 func (*UpdateInstanceStatusResponse) Descriptor() ([]byte, []int) {
-	return file_signal_proto_rawDescGZIP(), []int{11}
+	return file_signal_proto_rawDescGZIP(), []int{12}
+}
+
+// TransitionInstanceRequest addresses an instance by id, or by
+// (definition_id, entity_ref).
+type TransitionInstanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	DefinitionId  string                 `protobuf:"bytes,2,opt,name=definition_id,json=definitionId,proto3" json:"definition_id,omitempty"`
+	EntityRef     string                 `protobuf:"bytes,3,opt,name=entity_ref,json=entityRef,proto3" json:"entity_ref,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	SnoozedUntil  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=snoozed_until,json=snoozedUntil,proto3" json:"snoozed_until,omitempty"` // Required for SNOOZED
+	Actor         string                 `protobuf:"bytes,6,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	SourceRunId   string                 `protobuf:"bytes,8,opt,name=source_run_id,json=sourceRunId,proto3" json:"source_run_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitionInstanceRequest) Reset() {
+	*x = TransitionInstanceRequest{}
+	mi := &file_signal_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitionInstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitionInstanceRequest) ProtoMessage() {}
+
+func (x *TransitionInstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_signal_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitionInstanceRequest.ProtoReflect.Descriptor instead.
+func (*TransitionInstanceRequest) Descriptor() ([]byte, []int) {
+	return file_signal_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TransitionInstanceRequest) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *TransitionInstanceRequest) GetDefinitionId() string {
+	if x != nil {
+		return x.DefinitionId
+	}
+	return ""
+}
+
+func (x *TransitionInstanceRequest) GetEntityRef() string {
+	if x != nil {
+		return x.EntityRef
+	}
+	return ""
+}
+
+func (x *TransitionInstanceRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TransitionInstanceRequest) GetSnoozedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SnoozedUntil
+	}
+	return nil
+}
+
+func (x *TransitionInstanceRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *TransitionInstanceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *TransitionInstanceRequest) GetSourceRunId() string {
+	if x != nil {
+		return x.SourceRunId
+	}
+	return ""
+}
+
+type TransitionInstanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instance      *Instance              `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Changed       bool                   `protobuf:"varint,2,opt,name=changed,proto3" json:"changed,omitempty"` // False when the instance was already in the requested state
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitionInstanceResponse) Reset() {
+	*x = TransitionInstanceResponse{}
+	mi := &file_signal_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitionInstanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitionInstanceResponse) ProtoMessage() {}
+
+func (x *TransitionInstanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_signal_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitionInstanceResponse.ProtoReflect.Descriptor instead.
+func (*TransitionInstanceResponse) Descriptor() ([]byte, []int) {
+	return file_signal_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TransitionInstanceResponse) GetInstance() *Instance {
+	if x != nil {
+		return x.Instance
+	}
+	return nil
+}
+
+func (x *TransitionInstanceResponse) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+type ListInstanceHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	DefinitionId  string                 `protobuf:"bytes,2,opt,name=definition_id,json=definitionId,proto3" json:"definition_id,omitempty"`
+	EntityRef     string                 `protobuf:"bytes,3,opt,name=entity_ref,json=entityRef,proto3" json:"entity_ref,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"`
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInstanceHistoryRequest) Reset() {
+	*x = ListInstanceHistoryRequest{}
+	mi := &file_signal_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInstanceHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInstanceHistoryRequest) ProtoMessage() {}
+
+func (x *ListInstanceHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_signal_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInstanceHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListInstanceHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_signal_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListInstanceHistoryRequest) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *ListInstanceHistoryRequest) GetDefinitionId() string {
+	if x != nil {
+		return x.DefinitionId
+	}
+	return ""
+}
+
+func (x *ListInstanceHistoryRequest) GetEntityRef() string {
+	if x != nil {
+		return x.EntityRef
+	}
+	return ""
+}
+
+func (x *ListInstanceHistoryRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListInstanceHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListInstanceHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transitions   []*InstanceTransition  `protobuf:"bytes,1,rep,name=transitions,proto3" json:"transitions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInstanceHistoryResponse) Reset() {
+	*x = ListInstanceHistoryResponse{}
+	mi := &file_signal_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInstanceHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInstanceHistoryResponse) ProtoMessage() {}
+
+func (x *ListInstanceHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_signal_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInstanceHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListInstanceHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_signal_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListInstanceHistoryResponse) GetTransitions() []*InstanceTransition {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
 }
 
 var File_signal_proto protoreflect.FileDescriptor
 
 const file_signal_proto_rawDesc = "" +
 	"\n" +
-	"\fsignal.proto\x12\x06signal\x1a\x1cgoogle/protobuf/struct.proto\"\x8f\x04\n" +
+	"\fsignal.proto\x12\x06signal\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8f\x04\n" +
 	"\n" +
 	"Definition\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
@@ -760,7 +1219,7 @@ const file_signal_proto_rawDesc = "" +
 	"cdmModelId\x12<\n" +
 	"\rsurface_hints\x18\x0e \x01(\v2\x17.google.protobuf.StructR\fsurfaceHints\x12\x14\n" +
 	"\x05owner\x18\x0f \x01(\tR\x05owner\x12@\n" +
-	"\x0fdefinition_spec\x18\x10 \x01(\v2\x17.google.protobuf.StructR\x0edefinitionSpec\"\xa4\x02\n" +
+	"\x0fdefinition_spec\x18\x10 \x01(\v2\x17.google.protobuf.StructR\x0edefinitionSpec\"\x85\x05\n" +
 	"\bInstance\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
 	"\rdefinition_id\x18\x02 \x01(\tR\fdefinitionId\x12\x16\n" +
@@ -772,7 +1231,35 @@ const file_signal_proto_rawDesc = "" +
 	"\bseverity\x18\x06 \x01(\tR\bseverity\x12\x18\n" +
 	"\asummary\x18\a \x01(\tR\asummary\x121\n" +
 	"\adetails\x18\b \x01(\v2\x17.google.protobuf.StructR\adetails\x12\"\n" +
-	"\rsource_run_id\x18\t \x01(\tR\vsourceRunId\"M\n" +
+	"\rsource_run_id\x18\t \x01(\tR\vsourceRunId\x12?\n" +
+	"\rsnoozed_until\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\fsnoozedUntil\x12>\n" +
+	"\rfirst_seen_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vfirstSeenAt\x12<\n" +
+	"\flast_seen_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastSeenAt\x12;\n" +
+	"\vresolved_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"resolvedAt\x12'\n" +
+	"\x0facknowledged_by\x18\x0e \x01(\tR\x0eacknowledgedBy\x12\x1b\n" +
+	"\ttenant_id\x18\x0f \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x10 \x01(\tR\tprojectId\"\x95\x03\n" +
+	"\x12InstanceTransition\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\tR\n" +
+	"instanceId\x12#\n" +
+	"\rdefinition_id\x18\x03 \x01(\tR\fdefinitionId\x12\x1d\n" +
+	"\n" +
+	"entity_ref\x18\x04 \x01(\tR\tentityRef\x12\x1f\n" +
+	"\vfrom_status\x18\x05 \x01(\tR\n" +
+	"fromStatus\x12\x1b\n" +
+	"\tto_status\x18\x06 \x01(\tR\btoStatus\x12\x14\n" +
+	"\x05actor\x18\a \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\x12\"\n" +
+	"\rsource_run_id\x18\t \x01(\tR\vsourceRunId\x12?\n" +
+	"\rsnoozed_until\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\fsnoozedUntil\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"M\n" +
 	"\x17UpsertDefinitionRequest\x122\n" +
 	"\n" +
 	"definition\x18\x01 \x01(\v2\x12.signal.DefinitionR\n" +
@@ -795,13 +1282,39 @@ const file_signal_proto_rawDesc = "" +
 	"\n" +
 	"entity_ref\x18\x02 \x01(\tR\tentityRef\x12\x16\n" +
 	"\x06status\x18\x03 \x01(\tR\x06status\"\x1e\n" +
-	"\x1cUpdateInstanceStatusResponse2\xe3\x03\n" +
+	"\x1cUpdateInstanceStatusResponse\"\xab\x02\n" +
+	"\x19TransitionInstanceRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\tR\n" +
+	"instanceId\x12#\n" +
+	"\rdefinition_id\x18\x02 \x01(\tR\fdefinitionId\x12\x1d\n" +
+	"\n" +
+	"entity_ref\x18\x03 \x01(\tR\tentityRef\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12?\n" +
+	"\rsnoozed_until\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\fsnoozedUntil\x12\x14\n" +
+	"\x05actor\x18\x06 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12\"\n" +
+	"\rsource_run_id\x18\b \x01(\tR\vsourceRunId\"d\n" +
+	"\x1aTransitionInstanceResponse\x12,\n" +
+	"\binstance\x18\x01 \x01(\v2\x10.signal.InstanceR\binstance\x12\x18\n" +
+	"\achanged\x18\x02 \x01(\bR\achanged\"\xc9\x01\n" +
+	"\x1aListInstanceHistoryRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\tR\n" +
+	"instanceId\x12#\n" +
+	"\rdefinition_id\x18\x02 \x01(\tR\fdefinitionId\x12\x1d\n" +
+	"\n" +
+	"entity_ref\x18\x03 \x01(\tR\tentityRef\x120\n" +
+	"\x05since\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"[\n" +
+	"\x1bListInstanceHistoryResponse\x12<\n" +
+	"\vtransitions\x18\x01 \x03(\v2\x1a.signal.InstanceTransitionR\vtransitions2\xa0\x05\n" +
 	"\rSignalService\x12U\n" +
 	"\x10UpsertDefinition\x12\x1f.signal.UpsertDefinitionRequest\x1a .signal.UpsertDefinitionResponse\x12O\n" +
 	"\x0eUpsertInstance\x12\x1d.signal.UpsertInstanceRequest\x1a\x1e.signal.UpsertInstanceResponse\x12R\n" +
 	"\x0fListDefinitions\x12\x1e.signal.ListDefinitionsRequest\x1a\x1f.signal.ListDefinitionsResponse\x12s\n" +
 	"\x1aListInstancesForDefinition\x12).signal.ListInstancesForDefinitionRequest\x1a*.signal.ListInstancesForDefinitionResponse\x12a\n" +
-	"\x14UpdateInstanceStatus\x12#.signal.UpdateInstanceStatusRequest\x1a$.signal.UpdateInstanceStatusResponseB/Z-github.com/nucleus/store-core/gen/go/signalpbb\x06proto3"
+	"\x14UpdateInstanceStatus\x12#.signal.UpdateInstanceStatusRequest\x1a$.signal.UpdateInstanceStatusResponse\x12[\n" +
+	"\x12TransitionInstance\x12!.signal.TransitionInstanceRequest\x1a\".signal.TransitionInstanceResponse\x12^\n" +
+	"\x13ListInstanceHistory\x12\".signal.ListInstanceHistoryRequest\x1a#.signal.ListInstanceHistoryResponseB/Z-github.com/nucleus/store-core/gen/go/signalpbb\x06proto3"
 
 var (
 	file_signal_proto_rawDescOnce sync.Once
@@ -815,45 +1328,65 @@ func file_signal_proto_rawDescGZIP() []byte {
 	return file_signal_proto_rawDescData
 }
 
-var file_signal_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_signal_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_signal_proto_goTypes = []any{
 	(*Definition)(nil),                         // 0: signal.Definition
 	(*Instance)(nil),                           // 1: signal.Instance
-	(*UpsertDefinitionRequest)(nil),            // 2: signal.UpsertDefinitionRequest
-	(*UpsertDefinitionResponse)(nil),           // 3: signal.UpsertDefinitionResponse
-	(*UpsertInstanceRequest)(nil),              // 4: signal.UpsertInstanceRequest
-	(*UpsertInstanceResponse)(nil),             // 5: signal.UpsertInstanceResponse
-	(*ListDefinitionsRequest)(nil),             // 6: signal.ListDefinitionsRequest
-	(*ListDefinitionsResponse)(nil),            // 7: signal.ListDefinitionsResponse
-	(*ListInstancesForDefinitionRequest)(nil),  // 8: signal.ListInstancesForDefinitionRequest
-	(*ListInstancesForDefinitionResponse)(nil), // 9: signal.ListInstancesForDefinitionResponse
-	(*UpdateInstanceStatusRequest)(nil),        // 10: signal.UpdateInstanceStatusRequest
-	(*UpdateInstanceStatusResponse)(nil),       // 11: signal.UpdateInstanceStatusResponse
-	(*structpb.Struct)(nil),                    // 12: google.protobuf.Struct
+	(*InstanceTransition)(nil),                 // 2: signal.InstanceTransition
+	(*UpsertDefinitionRequest)(nil),            // 3: signal.UpsertDefinitionRequest
+	(*UpsertDefinitionResponse)(nil),           // 4: signal.UpsertDefinitionResponse
+	(*UpsertInstanceRequest)(nil),              // 5: signal.UpsertInstanceRequest
+	(*UpsertInstanceResponse)(nil),             // 6: signal.UpsertInstanceResponse
+	(*ListDefinitionsRequest)(nil),             // 7: signal.ListDefinitionsRequest
+	(*ListDefinitionsResponse)(nil),            // 8: signal.ListDefinitionsResponse
+	(*ListInstancesForDefinitionRequest)(nil),  // 9: signal.ListInstancesForDefinitionRequest
+	(*ListInstancesForDefinitionResponse)(nil), // 10: signal.ListInstancesForDefinitionResponse
+	(*UpdateInstanceStatusRequest)(nil),        // 11: signal.UpdateInstanceStatusRequest
+	(*UpdateInstanceStatusResponse)(nil),       // 12: signal.UpdateInstanceStatusResponse
+	(*TransitionInstanceRequest)(nil),          // 13: signal.TransitionInstanceRequest
+	(*TransitionInstanceResponse)(nil),         // 14: signal.TransitionInstanceResponse
+	(*ListInstanceHistoryRequest)(nil),         // 15: signal.ListInstanceHistoryRequest
+	(*ListInstanceHistoryResponse)(nil),        // 16: signal.ListInstanceHistoryResponse
+	(*structpb.Struct)(nil),                    // 17: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),              // 18: google.protobuf.Timestamp
 }
 var file_signal_proto_depIdxs = []int32{
-	12, // 0: signal.Definition.surface_hints:type_name -> google.protobuf.Struct
-	12, // 1: signal.Definition.definition_spec:type_name -> google.protobuf.Struct
-	12, // 2: signal.Instance.details:type_name -> google.protobuf.Struct
-	0,  // 3: signal.UpsertDefinitionRequest.definition:type_name -> signal.Definition
-	1,  // 4: signal.UpsertInstanceRequest.instance:type_name -> signal.Instance
-	0,  // 5: signal.ListDefinitionsResponse.definitions:type_name -> signal.Definition
-	1,  // 6: signal.ListInstancesForDefinitionResponse.instances:type_name -> signal.Instance
-	2,  // 7: signal.SignalService.UpsertDefinition:input_type -> signal.UpsertDefinitionRequest
-	4,  // 8: signal.SignalService.UpsertInstance:input_type -> signal.UpsertInstanceRequest
-	6,  // 9: signal.SignalService.ListDefinitions:input_type -> signal.ListDefinitionsRequest
-	8,  // 10: signal.SignalService.ListInstancesForDefinition:input_type -> signal.ListInstancesForDefinitionRequest
-	10, // 11: signal.SignalService.UpdateInstanceStatus:input_type -> signal.UpdateInstanceStatusRequest
-	3,  // 12: signal.SignalService.UpsertDefinition:output_type -> signal.UpsertDefinitionResponse
-	5,  // 13: signal.SignalService.UpsertInstance:output_type -> signal.UpsertInstanceResponse
-	7,  // 14: signal.SignalService.ListDefinitions:output_type -> signal.ListDefinitionsResponse
-	9,  // 15: signal.SignalService.ListInstancesForDefinition:output_type -> signal.ListInstancesForDefinitionResponse
-	11, // 16: signal.SignalService.UpdateInstanceStatus:output_type -> signal.UpdateInstanceStatusResponse
-	12, // [12:17] is the sub-list for method output_type
-	7,  // [7:12] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	17, // 0: signal.Definition.surface_hints:type_name -> google.protobuf.Struct
+	17, // 1: signal.Definition.definition_spec:type_name -> google.protobuf.Struct
+	17, // 2: signal.Instance.details:type_name -> google.protobuf.Struct
+	18, // 3: signal.Instance.snoozed_until:type_name -> google.protobuf.Timestamp
+	18, // 4: signal.Instance.first_seen_at:type_name -> google.protobuf.Timestamp
+	18, // 5: signal.Instance.last_seen_at:type_name -> google.protobuf.Timestamp
+	18, // 6: signal.Instance.resolved_at:type_name -> google.protobuf.Timestamp
+	18, // 7: signal.InstanceTransition.snoozed_until:type_name -> google.protobuf.Timestamp
+	18, // 8: signal.InstanceTransition.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 9: signal.UpsertDefinitionRequest.definition:type_name -> signal.Definition
+	1,  // 10: signal.UpsertInstanceRequest.instance:type_name -> signal.Instance
+	0,  // 11: signal.ListDefinitionsResponse.definitions:type_name -> signal.Definition
+	1,  // 12: signal.ListInstancesForDefinitionResponse.instances:type_name -> signal.Instance
+	18, // 13: signal.TransitionInstanceRequest.snoozed_until:type_name -> google.protobuf.Timestamp
+	1,  // 14: signal.TransitionInstanceResponse.instance:type_name -> signal.Instance
+	18, // 15: signal.ListInstanceHistoryRequest.since:type_name -> google.protobuf.Timestamp
+	2,  // 16: signal.ListInstanceHistoryResponse.transitions:type_name -> signal.InstanceTransition
+	3,  // 17: signal.SignalService.UpsertDefinition:input_type -> signal.UpsertDefinitionRequest
+	5,  // 18: signal.SignalService.UpsertInstance:input_type -> signal.UpsertInstanceRequest
+	7,  // 19: signal.SignalService.ListDefinitions:input_type -> signal.ListDefinitionsRequest
+	9,  // 20: signal.SignalService.ListInstancesForDefinition:input_type -> signal.ListInstancesForDefinitionRequest
+	11, // 21: signal.SignalService.UpdateInstanceStatus:input_type -> signal.UpdateInstanceStatusRequest
+	13, // 22: signal.SignalService.TransitionInstance:input_type -> signal.TransitionInstanceRequest
+	15, // 23: signal.SignalService.ListInstanceHistory:input_type -> signal.ListInstanceHistoryRequest
+	4,  // 24: signal.SignalService.UpsertDefinition:output_type -> signal.UpsertDefinitionResponse
+	6,  // 25: signal.SignalService.UpsertInstance:output_type -> signal.UpsertInstanceResponse
+	8,  // 26: signal.SignalService.ListDefinitions:output_type -> signal.ListDefinitionsResponse
+	10, // 27: signal.SignalService.ListInstancesForDefinition:output_type -> signal.ListInstancesForDefinitionResponse
+	12, // 28: signal.SignalService.UpdateInstanceStatus:output_type -> signal.UpdateInstanceStatusResponse
+	14, // 29: signal.SignalService.TransitionInstance:output_type -> signal.TransitionInstanceResponse
+	16, // 30: signal.SignalService.ListInstanceHistory:output_type -> signal.ListInstanceHistoryResponse
+	24, // [24:31] is the sub-list for method output_type
+	17, // [17:24] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_signal_proto_init() }
@@ -867,7 +1400,7 @@ func file_signal_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_signal_proto_rawDesc), len(file_signal_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -24,6 +24,8 @@ const (
 	SignalService_ListDefinitions_FullMethodName            = "/signal.SignalService/ListDefinitions"
 	SignalService_ListInstancesForDefinition_FullMethodName = "/signal.SignalService/ListInstancesForDefinition"
 	SignalService_UpdateInstanceStatus_FullMethodName       = "/signal.SignalService/UpdateInstanceStatus"
+	SignalService_TransitionInstance_FullMethodName         = "/signal.SignalService/TransitionInstance"
+	SignalService_ListInstanceHistory_FullMethodName        = "/signal.SignalService/ListInstanceHistory"
 )
 
 // SignalServiceClient is the client API for SignalService service.
@@ -35,6 +37,10 @@ type SignalServiceClient interface {
 	ListDefinitions(ctx context.Context, in *ListDefinitionsRequest, opts ...grpc.CallOption) (*ListDefinitionsResponse, error)
 	ListInstancesForDefinition(ctx context.Context, in *ListInstancesForDefinitionRequest, opts ...grpc.CallOption) (*ListInstancesForDefinitionResponse, error)
 	UpdateInstanceStatus(ctx context.Context, in *UpdateInstanceStatusRequest, opts ...grpc.CallOption) (*UpdateInstanceStatusResponse, error)
+	// TransitionInstance moves an instance through its lifecycle (acknowledge,
+	// snooze, resolve, reopen) and records the change in the instance history.
+	TransitionInstance(ctx context.Context, in *TransitionInstanceRequest, opts ...grpc.CallOption) (*TransitionInstanceResponse, error)
+	ListInstanceHistory(ctx context.Context, in *ListInstanceHistoryRequest, opts ...grpc.CallOption) (*ListInstanceHistoryResponse, error)
 }
 
 type signalServiceClient struct {
@@ -95,6 +101,26 @@ func (c *signalServiceClient) UpdateInstanceStatus(ctx context.Context, in *Upda
 	return out, nil
 }
 
+func (c *signalServiceClient) TransitionInstance(ctx context.Context, in *TransitionInstanceRequest, opts ...grpc.CallOption) (*TransitionInstanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransitionInstanceResponse)
+	err := c.cc.Invoke(ctx, SignalService_TransitionInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalServiceClient) ListInstanceHistory(ctx context.Context, in *ListInstanceHistoryRequest, opts ...grpc.CallOption) (*ListInstanceHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInstanceHistoryResponse)
+	err := c.cc.Invoke(ctx, SignalService_ListInstanceHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SignalServiceServer is the server API for SignalService service.
 // All implementations must embed UnimplementedSignalServiceServer
 // for forward compatibility.
@@ -104,6 +130,10 @@ type SignalServiceServer interface {
 	ListDefinitions(context.Context, *ListDefinitionsRequest) (*ListDefinitionsResponse, error)
 	ListInstancesForDefinition(context.Context, *ListInstancesForDefinitionRequest) (*ListInstancesForDefinitionResponse, error)
 	UpdateInstanceStatus(context.Context, *UpdateInstanceStatusRequest) (*UpdateInstanceStatusResponse, error)
+	// TransitionInstance moves an instance through its lifecycle (acknowledge,
+	// snooze, resolve, reopen) and records the change in the instance history.
+	TransitionInstance(context.Context, *TransitionInstanceRequest) (*TransitionInstanceResponse, error)
+	ListInstanceHistory(context.Context, *ListInstanceHistoryRequest) (*ListInstanceHistoryResponse, error)
 	mustEmbedUnimplementedSignalServiceServer()
 }
 
@@ -129,6 +159,12 @@ func (UnimplementedSignalServiceServer) ListInstancesForDefinition(context.Conte
 func (UnimplementedSignalServiceServer) UpdateInstanceStatus(context.Context, *UpdateInstanceStatusRequest) (*UpdateInstanceStatusResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpdateInstanceStatus not implemented")
 }
+func (UnimplementedSignalServiceServer) TransitionInstance(context.Context, *TransitionInstanceRequest) (*TransitionInstanceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TransitionInstance not implemented")
+}
+func (UnimplementedSignalServiceServer) ListInstanceHistory(context.Context, *ListInstanceHistoryRequest) (*ListInstanceHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListInstanceHistory not implemented")
+}
 func (UnimplementedSignalServiceServer) mustEmbedUnimplementedSignalServiceServer() {}
 func (UnimplementedSignalServiceServer) testEmbeddedByValue()                       {}
 
@@ -240,6 +276,42 @@ func _SignalService_UpdateInstanceStatus_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SignalService_TransitionInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalServiceServer).TransitionInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignalService_TransitionInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalServiceServer).TransitionInstance(ctx, req.(*TransitionInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SignalService_ListInstanceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInstanceHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalServiceServer).ListInstanceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignalService_ListInstanceHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalServiceServer).ListInstanceHistory(ctx, req.(*ListInstanceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SignalService_ServiceDesc is the grpc.ServiceDesc for SignalService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,6 +339,14 @@ var SignalService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateInstanceStatus",
 			Handler:    _SignalService_UpdateInstanceStatus_Handler,
 		},
+		{
+			MethodName: "TransitionInstance",
+			Handler:    _SignalService_TransitionInstance_Handler,
+		},
+		{
+			MethodName: "ListInstanceHistory",
+			Handler:    _SignalService_ListInstanceHistory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "signal.proto",
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// ===================================================
+// Candidate Blocking
+// Indexed keys that bring plausible duplicates together without scanning
+// every entity of a type
+// ===================================================
+
+// CandidateIndex is an optional EntityRegistry extension that looks up
+// entities sharing any blocking key. DefaultEntityMatcher uses it when the
+// registry provides it and falls back to name-prefix listing otherwise.
+type CandidateIndex interface {
+	ListByBlockingKeys(ctx context.Context, tenantID string, types []string, keys []string, limit int) ([]*CanonicalEntity, error)
+}
+
+// Blocking key prefixes.
+const (
+	blockEmail    = "email:"
+	blockName     = "name:"
+	blockPhonetic = "phon:"
+	blockDomain   = "dom:"
+)
+
+// SourceBlockingKeys returns the blocking keys of a source entity.
+func SourceBlockingKeys(source SourceEntity) []string {
+	names := append([]string{source.Name}, source.Aliases...)
+	emails := []string{source.Email}
+	for _, a := range source.Aliases {
+		if strings.Contains(a, "@") {
+			emails = append(emails, a)
+		}
+	}
+	return blockingKeys(names, emails)
+}
+
+// EntityBlockingKeys returns the blocking keys a registry should index for
+// a canonical entity.
+func EntityBlockingKeys(e *CanonicalEntity) []string {
+	return blockingKeys(append([]string{e.Name}, e.Aliases...), entityEmails(e))
+}
+
+// blockingKeys derives:
+//   - email:<address>
+//   - name:<normalized name>
+//   - phon:<surname metaphone>:<first initial>, so "J. Smith" meets "John Smith"
+//   - dom:<email domain>:<surname metaphone>, so one person's addresses on
+//     a shared domain meet without blocking the whole company together
+func blockingKeys(names, emails []string) []string {
+	set := map[string]bool{}
+	var phonetics []string
+	for _, name := range names {
+		if strings.Contains(name, "@") {
+			continue
+		}
+		norm := NormalizeName(name)
+		if norm == "" {
+			continue
+		}
+		set[blockName+norm] = true
+		tokens := strings.Fields(norm)
+		primary, alternate := DoubleMetaphone(tokens[len(tokens)-1])
+		for _, code := range []string{primary, alternate} {
+			if code == "" {
+				continue
+			}
+			phonetics = append(phonetics, code)
+			if len(tokens) > 1 {
+				set[blockPhonetic+code+":"+string([]rune(tokens[0])[0])] = true
+			} else {
+				set[blockPhonetic+code] = true
+			}
+		}
+	}
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" {
+			continue
+		}
+		set[blockEmail+email] = true
+		if domain := emailDomain(email); domain != "" {
+			for _, code := range phonetics {
+				set[blockDomain+domain+":"+code] = true
+			}
+		}
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
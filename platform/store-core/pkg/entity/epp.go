@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ===================================================
+// Policy and Process Entities
+// EPP structure stored on canonical entities
+// ===================================================
+
+// Canonical stores the policy as a canonical entity of type policy, with
+// its rules and terms under Properties["policy"].
+func (p *PolicyEntity) Canonical() *CanonicalEntity {
+	e := p.CanonicalEntity
+	e.Type = string(EntityTypePolicy)
+	e.Properties = withEPPProperty(e.Properties, "policy", map[string]any{
+		"rules":         p.Rules,
+		"appliesTo":     p.AppliesTo,
+		"enforcement":   p.Enforcement,
+		"effectiveDate": p.EffectiveDate,
+		"owners":        p.Owners,
+		"keywords":      p.Keywords,
+	})
+	return &e
+}
+
+// Canonical stores the process as a canonical entity of type process, with
+// its steps under Properties["process"].
+func (p *ProcessEntity) Canonical() *CanonicalEntity {
+	e := p.CanonicalEntity
+	e.Type = string(EntityTypeProcess)
+	e.Properties = withEPPProperty(e.Properties, "process", map[string]any{
+		"steps":    p.Steps,
+		"triggers": p.Triggers,
+		"outcomes": p.Outcomes,
+		"roles":    p.Roles,
+		"sla":      p.SLA,
+		"owner":    p.Owner,
+	})
+	return &e
+}
+
+// PolicyFromCanonical reads a policy stored by PolicyEntity.Canonical.
+func PolicyFromCanonical(e *CanonicalEntity) (*PolicyEntity, error) {
+	if e.Type != string(EntityTypePolicy) {
+		return nil, fmt.Errorf("entity %s is a %s, not a policy", e.ID, e.Type)
+	}
+	p := &PolicyEntity{CanonicalEntity: *e}
+	if err := decodeEPPProperty(e.Properties["policy"], p); err != nil {
+		return nil, fmt.Errorf("decode policy %s: %w", e.ID, err)
+	}
+	return p, nil
+}
+
+// ProcessFromCanonical reads a process stored by ProcessEntity.Canonical.
+func ProcessFromCanonical(e *CanonicalEntity) (*ProcessEntity, error) {
+	if e.Type != string(EntityTypeProcess) {
+		return nil, fmt.Errorf("entity %s is a %s, not a process", e.ID, e.Type)
+	}
+	p := &ProcessEntity{CanonicalEntity: *e}
+	if err := decodeEPPProperty(e.Properties["process"], p); err != nil {
+		return nil, fmt.Errorf("decode process %s: %w", e.ID, err)
+	}
+	return p, nil
+}
+
+func withEPPProperty(props map[string]any, key string, value map[string]any) map[string]any {
+	out := make(map[string]any, len(props)+1)
+	for k, v := range props {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// decodeEPPProperty decodes the structure whether it is still typed or has
+// been through JSON (as it has when read back from Postgres).
+func decodeEPPProperty(v any, into any) error {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
@@ -0,0 +1,410 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ===================================================
+// Identity Resolution
+// Linking one person's accounts across Jira, Confluence, GitHub and OneDrive
+// ===================================================
+
+// Account is one user account as a connector emits it.
+type Account struct {
+	Source       string   `json:"source"`       // Source system: jira, confluence, github, onedrive
+	AccountID    string   `json:"accountId"`    // Jira/Confluence accountId, GitHub login, Graph user ID
+	Login        string   `json:"login"`        // GitHub login
+	DisplayName  string   `json:"displayName"`  // Name shown in the source
+	Email        string   `json:"email"`        // Profile email
+	CommitEmails []string `json:"commitEmails"` // Git author/committer emails
+	URL          string   `json:"url"`          // Profile link
+	NodeID       string   `json:"nodeId"`       // KG node of the account; AccountNodeID when empty
+}
+
+// Key identifies the account within a tenant, as ResolveBatch keys results.
+func (a Account) Key() string {
+	return a.Source + ":" + a.AccountID
+}
+
+// AccountNodeID returns the KG node ID of an account. It matches the CDM
+// work-user ID (cdm:work:user:<source>:<account>), so SAME_AS edges attach
+// to the user nodes the connectors already write.
+func AccountNodeID(source, accountID string) string {
+	return fmt.Sprintf("cdm:work:user:%s:%s", source, accountID)
+}
+
+// personFields are record fields holding a user, as a nested object or (for
+// Confluence authors and OneDrive creators) a bare display name. In GitHub
+// records the bare string is a login.
+var personFields = []string{"reporter", "assignee", "creator", "author", "user", "createdBy", "updatedBy", "modifiedBy", "committer", "mergedBy", "lead"}
+
+// AccountsFromRecord extracts the user accounts in a connector record: the
+// record itself for user datasets (jira.users, cdm.work.user), and authors,
+// assignees and creators of issues, pages, commits and files. Accounts are
+// deduplicated by Key, merging their emails.
+func AccountsFromRecord(source string, rec map[string]any) []Account {
+	source = strings.ToLower(source)
+	var out []Account
+	if a, ok := accountFromMap(source, rec); ok {
+		out = append(out, a)
+	}
+	for _, field := range personFields {
+		switch v := rec[field].(type) {
+		case map[string]any:
+			if a, ok := accountFromMap(source, v); ok {
+				out = append(out, a)
+			}
+		case string:
+			if a, ok := accountFromString(source, v, stringField(rec, field+"Email")); ok {
+				out = append(out, a)
+			}
+		}
+	}
+	if list, ok := rec["assignees"].([]any); ok {
+		for _, item := range list {
+			if m, ok := item.(map[string]any); ok {
+				if a, ok := accountFromMap(source, m); ok {
+					out = append(out, a)
+				}
+			}
+		}
+	}
+	return dedupeAccounts(out)
+}
+
+// accountFromMap reads a user object. Field names cover the Jira and
+// Confluence REST users (accountId, emailAddress/email, displayName,
+// publicName), GitHub users (login, html_url), Microsoft Graph identities
+// ({user: {id, displayName, email}}) and CDM work users (sourceUserId).
+func accountFromMap(source string, m map[string]any) (Account, bool) {
+	graphUser := false
+	if inner, ok := m["user"].(map[string]any); ok && source == "onedrive" {
+		m, graphUser = inner, true
+	}
+	a := Account{
+		Source:      source,
+		Login:       stringField(m, "login"),
+		DisplayName: firstString(m, "displayName", "DisplayName", "publicName", "name"),
+		Email:       strings.ToLower(firstString(m, "emailAddress", "email", "Email", "mail")),
+		URL:         firstString(m, "html_url", "self"),
+	}
+	a.AccountID = firstString(m, "accountId", "sourceUserId", "SourceUserID")
+	if a.AccountID == "" {
+		a.AccountID = a.Login
+	}
+	if a.AccountID == "" && graphUser {
+		a.AccountID = stringField(m, "id")
+	}
+	if a.AccountID == "" {
+		return Account{}, false
+	}
+	if a.DisplayName == "" {
+		a.DisplayName = a.Login
+	}
+	return a, true
+}
+
+// accountFromString builds an account from a bare user field. GitHub
+// strings are logins, unless the commit has no linked login and the field
+// falls back to the git author name; other sources only give a display name,
+// which then doubles as the account ID.
+func accountFromString(source, value, email string) (Account, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Account{}, false
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	a := Account{Source: source, AccountID: value, DisplayName: value}
+	if source == "github" {
+		if email != "" {
+			a.CommitEmails = []string{email}
+		}
+		if !strings.Contains(value, " ") {
+			a.Login = value
+		} else if email != "" {
+			a.AccountID = email
+		}
+		return a, true
+	}
+	a.Email = email
+	return a, true
+}
+
+func dedupeAccounts(accounts []Account) []Account {
+	index := map[string]int{}
+	var out []Account
+	for _, a := range accounts {
+		i, ok := index[a.Key()]
+		if !ok {
+			index[a.Key()] = len(out)
+			out = append(out, a)
+			continue
+		}
+		cur := &out[i]
+		if cur.Email == "" {
+			cur.Email = a.Email
+		}
+		for _, e := range a.CommitEmails {
+			if !containsFold(cur.CommitEmails, e) {
+				cur.CommitEmails = append(cur.CommitEmails, e)
+			}
+		}
+	}
+	return out
+}
+
+// SourceEntity converts the account into a person for the matcher. Emails
+// beyond the profile email are kept as aliases, which is where the
+// commit-email rule and blocking look for them. The orgDomain qualifier is
+// the account's email domain when it is one of the organization's domains;
+// accounts without any email get none, so a display name alone never links
+// them.
+func (a Account) SourceEntity(orgDomains []string) SourceEntity {
+	name := a.DisplayName
+	if name == "" {
+		name = a.AccountID
+	}
+	var aliases []string
+	if a.Login != "" && a.Login != name {
+		aliases = append(aliases, a.Login)
+	}
+	for _, e := range a.CommitEmails {
+		if !strings.EqualFold(e, a.Email) && !containsFold(aliases, e) {
+			aliases = append(aliases, e)
+		}
+	}
+	nodeID := a.NodeID
+	if nodeID == "" {
+		nodeID = AccountNodeID(a.Source, a.AccountID)
+	}
+	source := SourceEntity{
+		Source:     a.Source,
+		ExternalID: a.AccountID,
+		Type:       "person",
+		Name:       name,
+		Email:      a.Email,
+		Aliases:    aliases,
+		URL:        a.URL,
+		NodeID:     nodeID,
+	}
+	if domain := a.orgDomain(orgDomains); domain != "" {
+		source.Qualifiers = map[string]string{"orgDomain": domain}
+	}
+	return source
+}
+
+func (a Account) orgDomain(orgDomains []string) string {
+	emails := append([]string{a.Email}, a.CommitEmails...)
+	for _, e := range emails {
+		if d := emailDomain(e); d != "" && containsFold(orgDomains, d) {
+			return strings.ToLower(d)
+		}
+	}
+	return ""
+}
+
+// IdentityMatchRules returns the rules for linking accounts: an exact
+// profile email, a commit email matching any known address, and the same
+// display name within the same organization domain. Unlike the default
+// person rules a display name alone never links two accounts.
+func IdentityMatchRules() []MatchRule {
+	return []MatchRule{
+		{
+			ID:          "email-exact",
+			Name:        "Email Exact Match",
+			Description: "Match persons by exact email address",
+			Priority:    100,
+			EntityTypes: []string{"person"},
+			Condition:   MatchCondition{ExactFields: []string{"email"}},
+		},
+		{
+			ID:          "commit-email",
+			Name:        "Commit Email Match",
+			Description: "Match a commit or secondary email against any known address of the person",
+			Priority:    95,
+			EntityTypes: []string{"person"},
+			Condition:   MatchCondition{ExactFields: []string{"anyEmail"}},
+		},
+		{
+			ID:          "external-id-source",
+			Name:        "Same Source External ID",
+			Description: "Match by external ID within same source",
+			Priority:    90,
+			EntityTypes: []string{},
+			Condition:   MatchCondition{ExactFields: []string{"source", "externalId"}},
+		},
+		{
+			ID:          "name-org-domain",
+			Name:        "Display Name within Org Domain",
+			Description: "Match by display name when both accounts belong to the same organization domain",
+			Priority:    60,
+			EntityTypes: []string{"person"},
+			Condition: MatchCondition{
+				ExactFields:        []string{"orgDomain"},
+				FuzzyNameThreshold: 0.92,
+			},
+		},
+	}
+}
+
+// IdentityResolver links accounts to canonical persons through
+// Service.ResolveBatch, using IdentityMatchRules and no probabilistic model.
+type IdentityResolver struct {
+	service    *Service
+	orgDomains []string
+}
+
+// NewIdentityResolver creates a resolver over a registry. orgDomains are the
+// organization's email domains, e.g. "acme.com".
+func NewIdentityResolver(registry EntityRegistry, orgDomains ...string) *IdentityResolver {
+	matcher := NewEntityMatcherWithRules(registry, IdentityMatchRules()).WithMatchModels()
+	return &IdentityResolver{service: NewService(registry, matcher), orgDomains: orgDomains}
+}
+
+// IdentityLink is an account and the person it resolved to.
+type IdentityLink struct {
+	Account    Account `json:"account"`
+	PersonID   string  `json:"personId"`
+	PersonName string  `json:"personName"`
+	Created    bool    `json:"created"` // The account started a new person
+}
+
+// IdentityResolution is the outcome of resolving a batch of accounts.
+type IdentityResolution struct {
+	Links  []IdentityLink    `json:"links"`
+	Errors map[string]string `json:"errors"` // Account key -> error
+}
+
+// Resolve links accounts to persons. Accounts with a profile email go first,
+// then those with commit emails, so name-only accounts meet persons that
+// already carry their org domain and aliases.
+func (r *IdentityResolver) Resolve(ctx context.Context, tenantID string, accounts []Account) (*IdentityResolution, error) {
+	accounts = dedupeAccounts(accounts)
+	sort.SliceStable(accounts, func(i, j int) bool {
+		return accountStrength(accounts[i]) > accountStrength(accounts[j])
+	})
+
+	sources := make([]SourceEntity, len(accounts))
+	for i, a := range accounts {
+		sources[i] = a.SourceEntity(r.orgDomains)
+	}
+	resp, err := r.service.ResolveBatch(ctx, &ResolveBatchRequest{TenantID: tenantID, Sources: sources})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &IdentityResolution{Errors: resp.Errors}
+	for _, a := range accounts {
+		person, ok := resp.Results[a.Key()]
+		if !ok || len(person.SourceRefs) == 0 {
+			continue
+		}
+		// A person's first source ref is the account that created it
+		first := person.SourceRefs[0]
+		res.Links = append(res.Links, IdentityLink{
+			Account:    a,
+			PersonID:   person.ID,
+			PersonName: person.Name,
+			Created:    first.Source == a.Source && first.ExternalID == a.AccountID,
+		})
+	}
+	return res, nil
+}
+
+func accountStrength(a Account) int {
+	switch {
+	case a.Email != "":
+		return 2
+	case len(a.CommitEmails) > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ===================================================
+// Identity View
+// ===================================================
+
+// Identity lists every account linked to a person.
+type Identity struct {
+	Person   *CanonicalEntity  `json:"person"`
+	Accounts []IdentityAccount `json:"accounts"`
+	Emails   []string          `json:"emails"` // Profile and commit emails
+}
+
+// IdentityAccount is one linked account.
+type IdentityAccount struct {
+	Source     string    `json:"source"`
+	ExternalID string    `json:"externalId"`
+	NodeID     string    `json:"nodeId"`
+	URL        string    `json:"url"`
+	LastSynced time.Time `json:"lastSynced"`
+}
+
+// BuildIdentity assembles the identity view of a canonical person.
+func BuildIdentity(person *CanonicalEntity) *Identity {
+	id := &Identity{Person: person}
+	for _, ref := range person.SourceRefs {
+		id.Accounts = append(id.Accounts, IdentityAccount{
+			Source:     ref.Source,
+			ExternalID: ref.ExternalID,
+			NodeID:     ref.NodeID,
+			URL:        ref.URL,
+			LastSynced: ref.LastSynced,
+		})
+	}
+	sort.SliceStable(id.Accounts, func(i, j int) bool {
+		if id.Accounts[i].Source != id.Accounts[j].Source {
+			return id.Accounts[i].Source < id.Accounts[j].Source
+		}
+		return id.Accounts[i].ExternalID < id.Accounts[j].ExternalID
+	})
+	for _, e := range entityEmails(person) {
+		if !containsFold(id.Emails, e) {
+			id.Emails = append(id.Emails, strings.ToLower(e))
+		}
+	}
+	return id
+}
+
+// sourceEmails returns the source's profile email and any email aliases.
+func sourceEmails(source SourceEntity) []string {
+	var out []string
+	if source.Email != "" {
+		out = append(out, source.Email)
+	}
+	for _, a := range source.Aliases {
+		if strings.Contains(a, "@") {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return strings.TrimSpace(s)
+}
+
+func firstString(m map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if s := stringField(m, k); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIdentityResolverLinksAccountsAcrossSources(t *testing.T) {
+	ctx := context.Background()
+	reg := newMemRegistry()
+
+	var accounts []Account
+	for _, rec := range []struct {
+		source string
+		rec    map[string]any
+	}{
+		{"github", map[string]any{"author": "jdoe", "authorEmail": "Jane.Doe@acme.com", "committer": "web-flow"}},
+		{"confluence", map[string]any{"pageId": "42", "author": "Jane Doe"}},
+		{"github", map[string]any{"assignees": []any{map[string]any{"login": "janed", "html_url": "https://github.com/janed"}}}},
+		{"jira", map[string]any{"accountId": "5b10", "displayName": "Jane Doe", "emailAddress": "jane.doe@acme.com"}},
+		{"onedrive", map[string]any{"id": "file-1", "createdBy": map[string]any{"user": map[string]any{"id": "g-1", "displayName": "Jane Doe", "email": "jane.doe@acme.com"}}}},
+	} {
+		accounts = append(accounts, AccountsFromRecord(rec.source, rec.rec)...)
+	}
+	if len(accounts) != 6 {
+		t.Fatalf("expected 6 accounts (file IDs are not users), got %+v", accounts)
+	}
+
+	res, err := NewIdentityResolver(reg, "acme.com").Resolve(ctx, "t1", accounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", res.Errors)
+	}
+	// Accounts that start a person: the first profile email, GitHub logins
+	// without an org email, and the Confluence author known only by name.
+	starts := map[string]bool{"jira:5b10": true, "github:janed": true, "github:web-flow": true, "confluence:Jane Doe": true}
+	person := map[string]string{}
+	for _, l := range res.Links {
+		person[l.Account.Key()] = l.PersonID
+		if l.Created != starts[l.Account.Key()] {
+			t.Errorf("%s: created = %v", l.Account.Key(), l.Created)
+		}
+	}
+	jane := person["jira:5b10"]
+	for _, key := range []string{"onedrive:g-1", "github:jdoe"} {
+		if person[key] != jane {
+			t.Errorf("%s resolved to %q, want %q", key, person[key], jane)
+		}
+	}
+	if person["github:janed"] == jane {
+		t.Error("a GitHub login without email or org domain must not link by name")
+	}
+	if person["confluence:Jane Doe"] == jane {
+		t.Error("a Confluence author without email must not link by display name alone")
+	}
+
+	svc := NewService(reg, NewDefaultEntityMatcher(reg))
+	resp, err := svc.GetIdentity(ctx, &GetIdentityRequest{TenantID: "t1", Source: "github", ExternalID: "jdoe"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := resp.Identity
+	if id.Person.ID != jane || len(id.Accounts) != 3 || id.Accounts[0].NodeID != "cdm:work:user:github:jdoe" {
+		t.Fatalf("unexpected identity: %+v", id)
+	}
+	if len(id.Emails) != 1 || id.Emails[0] != "jane.doe@acme.com" {
+		t.Errorf("emails = %v", id.Emails)
+	}
+	if _, err := svc.GetIdentity(ctx, &GetIdentityRequest{TenantID: "t1"}); err == nil {
+		t.Error("expected InvalidArgument without id or account")
+	}
+}
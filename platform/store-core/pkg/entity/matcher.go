@@ -15,16 +15,23 @@ import (
 // ===================================================
 
 // DefaultEntityMatcher implements EntityMatcher with configurable rules.
+// Exact rules (email, source ref) short-circuit; every blocked candidate is
+// also scored by the first MatchModel for its type.
 type DefaultEntityMatcher struct {
 	registry EntityRegistry
 	rules    []MatchRule
+	models   []*MatchModel
 }
 
+// modelMatchFloor drops model scores too weak to be worth reviewing.
+const modelMatchFloor = 0.5
+
 // NewDefaultEntityMatcher creates a new matcher with default rules.
 func NewDefaultEntityMatcher(registry EntityRegistry) *DefaultEntityMatcher {
 	return &DefaultEntityMatcher{
 		registry: registry,
 		rules:    DefaultMatchRules(),
+		models:   DefaultMatchModels(),
 	}
 }
 
@@ -33,9 +40,26 @@ func NewEntityMatcherWithRules(registry EntityRegistry, rules []MatchRule) *Defa
 	return &DefaultEntityMatcher{
 		registry: registry,
 		rules:    rules,
+		models:   DefaultMatchModels(),
 	}
 }
 
+// WithMatchModels replaces the probabilistic models. With none, only rules
+// match.
+func (m *DefaultEntityMatcher) WithMatchModels(models ...*MatchModel) *DefaultEntityMatcher {
+	m.models = models
+	return m
+}
+
+func (m *DefaultEntityMatcher) modelFor(entityType string) *MatchModel {
+	for _, model := range m.models {
+		if model.appliesTo(entityType) {
+			return model
+		}
+	}
+	return nil
+}
+
 // FindMatches finds potential canonical entity matches for a source entity.
 func (m *DefaultEntityMatcher) FindMatches(ctx context.Context, tenantID string, source SourceEntity) ([]MatchResult, error) {
 	var results []MatchResult
@@ -59,7 +83,20 @@ func (m *DefaultEntityMatcher) FindMatches(ctx context.Context, tenantID string,
 	}
 
 	// Apply matching rules in priority order
+	model := m.modelFor(source.Type)
 	for _, candidate := range candidates {
+		if model != nil {
+			score, fields := model.Score(source, candidate)
+			if score >= modelMatchFloor {
+				results = append(results, MatchResult{
+					CanonicalID: candidate.ID,
+					Score:       float32(score),
+					MatchedBy:   "fellegi-sunter",
+					Reason:      fmt.Sprintf("Match probability %.2f: %s", score, explainFields(fields)),
+					Fields:      fields,
+				})
+			}
+		}
 		for _, rule := range m.rules {
 			if !m.ruleApplies(rule, source.Type) {
 				continue
@@ -108,8 +145,15 @@ func (m *DefaultEntityMatcher) ResolveOrCreate(ctx context.Context, tenantID str
 			return nil, false, err
 		}
 
-		// Merge properties
-		existing.Properties = mergeProperties(existing.Properties, source.Properties)
+		// Merge properties, and keep the source's name variants and any
+		// new address so later mentions block and score against them
+		existing.Properties = mergeProperties(existing.Properties, sourceProperties(source))
+		for _, variant := range append([]string{source.Name}, source.Aliases...) {
+			existing.Aliases = appendNameVariant(existing, variant)
+		}
+		if source.Email != "" && !containsFold(entityEmails(existing), source.Email) {
+			existing.Aliases = append(existing.Aliases, source.Email)
+		}
 		existing.UpdatedAt = time.Now()
 		if err := m.registry.Update(ctx, existing); err != nil {
 			return nil, false, err
@@ -126,7 +170,7 @@ func (m *DefaultEntityMatcher) ResolveOrCreate(ctx context.Context, tenantID str
 		Name:     source.Name,
 		Aliases:  source.Aliases,
 		Qualifiers: source.Qualifiers,
-		Properties: source.Properties,
+		Properties: sourceProperties(source),
 		SourceRefs: []SourceRef{
 			{
 				Source:     source.Source,
@@ -147,8 +191,18 @@ func (m *DefaultEntityMatcher) ResolveOrCreate(ctx context.Context, tenantID str
 	return entity, true, nil
 }
 
-// getCandidates retrieves candidate entities to match against.
+// getCandidates retrieves candidate entities to match against, by blocking
+// key when the registry indexes them. When the keys find nothing it falls
+// back to the name-prefix lookup, which still finds entities whose keys
+// have not been backfilled.
 func (m *DefaultEntityMatcher) getCandidates(ctx context.Context, tenantID string, source SourceEntity) ([]*CanonicalEntity, error) {
+	if index, ok := m.registry.(CandidateIndex); ok {
+		candidates, err := index.ListByBlockingKeys(ctx, tenantID, []string{source.Type}, SourceBlockingKeys(source), 100)
+		if err != nil || len(candidates) > 0 {
+			return candidates, err
+		}
+	}
+
 	filter := EntityFilter{
 		Types:    []string{source.Type},
 		NameLike: extractNamePrefix(source.Name),
@@ -160,15 +214,12 @@ func (m *DefaultEntityMatcher) getCandidates(ctx context.Context, tenantID strin
 		return nil, err
 	}
 
-	// Also get by email if present
-	if source.Email != "" {
-		emailFilter := EntityFilter{
-			Types: []string{source.Type},
-			// Would ideally search by email in properties
-		}
-		emailCandidates, err := m.registry.List(ctx, tenantID, emailFilter, 20, 0)
+	// Also get by surname, which the prefix misses for "J. Smith"
+	if surname := lastToken(NormalizeName(source.Name)); surname != "" && surname != strings.ToLower(filter.NameLike) {
+		filter.NameLike = surname
+		more, err := m.registry.List(ctx, tenantID, filter, 100, 0)
 		if err == nil {
-			candidates = append(candidates, emailCandidates...)
+			candidates = append(candidates, more...)
 		}
 	}
 
@@ -238,6 +289,13 @@ func (m *DefaultEntityMatcher) evaluateRule(rule MatchRule, source SourceEntity,
 		if field == "source" || field == "externalId" {
 			continue // Already handled above
 		}
+		if field == "anyEmail" {
+			// Any source address (profile, commit, alias) against any known one
+			if !sharesEmail(sourceEmails(source), entityEmails(candidate)) {
+				return 0, false
+			}
+			continue
+		}
 		sourceVal := getFieldValue(source, field)
 		candidateVal := getEntityFieldValue(candidate, field)
 		if sourceVal == "" || candidateVal == "" {
@@ -255,13 +313,11 @@ func (m *DefaultEntityMatcher) evaluateRule(rule MatchRule, source SourceEntity,
 
 	// Check fuzzy name match
 	if cond.FuzzyNameThreshold > 0 {
-		nameScore := fuzzyNameScore(source.Name, candidate.Name)
-		
-		// Also check aliases
-		for _, alias := range candidate.Aliases {
-			aliasScore := fuzzyNameScore(source.Name, alias)
-			if aliasScore > nameScore {
-				nameScore = aliasScore
+		// Compare against the name and aliases, skipping abbreviated variants
+		var nameScore float32
+		for _, name := range entityNames(candidate) {
+			if score := fuzzyNameScore(source.Name, name); score > nameScore {
+				nameScore = score
 			}
 		}
 
@@ -336,44 +392,49 @@ func extractNamePrefix(name string) string {
 	return name
 }
 
-// fuzzyNameScore calculates similarity between two names.
+// fuzzyNameScore calculates similarity between two names for the fuzzy
+// rules, pairing tokens by Jaro-Winkler and initials (see NameSimilarity).
 func fuzzyNameScore(a, b string) float32 {
-	a = strings.ToLower(strings.TrimSpace(a))
-	b = strings.ToLower(strings.TrimSpace(b))
+	return float32(NameSimilarity(a, b))
+}
 
-	if a == b {
-		return 1.0
+// sourceProperties returns the source properties with its email, so
+// email rules and blocking see it on the canonical entity.
+func sourceProperties(source SourceEntity) map[string]any {
+	props := make(map[string]any, len(source.Properties)+1)
+	for k, v := range source.Properties {
+		props[k] = v
 	}
-
-	// Jaro-Winkler would be ideal here; using simple containment for now
-	if strings.Contains(a, b) || strings.Contains(b, a) {
-		shorter := len(a)
-		longer := len(b)
-		if shorter > longer {
-			shorter, longer = longer, shorter
+	if source.Email != "" {
+		if _, ok := props["email"]; !ok {
+			props["email"] = source.Email
 		}
-		return float32(shorter) / float32(longer)
 	}
+	return props
+}
 
-	// Token-based overlap
-	aTokens := strings.Fields(a)
-	bTokens := strings.Fields(b)
-	
-	matches := 0
-	for _, at := range aTokens {
-		for _, bt := range bTokens {
-			if at == bt {
-				matches++
-				break
-			}
+// appendNameVariant adds name to the entity's aliases unless it normalizes
+// to a name the entity already has.
+func appendNameVariant(e *CanonicalEntity, name string) []string {
+	norm := NormalizeName(name)
+	if norm == "" || norm == NormalizeName(e.Name) {
+		return e.Aliases
+	}
+	for _, a := range e.Aliases {
+		if NormalizeName(a) == norm {
+			return e.Aliases
 		}
 	}
+	return append(e.Aliases, name)
+}
 
-	total := len(aTokens) + len(bTokens)
-	if total == 0 {
-		return 0
+func sharesEmail(a, b []string) bool {
+	for _, e := range a {
+		if containsFold(b, e) {
+			return true
+		}
 	}
-	return float32(matches*2) / float32(total)
+	return false
 }
 
 // getFieldValue extracts a field value from SourceEntity.
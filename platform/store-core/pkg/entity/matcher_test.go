@@ -0,0 +1,246 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+)
+
+// memRegistry is an in-memory EntityRegistry with a blocking-key index.
+type memRegistry struct {
+	mu        sync.Mutex
+	entities  map[string]*CanonicalEntity
+	keyCalls  int
+	unindexed map[string]bool // entities without blocking keys, as before the backfill
+}
+
+func newMemRegistry() *memRegistry {
+	return &memRegistry{entities: map[string]*CanonicalEntity{}}
+}
+
+func (r *memRegistry) Create(_ context.Context, e *CanonicalEntity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entities[e.ID]; ok {
+		return fmt.Errorf("entity exists: %s", e.ID)
+	}
+	r.entities[e.ID] = e
+	return nil
+}
+
+func (r *memRegistry) Get(_ context.Context, tenantID, id string) (*CanonicalEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entities[id]
+	if !ok || e.TenantID != tenantID {
+		return nil, fmt.Errorf("entity not found: %s", id)
+	}
+	return e, nil
+}
+
+func (r *memRegistry) GetBySourceRef(_ context.Context, tenantID, source, externalID string) (*CanonicalEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.entities {
+		for _, ref := range e.SourceRefs {
+			if e.TenantID == tenantID && ref.Source == source && ref.ExternalID == externalID {
+				return e, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (r *memRegistry) Update(_ context.Context, e *CanonicalEntity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entities[e.ID] = e
+	return nil
+}
+
+func (r *memRegistry) Delete(_ context.Context, _, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entities, id)
+	return nil
+}
+
+func (r *memRegistry) List(_ context.Context, tenantID string, filter EntityFilter, limit, _ int) ([]*CanonicalEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*CanonicalEntity
+	for _, e := range r.entities {
+		if e.TenantID == tenantID && (len(filter.Types) == 0 || containsString(filter.Types, e.Type)) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRegistry) AddAlias(_ context.Context, _, id, alias string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entities[id].Aliases = append(r.entities[id].Aliases, alias)
+	return nil
+}
+
+func (r *memRegistry) AddSourceRef(_ context.Context, _, id string, ref SourceRef) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entities[id].SourceRefs = append(r.entities[id].SourceRefs, ref)
+	return nil
+}
+
+func (r *memRegistry) Merge(context.Context, string, string, string) (*CanonicalEntity, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (r *memRegistry) ListByBlockingKeys(_ context.Context, tenantID string, types []string, keys []string, _ int) ([]*CanonicalEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keyCalls++
+	var out []*CanonicalEntity
+	for _, e := range r.entities {
+		if e.TenantID != tenantID || (len(types) > 0 && !containsString(types, e.Type)) || r.unindexed[e.ID] {
+			continue
+		}
+		for _, k := range EntityBlockingKeys(e) {
+			if containsString(keys, k) {
+				out = append(out, e)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestResolveOrCreateLinksNameVariantsAcrossSources(t *testing.T) {
+	ctx := context.Background()
+	reg := newMemRegistry()
+	m := NewDefaultEntityMatcher(reg)
+
+	github, created, err := m.ResolveOrCreate(ctx, "t1", SourceEntity{
+		Source: "github", ExternalID: "gh-1", Type: "person",
+		Name: "John Smith (GitHub)", Email: "john.smith@acme.com",
+	})
+	if err != nil || !created {
+		t.Fatalf("first resolve: created=%v err=%v", created, err)
+	}
+
+	jira, created, err := m.ResolveOrCreate(ctx, "t1", SourceEntity{
+		Source: "jira", ExternalID: "JIRA-7", Type: "person",
+		Name: "J. Smith (Jira)", Email: "jsmith@acme.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || jira.ID != github.ID {
+		t.Fatalf("J. Smith should link to %s, got %s (created=%v)", github.ID, jira.ID, created)
+	}
+	if reg.keyCalls == 0 {
+		t.Error("matcher should use the registry's blocking index")
+	}
+
+	matches, err := m.FindMatches(ctx, "t1", SourceEntity{
+		Source: "slack", ExternalID: "U1", Type: "person",
+		Name: "Jane Smith", Email: "jane.smith@acme.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, match := range matches {
+		if match.Score >= 0.9 {
+			t.Errorf("Jane Smith should not auto-link: %+v", match)
+		}
+	}
+}
+
+func TestResolveOrCreateFindsEntitiesWithoutBlockingKeys(t *testing.T) {
+	ctx := context.Background()
+	reg := newMemRegistry()
+	reg.Create(ctx, &CanonicalEntity{
+		ID: "p1", TenantID: "t1", Type: "person", Name: "John Smith",
+		Properties: map[string]any{"email": "john.smith@acme.com"},
+	})
+	reg.unindexed = map[string]bool{"p1": true}
+
+	e, created, err := NewDefaultEntityMatcher(reg).ResolveOrCreate(ctx, "t1", SourceEntity{
+		Source: "jira", ExternalID: "JIRA-7", Type: "person",
+		Name: "John Smith", Email: "john.smith@acme.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || e.ID != "p1" {
+		t.Fatalf("expected the unindexed entity to be matched, got %s (created=%v)", e.ID, created)
+	}
+}
+
+func TestFindMatchesExplainsModelScore(t *testing.T) {
+	ctx := context.Background()
+	reg := newMemRegistry()
+	reg.Create(ctx, &CanonicalEntity{
+		ID: "p1", TenantID: "t1", Type: "person", Name: "Katherine Johnson",
+		Properties: map[string]any{"email": "kjohnson@nasa.gov"},
+	})
+
+	matches, err := NewDefaultEntityMatcher(reg).FindMatches(ctx, "t1", SourceEntity{
+		Source: "jira", ExternalID: "u9", Type: "person",
+		Name: "Kathryn Jonson", Email: "katherine.johnson@nasa.gov",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model *MatchResult
+	for i := range matches {
+		if matches[i].MatchedBy == "fellegi-sunter" {
+			model = &matches[i]
+		}
+	}
+	if model == nil {
+		t.Fatalf("expected a model match, got %+v", matches)
+	}
+	outcomes := map[string]string{}
+	for _, f := range model.Fields {
+		outcomes[f.Field+"/"+f.Scorer] = f.Outcome
+	}
+	if outcomes["email/exact"] != OutcomeDisagree || outcomes["email.domain/exact"] != OutcomeAgree {
+		t.Errorf("unexpected field outcomes: %v", outcomes)
+	}
+}
+
+func TestScorers(t *testing.T) {
+	if got := JaroWinkler("martha", "marhta"); math.Abs(got-0.961) > 0.001 {
+		t.Errorf("JaroWinkler(martha, marhta) = %.3f, want 0.961", got)
+	}
+	if got := LevenshteinSimilarity("kitten", "sitting"); math.Abs(got-(1-3.0/7)) > 1e-9 {
+		t.Errorf("LevenshteinSimilarity(kitten, sitting) = %.3f", got)
+	}
+	if got := NormalizeName("Smith, John (Jira)"); got != "john smith" {
+		t.Errorf("NormalizeName = %q", got)
+	}
+
+	smithP, smithA := DoubleMetaphone("Smith")
+	schmidtP, schmidtA := DoubleMetaphone("Schmidt")
+	if !(smithP == schmidtP || smithP == schmidtA || smithA == schmidtP || smithA == schmidtA) {
+		t.Errorf("Smith (%s/%s) and Schmidt (%s/%s) should share a code", smithP, smithA, schmidtP, schmidtA)
+	}
+
+	if got := NameSimilarity("J. Smith", "John Smith"); got < 0.85 {
+		t.Errorf("NameSimilarity(J. Smith, John Smith) = %.3f", got)
+	}
+	if got := NameSimilarity("Jane Smith", "John Smith"); got >= 0.85 {
+		t.Errorf("NameSimilarity(Jane Smith, John Smith) = %.3f", got)
+	}
+}
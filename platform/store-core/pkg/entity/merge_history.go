@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ===================================================
+// Merge History
+// Audited, reversible entity merges
+// ===================================================
+
+var (
+	// ErrMergeNotFound is returned when a merge record does not exist.
+	ErrMergeNotFound = errors.New("entity: merge not found")
+	// ErrAlreadyUnmerged is returned when a merge has already been undone.
+	ErrAlreadyUnmerged = errors.New("entity: merge already undone")
+	// ErrUnmergeConflict is returned when the tree has moved on in a way
+	// the merge can't be undone from: the survivor is gone (merged again or
+	// deleted) or the merged ID has been reused.
+	ErrUnmergeConflict = errors.New("entity: merge cannot be undone")
+)
+
+// MergeOptions records who or what triggered a merge.
+type MergeOptions struct {
+	TriggeredBy string  `json:"triggeredBy"` // user ID, or a system actor such as "entity-observer"
+	Rule        string  `json:"rule"`        // match rule or model that proposed the merge
+	Score       float32 `json:"score"`       // match score at merge time
+}
+
+// MergeRecord is one entry of the merge log: full snapshots of both
+// entities as they were before the merge, plus its provenance.
+type MergeRecord struct {
+	ID             string           `json:"id"`
+	TenantID       string           `json:"tenantId"`
+	SurvivorID     string           `json:"survivorId"`
+	MergedID       string           `json:"mergedId"`
+	SurvivorBefore *CanonicalEntity `json:"survivorBefore"`
+	MergedBefore   *CanonicalEntity `json:"mergedBefore"`
+	TriggeredBy    string           `json:"triggeredBy"`
+	Rule           string           `json:"rule"`
+	Score          float32          `json:"score"`
+	MergedAt       time.Time        `json:"mergedAt"`
+	UnmergedAt     *time.Time       `json:"unmergedAt,omitempty"`
+	UnmergedBy     string           `json:"unmergedBy,omitempty"`
+}
+
+// MergeHistory is an optional EntityRegistry extension for audited,
+// reversible merges. PostgresEntityRegistry implements it; its plain Merge
+// is logged too, with no provenance.
+type MergeHistory interface {
+	// MergeWithAudit merges mergedID into survivorID and logs the merge.
+	MergeWithAudit(ctx context.Context, tenantID, survivorID, mergedID string, opts MergeOptions) (*CanonicalEntity, *MergeRecord, error)
+
+	// Unmerge undoes a logged merge, restoring the merged entity and its
+	// source refs. It returns the updated survivor and the restored entity.
+	Unmerge(ctx context.Context, tenantID, mergeID, actor string) (*CanonicalEntity, *CanonicalEntity, error)
+
+	// ListMergeHistory lists merges involving entityID (as survivor or
+	// merged entity), newest first; an empty entityID lists the tenant's.
+	ListMergeHistory(ctx context.Context, tenantID, entityID string, limit int) ([]*MergeRecord, error)
+}
+
+// revertMerge removes from the survivor what the merge contributed, keeping
+// any changes made since. Aliases, qualifiers and properties the survivor
+// already had before the merge are kept even if the merged entity shared
+// them; values that have been changed since the merge are kept as well.
+func revertMerge(current *CanonicalEntity, rec *MergeRecord) {
+	before, merged := rec.SurvivorBefore, rec.MergedBefore
+
+	had := make(map[string]bool, len(before.Aliases)+1)
+	had[before.Name] = true
+	for _, a := range before.Aliases {
+		had[a] = true
+	}
+	added := map[string]bool{}
+	for _, a := range append([]string{merged.Name}, merged.Aliases...) {
+		if !had[a] {
+			added[a] = true
+		}
+	}
+	aliases := current.Aliases[:0:0]
+	for _, a := range current.Aliases {
+		if !added[a] {
+			aliases = append(aliases, a)
+		}
+	}
+	current.Aliases = aliases
+
+	for k, v := range merged.Qualifiers {
+		if _, ok := before.Qualifiers[k]; !ok && current.Qualifiers[k] == v {
+			delete(current.Qualifiers, k)
+		}
+	}
+	for k, v := range merged.Properties {
+		if _, ok := before.Properties[k]; !ok && reflect.DeepEqual(current.Properties[k], v) {
+			delete(current.Properties, k)
+		}
+	}
+
+	hadMerged := make(map[string]bool, len(before.MergedFrom))
+	for _, id := range before.MergedFrom {
+		hadMerged[id] = true
+	}
+	dropped := map[string]bool{rec.MergedID: true}
+	for _, id := range merged.MergedFrom {
+		if !hadMerged[id] {
+			dropped[id] = true
+		}
+	}
+	mergedFrom := current.MergedFrom[:0:0]
+	for _, id := range current.MergedFrom {
+		if !dropped[id] {
+			mergedFrom = append(mergedFrom, id)
+		}
+	}
+	current.MergedFrom = mergedFrom
+}
+
+// sharedSourceRef reports whether the survivor held ref before the merge,
+// in which case the merge deduplicated it rather than moving it.
+func sharedSourceRef(before *CanonicalEntity, ref SourceRef) bool {
+	for _, r := range before.SourceRefs {
+		if r.Source == ref.Source && r.ExternalID == ref.ExternalID {
+			return true
+		}
+	}
+	return false
+}
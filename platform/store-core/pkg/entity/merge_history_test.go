@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRevertMergeKeepsLaterChanges(t *testing.T) {
+	rec := &MergeRecord{
+		SurvivorID: "p1",
+		MergedID:   "p2",
+		SurvivorBefore: &CanonicalEntity{
+			ID: "p1", Name: "John Smith", Aliases: []string{"JS"},
+			Qualifiers: map[string]string{"department": "eng"},
+			Properties: map[string]any{"team": "core"},
+		},
+		MergedBefore: &CanonicalEntity{
+			ID: "p2", Name: "Jon Smith", Aliases: []string{"jsmith", "JS"},
+			Qualifiers: map[string]string{"department": "sales", "location": "NYC"},
+			Properties: map[string]any{"team": "growth", "github": "jsm"},
+			MergedFrom: []string{"p0"},
+		},
+	}
+	// Survivor after the merge, plus an alias and property added later
+	current := &CanonicalEntity{
+		ID: "p1", Name: "John Smith",
+		Aliases:    []string{"JS", "Jon Smith", "jsmith", "Johnny"},
+		Qualifiers: map[string]string{"department": "eng", "location": "NYC"},
+		Properties: map[string]any{"team": "core", "github": "jsm", "slack": "U1"},
+		MergedFrom: []string{"p2", "p0"},
+	}
+
+	revertMerge(current, rec)
+
+	if want := []string{"JS", "Johnny"}; !reflect.DeepEqual(current.Aliases, want) {
+		t.Errorf("aliases = %v, want %v", current.Aliases, want)
+	}
+	if want := map[string]string{"department": "eng"}; !reflect.DeepEqual(current.Qualifiers, want) {
+		t.Errorf("qualifiers = %v, want %v", current.Qualifiers, want)
+	}
+	if want := map[string]any{"team": "core", "slack": "U1"}; !reflect.DeepEqual(current.Properties, want) {
+		t.Errorf("properties = %v, want %v", current.Properties, want)
+	}
+	if len(current.MergedFrom) != 0 {
+		t.Errorf("merged_from = %v, want empty", current.MergedFrom)
+	}
+}
+
+func TestUnmergeRequiresMergeHistory(t *testing.T) {
+	svc := NewService(newMemRegistry(), nil)
+	_, err := svc.UnmergeEntities(context.Background(), &UnmergeEntitiesRequest{TenantID: "t1", MergeID: "m1"})
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected Unimplemented, got %v", err)
+	}
+}
@@ -0,0 +1,289 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ===================================================
+// Probabilistic Match Model
+// Fellegi-Sunter scoring: each compared field adds log2(m/u) evidence when
+// it agrees and log2((1-m)/(1-u)) when it disagrees; the summed weight plus
+// the prior log-odds is turned into a match probability.
+// ===================================================
+
+// FieldComparator compares one field of a source entity with a candidate.
+//
+// M is P(field agrees | same entity) and U is P(field agrees | different
+// entities). Similarities at or above Threshold count as agreement, scaled
+// from half to full weight as they approach 1; below it they disagree.
+type FieldComparator struct {
+	Field     string  `json:"field"`  // email, email.domain, name, or any property/qualifier
+	Scorer    string  `json:"scorer"` // see DefaultScorers
+	M         float64 `json:"m"`
+	U         float64 `json:"u"`
+	Threshold float64 `json:"threshold"`
+}
+
+// MatchModel holds the comparators for one or more entity types.
+type MatchModel struct {
+	EntityTypes []string          `json:"entityTypes"` // empty = all types
+	Prior       float64           `json:"prior"`       // P(match) for a blocked candidate pair
+	Fields      []FieldComparator `json:"fields"`
+
+	scorers map[string]Scorer // custom scorers; built-ins are always available
+}
+
+var builtinScorers = DefaultScorers()
+
+// FieldScore explains one comparator's contribution to a match.
+type FieldScore struct {
+	Field      string  `json:"field"`
+	Scorer     string  `json:"scorer"`
+	Source     string  `json:"source,omitempty"`
+	Candidate  string  `json:"candidate,omitempty"`
+	Similarity float64 `json:"similarity"`
+	Weight     float64 `json:"weight"`  // log2 evidence added
+	Outcome    string  `json:"outcome"` // agree, partial, disagree, missing
+}
+
+// Field outcomes.
+const (
+	OutcomeAgree    = "agree"
+	OutcomePartial  = "partial"
+	OutcomeDisagree = "disagree"
+	OutcomeMissing  = "missing"
+)
+
+// DefaultMatchModels returns a person model and a name-only fallback for
+// other types. Person emails often differ across tools (GitHub noreply
+// addresses), so email disagreement is weak evidence while agreement is
+// nearly conclusive.
+func DefaultMatchModels() []*MatchModel {
+	return []*MatchModel{
+		{
+			EntityTypes: []string{"person"},
+			Prior:       0.05,
+			Fields: []FieldComparator{
+				{Field: "email", Scorer: ScorerExact, M: 0.6, U: 0.0001, Threshold: 1},
+				{Field: "name", Scorer: ScorerPersonName, M: 0.9, U: 0.01, Threshold: 0.85},
+				{Field: "name", Scorer: ScorerDoubleMetaphone, M: 0.9, U: 0.02, Threshold: 0.8},
+				{Field: "email.domain", Scorer: ScorerExact, M: 0.9, U: 0.3, Threshold: 1},
+			},
+		},
+		{
+			Prior: 0.05,
+			Fields: []FieldComparator{
+				{Field: "name", Scorer: ScorerTokenSet, M: 0.9, U: 0.01, Threshold: 0.8},
+				{Field: "name", Scorer: ScorerJaroWinkler, M: 0.9, U: 0.05, Threshold: 0.92},
+			},
+		},
+	}
+}
+
+// WithScorers registers scorers beyond DefaultScorers, or replaces them.
+func (m *MatchModel) WithScorers(scorers map[string]Scorer) *MatchModel {
+	if m.scorers == nil {
+		m.scorers = make(map[string]Scorer, len(scorers))
+	}
+	for name, s := range scorers {
+		m.scorers[name] = s
+	}
+	return m
+}
+
+func (m *MatchModel) scorer(name string) Scorer {
+	if s, ok := m.scorers[name]; ok {
+		return s
+	}
+	return builtinScorers[name]
+}
+
+func (m *MatchModel) appliesTo(entityType string) bool {
+	if len(m.EntityTypes) == 0 {
+		return true
+	}
+	for _, t := range m.EntityTypes {
+		if t == entityType {
+			return true
+		}
+	}
+	return false
+}
+
+// Score returns the match probability of source and candidate with a per
+// field explanation.
+func (m *MatchModel) Score(source SourceEntity, candidate *CanonicalEntity) (float64, []FieldScore) {
+	prior := m.Prior
+	if prior <= 0 || prior >= 1 {
+		prior = 0.05
+	}
+	weight := math.Log2(prior / (1 - prior))
+
+	fields := make([]FieldScore, 0, len(m.Fields))
+	for _, fc := range m.Fields {
+		fs := m.compareField(fc, source, candidate)
+		weight += fs.Weight
+		fields = append(fields, fs)
+	}
+	odds := math.Exp2(weight)
+	return odds / (1 + odds), fields
+}
+
+func (m *MatchModel) compareField(fc FieldComparator, source SourceEntity, candidate *CanonicalEntity) FieldScore {
+	fs := FieldScore{Field: fc.Field, Scorer: fc.Scorer, Outcome: OutcomeMissing}
+	scorer := m.scorer(fc.Scorer)
+	if scorer == nil {
+		return fs
+	}
+	sv := sourceFieldValue(source, fc.Field)
+	cvs := candidateFieldValues(candidate, fc.Field)
+	if sv == "" || len(cvs) == 0 {
+		return fs
+	}
+	fs.Source = sv
+	for _, cv := range cvs {
+		if s := scorer.Similarity(sv, cv); s > fs.Similarity || fs.Candidate == "" {
+			fs.Similarity, fs.Candidate = s, cv
+		}
+	}
+
+	mProb, uProb := clampProb(fc.M), clampProb(fc.U)
+	agree := math.Log2(mProb / uProb)
+	disagree := math.Log2((1 - mProb) / (1 - uProb))
+	threshold := fc.Threshold
+	if threshold <= 0 || threshold > 1 {
+		threshold = 1
+	}
+	switch {
+	case fs.Similarity >= 1:
+		fs.Weight, fs.Outcome = agree, OutcomeAgree
+	case fs.Similarity >= threshold:
+		frac := (fs.Similarity - threshold) / (1 - threshold)
+		fs.Weight, fs.Outcome = agree*(0.5+0.5*frac), OutcomePartial
+	default:
+		fs.Weight, fs.Outcome = disagree, OutcomeDisagree
+	}
+	fs.Similarity = math.Round(fs.Similarity*1000) / 1000
+	fs.Weight = math.Round(fs.Weight*1000) / 1000
+	return fs
+}
+
+func clampProb(p float64) float64 {
+	return math.Min(math.Max(p, 1e-6), 1-1e-6)
+}
+
+// explainFields renders the strongest field evidence for MatchResult.Reason.
+func explainFields(fields []FieldScore) string {
+	var parts []string
+	for _, f := range fields {
+		if f.Outcome == OutcomeMissing {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s/%s %s (%.2f)", f.Field, f.Scorer, f.Outcome, f.Similarity))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sourceFieldValue resolves a comparator field on a source entity.
+func sourceFieldValue(source SourceEntity, field string) string {
+	switch field {
+	case "email.domain":
+		return emailDomain(source.Email)
+	default:
+		return getFieldValue(source, field)
+	}
+}
+
+// candidateFieldValues resolves a comparator field on a candidate. Names
+// include aliases, and emails include any alias that is an address.
+func candidateFieldValues(c *CanonicalEntity, field string) []string {
+	switch field {
+	case "name":
+		return entityNames(c)
+	case "email", "email.domain":
+		var out []string
+		for _, e := range entityEmails(c) {
+			if field == "email.domain" {
+				e = emailDomain(e)
+			}
+			if e != "" {
+				out = append(out, e)
+			}
+		}
+		return out
+	default:
+		if v := getEntityFieldValue(c, field); v != "" {
+			return []string{v}
+		}
+		return nil
+	}
+}
+
+// entityNames returns the entity's name and non-address aliases, minus
+// variants that only abbreviate a fuller one: once "J. Smith" is known to be
+// John Smith, it must not let "Jane Smith" match through the initial.
+func entityNames(c *CanonicalEntity) []string {
+	var all []string
+	for _, n := range append([]string{c.Name}, c.Aliases...) {
+		if n != "" && !strings.Contains(n, "@") {
+			all = append(all, n)
+		}
+	}
+	out := make([]string, 0, len(all))
+	for i, n := range all {
+		abbreviated := false
+		for j, full := range all {
+			if i != j && abbreviates(n, full) {
+				abbreviated = true
+				break
+			}
+		}
+		if !abbreviated {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// abbreviates reports whether short is long with one or more tokens cut to
+// their initial.
+func abbreviates(short, long string) bool {
+	ts, tl := strings.Fields(NormalizeName(short)), strings.Fields(NormalizeName(long))
+	if len(ts) != len(tl) || len(ts) == 0 {
+		return false
+	}
+	shorter := false
+	for i := range ts {
+		switch {
+		case ts[i] == tl[i]:
+		case len([]rune(ts[i])) == 1 && strings.HasPrefix(tl[i], ts[i]):
+			shorter = true
+		default:
+			return false
+		}
+	}
+	return shorter
+}
+
+func entityEmails(c *CanonicalEntity) []string {
+	var out []string
+	if v, ok := c.Properties["email"].(string); ok && v != "" {
+		out = append(out, v)
+	}
+	for _, a := range c.Aliases {
+		if strings.Contains(a, "@") {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 || at == len(email)-1 {
+		return ""
+	}
+	return strings.ToLower(email[at+1:])
+}
@@ -86,6 +86,24 @@ func (r *PostgresEntityRegistry) ensureSchema() error {
 
 	-- GIN index for alias search
 	CREATE INDEX IF NOT EXISTS idx_entities_aliases ON canonical_entities USING gin(aliases);
+
+	-- Merge log: pre-merge snapshots of both entities, for audit and unmerge
+	CREATE TABLE IF NOT EXISTS entity_merge_log (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL,
+		survivor_id TEXT NOT NULL,
+		merged_id TEXT NOT NULL,
+		survivor_snapshot JSONB NOT NULL,
+		merged_snapshot JSONB NOT NULL,
+		triggered_by TEXT NOT NULL DEFAULT '',
+		rule TEXT NOT NULL DEFAULT '',
+		score REAL NOT NULL DEFAULT 0,
+		merged_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		unmerged_at TIMESTAMPTZ,
+		unmerged_by TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX IF NOT EXISTS idx_merge_log_survivor ON entity_merge_log(tenant_id, survivor_id, merged_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_merge_log_merged ON entity_merge_log(tenant_id, merged_id, merged_at DESC);
 	`
 
 	// Execute main schema
@@ -107,6 +125,8 @@ func (r *PostgresEntityRegistry) ensureSchema() error {
 		`ALTER TABLE canonical_entities ADD COLUMN IF NOT EXISTS last_mentioned_at TIMESTAMPTZ`,
 		`ALTER TABLE canonical_entities ADD COLUMN IF NOT EXISTS source_first_seen JSONB DEFAULT '{}'`,
 		`ALTER TABLE canonical_entities ADD COLUMN IF NOT EXISTS source_last_seen JSONB DEFAULT '{}'`,
+		// Candidate blocking keys (see EntityBlockingKeys); backfilled at the end of ensureSchema
+		`ALTER TABLE canonical_entities ADD COLUMN IF NOT EXISTS blocking_keys TEXT[] DEFAULT '{}'`,
 	}
 
 	for _, stmt := range alterStatements {
@@ -143,6 +163,7 @@ func (r *PostgresEntityRegistry) ensureSchema() error {
 		`CREATE INDEX IF NOT EXISTS idx_entities_last_activity ON canonical_entities(tenant_id, last_activity_at)`,
 		`CREATE INDEX IF NOT EXISTS idx_entities_activity_count ON canonical_entities(tenant_id, activity_count)`,
 		`CREATE INDEX IF NOT EXISTS idx_entities_velocity ON canonical_entities(tenant_id, velocity)`,
+		`CREATE INDEX IF NOT EXISTS idx_entities_blocking_keys ON canonical_entities USING gin(blocking_keys)`,
 	}
 
 	for _, stmt := range temporalIndexes {
@@ -152,6 +173,19 @@ func (r *PostgresEntityRegistry) ensureSchema() error {
 		}
 	}
 
+	// Index entities written before blocking_keys existed, which the
+	// matcher could otherwise never find as candidates.
+	ctx := context.Background()
+	for {
+		n, err := r.RefreshBlockingKeys(ctx, "", 500)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+	}
+
 	return nil
 }
 
@@ -176,6 +210,21 @@ func (r *PostgresEntityRegistry) Create(ctx context.Context, entity *CanonicalEn
 		entity.Temporal.LastActivityAt = entity.CreatedAt
 	}
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.insertEntity(ctx, tx, entity); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertEntity inserts an entity row and its source refs within tx.
+func (r *PostgresEntityRegistry) insertEntity(ctx context.Context, tx *sql.Tx, entity *CanonicalEntity) error {
 	qualifiersJSON, err := json.Marshal(entity.Qualifiers)
 	if err != nil {
 		return fmt.Errorf("failed to marshal qualifiers: %w", err)
@@ -205,27 +254,21 @@ func (r *PostgresEntityRegistry) Create(ctx context.Context, entity *CanonicalEn
 		return fmt.Errorf("failed to marshal source_last_seen: %w", err)
 	}
 
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
 	// P2 Fix: Insert entity with ALL temporal columns (including JSONB maps)
 	_, err = tx.ExecContext(ctx, `
 		INSERT INTO canonical_entities 
 		(id, tenant_id, entity_type, name, aliases, qualifiers, properties, merged_from,
 		 first_seen_at, last_seen_at, last_activity_at, activity_count, mention_count, velocity,
 		 last_mentioned_at, source_first_seen, source_last_seen,
-		 created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		 created_at, updated_at, blocking_keys)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 	`, entity.ID, entity.TenantID, entity.Type, entity.Name,
 		pq.Array(entity.Aliases), qualifiersJSON, propertiesJSON,
 		pq.Array(entity.MergedFrom),
 		entity.Temporal.FirstSeenAt, entity.Temporal.LastSeenAt, entity.Temporal.LastActivityAt,
 		entity.Temporal.ActivityCount, entity.Temporal.MentionCount, entity.Temporal.Velocity,
 		entity.Temporal.LastMentionedAt, sourceFirstSeenJSON, sourceLastSeenJSON,
-		entity.CreatedAt, entity.UpdatedAt)
+		entity.CreatedAt, entity.UpdatedAt, pq.Array(EntityBlockingKeys(entity)))
 	if err != nil {
 		return fmt.Errorf("failed to insert entity: %w", err)
 	}
@@ -245,7 +288,7 @@ func (r *PostgresEntityRegistry) Create(ctx context.Context, entity *CanonicalEn
 		}
 	}
 
-	return tx.Commit()
+	return nil
 }
 
 // Get retrieves an entity by canonical ID.
@@ -385,14 +428,15 @@ func (r *PostgresEntityRegistry) Update(ctx context.Context, entity *CanonicalEn
 		    last_mentioned_at = COALESCE($12, last_mentioned_at),
 		    source_first_seen = CASE WHEN $13::jsonb = '{}'::jsonb THEN source_first_seen ELSE $13 END,
 		    source_last_seen = CASE WHEN $14::jsonb = '{}'::jsonb THEN source_last_seen ELSE $14 END,
-		    updated_at = $15
+		    updated_at = $15,
+		    blocking_keys = $18
 		WHERE id = $16 AND tenant_id = $17
 	`, entity.Type, entity.Name, pq.Array(entity.Aliases), qualifiersJSON,
 		propertiesJSON, pq.Array(entity.MergedFrom),
 		entity.Temporal.LastSeenAt, entity.Temporal.LastActivityAt,
 		entity.Temporal.ActivityCount, entity.Temporal.MentionCount, entity.Temporal.Velocity,
 		entity.Temporal.LastMentionedAt, sourceFirstSeenJSON, sourceLastSeenJSON,
-		entity.UpdatedAt, entity.ID, entity.TenantID)
+		entity.UpdatedAt, entity.ID, entity.TenantID, pq.Array(EntityBlockingKeys(entity)))
 	if err != nil {
 		return fmt.Errorf("failed to update entity: %w", err)
 	}
@@ -598,6 +642,21 @@ func (r *PostgresEntityRegistry) AddAlias(ctx context.Context, tenantID, id, ali
 			return fmt.Errorf("entity not found: %s", id)
 		}
 		// Alias already exists, which is fine
+		return nil
+	}
+
+	// Index the new alias for candidate blocking
+	var aliasEmails []string
+	if strings.Contains(alias, "@") {
+		aliasEmails = []string{alias}
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE canonical_entities
+		SET blocking_keys = ARRAY(SELECT DISTINCT unnest(COALESCE(blocking_keys, '{}') || $1::text[]))
+		WHERE id = $2 AND tenant_id = $3
+	`, pq.Array(blockingKeys([]string{alias}, aliasEmails)), id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to index alias: %w", err)
 	}
 
 	return nil
@@ -639,29 +698,47 @@ func (r *PostgresEntityRegistry) AddSourceRef(ctx context.Context, tenantID, id
 	return nil
 }
 
-// Merge merges two entities, returning the surviving entity.
+// Merge merges two entities, returning the surviving entity. The merge is
+// logged without provenance; use MergeWithAudit to record who triggered it.
 func (r *PostgresEntityRegistry) Merge(ctx context.Context, tenantID, survivorID, mergedID string) (*CanonicalEntity, error) {
+	survivor, _, err := r.MergeWithAudit(ctx, tenantID, survivorID, mergedID, MergeOptions{})
+	return survivor, err
+}
+
+// MergeWithAudit merges mergedID into survivorID, logging pre-merge
+// snapshots of both entities in the same transaction.
+func (r *PostgresEntityRegistry) MergeWithAudit(ctx context.Context, tenantID, survivorID, mergedID string, opts MergeOptions) (*CanonicalEntity, *MergeRecord, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
 	// P1 Fix: Get both entities with FOR UPDATE to prevent concurrent modifications
 	survivor, err := r.getEntityForUpdate(ctx, tx, tenantID, survivorID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get survivor: %w", err)
+		return nil, nil, fmt.Errorf("failed to get survivor: %w", err)
 	}
 	if survivor == nil {
-		return nil, fmt.Errorf("survivor entity not found: %s", survivorID)
+		return nil, nil, fmt.Errorf("survivor entity not found: %s", survivorID)
 	}
 
 	merged, err := r.getEntityForUpdate(ctx, tx, tenantID, mergedID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get merged: %w", err)
+		return nil, nil, fmt.Errorf("failed to get merged: %w", err)
 	}
 	if merged == nil {
-		return nil, fmt.Errorf("merged entity not found: %s", mergedID)
+		return nil, nil, fmt.Errorf("merged entity not found: %s", mergedID)
+	}
+
+	// Snapshot both entities before the survivor is modified
+	survivorSnapshot, err := json.Marshal(survivor)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to snapshot survivor: %w", err)
+	}
+	mergedSnapshot, err := json.Marshal(merged)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to snapshot merged: %w", err)
 	}
 
 	// Merge aliases
@@ -717,7 +794,7 @@ func (r *PostgresEntityRegistry) Merge(ctx context.Context, tenantID, survivorID
 		)
 	`, mergedID, survivorID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to deduplicate source refs: %w", err)
+		return nil, nil, fmt.Errorf("failed to deduplicate source refs: %w", err)
 	}
 	
 	// Now move remaining source refs to survivor
@@ -725,49 +802,339 @@ func (r *PostgresEntityRegistry) Merge(ctx context.Context, tenantID, survivorID
 		UPDATE entity_source_refs SET entity_id = $1 WHERE entity_id = $2
 	`, survivorID, mergedID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to move source refs: %w", err)
+		return nil, nil, fmt.Errorf("failed to move source refs: %w", err)
 	}
 
 	// Update survivor
 	// P2 Fix: Handle marshal errors to prevent data corruption
 	qualifiersJSON, err := json.Marshal(survivor.Qualifiers)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal qualifiers: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal qualifiers: %w", err)
 	}
 	propertiesJSON, err := json.Marshal(survivor.Properties)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal properties: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal properties: %w", err)
 	}
 	survivor.UpdatedAt = time.Now()
 
 	_, err = tx.ExecContext(ctx, `
 		UPDATE canonical_entities 
-		SET aliases = $1, qualifiers = $2, properties = $3, merged_from = $4, updated_at = $5
+		SET aliases = $1, qualifiers = $2, properties = $3, merged_from = $4, updated_at = $5,
+		    blocking_keys = $8
 		WHERE id = $6 AND tenant_id = $7
 	`, pq.Array(survivor.Aliases), qualifiersJSON, propertiesJSON,
-		pq.Array(survivor.MergedFrom), survivor.UpdatedAt, survivorID, tenantID)
+		pq.Array(survivor.MergedFrom), survivor.UpdatedAt, survivorID, tenantID,
+		pq.Array(EntityBlockingKeys(survivor)))
 	if err != nil {
-		return nil, fmt.Errorf("failed to update survivor: %w", err)
+		return nil, nil, fmt.Errorf("failed to update survivor: %w", err)
 	}
 
 	// Delete merged entity
 	_, err = tx.ExecContext(ctx, "DELETE FROM canonical_entities WHERE id = $1 AND tenant_id = $2", mergedID, tenantID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to delete merged entity: %w", err)
+		return nil, nil, fmt.Errorf("failed to delete merged entity: %w", err)
+	}
+
+	record := &MergeRecord{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		SurvivorID:  survivorID,
+		MergedID:    mergedID,
+		TriggeredBy: opts.TriggeredBy,
+		Rule:        opts.Rule,
+		Score:       opts.Score,
+		MergedAt:    survivor.UpdatedAt,
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO entity_merge_log
+		(id, tenant_id, survivor_id, merged_id, survivor_snapshot, merged_snapshot,
+		 triggered_by, rule, score, merged_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, record.ID, tenantID, survivorID, mergedID, survivorSnapshot, mergedSnapshot,
+		record.TriggeredBy, record.Rule, record.Score, record.MergedAt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to log merge: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit merge: %w", err)
+		return nil, nil, fmt.Errorf("failed to commit merge: %w", err)
 	}
 
 	// Refresh source refs - P3 Fix: Handle error
 	sourceRefs, err := r.getSourceRefs(ctx, survivorID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to refresh source refs: %w", err)
+		return nil, nil, fmt.Errorf("failed to refresh source refs: %w", err)
 	}
 	survivor.SourceRefs = sourceRefs
 
-	return survivor, nil
+	// Decode snapshots back so callers get independent copies
+	if err := json.Unmarshal(survivorSnapshot, &record.SurvivorBefore); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode survivor snapshot: %w", err)
+	}
+	if err := json.Unmarshal(mergedSnapshot, &record.MergedBefore); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode merged snapshot: %w", err)
+	}
+
+	return survivor, record, nil
+}
+
+// ListByBlockingKeys returns entities of the given types sharing at least
+// one blocking key, via the GIN index on blocking_keys.
+func (r *PostgresEntityRegistry) ListByBlockingKeys(ctx context.Context, tenantID string, types []string, keys []string, limit int) ([]*CanonicalEntity, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := `SELECT id FROM canonical_entities WHERE tenant_id = $1 AND blocking_keys && $2::text[]`
+	args := []any{tenantID, pq.Array(keys)}
+	if len(types) > 0 {
+		query += ` AND entity_type = ANY($3)`
+		args = append(args, pq.Array(types))
+	}
+	query += fmt.Sprintf(` ORDER BY updated_at DESC LIMIT %d`, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list by blocking keys: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan entity id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list by blocking keys: %w", err)
+	}
+
+	entities := make([]*CanonicalEntity, 0, len(ids))
+	for _, id := range ids {
+		e, err := r.Get(ctx, tenantID, id)
+		if err != nil {
+			continue // deleted or merged since the lookup
+		}
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+// RefreshBlockingKeys computes blocking keys for up to batch entities of a
+// tenant that have none (rows written before the column existed), and
+// returns how many were updated. An empty tenantID covers every tenant.
+// Call it until it returns 0; ensureSchema does so at startup.
+func (r *PostgresEntityRegistry) RefreshBlockingKeys(ctx context.Context, tenantID string, batch int) (int, error) {
+	if batch <= 0 {
+		batch = 500
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, tenant_id FROM canonical_entities
+		WHERE ($1 = '' OR tenant_id = $1) AND COALESCE(cardinality(blocking_keys), 0) = 0
+		LIMIT $2
+	`, tenantID, batch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find unindexed entities: %w", err)
+	}
+	type entityRef struct{ id, tenantID string }
+	var refs []entityRef
+	for rows.Next() {
+		var ref entityRef
+		if err := rows.Scan(&ref.id, &ref.tenantID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan entity id: %w", err)
+		}
+		refs = append(refs, ref)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("failed to list unindexed entities: %w", err)
+	}
+	rows.Close()
+
+	updated := 0
+	for _, ref := range refs {
+		keys := []string{blockName}
+		if e, err := r.Get(ctx, ref.tenantID, ref.id); err == nil {
+			if k := EntityBlockingKeys(e); len(k) > 0 {
+				keys = k
+			}
+		}
+		// Rows with nothing to index (or that cannot be loaded) get the
+		// sentinel, which keeps them from being revisited.
+		if _, err := r.db.ExecContext(ctx, `
+			UPDATE canonical_entities SET blocking_keys = $1 WHERE id = $2 AND tenant_id = $3
+		`, pq.Array(keys), ref.id, ref.tenantID); err != nil {
+			return updated, fmt.Errorf("failed to update blocking keys: %w", err)
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// Unmerge undoes a logged merge: the merged entity is re-created from its
+// snapshot, its source refs are re-pointed to it, and what it contributed
+// to the survivor is removed. Survivor changes made since the merge stay.
+func (r *PostgresEntityRegistry) Unmerge(ctx context.Context, tenantID, mergeID, actor string) (*CanonicalEntity, *CanonicalEntity, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	record, err := scanMergeRecord(tx.QueryRowContext(ctx, mergeRecordSelect+`
+		WHERE id = $1 AND tenant_id = $2
+		FOR UPDATE
+	`, mergeID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrMergeNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if record.UnmergedAt != nil {
+		return nil, nil, ErrAlreadyUnmerged
+	}
+
+	survivor, err := r.getEntityForUpdate(ctx, tx, tenantID, record.SurvivorID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get survivor: %w", err)
+	}
+	if survivor == nil {
+		return nil, nil, fmt.Errorf("%w: survivor %s no longer exists", ErrUnmergeConflict, record.SurvivorID)
+	}
+	var taken bool
+	err = tx.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM canonical_entities WHERE id = $1)",
+		record.MergedID).Scan(&taken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check merged entity: %w", err)
+	}
+	if taken {
+		return nil, nil, fmt.Errorf("%w: entity %s exists again", ErrUnmergeConflict, record.MergedID)
+	}
+
+	// Take back the source refs the merge moved; refs both entities held
+	// were deduplicated and stay with the survivor as well
+	for _, ref := range record.MergedBefore.SourceRefs {
+		if sharedSourceRef(record.SurvivorBefore, ref) {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM entity_source_refs WHERE entity_id = $1 AND source = $2 AND external_id = $3
+		`, record.SurvivorID, ref.Source, ref.ExternalID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to move source ref back: %w", err)
+		}
+	}
+
+	restored := record.MergedBefore
+	restored.UpdatedAt = time.Now()
+	if err := r.insertEntity(ctx, tx, restored); err != nil {
+		return nil, nil, fmt.Errorf("failed to restore merged entity: %w", err)
+	}
+
+	revertMerge(survivor, record)
+	qualifiersJSON, err := json.Marshal(survivor.Qualifiers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal qualifiers: %w", err)
+	}
+	propertiesJSON, err := json.Marshal(survivor.Properties)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal properties: %w", err)
+	}
+	survivor.UpdatedAt = restored.UpdatedAt
+	_, err = tx.ExecContext(ctx, `
+		UPDATE canonical_entities
+		SET aliases = $1, qualifiers = $2, properties = $3, merged_from = $4, updated_at = $5,
+		    blocking_keys = $8
+		WHERE id = $6 AND tenant_id = $7
+	`, pq.Array(survivor.Aliases), qualifiersJSON, propertiesJSON,
+		pq.Array(survivor.MergedFrom), survivor.UpdatedAt, survivor.ID, tenantID,
+		pq.Array(EntityBlockingKeys(survivor)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to update survivor: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE entity_merge_log SET unmerged_at = $1, unmerged_by = $2 WHERE id = $3
+	`, restored.UpdatedAt, actor, mergeID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to mark merge undone: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit unmerge: %w", err)
+	}
+
+	if survivor.SourceRefs, err = r.getSourceRefs(ctx, survivor.ID); err != nil {
+		return nil, nil, fmt.Errorf("failed to refresh source refs: %w", err)
+	}
+	return survivor, restored, nil
+}
+
+// ListMergeHistory lists merges involving entityID, newest first.
+func (r *PostgresEntityRegistry) ListMergeHistory(ctx context.Context, tenantID, entityID string, limit int) ([]*MergeRecord, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := mergeRecordSelect + ` WHERE tenant_id = $1`
+	args := []any{tenantID}
+	if entityID != "" {
+		query += ` AND (survivor_id = $2 OR merged_id = $2)`
+		args = append(args, entityID)
+	}
+	query += fmt.Sprintf(` ORDER BY merged_at DESC LIMIT %d`, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge history: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*MergeRecord
+	for rows.Next() {
+		record, err := scanMergeRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+const mergeRecordSelect = `
+	SELECT id, tenant_id, survivor_id, merged_id, survivor_snapshot, merged_snapshot,
+	       triggered_by, rule, score, merged_at, unmerged_at, unmerged_by
+	FROM entity_merge_log`
+
+// scanMergeRecord scans a mergeRecordSelect row. sql.ErrNoRows is returned
+// unwrapped.
+func scanMergeRecord(row interface{ Scan(...any) error }) (*MergeRecord, error) {
+	record := &MergeRecord{}
+	var survivorJSON, mergedJSON []byte
+	err := row.Scan(&record.ID, &record.TenantID, &record.SurvivorID, &record.MergedID,
+		&survivorJSON, &mergedJSON, &record.TriggeredBy, &record.Rule, &record.Score,
+		&record.MergedAt, &record.UnmergedAt, &record.UnmergedBy)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan merge record: %w", err)
+	}
+	if err := json.Unmarshal(survivorJSON, &record.SurvivorBefore); err != nil {
+		return nil, fmt.Errorf("failed to decode survivor snapshot: %w", err)
+	}
+	if err := json.Unmarshal(mergedJSON, &record.MergedBefore); err != nil {
+		return nil, fmt.Errorf("failed to decode merged snapshot: %w", err)
+	}
+	return record, nil
 }
 
 // getSourceRefs retrieves source refs for an entity.
@@ -861,3 +1228,5 @@ func (r *PostgresEntityRegistry) getEntityForUpdate(ctx context.Context, tx *sql
 
 // Ensure interface compliance
 var _ EntityRegistry = (*PostgresEntityRegistry)(nil)
+var _ CandidateIndex = (*PostgresEntityRegistry)(nil)
+var _ MergeHistory = (*PostgresEntityRegistry)(nil)
@@ -2,7 +2,9 @@ package entity
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc/codes"
@@ -83,14 +85,43 @@ type FindMatchesResponse struct {
 
 // MergeEntitiesRequest for merging entities.
 type MergeEntitiesRequest struct {
-	TenantID   string `json:"tenantId"`
-	SurvivorID string `json:"survivorId"`
-	MergedID   string `json:"mergedId"`
+	TenantID    string  `json:"tenantId"`
+	SurvivorID  string  `json:"survivorId"`
+	MergedID    string  `json:"mergedId"`
+	TriggeredBy string  `json:"triggeredBy"` // User or system actor, recorded in the merge log
+	Rule        string  `json:"rule"`        // Match rule that proposed the merge
+	Score       float32 `json:"score"`       // Match score
 }
 
 // MergeEntitiesResponse for merge result.
 type MergeEntitiesResponse struct {
-	Entity *CanonicalEntity `json:"entity"`
+	Entity  *CanonicalEntity `json:"entity"`
+	MergeID string           `json:"mergeId"` // Merge log entry, for UnmergeEntities
+}
+
+// UnmergeEntitiesRequest for undoing a merge.
+type UnmergeEntitiesRequest struct {
+	TenantID string `json:"tenantId"`
+	MergeID  string `json:"mergeId"`
+	Actor    string `json:"actor"` // Who is undoing the merge
+}
+
+// UnmergeEntitiesResponse returns both entities after the unmerge.
+type UnmergeEntitiesResponse struct {
+	Survivor *CanonicalEntity `json:"survivor"`
+	Restored *CanonicalEntity `json:"restored"`
+}
+
+// ListMergeHistoryRequest for querying the merge log.
+type ListMergeHistoryRequest struct {
+	TenantID string `json:"tenantId"`
+	EntityID string `json:"entityId"` // Optional: merges where the entity was survivor or merged
+	Limit    int    `json:"limit"`
+}
+
+// ListMergeHistoryResponse lists merge log entries, newest first.
+type ListMergeHistoryResponse struct {
+	Merges []*MergeRecord `json:"merges"`
 }
 
 // AddAliasRequest for adding an alias.
@@ -107,6 +138,20 @@ type GetBySourceRefRequest struct {
 	ExternalID string `json:"externalId"`
 }
 
+// GetIdentityRequest looks up a person by canonical ID or by any of their
+// accounts.
+type GetIdentityRequest struct {
+	TenantID   string `json:"tenantId"`
+	ID         string `json:"id"`     // Canonical person ID, or
+	Source     string `json:"source"` // source + external ID of one account
+	ExternalID string `json:"externalId"`
+}
+
+// GetIdentityResponse lists all accounts of a person.
+type GetIdentityResponse struct {
+	Identity *Identity `json:"identity"`
+}
+
 // P1 Fix: Add AddSourceRef request type
 type AddSourceRefRequest struct {
 	TenantID  string    `json:"tenantId"`
@@ -142,6 +187,20 @@ type ListEntitiesResponse struct {
 	Total    int                `json:"total"`
 }
 
+// ListPoliciesRequest for browsing the policy catalogue.
+type ListPoliciesRequest struct {
+	TenantID    string `json:"tenantId"`
+	NameLike    string `json:"nameLike"`
+	Enforcement string `json:"enforcement"` // Optional: mandatory, recommended, optional
+	Limit       int    `json:"limit"`
+	Offset      int    `json:"offset"`
+}
+
+// ListPoliciesResponse lists policies with their rules.
+type ListPoliciesResponse struct {
+	Policies []*PolicyEntity `json:"policies"`
+}
+
 // ResolveBatchRequest for batch entity resolution.
 type ResolveBatchRequest struct {
 	TenantID string         `json:"tenantId"`
@@ -258,12 +317,77 @@ func (s *Service) MergeEntities(ctx context.Context, req *MergeEntitiesRequest)
 		return nil, status.Error(codes.InvalidArgument, "cannot merge entity with itself")
 	}
 
-	entity, err := s.registry.Merge(ctx, req.TenantID, req.SurvivorID, req.MergedID)
+	history, ok := s.registry.(MergeHistory)
+	if !ok {
+		entity, err := s.registry.Merge(ctx, req.TenantID, req.SurvivorID, req.MergedID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to merge entities: %v", err)
+		}
+		return &MergeEntitiesResponse{Entity: entity}, nil
+	}
+
+	entity, record, err := history.MergeWithAudit(ctx, req.TenantID, req.SurvivorID, req.MergedID, MergeOptions{
+		TriggeredBy: req.TriggeredBy,
+		Rule:        req.Rule,
+		Score:       req.Score,
+	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to merge entities: %v", err)
 	}
 
-	return &MergeEntitiesResponse{Entity: entity}, nil
+	return &MergeEntitiesResponse{Entity: entity, MergeID: record.ID}, nil
+}
+
+// UnmergeEntities undoes a logged merge, restoring the merged entity.
+func (s *Service) UnmergeEntities(ctx context.Context, req *UnmergeEntitiesRequest) (*UnmergeEntitiesResponse, error) {
+	if req.TenantID == "" {
+		return nil, status.Error(codes.InvalidArgument, "tenant_id is required")
+	}
+	if req.MergeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "merge_id is required")
+	}
+	history, ok := s.registry.(MergeHistory)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "registry does not keep merge history")
+	}
+
+	survivor, restored, err := history.Unmerge(ctx, req.TenantID, req.MergeID, req.Actor)
+	switch {
+	case errors.Is(err, ErrMergeNotFound):
+		return nil, status.Errorf(codes.NotFound, "merge not found: %s", req.MergeID)
+	case errors.Is(err, ErrAlreadyUnmerged), errors.Is(err, ErrUnmergeConflict):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to unmerge entities: %v", err)
+	}
+
+	return &UnmergeEntitiesResponse{Survivor: survivor, Restored: restored}, nil
+}
+
+// ListMergeHistory lists merge log entries for a tenant or entity.
+func (s *Service) ListMergeHistory(ctx context.Context, req *ListMergeHistoryRequest) (*ListMergeHistoryResponse, error) {
+	if req.TenantID == "" {
+		return nil, status.Error(codes.InvalidArgument, "tenant_id is required")
+	}
+	history, ok := s.registry.(MergeHistory)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "registry does not keep merge history")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	merges, err := history.ListMergeHistory(ctx, req.TenantID, req.EntityID, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list merge history: %v", err)
+	}
+
+	return &ListMergeHistoryResponse{Merges: merges}, nil
 }
 
 // AddAlias adds an alias to an entity.
@@ -305,6 +429,36 @@ func (s *Service) GetBySourceRef(ctx context.Context, req *GetBySourceRefRequest
 	return &GetEntityResponse{Entity: entity}, nil
 }
 
+// GetIdentity returns a person with every linked account, for ownership and
+// "who worked on what" queries that start from any one account.
+func (s *Service) GetIdentity(ctx context.Context, req *GetIdentityRequest) (*GetIdentityResponse, error) {
+	if req.TenantID == "" {
+		return nil, status.Error(codes.InvalidArgument, "tenant_id is required")
+	}
+
+	var person *CanonicalEntity
+	var err error
+	switch {
+	case req.ID != "":
+		person, err = s.registry.Get(ctx, req.TenantID, req.ID)
+	case req.Source != "" && req.ExternalID != "":
+		person, err = s.registry.GetBySourceRef(ctx, req.TenantID, req.Source, req.ExternalID)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "id or source and external_id are required")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "entity not found: %v", err)
+	}
+	if person == nil {
+		return nil, status.Error(codes.NotFound, "entity not found")
+	}
+	if person.Type != "person" {
+		return nil, status.Errorf(codes.FailedPrecondition, "entity %s is a %s, not a person", person.ID, person.Type)
+	}
+
+	return &GetIdentityResponse{Identity: BuildIdentity(person)}, nil
+}
+
 // P1 Fix: AddSourceRef links a source entity to a canonical entity.
 func (s *Service) AddSourceRef(ctx context.Context, req *AddSourceRefRequest) error {
 	if req.TenantID == "" {
@@ -368,6 +522,41 @@ func (s *Service) ListEntities(ctx context.Context, req *ListEntitiesRequest) (*
 	}, nil
 }
 
+// ListPolicies lists the policies extracted from documents, with their
+// rules, for the policy catalogue.
+func (s *Service) ListPolicies(ctx context.Context, req *ListPoliciesRequest) (*ListPoliciesResponse, error) {
+	if req.TenantID == "" {
+		return nil, status.Error(codes.InvalidArgument, "tenant_id is required")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	filter := EntityFilter{Types: []string{string(EntityTypePolicy)}, NameLike: req.NameLike}
+	entities, err := s.registry.List(ctx, req.TenantID, filter, limit, req.Offset)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list policies: %v", err)
+	}
+
+	resp := &ListPoliciesResponse{}
+	for _, e := range entities {
+		p, err := PolicyFromCanonical(e)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		if req.Enforcement != "" && !strings.EqualFold(p.Enforcement, req.Enforcement) {
+			continue
+		}
+		resp.Policies = append(resp.Policies, p)
+	}
+	return resp, nil
+}
+
 // ===================================================
 // Batch Operations
 // ===================================================
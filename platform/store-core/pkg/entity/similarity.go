@@ -0,0 +1,569 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ===================================================
+// String Similarity Scorers
+// Pluggable field comparators for the match model
+// ===================================================
+
+// Scorer compares two field values, returning a similarity in [0, 1].
+type Scorer interface {
+	Name() string
+	Similarity(a, b string) float64
+}
+
+// Built-in scorer names, usable in FieldComparator.Scorer.
+const (
+	ScorerExact           = "exact"
+	ScorerJaroWinkler     = "jaro_winkler"
+	ScorerLevenshtein     = "levenshtein"
+	ScorerDoubleMetaphone = "double_metaphone"
+	ScorerTokenSet        = "token_set"
+	ScorerPersonName      = "person_name"
+)
+
+// DefaultScorers returns the built-in scorers keyed by name.
+func DefaultScorers() map[string]Scorer {
+	scorers := map[string]Scorer{}
+	for _, s := range []Scorer{
+		exactScorer{}, jaroWinklerScorer{}, levenshteinScorer{},
+		doubleMetaphoneScorer{}, tokenSetScorer{}, personNameScorer{},
+	} {
+		scorers[s.Name()] = s
+	}
+	return scorers
+}
+
+type exactScorer struct{}
+
+func (exactScorer) Name() string { return ScorerExact }
+func (exactScorer) Similarity(a, b string) float64 {
+	if strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b)) {
+		return 1
+	}
+	return 0
+}
+
+type jaroWinklerScorer struct{}
+
+func (jaroWinklerScorer) Name() string { return ScorerJaroWinkler }
+func (jaroWinklerScorer) Similarity(a, b string) float64 {
+	return JaroWinkler(NormalizeName(a), NormalizeName(b))
+}
+
+type levenshteinScorer struct{}
+
+func (levenshteinScorer) Name() string { return ScorerLevenshtein }
+func (levenshteinScorer) Similarity(a, b string) float64 {
+	return LevenshteinSimilarity(NormalizeName(a), NormalizeName(b))
+}
+
+// doubleMetaphoneScorer compares the phonetic codes of each name's last
+// token (the surname for people): 1 when primary codes agree, 0.8 when only
+// an alternate code does.
+type doubleMetaphoneScorer struct{}
+
+func (doubleMetaphoneScorer) Name() string { return ScorerDoubleMetaphone }
+func (doubleMetaphoneScorer) Similarity(a, b string) float64 {
+	pa, aa := DoubleMetaphone(lastToken(NormalizeName(a)))
+	pb, ab := DoubleMetaphone(lastToken(NormalizeName(b)))
+	switch {
+	case pa == "" || pb == "":
+		return 0
+	case pa == pb:
+		return 1
+	case pa == ab || aa == pb || (aa != "" && aa == ab):
+		return 0.8
+	}
+	return 0
+}
+
+type tokenSetScorer struct{}
+
+func (tokenSetScorer) Name() string { return ScorerTokenSet }
+func (tokenSetScorer) Similarity(a, b string) float64 {
+	return TokenSetSimilarity(NormalizeName(a), NormalizeName(b))
+}
+
+type personNameScorer struct{}
+
+func (personNameScorer) Name() string { return ScorerPersonName }
+func (personNameScorer) Similarity(a, b string) float64 {
+	return NameSimilarity(a, b)
+}
+
+// ===================================================
+// Normalization
+// ===================================================
+
+var (
+	parenthetical  = regexp.MustCompile(`\([^)]*\)|\[[^\]]*\]|<[^>]*>`)
+	namePunct      = regexp.MustCompile(`[._\-/\\,'"]+`)
+	nameAffixWords = map[string]bool{
+		"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true,
+		"jr": true, "sr": true, "ii": true, "iii": true, "phd": true,
+	}
+)
+
+// NormalizeName lowercases a display name and strips what sources decorate
+// it with: parenthesized source tags ("J. Smith (Jira)"), bracketed emails,
+// punctuation, titles and suffixes. "Smith, John" becomes "john smith".
+func NormalizeName(name string) string {
+	name = parenthetical.ReplaceAllString(name, " ")
+	if parts := strings.Split(name, ","); len(parts) == 2 && strings.TrimSpace(parts[0]) != "" && strings.TrimSpace(parts[1]) != "" &&
+		len(strings.Fields(parts[0])) == 1 {
+		name = parts[1] + " " + parts[0]
+	}
+	name = namePunct.ReplaceAllString(strings.ToLower(name), " ")
+	var tokens []string
+	for _, t := range strings.Fields(name) {
+		if !nameAffixWords[t] {
+			tokens = append(tokens, t)
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+func lastToken(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// ===================================================
+// Similarity functions
+// ===================================================
+
+// JaroWinkler returns the Jaro-Winkler similarity of a and b.
+func JaroWinkler(a, b string) float64 {
+	if a == b {
+		if a == "" {
+			return 0
+		}
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+	transpositions, j := 0, 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions/2))/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// LevenshteinSimilarity is 1 - edit distance / longer length.
+func LevenshteinSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longer := max(len(ra), len(rb))
+	if longer == 0 {
+		return 0
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(longer)
+}
+
+// TokenSetSimilarity is the Dice coefficient of the two token sets, so word
+// order and repeated words do not matter.
+func TokenSetSimilarity(a, b string) float64 {
+	ta, tb := tokenSet(a), tokenSet(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ta)+len(tb))
+}
+
+func tokenSet(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, t := range strings.Fields(s) {
+		set[t] = true
+	}
+	return set
+}
+
+// initialMatch scores an initial against a full token ("j" vs "john").
+const initialMatch = 0.9
+
+// NameSimilarity compares names token by token after normalization. Each
+// token of the shorter name is paired with its best unused counterpart: an
+// initial pairs with any token it starts, other tokens score Jaro-Winkler.
+// The score is the product of the pairings, so one clearly different token
+// ("Jane" vs "John") sinks it. Each unpaired extra token costs 5% when it
+// is an initial (a middle initial) and 20% otherwise, so "Billing" stays
+// below fuzzy-rule thresholds against "Billing Service".
+func NameSimilarity(a, b string) float64 {
+	ta, tb := strings.Fields(NormalizeName(a)), strings.Fields(NormalizeName(b))
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	if strings.Join(ta, " ") == strings.Join(tb, " ") {
+		return 1
+	}
+	if len(ta) > len(tb) {
+		ta, tb = tb, ta
+	}
+	used := make([]bool, len(tb))
+	score := 1.0
+	for _, x := range ta {
+		best, bestIdx := 0.0, -1
+		for j, y := range tb {
+			if used[j] {
+				continue
+			}
+			var s float64
+			switch {
+			case x == y:
+				s = 1
+			case len([]rune(x)) == 1 || len([]rune(y)) == 1:
+				if []rune(x)[0] == []rune(y)[0] {
+					s = initialMatch
+				}
+			default:
+				s = JaroWinkler(x, y)
+			}
+			if s > best {
+				best, bestIdx = s, j
+			}
+		}
+		if bestIdx < 0 {
+			return 0
+		}
+		used[bestIdx] = true
+		score *= best
+	}
+	for j, y := range tb {
+		if used[j] {
+			continue
+		}
+		if len([]rune(y)) == 1 {
+			score *= 0.95
+		} else {
+			score *= 0.8
+		}
+	}
+	return score
+}
+
+// ===================================================
+// Double Metaphone
+// ===================================================
+
+// DoubleMetaphone returns primary and alternate phonetic codes (up to four
+// characters) for a single word. It implements the commonly hit rules of
+// Lawrence Philips' algorithm — silent letters, CH/SH/TH/PH digraphs, soft
+// C and G, and the Germanic/Slavic alternates (J as H, W as F, "-ski") —
+// rather than every special case of the reference implementation.
+func DoubleMetaphone(word string) (string, string) {
+	w := []rune(strings.ToUpper(word))
+	n := len(w)
+	var primary, alternate strings.Builder
+	add := func(p, a string) {
+		primary.WriteString(p)
+		alternate.WriteString(a)
+	}
+	at := func(i int) rune {
+		if i < 0 || i >= n {
+			return 0
+		}
+		return w[i]
+	}
+	isVowel := func(r rune) bool { return strings.ContainsRune("AEIOUY", r) }
+	has := func(i int, subs ...string) bool {
+		for _, s := range subs {
+			rs := []rune(s)
+			if i < 0 || i+len(rs) > n {
+				continue
+			}
+			if string(w[i:i+len(rs)]) == s {
+				return true
+			}
+		}
+		return false
+	}
+	slavoGermanic := strings.ContainsAny(string(w), "WK") || strings.Contains(string(w), "CZ")
+
+	i := 0
+	// Skip silent leading pairs.
+	if has(0, "GN", "KN", "PN", "WR", "PS") {
+		i = 1
+	}
+	if at(0) == 'X' {
+		add("S", "S")
+		i = 1
+	}
+
+	for i < n && (primary.Len() < 4 || alternate.Len() < 4) {
+		c := w[i]
+		if !unicode.IsLetter(c) {
+			i++
+			continue
+		}
+		switch {
+		case isVowel(c):
+			if i == 0 {
+				add("A", "A")
+			}
+			i++
+		case c == 'B':
+			add("P", "P")
+			i = skipDouble(w, i, 'B')
+		case c == 'C':
+			switch {
+			case has(i, "CHAE"):
+				add("K", "X")
+				i += 2
+			case has(i, "CH"):
+				if i == 0 && (has(i+2, "OR", "YM") || slavoGermanic) {
+					add("K", "K")
+				} else {
+					add("X", "K")
+				}
+				i += 2
+			case has(i, "CIA"):
+				add("X", "X")
+				i += 3
+			case has(i, "CK", "CQ", "CG"):
+				add("K", "K")
+				i += 2
+			case has(i, "CI", "CE", "CY"):
+				add("S", "S")
+				i += 2
+			case has(i, "CZ"):
+				add("S", "X")
+				i += 2
+			default:
+				add("K", "K")
+				i = skipDouble(w, i, 'C')
+			}
+		case c == 'D':
+			if has(i, "DGE", "DGI", "DGY") {
+				add("J", "J")
+				i += 3
+			} else {
+				add("T", "T")
+				if has(i, "DT", "DD") {
+					i += 2
+				} else {
+					i++
+				}
+			}
+		case c == 'F':
+			add("F", "F")
+			i = skipDouble(w, i, 'F')
+		case c == 'G':
+			switch {
+			case at(i+1) == 'H':
+				if i > 0 && !isVowel(at(i-1)) {
+					add("K", "K")
+				} else if i == 0 {
+					add("K", "K")
+				} else if has(i-1, "UGH") && !has(i-2, "OUGH") {
+					add("F", "F") // laugh, tough
+				}
+				i += 2
+			case at(i+1) == 'N':
+				if i == 1 && isVowel(at(0)) {
+					add("KN", "N")
+				} else {
+					add("N", "KN")
+				}
+				i += 2
+			case strings.ContainsRune("EIY", at(i+1)):
+				if has(0, "GET", "GER") || slavoGermanic {
+					add("K", "K")
+				} else {
+					add("J", "K")
+				}
+				i += 2
+			default:
+				add("K", "K")
+				i = skipDouble(w, i, 'G')
+			}
+		case c == 'H':
+			if (i == 0 || isVowel(at(i-1))) && isVowel(at(i+1)) {
+				add("H", "H")
+			}
+			i++
+		case c == 'J':
+			if i == 0 && !has(i, "JOSE") {
+				add("J", "A")
+			} else if has(i, "JOSE") {
+				add("H", "H")
+			} else {
+				add("J", "H")
+			}
+			i = skipDouble(w, i, 'J')
+		case c == 'K':
+			add("K", "K")
+			i = skipDouble(w, i, 'K')
+		case c == 'L':
+			add("L", "L")
+			i = skipDouble(w, i, 'L')
+		case c == 'M':
+			add("M", "M")
+			i = skipDouble(w, i, 'M')
+		case c == 'N':
+			add("N", "N")
+			i = skipDouble(w, i, 'N')
+		case c == 'P':
+			if at(i+1) == 'H' {
+				add("F", "F")
+				i += 2
+			} else {
+				add("P", "P")
+				if strings.ContainsRune("PB", at(i+1)) {
+					i += 2
+				} else {
+					i++
+				}
+			}
+		case c == 'Q':
+			add("K", "K")
+			i = skipDouble(w, i, 'Q')
+		case c == 'R':
+			add("R", "R")
+			i = skipDouble(w, i, 'R')
+		case c == 'S':
+			switch {
+			case has(i, "SCH"):
+				if i == 0 && !isVowel(at(3)) && at(3) != 'W' {
+					add("X", "S") // Schmidt, Schneider
+				} else {
+					add("SK", "SK")
+				}
+				i += 3
+			case has(i, "SH"):
+				add("X", "X")
+				i += 2
+			case has(i, "SIO", "SIA"):
+				add("S", "X")
+				i += 3
+			case i == 0 && strings.ContainsRune("MNLW", at(i+1)):
+				add("S", "X") // Schmidt/Smith style alternates
+				i++
+			default:
+				add("S", "S")
+				i = skipDouble(w, i, 'S')
+			}
+		case c == 'T':
+			switch {
+			case has(i, "TION", "TIA", "TCH"):
+				add("X", "X")
+				i += 3
+			case has(i, "TH"):
+				add("0", "T")
+				i += 2
+			default:
+				add("T", "T")
+				if has(i, "TT", "TD") {
+					i += 2
+				} else {
+					i++
+				}
+			}
+		case c == 'V':
+			add("F", "F")
+			i = skipDouble(w, i, 'V')
+		case c == 'W':
+			switch {
+			case i == 0 && isVowel(at(i+1)):
+				add("A", "F")
+			case i == n-1 && i > 0 && isVowel(at(i-1)), has(i-1, "EWSKI", "OWSKI"):
+				add("", "F")
+			}
+			i++
+		case c == 'X':
+			add("KS", "KS")
+			if strings.ContainsRune("CX", at(i+1)) {
+				i += 2
+			} else {
+				i++
+			}
+		case c == 'Z':
+			add("S", "TS")
+			i = skipDouble(w, i, 'Z')
+		default:
+			i++
+		}
+	}
+	p, a := primary.String(), alternate.String()
+	if len(p) > 4 {
+		p = p[:4]
+	}
+	if len(a) > 4 {
+		a = a[:4]
+	}
+	return p, a
+}
+
+func skipDouble(w []rune, i int, c rune) int {
+	if i+1 < len(w) && w[i+1] == c {
+		return i + 2
+	}
+	return i + 1
+}
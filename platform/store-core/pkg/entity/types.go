@@ -112,6 +112,7 @@ type MatchResult struct {
 	Score       float32 `json:"score"`       // Match confidence
 	MatchedBy   string  `json:"matchedBy"`   // Rule ID that matched
 	Reason      string  `json:"reason"`      // Human-readable explanation
+	Fields      []FieldScore `json:"fields,omitempty"` // Per-field evidence for model matches
 }
 
 // ===================================================
@@ -206,9 +207,12 @@ type EPPClassifier interface {
 // PolicyEntity extends CanonicalEntity for policies.
 type PolicyEntity struct {
 	CanonicalEntity
-	Rules       []PolicyRule `json:"rules"`       // Extracted rules
-	AppliesTo   []string     `json:"appliesTo"`   // Entity types this applies to
-	Enforcement string       `json:"enforcement"` // mandatory, recommended, optional
+	Rules         []PolicyRule `json:"rules"`         // Extracted rules
+	AppliesTo     []string     `json:"appliesTo"`     // Entity types this applies to
+	Enforcement   string       `json:"enforcement"`   // mandatory, recommended, optional
+	EffectiveDate string       `json:"effectiveDate"` // As stated in the document
+	Owners        []string     `json:"owners"`
+	Keywords      []string     `json:"keywords"`
 }
 
 // PolicyRule represents an extracted rule from a policy.
@@ -217,6 +221,8 @@ type PolicyRule struct {
 	Description string   `json:"description"`
 	Keywords    []string `json:"keywords"`
 	Requirement string   `json:"requirement"` // must, should, may
+	Category    string   `json:"category"`    // security, compliance, operational
+	Exceptions  []string `json:"exceptions"`
 }
 
 // ProcessEntity extends CanonicalEntity for processes.
@@ -225,16 +231,21 @@ type ProcessEntity struct {
 	Steps       []ProcessStep `json:"steps"`
 	Triggers    []string      `json:"triggers"`
 	Outcomes    []string      `json:"outcomes"`
+	Roles       []string      `json:"roles"`
+	SLA         string        `json:"sla"`
+	Owner       string        `json:"owner"`
 }
 
 // ProcessStep represents a step in a process.
 type ProcessStep struct {
 	ID          string   `json:"id"`
+	Order       int      `json:"order"`
 	Name        string   `json:"name"`
 	Description string   `json:"description"`
 	Actor       string   `json:"actor"`    // Who performs this step
 	Inputs      []string `json:"inputs"`   // Required inputs
 	Outputs     []string `json:"outputs"`  // Produced outputs
+	Conditions  []string `json:"conditions"` // When this step applies
 	NextSteps   []string `json:"nextSteps"` // Following step IDs
 }
 
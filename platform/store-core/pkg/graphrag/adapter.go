@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/nucleus/store-core/pkg/hybridsearch"
+	"github.com/nucleus/ucl-core/pkg/kgpb"
 )
 
 // ===================================================
@@ -112,5 +113,128 @@ func convertMetadata(m map[string]any) map[string]string {
 	return result
 }
 
+// ===================================================
+// KG Service Adapter
+// Connects the ucl-core KgService to GraphRAG's KGClient interface
+// ===================================================
+
+// KGServiceAdapter adapts a kgpb.KgServiceClient to KGClient and
+// KGTraverser, so DefaultExpander expands through one Traverse call.
+type KGServiceAdapter struct {
+	client    kgpb.KgServiceClient
+	projectID string
+}
+
+// NewKGServiceAdapter creates an adapter reading the KG of projectID; an
+// empty projectID reads every project of the tenant.
+func NewKGServiceAdapter(client kgpb.KgServiceClient, projectID string) *KGServiceAdapter {
+	return &KGServiceAdapter{
+		client:    client,
+		projectID: projectID,
+	}
+}
+
+// GetNode returns the node, or nil when it does not exist.
+func (a *KGServiceAdapter) GetNode(ctx context.Context, tenantID, nodeID string) (*GraphNode, error) {
+	resp, err := a.client.GetNode(ctx, &kgpb.GetNodeRequest{TenantId: tenantID, ProjectId: a.projectID, NodeId: nodeID})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Node == nil {
+		return nil, nil
+	}
+	node := graphNodeFromKG(resp.Node, 0)
+	return &node, nil
+}
+
+// ListNeighbors returns the nodes one hop from nodeID and the edges leading
+// to them, using a single-hop Traverse so direction is honoured.
+func (a *KGServiceAdapter) ListNeighbors(ctx context.Context, tenantID, nodeID string, edgeTypes []string, direction EdgeDirection, limit int) ([]GraphNode, []GraphEdge, error) {
+	maxNodes := 0
+	if limit > 0 {
+		maxNodes = limit + 1 // the node itself counts against the budget
+	}
+	nodes, edges, err := a.Traverse(ctx, tenantID, []string{nodeID}, edgeTypes, direction, 1, maxNodes)
+	if err != nil {
+		return nil, nil, err
+	}
+	neighbors := make([]GraphNode, 0, len(nodes))
+	for _, n := range nodes {
+		if n.HopDistance == 1 {
+			neighbors = append(neighbors, n)
+		}
+	}
+	return neighbors, edges, nil
+}
+
+// ListEdges returns edges filtered by source, target and type.
+func (a *KGServiceAdapter) ListEdges(ctx context.Context, tenantID, sourceID, targetID string, edgeTypes []string, limit int) ([]GraphEdge, error) {
+	resp, err := a.client.ListEdges(ctx, &kgpb.ListEdgesRequest{
+		TenantId:  tenantID,
+		ProjectId: a.projectID,
+		EdgeTypes: edgeTypes,
+		SourceId:  sourceID,
+		TargetId:  targetID,
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]GraphEdge, 0, len(resp.Edges))
+	for _, e := range resp.Edges {
+		edges = append(edges, graphEdgeFromKG(e))
+	}
+	return edges, nil
+}
+
+// Traverse implements KGTraverser with KgService.Traverse.
+func (a *KGServiceAdapter) Traverse(ctx context.Context, tenantID string, seedIDs []string, edgeTypes []string, direction EdgeDirection, maxHops, maxNodes int) ([]GraphNode, []GraphEdge, error) {
+	resp, err := a.client.Traverse(ctx, &kgpb.TraverseRequest{
+		TenantId:  tenantID,
+		ProjectId: a.projectID,
+		SeedIds:   seedIDs,
+		MaxHops:   int32(maxHops),
+		EdgeTypes: edgeTypes,
+		Direction: kgDirection(direction),
+		MaxNodes:  int32(maxNodes),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes := make([]GraphNode, 0, len(resp.Nodes))
+	for _, tn := range resp.Nodes {
+		if tn.Node != nil {
+			nodes = append(nodes, graphNodeFromKG(tn.Node, int(tn.Hop)))
+		}
+	}
+	edges := make([]GraphEdge, 0, len(resp.Edges))
+	for _, e := range resp.Edges {
+		edges = append(edges, graphEdgeFromKG(e))
+	}
+	return nodes, edges, nil
+}
+
+// kgDirection maps an EdgeDirection to the KgService direction string.
+func kgDirection(d EdgeDirection) string {
+	switch d {
+	case EdgeDirectionOutgoing:
+		return "out"
+	case EdgeDirectionIncoming:
+		return "in"
+	default:
+		return "any"
+	}
+}
+
+func graphNodeFromKG(n *kgpb.Node, hop int) GraphNode {
+	return GraphNode{ID: n.Id, Type: n.Type, Properties: n.Properties, HopDistance: hop}
+}
+
+func graphEdgeFromKG(e *kgpb.Edge) GraphEdge {
+	return GraphEdge{ID: e.Id, Type: e.Type, FromID: e.FromId, ToID: e.ToId, Properties: e.Properties, Weight: 1, Direction: EdgeDirectionOutgoing}
+}
+
 // Ensure interface compliance
 var _ HybridSearcher = (*HybridSearchAdapter)(nil)
+var _ KGClient = (*KGServiceAdapter)(nil)
+var _ KGTraverser = (*KGServiceAdapter)(nil)
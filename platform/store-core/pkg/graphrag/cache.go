@@ -0,0 +1,545 @@
+package graphrag
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nucleus/store-core/pkg/kvstore"
+)
+
+// ===================================================
+// Context Cache Implementations
+// In-process LRU with TTL, and a shared cache in the KV store.
+// Both drop a tenant's contexts when its cache generation changes.
+// ===================================================
+
+// DefaultContextCacheTTL bounds how long a built context is reused.
+const DefaultContextCacheTTL = 5 * time.Minute
+
+// CacheGenerationKey is the KV key (scoped to the tenant, empty project)
+// whose version is the tenant's cache generation. The indexing activity
+// writes it after a dataset is re-indexed, so every cache built on a
+// KVGenerationSource drops contexts built from older data.
+const CacheGenerationKey = "graphrag:cache-generation"
+
+// CacheStats are a cache's counters since startup (per process).
+type CacheStats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64 // LRU capacity evictions
+	Expirations uint64 // TTL expirations
+	Invalidated uint64 // entries dropped by tenant invalidation
+	Size        int    // current entries; -1 when unknown (shared caches)
+}
+
+// HitRate returns hits / (hits + misses).
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// CacheStatsReporter is implemented by caches exposing CacheStats.
+type CacheStatsReporter interface {
+	Stats() CacheStats
+}
+
+// TenantInvalidator is implemented by caches that can drop a tenant's entries.
+type TenantInvalidator interface {
+	InvalidateTenant(ctx context.Context, tenantID string) error
+}
+
+type cacheCounters struct {
+	hits, misses, evictions, expirations, invalidated uint64
+}
+
+func (c *cacheCounters) snapshot() CacheStats {
+	return CacheStats{
+		Hits:        atomic.LoadUint64(&c.hits),
+		Misses:      atomic.LoadUint64(&c.misses),
+		Evictions:   atomic.LoadUint64(&c.evictions),
+		Expirations: atomic.LoadUint64(&c.expirations),
+		Invalidated: atomic.LoadUint64(&c.invalidated),
+		Size:        -1,
+	}
+}
+
+// ===================================================
+// Tenant cache generations
+// ===================================================
+
+// GenerationSource reports a tenant's current cache generation. A cached
+// context is only served while the generation it was built at is current.
+type GenerationSource interface {
+	Generation(tenantID string) int64
+}
+
+// KVGenerationSource reads generations from the KV store, remembering each
+// for Refresh so cache lookups do not hit the store every time.
+type KVGenerationSource struct {
+	kv      kvstore.Store
+	Refresh time.Duration
+
+	mu     sync.Mutex
+	cached map[string]generationEntry
+	now    func() time.Time
+}
+
+type generationEntry struct {
+	generation int64
+	checkedAt  time.Time
+}
+
+// NewKVGenerationSource creates a generation source with a 5s refresh.
+func NewKVGenerationSource(kv kvstore.Store) *KVGenerationSource {
+	return &KVGenerationSource{kv: kv, Refresh: 5 * time.Second, cached: make(map[string]generationEntry), now: time.Now}
+}
+
+// Generation returns the tenant's generation, or the last known one when the
+// store is unavailable.
+func (g *KVGenerationSource) Generation(tenantID string) int64 {
+	g.mu.Lock()
+	entry, ok := g.cached[tenantID]
+	g.mu.Unlock()
+	if ok && g.now().Sub(entry.checkedAt) < g.Refresh {
+		return entry.generation
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	rec, err := g.kv.Get(ctx, tenantID, "", CacheGenerationKey)
+	if err != nil {
+		log.Printf("graphrag cache: read generation for %s: %v", tenantID, err)
+		return entry.generation
+	}
+	var generation int64
+	if rec != nil {
+		generation = rec.Version
+	}
+	g.mu.Lock()
+	g.cached[tenantID] = generationEntry{generation: generation, checkedAt: g.now()}
+	g.mu.Unlock()
+	return generation
+}
+
+// Bump advances the tenant's generation, invalidating its cached contexts in
+// every process sharing the KV store.
+func (g *KVGenerationSource) Bump(ctx context.Context, tenantID, reason string) error {
+	value, _ := json.Marshal(map[string]string{
+		"reason":        reason,
+		"invalidatedAt": time.Now().UTC().Format(time.RFC3339),
+	})
+	version, err := g.kv.Put(ctx, kvstore.Record{TenantID: tenantID, Key: CacheGenerationKey, Value: value}, 0)
+	if err != nil {
+		return err
+	}
+	g.mu.Lock()
+	g.cached[tenantID] = generationEntry{generation: version, checkedAt: g.now()}
+	g.mu.Unlock()
+	return nil
+}
+
+// ===================================================
+// In-Memory Cache Implementation
+// Concurrency-safe LRU with TTL
+// ===================================================
+
+// InMemoryContextCache is an LRU cache of built contexts. Entries expire
+// after the TTL, are evicted least-recently-used first, and are dropped when
+// their tenant is invalidated locally or its generation changes.
+type InMemoryContextCache struct {
+	mu          sync.Mutex
+	maxSize     int
+	ttl         time.Duration
+	entries     map[string]*list.Element
+	order       *list.List // front is most recently used
+	generations GenerationSource
+	now         func() time.Time
+	stats       cacheCounters
+}
+
+type memoryCacheEntry struct {
+	key        string
+	tenantID   string
+	ctx        *RAGContext
+	expiresAt  time.Time
+	generation int64
+}
+
+// NewInMemoryContextCache creates a cache holding maxSize contexts for
+// DefaultContextCacheTTL.
+func NewInMemoryContextCache(maxSize int) *InMemoryContextCache {
+	return NewInMemoryContextCacheWithTTL(maxSize, DefaultContextCacheTTL)
+}
+
+// NewInMemoryContextCacheWithTTL creates a cache with an explicit TTL.
+func NewInMemoryContextCacheWithTTL(maxSize int, ttl time.Duration) *InMemoryContextCache {
+	if maxSize <= 0 {
+		maxSize = 100
+	}
+	if ttl <= 0 {
+		ttl = DefaultContextCacheTTL
+	}
+	return &InMemoryContextCache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// WithGenerations makes the cache honour tenant generations from src, e.g. a
+// KVGenerationSource bumped by the indexing activity.
+func (c *InMemoryContextCache) WithGenerations(src GenerationSource) *InMemoryContextCache {
+	c.generations = src
+	return c
+}
+
+// cacheKeyString converts a CacheKey to string.
+// P2 Fix: Include all fields in key string with full precision for floats.
+// Strings are quoted so a ':' in the query cannot shift the other fields.
+func cacheKeyString(key CacheKey) string {
+	return fmt.Sprintf("%q:%q:%s:%d:%g:%g:%g:%q:%q:%q:%d:%d:%d:%q:%t:%d:%t:%d:%q",
+		key.TenantID, key.Query, key.EmbeddingHash, key.TopK, key.ScoreThreshold,
+		key.VectorWeight, key.KeywordWeight, key.ProjectID, key.ProfileIDs, key.EntityKinds,
+		key.MaxHops, key.MaxNodesPerHop, key.MaxTotalNodes,
+		key.EdgeTypes, key.IncludeCommunities, key.MaxCommunities,
+		key.IncludeContent, key.MaxContentLength, key.CarriedSeedIDs)
+}
+
+func (c *InMemoryContextCache) generation(tenantID string) int64 {
+	if c.generations == nil {
+		return 0
+	}
+	return c.generations.Generation(tenantID)
+}
+
+// Get retrieves a cached context.
+func (c *InMemoryContextCache) Get(key CacheKey) (*RAGContext, bool) {
+	// Read the generation outside the lock; it may call the KV store.
+	generation := c.generation(key.TenantID)
+	keyStr := cacheKeyString(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[keyStr]
+	if !ok {
+		atomic.AddUint64(&c.stats.misses, 1)
+		return nil, false
+	}
+	entry := el.Value.(*memoryCacheEntry)
+	switch {
+	case !c.now().Before(entry.expiresAt):
+		c.removeLocked(el)
+		atomic.AddUint64(&c.stats.expirations, 1)
+	case entry.generation != generation:
+		c.removeLocked(el)
+		atomic.AddUint64(&c.stats.invalidated, 1)
+	default:
+		c.order.MoveToFront(el)
+		atomic.AddUint64(&c.stats.hits, 1)
+		return entry.ctx, true
+	}
+	atomic.AddUint64(&c.stats.misses, 1)
+	return nil, false
+}
+
+// Set stores a context in cache.
+func (c *InMemoryContextCache) Set(key CacheKey, ctx *RAGContext) {
+	generation := c.generation(key.TenantID)
+	keyStr := cacheKeyString(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &memoryCacheEntry{
+		key:        keyStr,
+		tenantID:   key.TenantID,
+		ctx:        ctx,
+		expiresAt:  c.now().Add(c.ttl),
+		generation: generation,
+	}
+	if el, ok := c.entries[keyStr]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	for c.order.Len() >= c.maxSize {
+		c.removeLocked(c.order.Back())
+		atomic.AddUint64(&c.stats.evictions, 1)
+	}
+	c.entries[keyStr] = c.order.PushFront(entry)
+}
+
+// InvalidateTenant drops all of the tenant's entries from this process.
+func (c *InMemoryContextCache) InvalidateTenant(_ context.Context, tenantID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		if el.Value.(*memoryCacheEntry).tenantID == tenantID {
+			c.removeLocked(el)
+			atomic.AddUint64(&c.stats.invalidated, 1)
+		}
+		el = next
+	}
+	return nil
+}
+
+// Stats returns the cache counters.
+func (c *InMemoryContextCache) Stats() CacheStats {
+	stats := c.stats.snapshot()
+	c.mu.Lock()
+	stats.Size = c.order.Len()
+	c.mu.Unlock()
+	return stats
+}
+
+func (c *InMemoryContextCache) removeLocked(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*memoryCacheEntry).key)
+}
+
+// ===================================================
+// Shared Cache Implementation
+// Contexts stored in the KV store, shared by all replicas
+// ===================================================
+
+// contextCacheKeyPrefix namespaces cached contexts within a tenant scope.
+const contextCacheKeyPrefix = "graphrag:context-cache:"
+
+// contextCacheSweepPage is how many keys a sweep lists per KV call.
+const contextCacheSweepPage = 500
+
+// KVContextCache stores built contexts in the KV store so replicas share
+// them. Generations come from the same store. The KV store has no expiry,
+// so entries are deleted when read stale, when InvalidateTenant drops the
+// tenant's entries, and by Sweep, which Set runs in the background at most
+// once per TTL for each tenant.
+type KVContextCache struct {
+	kv          kvstore.Store
+	ttl         time.Duration
+	generations *KVGenerationSource
+	timeout     time.Duration
+	stats       cacheCounters
+
+	sweepMu    sync.Mutex
+	lastSweeps map[string]time.Time
+}
+
+type kvCacheValue struct {
+	Generation int64       `json:"generation"`
+	ExpiresAt  time.Time   `json:"expiresAt"`
+	Context    *RAGContext `json:"context"`
+}
+
+// NewKVContextCache creates a shared cache; ttl <= 0 uses DefaultContextCacheTTL.
+func NewKVContextCache(kv kvstore.Store, ttl time.Duration) *KVContextCache {
+	if ttl <= 0 {
+		ttl = DefaultContextCacheTTL
+	}
+	return &KVContextCache{kv: kv, ttl: ttl, generations: NewKVGenerationSource(kv), timeout: 2 * time.Second, lastSweeps: make(map[string]time.Time)}
+}
+
+func kvCacheKey(key CacheKey) string {
+	sum := sha256.Sum256([]byte(cacheKeyString(key)))
+	return contextCacheKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// Get retrieves a cached context. Store errors count as misses.
+func (c *KVContextCache) Get(key CacheKey) (*RAGContext, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	k := kvCacheKey(key)
+	rec, err := c.kv.Get(ctx, key.TenantID, "", k)
+	if err != nil || rec == nil {
+		atomic.AddUint64(&c.stats.misses, 1)
+		return nil, false
+	}
+	var value kvCacheValue
+	if err := json.Unmarshal(rec.Value, &value); err != nil || value.Context == nil {
+		atomic.AddUint64(&c.stats.misses, 1)
+		return nil, false
+	}
+	stale := false
+	switch {
+	case !time.Now().Before(value.ExpiresAt):
+		atomic.AddUint64(&c.stats.expirations, 1)
+		stale = true
+	case value.Generation != c.generations.Generation(key.TenantID):
+		atomic.AddUint64(&c.stats.invalidated, 1)
+		stale = true
+	}
+	if stale {
+		// Only delete the version we read, in case another replica refreshed it.
+		_, _ = c.kv.Delete(ctx, key.TenantID, "", k, rec.Version)
+		atomic.AddUint64(&c.stats.misses, 1)
+		return nil, false
+	}
+	atomic.AddUint64(&c.stats.hits, 1)
+	return value.Context, true
+}
+
+// Set stores a context. Write failures are logged; the cache is best effort.
+func (c *KVContextCache) Set(key CacheKey, ragCtx *RAGContext) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	data, err := json.Marshal(kvCacheValue{
+		Generation: c.generations.Generation(key.TenantID),
+		ExpiresAt:  time.Now().Add(c.ttl),
+		Context:    ragCtx,
+	})
+	if err != nil {
+		return
+	}
+	if _, err := c.kv.Put(ctx, kvstore.Record{TenantID: key.TenantID, Key: kvCacheKey(key), Value: data}, 0); err != nil {
+		log.Printf("graphrag cache: store context: %v", err)
+		return
+	}
+	c.maybeSweep(key.TenantID)
+}
+
+// maybeSweep starts a background Sweep of the tenant's entries unless this
+// process swept them within the TTL.
+func (c *KVContextCache) maybeSweep(tenantID string) {
+	c.sweepMu.Lock()
+	if time.Since(c.lastSweeps[tenantID]) < c.ttl {
+		c.sweepMu.Unlock()
+		return
+	}
+	c.lastSweeps[tenantID] = time.Now()
+	c.sweepMu.Unlock()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if _, err := c.Sweep(ctx, tenantID); err != nil {
+			log.Printf("graphrag cache: sweep %s: %v", tenantID, err)
+		}
+	}()
+}
+
+// Sweep deletes the tenant's entries that have expired or were built at an
+// older generation, and returns how many it deleted.
+func (c *KVContextCache) Sweep(ctx context.Context, tenantID string) (int, error) {
+	generation := c.generations.Generation(tenantID)
+	deleted := 0
+	err := c.eachKey(ctx, tenantID, func(k string) error {
+		rec, err := c.kv.Get(ctx, tenantID, "", k)
+		if err != nil || rec == nil {
+			return err
+		}
+		var value kvCacheValue
+		counter := &c.stats.invalidated
+		if err := json.Unmarshal(rec.Value, &value); err == nil {
+			if time.Now().Before(value.ExpiresAt) && value.Generation == generation {
+				return nil
+			}
+			if !time.Now().Before(value.ExpiresAt) {
+				counter = &c.stats.expirations
+			}
+		}
+		ok, err := c.kv.Delete(ctx, tenantID, "", k, rec.Version)
+		if ok {
+			deleted++
+			atomic.AddUint64(counter, 1)
+		}
+		return err
+	})
+	return deleted, err
+}
+
+// eachKey calls fn for every cache key of the tenant, in key order.
+func (c *KVContextCache) eachKey(ctx context.Context, tenantID string, fn func(key string) error) error {
+	after := ""
+	for {
+		keys, err := c.kv.ListKeysAfter(ctx, tenantID, "", contextCacheKeyPrefix, after, contextCacheSweepPage)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := fn(k); err != nil {
+				return err
+			}
+		}
+		if len(keys) < contextCacheSweepPage {
+			return nil
+		}
+		after = keys[len(keys)-1]
+	}
+}
+
+// InvalidateTenant bumps the tenant's generation for all replicas, then
+// deletes the tenant's entries.
+func (c *KVContextCache) InvalidateTenant(ctx context.Context, tenantID string) error {
+	if err := c.generations.Bump(ctx, tenantID, "invalidate"); err != nil {
+		return err
+	}
+	return c.eachKey(ctx, tenantID, func(k string) error {
+		ok, err := c.kv.Delete(ctx, tenantID, "", k, 0)
+		if ok {
+			atomic.AddUint64(&c.stats.invalidated, 1)
+		}
+		return err
+	})
+}
+
+// Stats returns this process's counters for the shared cache.
+func (c *KVContextCache) Stats() CacheStats {
+	return c.stats.snapshot()
+}
+
+// ===================================================
+// Cache RPCs
+// ===================================================
+
+// GetCacheStats returns the context cache counters. ok is false when the
+// service's context builder does not cache or its cache keeps no stats.
+func (s *Service) GetCacheStats() (stats CacheStats, ok bool) {
+	cached, isCached := s.contextBuilder.(*CachedContextBuilder)
+	if !isCached {
+		return CacheStats{}, false
+	}
+	return cached.Stats()
+}
+
+// InvalidateCacheRequest drops a tenant's cached contexts.
+type InvalidateCacheRequest struct {
+	TenantID string
+}
+
+// InvalidateCache drops the tenant's cached contexts. Re-indexing invalidates
+// through CacheGenerationKey instead; this is for manual use.
+func (s *Service) InvalidateCache(ctx context.Context, req *InvalidateCacheRequest) error {
+	if req.TenantID == "" {
+		return status.Error(codes.InvalidArgument, "tenant_id is required")
+	}
+	if cached, ok := s.contextBuilder.(*CachedContextBuilder); ok {
+		if err := cached.InvalidateTenant(ctx, req.TenantID); err != nil {
+			return status.Errorf(codes.Internal, "invalidate cache: %v", err)
+		}
+	}
+	return nil
+}
+
+// Ensure interface compliance
+var (
+	_ ContextCache       = (*InMemoryContextCache)(nil)
+	_ ContextCache       = (*KVContextCache)(nil)
+	_ CacheStatsReporter = (*InMemoryContextCache)(nil)
+	_ CacheStatsReporter = (*KVContextCache)(nil)
+	_ TenantInvalidator  = (*InMemoryContextCache)(nil)
+	_ TenantInvalidator  = (*KVContextCache)(nil)
+	_ GenerationSource   = (*KVGenerationSource)(nil)
+)
@@ -0,0 +1,177 @@
+package graphrag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nucleus/store-core/pkg/kvstore"
+)
+
+func cacheKey(tenantID, query string) CacheKey {
+	return CacheKey{TenantID: tenantID, Query: query}
+}
+
+func TestInMemoryContextCacheLRUAndTTL(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := NewInMemoryContextCacheWithTTL(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set(cacheKey("t1", "a"), NewRAGContext("t1", "a"))
+	c.Set(cacheKey("t1", "b"), NewRAGContext("t1", "b"))
+	if _, ok := c.Get(cacheKey("t1", "a")); !ok { // a is now most recently used
+		t.Fatal("a should be cached")
+	}
+	c.Set(cacheKey("t1", "c"), NewRAGContext("t1", "c"))
+	if _, ok := c.Get(cacheKey("t1", "b")); ok {
+		t.Fatal("b should have been evicted as least recently used")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.Get(cacheKey("t1", "a")); ok {
+		t.Fatal("a should have expired")
+	}
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Evictions != 1 || stats.Expirations != 1 || stats.Size != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestInMemoryContextCacheTenantInvalidation(t *testing.T) {
+	kv := kvstore.NewMemoryStore()
+	gens := NewKVGenerationSource(kv)
+	gens.Refresh = 0 // re-read on every lookup
+	c := NewInMemoryContextCache(10).WithGenerations(gens)
+
+	c.Set(cacheKey("t1", "q"), NewRAGContext("t1", "q"))
+	c.Set(cacheKey("t2", "q"), NewRAGContext("t2", "q"))
+
+	// The indexing activity writes the generation key through the KV service.
+	if _, err := kv.Put(context.Background(), kvstore.Record{TenantID: "t1", Key: CacheGenerationKey, Value: []byte(`{}`)}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get(cacheKey("t1", "q")); ok {
+		t.Fatal("t1 context should be stale after re-indexing")
+	}
+	if _, ok := c.Get(cacheKey("t2", "q")); !ok {
+		t.Fatal("t2 context should be unaffected")
+	}
+
+	if err := c.InvalidateTenant(context.Background(), "t2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get(cacheKey("t2", "q")); ok {
+		t.Fatal("t2 context should be dropped by local invalidation")
+	}
+	if got := c.Stats().Invalidated; got != 2 {
+		t.Fatalf("invalidated = %d", got)
+	}
+}
+
+func TestInMemoryContextCacheConcurrentAccess(t *testing.T) {
+	c := NewInMemoryContextCache(16)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := cacheKey(fmt.Sprintf("t%d", g%2), fmt.Sprintf("q%d", i%32))
+				if _, ok := c.Get(key); !ok {
+					c.Set(key, NewRAGContext(key.TenantID, key.Query))
+				}
+				if i%50 == 0 {
+					_ = c.InvalidateTenant(context.Background(), key.TenantID)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if stats := c.Stats(); stats.Size > 16 || stats.Hits+stats.Misses != 1600 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestKVContextCacheSharedAcrossReplicas(t *testing.T) {
+	kv := kvstore.NewMemoryStore()
+	replicaA, replicaB := NewKVContextCache(kv, time.Minute), NewKVContextCache(kv, time.Minute)
+	replicaB.generations.Refresh = 0
+
+	ragCtx := NewRAGContext("t1", "who owns billing?")
+	ragCtx.AddSeed(EntityMatch{ID: "svc-billing", Name: "Billing"})
+	replicaA.Set(cacheKey("t1", "who owns billing?"), ragCtx)
+
+	got, ok := replicaB.Get(cacheKey("t1", "who owns billing?"))
+	if !ok || len(got.SeedEntities) != 1 || got.SeedEntities[0].ID != "svc-billing" {
+		t.Fatalf("replica B should read A's context: %+v, %v", got, ok)
+	}
+
+	if err := replicaA.InvalidateTenant(context.Background(), "t1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := replicaB.Get(cacheKey("t1", "who owns billing?")); ok {
+		t.Fatal("invalidation should reach other replicas")
+	}
+	if stats := replicaB.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if keys, _ := kv.ListKeys(context.Background(), "t1", "", contextCacheKeyPrefix, 0); len(keys) != 0 || replicaA.Stats().Invalidated != 1 {
+		t.Fatalf("invalidation should delete the tenant's entries, left %v", keys)
+	}
+}
+
+func TestKVContextCacheSweepDeletesStaleEntries(t *testing.T) {
+	ctx := context.Background()
+	kv := kvstore.NewMemoryStore()
+	c := NewKVContextCache(kv, time.Minute)
+	c.lastSweeps["t1"] = time.Now() // no background sweep during the test
+	for _, q := range []string{"fresh", "expired", "old generation"} {
+		c.Set(cacheKey("t1", q), NewRAGContext("t1", q))
+	}
+	rec, _ := kv.Get(ctx, "t1", "", kvCacheKey(cacheKey("t1", "expired")))
+	expired, _ := json.Marshal(kvCacheValue{ExpiresAt: time.Now().Add(-time.Second), Context: NewRAGContext("t1", "expired")})
+	if _, err := kv.Put(ctx, kvstore.Record{TenantID: "t1", Key: rec.Key, Value: expired}, 0); err != nil {
+		t.Fatal(err)
+	}
+	rec, _ = kv.Get(ctx, "t1", "", kvCacheKey(cacheKey("t1", "old generation")))
+	old, _ := json.Marshal(kvCacheValue{Generation: -1, ExpiresAt: time.Now().Add(time.Minute), Context: NewRAGContext("t1", "old")})
+	if _, err := kv.Put(ctx, kvstore.Record{TenantID: "t1", Key: rec.Key, Value: old}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := c.Sweep(ctx, "t1")
+	if err != nil || n != 2 {
+		t.Fatalf("sweep deleted %d err=%v, want 2", n, err)
+	}
+	if _, ok := c.Get(cacheKey("t1", "fresh")); !ok {
+		t.Fatal("sweep should keep the fresh entry")
+	}
+	if stats := c.Stats(); stats.Expirations != 1 || stats.Invalidated != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestCachedContextBuilderKeysOnFiltersAndWeights(t *testing.T) {
+	inner := &keywordBuilder{}
+	b := NewCachedContextBuilder(inner, NewInMemoryContextCache(10))
+	build := func(config ContextBuilderConfig) {
+		t.Helper()
+		if _, err := b.BuildContext(context.Background(), "t1", "who owns billing?", config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	build(ContextBuilderConfig{ProjectID: "p1"})
+	build(ContextBuilderConfig{ProjectID: "p2"})
+	build(ContextBuilderConfig{ProjectID: "p1", ProfileIDs: []string{"docs", "code"}})
+	build(ContextBuilderConfig{ProjectID: "p1", ProfileIDs: []string{"code", "docs"}}) // same filters
+	build(ContextBuilderConfig{ProjectID: "p1", EntityKinds: []string{"service"}})
+	build(ContextBuilderConfig{ProjectID: "p1", VectorWeight: 0.9, KeywordWeight: 0.1})
+	build(ContextBuilderConfig{ProjectID: "p1", VectorWeight: 0.5, KeywordWeight: 0.5}) // the default weights
+	if len(inner.calls) != 5 {
+		t.Fatalf("expected 5 distinct contexts to be built, got %d", len(inner.calls))
+	}
+}
@@ -0,0 +1,312 @@
+package graphrag
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ===================================================
+// Inline citations
+// The answer prompt numbers every context source; the model cites claims
+// with [n] markers, which are verified against the numbered sources.
+// ===================================================
+
+// maxCitationSources caps how many context items are numbered in the prompt.
+const maxCitationSources = 50
+
+// citationSource is a numbered context item the model may cite.
+type citationSource struct {
+	Index   int
+	ID      string
+	Type    string
+	Name    string
+	Excerpt string
+}
+
+// buildCitationSources numbers seed entities, then expanded graph nodes, then
+// communities, skipping duplicates.
+func buildCitationSources(ragCtx *RAGContext) []citationSource {
+	var sources []citationSource
+	seen := make(map[string]bool)
+	add := func(id, typ, name, excerpt string) {
+		if id == "" || seen[id] || len(sources) >= maxCitationSources {
+			return
+		}
+		seen[id] = true
+		sources = append(sources, citationSource{
+			Index:   len(sources) + 1,
+			ID:      id,
+			Type:    typ,
+			Name:    defaultString(name, id),
+			Excerpt: truncateText(excerpt, 200),
+		})
+	}
+	for _, e := range ragCtx.SeedEntities {
+		add(e.ID, e.Type, e.Name, firstNonEmpty(e.Description, e.Content))
+	}
+	if ragCtx.ExpandedGraph != nil {
+		for _, n := range ragCtx.ExpandedGraph.Nodes {
+			add(n.ID, n.Type, nodeDisplayName(n), firstNonEmpty(n.Properties["description"], n.Properties["content"]))
+		}
+	}
+	for _, c := range ragCtx.Communities {
+		add(c.ID, "community", c.Label, c.Description)
+	}
+	return sources
+}
+
+// buildCitedAnswerPrompt formats the context as numbered sources and asks the
+// model to cite them inline.
+func buildCitedAnswerPrompt(query string, ragCtx *RAGContext, sources []citationSource, maxTokens int) string {
+	var b strings.Builder
+	b.WriteString("Answer the query using only the numbered sources below. ")
+	b.WriteString("Cite every claim with the number of the source that supports it, e.g. \"The billing service is owned by Alice [2].\" ")
+	b.WriteString("Use several markers when a claim relies on several sources ([1][3]). ")
+	b.WriteString("Only cite numbers listed below. If the sources do not answer the query, say so.\n")
+	b.WriteString(fmt.Sprintf("Query: %s\n\n", query))
+
+	b.WriteString("Sources:\n")
+	index := make(map[string]int, len(sources))
+	for _, src := range sources {
+		index[src.ID] = src.Index
+		line := fmt.Sprintf("[%d] %s", src.Index, src.Name)
+		if src.Type != "" {
+			line += fmt.Sprintf(" (%s)", src.Type)
+		}
+		if src.Excerpt != "" {
+			line += ": " + src.Excerpt
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if ragCtx.ExpandedGraph != nil && len(ragCtx.ExpandedGraph.Edges) > 0 {
+		b.WriteString("\nRelationships:\n")
+		labels := buildNodeLabelLookup(ragCtx)
+		ref := func(id string) string {
+			if n, ok := index[id]; ok {
+				return fmt.Sprintf("%s [%d]", defaultString(labels[id], id), n)
+			}
+			return defaultString(labels[id], id)
+		}
+		for _, e := range ragCtx.ExpandedGraph.Edges {
+			b.WriteString(fmt.Sprintf("- %s -[%s]-> %s\n", ref(e.FromID), e.Type, ref(e.ToID)))
+		}
+	}
+
+	prompt := b.String()
+	if maxTokens > 0 {
+		maxChars := maxTokens * 4
+		if len(prompt) > maxChars {
+			prompt = prompt[:maxChars] + "..."
+		}
+	}
+	return prompt
+}
+
+var (
+	markerPattern    = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+	markerPrefixChar = regexp.MustCompile(`^\[[\d,\s]*$`)
+)
+
+// markerFilter rewrites [n] markers in streamed text, dropping numbers that
+// do not refer to a source. Text from an unclosed '[' is held back until the
+// marker completes, so emitted text never needs to be retracted.
+type markerFilter struct {
+	sources int
+	pending strings.Builder
+	valid   int
+	invalid int
+}
+
+// maxMarkerLen bounds how long a '[' is held back before it is treated as
+// plain text.
+const maxMarkerLen = 24
+
+func (f *markerFilter) Write(delta string) string {
+	var out strings.Builder
+	for _, r := range delta {
+		if f.pending.Len() == 0 {
+			if r == '[' {
+				f.pending.WriteRune(r)
+			} else {
+				out.WriteRune(r)
+			}
+			continue
+		}
+		if r == '[' {
+			out.WriteString(f.pending.String())
+			f.pending.Reset()
+			f.pending.WriteRune(r)
+			continue
+		}
+		f.pending.WriteRune(r)
+		held := f.pending.String()
+		switch {
+		case r == ']':
+			out.WriteString(f.resolve(held))
+			f.pending.Reset()
+		case !markerPrefixChar.MatchString(held) || len(held) > maxMarkerLen:
+			out.WriteString(held)
+			f.pending.Reset()
+		}
+	}
+	return out.String()
+}
+
+// Flush releases any held-back text at the end of the stream.
+func (f *markerFilter) Flush() string {
+	held := f.pending.String()
+	f.pending.Reset()
+	return held
+}
+
+// resolve rewrites one complete bracketed token.
+func (f *markerFilter) resolve(token string) string {
+	m := markerPattern.FindStringSubmatch(token)
+	if m == nil || m[0] != token {
+		return token // not a citation, e.g. "[sic]"
+	}
+	var kept []string
+	for _, part := range strings.Split(m[1], ",") {
+		n, _ := strconv.Atoi(strings.TrimSpace(part))
+		if n >= 1 && n <= f.sources {
+			kept = append(kept, strconv.Itoa(n))
+			f.valid++
+		} else {
+			f.invalid++
+		}
+	}
+	if len(kept) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(kept, ", ") + "]"
+}
+
+// claimSpan is a sentence of the answer.
+type claimSpan struct {
+	start, end int
+	sources    []int
+}
+
+// verifyCitations maps each claim (sentence) of a marker-filtered answer to
+// the sources it cites. Confidence is the share of claims carrying at least
+// one valid citation, scaled by the share of markers that were valid.
+func verifyCitations(answer string, sources []citationSource, valid, invalid int) ([]Citation, float32) {
+	claims := splitClaims(answer)
+	for _, loc := range markerPattern.FindAllStringSubmatchIndex(answer, -1) {
+		i := claimAt(claims, answer, loc[0])
+		if i < 0 {
+			continue
+		}
+		for _, part := range strings.Split(answer[loc[2]:loc[3]], ",") {
+			n, _ := strconv.Atoi(strings.TrimSpace(part))
+			if n >= 1 && n <= len(sources) {
+				claims[i].sources = append(claims[i].sources, n)
+			}
+		}
+	}
+
+	var citations []Citation
+	var total, cited int
+	for _, c := range claims {
+		text := claimText(answer[c.start:c.end])
+		if len(strings.Fields(text)) < 3 {
+			continue // headings, list bullets and other fragments are not claims
+		}
+		total++
+		if len(c.sources) > 0 {
+			cited++
+		}
+		seen := map[int]bool{}
+		for _, n := range c.sources {
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			src := sources[n-1]
+			citations = append(citations, Citation{
+				SourceID:    src.ID,
+				SourceType:  src.Type,
+				SourceName:  src.Name,
+				Excerpt:     src.Excerpt,
+				StartOffset: c.start,
+				EndOffset:   c.end,
+				Marker:      n,
+				Claim:       text,
+			})
+		}
+	}
+	if total == 0 {
+		return citations, 0
+	}
+	confidence := float32(cited) / float32(total)
+	if valid+invalid > 0 {
+		confidence *= float32(valid) / float32(valid+invalid)
+	}
+	return citations, confidence
+}
+
+var spaceBeforePunct = regexp.MustCompile(`\s+([.,;:!?])`)
+
+// claimText strips markers from a claim, e.g. "owned by Alice [2]." becomes
+// "owned by Alice.".
+func claimText(span string) string {
+	text := markerPattern.ReplaceAllString(span, "")
+	return strings.TrimSpace(spaceBeforePunct.ReplaceAllString(text, "$1"))
+}
+
+// splitClaims splits text into sentences on ., ! and ? followed by space, and
+// on newlines. Spans exclude surrounding whitespace.
+func splitClaims(text string) []claimSpan {
+	var spans []claimSpan
+	start := 0
+	push := func(end int) {
+		s, e := start, end
+		for s < e && isSpace(text[s]) {
+			s++
+		}
+		for e > s && isSpace(text[e-1]) {
+			e--
+		}
+		if e > s {
+			spans = append(spans, claimSpan{start: s, end: e})
+		}
+		start = end
+	}
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\n':
+			push(i + 1)
+		case '.', '!', '?':
+			if i+1 == len(text) || isSpace(text[i+1]) {
+				push(i + 1)
+			}
+		}
+	}
+	push(len(text))
+	return spans
+}
+
+// claimAt returns the claim a marker at pos belongs to. A marker that opens a
+// sentence ("... service. [2]") belongs to the previous one.
+func claimAt(claims []claimSpan, text string, pos int) int {
+	for i, c := range claims {
+		if pos < c.start || pos >= c.end {
+			continue
+		}
+		if i > 0 && strings.TrimSpace(markerPattern.ReplaceAllString(text[c.start:pos], "")) == "" {
+			prev := claims[i-1]
+			if !strings.Contains(text[prev.end:c.start], "\n") {
+				return i - 1
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,106 @@
+package graphrag
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type fakeStreamingLLM struct{ chunks []string }
+
+func (f *fakeStreamingLLM) Name() string { return "fake" }
+
+func (f *fakeStreamingLLM) Complete(_ context.Context, _ string, _ LLMCompletionOptions) (string, error) {
+	return strings.Join(f.chunks, ""), nil
+}
+
+func (f *fakeStreamingLLM) CompleteStream(_ context.Context, _ string, _ LLMCompletionOptions, onDelta func(string) error) (string, error) {
+	for _, c := range f.chunks {
+		if err := onDelta(c); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(f.chunks, ""), nil
+}
+
+type recordingStream struct{ chunks []*AnswerChunk }
+
+func (r *recordingStream) Context() context.Context { return context.Background() }
+
+func (r *recordingStream) Send(c *AnswerChunk) error {
+	r.chunks = append(r.chunks, c)
+	return nil
+}
+
+func testRAGContext() *RAGContext {
+	return &RAGContext{
+		TenantID: "t1",
+		SeedEntities: []EntityMatch{
+			{ID: "svc-billing", Name: "Billing service", Type: "service", Description: "Handles invoices"},
+			{ID: "person-alice", Name: "Alice", Type: "person"},
+		},
+		ExpandedGraph: &GraphExpansion{Nodes: []GraphNode{
+			{ID: "svc-billing", Type: "service"},
+			{ID: "team-payments", Type: "team", Properties: map[string]string{"name": "Payments"}},
+		}},
+	}
+}
+
+func TestStreamAnswerVerifiesMarkers(t *testing.T) {
+	// Markers are split across chunks; [7] points at nothing.
+	llm := &fakeStreamingLLM{chunks: []string{
+		"The billing service is owned by Alice [", "1, 2]. It belongs to the Payments team. [3",
+		"]\nIt was rewritten in Rust last year [7]. See [the runbook] for details on deploys.",
+	}}
+	svc := NewService(nil, nil, nil, nil, llm)
+	stream := &recordingStream{}
+	err := svc.StreamAnswer(&GenerateAnswerRequest{TenantID: "t1", Query: "who owns billing?", Context: testRAGContext()}, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := stream.chunks[len(stream.chunks)-1]
+	if last.Answer == nil {
+		t.Fatal("final chunk must carry the answer")
+	}
+	var streamed strings.Builder
+	for _, c := range stream.chunks[:len(stream.chunks)-1] {
+		streamed.WriteString(c.Delta)
+	}
+	answer := last.Answer
+	if streamed.String() != answer.Answer {
+		t.Fatalf("streamed text %q differs from answer %q", streamed.String(), answer.Answer)
+	}
+	if strings.Contains(answer.Answer, "[7]") || !strings.Contains(answer.Answer, "[the runbook]") {
+		t.Fatalf("unexpected marker filtering: %q", answer.Answer)
+	}
+	if answer.DroppedCitations != 1 {
+		t.Fatalf("dropped = %d, want 1", answer.DroppedCitations)
+	}
+
+	got := map[string]string{}
+	for _, c := range answer.Citations {
+		if answer.Answer[c.StartOffset:c.EndOffset] == "" {
+			t.Fatalf("empty claim span for %+v", c)
+		}
+		got[c.SourceID] = c.Claim
+	}
+	if len(answer.Citations) != 3 || got["svc-billing"] != "The billing service is owned by Alice." ||
+		got["person-alice"] == "" || got["team-payments"] != "It belongs to the Payments team." {
+		t.Fatalf("unexpected citations: %+v", answer.Citations)
+	}
+	// 2 of 4 claims cited; 3 of 4 markers valid.
+	if want := float32(0.5 * 0.75); answer.Confidence != want {
+		t.Fatalf("confidence = %v, want %v", answer.Confidence, want)
+	}
+}
+
+func TestGenerateAnswerWithoutMarkersHasNoConfidence(t *testing.T) {
+	svc := NewService(nil, nil, nil, nil, &fakeStreamingLLM{chunks: []string{"Billing is owned by the payments team."}})
+	answer, err := svc.GenerateAnswer(context.Background(), &GenerateAnswerRequest{TenantID: "t1", Query: "q", Context: testRAGContext()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(answer.Citations) != 0 || answer.Confidence != 0 {
+		t.Fatalf("uncited answer should not be trusted: %+v", answer)
+	}
+}
@@ -2,7 +2,13 @@ package graphrag
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -19,6 +25,7 @@ type DefaultContextBuilder struct {
 	embeddingProvider EmbeddingProvider // Optional: generates embeddings from text
 	graphExpander     GraphExpander
 	communityProvider CommunityProvider
+	planner           QueryPlanner // Optional: decomposes multi-part queries
 }
 
 // EmbeddingProvider generates embeddings from text queries.
@@ -42,6 +49,14 @@ func NewDefaultContextBuilder(
 	}
 }
 
+// WithPlanner enables query decomposition. Each sub-query of the plan is
+// searched separately and the seeds merged; the plan is recorded on the
+// RAGContext. A nil planner restores single-search behaviour.
+func (b *DefaultContextBuilder) WithPlanner(planner QueryPlanner) *DefaultContextBuilder {
+	b.planner = planner
+	return b
+}
+
 // BuildContext creates a complete RAG context for a query.
 func (b *DefaultContextBuilder) BuildContext(
 	ctx context.Context,
@@ -96,14 +111,25 @@ func (b *DefaultContextBuilder) BuildContext(
 			EntityKinds:   config.EntityKinds,
 		}
 
-		seeds, err := b.hybridSearcher.Search(
+		var plan *QueryPlan
+		if b.planner != nil {
+			if p, err := b.planner.Plan(ctx, query); err == nil && len(p.SubQueries) > 1 {
+				plan = p
+			}
+		}
+
+		if plan != nil {
+			ragCtx.Plan = plan
+			for _, seed := range b.plannedSearch(ctx, tenantID, plan, hybridConfig, embedding) {
+				ragCtx.AddSeed(seed)
+			}
+		} else if seeds, err := b.hybridSearcher.Search(
 			ctx,
 			tenantID,
 			query,
 			embedding,
 			hybridConfig,
-		)
-		if err != nil {
+		); err != nil {
 			// Log but continue - search failure shouldn't block everything
 		} else {
 			for _, seed := range seeds {
@@ -112,6 +138,10 @@ func (b *DefaultContextBuilder) BuildContext(
 		}
 	}
 
+	// Phase 1b: Seeds carried over from earlier conversation turns, so a
+	// follow-up expands from the entities already under discussion.
+	addCarriedSeeds(ragCtx, config.CarriedSeeds)
+
 	// Phase 2: Graph expansion from seed entities
 	if b.graphExpander != nil && len(ragCtx.SeedEntities) > 0 {
 		seedIDs := make([]string, 0, len(ragCtx.SeedEntities))
@@ -161,6 +191,21 @@ func (b *DefaultContextBuilder) BuildContext(
 	return ragCtx, nil
 }
 
+// addCarriedSeeds appends carried seeds that search did not already return.
+func addCarriedSeeds(ragCtx *RAGContext, carried []EntityMatch) {
+	present := make(map[string]bool, len(ragCtx.SeedEntities))
+	for _, seed := range ragCtx.SeedEntities {
+		present[seed.ID] = true
+	}
+	for _, seed := range carried {
+		if seed.ID == "" || present[seed.ID] {
+			continue
+		}
+		present[seed.ID] = true
+		ragCtx.AddSeed(seed)
+	}
+}
+
 // applyContextBuilderDefaults fills in missing numeric config values.
 // IMPORTANT: Boolean fields (IncludeCommunities, IncludeContent) are NOT defaulted here.
 // This is because Go's bool zero value is false, and we cannot distinguish between
@@ -283,8 +328,14 @@ func (b *SearchOnlyContextBuilder) BuildContext(
 type CacheKey struct {
 	TenantID           string
 	Query              string
+	EmbeddingHash      string // Hash of a caller-supplied query embedding
 	TopK               int
 	ScoreThreshold     float32
+	VectorWeight       float32
+	KeywordWeight      float32
+	ProjectID          string
+	ProfileIDs         string // Sorted join of profile filters
+	EntityKinds        string // Sorted join of entity kind filters
 	MaxHops            int
 	MaxNodesPerHop     int
 	MaxTotalNodes      int
@@ -293,6 +344,7 @@ type CacheKey struct {
 	MaxCommunities     int
 	IncludeContent     bool
 	MaxContentLength   int
+	CarriedSeedIDs     string // Join of carried seed IDs
 }
 
 // ContextCache defines the caching interface.
@@ -341,11 +393,28 @@ func (b *CachedContextBuilder) BuildContext(
 		edgeTypesStr += et
 	}
 
+	carriedIDs := make([]string, 0, len(config.CarriedSeeds))
+	for _, seed := range config.CarriedSeeds {
+		carriedIDs = append(carriedIDs, seed.ID)
+	}
+
+	// Equivalent weights share an entry, as in DefaultContextBuilder.
+	vectorWeight, keywordWeight := config.VectorWeight, config.KeywordWeight
+	if vectorWeight <= 0 && keywordWeight <= 0 {
+		vectorWeight, keywordWeight = 0.5, 0.5
+	}
+
 	key := CacheKey{
 		TenantID:           tenantID,
 		Query:              query,
+		EmbeddingHash:      embeddingHash(config.QueryEmbedding),
 		TopK:               config.TopK,
 		ScoreThreshold:     config.ScoreThreshold,
+		VectorWeight:       vectorWeight,
+		KeywordWeight:      keywordWeight,
+		ProjectID:          config.ProjectID,
+		ProfileIDs:         sortedJoin(config.ProfileIDs),
+		EntityKinds:        sortedJoin(config.EntityKinds),
 		MaxHops:            config.MaxHops,
 		MaxNodesPerHop:     config.MaxNodesPerHop,
 		MaxTotalNodes:      config.MaxTotalNodes,
@@ -354,6 +423,7 @@ func (b *CachedContextBuilder) BuildContext(
 		MaxCommunities:     config.MaxCommunities,
 		IncludeContent:     config.IncludeContent,
 		MaxContentLength:   config.MaxContentLength,
+		CarriedSeedIDs:     strings.Join(carriedIDs, ","),
 	}
 
 	// Check cache
@@ -371,73 +441,45 @@ func (b *CachedContextBuilder) BuildContext(
 	return result, nil
 }
 
-// ===================================================
-// In-Memory Cache Implementation
-// Simple LRU-like cache for development/testing
-// ===================================================
-
-// InMemoryContextCache provides a simple in-memory cache.
-type InMemoryContextCache struct {
-	entries  map[string]*RAGContext
-	maxSize  int
-	insertOrder []string
+// sortedJoin joins filter values order-independently.
+func sortedJoin(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
 }
 
-// NewInMemoryContextCache creates a new in-memory cache.
-func NewInMemoryContextCache(maxSize int) *InMemoryContextCache {
-	if maxSize <= 0 {
-		maxSize = 100
+// embeddingHash identifies a query embedding; empty when there is none.
+func embeddingHash(embedding []float32) string {
+	if len(embedding) == 0 {
+		return ""
 	}
-	return &InMemoryContextCache{
-		entries:     make(map[string]*RAGContext),
-		maxSize:     maxSize,
-		insertOrder: make([]string, 0, maxSize),
+	h := sha256.New()
+	buf := make([]byte, 4)
+	for _, v := range embedding {
+		binary.LittleEndian.PutUint32(buf, math.Float32bits(v))
+		h.Write(buf)
 	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
 }
 
-// cacheKeyString converts a CacheKey to string.
-// P2 Fix: Include all fields in key string with full precision for floats.
-func cacheKeyString(key CacheKey) string {
-	return fmt.Sprintf("%s:%s:%d:%g:%d:%d:%d:%s:%t:%d:%t:%d",
-		key.TenantID, key.Query, key.TopK, key.ScoreThreshold,
-		key.MaxHops, key.MaxNodesPerHop, key.MaxTotalNodes,
-		key.EdgeTypes, key.IncludeCommunities, key.MaxCommunities,
-		key.IncludeContent, key.MaxContentLength)
-}
-
-// Get retrieves a cached context.
-func (c *InMemoryContextCache) Get(key CacheKey) (*RAGContext, bool) {
-	keyStr := cacheKeyString(key)
-	ctx, ok := c.entries[keyStr]
-	return ctx, ok
+// Stats returns the cache counters when the cache reports them.
+func (b *CachedContextBuilder) Stats() (CacheStats, bool) {
+	reporter, ok := b.cache.(CacheStatsReporter)
+	if !ok {
+		return CacheStats{}, false
+	}
+	return reporter.Stats(), true
 }
 
-// Set stores a context in cache.
-func (c *InMemoryContextCache) Set(key CacheKey, ctx *RAGContext) {
-	keyStr := cacheKeyString(key)
-	
-	// P2 Fix: If key already exists, don't add to insertOrder again
-	// Check if it's a new entry vs update
-	isNew := c.entries[keyStr] == nil
-	
-	// Evict oldest if at capacity and this is a new entry
-	if isNew && len(c.entries) >= c.maxSize {
-		if len(c.insertOrder) > 0 {
-			oldest := c.insertOrder[0]
-			delete(c.entries, oldest)
-			c.insertOrder = c.insertOrder[1:]
-		}
-	}
-	
-	c.entries[keyStr] = ctx
-	// Only append to order if this is a new key
-	if isNew {
-		c.insertOrder = append(c.insertOrder, keyStr)
+// InvalidateTenant drops the tenant's cached contexts when the cache supports it.
+func (b *CachedContextBuilder) InvalidateTenant(ctx context.Context, tenantID string) error {
+	if inv, ok := b.cache.(TenantInvalidator); ok {
+		return inv.InvalidateTenant(ctx, tenantID)
 	}
+	return nil
 }
 
 // Ensure interface compliance
 var _ ContextBuilder = (*DefaultContextBuilder)(nil)
 var _ ContextBuilder = (*SearchOnlyContextBuilder)(nil)
 var _ ContextBuilder = (*CachedContextBuilder)(nil)
-var _ ContextCache = (*InMemoryContextCache)(nil)
@@ -0,0 +1,108 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func loadTestdata(t *testing.T) (*Fixture, *GoldenSet) {
+	t.Helper()
+	fixture, err := LoadFixture("testdata/fixture.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := LoadGoldenSet("testdata/golden.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fixture, set
+}
+
+func TestRankingMetrics(t *testing.T) {
+	ranked := []string{"noise", "a", "b", "noise2", "c"}
+	expected := idSet([]string{"a", "c", "missing"})
+
+	if got := recallAtK(ranked, expected, 3); got != 1.0/3 {
+		t.Fatalf("recall@3 = %v", got)
+	}
+	if got := recallAtK(ranked, expected, 5); got != 2.0/3 {
+		t.Fatalf("recall@5 = %v", got)
+	}
+	if got := reciprocalRank(ranked, expected); got != 0.5 {
+		t.Fatalf("reciprocal rank = %v", got)
+	}
+	// Relevant at ranks 2 and 5: (1/2 + 2/5) / 2.
+	if got := round(contextPrecision(ranked, expected, 5)); got != 0.45 {
+		t.Fatalf("context precision = %v", got)
+	}
+	if got := round(answerF1("Alice owns billing [1].", "Billing is owned by Alice")); got != 0.5 {
+		t.Fatalf("answer F1 = %v", got)
+	}
+	if got := round(answerF1("Alice owns billing [1, 2][3,4].", "Billing is owned by Alice")); got != 0.5 {
+		t.Fatalf("answer F1 with grouped markers = %v", got)
+	}
+}
+
+func TestRunAgainstFixture(t *testing.T) {
+	fixture, set := loadTestdata(t)
+	report, err := Run(context.Background(), fixture.Service(nil), set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Summary.Questions != 3 || report.Summary.Errors != 0 {
+		t.Fatalf("summary = %+v", report.Summary)
+	}
+	byID := make(map[string]QuestionResult)
+	for _, q := range report.Questions {
+		byID[q.ID] = q
+	}
+	rollback := byID["rollback-cause"]
+	if rollback.RecallAtK != 1 || rollback.ReciprocalRank != 1 || rollback.CitationAccuracy == nil || rollback.AnswerF1 == nil {
+		t.Fatalf("rollback-cause = %+v", rollback)
+	}
+	if report.Summary.CitationAccuracy == nil || report.Summary.AnswerF1 == nil {
+		t.Fatalf("answer metrics missing from summary: %+v", report.Summary)
+	}
+
+	// Identical runs produce identical reports.
+	var first, second bytes.Buffer
+	if err := WriteReport(&first, report); err != nil {
+		t.Fatal(err)
+	}
+	again, _ := Run(context.Background(), fixture.Service(nil), set)
+	_ = WriteReport(&second, again)
+	if first.String() != second.String() {
+		t.Fatalf("reports differ:\n%s\n%s", first.String(), second.String())
+	}
+	if deltas := Compare(report, again, 0); len(deltas) != 0 {
+		t.Fatalf("deltas = %+v", deltas)
+	}
+}
+
+func TestCompareFlagsRetrievalRegression(t *testing.T) {
+	fixture, set := loadTestdata(t)
+	baseline, err := Run(context.Background(), fixture.Service(nil), set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One seed, expanded only along assignments, loses the PRs behind the rollback.
+	narrowed := *set
+	narrowed.Retrieval.TopK = 1
+	narrowed.Retrieval.EdgeTypes = []string{"ASSIGNED_TO"}
+	current, err := Run(context.Background(), fixture.Service(nil), &narrowed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var regressed bool
+	for _, d := range Compare(baseline, current, 0.001) {
+		if d.Question == "" && d.Metric == "recallAtK" && d.Regression() {
+			regressed = true
+		}
+	}
+	if !regressed {
+		t.Fatalf("expected summary recall regression: baseline %+v, current %+v", baseline.Summary, current.Summary)
+	}
+}
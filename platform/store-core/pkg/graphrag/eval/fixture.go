@@ -0,0 +1,273 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nucleus/store-core/pkg/graphrag"
+)
+
+// ===================================================
+// Fixture graph
+// An in-memory knowledge graph and keyword searcher loaded from YAML, so
+// golden sets can run in CI without Postgres or the KG service.
+// ===================================================
+
+// Fixture is a small tenant graph.
+type Fixture struct {
+	TenantID string        `yaml:"tenantId"`
+	Nodes    []FixtureNode `yaml:"nodes"`
+	Edges    []FixtureEdge `yaml:"edges"`
+
+	byID map[string]*FixtureNode
+}
+
+// FixtureNode is a graph node that is also indexed for search.
+type FixtureNode struct {
+	ID         string            `yaml:"id"`
+	Type       string            `yaml:"type"` // entity kind, matched by entityKinds filters
+	Name       string            `yaml:"name"`
+	Text       string            `yaml:"text,omitempty"`
+	Properties map[string]string `yaml:"properties,omitempty"`
+}
+
+// FixtureEdge connects two fixture nodes.
+type FixtureEdge struct {
+	From   string  `yaml:"from"`
+	To     string  `yaml:"to"`
+	Type   string  `yaml:"type"`
+	Weight float64 `yaml:"weight,omitempty"`
+}
+
+// LoadFixture reads and validates a fixture file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := ParseFixture(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// ParseFixture parses and validates a YAML fixture.
+func ParseFixture(data []byte) (*Fixture, error) {
+	var f Fixture
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	f.byID = make(map[string]*FixtureNode, len(f.Nodes))
+	for i := range f.Nodes {
+		n := &f.Nodes[i]
+		if n.ID == "" {
+			return nil, fmt.Errorf("node %d: id is required", i+1)
+		}
+		if f.byID[n.ID] != nil {
+			return nil, fmt.Errorf("duplicate node id %q", n.ID)
+		}
+		f.byID[n.ID] = n
+	}
+	for i, e := range f.Edges {
+		if f.byID[e.From] == nil || f.byID[e.To] == nil {
+			return nil, fmt.Errorf("edge %d: unknown node in %s -> %s", i+1, e.From, e.To)
+		}
+	}
+	return &f, nil
+}
+
+// Service returns a GraphRAG service over the fixture: keyword search for
+// seeds and the fixture graph for expansion. A nil llm gives the service's
+// deterministic mock answers.
+func (f *Fixture) Service(llm graphrag.LLMProvider) *graphrag.Service {
+	expander := graphrag.NewDefaultExpander(f)
+	builder := graphrag.NewDefaultContextBuilder(f, nil, expander, nil)
+	return graphrag.NewService(builder, expander, nil, nil, llm)
+}
+
+func (f *Fixture) checkTenant(tenantID string) error {
+	if f.TenantID != "" && tenantID != f.TenantID {
+		return fmt.Errorf("fixture holds tenant %q, not %q", f.TenantID, tenantID)
+	}
+	return nil
+}
+
+// Search scores nodes by the query terms their name and text share. Each
+// matched term halves the distance to 1, so one match scores 0.5.
+func (f *Fixture) Search(_ context.Context, tenantID, query string, _ []float32, config graphrag.HybridSearchConfig) ([]graphrag.EntityMatch, error) {
+	if err := f.checkTenant(tenantID); err != nil {
+		return nil, err
+	}
+	terms := searchTerms(query)
+	var kinds map[string]bool
+	if len(config.EntityKinds) > 0 {
+		kinds = idSet(config.EntityKinds)
+	}
+
+	var matches []graphrag.EntityMatch
+	for _, n := range f.Nodes {
+		if kinds != nil && !kinds[n.Type] {
+			continue
+		}
+		doc := searchTerms(n.Name + " " + n.Text)
+		matched := 0
+		for t := range terms {
+			if doc[t] {
+				matched++
+			}
+		}
+		if matched == 0 {
+			continue
+		}
+		score := float32(1 - math.Pow(0.5, float64(matched)))
+		if config.MinScore > 0 && score < config.MinScore {
+			continue
+		}
+		matches = append(matches, graphrag.EntityMatch{
+			ID:          n.ID,
+			Type:        n.Type,
+			Name:        n.Name,
+			Description: n.Text,
+			Content:     n.Text,
+			Score:       score,
+			Properties:  n.Properties,
+		})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if config.TopK > 0 && len(matches) > config.TopK {
+		matches = matches[:config.TopK]
+	}
+	return matches, nil
+}
+
+// searchTerms lowercases text into a term set, dropping short words and a
+// trailing plural "s" so "designs" finds "design".
+func searchTerms(text string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, t := range tokenPattern.FindAllString(strings.ToLower(text), -1) {
+		if len(t) < 3 || stopWords[t] {
+			continue
+		}
+		if len(t) > 3 && strings.HasSuffix(t, "s") && !strings.HasSuffix(t, "ss") {
+			t = strings.TrimSuffix(t, "s")
+		}
+		terms[t] = true
+	}
+	return terms
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true,
+	"which": true, "what": true, "who": true, "how": true, "are": true, "was": true,
+	"does": true, "did": true, "from": true, "into": true, "about": true, "its": true,
+}
+
+func (f *Fixture) graphNode(n *FixtureNode) graphrag.GraphNode {
+	props := map[string]string{"name": n.Name}
+	for k, v := range n.Properties {
+		props[k] = v
+	}
+	return graphrag.GraphNode{ID: n.ID, Type: n.Type, Properties: props}
+}
+
+// GetNode implements graphrag.KGClient.
+func (f *Fixture) GetNode(_ context.Context, tenantID, nodeID string) (*graphrag.GraphNode, error) {
+	if err := f.checkTenant(tenantID); err != nil {
+		return nil, err
+	}
+	n := f.byID[nodeID]
+	if n == nil {
+		return nil, fmt.Errorf("node %s not found", nodeID)
+	}
+	node := f.graphNode(n)
+	return &node, nil
+}
+
+// ListNeighbors implements graphrag.KGClient.
+func (f *Fixture) ListNeighbors(_ context.Context, tenantID, nodeID string, edgeTypes []string, direction graphrag.EdgeDirection, limit int) ([]graphrag.GraphNode, []graphrag.GraphEdge, error) {
+	if err := f.checkTenant(tenantID); err != nil {
+		return nil, nil, err
+	}
+	var nodes []graphrag.GraphNode
+	var edges []graphrag.GraphEdge
+	for i, e := range f.Edges {
+		if !edgeTypeAllowed(e.Type, edgeTypes) {
+			continue
+		}
+		var other string
+		switch {
+		case e.From == nodeID && direction != graphrag.EdgeDirectionIncoming:
+			other = e.To
+		case e.To == nodeID && direction != graphrag.EdgeDirectionOutgoing:
+			other = e.From
+		default:
+			continue
+		}
+		if limit > 0 && len(nodes) >= limit {
+			break
+		}
+		nodes = append(nodes, f.graphNode(f.byID[other]))
+		edges = append(edges, f.graphEdge(i))
+	}
+	return nodes, edges, nil
+}
+
+// ListEdges implements graphrag.KGClient.
+func (f *Fixture) ListEdges(_ context.Context, tenantID, sourceID, targetID string, edgeTypes []string, limit int) ([]graphrag.GraphEdge, error) {
+	if err := f.checkTenant(tenantID); err != nil {
+		return nil, err
+	}
+	var edges []graphrag.GraphEdge
+	for i, e := range f.Edges {
+		if (sourceID != "" && e.From != sourceID) || (targetID != "" && e.To != targetID) || !edgeTypeAllowed(e.Type, edgeTypes) {
+			continue
+		}
+		if limit > 0 && len(edges) >= limit {
+			break
+		}
+		edges = append(edges, f.graphEdge(i))
+	}
+	return edges, nil
+}
+
+func (f *Fixture) graphEdge(i int) graphrag.GraphEdge {
+	e := f.Edges[i]
+	weight := e.Weight
+	if weight == 0 {
+		weight = 1
+	}
+	return graphrag.GraphEdge{
+		ID:        fmt.Sprintf("%s-%s-%s", e.From, e.Type, e.To),
+		Type:      e.Type,
+		FromID:    e.From,
+		ToID:      e.To,
+		Weight:    weight,
+		Direction: graphrag.EdgeDirectionOutgoing,
+	}
+}
+
+func edgeTypeAllowed(edgeType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, t := range allowed {
+		if t == edgeType {
+			return true
+		}
+	}
+	return false
+}
+
+// Ensure interface compliance
+var (
+	_ graphrag.HybridSearcher = (*Fixture)(nil)
+	_ graphrag.KGClient       = (*Fixture)(nil)
+	_ Target                  = (*graphrag.Service)(nil)
+)
@@ -0,0 +1,101 @@
+// Package eval measures GraphRAG retrieval and answer quality against golden
+// question sets, so changes to context building, expansion or hybrid weights
+// can be compared run over run.
+package eval
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultK is the cutoff for recall@k and context precision.
+const DefaultK = 10
+
+// GoldenSet is a YAML file of questions with the graph nodes a good context
+// should contain.
+type GoldenSet struct {
+	Name      string           `yaml:"name" json:"name"`
+	TenantID  string           `yaml:"tenantId" json:"tenantId"`
+	ProjectID string           `yaml:"projectId,omitempty" json:"projectId,omitempty"`
+	K         int              `yaml:"k,omitempty" json:"k"`
+	Retrieval RetrievalConfig  `yaml:"retrieval,omitempty" json:"retrieval"`
+	Questions []GoldenQuestion `yaml:"questions" json:"-"`
+}
+
+// GoldenQuestion is one evaluated question.
+type GoldenQuestion struct {
+	ID              string   `yaml:"id"`
+	Question        string   `yaml:"question"`
+	ExpectedNodeIDs []string `yaml:"expectedNodeIds"`
+	ReferenceAnswer string   `yaml:"referenceAnswer,omitempty"` // scored with token F1 when answers are generated
+	Tags            []string `yaml:"tags,omitempty"`
+}
+
+// RetrievalConfig is the BuildContext configuration under test. Zero values
+// take the context builder's defaults.
+type RetrievalConfig struct {
+	TopK               int      `yaml:"topK,omitempty" json:"topK,omitempty"`
+	MinScore           float32  `yaml:"minScore,omitempty" json:"minScore,omitempty"`
+	VectorWeight       float32  `yaml:"vectorWeight,omitempty" json:"vectorWeight,omitempty"`
+	KeywordWeight      float32  `yaml:"keywordWeight,omitempty" json:"keywordWeight,omitempty"`
+	MaxHops            int      `yaml:"maxHops,omitempty" json:"maxHops,omitempty"`
+	MaxNodesPerHop     int      `yaml:"maxNodesPerHop,omitempty" json:"maxNodesPerHop,omitempty"`
+	MaxTotalNodes      int      `yaml:"maxTotalNodes,omitempty" json:"maxTotalNodes,omitempty"`
+	EdgeTypes          []string `yaml:"edgeTypes,omitempty" json:"edgeTypes,omitempty"`
+	EntityKinds        []string `yaml:"entityKinds,omitempty" json:"entityKinds,omitempty"`
+	ProfileIDs         []string `yaml:"profileIds,omitempty" json:"profileIds,omitempty"`
+	IncludeCommunities bool     `yaml:"includeCommunities,omitempty" json:"includeCommunities,omitempty"`
+	// GenerateAnswers also runs GenerateAnswer, enabling citation accuracy
+	// and reference-answer scoring.
+	GenerateAnswers bool `yaml:"generateAnswers,omitempty" json:"generateAnswers,omitempty"`
+}
+
+// LoadGoldenSet reads and validates a golden set file.
+func LoadGoldenSet(path string) (*GoldenSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	set, err := ParseGoldenSet(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return set, nil
+}
+
+// ParseGoldenSet parses and validates a YAML golden set.
+func ParseGoldenSet(data []byte) (*GoldenSet, error) {
+	var set GoldenSet
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	if set.TenantID == "" {
+		return nil, fmt.Errorf("tenantId is required")
+	}
+	if len(set.Questions) == 0 {
+		return nil, fmt.Errorf("no questions")
+	}
+	if set.K <= 0 {
+		set.K = DefaultK
+	}
+	seen := make(map[string]bool, len(set.Questions))
+	for i, q := range set.Questions {
+		if q.ID == "" {
+			q.ID = fmt.Sprintf("q%d", i+1)
+			set.Questions[i].ID = q.ID
+		}
+		if seen[q.ID] {
+			return nil, fmt.Errorf("duplicate question id %q", q.ID)
+		}
+		seen[q.ID] = true
+		if q.Question == "" {
+			return nil, fmt.Errorf("question %s: question is required", q.ID)
+		}
+		if len(q.ExpectedNodeIDs) == 0 {
+			return nil, fmt.Errorf("question %s: expectedNodeIds is required", q.ID)
+		}
+	}
+	return &set, nil
+}
@@ -0,0 +1,152 @@
+package eval
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nucleus/store-core/pkg/graphrag"
+)
+
+// rankedNodeIDs orders a context's nodes the way a reader meets them: seeds
+// in search order, then expanded nodes by hop distance.
+func rankedNodeIDs(ragCtx *graphrag.RAGContext) []string {
+	if ragCtx == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, s := range ragCtx.SeedEntities {
+		add(s.ID)
+	}
+	if ragCtx.ExpandedGraph != nil {
+		nodes := append([]graphrag.GraphNode(nil), ragCtx.ExpandedGraph.Nodes...)
+		sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].HopDistance < nodes[j].HopDistance })
+		for _, n := range nodes {
+			add(n.ID)
+		}
+	}
+	return ids
+}
+
+func idSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// recallAtK is the share of expected nodes found in the top k.
+func recallAtK(ranked []string, expected map[string]bool, k int) float64 {
+	if len(expected) == 0 {
+		return 0
+	}
+	found := 0
+	for i, id := range ranked {
+		if i >= k {
+			break
+		}
+		if expected[id] {
+			found++
+		}
+	}
+	return float64(found) / float64(len(expected))
+}
+
+// reciprocalRank is 1/rank of the first expected node, 0 if none is found.
+func reciprocalRank(ranked []string, expected map[string]bool) float64 {
+	for i, id := range ranked {
+		if expected[id] {
+			return 1 / float64(i+1)
+		}
+	}
+	return 0
+}
+
+// contextPrecision is rank-weighted precision over the top k: the mean of
+// precision@i at each position i holding an expected node. Relevant nodes
+// ranked below noise lower it.
+func contextPrecision(ranked []string, expected map[string]bool, k int) float64 {
+	var sum float64
+	relevant := 0
+	for i, id := range ranked {
+		if i >= k {
+			break
+		}
+		if expected[id] {
+			relevant++
+			sum += float64(relevant) / float64(i+1)
+		}
+	}
+	if relevant == 0 {
+		return 0
+	}
+	return sum / float64(relevant)
+}
+
+// citationAccuracy is the share of distinct cited sources that are expected
+// nodes. ok is false when the answer cites nothing.
+func citationAccuracy(citations []graphrag.Citation, expected map[string]bool) (float64, bool) {
+	cited := make(map[string]bool)
+	for _, c := range citations {
+		if c.SourceID != "" {
+			cited[c.SourceID] = true
+		}
+	}
+	if len(cited) == 0 {
+		return 0, false
+	}
+	correct := 0
+	for id := range cited {
+		if expected[id] {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(cited)), true
+}
+
+var (
+	tokenPattern  = regexp.MustCompile(`[\p{L}\p{N}]+`)
+	markerPattern = regexp.MustCompile(`\[\d+(?:\s*,\s*\d+)*\]`)
+)
+
+// answerF1 is the token-overlap F1 between an answer and the reference.
+func answerF1(answer, reference string) float64 {
+	tokens := func(s string) map[string]int {
+		counts := make(map[string]int)
+		for _, t := range tokenPattern.FindAllString(strings.ToLower(markerPattern.ReplaceAllString(s, "")), -1) {
+			counts[t]++
+		}
+		return counts
+	}
+	got, want := tokens(answer), tokens(reference)
+	var overlap, gotN, wantN int
+	for t, n := range got {
+		gotN += n
+		if m := want[t]; m > 0 {
+			overlap += min(n, m)
+		}
+	}
+	for _, n := range want {
+		wantN += n
+	}
+	if overlap == 0 {
+		return 0
+	}
+	precision := float64(overlap) / float64(gotN)
+	recall := float64(overlap) / float64(wantN)
+	return 2 * precision * recall / (precision + recall)
+}
+
+// round keeps reports stable across platforms and readable in diffs.
+func round(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
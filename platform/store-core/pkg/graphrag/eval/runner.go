@@ -0,0 +1,264 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/nucleus/store-core/pkg/graphrag"
+)
+
+// Target is the GraphRAG surface under evaluation; *graphrag.Service
+// satisfies it.
+type Target interface {
+	BuildContext(ctx context.Context, req *graphrag.BuildContextRequest) (*graphrag.BuildContextResponse, error)
+	GenerateAnswer(ctx context.Context, req *graphrag.GenerateAnswerRequest) (*graphrag.GroundedAnswer, error)
+}
+
+// Report is the result of one run. It holds no timings or timestamps so two
+// reports of the same configuration diff cleanly.
+type Report struct {
+	Set       string           `json:"set"`
+	TenantID  string           `json:"tenantId"`
+	K         int              `json:"k"`
+	Retrieval RetrievalConfig  `json:"retrieval"`
+	Summary   Summary          `json:"summary"`
+	Questions []QuestionResult `json:"questions"`
+}
+
+// Summary averages question metrics. Questions that failed score zero for
+// retrieval; citation and answer metrics average only where they apply.
+type Summary struct {
+	Questions        int      `json:"questions"`
+	Errors           int      `json:"errors"`
+	RecallAtK        float64  `json:"recallAtK"`
+	MRR              float64  `json:"mrr"`
+	ContextPrecision float64  `json:"contextPrecision"`
+	CitationAccuracy *float64 `json:"citationAccuracy,omitempty"`
+	AnswerF1         *float64 `json:"answerF1,omitempty"`
+}
+
+// QuestionResult holds the metrics for one golden question.
+type QuestionResult struct {
+	ID               string   `json:"id"`
+	Question         string   `json:"question"`
+	Tags             []string `json:"tags,omitempty"`
+	RecallAtK        float64  `json:"recallAtK"`
+	ReciprocalRank   float64  `json:"reciprocalRank"`
+	ContextPrecision float64  `json:"contextPrecision"`
+	CitationAccuracy *float64 `json:"citationAccuracy,omitempty"`
+	AnswerF1         *float64 `json:"answerF1,omitempty"`
+	Retrieved        []string `json:"retrieved"` // top k node IDs
+	Missing          []string `json:"missing,omitempty"`
+	Cited            []string `json:"cited,omitempty"` // distinct, in citation order
+	Error            string   `json:"error,omitempty"`
+}
+
+// Run evaluates every question in set against target.
+func Run(ctx context.Context, target Target, set *GoldenSet) (*Report, error) {
+	if target == nil {
+		return nil, fmt.Errorf("target is required")
+	}
+	k := set.K
+	if k <= 0 {
+		k = DefaultK
+	}
+	report := &Report{Set: set.Name, TenantID: set.TenantID, K: k, Retrieval: set.Retrieval}
+	for _, q := range set.Questions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report.Questions = append(report.Questions, runQuestion(ctx, target, set, k, q))
+	}
+	report.Summary = summarize(report.Questions)
+	return report, nil
+}
+
+func runQuestion(ctx context.Context, target Target, set *GoldenSet, k int, q GoldenQuestion) QuestionResult {
+	res := QuestionResult{ID: q.ID, Question: q.Question, Tags: q.Tags, Retrieved: []string{}}
+	cfg := set.Retrieval
+	resp, err := target.BuildContext(ctx, &graphrag.BuildContextRequest{
+		TenantID:           set.TenantID,
+		ProjectID:          set.ProjectID,
+		Query:              q.Question,
+		TopK:               cfg.TopK,
+		MinScore:           cfg.MinScore,
+		VectorWeight:       cfg.VectorWeight,
+		KeywordWeight:      cfg.KeywordWeight,
+		MaxHops:            cfg.MaxHops,
+		MaxNodesPerHop:     cfg.MaxNodesPerHop,
+		MaxTotalNodes:      cfg.MaxTotalNodes,
+		EdgeTypes:          cfg.EdgeTypes,
+		EntityKinds:        cfg.EntityKinds,
+		ProfileIDs:         cfg.ProfileIDs,
+		IncludeCommunities: cfg.IncludeCommunities,
+	})
+	if err != nil {
+		res.Error = err.Error()
+		res.Missing = append([]string(nil), q.ExpectedNodeIDs...)
+		return res
+	}
+
+	expected := idSet(q.ExpectedNodeIDs)
+	ranked := rankedNodeIDs(resp.Context)
+	res.RecallAtK = round(recallAtK(ranked, expected, k))
+	res.ReciprocalRank = round(reciprocalRank(ranked, expected))
+	res.ContextPrecision = round(contextPrecision(ranked, expected, k))
+	if len(ranked) > k {
+		ranked = ranked[:k]
+	}
+	res.Retrieved = append(res.Retrieved, ranked...)
+	top := idSet(ranked)
+	for _, id := range q.ExpectedNodeIDs {
+		if !top[id] {
+			res.Missing = append(res.Missing, id)
+		}
+	}
+
+	if !cfg.GenerateAnswers {
+		return res
+	}
+	answer, err := target.GenerateAnswer(ctx, &graphrag.GenerateAnswerRequest{
+		TenantID: set.TenantID,
+		Query:    q.Question,
+		Context:  resp.Context,
+	})
+	if err != nil {
+		res.Error = fmt.Sprintf("generate answer: %v", err)
+		return res
+	}
+	if acc, ok := citationAccuracy(answer.Citations, expected); ok {
+		acc = round(acc)
+		res.CitationAccuracy = &acc
+	}
+	cited := make(map[string]bool)
+	for _, c := range answer.Citations {
+		if !cited[c.SourceID] {
+			cited[c.SourceID] = true
+			res.Cited = append(res.Cited, c.SourceID)
+		}
+	}
+	if q.ReferenceAnswer != "" {
+		f1 := round(answerF1(answer.Answer, q.ReferenceAnswer))
+		res.AnswerF1 = &f1
+	}
+	return res
+}
+
+func summarize(results []QuestionResult) Summary {
+	s := Summary{Questions: len(results)}
+	if len(results) == 0 {
+		return s
+	}
+	var citations, answers []float64
+	for _, r := range results {
+		if r.Error != "" {
+			s.Errors++
+		}
+		s.RecallAtK += r.RecallAtK
+		s.MRR += r.ReciprocalRank
+		s.ContextPrecision += r.ContextPrecision
+		if r.CitationAccuracy != nil {
+			citations = append(citations, *r.CitationAccuracy)
+		}
+		if r.AnswerF1 != nil {
+			answers = append(answers, *r.AnswerF1)
+		}
+	}
+	n := float64(len(results))
+	s.RecallAtK = round(s.RecallAtK / n)
+	s.MRR = round(s.MRR / n)
+	s.ContextPrecision = round(s.ContextPrecision / n)
+	s.CitationAccuracy = mean(citations)
+	s.AnswerF1 = mean(answers)
+	return s
+}
+
+func mean(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	m := round(sum / float64(len(values)))
+	return &m
+}
+
+// WriteReport writes report as indented JSON.
+func WriteReport(w io.Writer, report *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// ReadReport reads a report written by WriteReport.
+func ReadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// Delta is a metric that changed between two reports. Question is empty for
+// summary metrics.
+type Delta struct {
+	Question string  `json:"question,omitempty"`
+	Metric   string  `json:"metric"`
+	Before   float64 `json:"before"`
+	After    float64 `json:"after"`
+}
+
+// Regression reports whether the metric got worse.
+func (d Delta) Regression() bool { return d.After < d.Before }
+
+// Compare lists metrics that moved by more than tolerance between baseline
+// and current, summary first, then per question in ID order. Questions
+// present in only one report are skipped.
+func Compare(baseline, current *Report, tolerance float64) []Delta {
+	var deltas []Delta
+	add := func(question, metric string, before, after float64) {
+		if after-before > tolerance || before-after > tolerance {
+			deltas = append(deltas, Delta{Question: question, Metric: metric, Before: before, After: after})
+		}
+	}
+	addOptional := func(question, metric string, before, after *float64) {
+		if before != nil && after != nil {
+			add(question, metric, *before, *after)
+		}
+	}
+
+	add("", "recallAtK", baseline.Summary.RecallAtK, current.Summary.RecallAtK)
+	add("", "mrr", baseline.Summary.MRR, current.Summary.MRR)
+	add("", "contextPrecision", baseline.Summary.ContextPrecision, current.Summary.ContextPrecision)
+	addOptional("", "citationAccuracy", baseline.Summary.CitationAccuracy, current.Summary.CitationAccuracy)
+	addOptional("", "answerF1", baseline.Summary.AnswerF1, current.Summary.AnswerF1)
+
+	before := make(map[string]QuestionResult, len(baseline.Questions))
+	for _, q := range baseline.Questions {
+		before[q.ID] = q
+	}
+	after := append([]QuestionResult(nil), current.Questions...)
+	sort.Slice(after, func(i, j int) bool { return after[i].ID < after[j].ID })
+	for _, q := range after {
+		b, ok := before[q.ID]
+		if !ok {
+			continue
+		}
+		add(q.ID, "recallAtK", b.RecallAtK, q.RecallAtK)
+		add(q.ID, "reciprocalRank", b.ReciprocalRank, q.ReciprocalRank)
+		add(q.ID, "contextPrecision", b.ContextPrecision, q.ContextPrecision)
+		addOptional(q.ID, "citationAccuracy", b.CitationAccuracy, q.CitationAccuracy)
+		addOptional(q.ID, "answerF1", b.AnswerF1, q.AnswerF1)
+	}
+	return deltas
+}
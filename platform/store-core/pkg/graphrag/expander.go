@@ -3,6 +3,7 @@ package graphrag
 import (
 	"context"
 	"fmt"
+	"sort"
 )
 
 // Note: Direction filtering is passed to KGClient.ListNeighbors
@@ -58,6 +59,15 @@ func (e *DefaultExpander) Expand(
 		config.MaxTotalNodes = 100
 	}
 
+	// Prefer a single server-side traversal when the KG client supports it;
+	// fall back to client-side BFS if the call fails.
+	if traverser, ok := e.kgClient.(KGTraverser); ok {
+		nodes, edges, err := traverser.Traverse(ctx, tenantID, seedIDs, config.EdgeTypes, config.Direction, config.MaxHops, config.MaxTotalNodes)
+		if err == nil {
+			return buildExpansion(nodes, edges, config), nil
+		}
+	}
+
 	result := &GraphExpansion{
 		Nodes:      make([]GraphNode, 0),
 		Edges:      make([]GraphEdge, 0),
@@ -172,6 +182,69 @@ func (e *DefaultExpander) Expand(
 	return result, nil
 }
 
+// buildExpansion applies per-hop and total limits to a server-side traversal
+// result and keeps only edges whose endpoints survived. Nodes are taken hop
+// by hop, and a node beyond the seeds is only kept while an edge links it to
+// a kept node one hop closer, so dropping a node also drops what was only
+// reachable through it.
+func buildExpansion(nodes []GraphNode, edges []GraphEdge, config ExpansionConfig) *GraphExpansion {
+	result := &GraphExpansion{
+		Nodes:      make([]GraphNode, 0, len(nodes)),
+		Edges:      make([]GraphEdge, 0, len(edges)),
+		NodesByHop: make(map[int][]string),
+	}
+	// parents maps a node to the nodes it can be reached from in one step.
+	parents := make(map[string][]string)
+	for _, edge := range edges {
+		if config.Direction != EdgeDirectionIncoming {
+			parents[edge.ToID] = append(parents[edge.ToID], edge.FromID)
+		}
+		if config.Direction != EdgeDirectionOutgoing {
+			parents[edge.FromID] = append(parents[edge.FromID], edge.ToID)
+		}
+	}
+	ordered := append([]GraphNode(nil), nodes...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].HopDistance < ordered[j].HopDistance })
+
+	kept := make(map[string]int, len(nodes)) // node ID -> hop
+	reachable := func(n GraphNode) bool {
+		for _, p := range parents[n.ID] {
+			if hop, ok := kept[p]; ok && hop == n.HopDistance-1 {
+				return true
+			}
+		}
+		return false
+	}
+	for _, n := range ordered {
+		if _, ok := kept[n.ID]; ok || n.HopDistance > config.MaxHops {
+			continue
+		}
+		if result.TotalNodes >= config.MaxTotalNodes {
+			break
+		}
+		// Seeds (hop 0) are never dropped by the per-hop limit.
+		if n.HopDistance > 0 && (len(result.NodesByHop[n.HopDistance]) >= config.MaxNodesPerHop || !reachable(n)) {
+			continue
+		}
+		kept[n.ID] = n.HopDistance
+		result.Nodes = append(result.Nodes, n)
+		result.NodesByHop[n.HopDistance] = append(result.NodesByHop[n.HopDistance], n.ID)
+		result.TotalNodes++
+		if n.HopDistance > result.MaxHops {
+			result.MaxHops = n.HopDistance
+		}
+	}
+	for _, edge := range edges {
+		_, from := kept[edge.FromID]
+		_, to := kept[edge.ToID]
+		if from && to {
+			result.Edges = append(result.Edges, edge)
+			result.TotalEdges++
+		}
+	}
+	return result
+}
+
 // GetNeighbors gets immediate neighbors of a node.
 func (e *DefaultExpander) GetNeighbors(
 	ctx context.Context,
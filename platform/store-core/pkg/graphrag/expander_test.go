@@ -0,0 +1,70 @@
+package graphrag
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nucleus/ucl-core/pkg/kgpb"
+	"google.golang.org/grpc"
+)
+
+func TestBuildExpansionDropsNodesBehindDroppedParents(t *testing.T) {
+	nodes := []GraphNode{
+		{ID: "c", HopDistance: 2}, {ID: "d", HopDistance: 2},
+		{ID: "s", HopDistance: 0}, {ID: "a", HopDistance: 1}, {ID: "b", HopDistance: 1},
+	}
+	edges := []GraphEdge{
+		{ID: "sa", FromID: "s", ToID: "a"}, {ID: "sb", FromID: "s", ToID: "b"},
+		{ID: "bc", FromID: "b", ToID: "c"}, {ID: "ad", FromID: "a", ToID: "d"},
+	}
+	got := buildExpansion(nodes, edges, ExpansionConfig{MaxHops: 3, MaxNodesPerHop: 1, MaxTotalNodes: 10, Direction: EdgeDirectionOutgoing})
+	if ids := fmt.Sprint(got.NodesByHop[0], got.NodesByHop[1], got.NodesByHop[2]); ids != "[s] [a] [d]" {
+		t.Fatalf("nodes by hop = %s, want [s] [a] [d]", ids)
+	}
+	if got.TotalEdges != 2 || got.MaxHops != 2 {
+		t.Fatalf("expected edges sa and ad up to hop 2, got %+v", got.Edges)
+	}
+
+	// Against the edge direction, a is not reachable from s.
+	got = buildExpansion(nodes, edges, ExpansionConfig{MaxHops: 3, MaxNodesPerHop: 5, MaxTotalNodes: 10, Direction: EdgeDirectionIncoming})
+	if got.TotalNodes != 1 {
+		t.Fatalf("expected only the seed for an incoming walk, got %v", got.NodesByHop)
+	}
+}
+
+// traverseKg answers Traverse from a fixed response and records requests.
+type traverseKg struct {
+	kgpb.KgServiceClient
+	resp *kgpb.TraverseResponse
+	reqs []*kgpb.TraverseRequest
+}
+
+func (k *traverseKg) Traverse(_ context.Context, in *kgpb.TraverseRequest, _ ...grpc.CallOption) (*kgpb.TraverseResponse, error) {
+	k.reqs = append(k.reqs, in)
+	return k.resp, nil
+}
+
+func TestDefaultExpanderUsesKGServiceTraverse(t *testing.T) {
+	kg := &traverseKg{resp: &kgpb.TraverseResponse{
+		Nodes: []*kgpb.TraversedNode{
+			{Node: &kgpb.Node{Id: "pr", Type: "github.pr"}, Hop: 0},
+			{Node: &kgpb.Node{Id: "commit", Type: "github.commit"}, Hop: 1},
+		},
+		Edges: []*kgpb.Edge{{Id: "e1", Type: "CONTAINS", FromId: "pr", ToId: "commit"}},
+	}}
+	exp := NewDefaultExpander(NewKGServiceAdapter(kg, "p1"))
+	got, err := exp.Expand(context.Background(), "t1", []string{"pr"}, ExpansionConfig{MaxHops: 2, Direction: EdgeDirectionOutgoing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kg.reqs) != 1 {
+		t.Fatalf("expected one Traverse call, got %d", len(kg.reqs))
+	}
+	if req := kg.reqs[0]; req.TenantId != "t1" || req.ProjectId != "p1" || req.Direction != "out" || req.MaxHops != 2 || req.MaxNodes != 100 {
+		t.Fatalf("unexpected traverse request %+v", req)
+	}
+	if got.TotalNodes != 2 || got.TotalEdges != 1 || got.NodesByHop[1][0] != "commit" {
+		t.Fatalf("unexpected expansion %+v", got)
+	}
+}
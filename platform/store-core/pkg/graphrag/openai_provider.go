@@ -1,6 +1,7 @@
 package graphrag
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -8,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -17,6 +19,8 @@ import (
 // ===================================================
 
 // OpenAIProvider implements LLMProvider using OpenAI's chat completion API.
+// New code should prefer llm.NewGraphRAGProvider, which also supports
+// Anthropic and OpenAI-compatible servers with retries.
 type OpenAIProvider struct {
 	apiKey  string
 	baseURL string
@@ -44,6 +48,11 @@ func (p *OpenAIProvider) Name() string {
 	return "openai"
 }
 
+// DefaultModel returns the model used when options name none.
+func (p *OpenAIProvider) DefaultModel() string {
+	return "gpt-4o-mini"
+}
+
 // Complete sends a prompt to OpenAI and returns the completion.
 func (p *OpenAIProvider) Complete(ctx context.Context, prompt string, options LLMCompletionOptions) (string, error) {
 	model := options.Model
@@ -106,12 +115,109 @@ func (p *OpenAIProvider) Complete(ctx context.Context, prompt string, options LL
 	return parsed.Choices[0].Message.Content, nil
 }
 
+// CompleteStream streams the completion over server-sent events, calling
+// onDelta for each content delta.
+func (p *OpenAIProvider) CompleteStream(ctx context.Context, prompt string, options LLMCompletionOptions, onDelta func(string) error) (string, error) {
+	model := options.Model
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	maxTokens := options.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 1024
+	}
+	temp := options.Temperature
+	if temp <= 0 {
+		temp = 0.3
+	}
+
+	messages := []openAIMessage{}
+	if options.SystemPrompt != "" {
+		messages = append(messages, openAIMessage{Role: "system", Content: options.SystemPrompt})
+	}
+	messages = append(messages, openAIMessage{Role: "user", Content: prompt})
+
+	data, err := json.Marshal(openAIChatRequest{
+		Model:       model,
+		Messages:    messages,
+		Temperature: float64(temp),
+		MaxTokens:   maxTokens,
+		Stream:      true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	// The client timeout would cut long streams short; rely on ctx instead.
+	client := *p.client
+	client.Timeout = 0
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("openai error status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var full strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "[DONE]" {
+			break
+		}
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			return "", fmt.Errorf("failed to parse stream chunk: %w", err)
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := chunk.Choices[0].Delta.Content
+		full.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return "", err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("stream read failed: %w", err)
+	}
+	if full.Len() == 0 {
+		return "", fmt.Errorf("openai returned empty content")
+	}
+	return full.String(), nil
+}
+
 // OpenAI API types
 type openAIChatRequest struct {
 	Model       string          `json:"model"`
 	Messages    []openAIMessage `json:"messages"`
 	Temperature float64         `json:"temperature,omitempty"`
 	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Stream      bool            `json:"stream,omitempty"`
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
 }
 
 type openAIMessage struct {
@@ -127,5 +233,7 @@ type openAIChatResponse struct {
 	} `json:"choices"`
 }
 
-// Ensure OpenAIProvider implements LLMProvider
+// Ensure OpenAIProvider implements LLMProvider and StreamingLLMProvider
 var _ LLMProvider = (*OpenAIProvider)(nil)
+var _ ModelDefaulter = (*OpenAIProvider)(nil)
+var _ StreamingLLMProvider = (*OpenAIProvider)(nil)
@@ -0,0 +1,305 @@
+package graphrag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ===================================================
+// Query Planner
+// Splits multi-part and comparative questions into
+// sub-queries with entity-kind hints before retrieval
+// ===================================================
+
+// maxSubQueries caps a plan, including the original query.
+const maxSubQueries = 4
+
+// SubQuery is one retrieval step of a plan.
+type SubQuery struct {
+	Query       string   `json:"query"`
+	EntityKinds []string `json:"entityKinds,omitempty"` // hint; dropped if it finds nothing
+	// Filled in by the context builder:
+	SeedIDs []string `json:"seedIds,omitempty"` // merged seeds this sub-query contributed
+	Error   string   `json:"error,omitempty"`
+}
+
+// QueryPlan records how a query was decomposed, for debugging retrieval.
+type QueryPlan struct {
+	Planner    string     `json:"planner"` // "rules" or "llm"
+	SubQueries []SubQuery `json:"subQueries"`
+}
+
+// QueryPlanner decomposes a query. Plans start with the original query so
+// decomposition only ever adds seeds.
+type QueryPlanner interface {
+	Plan(ctx context.Context, query string) (*QueryPlan, error)
+}
+
+// DefaultKindHints maps words in a question to the entity kinds they suggest.
+func DefaultKindHints() map[string][]string {
+	docs := []string{"doc.page", "doc.item", "doc.file"}
+	work := []string{"work.item"}
+	code := []string{"work.pr.diff", "code.file_chunk"}
+	return map[string][]string{
+		"confluence": docs, "design": docs, "designs": docs, "doc": docs, "docs": docs,
+		"document": docs, "documents": docs, "page": docs, "pages": docs, "wiki": docs,
+		"spec": docs, "specs": docs, "onedrive": docs, "runbook": docs, "runbooks": docs,
+		"jira": work, "ticket": work, "tickets": work, "issue": work, "issues": work,
+		"bug": work, "bugs": work, "epic": work, "epics": work, "story": work, "stories": work,
+		"github": code, "pr": code, "prs": code, "pull": code, "commit": code, "commits": code,
+		"code": code, "repo": code, "repository": code,
+	}
+}
+
+// RuleBasedPlanner is the deterministic planner used without an LLM. It
+// splits on question boundaries and relation phrases ("relate to", "caused
+// by", "vs") and derives entity-kind hints from source words.
+type RuleBasedPlanner struct {
+	KindHints map[string][]string
+}
+
+// NewRuleBasedPlanner creates a planner with DefaultKindHints.
+func NewRuleBasedPlanner() *RuleBasedPlanner {
+	return &RuleBasedPlanner{KindHints: DefaultKindHints()}
+}
+
+var (
+	questionBreak = regexp.MustCompile(`[?;]\s+`)
+	relationSplit = regexp.MustCompile(`(?i)\s+(?:relate[sd]? to|related to|linked to|connected to|referenced by|referencing|mentioned in|that mention|caused by|blocked by|compared (?:to|with)|vs\.?|versus)\s+`)
+	andSplit      = regexp.MustCompile(`(?i)\s+(?:and|or)\s+`)
+	leadingWords  = regexp.MustCompile(`(?i)^(?:(?:which|what|who|whom|where|when|how|why|are|is|do|does|did|all|the|of|to|compare|list|show|find)\s+)+`)
+	wordPattern   = regexp.MustCompile(`[a-z0-9]+`)
+)
+
+// Plan decomposes query.
+func (p *RuleBasedPlanner) Plan(_ context.Context, query string) (*QueryPlan, error) {
+	plan := &QueryPlan{Planner: "rules", SubQueries: []SubQuery{{Query: query}}}
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(query)): true}
+
+	var fragments []string
+	for _, part := range questionBreak.Split(strings.TrimSpace(query), -1) {
+		for _, frag := range relationSplit.Split(part, -1) {
+			// Split "A and B" only when both sides name a source, as in
+			// "Jira tickets and GitHub PRs"; otherwise "and" is part of the phrase.
+			sides := andSplit.Split(frag, -1)
+			allHinted := len(sides) > 1
+			for _, side := range sides {
+				if len(p.hints(side)) == 0 {
+					allHinted = false
+				}
+			}
+			if allHinted {
+				fragments = append(fragments, sides...)
+			} else {
+				fragments = append(fragments, frag)
+			}
+		}
+	}
+
+	for _, frag := range fragments {
+		frag = strings.TrimSpace(leadingWords.ReplaceAllString(strings.TrimSpace(frag), ""))
+		frag = strings.TrimRight(frag, "?.!, ")
+		key := strings.ToLower(frag)
+		if len(strings.Fields(frag)) < 2 && len(p.hints(frag)) == 0 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if len(plan.SubQueries) == maxSubQueries {
+			break
+		}
+		plan.SubQueries = append(plan.SubQueries, SubQuery{Query: frag, EntityKinds: p.hints(frag)})
+	}
+	// A single fragment without hints is just the original query again.
+	if len(plan.SubQueries) == 2 && len(plan.SubQueries[1].EntityKinds) == 0 {
+		plan.SubQueries = plan.SubQueries[:1]
+	}
+	return plan, nil
+}
+
+func (p *RuleBasedPlanner) hints(text string) []string {
+	set := map[string]bool{}
+	for _, w := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		for _, kind := range p.KindHints[w] {
+			set[kind] = true
+		}
+	}
+	kinds := make([]string, 0, len(set))
+	for k := range set {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// LLMQueryPlanner asks the LLM for sub-queries, falling back to the rule-based
+// planner when the call fails or returns nothing usable.
+type LLMQueryPlanner struct {
+	llm   LLMProvider
+	rules *RuleBasedPlanner
+}
+
+// NewLLMQueryPlanner creates an LLM-backed planner.
+func NewLLMQueryPlanner(llm LLMProvider) *LLMQueryPlanner {
+	return &LLMQueryPlanner{llm: llm, rules: NewRuleBasedPlanner()}
+}
+
+// Plan decomposes query.
+func (p *LLMQueryPlanner) Plan(ctx context.Context, query string) (*QueryPlan, error) {
+	if p.llm == nil {
+		return p.rules.Plan(ctx, query)
+	}
+	out, err := p.llm.Complete(ctx, buildPlannerPrompt(query, p.rules.KindHints), LLMCompletionOptions{
+		MaxTokens:    300,
+		Temperature:  0,
+		SystemPrompt: "You plan retrieval for a knowledge graph search. Reply with JSON only.",
+	})
+	if err != nil {
+		return p.rules.Plan(ctx, query)
+	}
+	plan, ok := parsePlannerOutput(query, out, p.rules.KindHints)
+	if !ok {
+		return p.rules.Plan(ctx, query)
+	}
+	return plan, nil
+}
+
+func buildPlannerPrompt(query string, hints map[string][]string) string {
+	kindSet := map[string]bool{}
+	for _, kinds := range hints {
+		for _, k := range kinds {
+			kindSet[k] = true
+		}
+	}
+	kinds := make([]string, 0, len(kindSet))
+	for k := range kindSet {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+
+	var b strings.Builder
+	b.WriteString("Split the question into at most 3 short search queries, one per entity set the answer needs. ")
+	b.WriteString("For each, optionally list entity kinds to search, chosen from: ")
+	b.WriteString(strings.Join(kinds, ", "))
+	b.WriteString(". A simple question needs a single query.\n")
+	b.WriteString(`Reply as {"subQueries":[{"query":"...","entityKinds":["..."]}]}` + "\n")
+	b.WriteString(fmt.Sprintf("Question: %s\n", query))
+	return b.String()
+}
+
+// parsePlannerOutput reads the LLM's JSON plan, keeping only known kinds.
+func parsePlannerOutput(query, out string, hints map[string][]string) (*QueryPlan, bool) {
+	start, end := strings.Index(out, "{"), strings.LastIndex(out, "}")
+	if start < 0 || end <= start {
+		return nil, false
+	}
+	var parsed struct {
+		SubQueries []SubQuery `json:"subQueries"`
+	}
+	if err := json.Unmarshal([]byte(out[start:end+1]), &parsed); err != nil {
+		return nil, false
+	}
+	known := map[string]bool{}
+	for _, kinds := range hints {
+		for _, k := range kinds {
+			known[k] = true
+		}
+	}
+	plan := &QueryPlan{Planner: "llm", SubQueries: []SubQuery{{Query: query}}}
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(query)): true}
+	for _, sq := range parsed.SubQueries {
+		q := strings.TrimSpace(sq.Query)
+		if q == "" || seen[strings.ToLower(q)] || len(plan.SubQueries) == maxSubQueries {
+			continue
+		}
+		seen[strings.ToLower(q)] = true
+		var kinds []string
+		for _, k := range sq.EntityKinds {
+			if known[k] {
+				kinds = append(kinds, k)
+			}
+		}
+		plan.SubQueries = append(plan.SubQueries, SubQuery{Query: q, EntityKinds: kinds})
+	}
+	return plan, len(parsed.SubQueries) > 0
+}
+
+// ===================================================
+// Planned retrieval
+// ===================================================
+
+// plannedSearch runs one hybrid search per sub-query and merges the results.
+// Merging is round-robin by rank so every sub-query contributes seeds before
+// any contributes its weaker matches; duplicates keep their best score.
+func (b *DefaultContextBuilder) plannedSearch(
+	ctx context.Context,
+	tenantID string,
+	plan *QueryPlan,
+	base HybridSearchConfig,
+	originalEmbedding []float32,
+) []EntityMatch {
+	results := make([][]EntityMatch, len(plan.SubQueries))
+	for i := range plan.SubQueries {
+		sq := &plan.SubQueries[i]
+		embedding := originalEmbedding
+		if i > 0 {
+			embedding = nil
+			if b.embeddingProvider != nil {
+				if emb, err := b.embeddingProvider.Embed(ctx, sq.Query); err == nil {
+					embedding = emb
+				}
+			}
+		}
+		cfg := base
+		if len(sq.EntityKinds) > 0 && len(base.EntityKinds) == 0 {
+			cfg.EntityKinds = sq.EntityKinds
+		}
+		matches, err := b.hybridSearcher.Search(ctx, tenantID, sq.Query, embedding, cfg)
+		if err == nil && len(matches) == 0 && len(cfg.EntityKinds) > len(base.EntityKinds) {
+			// The kind hint may not match how this tenant's data is indexed.
+			matches, err = b.hybridSearcher.Search(ctx, tenantID, sq.Query, embedding, base)
+		}
+		if err != nil {
+			sq.Error = err.Error()
+			continue
+		}
+		results[i] = matches
+	}
+
+	best := map[string]int{} // id -> index in merged
+	var merged []EntityMatch
+	for rank := 0; len(merged) < base.TopK; rank++ {
+		progressed := false
+		for i, matches := range results {
+			if rank >= len(matches) {
+				continue
+			}
+			progressed = true
+			m := matches[rank]
+			if idx, ok := best[m.ID]; ok {
+				if m.Score > merged[idx].Score {
+					merged[idx].Score = m.Score
+				}
+				continue
+			}
+			if len(merged) == base.TopK {
+				continue
+			}
+			best[m.ID] = len(merged)
+			merged = append(merged, m)
+			plan.SubQueries[i].SeedIDs = append(plan.SubQueries[i].SeedIDs, m.ID)
+		}
+		if !progressed {
+			break
+		}
+	}
+	return merged
+}
+
+var (
+	_ QueryPlanner = (*RuleBasedPlanner)(nil)
+	_ QueryPlanner = (*LLMQueryPlanner)(nil)
+)
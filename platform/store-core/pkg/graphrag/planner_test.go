@@ -0,0 +1,168 @@
+package graphrag
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// corpusSearcher scores entities by the query words their names share,
+// honouring the entity-kind filter, and records every search it serves.
+type corpusSearcher struct {
+	entities []EntityMatch
+
+	mu    sync.Mutex
+	calls []HybridSearchConfig
+}
+
+func (s *corpusSearcher) Search(_ context.Context, _, query string, _ []float32, config HybridSearchConfig) ([]EntityMatch, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, config)
+	s.mu.Unlock()
+
+	var out []EntityMatch
+	for _, e := range s.entities {
+		if len(config.EntityKinds) > 0 && !containsString(config.EntityKinds, e.Type) {
+			continue
+		}
+		var score float32
+		for _, q := range strings.Fields(strings.ToLower(query)) {
+			for _, w := range strings.Fields(strings.ToLower(e.Name)) {
+				if len(q) > 3 && (strings.HasPrefix(w, q) || strings.HasPrefix(q, w)) {
+					score++
+				}
+			}
+		}
+		if score > 0 {
+			e.Score = score
+			out = append(out, e)
+		}
+	}
+	// Highest score first, stable for equal scores.
+	for i := 1; i < len(out); i++ {
+		for j := i; j > 0 && out[j].Score > out[j-1].Score; j-- {
+			out[j], out[j-1] = out[j-1], out[j]
+		}
+	}
+	if len(out) > config.TopK {
+		out = out[:config.TopK]
+	}
+	return out, nil
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRuleBasedPlannerSplitsComparativeQuestion(t *testing.T) {
+	plan, err := NewRuleBasedPlanner().Plan(context.Background(), "which Confluence designs relate to the GitHub PRs that broke the release?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, sq := range plan.SubQueries {
+		got = append(got, sq.Query+" "+strings.Join(sq.EntityKinds, ","))
+	}
+	want := []string{
+		"which Confluence designs relate to the GitHub PRs that broke the release? ",
+		"Confluence designs doc.file,doc.item,doc.page",
+		"GitHub PRs that broke the release code.file_chunk,work.pr.diff",
+	}
+	if plan.Planner != "rules" || !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan = %q", got)
+	}
+
+	for _, q := range []string{"who owns the billing service?", "payments and refunds roadmap"} {
+		plan, _ := NewRuleBasedPlanner().Plan(context.Background(), q)
+		if len(plan.SubQueries) != 1 {
+			t.Fatalf("%q should not be decomposed: %+v", q, plan.SubQueries)
+		}
+	}
+}
+
+func TestPlannedContextMergesSubQuerySeeds(t *testing.T) {
+	searcher := &corpusSearcher{entities: []EntityMatch{
+		{ID: "jira-1", Type: "work.item", Name: "release checklist release notes"},
+		{ID: "jira-2", Type: "work.item", Name: "release blocked github outage"},
+		{ID: "conf-1", Type: "doc.page", Name: "signing service design"},
+		{ID: "pr-7", Type: "work.pr.diff", Name: "rotate signing keys broke release"},
+	}}
+	cfg := DefaultContextBuilderConfig()
+	cfg.TopK = 3
+	query := "which Confluence designs relate to the GitHub PRs that broke the release?"
+
+	// Without a planner the raw query favours tickets that repeat "release".
+	plain, err := NewDefaultContextBuilder(searcher, nil, nil, nil).BuildContext(context.Background(), "t1", query, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Plan != nil || containsString(seedIDs(plain), "conf-1") {
+		t.Fatalf("plain seeds = %v", seedIDs(plain))
+	}
+
+	builder := NewDefaultContextBuilder(searcher, nil, nil, nil).WithPlanner(NewRuleBasedPlanner())
+	ragCtx, err := builder.BuildContext(context.Background(), "t1", query, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seeds := seedIDs(ragCtx)
+	if len(seeds) != 3 || !containsString(seeds, "conf-1") || !containsString(seeds, "pr-7") {
+		t.Fatalf("planned seeds = %v", seeds)
+	}
+	plan := ragCtx.Plan
+	if plan == nil || len(plan.SubQueries) != 3 {
+		t.Fatalf("plan = %+v", plan)
+	}
+	if !reflect.DeepEqual(plan.SubQueries[1].SeedIDs, []string{"conf-1"}) {
+		t.Fatalf("design sub-query seeds = %v", plan.SubQueries[1].SeedIDs)
+	}
+	// pr-7 also ranks for the original query; it is merged once.
+	var contributed int
+	for _, sq := range plan.SubQueries {
+		contributed += len(sq.SeedIDs)
+	}
+	if contributed != len(seeds) {
+		t.Fatalf("seed attribution %+v does not match seeds %v", plan.SubQueries, seeds)
+	}
+}
+
+func TestPlannedSearchDropsKindHintThatFindsNothing(t *testing.T) {
+	searcher := &corpusSearcher{entities: []EntityMatch{
+		{ID: "wiki-1", Type: "doc.wiki", Name: "onboarding runbook"},
+		{ID: "jira-9", Type: "work.item", Name: "onboarding flaky"},
+	}}
+	builder := NewDefaultContextBuilder(searcher, nil, nil, nil).WithPlanner(NewRuleBasedPlanner())
+	cfg := DefaultContextBuilderConfig()
+	ragCtx, err := builder.BuildContext(context.Background(), "t1", "onboarding runbook vs flaky Jira tickets", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "runbook" hints doc kinds, but this tenant indexes wiki pages as doc.wiki.
+	if ragCtx.Plan == nil || !containsString(seedIDs(ragCtx), "wiki-1") {
+		t.Fatalf("seeds = %v, plan = %+v", seedIDs(ragCtx), ragCtx.Plan)
+	}
+	var retried bool
+	for i := 1; i < len(searcher.calls); i++ {
+		if len(searcher.calls[i-1].EntityKinds) > 0 && len(searcher.calls[i].EntityKinds) == 0 {
+			retried = true
+		}
+	}
+	if !retried {
+		t.Fatalf("expected an unhinted retry, calls = %+v", searcher.calls)
+	}
+}
+
+func seedIDs(ragCtx *RAGContext) []string {
+	ids := make([]string, 0, len(ragCtx.SeedEntities))
+	for _, s := range ragCtx.SeedEntities {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
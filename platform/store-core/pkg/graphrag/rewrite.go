@@ -0,0 +1,142 @@
+package graphrag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ===================================================
+// Follow-up Query Rewriting
+// Turns "and who owns it?" into a standalone query before
+// context building
+// ===================================================
+
+// QueryRewriter rewrites a follow-up into a query that can be answered
+// without the conversation. Implementations fall back to the original query
+// rather than failing the turn.
+type QueryRewriter interface {
+	Rewrite(ctx context.Context, sess *Session, query string) string
+}
+
+// rewriteHistoryTurns is how many previous turns the rewriter sees.
+const rewriteHistoryTurns = 3
+
+// LLMQueryRewriter asks the LLM for a standalone query, falling back to
+// HeuristicQueryRewriter when the call fails.
+type LLMQueryRewriter struct {
+	llm LLMProvider
+}
+
+// NewLLMQueryRewriter creates an LLM-backed rewriter.
+func NewLLMQueryRewriter(llm LLMProvider) *LLMQueryRewriter {
+	return &LLMQueryRewriter{llm: llm}
+}
+
+// Rewrite returns the standalone form of query.
+func (r *LLMQueryRewriter) Rewrite(ctx context.Context, sess *Session, query string) string {
+	if r.llm == nil {
+		return HeuristicQueryRewriter{}.Rewrite(ctx, sess, query)
+	}
+	out, err := r.llm.Complete(ctx, buildRewritePrompt(sess, query), LLMCompletionOptions{
+		MaxTokens:   128,
+		Temperature: 0,
+		SystemPrompt: "You rewrite follow-up questions into standalone questions. " +
+			"Replace pronouns and ellipsis with the entities they refer to. Reply with the question only.",
+	})
+	if err != nil {
+		return HeuristicQueryRewriter{}.Rewrite(ctx, sess, query)
+	}
+	rewritten := strings.TrimSpace(out)
+	if i := strings.IndexByte(rewritten, '\n'); i >= 0 {
+		rewritten = strings.TrimSpace(rewritten[:i])
+	}
+	rewritten = strings.Trim(rewritten, "\"'`")
+	if rewritten == "" || len(rewritten) > 4*len(query)+200 {
+		return HeuristicQueryRewriter{}.Rewrite(ctx, sess, query)
+	}
+	return rewritten
+}
+
+func buildRewritePrompt(sess *Session, query string) string {
+	var b strings.Builder
+	b.WriteString("Conversation so far:\n")
+	turns := sess.Turns
+	if len(turns) > rewriteHistoryTurns {
+		turns = turns[len(turns)-rewriteHistoryTurns:]
+	}
+	for _, t := range turns {
+		b.WriteString(fmt.Sprintf("User: %s\n", defaultString(t.StandaloneQuery, t.Query)))
+		b.WriteString(fmt.Sprintf("Assistant: %s\n", truncateText(t.Answer, 300)))
+	}
+	if names := carriedSeedNames(sess, maxCarriedSeeds); len(names) > 0 {
+		b.WriteString(fmt.Sprintf("\nEntities under discussion: %s\n", strings.Join(names, ", ")))
+	}
+	b.WriteString(fmt.Sprintf("\nFollow-up: %s\nStandalone question:", query))
+	return b.String()
+}
+
+// HeuristicQueryRewriter is the LLM-free rewriter: when a query looks like a
+// follow-up (pronouns, a leading "and", very short) it drops the connective
+// and names the entities under discussion, which is enough for hybrid search
+// to find them again.
+type HeuristicQueryRewriter struct{}
+
+var (
+	followUpPronouns = map[string]bool{
+		"it": true, "its": true, "they": true, "them": true, "their": true,
+		"this": true, "that": true, "these": true, "those": true,
+		"he": true, "him": true, "his": true, "she": true, "her": true,
+	}
+	followUpLeads = []string{"and ", "so ", "also ", "what about ", "how about "}
+)
+
+// Rewrite returns the standalone form of query.
+func (HeuristicQueryRewriter) Rewrite(_ context.Context, sess *Session, query string) string {
+	names := carriedSeedNames(sess, 2)
+	if len(names) == 0 || !isFollowUp(query) {
+		return query
+	}
+	q := strings.TrimSpace(query)
+	for _, lead := range []string{"and ", "so ", "also "} {
+		if len(q) > len(lead) && strings.EqualFold(q[:len(lead)], lead) {
+			q = strings.TrimSpace(q[len(lead):])
+			break
+		}
+	}
+	return fmt.Sprintf("%s (%s)", q, strings.Join(names, ", "))
+}
+
+func isFollowUp(query string) bool {
+	lower := strings.ToLower(strings.TrimSpace(query))
+	for _, lead := range followUpLeads {
+		if strings.HasPrefix(lower, lead) {
+			return true
+		}
+	}
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '\'')
+	})
+	for _, w := range words {
+		if followUpPronouns[strings.TrimSuffix(w, "'s")] {
+			return true
+		}
+	}
+	return len(words) < 3
+}
+
+func carriedSeedNames(sess *Session, limit int) []string {
+	var names []string
+	for _, seed := range sess.CarriedSeeds {
+		if len(names) == limit {
+			break
+		}
+		names = append(names, defaultString(seed.Name, seed.ID))
+	}
+	return names
+}
+
+var (
+	_ QueryRewriter = (*LLMQueryRewriter)(nil)
+	_ QueryRewriter = HeuristicQueryRewriter{}
+)
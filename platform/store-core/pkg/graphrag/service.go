@@ -38,7 +38,9 @@ type Service struct {
 	graphExpander     GraphExpander
 	communityProvider CommunityProvider
 	embeddingProvider EmbeddingProvider
-	llmProvider       LLMProvider // Optional: nil falls back to mock
+	llmProvider       LLMProvider   // Optional: nil falls back to mock
+	sessions          *SessionStore // Optional: enables the session RPCs
+	rewriter          QueryRewriter
 }
 
 // NewService creates a new GraphRAG service.
@@ -109,10 +111,25 @@ func (s *Service) BuildContext(ctx context.Context, req *BuildContextRequest) (*
 		return nil, status.Error(codes.FailedPrecondition, "context builder not configured")
 	}
 
-	// Build configuration - forward all search parameters
-	// NOTE: Zero-valued fields will have defaults applied by applyContextBuilderDefaults
-	// inside DefaultContextBuilder.BuildContext
-	config := ContextBuilderConfig{
+	config := contextConfig(req)
+
+	// Build context
+	ragCtx, err := s.contextBuilder.BuildContext(ctx, req.TenantID, req.Query, config)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to build context: %v", err)
+	}
+
+	return &BuildContextResponse{
+		Context:     ragCtx,
+		TotalTimeMs: float32(time.Since(start).Milliseconds()),
+	}, nil
+}
+
+// contextConfig forwards all search parameters of req.
+// NOTE: Zero-valued fields will have defaults applied by applyContextBuilderDefaults
+// inside DefaultContextBuilder.BuildContext
+func contextConfig(req *BuildContextRequest) ContextBuilderConfig {
+	return ContextBuilderConfig{
 		// Search settings
 		TopK:           req.TopK,
 		ScoreThreshold: req.MinScore,
@@ -135,17 +152,6 @@ func (s *Service) BuildContext(ctx context.Context, req *BuildContextRequest) (*
 		IncludeContent:   req.IncludeContent,
 		MaxContentLength: req.MaxContentLength,
 	}
-
-	// Build context
-	ragCtx, err := s.contextBuilder.BuildContext(ctx, req.TenantID, req.Query, config)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to build context: %v", err)
-	}
-
-	return &BuildContextResponse{
-		Context:     ragCtx,
-		TotalTimeMs: float32(time.Since(start).Milliseconds()),
-	}, nil
 }
 
 // ===================================================
@@ -168,6 +174,9 @@ type GroundedAnswer struct {
 	ModelUsed  string
 	Confidence float32
 	TokensUsed int
+	// DroppedCitations counts [n] markers that referred to no source and
+	// were removed from the answer.
+	DroppedCitations int
 }
 
 // Citation captures which sources were used for the answer.
@@ -176,78 +185,134 @@ type Citation struct {
 	SourceType  string
 	SourceName  string
 	Excerpt     string
-	StartOffset int
+	StartOffset int // claim span in the answer
 	EndOffset   int
+	Marker      int    // [n] marker number; 0 for mock answers
+	Claim       string // cited sentence without markers
 }
 
-// GenerateAnswer builds an LLM prompt from the RAGContext and returns a mock grounded answer.
+// GenerateAnswer builds an LLM prompt from the RAGContext and returns a grounded
+// answer whose citations are parsed from the [n] markers in the answer.
 func (s *Service) GenerateAnswer(ctx context.Context, req *GenerateAnswerRequest) (*GroundedAnswer, error) {
+	if err := validateAnswerRequest(req); err != nil {
+		return nil, err
+	}
+	if s.llmProvider == nil {
+		return mockAnswer(req), nil
+	}
+	return s.generateCitedAnswer(ctx, req, nil)
+}
+
+func validateAnswerRequest(req *GenerateAnswerRequest) error {
 	if req == nil {
-		return nil, status.Error(codes.InvalidArgument, "request is required")
+		return status.Error(codes.InvalidArgument, "request is required")
 	}
 	if req.TenantID == "" {
-		return nil, status.Error(codes.InvalidArgument, "tenant_id is required")
+		return status.Error(codes.InvalidArgument, "tenant_id is required")
 	}
 	if req.Query == "" {
-		return nil, status.Error(codes.InvalidArgument, "query is required")
+		return status.Error(codes.InvalidArgument, "query is required")
 	}
 	if req.Context == nil {
-		return nil, status.Error(codes.InvalidArgument, "context is required")
+		return status.Error(codes.InvalidArgument, "context is required")
 	}
 	if req.Context.TenantID != req.TenantID {
-		return nil, status.Error(codes.PermissionDenied, "context tenant_id does not match request tenant_id")
+		return status.Error(codes.PermissionDenied, "context tenant_id does not match request tenant_id")
 	}
+	return nil
+}
 
-	// Determine model and max tokens first (used for both prompt and LLM call)
-	model := req.Model
-	if model == "" {
-		model = "gpt-4o-mini"
-	}
+// answerOptions applies the token default. An empty model leaves the choice
+// to the provider's configured model.
+func answerOptions(req *GenerateAnswerRequest) (string, int) {
 	maxTokens := req.MaxTokens
 	if maxTokens <= 0 {
 		maxTokens = 1024
 	}
+	return req.Model, maxTokens
+}
+
+// ModelDefaulter is implemented by providers that report the model they use
+// for requests naming none.
+type ModelDefaulter interface {
+	DefaultModel() string
+}
 
-	// Build prompt from context using defaulted maxTokens
+// mockAnswer returns the deterministic placeholder used without an LLM.
+func mockAnswer(req *GenerateAnswerRequest) *GroundedAnswer {
+	_, maxTokens := answerOptions(req)
 	prompt := buildAnswerPrompt(req.Query, req.Context, maxTokens)
+	answerText, citations := mockGroundedAnswer(req.Query, req.Context)
+	return &GroundedAnswer{
+		Answer:     answerText,
+		Citations:  citations,
+		ModelUsed:  "mock-graphrag-llm",
+		Confidence: 0.5, // Lower confidence for mock responses
+		TokensUsed: estimateTokens(prompt, answerText),
+	}
+}
+
+// generateCitedAnswer prompts with numbered sources and verifies the markers
+// in the completion. With onDelta set and a streaming provider, verified text
+// is passed to onDelta as it arrives.
+func (s *Service) generateCitedAnswer(ctx context.Context, req *GenerateAnswerRequest, onDelta func(string) error) (*GroundedAnswer, error) {
+	model, maxTokens := answerOptions(req)
+	if d, ok := s.llmProvider.(ModelDefaulter); ok && model == "" {
+		model = d.DefaultModel()
+	}
+	sources := buildCitationSources(req.Context)
+	prompt := buildCitedAnswerPrompt(req.Query, req.Context, sources, maxTokens)
+	opts := LLMCompletionOptions{
+		Model:       model,
+		MaxTokens:   maxTokens,
+		Temperature: 0.3,
+		SystemPrompt: "You are a helpful assistant that answers questions based on the provided sources. " +
+			"Ground every claim in the sources and cite them with [n] markers.",
+	}
 
-	var answerText string
-	var citations []Citation
-	var modelUsed string
-	var confidence float32
-
-	// Use real LLM if available, otherwise fall back to mock
-	if s.llmProvider != nil {
-		opts := LLMCompletionOptions{
-			Model:     model,
-			MaxTokens: maxTokens,
-			Temperature: 0.3,
-			SystemPrompt: "You are a helpful assistant that answers questions based on the provided context. " +
-				"Always ground your answers in the context and cite sources where applicable.",
+	filter := &markerFilter{sources: len(sources)}
+	var answer strings.Builder
+	emit := func(text string) error {
+		if text == "" {
+			return nil
 		}
-		llmResponse, err := s.llmProvider.Complete(ctx, prompt, opts)
+		answer.WriteString(text)
+		if onDelta != nil {
+			return onDelta(text)
+		}
+		return nil
+	}
+
+	streamer, ok := s.llmProvider.(StreamingLLMProvider)
+	if ok && onDelta != nil {
+		_, err := streamer.CompleteStream(ctx, prompt, opts, func(delta string) error {
+			return emit(filter.Write(delta))
+		})
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "LLM completion failed: %v", err)
 		}
-		answerText = llmResponse
-		// For real LLM responses, generate citations from context entities without text offsets
-		// (offsets are only meaningful for deterministic mock answers)
-		citations = generateContextCitations(req.Context)
-		modelUsed = fmt.Sprintf("%s/%s", s.llmProvider.Name(), model)
-		confidence = 0.85 // Higher confidence with real LLM
 	} else {
-		// Mock mode: use placeholder answer
-		answerText, citations = mockGroundedAnswer(req.Query, req.Context)
-		modelUsed = "mock-graphrag-llm"
-		confidence = 0.5 // Lower confidence for mock responses
+		llmResponse, err := s.llmProvider.Complete(ctx, prompt, opts)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "LLM completion failed: %v", err)
+		}
+		if err := emit(filter.Write(llmResponse)); err != nil {
+			return nil, err
+		}
+	}
+	if err := emit(filter.Flush()); err != nil {
+		return nil, err
 	}
 
+	answerText := answer.String()
+	citations, confidence := verifyCitations(answerText, sources, filter.valid, filter.invalid)
 	return &GroundedAnswer{
-		Answer:     answerText,
-		Citations:  citations,
-		ModelUsed:  modelUsed,
-		Confidence: confidence,
-		TokensUsed: estimateTokens(prompt, answerText),
+		Answer:           answerText,
+		Citations:        citations,
+		ModelUsed:        fmt.Sprintf("%s/%s", s.llmProvider.Name(), defaultString(model, "default")),
+		Confidence:       confidence,
+		TokensUsed:       estimateTokens(prompt, answerText),
+		DroppedCitations: filter.invalid,
 	}, nil
 }
 
@@ -444,54 +509,6 @@ func buildNodeTypeLookup(ctx *RAGContext) map[string]string {
 	return types
 }
 
-// generateContextCitations creates citations from RAG context entities without text offsets.
-// Used for real LLM responses where answer text is not deterministic.
-func generateContextCitations(ctx *RAGContext) []Citation {
-	var citations []Citation
-	seen := make(map[string]bool)
-
-	// Add citations from seed entities
-	for _, e := range ctx.SeedEntities {
-		if seen[e.ID] {
-			continue
-		}
-		seen[e.ID] = true
-		citations = append(citations, Citation{
-			SourceID:   e.ID,
-			SourceType: e.Type,
-			SourceName: defaultString(e.Name, e.ID),
-			Excerpt:    truncateText(firstNonEmpty(e.Description, e.Content), 100),
-			// No offsets for LLM-generated text
-		})
-	}
-
-	// Add citations from expanded graph nodes
-	if ctx.ExpandedGraph != nil {
-		for _, n := range ctx.ExpandedGraph.Nodes {
-			if seen[n.ID] {
-				continue
-			}
-			seen[n.ID] = true
-			name := n.ID
-			if n.Properties != nil {
-				name = defaultString(n.Properties["name"], n.Properties["label"])
-			}
-			if name == "" {
-				name = n.ID
-			}
-			citations = append(citations, Citation{
-				SourceID:   n.ID,
-				SourceType: n.Type,
-				SourceName: name,
-				Excerpt:    truncateText(n.Properties["description"], 100),
-				// No offsets for LLM-generated text
-			})
-		}
-	}
-
-	return citations
-}
-
 func nodeDisplayName(n GraphNode) string {
 	if n.Properties != nil {
 		if name := n.Properties["name"]; name != "" {
@@ -676,6 +693,7 @@ func ToProtoRAGContext(ctx *RAGContext) *ProtoRAGContext {
 		ExpandedGraph: toProtoGraphExpansion(ctx.ExpandedGraph),
 		Communities:   toProtoCommunitySummaries(ctx.Communities),
 		Lineage:       ctx.Lineage,
+		Plan:          ctx.Plan,
 	}
 }
 
@@ -688,6 +706,7 @@ type ProtoRAGContext struct {
 	ExpandedGraph *ProtoGraphExpansion
 	Communities   []*ProtoCommunitySummary
 	Lineage       []string
+	Plan          *QueryPlan
 }
 
 // ProtoEntityMatch is the proto representation of EntityMatch.
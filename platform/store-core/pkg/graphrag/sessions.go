@@ -0,0 +1,488 @@
+package graphrag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nucleus/store-core/pkg/kvstore"
+)
+
+// ===================================================
+// Conversation Sessions
+// Multi-turn GraphRAG: turn history and carried-over seed
+// entities persisted in the KV store
+// ===================================================
+
+const (
+	// sessionKeyPrefix namespaces sessions within a tenant/project KV scope.
+	sessionKeyPrefix = "graphrag/sessions/"
+	// maxSessionTurns bounds the stored history; older turns are dropped.
+	maxSessionTurns = 20
+	// maxCarriedSeeds bounds how many entities follow the conversation.
+	maxCarriedSeeds = 5
+)
+
+// ErrSessionNotFound is returned when a session does not exist in the scope.
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session is a conversation with the GraphRAG assistant.
+type Session struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenantId"`
+	ProjectID string    `json:"projectId"`
+	Title     string    `json:"title"`
+	Turns     []Turn    `json:"turns"`
+	TurnCount int       `json:"turnCount"` // all turns, including dropped ones
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// CarriedSeeds are the entities the conversation is about, most recent
+	// first. They are added as seeds when building context for the next turn.
+	CarriedSeeds []EntityMatch `json:"carriedSeeds"`
+
+	version int64 // KV version the session was read at
+}
+
+// Turn is one question and answer of a session.
+type Turn struct {
+	Query           string    `json:"query"`
+	StandaloneQuery string    `json:"standaloneQuery"` // Query after follow-up rewriting
+	Answer          string    `json:"answer"`
+	SeedIDs         []string  `json:"seedIds"`
+	CitedIDs        []string  `json:"citedIds"`
+	Confidence      float32   `json:"confidence"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// SessionStore persists sessions as JSON values in a kvstore.Store, scoped by
+// tenant and project. Writes use the KV version for optimistic concurrency.
+type SessionStore struct {
+	kv  kvstore.Store
+	now func() time.Time
+}
+
+// NewSessionStore creates a session store on top of kv.
+func NewSessionStore(kv kvstore.Store) *SessionStore {
+	return &SessionStore{kv: kv, now: time.Now}
+}
+
+// Create starts an empty session.
+func (s *SessionStore) Create(ctx context.Context, tenantID, projectID, title string) (*Session, error) {
+	now := s.now().UTC()
+	sess := &Session{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		ProjectID: projectID,
+		Title:     title,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.save(ctx, sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// Get loads a session, returning ErrSessionNotFound when it does not exist.
+func (s *SessionStore) Get(ctx context.Context, tenantID, projectID, id string) (*Session, error) {
+	rec, err := s.kv.Get(ctx, tenantID, projectID, sessionKeyPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, ErrSessionNotFound
+	}
+	var sess Session
+	if err := json.Unmarshal(rec.Value, &sess); err != nil {
+		return nil, fmt.Errorf("decode session %s: %w", id, err)
+	}
+	sess.version = rec.Version
+	return &sess, nil
+}
+
+// sessionListPage is how many session keys List reads per KV call.
+const sessionListPage = 500
+
+// List returns the sessions of a scope, most recently updated first. It pages
+// through every session key, keeping only the limit most recent sessions
+// between pages.
+func (s *SessionStore) List(ctx context.Context, tenantID, projectID string, limit int) ([]*Session, error) {
+	var sessions []*Session
+	after := ""
+	for {
+		keys, err := s.kv.ListKeysAfter(ctx, tenantID, projectID, sessionKeyPrefix, after, sessionListPage)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			sess, err := s.Get(ctx, tenantID, projectID, strings.TrimPrefix(key, sessionKeyPrefix))
+			if errors.Is(err, ErrSessionNotFound) {
+				continue // deleted concurrently
+			}
+			if err != nil {
+				return nil, err
+			}
+			sessions = append(sessions, sess)
+		}
+		sort.SliceStable(sessions, func(i, j int) bool {
+			return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+		})
+		if limit > 0 && len(sessions) > limit {
+			sessions = sessions[:limit]
+		}
+		if len(keys) < sessionListPage {
+			return sessions, nil
+		}
+		after = keys[len(keys)-1]
+	}
+}
+
+// Delete removes a session and reports whether it existed.
+func (s *SessionStore) Delete(ctx context.Context, tenantID, projectID, id string) (bool, error) {
+	return s.kv.Delete(ctx, tenantID, projectID, sessionKeyPrefix+id, 0)
+}
+
+// save writes sess, failing if it changed since it was read.
+func (s *SessionStore) save(ctx context.Context, sess *Session) error {
+	value, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+	version, err := s.kv.Put(ctx, kvstore.Record{
+		TenantID:  sess.TenantID,
+		ProjectID: sess.ProjectID,
+		Key:       sessionKeyPrefix + sess.ID,
+		Value:     value,
+	}, sess.version)
+	if err != nil {
+		return err
+	}
+	sess.version = version
+	return nil
+}
+
+// AppendTurn records a turn and carries its entities over to the next one:
+// the cited seeds and expanded nodes, or the top seeds when the answer cited
+// neither (no citations, or only community summaries).
+func (s *SessionStore) AppendTurn(ctx context.Context, sess *Session, turn Turn, ragCtx *RAGContext, answer *GroundedAnswer) error {
+	now := s.now().UTC()
+	turn.CreatedAt = now
+	cited := make(map[string]bool)
+	if answer != nil {
+		turn.Answer = answer.Answer
+		turn.Confidence = answer.Confidence
+		for _, c := range answer.Citations {
+			if !cited[c.SourceID] {
+				cited[c.SourceID] = true
+				turn.CitedIDs = append(turn.CitedIDs, c.SourceID)
+			}
+		}
+	}
+
+	var carried []EntityMatch
+	if ragCtx != nil {
+		for _, seed := range ragCtx.SeedEntities {
+			turn.SeedIDs = append(turn.SeedIDs, seed.ID)
+		}
+		carried = carriedSeeds(ragCtx, cited)
+	}
+	sess.CarriedSeeds = mergeCarriedSeeds(carried, sess.CarriedSeeds)
+
+	sess.Turns = append(sess.Turns, turn)
+	if len(sess.Turns) > maxSessionTurns {
+		sess.Turns = sess.Turns[len(sess.Turns)-maxSessionTurns:]
+	}
+	sess.TurnCount++
+	if sess.Title == "" {
+		sess.Title = truncateText(turn.Query, 80)
+	}
+	sess.UpdatedAt = now
+	return s.save(ctx, sess)
+}
+
+// carriedSeeds picks the entities of a turn worth carrying over: cited seeds,
+// then cited expanded nodes, falling back to the top seeds when none of them
+// was cited. Only the fields needed for prompting are kept, so session values
+// stay small.
+func carriedSeeds(ragCtx *RAGContext, cited map[string]bool) []EntityMatch {
+	var picked []EntityMatch
+	seen := make(map[string]bool)
+	add := func(e EntityMatch) bool {
+		if seen[e.ID] {
+			return true
+		}
+		seen[e.ID] = true
+		picked = append(picked, EntityMatch{
+			ID:          e.ID,
+			Type:        e.Type,
+			Name:        e.Name,
+			Description: truncateText(e.Description, 200),
+			Score:       e.Score,
+			ProfileID:   e.ProfileID,
+		})
+		return len(picked) < maxCarriedSeeds
+	}
+	for _, seed := range ragCtx.SeedEntities {
+		if cited[seed.ID] && !add(seed) {
+			return picked
+		}
+	}
+	if ragCtx.ExpandedGraph != nil {
+		for _, n := range ragCtx.ExpandedGraph.Nodes {
+			if cited[n.ID] && !add(EntityMatch{ID: n.ID, Type: n.Type, Name: nodeDisplayName(n)}) {
+				return picked
+			}
+		}
+	}
+	if len(picked) > 0 {
+		return picked
+	}
+	for _, seed := range ragCtx.SeedEntities {
+		if !add(seed) {
+			break
+		}
+	}
+	return picked
+}
+
+// mergeCarriedSeeds puts the latest turn's seeds ahead of older ones.
+func mergeCarriedSeeds(latest, previous []EntityMatch) []EntityMatch {
+	seen := make(map[string]bool)
+	var merged []EntityMatch
+	for _, list := range [][]EntityMatch{latest, previous} {
+		for _, seed := range list {
+			if seen[seed.ID] || len(merged) == maxCarriedSeeds {
+				continue
+			}
+			seen[seed.ID] = true
+			merged = append(merged, seed)
+		}
+	}
+	return merged
+}
+
+// ===================================================
+// Session RPCs
+// ===================================================
+
+// WithSessions enables the conversational RPCs. rewriter may be nil, in which
+// case follow-ups are rewritten with the LLM provider when one is configured
+// and heuristically otherwise.
+func (s *Service) WithSessions(store *SessionStore, rewriter QueryRewriter) *Service {
+	s.sessions = store
+	s.rewriter = rewriter
+	return s
+}
+
+// CreateSessionRequest starts a conversation.
+type CreateSessionRequest struct {
+	TenantID  string
+	ProjectID string
+	Title     string
+}
+
+// CreateSession creates an empty session.
+func (s *Service) CreateSession(ctx context.Context, req *CreateSessionRequest) (*Session, error) {
+	if err := s.checkSessionScope(req.TenantID); err != nil {
+		return nil, err
+	}
+	sess, err := s.sessions.Create(ctx, req.TenantID, req.ProjectID, req.Title)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "create session: %v", err)
+	}
+	return sess, nil
+}
+
+// SessionRef identifies a session.
+type SessionRef struct {
+	TenantID  string
+	ProjectID string
+	SessionID string
+}
+
+// GetSession returns a session with its turn history.
+func (s *Service) GetSession(ctx context.Context, req *SessionRef) (*Session, error) {
+	if err := s.checkSessionScope(req.TenantID); err != nil {
+		return nil, err
+	}
+	return s.loadSession(ctx, req.TenantID, req.ProjectID, req.SessionID)
+}
+
+// ListSessionsRequest lists the sessions of a scope.
+type ListSessionsRequest struct {
+	TenantID  string
+	ProjectID string
+	Limit     int
+}
+
+// ListSessionsResponse holds sessions without their turns.
+type ListSessionsResponse struct {
+	Sessions []*Session
+}
+
+// ListSessions returns session summaries, most recently updated first.
+func (s *Service) ListSessions(ctx context.Context, req *ListSessionsRequest) (*ListSessionsResponse, error) {
+	if err := s.checkSessionScope(req.TenantID); err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	sessions, err := s.sessions.List(ctx, req.TenantID, req.ProjectID, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list sessions: %v", err)
+	}
+	for _, sess := range sessions {
+		sess.Turns = nil
+		sess.CarriedSeeds = nil
+	}
+	return &ListSessionsResponse{Sessions: sessions}, nil
+}
+
+// DeleteSessionResponse reports whether the session existed.
+type DeleteSessionResponse struct {
+	Deleted bool
+}
+
+// DeleteSession removes a session.
+func (s *Service) DeleteSession(ctx context.Context, req *SessionRef) (*DeleteSessionResponse, error) {
+	if err := s.checkSessionScope(req.TenantID); err != nil {
+		return nil, err
+	}
+	if req.SessionID == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+	deleted, err := s.sessions.Delete(ctx, req.TenantID, req.ProjectID, req.SessionID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "delete session: %v", err)
+	}
+	return &DeleteSessionResponse{Deleted: deleted}, nil
+}
+
+// ConverseRequest asks a question within a session.
+type ConverseRequest struct {
+	TenantID  string
+	ProjectID string
+	SessionID string
+	Query     string
+	// Retrieval optionally overrides search/expansion settings; its TenantID
+	// and Query are ignored. Nil uses DefaultContextBuilderConfig.
+	Retrieval *BuildContextRequest
+	Model     string
+	MaxTokens int
+}
+
+// ConverseResponse is the answer to one turn.
+type ConverseResponse struct {
+	SessionID       string
+	StandaloneQuery string
+	Context         *RAGContext
+	Answer          *GroundedAnswer
+	TurnCount       int
+}
+
+// Converse answers a follow-up in the context of its session: the query is
+// rewritten into a standalone question, context is built through the shared
+// (typically cached) context builder with the session's carried seeds, and the
+// turn is appended to the session.
+func (s *Service) Converse(ctx context.Context, req *ConverseRequest) (*ConverseResponse, error) {
+	if err := s.checkSessionScope(req.TenantID); err != nil {
+		return nil, err
+	}
+	if req.Query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	if s.contextBuilder == nil {
+		return nil, status.Error(codes.FailedPrecondition, "context builder not configured")
+	}
+	sess, err := s.loadSession(ctx, req.TenantID, req.ProjectID, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	standalone := req.Query
+	if len(sess.Turns) > 0 {
+		standalone = s.queryRewriter().Rewrite(ctx, sess, req.Query)
+	}
+
+	config := DefaultContextBuilderConfig()
+	if req.Retrieval != nil {
+		config = contextConfig(req.Retrieval)
+	}
+	if config.ProjectID == "" {
+		config.ProjectID = req.ProjectID
+	}
+	config.CarriedSeeds = sess.CarriedSeeds
+
+	ragCtx, err := s.contextBuilder.BuildContext(ctx, req.TenantID, standalone, config)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to build context: %v", err)
+	}
+	answer, err := s.GenerateAnswer(ctx, &GenerateAnswerRequest{
+		TenantID:  req.TenantID,
+		Query:     standalone,
+		Context:   ragCtx,
+		Model:     req.Model,
+		MaxTokens: req.MaxTokens,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	turn := Turn{Query: req.Query, StandaloneQuery: standalone}
+	if err := s.sessions.AppendTurn(ctx, sess, turn, ragCtx, answer); err != nil {
+		// Another turn was written concurrently; the client should retry.
+		return nil, status.Errorf(codes.Aborted, "save session: %v", err)
+	}
+	return &ConverseResponse{
+		SessionID:       sess.ID,
+		StandaloneQuery: standalone,
+		Context:         ragCtx,
+		Answer:          answer,
+		TurnCount:       sess.TurnCount,
+	}, nil
+}
+
+func (s *Service) checkSessionScope(tenantID string) error {
+	if s.sessions == nil {
+		return status.Error(codes.FailedPrecondition, "sessions not configured")
+	}
+	if tenantID == "" {
+		return status.Error(codes.InvalidArgument, "tenant_id is required")
+	}
+	return nil
+}
+
+func (s *Service) loadSession(ctx context.Context, tenantID, projectID, id string) (*Session, error) {
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+	sess, err := s.sessions.Get(ctx, tenantID, projectID, id)
+	if errors.Is(err, ErrSessionNotFound) {
+		return nil, status.Errorf(codes.NotFound, "session %s not found", id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "load session: %v", err)
+	}
+	return sess, nil
+}
+
+func (s *Service) queryRewriter() QueryRewriter {
+	if s.rewriter != nil {
+		return s.rewriter
+	}
+	if s.llmProvider != nil {
+		return NewLLMQueryRewriter(s.llmProvider)
+	}
+	return HeuristicQueryRewriter{}
+}
@@ -0,0 +1,158 @@
+package graphrag
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nucleus/store-core/pkg/kvstore"
+)
+
+// keywordBuilder is a ContextBuilder whose search returns the entities whose
+// name appears in the query; carried seeds are merged like DefaultContextBuilder.
+type keywordBuilder struct {
+	entities []EntityMatch
+	calls    []string
+	carried  [][]EntityMatch
+}
+
+func (b *keywordBuilder) BuildContext(_ context.Context, tenantID, query string, config ContextBuilderConfig) (*RAGContext, error) {
+	b.calls = append(b.calls, query)
+	b.carried = append(b.carried, config.CarriedSeeds)
+	ragCtx := NewRAGContext(tenantID, query)
+	for _, e := range b.entities {
+		if strings.Contains(strings.ToLower(query), strings.ToLower(e.Name)) {
+			ragCtx.AddSeed(e)
+		}
+	}
+	addCarriedSeeds(ragCtx, config.CarriedSeeds)
+	return ragCtx, nil
+}
+
+func TestConverseCarriesEntitiesAcrossTurns(t *testing.T) {
+	ctx := context.Background()
+	inner := &keywordBuilder{entities: []EntityMatch{
+		{ID: "svc-billing", Name: "billing service", Type: "service"},
+		{ID: "team-payments", Name: "payments", Type: "team"},
+	}}
+	svc := NewService(NewCachedContextBuilder(inner, NewInMemoryContextCache(10)), nil, nil, nil, nil).
+		WithSessions(NewSessionStore(kvstore.NewMemoryStore()), nil)
+
+	sess, err := svc.CreateSession(ctx, &CreateSessionRequest{TenantID: "t1", ProjectID: "p1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := svc.Converse(ctx, &ConverseRequest{TenantID: "t1", ProjectID: "p1", SessionID: sess.ID, Query: "What does the billing service do?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.StandaloneQuery != "What does the billing service do?" {
+		t.Fatalf("first turn must not be rewritten: %q", first.StandaloneQuery)
+	}
+
+	second, err := svc.Converse(ctx, &ConverseRequest{TenantID: "t1", ProjectID: "p1", SessionID: sess.ID, Query: "and who owns it?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.StandaloneQuery != "who owns it? (billing service)" {
+		t.Fatalf("standalone query = %q", second.StandaloneQuery)
+	}
+	if got := inner.carried[1]; len(got) != 1 || got[0].ID != "svc-billing" {
+		t.Fatalf("carried seeds = %+v", got)
+	}
+	if second.TurnCount != 2 {
+		t.Fatalf("turn count = %d", second.TurnCount)
+	}
+
+	// A second session asking the same opening question reuses the cache.
+	other, _ := svc.CreateSession(ctx, &CreateSessionRequest{TenantID: "t1", ProjectID: "p1"})
+	if _, err := svc.Converse(ctx, &ConverseRequest{TenantID: "t1", ProjectID: "p1", SessionID: other.ID, Query: "What does the billing service do?"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(inner.calls) != 2 {
+		t.Fatalf("expected a cache hit, inner builder calls = %v", inner.calls)
+	}
+
+	stored, err := svc.GetSession(ctx, &SessionRef{TenantID: "t1", ProjectID: "p1", SessionID: sess.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.Turns) != 2 || stored.Title != "What does the billing service do?" || stored.Turns[1].Query != "and who owns it?" {
+		t.Fatalf("stored session = %+v", stored)
+	}
+
+	list, err := svc.ListSessions(ctx, &ListSessionsRequest{TenantID: "t1", ProjectID: "p1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Sessions) != 2 || list.Sessions[0].ID != other.ID || list.Sessions[0].Turns != nil {
+		t.Fatalf("list = %+v", list.Sessions)
+	}
+	if got, _ := svc.ListSessions(ctx, &ListSessionsRequest{TenantID: "t1", ProjectID: "p2"}); len(got.Sessions) != 0 {
+		t.Fatalf("sessions leaked across projects: %+v", got.Sessions)
+	}
+
+	del, err := svc.DeleteSession(ctx, &SessionRef{TenantID: "t1", ProjectID: "p1", SessionID: sess.ID})
+	if err != nil || !del.Deleted {
+		t.Fatalf("delete = %+v, %v", del, err)
+	}
+	_, err = svc.Converse(ctx, &ConverseRequest{TenantID: "t1", ProjectID: "p1", SessionID: sess.ID, Query: "and its team?"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("converse on deleted session: %v", err)
+	}
+}
+
+func TestHeuristicRewriterLeavesStandaloneQueries(t *testing.T) {
+	sess := &Session{CarriedSeeds: []EntityMatch{{ID: "svc-billing", Name: "Billing"}}}
+	r := HeuristicQueryRewriter{}
+	if got := r.Rewrite(context.Background(), sess, "Which teams deploy on Fridays?"); got != "Which teams deploy on Fridays?" {
+		t.Fatalf("standalone query rewritten to %q", got)
+	}
+	if got := r.Rewrite(context.Background(), sess, "What about its SLA?"); got != "What about its SLA? (Billing)" {
+		t.Fatalf("follow-up rewritten to %q", got)
+	}
+}
+
+func TestCarriedSeedsIncludeCitedExpandedNodes(t *testing.T) {
+	ragCtx := NewRAGContext("t1", "who deploys billing?")
+	ragCtx.AddSeed(EntityMatch{ID: "svc-billing", Name: "Billing"})
+	ragCtx.AddSeed(EntityMatch{ID: "svc-ledger", Name: "Ledger"})
+	ragCtx.ExpandedGraph = &GraphExpansion{Nodes: []GraphNode{
+		{ID: "svc-billing"},
+		{ID: "team-payments", Type: "team", Properties: map[string]string{"name": "Payments"}, HopDistance: 1},
+	}}
+
+	got := carriedSeeds(ragCtx, map[string]bool{"team-payments": true, "svc-billing": true})
+	if len(got) != 2 || got[0].ID != "svc-billing" || got[1].ID != "team-payments" || got[1].Name != "Payments" {
+		t.Fatalf("carried = %+v", got)
+	}
+	// Only a community summary was cited: fall back to the top seeds.
+	got = carriedSeeds(ragCtx, map[string]bool{"community-7": true})
+	if len(got) != 2 || got[0].ID != "svc-billing" || got[1].ID != "svc-ledger" {
+		t.Fatalf("fallback carried = %+v", got)
+	}
+}
+
+func TestSessionListPagesThroughAllSessions(t *testing.T) {
+	ctx := context.Background()
+	store := NewSessionStore(kvstore.NewMemoryStore())
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var latest string
+	for i := 0; i < sessionListPage+3; i++ {
+		at := start.Add(time.Duration(i) * time.Minute)
+		store.now = func() time.Time { return at }
+		sess, err := store.Create(ctx, "t1", "p1", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		latest = sess.ID
+	}
+	list, err := store.List(ctx, "t1", "p1", 1)
+	if err != nil || len(list) != 1 || list[0].ID != latest {
+		t.Fatalf("expected the most recent of every session, got %+v err=%v", list, err)
+	}
+}
@@ -0,0 +1,57 @@
+package graphrag
+
+import (
+	"context"
+	"strings"
+)
+
+// ===================================================
+// StreamAnswer RPC (server streaming)
+// ===================================================
+
+// StreamingLLMProvider is implemented by providers that can emit tokens as
+// they are generated.
+type StreamingLLMProvider interface {
+	LLMProvider
+	// CompleteStream calls onDelta for each chunk of the completion and
+	// returns the full text.
+	CompleteStream(ctx context.Context, prompt string, options LLMCompletionOptions, onDelta func(string) error) (string, error)
+}
+
+// AnswerChunk is one message of the StreamAnswer stream: text deltas, then a
+// final chunk carrying the verified answer and its citations.
+type AnswerChunk struct {
+	Delta  string
+	Answer *GroundedAnswer // set on the final chunk only
+}
+
+// AnswerStream is the server side of StreamAnswer (grpc.ServerStream shape).
+type AnswerStream interface {
+	Context() context.Context
+	Send(*AnswerChunk) error
+}
+
+// StreamAnswer streams a grounded answer as it is generated. Deltas have
+// already had invalid [n] markers removed, so their concatenation equals the
+// final answer and citation offsets apply to it directly.
+func (s *Service) StreamAnswer(req *GenerateAnswerRequest, stream AnswerStream) error {
+	if err := validateAnswerRequest(req); err != nil {
+		return err
+	}
+	if s.llmProvider == nil {
+		answer := mockAnswer(req)
+		for _, word := range strings.SplitAfter(answer.Answer, " ") {
+			if err := stream.Send(&AnswerChunk{Delta: word}); err != nil {
+				return err
+			}
+		}
+		return stream.Send(&AnswerChunk{Answer: answer})
+	}
+	answer, err := s.generateCitedAnswer(stream.Context(), req, func(delta string) error {
+		return stream.Send(&AnswerChunk{Delta: delta})
+	})
+	if err != nil {
+		return err
+	}
+	return stream.Send(&AnswerChunk{Answer: answer})
+}
@@ -33,6 +33,9 @@ type RAGContext struct {
 
 	// Processing stats
 	ProcessingTime  time.Duration      `json:"processingTime"`
+
+	// Query decomposition, when a planner is configured
+	Plan            *QueryPlan         `json:"plan,omitempty"`
 }
 
 // EntityMatch represents a vector search result entity.
@@ -138,6 +141,9 @@ type ContextBuilderConfig struct {
 	// Content settings
 	IncludeContent    bool      `json:"includeContent"`    // Include text content
 	MaxContentLength  int       `json:"maxContentLength"`  // Truncate content
+
+	// Conversation settings
+	CarriedSeeds      []EntityMatch `json:"carriedSeeds"`    // Seeds carried over from earlier turns
 }
 
 // DefaultContextBuilderConfig returns sensible defaults.
@@ -244,6 +250,15 @@ type KGClient interface {
 	ListEdges(ctx context.Context, tenantID, sourceID, targetID string, edgeTypes []string, limit int) ([]GraphEdge, error)
 }
 
+// KGTraverser is an optional KGClient extension backed by KgService.Traverse.
+// When the configured KGClient implements it, DefaultExpander fetches the whole
+// neighbourhood in one call instead of one ListNeighbors call per node.
+type KGTraverser interface {
+	// Traverse returns nodes (with hop distance) reachable from seedIDs within
+	// maxHops, plus the edges between them. maxNodes is the total node budget.
+	Traverse(ctx context.Context, tenantID string, seedIDs []string, edgeTypes []string, direction EdgeDirection, maxHops, maxNodes int) ([]GraphNode, []GraphEdge, error)
+}
+
 // ===================================================
 // Helper Functions
 // ===================================================
@@ -0,0 +1,216 @@
+package hybridsearch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Facet fields understood by FacetedSearch.
+const (
+	FacetSourceFamily = "source_family"
+	FacetEntityKind   = "entity_kind"
+	FacetDataset      = "dataset_slug"
+	FacetLabel        = "label"
+	FacetTimeBucket   = "time_bucket"
+)
+
+// DefaultFacetFields are aggregated when a request names none.
+var DefaultFacetFields = []string{FacetSourceFamily, FacetEntityKind, FacetDataset, FacetLabel, FacetTimeBucket}
+
+// TimeBucket is the granularity of the time_bucket facet, keyed on
+// last_activity_at.
+type TimeBucket string
+
+const (
+	BucketDay   TimeBucket = "day"
+	BucketWeek  TimeBucket = "week"
+	BucketMonth TimeBucket = "month"
+	BucketYear  TimeBucket = "year"
+)
+
+// Facet is the value counts for one field.
+type Facet struct {
+	Field  string
+	Values []FacetValue
+}
+
+// FacetValue is a single facet bucket.
+type FacetValue struct {
+	Value string
+	Count int
+}
+
+// FacetedResult is the outcome of a faceted search.
+type FacetedResult struct {
+	Results    []Result // RRF-fused hits, limited
+	Facets     []Facet  // Counts over every fused candidate, not just the returned page
+	TotalCount int      // Fused candidates
+}
+
+// FacetedSearch runs a hybrid search and aggregates the fused candidates by
+// the requested fields: the top MaxResults hits of each leg, deduplicated by
+// node. Facets therefore describe the same set the ranking draws from rather
+// than every row the filters admit. An empty fields list aggregates
+// DefaultFacetFields.
+func (s *Searcher) FacetedSearch(ctx context.Context, q Query, tf *TemporalFilter, fields []string, bucket TimeBucket) (*FacetedResult, error) {
+	if len(fields) == 0 {
+		fields = DefaultFacetFields
+	}
+	// Reject bad fields and buckets before querying.
+	if _, err := ComputeFacets(nil, fields, bucket); err != nil {
+		return nil, err
+	}
+	fused, err := s.candidates(ctx, q, tf)
+	if err != nil {
+		return nil, err
+	}
+	facets, err := ComputeFacets(fused, fields, bucket)
+	if err != nil {
+		return nil, err
+	}
+	return &FacetedResult{
+		Results:    s.limit(fused, q.Limit),
+		Facets:     facets,
+		TotalCount: len(fused),
+	}, nil
+}
+
+// ComputeFacets counts results per value of each field. Values are ordered
+// by count (ties by value), except time buckets, which are chronological.
+// Results without a value for a field are not counted for it.
+func ComputeFacets(results []Result, fields []string, bucket TimeBucket) ([]Facet, error) {
+	if bucket == "" {
+		bucket = BucketMonth
+	}
+	facets := make([]Facet, 0, len(fields))
+	for _, field := range fields {
+		// Reject unknown fields and buckets even when there are no hits.
+		if _, err := facetValues(Result{}, field, bucket); err != nil {
+			return nil, err
+		}
+		if field == FacetTimeBucket {
+			if _, err := bucketStart(time.Time{}, bucket); err != nil {
+				return nil, err
+			}
+		}
+		counts := map[string]int{}
+		for _, r := range results {
+			values, err := facetValues(r, field, bucket)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range values {
+				counts[v]++
+			}
+		}
+		values := make([]FacetValue, 0, len(counts))
+		for v, c := range counts {
+			values = append(values, FacetValue{Value: v, Count: c})
+		}
+		if field == FacetTimeBucket {
+			sort.Slice(values, func(i, j int) bool { return values[i].Value < values[j].Value })
+		} else {
+			sort.Slice(values, func(i, j int) bool {
+				if values[i].Count != values[j].Count {
+					return values[i].Count > values[j].Count
+				}
+				return values[i].Value < values[j].Value
+			})
+		}
+		facets = append(facets, Facet{Field: field, Values: values})
+	}
+	return facets, nil
+}
+
+func facetValues(r Result, field string, bucket TimeBucket) ([]string, error) {
+	single := func(v string) []string {
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	}
+	switch field {
+	case FacetSourceFamily:
+		return single(r.SourceFamily), nil
+	case FacetEntityKind:
+		return single(r.EntityKind), nil
+	case FacetDataset:
+		return single(r.DatasetSlug), nil
+	case FacetLabel:
+		// Count each label once per result even if repeated.
+		seen := make(map[string]struct{}, len(r.Labels))
+		out := make([]string, 0, len(r.Labels))
+		for _, l := range r.Labels {
+			if _, ok := seen[l]; ok || l == "" {
+				continue
+			}
+			seen[l] = struct{}{}
+			out = append(out, l)
+		}
+		return out, nil
+	case FacetTimeBucket:
+		if r.LastActivityAt == nil {
+			return nil, nil
+		}
+		v, err := bucketStart(*r.LastActivityAt, bucket)
+		if err != nil {
+			return nil, err
+		}
+		return []string{v}, nil
+	default:
+		return nil, fmt.Errorf("unsupported facet field %q", field)
+	}
+}
+
+// bucketStart formats the UTC start of the bucket containing t. Weeks start
+// on Monday.
+func bucketStart(t time.Time, bucket TimeBucket) (string, error) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch bucket {
+	case BucketDay:
+	case BucketWeek:
+		day = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BucketMonth:
+		day = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case BucketYear:
+		day = time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return "", fmt.Errorf("unsupported time bucket %q", bucket)
+	}
+	return day.Format("2006-01-02"), nil
+}
+
+// ApplyFilters narrows q with field:value filters. Values may list several
+// alternatives separated by commas.
+func ApplyFilters(q *Query, filters map[string]string) error {
+	for field, raw := range filters {
+		var values []string
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		switch field {
+		case FacetSourceFamily:
+			q.SourceFamilies = append(q.SourceFamilies, values...)
+		case FacetEntityKind:
+			q.EntityKinds = append(q.EntityKinds, values...)
+		case FacetDataset:
+			q.DatasetSlugs = append(q.DatasetSlugs, values...)
+		case FacetLabel:
+			q.Labels = append(q.Labels, values...)
+		case "profile_id":
+			q.ProfileIDs = append(q.ProfileIDs, values...)
+		default:
+			return fmt.Errorf("unsupported filter field %q", field)
+		}
+	}
+	return nil
+}
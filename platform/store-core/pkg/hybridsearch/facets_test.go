@@ -0,0 +1,111 @@
+package hybridsearch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeFacets(t *testing.T) {
+	at := func(s string) *time.Time {
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &ts
+	}
+	results := []Result{
+		{NodeID: "a", SourceFamily: "jira", EntityKind: "issue", DatasetSlug: "eng", Labels: []string{"bug", "bug", "p1"}, LastActivityAt: at("2024-03-04T10:00:00Z")},
+		{NodeID: "b", SourceFamily: "jira", EntityKind: "issue", Labels: []string{"bug"}, LastActivityAt: at("2024-03-10T23:00:00Z")},
+		{NodeID: "c", SourceFamily: "confluence", EntityKind: "page", DatasetSlug: "eng", LastActivityAt: at("2024-04-01T00:00:00Z")},
+		{NodeID: "d", SourceFamily: "confluence"},
+	}
+
+	facets, err := ComputeFacets(results, DefaultFacetFields, BucketWeek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]FacetValue{}
+	for _, f := range facets {
+		got[f.Field] = f.Values
+	}
+	want := map[string][]FacetValue{
+		FacetSourceFamily: {{"confluence", 2}, {"jira", 2}},
+		FacetEntityKind:   {{"issue", 2}, {"page", 1}},
+		FacetDataset:      {{"eng", 2}},
+		FacetLabel:        {{"bug", 2}, {"p1", 1}},
+		// Weeks start on Monday: 2024-03-10 is a Sunday.
+		FacetTimeBucket: {{"2024-03-04", 2}, {"2024-04-01", 1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("facets = %+v, want %+v", got, want)
+	}
+
+	if _, err := ComputeFacets(nil, []string{"color"}, ""); err == nil {
+		t.Fatal("expected unknown facet field to be rejected")
+	}
+	if _, err := ComputeFacets(nil, []string{FacetTimeBucket}, "fortnight"); err == nil {
+		t.Fatal("expected unknown time bucket to be rejected")
+	}
+}
+
+func TestFilterSQLUsesTableColumns(t *testing.T) {
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := Query{ProjectID: "p1", Labels: []string{"bug"}}
+	tf := &TemporalFilter{LastActivityAfter: &after, FirstSeenBefore: &after}
+
+	// vector_entries: labels, but no temporal columns of its own.
+	entries := map[string]bool{"project_id": true, "labels": true, "created_at": true, "updated_at": true}
+	where, args := filterSQL(entries, q, tf, []any{"emb", "t1"})
+	if where != " AND project_id = $3 AND labels && $4 AND updated_at > $5 AND created_at < $6" || len(args) != 6 {
+		t.Fatalf("unexpected vector_entries filter %q (%d args)", where, len(args))
+	}
+	if sel := facetSelect(entries); !strings.Contains(sel, "labels AS labels") || !strings.Contains(sel, "updated_at::timestamptz") {
+		t.Errorf("unexpected vector_entries facets %q", sel)
+	}
+
+	// vector_index_entries: no labels, projects keyed by project_key.
+	index := map[string]bool{"project_key": true, "first_seen_at": true, "last_activity_at": true}
+	where, args = filterSQL(index, q, tf, []any{"text", "t1"})
+	if where != " AND project_key = $3 AND false AND last_activity_at > $4 AND first_seen_at < $5" || len(args) != 5 {
+		t.Fatalf("unexpected vector_index_entries filter %q (%d args)", where, len(args))
+	}
+	if sel := facetSelect(index); !strings.Contains(sel, "'{}'::text[] AS labels") || !strings.Contains(sel, "last_activity_at::timestamptz") {
+		t.Errorf("unexpected vector_index_entries facets %q", sel)
+	}
+}
+
+func TestApplyFilters(t *testing.T) {
+	var q Query
+	err := ApplyFilters(&q, map[string]string{
+		FacetSourceFamily: "jira, confluence",
+		FacetLabel:        "bug",
+		FacetDataset:      " ",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(q.SourceFamilies, []string{"jira", "confluence"}) || !reflect.DeepEqual(q.Labels, []string{"bug"}) || q.DatasetSlugs != nil {
+		t.Fatalf("unexpected query: %+v", q)
+	}
+	if err := ApplyFilters(&q, map[string]string{"owner": "x"}); err == nil {
+		t.Fatal("expected unknown filter field to be rejected")
+	}
+}
+
+func TestDedupeSuggestions(t *testing.T) {
+	got := dedupeSuggestions([]Suggestion{
+		{Text: "Acme Corp", EntityKind: "org", Score: 0.4},
+		{Text: "acme corp", EntityKind: "org", Score: 1},
+		{Text: "Acme", EntityKind: "org", Score: 1},
+		{Text: "Acne study", EntityKind: "doc", Score: 0.35},
+	}, 2)
+	want := []Suggestion{{Text: "Acme", EntityKind: "org", Score: 1}, {Text: "acme corp", EntityKind: "org", Score: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("suggestions = %+v, want %+v", got, want)
+	}
+	if escapeLike(`50%_off\`) != `50\%\_off\\` {
+		t.Fatalf("unexpected escape: %s", escapeLike(`50%_off\`))
+	}
+}
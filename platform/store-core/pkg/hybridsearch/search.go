@@ -8,7 +8,10 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // Result represents a search result with combined score.
@@ -22,6 +25,13 @@ type Result struct {
 	KeywordRank  int       // Rank from keyword search
 	ContentText  string
 	Metadata     map[string]any
+
+	// Facet dimensions, populated by both search legs.
+	EntityKind     string
+	SourceFamily   string
+	DatasetSlug    string
+	Labels         []string
+	LastActivityAt *time.Time
 }
 
 // Query represents a hybrid search query.
@@ -34,6 +44,7 @@ type Query struct {
 	SourceFamilies []string
 	EntityKinds    []string
 	DatasetSlugs   []string
+	Labels         []string // Matches rows carrying any of these labels
 	Limit          int
 }
 
@@ -53,6 +64,7 @@ type Options struct {
 	VectorK       int     // RRF constant for vector (default: 60)
 	KeywordK      int     // RRF constant for keyword (default: 60)
 	MaxResults    int     // Max results to return (default: 100)
+	EntityTable   string  // Entity registry table for suggestions (default: canonical_entities)
 }
 
 // DefaultOptions returns sensible defaults for hybrid search.
@@ -63,6 +75,7 @@ func DefaultOptions() Options {
 		VectorK:       60,
 		KeywordK:      60,
 		MaxResults:    100,
+		EntityTable:   "canonical_entities",
 	}
 }
 
@@ -72,6 +85,9 @@ type Searcher struct {
 	opts    Options
 	table   string // Vector table name
 	ftsView string // FTS view/table name
+
+	mu   sync.Mutex
+	cols map[string]map[string]bool // table -> column names, see columns
 }
 
 // New creates a new hybrid searcher.
@@ -92,16 +108,29 @@ func New(db *sql.DB, vectorTable, ftsView string, opts Options) *Searcher {
 	if opts.MaxResults == 0 {
 		opts.MaxResults = 100
 	}
+	if opts.EntityTable == "" {
+		opts.EntityTable = "canonical_entities"
+	}
 	return &Searcher{
 		db:      db,
 		opts:    opts,
 		table:   vectorTable,
 		ftsView: ftsView,
+		cols:    map[string]map[string]bool{},
 	}
 }
 
 // Search performs hybrid search using RRF (Reciprocal Rank Fusion).
 func (s *Searcher) Search(ctx context.Context, q Query, tf *TemporalFilter) ([]Result, error) {
+	fused, err := s.candidates(ctx, q, tf)
+	if err != nil {
+		return nil, err
+	}
+	return s.limit(fused, q.Limit), nil
+}
+
+// candidates returns every fused hit, before the result limit is applied.
+func (s *Searcher) candidates(ctx context.Context, q Query, tf *TemporalFilter) ([]Result, error) {
 	// Get vector results
 	vectorResults, err := s.vectorSearch(ctx, q, tf)
 	if err != nil {
@@ -115,18 +144,19 @@ func (s *Searcher) Search(ctx context.Context, q Query, tf *TemporalFilter) ([]R
 	}
 
 	// Fuse results using RRF
-	fused := s.rrfFusion(vectorResults, keywordResults)
+	return s.rrfFusion(vectorResults, keywordResults), nil
+}
 
-	// Limit results - enforce MaxResults as absolute cap
+// limit enforces MaxResults as an absolute cap on fused results.
+func (s *Searcher) limit(fused []Result, requested int) []Result {
 	limit := s.opts.MaxResults
-	if q.Limit > 0 && q.Limit < s.opts.MaxResults {
-		limit = q.Limit // Use caller's limit if smaller than max
+	if requested > 0 && requested < s.opts.MaxResults {
+		limit = requested // Use caller's limit if smaller than max
 	}
 	if len(fused) > limit {
 		fused = fused[:limit]
 	}
-
-	return fused, nil
+	return fused
 }
 
 // vectorSearch performs vector similarity search.
@@ -134,6 +164,10 @@ func (s *Searcher) vectorSearch(ctx context.Context, q Query, tf *TemporalFilter
 	if len(q.Embedding) == 0 {
 		return nil, nil
 	}
+	cols, err := s.columns(ctx, s.table)
+	if err != nil {
+		return nil, err
+	}
 
 	// Build embedding array string
 	embStr := make([]string, len(q.Embedding))
@@ -142,79 +176,15 @@ func (s *Searcher) vectorSearch(ctx context.Context, q Query, tf *TemporalFilter
 	}
 	embArray := "[" + strings.Join(embStr, ",") + "]"
 
+	where, args := filterSQL(cols, q, tf, []any{embArray, q.TenantID})
 	sql := fmt.Sprintf(`
 		SELECT node_id, profile_id, content_text, 
 		       1 - (embedding <=> $1::vector) as score,
-		       metadata
+		       %s, %s
 		FROM %s
-		WHERE tenant_id = $2
-	`, s.table)
-
-	args := []any{embArray, q.TenantID}
-	argIdx := 3
-
-	if q.ProjectID != "" {
-		sql += fmt.Sprintf(" AND project_id = $%d", argIdx)
-		args = append(args, q.ProjectID)
-		argIdx++
-	}
-
-	if len(q.ProfileIDs) > 0 {
-		sql += fmt.Sprintf(" AND profile_id = ANY($%d)", argIdx)
-		args = append(args, q.ProfileIDs)
-		argIdx++
-	}
-
-	if len(q.SourceFamilies) > 0 {
-		sql += fmt.Sprintf(" AND source_family = ANY($%d)", argIdx)
-		args = append(args, q.SourceFamilies)
-		argIdx++
-	}
-
-	if len(q.EntityKinds) > 0 {
-		sql += fmt.Sprintf(" AND entity_kind = ANY($%d)", argIdx)
-		args = append(args, q.EntityKinds)
-		argIdx++
-	}
-
-	// P2 Fix: Add dataset filter to vector search
-	if len(q.DatasetSlugs) > 0 {
-		sql += fmt.Sprintf(" AND dataset_slug = ANY($%d)", argIdx)
-		args = append(args, q.DatasetSlugs)
-		argIdx++
-	}
-
-	// Temporal filters (P2 Fix: include all temporal fields)
-	if tf != nil {
-		if tf.LastActivityAfter != nil {
-			sql += fmt.Sprintf(" AND last_activity_at > $%d", argIdx)
-			args = append(args, tf.LastActivityAfter)
-			argIdx++
-		}
-		if tf.LastActivityBefore != nil {
-			sql += fmt.Sprintf(" AND last_activity_at < $%d", argIdx)
-			args = append(args, tf.LastActivityBefore)
-			argIdx++
-		}
-		if tf.FirstSeenAfter != nil {
-			sql += fmt.Sprintf(" AND first_seen_at > $%d", argIdx)
-			args = append(args, tf.FirstSeenAfter)
-			argIdx++
-		}
-		if tf.FirstSeenBefore != nil {
-			sql += fmt.Sprintf(" AND first_seen_at < $%d", argIdx)
-			args = append(args, tf.FirstSeenBefore)
-			argIdx++
-		}
-		// AsOf is used for point-in-time queries - filter records that existed at that time
-		if tf.AsOf != nil {
-			sql += fmt.Sprintf(" AND first_seen_at <= $%d", argIdx)
-			args = append(args, tf.AsOf)
-			argIdx++
-		}
-	}
-
-	sql += fmt.Sprintf(" ORDER BY embedding <=> $1::vector LIMIT %d", s.opts.MaxResults)
+		WHERE tenant_id = $2%s
+		ORDER BY embedding <=> $1::vector LIMIT %d
+	`, columnOr(cols, "NULL::jsonb", "metadata", "raw_metadata"), facetSelect(cols), s.table, where, s.opts.MaxResults)
 
 	rows, err := s.db.QueryContext(ctx, sql, args...)
 	if err != nil {
@@ -228,7 +198,7 @@ func (s *Searcher) vectorSearch(ctx context.Context, q Query, tf *TemporalFilter
 		rank++
 		var r Result
 		var metadata []byte
-		if err := rows.Scan(&r.NodeID, &r.ProfileID, &r.ContentText, &r.VectorScore, &metadata); err != nil {
+		if err := rows.Scan(append([]any{&r.NodeID, &r.ProfileID, &r.ContentText, &r.VectorScore, &metadata}, facetDest(&r)...)...); err != nil {
 			return nil, err
 		}
 		// P2 Fix: Populate metadata from JSON bytes
@@ -249,98 +219,162 @@ func (s *Searcher) keywordSearch(ctx context.Context, q Query, tf *TemporalFilte
 	if strings.TrimSpace(q.Text) == "" {
 		return nil, nil
 	}
+	cols, err := s.columns(ctx, s.ftsView)
+	if err != nil {
+		return nil, err
+	}
 
+	where, args := filterSQL(cols, q, tf, []any{q.Text, q.TenantID})
 	sql := fmt.Sprintf(`
 		SELECT node_id, profile_id, content_text,
-		       ts_rank_cd(tsv, plainto_tsquery($1)) as score
+		       ts_rank_cd(tsv, plainto_tsquery($1)) as score,
+		       %s
 		FROM %s
 		WHERE tsv @@ plainto_tsquery($1)
-		  AND tenant_id = $2
-	`, s.ftsView)
+		  AND tenant_id = $2%s
+		ORDER BY ts_rank_cd(tsv, plainto_tsquery($1)) DESC LIMIT %d
+	`, facetSelect(cols), s.ftsView, where, s.opts.MaxResults)
 
-	args := []any{q.Text, q.TenantID}
-	argIdx := 3
+	rows, err := s.db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	if q.ProjectID != "" {
-		sql += fmt.Sprintf(" AND project_id = $%d", argIdx)
-		args = append(args, q.ProjectID)
-		argIdx++
+	var results []Result
+	rank := 0
+	for rows.Next() {
+		rank++
+		var r Result
+		if err := rows.Scan(append([]any{&r.NodeID, &r.ProfileID, &r.ContentText, &r.KeywordScore}, facetDest(&r)...)...); err != nil {
+			return nil, err
+		}
+		r.KeywordRank = rank
+		results = append(results, r)
 	}
+	return results, rows.Err()
+}
 
-	if len(q.SourceFamilies) > 0 {
-		sql += fmt.Sprintf(" AND source_family = ANY($%d)", argIdx)
-		args = append(args, q.SourceFamilies)
-		argIdx++
+// columns returns the column names of a table or view, cached per table.
+// The searchable tables do not share one schema: vector_entries has no
+// first_seen_at or last_activity_at, and vector_index_entries has no labels
+// and keys projects by project_key.
+func (s *Searcher) columns(ctx context.Context, table string) (map[string]bool, error) {
+	s.mu.Lock()
+	cols, ok := s.cols[table]
+	s.mu.Unlock()
+	if ok {
+		return cols, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT attname FROM pg_attribute
+		WHERE attrelid = to_regclass($1) AND attnum > 0 AND NOT attisdropped`, table)
+	if err != nil {
+		return nil, fmt.Errorf("columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+	cols = map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		cols[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
 	}
 
-	// P1 Fix: Add profile and dataset filters to keyword search
-	if len(q.ProfileIDs) > 0 {
-		sql += fmt.Sprintf(" AND profile_id = ANY($%d)", argIdx)
-		args = append(args, q.ProfileIDs)
-		argIdx++
+	s.mu.Lock()
+	s.cols[table] = cols
+	s.mu.Unlock()
+	return cols, nil
+}
+
+// columnOr returns the first of names the table has, or def.
+func columnOr(cols map[string]bool, def string, names ...string) string {
+	for _, n := range names {
+		if cols[n] {
+			return n
+		}
 	}
+	return def
+}
 
-	if len(q.DatasetSlugs) > 0 {
-		sql += fmt.Sprintf(" AND dataset_slug = ANY($%d)", argIdx)
-		args = append(args, q.DatasetSlugs)
-		argIdx++
+// filterSQL renders the scope, facet and temporal filters of a query as
+// AND clauses, numbering parameters after args. Temporal filters fall back
+// to created_at and updated_at on tables without first_seen_at and
+// last_activity_at; a label filter on a table without labels matches
+// nothing.
+func filterSQL(cols map[string]bool, q Query, tf *TemporalFilter, args []any) (string, []any) {
+	var b strings.Builder
+	add := func(cond string, v any) {
+		args = append(args, v)
+		fmt.Fprintf(&b, " AND "+cond, len(args))
 	}
 
+	if q.ProjectID != "" {
+		add(columnOr(cols, "project_id", "project_id", "project_key")+" = $%d", q.ProjectID)
+	}
+	if len(q.ProfileIDs) > 0 {
+		add("profile_id = ANY($%d)", pq.Array(q.ProfileIDs))
+	}
+	if len(q.SourceFamilies) > 0 {
+		add("source_family = ANY($%d)", pq.Array(q.SourceFamilies))
+	}
 	if len(q.EntityKinds) > 0 {
-		sql += fmt.Sprintf(" AND entity_kind = ANY($%d)", argIdx)
-		args = append(args, q.EntityKinds)
-		argIdx++
+		add("entity_kind = ANY($%d)", pq.Array(q.EntityKinds))
+	}
+	// P2 Fix: Add dataset filter to vector search
+	if len(q.DatasetSlugs) > 0 {
+		add("dataset_slug = ANY($%d)", pq.Array(q.DatasetSlugs))
+	}
+	if len(q.Labels) > 0 {
+		if cols["labels"] {
+			add("labels && $%d", pq.Array(q.Labels))
+		} else {
+			b.WriteString(" AND false")
+		}
 	}
 
 	// Temporal filters (P2 Fix: include all temporal fields)
 	if tf != nil {
+		activity := columnOr(cols, "last_activity_at", "last_activity_at", "updated_at")
+		seen := columnOr(cols, "first_seen_at", "first_seen_at", "created_at")
 		if tf.LastActivityAfter != nil {
-			sql += fmt.Sprintf(" AND last_activity_at > $%d", argIdx)
-			args = append(args, tf.LastActivityAfter)
-			argIdx++
+			add(activity+" > $%d", tf.LastActivityAfter)
 		}
 		if tf.LastActivityBefore != nil {
-			sql += fmt.Sprintf(" AND last_activity_at < $%d", argIdx)
-			args = append(args, tf.LastActivityBefore)
-			argIdx++
+			add(activity+" < $%d", tf.LastActivityBefore)
 		}
 		if tf.FirstSeenAfter != nil {
-			sql += fmt.Sprintf(" AND first_seen_at > $%d", argIdx)
-			args = append(args, tf.FirstSeenAfter)
-			argIdx++
+			add(seen+" > $%d", tf.FirstSeenAfter)
 		}
 		if tf.FirstSeenBefore != nil {
-			sql += fmt.Sprintf(" AND first_seen_at < $%d", argIdx)
-			args = append(args, tf.FirstSeenBefore)
-			argIdx++
+			add(seen+" < $%d", tf.FirstSeenBefore)
 		}
+		// AsOf is used for point-in-time queries - filter records that existed at that time
 		if tf.AsOf != nil {
-			sql += fmt.Sprintf(" AND first_seen_at <= $%d", argIdx)
-			args = append(args, tf.AsOf)
-			argIdx++
+			add(seen+" <= $%d", tf.AsOf)
 		}
 	}
+	return b.String(), args
+}
 
-	sql += fmt.Sprintf(" ORDER BY ts_rank_cd(tsv, plainto_tsquery($1)) DESC LIMIT %d", s.opts.MaxResults)
-
-	rows, err := s.db.QueryContext(ctx, sql, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+// facetSelect selects the facet dimensions of a table, substituting empty
+// values for the columns it lacks.
+func facetSelect(cols map[string]bool) string {
+	return fmt.Sprintf(`COALESCE(entity_kind, '') AS entity_kind, COALESCE(source_family, '') AS source_family,
+		       COALESCE(dataset_slug, '') AS dataset_slug, %s AS labels, %s::timestamptz AS last_activity_at`,
+		columnOr(cols, "'{}'::text[]", "labels"), columnOr(cols, "NULL", "last_activity_at", "updated_at"))
+}
 
-	var results []Result
-	rank := 0
-	for rows.Next() {
-		rank++
-		var r Result
-		if err := rows.Scan(&r.NodeID, &r.ProfileID, &r.ContentText, &r.KeywordScore); err != nil {
-			return nil, err
-		}
-		r.KeywordRank = rank
-		results = append(results, r)
-	}
-	return results, rows.Err()
+func facetDest(r *Result) []any {
+	return []any{&r.EntityKind, &r.SourceFamily, &r.DatasetSlug, pq.Array(&r.Labels), &r.LastActivityAt}
 }
 
 // rrfFusion combines vector and keyword results using Reciprocal Rank Fusion.
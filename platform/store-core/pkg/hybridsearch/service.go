@@ -143,22 +143,28 @@ func (s *SearchService) toResponse(results []Result) *HybridSearchResponse {
 	}
 
 	for i, r := range results {
-		resp.Results[i] = SearchResult{
-			NodeID:       r.NodeID,
-			ProfileID:    r.ProfileID,
-			Score:        r.Score,
-			VectorScore:  r.VectorScore,
-			KeywordScore: r.KeywordScore,
-			VectorRank:   int32(r.VectorRank),
-			KeywordRank:  int32(r.KeywordRank),
-			ContentText:  r.ContentText,
-			Metadata:     toStringMap(r.Metadata),
-		}
+		resp.Results[i] = toSearchResult(r)
 	}
 
 	return resp
 }
 
+func toSearchResult(r Result) SearchResult {
+	return SearchResult{
+		NodeID:       r.NodeID,
+		ProfileID:    r.ProfileID,
+		Score:        r.Score,
+		VectorScore:  r.VectorScore,
+		KeywordScore: r.KeywordScore,
+		VectorRank:   int32(r.VectorRank),
+		KeywordRank:  int32(r.KeywordRank),
+		ContentText:  r.ContentText,
+		Metadata:     toStringMap(r.Metadata),
+		EntityKind:   r.EntityKind,
+		SourceFamily: r.SourceFamily,
+	}
+}
+
 func toStringMap(m map[string]any) map[string]string {
 	if m == nil {
 		return nil
@@ -247,22 +253,128 @@ func (s *SearchService) NearbyEntities(ctx context.Context, req *NearbyRequest)
 		if req.MinSimilarity > 0 && r.VectorScore < req.MinSimilarity {
 			continue
 		}
-		filtered = append(filtered, SearchResult{
-			NodeID:       r.NodeID,
-			ProfileID:    r.ProfileID,
-			Score:        r.Score,
-			VectorScore:  r.VectorScore,
-			KeywordScore: r.KeywordScore,
-			VectorRank:   int32(r.VectorRank),
-			KeywordRank:  int32(r.KeywordRank),
-			ContentText:  r.ContentText,
-			Metadata:     toStringMap(r.Metadata),
-		})
+		filtered = append(filtered, toSearchResult(r))
 	}
 
 	return &NearbyResponse{Results: filtered}, nil
 }
 
+// FacetedSearchRequest represents a faceted search request.
+type FacetedSearchRequest struct {
+	Query       string
+	Embedding   []float32
+	TenantID    string
+	ProjectID   string
+	FacetFields []string          // Defaults to DefaultFacetFields
+	Filters     map[string]string // Field:value filters; comma-separated values match any
+	Temporal    *TemporalFilterRequest
+	Limit       int32
+	TimeBucket  string // day, week, month (default) or year
+}
+
+// FacetedSearchResponse represents faceted search results.
+type FacetedSearchResponse struct {
+	Results    []SearchResult
+	Facets     []Facet
+	TotalCount int32
+}
+
+// FacetedSearch performs hybrid search and aggregates the fused candidates by
+// source family, entity kind, dataset, label and time bucket.
+func (s *SearchService) FacetedSearch(ctx context.Context, req *FacetedSearchRequest) (*FacetedSearchResponse, error) {
+	if req.TenantID == "" {
+		return nil, status.Error(codes.InvalidArgument, "tenant_id is required")
+	}
+	if req.Query == "" && len(req.Embedding) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "query or embedding is required")
+	}
+
+	q := Query{
+		Text:      req.Query,
+		Embedding: req.Embedding,
+		TenantID:  req.TenantID,
+		ProjectID: req.ProjectID,
+		Limit:     int(req.Limit),
+	}
+	if err := ApplyFilters(&q, req.Filters); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	fields := req.FacetFields
+	if len(fields) == 0 {
+		fields = DefaultFacetFields
+	}
+	bucket := TimeBucket(strings.ToLower(req.TimeBucket))
+	// Validate facets up front so bad input is not reported as a search failure.
+	if _, err := ComputeFacets(nil, fields, bucket); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res, err := s.searcher.FacetedSearch(ctx, q, toTemporalFilter(req.Temporal), fields, bucket)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "search failed: %v", err)
+	}
+
+	resp := &FacetedSearchResponse{
+		Results:    make([]SearchResult, len(res.Results)),
+		Facets:     res.Facets,
+		TotalCount: int32(res.TotalCount),
+	}
+	for i, r := range res.Results {
+		resp.Results[i] = toSearchResult(r)
+	}
+	return resp, nil
+}
+
+// SuggestRequest represents an autocomplete request.
+type SuggestRequest struct {
+	Prefix         string
+	TenantID       string
+	ProjectID      string
+	SourceFamilies []string
+	Limit          int32
+}
+
+// SuggestResponse represents autocomplete suggestions.
+type SuggestResponse struct {
+	Suggestions []Suggestion
+}
+
+// Suggest provides prefix and typo-tolerant autocomplete suggestions over
+// entity display names and titles.
+func (s *SearchService) Suggest(ctx context.Context, req *SuggestRequest) (*SuggestResponse, error) {
+	if req.TenantID == "" {
+		return nil, status.Error(codes.InvalidArgument, "tenant_id is required")
+	}
+	if strings.TrimSpace(req.Prefix) == "" {
+		return nil, status.Error(codes.InvalidArgument, "prefix is required")
+	}
+
+	suggestions, err := s.searcher.Suggest(ctx, SuggestQuery{
+		Prefix:         req.Prefix,
+		TenantID:       req.TenantID,
+		ProjectID:      req.ProjectID,
+		SourceFamilies: req.SourceFamilies,
+		Limit:          int(req.Limit),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "suggest failed: %v", err)
+	}
+	return &SuggestResponse{Suggestions: suggestions}, nil
+}
+
+func toTemporalFilter(req *TemporalFilterRequest) *TemporalFilter {
+	if req == nil {
+		return nil
+	}
+	return &TemporalFilter{
+		LastActivityAfter:  req.LastActivityAfter,
+		LastActivityBefore: req.LastActivityBefore,
+		FirstSeenAfter:     req.FirstSeenAfter,
+		FirstSeenBefore:    req.FirstSeenBefore,
+		AsOf:               req.AsOf,
+	}
+}
+
 // parseEmbedding parses an embedding from PostgreSQL vector format.
 func parseEmbedding(s string) []float32 {
 	s = strings.TrimPrefix(s, "[")
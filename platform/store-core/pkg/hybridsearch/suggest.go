@@ -0,0 +1,167 @@
+package hybridsearch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Suggestion is an autocomplete candidate.
+type Suggestion struct {
+	Text       string
+	EntityKind string
+	Score      float32 // 1 for prefix matches, trigram word similarity otherwise
+}
+
+// SuggestQuery scopes an autocomplete lookup.
+type SuggestQuery struct {
+	Prefix         string
+	TenantID       string
+	ProjectID      string
+	SourceFamilies []string
+	Limit          int
+}
+
+// minWordSimilarity is the pg_trgm word similarity a non-prefix candidate
+// needs to be suggested; low enough to survive a typo in a short prefix.
+const minWordSimilarity = 0.3
+
+// Suggest returns prefix and typo-tolerant completions over entity display
+// names (the entity registry, names and aliases) and document titles (the
+// vector table's metadata), scoped to the project when one is given.
+// Ranking uses pg_trgm word similarity, so the pg_trgm extension must be
+// installed.
+func (s *Searcher) Suggest(ctx context.Context, q SuggestQuery) ([]Suggestion, error) {
+	prefix := strings.TrimSpace(q.Prefix)
+	if prefix == "" {
+		return nil, nil
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > s.opts.MaxResults {
+		limit = s.opts.MaxResults
+	}
+
+	// The registry is optional; skip it when it has not been created.
+	var hasEntities bool
+	if err := s.db.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, s.opts.EntityTable).Scan(&hasEntities); err != nil {
+		return nil, err
+	}
+
+	cols, err := s.columns(ctx, s.table)
+	if err != nil {
+		return nil, err
+	}
+	args := []any{prefix, escapeLike(prefix) + "%", q.TenantID, minWordSimilarity}
+	argIdx := 5
+
+	metadata := columnOr(cols, "metadata", "metadata", "raw_metadata")
+	titles := fmt.Sprintf(`
+		SELECT %[1]s->>'title' AS text, COALESCE(entity_kind, '') AS kind
+		FROM %[2]s
+		WHERE tenant_id = $3 AND %[1]s ? 'title'`, metadata, s.table)
+	projectIdx := 0
+	project := columnOr(cols, "project_id", "project_id", "project_key")
+	if q.ProjectID != "" {
+		projectIdx = argIdx
+		titles += fmt.Sprintf(" AND %s = $%d", project, projectIdx)
+		args = append(args, q.ProjectID)
+		argIdx++
+	}
+	sourceIdx := 0
+	if len(q.SourceFamilies) > 0 {
+		sourceIdx = argIdx
+		titles += fmt.Sprintf(" AND source_family = ANY($%d)", sourceIdx)
+		args = append(args, pq.Array(q.SourceFamilies))
+	}
+
+	source := titles
+	if hasEntities {
+		entities := fmt.Sprintf(`
+		SELECT n AS text, e.entity_type AS kind
+		FROM %s e, unnest(e.name || e.aliases) AS n
+		WHERE e.tenant_id = $3`, s.opts.EntityTable)
+		if sourceIdx > 0 {
+			// Registry entities carry their source on their refs.
+			entities += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM entity_source_refs r WHERE r.entity_id = e.id AND r.source = ANY($%d))", sourceIdx)
+		}
+		if projectIdx > 0 {
+			// The registry is tenant-wide; an entity belongs to a project
+			// through the indexed nodes its refs point at.
+			entities += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM entity_source_refs r JOIN %s v ON v.node_id = r.node_id
+			WHERE r.entity_id = e.id AND v.tenant_id = e.tenant_id AND v.%s = $%d)`, s.table, project, projectIdx)
+		}
+		source = entities + "\n\t\tUNION ALL" + titles
+	}
+
+	sql := fmt.Sprintf(`
+		SELECT text, kind,
+		       CASE WHEN text ILIKE $2 THEN 1 ELSE word_similarity($1, text) END AS score
+		FROM (%s
+		) candidates
+		WHERE text ILIKE $2 OR word_similarity($1, text) >= $4
+		ORDER BY score DESC, length(text), text
+		LIMIT %d`, source, limit*3)
+
+	rows, err := s.db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []Suggestion
+	for rows.Next() {
+		var sg Suggestion
+		if err := rows.Scan(&sg.Text, &sg.EntityKind, &sg.Score); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, sg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return dedupeSuggestions(candidates, limit), nil
+}
+
+// dedupeSuggestions collapses case-insensitive duplicates, keeping the best
+// scored, and returns at most limit suggestions ordered by score then text.
+func dedupeSuggestions(candidates []Suggestion, limit int) []Suggestion {
+	best := make(map[string]Suggestion, len(candidates))
+	for _, c := range candidates {
+		key := strings.ToLower(strings.TrimSpace(c.Text))
+		if key == "" {
+			continue
+		}
+		if cur, ok := best[key]; !ok || c.Score > cur.Score {
+			best[key] = c
+		}
+	}
+	out := make([]Suggestion, 0, len(best))
+	for _, s := range best {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		if len(out[i].Text) != len(out[j].Text) {
+			return len(out[i].Text) < len(out[j].Text)
+		}
+		return out[i].Text < out[j].Text
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// escapeLike escapes LIKE wildcards in user input.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
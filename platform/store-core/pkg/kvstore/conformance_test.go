@@ -0,0 +1,133 @@
+package kvstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestStoreConformance runs the same behavioural checks against every Store
+// implementation. The Postgres run needs KV_TEST_DATABASE_URL.
+func TestStoreConformance(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testStore(t, NewMemoryStore())
+	})
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv("KV_TEST_DATABASE_URL")
+		if dsn == "" {
+			t.Skip("KV_TEST_DATABASE_URL not set")
+		}
+		t.Setenv("KV_DATABASE_URL", dsn)
+		store, err := NewPostgresStore()
+		if err != nil {
+			t.Fatalf("open postgres: %v", err)
+		}
+		defer store.Close()
+		testStore(t, store)
+	})
+}
+
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+	// Unique scope per run so repeated Postgres runs don't collide.
+	tenant := fmt.Sprintf("conformance-%d", os.Getpid())
+	const project = "p1"
+
+	v, err := s.Put(ctx, Record{TenantID: tenant, ProjectID: project, Key: "a/1", Value: []byte(`{"n":1}`)}, 0)
+	if err != nil || v != 1 {
+		t.Fatalf("insert: version=%d err=%v", v, err)
+	}
+	if _, err := s.Put(ctx, Record{TenantID: tenant, ProjectID: project, Key: "a/2", Value: []byte(`{}`)}, 3); err == nil {
+		t.Fatalf("expected version mismatch for missing key")
+	}
+	if _, err := s.Put(ctx, Record{TenantID: tenant, ProjectID: project, Key: "a/1", Value: []byte(`{"n":2}`)}, 7); err == nil {
+		t.Fatalf("expected version mismatch for stale version")
+	}
+	if v, err = s.Put(ctx, Record{TenantID: tenant, ProjectID: project, Key: "a/1", Value: []byte(`{"n":2}`)}, 1); err != nil || v != 2 {
+		t.Fatalf("update: version=%d err=%v", v, err)
+	}
+	if v, err = s.Put(ctx, Record{TenantID: tenant, ProjectID: project, Key: "a/1", Value: []byte(`{"n":3}`)}, 0); err != nil || v != 3 {
+		t.Fatalf("unconditional update: version=%d err=%v", v, err)
+	}
+
+	rec, err := s.Get(ctx, tenant, project, "a/1")
+	if err != nil || rec == nil || rec.Version != 3 || !sameJSON(rec.Value, `{"n":3}`) {
+		t.Fatalf("get: %+v err=%v", rec, err)
+	}
+	if rec, err := s.Get(ctx, tenant, "other", "a/1"); err != nil || rec != nil {
+		t.Fatalf("expected project isolation, got %+v err=%v", rec, err)
+	}
+
+	for _, k := range []string{"b/2", "a/3", "b/1"} {
+		if _, err := s.Put(ctx, Record{TenantID: tenant, ProjectID: project, Key: k, Value: []byte(`{}`)}, 0); err != nil {
+			t.Fatalf("put %s: %v", k, err)
+		}
+	}
+	keys, err := s.ListKeys(ctx, tenant, project, "a/", 0)
+	if err != nil || fmt.Sprint(keys) != "[a/1 a/3]" {
+		t.Fatalf("list prefix: %v err=%v", keys, err)
+	}
+	keys, err = s.ListKeys(ctx, tenant, project, "", 3)
+	if err != nil || fmt.Sprint(keys) != "[a/1 a/3 b/1]" {
+		t.Fatalf("list limit: %v err=%v", keys, err)
+	}
+	keys, err = s.ListKeysAfter(ctx, tenant, project, "", "a/3", 3)
+	if err != nil || fmt.Sprint(keys) != "[b/1 b/2]" {
+		t.Fatalf("list after: %v err=%v", keys, err)
+	}
+
+	if ok, err := s.Delete(ctx, tenant, project, "a/1", 2); err != nil || ok {
+		t.Fatalf("delete with stale version: ok=%v err=%v", ok, err)
+	}
+	if ok, err := s.Delete(ctx, tenant, project, "a/1", 3); err != nil || !ok {
+		t.Fatalf("delete: ok=%v err=%v", ok, err)
+	}
+	if ok, err := s.Delete(ctx, tenant, project, "a/1", 0); err != nil || ok {
+		t.Fatalf("delete missing: ok=%v err=%v", ok, err)
+	}
+	if v, err = s.Put(ctx, Record{TenantID: tenant, ProjectID: project, Key: "a/1", Value: []byte(`{}`)}, 0); err != nil || v != 1 {
+		t.Fatalf("re-insert after delete: version=%d err=%v", v, err)
+	}
+
+	// Concurrent compare-and-swap: exactly one writer wins each version.
+	const writers = 8
+	var wg sync.WaitGroup
+	wins := make(chan int64, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := s.Put(ctx, Record{TenantID: tenant, ProjectID: project, Key: "cas", Value: []byte(`{}`)}, 0); err == nil {
+				if _, err := s.Put(ctx, Record{TenantID: tenant, ProjectID: project, Key: "cas", Value: []byte(`{}`)}, v); err == nil {
+					wins <- v + 1
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(wins)
+	seen := map[int64]bool{}
+	for v := range wins {
+		if seen[v] {
+			t.Fatalf("version %d written twice", v)
+		}
+		seen[v] = true
+	}
+
+	for _, k := range []string{"a/1", "a/3", "b/1", "b/2", "cas"} {
+		_, _ = s.Delete(ctx, tenant, project, k, 0)
+	}
+}
+
+// sameJSON compares semantically; Postgres normalises jsonb whitespace.
+func sameJSON(got []byte, want string) bool {
+	var a, b any
+	if json.Unmarshal(got, &a) != nil || json.Unmarshal([]byte(want), &b) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
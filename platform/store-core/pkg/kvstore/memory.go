@@ -0,0 +1,103 @@
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryStore implements Store in process memory. It mirrors PostgresStore's
+// optimistic versioning and is intended for tests and local runs.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records map[memoryKey]Record
+}
+
+type memoryKey struct {
+	tenantID  string
+	projectID string
+	key       string
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[memoryKey]Record)}
+}
+
+func (s *MemoryStore) Put(_ context.Context, rec Record, expectedVersion int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := memoryKey{rec.TenantID, rec.ProjectID, rec.Key}
+	current, ok := s.records[k]
+	if !ok && expectedVersion > 0 {
+		return 0, fmt.Errorf("version mismatch: expected %d but key missing", expectedVersion)
+	}
+	if ok && expectedVersion > 0 && current.Version != expectedVersion {
+		return 0, fmt.Errorf("version mismatch: expected %d got %d", expectedVersion, current.Version)
+	}
+	next := current.Version + 1
+	s.records[k] = Record{
+		TenantID:  rec.TenantID,
+		ProjectID: rec.ProjectID,
+		Key:       rec.Key,
+		Value:     append([]byte(nil), rec.Value...),
+		Version:   next,
+	}
+	return next, nil
+}
+
+func (s *MemoryStore) Get(_ context.Context, tenantID, projectID, key string) (*Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.records[memoryKey{tenantID, projectID, key}]
+	if !ok {
+		return nil, nil
+	}
+	rec.Value = append([]byte(nil), rec.Value...)
+	return &rec, nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, tenantID, projectID, key string, expectedVersion int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := memoryKey{tenantID, projectID, key}
+	rec, ok := s.records[k]
+	if !ok || (expectedVersion > 0 && rec.Version != expectedVersion) {
+		return false, nil
+	}
+	delete(s.records, k)
+	return true, nil
+}
+
+func (s *MemoryStore) ListKeys(ctx context.Context, tenantID, projectID, prefix string, limit int) ([]string, error) {
+	return s.ListKeysAfter(ctx, tenantID, projectID, prefix, "", limit)
+}
+
+func (s *MemoryStore) ListKeysAfter(_ context.Context, tenantID, projectID, prefix, after string, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	s.mu.RLock()
+	var keys []string
+	for k := range s.records {
+		if k.tenantID == tenantID && k.projectID == projectID && strings.HasPrefix(k.key, prefix) && k.key > after {
+			keys = append(keys, k.key)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys, nil
+}
+
+func (s *MemoryStore) Close() error { return nil }
+
+var _ Store = (*MemoryStore)(nil)
@@ -26,6 +26,9 @@ type Store interface {
 	Get(ctx context.Context, tenantID, projectID, key string) (*Record, error)
 	Delete(ctx context.Context, tenantID, projectID, key string, expectedVersion int64) (bool, error)
 	ListKeys(ctx context.Context, tenantID, projectID, prefix string, limit int) ([]string, error)
+	// ListKeysAfter lists keys with the prefix that sort after the given key,
+	// so callers can page through every key by passing the last one seen.
+	ListKeysAfter(ctx context.Context, tenantID, projectID, prefix, after string, limit int) ([]string, error)
 	Close() error
 }
 
@@ -91,7 +94,7 @@ func (s *PostgresStore) Put(ctx context.Context, rec Record, expectedVersion int
 	defer tx.Rollback()
 
 	var currentVersion int64
-	err = tx.QueryRowContext(ctx, `SELECT version FROM kv_store WHERE tenant_id=$1 AND project_id=$2 AND key=$3`,
+	err = tx.QueryRowContext(ctx, `SELECT version FROM kv_store WHERE tenant_id=$1 AND project_id=$2 AND key=$3 FOR UPDATE`,
 		rec.TenantID, rec.ProjectID, rec.Key).Scan(&currentVersion)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -162,11 +165,15 @@ func (s *PostgresStore) Delete(ctx context.Context, tenantID, projectID, key str
 }
 
 func (s *PostgresStore) ListKeys(ctx context.Context, tenantID, projectID, prefix string, limit int) ([]string, error) {
+	return s.ListKeysAfter(ctx, tenantID, projectID, prefix, "", limit)
+}
+
+func (s *PostgresStore) ListKeysAfter(ctx context.Context, tenantID, projectID, prefix, after string, limit int) ([]string, error) {
 	if limit <= 0 {
 		limit = 100
 	}
-	rows, err := s.db.QueryContext(ctx, `SELECT key FROM kv_store WHERE tenant_id=$1 AND project_id=$2 AND key LIKE $3 ORDER BY key LIMIT $4`,
-		tenantID, projectID, prefix+"%", limit)
+	rows, err := s.db.QueryContext(ctx, `SELECT key FROM kv_store WHERE tenant_id=$1 AND project_id=$2 AND key LIKE $3 AND key > $4 ORDER BY key LIMIT $5`,
+		tenantID, projectID, prefix+"%", after, limit)
 	if err != nil {
 		return nil, err
 	}
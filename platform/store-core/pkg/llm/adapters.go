@@ -0,0 +1,121 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/nucleus/store-core/pkg/community"
+	"github.com/nucleus/store-core/pkg/graphrag"
+	"github.com/nucleus/store-core/pkg/ner"
+)
+
+// ===================================================
+// Component adapters
+// graphrag and ner both name their method Complete with their own option
+// types, so each component gets a thin wrapper around the same Client.
+// ===================================================
+
+func temperature(t float32) *float64 {
+	v := float64(t)
+	return &v
+}
+
+func userPrompt(system, prompt string, model string, maxTokens int, temp *float64) Request {
+	return Request{
+		Model:       model,
+		System:      system,
+		Messages:    []Message{{Role: "user", Content: prompt}},
+		MaxTokens:   maxTokens,
+		Temperature: temp,
+	}
+}
+
+// GraphRAGProvider implements graphrag.LLMProvider and
+// graphrag.StreamingLLMProvider.
+type GraphRAGProvider struct {
+	Client Client
+}
+
+// NewGraphRAGProvider wraps c for graphrag.NewService.
+func NewGraphRAGProvider(c Client) *GraphRAGProvider {
+	return &GraphRAGProvider{Client: c}
+}
+
+func (p *GraphRAGProvider) Name() string { return p.Client.Provider() }
+
+// DefaultModel reports the model used when a request names none.
+func (p *GraphRAGProvider) DefaultModel() string { return p.Client.Model() }
+
+func (p *GraphRAGProvider) Complete(ctx context.Context, prompt string, options graphrag.LLMCompletionOptions) (string, error) {
+	resp, err := p.Client.Chat(ctx, userPrompt(options.SystemPrompt, prompt, options.Model, options.MaxTokens, temperature(options.Temperature)))
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+func (p *GraphRAGProvider) CompleteStream(ctx context.Context, prompt string, options graphrag.LLMCompletionOptions, onDelta func(string) error) (string, error) {
+	resp, err := p.Client.ChatStream(ctx, userPrompt(options.SystemPrompt, prompt, options.Model, options.MaxTokens, temperature(options.Temperature)), onDelta)
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+// NERProvider implements ner.LLMProvider.
+type NERProvider struct {
+	Client Client
+}
+
+// NewNERProvider wraps c for ner.NewNERExtractor.
+func NewNERProvider(c Client) *NERProvider {
+	return &NERProvider{Client: c}
+}
+
+func (p *NERProvider) Name() string { return p.Client.Provider() }
+
+func (p *NERProvider) Complete(ctx context.Context, prompt string, options ner.CompletionOptions) (string, error) {
+	resp, err := p.Client.Chat(ctx, userPrompt(options.SystemPrompt, prompt, options.Model, options.MaxTokens, temperature(options.Temperature)))
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+// CommunityClient implements community.LLMClient. System messages become the
+// request's system prompt.
+type CommunityClient struct {
+	Client Client
+}
+
+// NewCommunityClient wraps c for community.NewLLMLabeler.
+func NewCommunityClient(c Client) *CommunityClient {
+	return &CommunityClient{Client: c}
+}
+
+func (p *CommunityClient) ChatComplete(ctx context.Context, model string, messages []community.Message, opts community.ChatOptions) (string, error) {
+	temp := opts.Temperature
+	req := Request{Model: model, MaxTokens: opts.MaxTokens, Temperature: &temp}
+	for _, m := range messages {
+		if m.Role == "system" {
+			if req.System != "" {
+				req.System += "\n\n"
+			}
+			req.System += m.Content
+			continue
+		}
+		req.Messages = append(req.Messages, Message{Role: m.Role, Content: m.Content})
+	}
+	resp, err := p.Client.Chat(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+// Ensure interface compliance
+var (
+	_ graphrag.LLMProvider          = (*GraphRAGProvider)(nil)
+	_ graphrag.StreamingLLMProvider = (*GraphRAGProvider)(nil)
+	_ ner.LLMProvider               = (*NERProvider)(nil)
+	_ community.LLMClient           = (*CommunityClient)(nil)
+)
@@ -0,0 +1,184 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// anthropicVersion is the Messages API version header value.
+const anthropicVersion = "2023-06-01"
+
+// anthropicClient speaks the Anthropic Messages API.
+type anthropicClient struct {
+	*base
+}
+
+type anthropicRequest struct {
+	Model       string    `json:"model"`
+	System      string    `json:"system,omitempty"`
+	Messages    []Message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicResponse struct {
+	Model   string `json:"model"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage anthropicUsage `json:"usage"`
+}
+
+// anthropicEvent is one server-sent event of a streamed message.
+type anthropicEvent struct {
+	Type    string             `json:"type"`
+	Message *anthropicResponse `json:"message"` // message_start
+	Delta   struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"` // content_block_delta
+	Usage *anthropicUsage `json:"usage"` // message_delta
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *anthropicClient) body(req Request, stream bool) anthropicRequest {
+	return anthropicRequest{
+		Model:       c.model(req.Model),
+		System:      req.System,
+		Messages:    req.Messages,
+		MaxTokens:   maxTokens(req.MaxTokens),
+		Temperature: req.Temperature,
+		Stream:      stream,
+	}
+}
+
+func (c *anthropicClient) post(ctx context.Context, body anthropicRequest) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, permanent("marshal request: %v", err)
+	}
+	url := strings.TrimSuffix(c.cfg.BaseURL, "/") + "/messages"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, permanent("create request: %v", err)
+	}
+	httpReq.Header.Set("content-type", "application/json")
+	httpReq.Header.Set("x-api-key", c.cfg.APIKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &StatusError{Provider: c.cfg.Provider, StatusCode: resp.StatusCode, Body: string(msg), retryAfter: parseRetryAfter(resp.Header)}
+	}
+	return resp, nil
+}
+
+func (c *anthropicClient) Chat(ctx context.Context, req Request) (*Response, error) {
+	body := c.body(req, false)
+	return c.withRetries(ctx, false, func(ctx context.Context) (*Response, error) {
+		resp, err := c.post(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		var parsed anthropicResponse
+		if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+			return nil, fmt.Errorf("parse response: %w", err)
+		}
+		var text strings.Builder
+		for _, block := range parsed.Content {
+			if block.Type == "text" {
+				text.WriteString(block.Text)
+			}
+		}
+		if text.Len() == 0 {
+			return nil, permanent("anthropic returned empty content")
+		}
+		return &Response{
+			Text:  text.String(),
+			Model: defaultString(parsed.Model, body.Model),
+			Usage: Usage{InputTokens: parsed.Usage.InputTokens, OutputTokens: parsed.Usage.OutputTokens},
+		}, nil
+	})
+}
+
+func (c *anthropicClient) ChatStream(ctx context.Context, req Request, onDelta func(string) error) (*Response, error) {
+	body := c.body(req, true)
+	return c.withRetries(ctx, true, func(ctx context.Context) (*Response, error) {
+		resp, err := c.post(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		out := &Response{Model: body.Model}
+		var full strings.Builder
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue // "event:" lines repeat the type carried in the data
+			}
+			var ev anthropicEvent
+			if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &ev); err != nil {
+				return nil, streamErr(full.Len(), fmt.Errorf("parse stream event: %w", err))
+			}
+			switch ev.Type {
+			case "message_start":
+				if ev.Message != nil {
+					out.Model = defaultString(ev.Message.Model, out.Model)
+					out.Usage.InputTokens = ev.Message.Usage.InputTokens
+				}
+			case "content_block_delta":
+				if ev.Delta.Type != "text_delta" || ev.Delta.Text == "" {
+					continue
+				}
+				full.WriteString(ev.Delta.Text)
+				if err := onDelta(ev.Delta.Text); err != nil {
+					return nil, streamErr(full.Len(), err)
+				}
+			case "message_delta":
+				if ev.Usage != nil {
+					out.Usage.OutputTokens = ev.Usage.OutputTokens
+				}
+			case "error":
+				msg := "unknown error"
+				if ev.Error != nil {
+					msg = ev.Error.Type + ": " + ev.Error.Message
+				}
+				return nil, streamErr(full.Len(), fmt.Errorf("anthropic stream error: %s", msg))
+			case "message_stop":
+				if full.Len() == 0 {
+					return nil, permanent("anthropic returned empty content")
+				}
+				out.Text = full.String()
+				return out, nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, streamErr(full.Len(), fmt.Errorf("stream read failed: %w", err))
+		}
+		return nil, streamErr(full.Len(), fmt.Errorf("anthropic stream ended without message_stop"))
+	})
+}
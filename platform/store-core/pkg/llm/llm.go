@@ -0,0 +1,365 @@
+// Package llm is the shared LLM client used by GraphRAG answers, NER,
+// community labelling and brain-core insights. One Config selects the
+// provider (OpenAI, Anthropic Messages, or any OpenAI-compatible server such
+// as vLLM, Ollama or llama.cpp) and the approved model; adapters expose the
+// client through each component's own provider interface.
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Provider names.
+const (
+	ProviderOpenAI           = "openai"
+	ProviderAnthropic        = "anthropic"
+	ProviderOpenAICompatible = "openai-compatible"
+)
+
+// Default models used when neither the request nor the config names one.
+const (
+	DefaultOpenAIModel    = "gpt-4o-mini"
+	DefaultAnthropicModel = "claude-3-haiku-20240307"
+)
+
+// Message is one chat message.
+type Message struct {
+	Role    string `json:"role"` // "user" or "assistant"
+	Content string `json:"content"`
+}
+
+// Request is a chat completion request.
+type Request struct {
+	Model       string // empty uses Config.Model
+	System      string
+	Messages    []Message
+	MaxTokens   int      // default 1024
+	Temperature *float64 // nil leaves the provider default
+}
+
+// Usage is the token accounting of one completion.
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// Response is a completed chat response.
+type Response struct {
+	Text  string
+	Model string // model that served the request
+	Usage Usage
+}
+
+// Client is a configured LLM endpoint.
+type Client interface {
+	// Chat returns the completion for req.
+	Chat(ctx context.Context, req Request) (*Response, error)
+	// ChatStream calls onDelta with each text delta and returns the full
+	// response once the stream ends.
+	ChatStream(ctx context.Context, req Request, onDelta func(string) error) (*Response, error)
+	// Provider returns the provider name.
+	Provider() string
+	// Model returns the default model.
+	Model() string
+	// Stats returns the client's counters.
+	Stats() Stats
+}
+
+// Config configures a Client.
+type Config struct {
+	Provider string
+	// BaseURL overrides the API root, e.g. "http://localhost:11434/v1" for
+	// Ollama. Required for ProviderOpenAICompatible.
+	BaseURL string
+	// APIKey defaults to OPENAI_API_KEY or ANTHROPIC_API_KEY by provider.
+	// OpenAI-compatible servers may not need one.
+	APIKey string
+	// Model is used for requests that name no model.
+	Model string
+	// AllowedModels, when set, restricts requests to these models; requests
+	// for any other model are served with Model instead. This lets every
+	// component follow the approved model even when it has its own default.
+	AllowedModels []string
+	// Timeout bounds each attempt of a non-streaming call (default 60s).
+	Timeout time.Duration
+	// MaxRetries is the number of retries after a retryable failure (429,
+	// 5xx, transport errors). Default 2; negative disables retries.
+	MaxRetries int
+	// RetryBackoff is the first retry delay, doubled per retry (default 500ms).
+	RetryBackoff time.Duration
+	// HTTPClient overrides the transport; its Timeout is replaced by Timeout.
+	HTTPClient *http.Client
+}
+
+// ConfigFromEnv reads the LLM_* variables. A non-empty prefix lets a
+// component override them, e.g. prefix "INSIGHT_" reads INSIGHT_PROVIDER
+// before LLM_PROVIDER:
+//
+//	LLM_PROVIDER         openai (default), anthropic or openai-compatible
+//	LLM_BASE_URL         API root for OpenAI-compatible servers
+//	LLM_API_KEY          overrides OPENAI_API_KEY / ANTHROPIC_API_KEY
+//	LLM_MODEL            approved model; when set, all requests use it
+//	LLM_ALLOWED_MODELS   comma-separated models requests may name
+//	LLM_TIMEOUT_SECONDS  per-attempt timeout (default 60)
+//	LLM_MAX_RETRIES      retries after retryable failures (default 2)
+func ConfigFromEnv(prefix string) Config {
+	get := func(name string) string {
+		if prefix != "" {
+			if v := strings.TrimSpace(os.Getenv(prefix + name)); v != "" {
+				return v
+			}
+		}
+		return strings.TrimSpace(os.Getenv("LLM_" + name))
+	}
+	cfg := Config{
+		Provider: strings.ToLower(get("PROVIDER")),
+		BaseURL:  get("BASE_URL"),
+		APIKey:   get("API_KEY"),
+		Model:    get("MODEL"),
+	}
+	for _, m := range strings.Split(get("ALLOWED_MODELS"), ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			cfg.AllowedModels = append(cfg.AllowedModels, m)
+		}
+	}
+	if len(cfg.AllowedModels) == 0 && cfg.Model != "" {
+		cfg.AllowedModels = []string{cfg.Model}
+	}
+	if secs, err := strconv.Atoi(get("TIMEOUT_SECONDS")); err == nil && secs > 0 {
+		cfg.Timeout = time.Duration(secs) * time.Second
+	}
+	if n, err := strconv.Atoi(get("MAX_RETRIES")); err == nil {
+		cfg.MaxRetries = n
+		if n == 0 {
+			cfg.MaxRetries = -1
+		}
+	}
+	return cfg
+}
+
+// New creates a client for cfg.
+func New(cfg Config) (Client, error) {
+	if cfg.Provider == "" {
+		cfg.Provider = ProviderOpenAI
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 60 * time.Second
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 2
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 500 * time.Millisecond
+	}
+	if cfg.Model == "" && len(cfg.AllowedModels) > 0 {
+		cfg.Model = cfg.AllowedModels[0]
+	}
+	httpClient := &http.Client{}
+	if cfg.HTTPClient != nil {
+		copied := *cfg.HTTPClient
+		httpClient = &copied
+	}
+	httpClient.Timeout = 0 // per attempt, via context
+	b := &base{cfg: cfg, http: httpClient}
+
+	switch cfg.Provider {
+	case ProviderOpenAI, ProviderOpenAICompatible:
+		if cfg.Provider == ProviderOpenAI {
+			if b.cfg.APIKey == "" {
+				b.cfg.APIKey = os.Getenv("OPENAI_API_KEY")
+			}
+			if b.cfg.APIKey == "" {
+				return nil, fmt.Errorf("OPENAI_API_KEY not set")
+			}
+			if b.cfg.BaseURL == "" {
+				b.cfg.BaseURL = "https://api.openai.com/v1"
+			}
+		} else if b.cfg.BaseURL == "" {
+			return nil, fmt.Errorf("base URL is required for %s", ProviderOpenAICompatible)
+		}
+		if b.cfg.Model == "" && cfg.Provider == ProviderOpenAI {
+			b.cfg.Model = DefaultOpenAIModel
+		}
+		return &openAIClient{base: b}, nil
+	case ProviderAnthropic:
+		if b.cfg.APIKey == "" {
+			b.cfg.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+		}
+		if b.cfg.APIKey == "" {
+			return nil, fmt.Errorf("ANTHROPIC_API_KEY not set")
+		}
+		if b.cfg.BaseURL == "" {
+			b.cfg.BaseURL = "https://api.anthropic.com/v1"
+		}
+		if b.cfg.Model == "" {
+			b.cfg.Model = DefaultAnthropicModel
+		}
+		return &anthropicClient{base: b}, nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider %q", cfg.Provider)
+	}
+}
+
+// Stats are a client's counters since it was created.
+type Stats struct {
+	Requests     uint64 // completions attempted, excluding retries
+	Retries      uint64
+	Errors       uint64 // completions that failed after retries
+	InputTokens  uint64
+	OutputTokens uint64
+}
+
+type meter struct {
+	requests, retries, errors, inputTokens, outputTokens uint64
+}
+
+func (m *meter) snapshot() Stats {
+	return Stats{
+		Requests:     atomic.LoadUint64(&m.requests),
+		Retries:      atomic.LoadUint64(&m.retries),
+		Errors:       atomic.LoadUint64(&m.errors),
+		InputTokens:  atomic.LoadUint64(&m.inputTokens),
+		OutputTokens: atomic.LoadUint64(&m.outputTokens),
+	}
+}
+
+func (m *meter) record(resp *Response, err error) {
+	if err != nil {
+		atomic.AddUint64(&m.errors, 1)
+		return
+	}
+	atomic.AddUint64(&m.inputTokens, uint64(resp.Usage.InputTokens))
+	atomic.AddUint64(&m.outputTokens, uint64(resp.Usage.OutputTokens))
+}
+
+// base holds what all providers share: config, transport, retries, counters.
+type base struct {
+	cfg   Config
+	http  *http.Client
+	meter meter
+}
+
+func (b *base) Provider() string { return b.cfg.Provider }
+func (b *base) Model() string    { return b.cfg.Model }
+func (b *base) Stats() Stats     { return b.meter.snapshot() }
+
+// model resolves the model for a request.
+func (b *base) model(requested string) string {
+	if requested == "" {
+		return b.cfg.Model
+	}
+	if len(b.cfg.AllowedModels) == 0 {
+		return requested
+	}
+	for _, m := range b.cfg.AllowedModels {
+		if m == requested {
+			return requested
+		}
+	}
+	return b.cfg.Model
+}
+
+// StatusError is a non-2xx response from the provider.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+	retryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s error status %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the request may succeed if repeated.
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusRequestTimeout || e.StatusCode >= 500
+}
+
+// withRetries runs attempt until it succeeds, fails permanently or retries
+// run out. Each non-streaming attempt gets its own timeout.
+func (b *base) withRetries(ctx context.Context, streaming bool, attempt func(ctx context.Context) (*Response, error)) (*Response, error) {
+	atomic.AddUint64(&b.meter.requests, 1)
+	delay := b.cfg.RetryBackoff
+	for i := 0; ; i++ {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if !streaming {
+			attemptCtx, cancel = context.WithTimeout(ctx, b.cfg.Timeout)
+		}
+		resp, err := attempt(attemptCtx)
+		cancel()
+		if err == nil || i >= b.cfg.MaxRetries || ctx.Err() != nil || !retryable(err) {
+			b.meter.record(resp, err)
+			return resp, err
+		}
+		wait := delay
+		var se *StatusError
+		if errors.As(err, &se) && se.retryAfter > 0 {
+			wait = se.retryAfter
+		}
+		// Jitter spreads retries of concurrent callers.
+		wait += time.Duration(rand.Int63n(int64(wait)/4 + 1))
+		atomic.AddUint64(&b.meter.retries, 1)
+		select {
+		case <-ctx.Done():
+			b.meter.record(nil, ctx.Err())
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// errStreamStarted marks streaming failures after text was delivered; those
+// cannot be retried without repeating output.
+var errStreamStarted = errors.New("stream interrupted")
+
+func retryable(err error) bool {
+	if errors.Is(err, errStreamStarted) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Retryable()
+	}
+	var perm *permanentError
+	return !errors.As(err, &perm) // transport errors and attempt timeouts
+}
+
+// permanentError wraps failures that repeating the request will not fix,
+// such as malformed responses.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func permanent(format string, args ...any) error {
+	return &permanentError{err: fmt.Errorf(format, args...)}
+}
+
+func parseRetryAfter(h http.Header) time.Duration {
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil && secs > 0 {
+		d := time.Duration(secs) * time.Second
+		if d > time.Minute {
+			d = time.Minute
+		}
+		return d
+	}
+	return 0
+}
+
+func maxTokens(n int) int {
+	if n <= 0 {
+		return 1024
+	}
+	return n
+}
@@ -0,0 +1,139 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nucleus/store-core/pkg/community"
+	"github.com/nucleus/store-core/pkg/graphrag"
+	"github.com/nucleus/store-core/pkg/llm/llmtest"
+)
+
+func newTestClient(t *testing.T, cfg Config) Client {
+	t.Helper()
+	cfg.RetryBackoff = time.Millisecond
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestOpenAICompatibleChat(t *testing.T) {
+	srv := llmtest.NewServer(llmtest.Reply{Text: "Paris", InputTokens: 12, OutputTokens: 1})
+	defer srv.Close()
+	c := newTestClient(t, Config{Provider: ProviderOpenAICompatible, BaseURL: srv.OpenAIBaseURL(), Model: "llama3.1:8b"})
+
+	resp, err := c.Chat(context.Background(), Request{System: "Be brief.", Messages: []Message{{Role: "user", Content: "Capital of France?"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != "Paris" || resp.Model != "llama3.1:8b" || resp.Usage != (Usage{InputTokens: 12, OutputTokens: 1}) {
+		t.Fatalf("response = %+v", resp)
+	}
+	req := srv.Requests()[0]
+	if req.Header.Get("Authorization") != "" {
+		t.Fatal("no API key configured, no Authorization header expected")
+	}
+	if len(req.Messages) != 2 || req.Messages[0]["role"] != "system" || req.Temperature != nil || req.MaxTokens != 1024 {
+		t.Fatalf("request = %+v", req)
+	}
+	if stats := c.Stats(); stats.Requests != 1 || stats.InputTokens != 12 || stats.OutputTokens != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestAnthropicStream(t *testing.T) {
+	srv := llmtest.NewServer(llmtest.Reply{Text: "The billing service is owned by Alice.", InputTokens: 40, OutputTokens: 9})
+	defer srv.Close()
+	c := newTestClient(t, Config{Provider: ProviderAnthropic, BaseURL: srv.AnthropicBaseURL(), APIKey: "k"})
+
+	var deltas []string
+	resp, err := c.ChatStream(context.Background(), Request{System: "Cite sources.", Messages: []Message{{Role: "user", Content: "Who owns billing?"}}},
+		func(d string) error { deltas = append(deltas, d); return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deltas) < 2 || strings.Join(deltas, "") != resp.Text || resp.Usage != (Usage{InputTokens: 40, OutputTokens: 9}) {
+		t.Fatalf("deltas = %q, response = %+v", deltas, resp)
+	}
+	req := srv.Requests()[0]
+	if req.System != "Cite sources." || req.Model != DefaultAnthropicModel || req.Header.Get("x-api-key") != "k" || req.Header.Get("anthropic-version") == "" {
+		t.Fatalf("request = %+v", req)
+	}
+}
+
+func TestRetriesTransientFailures(t *testing.T) {
+	srv := llmtest.NewServer(
+		llmtest.Reply{Status: http.StatusTooManyRequests, Body: "slow down"},
+		llmtest.Reply{Status: http.StatusServiceUnavailable, Body: "overloaded"},
+		llmtest.Reply{Text: "ok"},
+	)
+	defer srv.Close()
+	c := newTestClient(t, Config{Provider: ProviderOpenAI, BaseURL: srv.OpenAIBaseURL(), APIKey: "k"})
+	resp, err := c.Chat(context.Background(), Request{Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil || resp.Text != "ok" {
+		t.Fatalf("resp = %+v, err = %v", resp, err)
+	}
+	if stats := c.Stats(); stats.Retries != 2 || stats.Errors != 0 {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	bad := llmtest.NewServer(llmtest.Reply{Status: http.StatusBadRequest, Body: "bad model"})
+	defer bad.Close()
+	c = newTestClient(t, Config{Provider: ProviderOpenAI, BaseURL: bad.OpenAIBaseURL(), APIKey: "k"})
+	_, err = c.Chat(context.Background(), Request{Messages: []Message{{Role: "user", Content: "hi"}}})
+	var se *StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusBadRequest || len(bad.Requests()) != 1 {
+		t.Fatalf("4xx must not be retried: %v (%d requests)", err, len(bad.Requests()))
+	}
+	if stats := c.Stats(); stats.Errors != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestAllowedModelsPinComponentDefaults(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", ProviderOpenAICompatible)
+	t.Setenv("LLM_MODEL", "approved-model")
+	t.Setenv("INSIGHT_MODEL", "")
+	srv := llmtest.NewServer()
+	defer srv.Close()
+	cfg := ConfigFromEnv("INSIGHT_")
+	cfg.BaseURL = srv.OpenAIBaseURL()
+	c := newTestClient(t, cfg)
+
+	// The community labeler asks for its own default model.
+	labeler := NewCommunityClient(c)
+	out, err := labeler.ChatComplete(context.Background(), "gpt-4o-mini", []community.Message{
+		{Role: "system", Content: "Label the community."},
+		{Role: "user", Content: "alice bob billing"},
+	}, community.ChatOptions{MaxTokens: 50})
+	if err != nil || out != "echo: alice bob billing" {
+		t.Fatalf("out = %q, err = %v", out, err)
+	}
+	req := srv.Requests()[0]
+	if req.Model != "approved-model" || len(req.Messages) != 2 || req.Messages[0]["content"] != "Label the community." {
+		t.Fatalf("request = %+v", req)
+	}
+}
+
+func TestGraphRAGProviderStreamsCitedAnswer(t *testing.T) {
+	srv := llmtest.NewServer(llmtest.Reply{Text: "Alice owns the billing service [1]."})
+	defer srv.Close()
+	c := newTestClient(t, Config{Provider: ProviderOpenAICompatible, BaseURL: srv.OpenAIBaseURL(), Model: "qwen2.5"})
+
+	ragCtx := graphrag.NewRAGContext("t1", "who owns billing?")
+	ragCtx.AddSeed(graphrag.EntityMatch{ID: "svc-billing", Name: "Billing service"})
+	svc := graphrag.NewService(nil, nil, nil, nil, NewGraphRAGProvider(c))
+	answer, err := svc.GenerateAnswer(context.Background(), &graphrag.GenerateAnswerRequest{TenantID: "t1", Query: "who owns billing?", Context: ragCtx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer.ModelUsed != "openai-compatible/qwen2.5" || len(answer.Citations) != 1 || answer.Citations[0].SourceID != "svc-billing" {
+		t.Fatalf("answer = %+v", answer)
+	}
+}
@@ -0,0 +1,176 @@
+// Package llmtest provides an httptest server that speaks the OpenAI chat
+// completions and Anthropic Messages APIs, for testing code built on llm.
+package llmtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Reply is the scripted outcome of one request.
+type Reply struct {
+	Text         string
+	InputTokens  int
+	OutputTokens int
+	// Status, when non-zero and not 200, fails the request with Body.
+	Status     int
+	Body       string
+	RetryAfter string
+}
+
+// Request is a request the server received.
+type Request struct {
+	API         string // "openai" or "anthropic"
+	Path        string
+	Header      http.Header
+	Model       string
+	System      string
+	Messages    []map[string]any
+	MaxTokens   int
+	Temperature *float64
+	Stream      bool
+}
+
+// Server is a fake LLM API. Replies are served in order; once they run out,
+// the last one repeats. With no replies, the server echoes the last message.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	replies  []Reply
+	requests []Request
+}
+
+// NewServer starts a fake server. Close it when done.
+func NewServer(replies ...Reply) *Server {
+	s := &Server{replies: replies}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// OpenAIBaseURL is the base URL for OpenAI-compatible clients.
+func (s *Server) OpenAIBaseURL() string { return s.URL + "/v1" }
+
+// AnthropicBaseURL is the base URL for Anthropic clients.
+func (s *Server) AnthropicBaseURL() string { return s.URL + "/anthropic/v1" }
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) next(req Request) Reply {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+	if len(s.replies) == 0 {
+		var last string
+		if n := len(req.Messages); n > 0 {
+			last, _ = req.Messages[n-1]["content"].(string)
+		}
+		return Reply{Text: "echo: " + last, InputTokens: len(strings.Fields(last)), OutputTokens: len(strings.Fields(last)) + 1}
+	}
+	reply := s.replies[0]
+	if len(s.replies) > 1 {
+		s.replies = s.replies[1:]
+	}
+	return reply
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Model       string           `json:"model"`
+		System      string           `json:"system"`
+		Messages    []map[string]any `json:"messages"`
+		MaxTokens   int              `json:"max_tokens"`
+		Temperature *float64         `json:"temperature"`
+		Stream      bool             `json:"stream"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := Request{
+		Path: r.URL.Path, Header: r.Header.Clone(), Model: body.Model, System: body.System,
+		Messages: body.Messages, MaxTokens: body.MaxTokens, Temperature: body.Temperature, Stream: body.Stream,
+	}
+	switch r.URL.Path {
+	case "/v1/chat/completions":
+		req.API = "openai"
+	case "/anthropic/v1/messages":
+		req.API = "anthropic"
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	reply := s.next(req)
+	if reply.Status != 0 && reply.Status != http.StatusOK {
+		if reply.RetryAfter != "" {
+			w.Header().Set("Retry-After", reply.RetryAfter)
+		}
+		http.Error(w, reply.Body, reply.Status)
+		return
+	}
+	switch {
+	case req.API == "openai" && req.Stream:
+		writeOpenAIStream(w, body.Model, reply)
+	case req.API == "openai":
+		writeJSON(w, map[string]any{
+			"model":   body.Model,
+			"choices": []any{map[string]any{"message": map[string]any{"role": "assistant", "content": reply.Text}}},
+			"usage":   map[string]any{"prompt_tokens": reply.InputTokens, "completion_tokens": reply.OutputTokens},
+		})
+	case req.Stream:
+		writeAnthropicStream(w, body.Model, reply)
+	default:
+		writeJSON(w, map[string]any{
+			"model":   body.Model,
+			"content": []any{map[string]any{"type": "text", "text": reply.Text}},
+			"usage":   map[string]any{"input_tokens": reply.InputTokens, "output_tokens": reply.OutputTokens},
+		})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// words splits text into stream deltas, keeping the separating spaces.
+func words(text string) []string {
+	return strings.SplitAfter(text, " ")
+}
+
+func writeOpenAIStream(w http.ResponseWriter, model string, reply Reply) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	send := func(v any) {
+		data, _ := json.Marshal(v)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+	}
+	for _, word := range words(reply.Text) {
+		send(map[string]any{"model": model, "choices": []any{map[string]any{"delta": map[string]any{"content": word}}}})
+	}
+	send(map[string]any{"model": model, "choices": []any{}, "usage": map[string]any{"prompt_tokens": reply.InputTokens, "completion_tokens": reply.OutputTokens}})
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+func writeAnthropicStream(w http.ResponseWriter, model string, reply Reply) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	send := func(event string, v any) {
+		data, _ := json.Marshal(v)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	}
+	send("message_start", map[string]any{"type": "message_start", "message": map[string]any{"model": model, "usage": map[string]any{"input_tokens": reply.InputTokens}}})
+	for _, word := range words(reply.Text) {
+		send("content_block_delta", map[string]any{"type": "content_block_delta", "delta": map[string]any{"type": "text_delta", "text": word}})
+	}
+	send("message_delta", map[string]any{"type": "message_delta", "usage": map[string]any{"output_tokens": reply.OutputTokens}})
+	send("message_stop", map[string]any{"type": "message_stop"})
+}
@@ -0,0 +1,187 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// openAIClient speaks the OpenAI chat completions API, which vLLM, Ollama and
+// llama.cpp also serve.
+type openAIClient struct {
+	*base
+}
+
+type openAIRequest struct {
+	Model         string               `json:"model"`
+	Messages      []Message            `json:"messages"`
+	Temperature   *float64             `json:"temperature,omitempty"`
+	MaxTokens     int                  `json:"max_tokens,omitempty"`
+	Stream        bool                 `json:"stream,omitempty"`
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+func (c *openAIClient) body(req Request, stream bool) openAIRequest {
+	messages := make([]Message, 0, len(req.Messages)+1)
+	if req.System != "" {
+		messages = append(messages, Message{Role: "system", Content: req.System})
+	}
+	messages = append(messages, req.Messages...)
+	body := openAIRequest{
+		Model:       c.model(req.Model),
+		Messages:    messages,
+		Temperature: req.Temperature,
+		MaxTokens:   maxTokens(req.MaxTokens),
+		Stream:      stream,
+	}
+	if stream {
+		body.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+	}
+	return body
+}
+
+func (c *openAIClient) post(ctx context.Context, body openAIRequest) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, permanent("marshal request: %v", err)
+	}
+	url := strings.TrimSuffix(c.cfg.BaseURL, "/") + "/chat/completions"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, permanent("create request: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.cfg.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+	if body.Stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &StatusError{Provider: c.cfg.Provider, StatusCode: resp.StatusCode, Body: string(msg), retryAfter: parseRetryAfter(resp.Header)}
+	}
+	return resp, nil
+}
+
+func (c *openAIClient) Chat(ctx context.Context, req Request) (*Response, error) {
+	body := c.body(req, false)
+	return c.withRetries(ctx, false, func(ctx context.Context) (*Response, error) {
+		resp, err := c.post(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		var parsed openAIResponse
+		if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+			return nil, fmt.Errorf("parse response: %w", err)
+		}
+		if len(parsed.Choices) == 0 || parsed.Choices[0].Message.Content == "" {
+			return nil, permanent("%s returned empty content", c.cfg.Provider)
+		}
+		out := &Response{Text: parsed.Choices[0].Message.Content, Model: defaultString(parsed.Model, body.Model)}
+		if parsed.Usage != nil {
+			out.Usage = Usage{InputTokens: parsed.Usage.PromptTokens, OutputTokens: parsed.Usage.CompletionTokens}
+		}
+		return out, nil
+	})
+}
+
+func (c *openAIClient) ChatStream(ctx context.Context, req Request, onDelta func(string) error) (*Response, error) {
+	body := c.body(req, true)
+	return c.withRetries(ctx, true, func(ctx context.Context) (*Response, error) {
+		resp, err := c.post(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		out := &Response{Model: body.Model}
+		var full strings.Builder
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if payload == "[DONE]" {
+				break
+			}
+			var chunk openAIResponse
+			if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+				return nil, streamErr(full.Len(), fmt.Errorf("parse stream chunk: %w", err))
+			}
+			if chunk.Model != "" {
+				out.Model = chunk.Model
+			}
+			if chunk.Usage != nil {
+				out.Usage = Usage{InputTokens: chunk.Usage.PromptTokens, OutputTokens: chunk.Usage.CompletionTokens}
+			}
+			if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+				continue
+			}
+			delta := chunk.Choices[0].Delta.Content
+			full.WriteString(delta)
+			if err := onDelta(delta); err != nil {
+				return nil, streamErr(full.Len(), err)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, streamErr(full.Len(), fmt.Errorf("stream read failed: %w", err))
+		}
+		if full.Len() == 0 {
+			return nil, permanent("%s returned empty content", c.cfg.Provider)
+		}
+		out.Text = full.String()
+		return out, nil
+	})
+}
+
+// streamErr makes failures after the first delta non-retryable.
+func streamErr(emitted int, err error) error {
+	if emitted > 0 {
+		return fmt.Errorf("%w: %w", errStreamStarted, err)
+	}
+	return err
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
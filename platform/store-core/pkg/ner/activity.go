@@ -23,6 +23,9 @@ type ActivityConfig struct {
 	// Extraction settings
 	ExtractEntities bool `json:"extractEntities"`
 	ClassifyEPP     bool `json:"classifyEpp"`
+	// ExtractionMode is "llm", "rules" or "hybrid" (rules merged with LLM
+	// output). Without an LLM provider extraction always uses rules.
+	ExtractionMode string `json:"extractionMode"`
 	
 	// Observer settings
 	AutoMergeThreshold float32 `json:"autoMergeThreshold"`
@@ -37,38 +40,80 @@ func DefaultActivityConfig() ActivityConfig {
 		Temperature:        0.1,
 		ExtractEntities:    true,
 		ClassifyEPP:        true,
+		ExtractionMode:     ExtractionHybrid,
 		AutoMergeThreshold: 0.9,
 		TrackCrossSource:   true,
 	}
 }
 
+// Extraction modes.
+const (
+	ExtractionLLM    = "llm"
+	ExtractionRules  = "rules"
+	ExtractionHybrid = "hybrid"
+)
+
 // NERActivity orchestrates NER and EPP classification.
 type NERActivity struct {
-	extractor  *NERExtractor
-	classifier *EPPClassifier
+	extractor  Extractor
+	rules      *RuleExtractor
+	classifier *EPPClassifier // nil without an LLM provider
 	observer   *EntityObserver
 	matcher    entity.EntityMatcher
 	config     ActivityConfig
 }
 
-// NewNERActivity creates a new NER activity.
+// NewNERActivity creates a new NER activity with an in-memory observation
+// store.
 func NewNERActivity(
 	provider LLMProvider,
 	matcher entity.EntityMatcher,
 	config ActivityConfig,
 ) *NERActivity {
-	observer := NewEntityObserver(matcher)
+	return NewNERActivityWithStore(provider, matcher, config, NewMemoryObservationStore())
+}
+
+// NewNERActivityWithStore creates a NER activity whose observations and
+// review queue persist in store.
+func NewNERActivityWithStore(
+	provider LLMProvider,
+	matcher entity.EntityMatcher,
+	config ActivityConfig,
+	store ObservationStore,
+) *NERActivity {
+	observer := NewEntityObserverWithStore(matcher, store)
 	// P2 Fix: Apply auto-merge threshold from config
 	observer.SetAutoMergeThreshold(config.AutoMergeThreshold)
 	
+	a := &NERActivity{
+		rules:    NewRuleExtractor(nil),
+		observer: observer,
+		matcher:  matcher,
+		config:   config,
+	}
+	if provider == nil {
+		a.extractor = a.rules
+		return a
+	}
+
 	// P2 Fix: Pass MaxTokens and Temperature from config to extractors
-	return &NERActivity{
-		extractor:  NewNERExtractorWithConfig(provider, config.Model, config.MaxTokens, config.Temperature),
-		classifier: NewEPPClassifierWithConfig(provider, config.Model, config.MaxTokens, config.Temperature),
-		observer:   observer,
-		matcher:    matcher,
-		config:     config,
+	llm := NewNERExtractorWithConfig(provider, config.Model, config.MaxTokens, config.Temperature)
+	switch config.ExtractionMode {
+	case ExtractionRules:
+		a.extractor = a.rules
+	case ExtractionHybrid:
+		a.extractor = NewCombinedExtractor(a.rules, llm)
+	default:
+		a.extractor = llm
 	}
+	a.classifier = NewEPPClassifierWithConfig(provider, config.Model, config.MaxTokens, config.Temperature)
+	return a
+}
+
+// SetGazetteer sets the known names the rule extractor matches, typically
+// from LoadGazetteer. It can be called again to refresh them.
+func (a *NERActivity) SetGazetteer(g *Gazetteer) {
+	a.rules.SetGazetteer(g)
 }
 
 // ProcessRequest represents a request to process content.
@@ -152,7 +197,7 @@ func (a *NERActivity) Process(ctx context.Context, req ProcessRequest) (*Process
 	}
 
 	// Step 2: Classify as EPP
-	if a.config.ClassifyEPP {
+	if a.config.ClassifyEPP && a.classifier != nil {
 		classifyReq := ClassifyRequest{
 			TenantID:   req.TenantID,
 			Title:      req.Title,
@@ -173,11 +218,12 @@ func (a *NERActivity) Process(ctx context.Context, req ProcessRequest) (*Process
 
 	// Step 3: Resolve observations to canonical entities
 	if a.config.TrackCrossSource && len(result.Observations) > 0 {
-		for _, obs := range result.Observations {
-			resolvedObs, err := a.observer.ResolveObservation(ctx, obs.ID)
+		for i, obs := range result.Observations {
+			resolvedObs, err := a.observer.ResolveObservation(ctx, req.TenantID, obs.ID)
 			if err != nil {
 				continue
 			}
+			result.Observations[i] = resolvedObs
 
 			switch resolvedObs.Status {
 			case StatusMatched:
@@ -209,30 +255,43 @@ func (a *NERActivity) Process(ctx context.Context, req ProcessRequest) (*Process
 
 // GetCrossSourceView returns a cross-source view of an entity.
 // P0 Fix: Added tenantID parameter for tenant isolation.
-func (a *NERActivity) GetCrossSourceView(tenantID string, normalized string, entityType EntityType) *CrossSourceEntityView {
-	return a.observer.BuildCrossSourceView(tenantID, normalized, entityType)
+func (a *NERActivity) GetCrossSourceView(ctx context.Context, tenantID string, normalized string, entityType EntityType) (*CrossSourceEntityView, error) {
+	return a.observer.BuildCrossSourceView(ctx, tenantID, normalized, entityType)
+}
+
+// ReviewQueuePage is one page of the review queue.
+type ReviewQueuePage struct {
+	Observations []*ObservedEntity `json:"observations"`
+	Total        int               `json:"total"` // Queue length across all pages
 }
 
-// GetPendingReviews returns observations needing manual review.
-func (a *NERActivity) GetPendingReviews(tenantID string) []*ObservedEntity {
-	return a.observer.GetReviewObservations(tenantID)
+// GetPendingReviews returns a page of observations needing manual review,
+// highest match score first. Only TenantID, EntityType, SourceType, Limit
+// and Offset of q are used.
+func (a *NERActivity) GetPendingReviews(ctx context.Context, q ObservationQuery) (*ReviewQueuePage, error) {
+	obs, total, err := a.observer.GetReviewQueue(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return &ReviewQueuePage{Observations: obs, Total: total}, nil
 }
 
-// ApproveEntityMatch approves a match for an observation.
+// ApproveEntityMatch approves matches for observations on behalf of
+// reviewer. An empty canonicalID accepts each observation's suggestion.
 // P1 Fix: Added tenantID parameter for tenant isolation.
-func (a *NERActivity) ApproveEntityMatch(tenantID, obsID, canonicalID string) error {
-	return a.observer.ApproveMatch(tenantID, obsID, canonicalID)
+func (a *NERActivity) ApproveEntityMatch(ctx context.Context, tenantID string, obsIDs []string, canonicalID, reviewer, note string) (*ReviewResult, error) {
+	return a.observer.ApproveMatches(ctx, tenantID, obsIDs, canonicalID, reviewer, note)
 }
 
-// RejectEntity rejects an observation as invalid.
+// RejectEntity rejects observations as invalid on behalf of reviewer.
 // P1 Fix: Added tenantID parameter for tenant isolation.
-func (a *NERActivity) RejectEntity(tenantID, obsID string) error {
-	return a.observer.RejectObservation(tenantID, obsID)
+func (a *NERActivity) RejectEntity(ctx context.Context, tenantID string, obsIDs []string, reviewer, note string) (*ReviewResult, error) {
+	return a.observer.RejectObservations(ctx, tenantID, obsIDs, reviewer, note)
 }
 
 // GetObserverStats returns observation statistics.
-func (a *NERActivity) GetObserverStats(tenantID string) ObserverStats {
-	return a.observer.Stats(tenantID)
+func (a *NERActivity) GetObserverStats(ctx context.Context, tenantID string) (ObserverStats, error) {
+	return a.observer.Stats(ctx, tenantID)
 }
 
 // ===================================================
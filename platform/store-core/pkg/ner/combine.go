@@ -0,0 +1,88 @@
+package ner
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ===================================================
+// Combined Extractor
+// Rule spans merged with LLM output
+// ===================================================
+
+// CombinedExtractor runs the rule extractor and, when configured, the LLM
+// extractor, and merges their spans. Rule spans win where the two overlap
+// (they are exact and carry registry IDs), picking up any LLM qualifiers;
+// LLM entities the rules missed are added. If the LLM fails the rule
+// result is returned on its own.
+type CombinedExtractor struct {
+	rules Extractor
+	llm   Extractor // optional
+}
+
+// NewCombinedExtractor combines rules with an optional LLM extractor.
+func NewCombinedExtractor(rules, llm Extractor) *CombinedExtractor {
+	return &CombinedExtractor{rules: rules, llm: llm}
+}
+
+// Extract extracts entities from the given text.
+func (c *CombinedExtractor) Extract(ctx context.Context, req NERRequest) (*NERResponse, error) {
+	start := time.Now()
+	ruleResp, err := c.rules.Extract(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if c.llm == nil {
+		return ruleResp, nil
+	}
+
+	llmResp, err := c.llm.Extract(ctx, req)
+	if err != nil {
+		return ruleResp, nil // LLM is enrichment; rules already cover the text
+	}
+
+	return &NERResponse{
+		Entities:     mergeSpans(ruleResp.Entities, llmResp.Entities),
+		ProcessingMs: time.Since(start).Milliseconds(),
+		ModelUsed:    ruleResp.ModelUsed + "+" + llmResp.ModelUsed,
+		TokensUsed:   ruleResp.TokensUsed + llmResp.TokensUsed,
+	}, nil
+}
+
+// mergeSpans adds LLM entities to the rule entities. An LLM entity that
+// overlaps a rule span (or, without an offset, repeats its text) only
+// contributes the qualifiers the rule span lacks; the rule span keeps its
+// own text, offset and context snippet.
+func mergeSpans(rules, llm []ExtractedEntity) []ExtractedEntity {
+	merged := make([]ExtractedEntity, len(rules), len(rules)+len(llm))
+	copy(merged, rules)
+	for _, l := range llm {
+		idx := -1
+		for i := range rules {
+			r := &merged[i]
+			if l.Offset >= 0 && l.Offset < r.Offset+r.Length && r.Offset < l.Offset+l.Length ||
+				l.Offset < 0 && strings.EqualFold(l.Text, r.Text) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			merged = append(merged, l)
+			continue
+		}
+		r := &merged[idx]
+		for k, v := range l.Qualifiers {
+			if _, ok := r.Qualifiers[k]; !ok {
+				if r.Qualifiers == nil {
+					r.Qualifiers = map[string]string{}
+				}
+				r.Qualifiers[k] = v
+			}
+		}
+	}
+	return merged
+}
+
+// Ensure interface compliance
+var _ Extractor = (*CombinedExtractor)(nil)
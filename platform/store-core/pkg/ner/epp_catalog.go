@@ -0,0 +1,181 @@
+package ner
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"time"
+
+	"github.com/nucleus/store-core/pkg/entity"
+)
+
+// ===================================================
+// EPP Catalog
+// Policies and processes classified from documents, kept as canonical
+// entities
+// ===================================================
+
+// EPPDocument is a document to classify.
+type EPPDocument struct {
+	Source  string `json:"source"` // confluence, onedrive
+	ID      string `json:"id"`     // Document ID in the source (or its Brain NodeID)
+	NodeID  string `json:"nodeId"` // Brain NodeID of the document
+	URL     string `json:"url"`    // Link to the document
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// EPPCatalogResult is the outcome of classifying one document.
+type EPPCatalogResult struct {
+	Classification *EPPClassification      `json:"classification"`
+	Entity         *entity.CanonicalEntity `json:"entity,omitempty"`    // The stored policy or process
+	RemovedID      string                  `json:"removedId,omitempty"` // Entity dropped because the document changed type
+}
+
+// minEPPConfidence is the classifier confidence below which a document is
+// not catalogued as a policy or process.
+const minEPPConfidence = 0.5
+
+// EPPCatalog classifies documents and keeps the policies and processes they
+// define in the entity registry, one canonical entity per document. A
+// document that is reclassified replaces its entity; one that no longer
+// reads as a policy or process has it removed.
+type EPPCatalog struct {
+	classifier *EPPClassifier
+	registry   entity.EntityRegistry
+}
+
+// NewEPPCatalog creates a catalog.
+func NewEPPCatalog(classifier *EPPClassifier, registry entity.EntityRegistry) *EPPCatalog {
+	return &EPPCatalog{classifier: classifier, registry: registry}
+}
+
+// Classify classifies the document and stores the result.
+func (c *EPPCatalog) Classify(ctx context.Context, tenantID string, doc EPPDocument) (*EPPCatalogResult, error) {
+	resp, err := c.classifier.Classify(ctx, ClassifyRequest{
+		TenantID:   tenantID,
+		Title:      doc.Title,
+		Content:    doc.Content,
+		SourceID:   doc.ID,
+		SourceType: doc.Source,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := &EPPCatalogResult{Classification: resp.Classification}
+
+	existing, err := c.registry.GetBySourceRef(ctx, tenantID, doc.Source, doc.ID)
+	if err != nil {
+		existing = nil // Not found
+	}
+
+	next := EPPEntity(tenantID, doc, resp.Classification)
+	if existing != nil && (next == nil || existing.ID != next.ID) {
+		if err := c.registry.Delete(ctx, tenantID, existing.ID); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", existing.ID, err)
+		}
+		result.RemovedID = existing.ID
+		existing = nil
+	}
+	if next == nil {
+		return result, nil
+	}
+
+	if existing != nil {
+		next.CreatedAt = existing.CreatedAt
+		next.Aliases = existing.Aliases
+		err = c.registry.Update(ctx, next)
+	} else {
+		err = c.registry.Create(ctx, next)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store %s: %w", next.ID, err)
+	}
+	result.Entity = next
+	return result, nil
+}
+
+// EPPEntity converts a policy or process classification into a canonical
+// entity, or returns nil for anything else. The ID is derived from the
+// document, so reclassifying it updates the same entity.
+func EPPEntity(tenantID string, doc EPPDocument, c *EPPClassification) *entity.CanonicalEntity {
+	if c == nil || c.Confidence < minEPPConfidence {
+		return nil
+	}
+	name := c.Title
+	if name == "" {
+		name = doc.Title
+	}
+	now := time.Now()
+	base := entity.CanonicalEntity{
+		TenantID: tenantID,
+		Name:     name,
+		Properties: map[string]any{
+			"description": c.Description,
+			"confidence":  c.Confidence,
+		},
+		SourceRefs: []entity.SourceRef{{
+			Source:     doc.Source,
+			ExternalID: doc.ID,
+			NodeID:     doc.NodeID,
+			URL:        doc.URL,
+			LastSynced: now,
+		}},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	switch {
+	case c.Type == EPPTypePolicy && c.Policy != nil:
+		base.ID = eppEntityID(entity.EntityTypePolicy, doc)
+		p := &entity.PolicyEntity{
+			CanonicalEntity: base,
+			AppliesTo:       c.Policy.AppliesTo,
+			Enforcement:     c.Policy.Enforcement,
+			EffectiveDate:   c.Policy.EffectiveDate,
+			Owners:          c.Policy.Owners,
+			Keywords:        c.Policy.Keywords,
+		}
+		for _, r := range c.Policy.Rules {
+			p.Rules = append(p.Rules, entity.PolicyRule{
+				ID:          r.ID,
+				Description: r.Statement,
+				Requirement: r.Requirement,
+				Category:    r.Category,
+				Exceptions:  r.Exceptions,
+			})
+		}
+		return p.Canonical()
+	case c.Type == EPPTypeProcess && c.Process != nil:
+		base.ID = eppEntityID(entity.EntityTypeProcess, doc)
+		p := &entity.ProcessEntity{
+			CanonicalEntity: base,
+			Triggers:        c.Process.Triggers,
+			Outcomes:        c.Process.Outcomes,
+			Roles:           c.Process.Roles,
+			SLA:             c.Process.SLA,
+			Owner:           c.Process.Owner,
+		}
+		for _, s := range c.Process.Steps {
+			p.Steps = append(p.Steps, entity.ProcessStep{
+				ID:          s.ID,
+				Order:       s.Order,
+				Name:        s.Name,
+				Description: s.Description,
+				Actor:       s.Actor,
+				Inputs:      s.Inputs,
+				Outputs:     s.Outputs,
+				Conditions:  s.Conditions,
+				NextSteps:   s.NextSteps,
+			})
+		}
+		return p.Canonical()
+	default:
+		return nil
+	}
+}
+
+func eppEntityID(t entity.EntityType, doc EPPDocument) string {
+	sum := sha1.Sum([]byte(doc.Source + "|" + doc.ID))
+	return fmt.Sprintf("entity:%s:%x", t, sum[:4])
+}
@@ -0,0 +1,122 @@
+package ner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nucleus/store-core/pkg/entity"
+)
+
+// scriptedLLM answers by system prompt: type classification, then policy or
+// process extraction.
+type scriptedLLM struct {
+	answers map[string]string
+}
+
+func (s *scriptedLLM) Name() string { return "scripted" }
+
+func (s *scriptedLLM) Complete(_ context.Context, _ string, options CompletionOptions) (string, error) {
+	return s.answers[options.SystemPrompt], nil
+}
+
+// mapRegistry is the subset of an EntityRegistry the catalog uses.
+type mapRegistry struct {
+	entity.EntityRegistry
+	entities map[string]*entity.CanonicalEntity
+}
+
+func (r *mapRegistry) Create(_ context.Context, e *entity.CanonicalEntity) error {
+	if _, ok := r.entities[e.ID]; ok {
+		return fmt.Errorf("entity exists: %s", e.ID)
+	}
+	r.entities[e.ID] = e
+	return nil
+}
+
+func (r *mapRegistry) Update(_ context.Context, e *entity.CanonicalEntity) error {
+	r.entities[e.ID] = e
+	return nil
+}
+
+func (r *mapRegistry) Delete(_ context.Context, _, id string) error {
+	delete(r.entities, id)
+	return nil
+}
+
+func (r *mapRegistry) GetBySourceRef(_ context.Context, _, source, externalID string) (*entity.CanonicalEntity, error) {
+	for _, e := range r.entities {
+		for _, ref := range e.SourceRefs {
+			if ref.Source == source && ref.ExternalID == externalID {
+				return e, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("entity not found")
+}
+
+func (r *mapRegistry) List(_ context.Context, _ string, filter entity.EntityFilter, _, _ int) ([]*entity.CanonicalEntity, error) {
+	var out []*entity.CanonicalEntity
+	for _, e := range r.entities {
+		if len(filter.Types) == 0 || filter.Types[0] == e.Type {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func TestEPPCatalogStoresAndReclassifies(t *testing.T) {
+	ctx := context.Background()
+	llm := &scriptedLLM{answers: map[string]string{
+		eppSystemPrompt: `{"type": "policy", "confidence": 0.9}`,
+		policyExtractionPrompt: "```json\n" + `{"rules": [{"statement": "Laptops must use disk encryption", "requirement": "must", "category": "security"}],
+			"appliesTo": ["laptops"], "enforcement": "mandatory", "owners": ["security@acme.com"]}` + "\n```",
+		processExtractionPrompt: `{"steps": [{"name": "Request access"}, {"name": "Manager approves", "actor": "manager"}], "triggers": ["new hire"]}`,
+	}}
+	reg := &mapRegistry{entities: map[string]*entity.CanonicalEntity{}}
+	catalog := NewEPPCatalog(NewEPPClassifier(llm, ""), reg)
+	doc := EPPDocument{Source: "confluence", ID: "page-7", NodeID: "doc:confluence:SEC:page:7", Title: "Device Policy", Content: "..."}
+
+	res, err := catalog.Classify(ctx, "t1", doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Entity == nil || res.Entity.Type != "policy" || res.Entity.Name != "Device Policy" {
+		t.Fatalf("expected a stored policy, got %+v", res)
+	}
+
+	policies, err := entity.NewService(reg, nil).ListPolicies(ctx, &entity.ListPoliciesRequest{TenantID: "t1", Enforcement: "Mandatory"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies.Policies) != 1 {
+		t.Fatalf("expected one policy in the catalogue, got %+v", policies.Policies)
+	}
+	p := policies.Policies[0]
+	if len(p.Rules) != 1 || p.Rules[0].ID != "R1" || p.Rules[0].Description != "Laptops must use disk encryption" || p.AppliesTo[0] != "laptops" {
+		t.Errorf("policy structure lost: %+v", p)
+	}
+
+	// The page is rewritten as a runbook: the policy makes way for a process
+	llm.answers[eppSystemPrompt] = `{"type": "process", "confidence": 0.8}`
+	res, err = catalog.Classify(ctx, "t1", doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RemovedID != p.ID || res.Entity == nil || res.Entity.Type != "process" || len(reg.entities) != 1 {
+		t.Fatalf("expected the policy replaced by a process, got %+v", res)
+	}
+	proc, err := entity.ProcessFromCanonical(res.Entity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proc.Steps) != 2 || proc.Steps[1].ID != "S2" || proc.Steps[1].Order != 2 || proc.Steps[1].Actor != "manager" {
+		t.Errorf("process steps lost: %+v", proc.Steps)
+	}
+
+	// Unsure classifications are not catalogued
+	llm.answers[eppSystemPrompt] = `{"type": "process", "confidence": 0.3}`
+	if res, err = catalog.Classify(ctx, "t1", doc); err != nil || res.Entity != nil || len(reg.entities) != 0 {
+		t.Fatalf("expected low-confidence document to be dropped, got %+v, %v", res, err)
+	}
+}
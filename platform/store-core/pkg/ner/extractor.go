@@ -305,6 +305,4 @@ Guidelines:
 `
 
 // Ensure interface compliance
-var _ interface {
-	Extract(ctx context.Context, req NERRequest) (*NERResponse, error)
-} = (*NERExtractor)(nil)
+var _ Extractor = (*NERExtractor)(nil)
@@ -0,0 +1,206 @@
+package ner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nucleus/store-core/pkg/entity"
+)
+
+// ===================================================
+// Gazetteer
+// Dictionary matching of known names (Aho-Corasick)
+// ===================================================
+
+// GazetteerEntry is a known name and the entity it refers to.
+type GazetteerEntry struct {
+	Term        string     `json:"term"`        // Name or alias as it appears in text
+	Type        EntityType `json:"type"`        // Entity type
+	Normalized  string     `json:"normalized"`  // Canonical display name
+	CanonicalID string     `json:"canonicalId"` // Registry ID, if loaded from the registry
+}
+
+// gazetteerMatch is a dictionary hit at [start, end) of the text.
+type gazetteerMatch struct {
+	start, end int
+	entry      *GazetteerEntry
+}
+
+// Gazetteer finds every known term in a text in one pass. Matching is ASCII
+// case-insensitive and only accepts hits on word boundaries. A Gazetteer is
+// immutable once built and safe for concurrent use.
+type Gazetteer struct {
+	nodes   []acNode
+	entries []GazetteerEntry
+}
+
+type acNode struct {
+	next   map[byte]int
+	fail   int
+	output []int // entry indices ending here, including via fail links
+}
+
+// minGazetteerTerm skips terms too short to match without noise.
+const minGazetteerTerm = 3
+
+// NewGazetteer builds a gazetteer. Terms shorter than three characters are
+// skipped; when two entries share a term the first wins.
+func NewGazetteer(entries []GazetteerEntry) *Gazetteer {
+	g := &Gazetteer{nodes: []acNode{{next: map[byte]int{}}}}
+	seen := map[string]bool{}
+	for _, e := range entries {
+		term := lowerASCII(e.Term)
+		if len(term) < minGazetteerTerm || seen[term] {
+			continue
+		}
+		seen[term] = true
+		g.entries = append(g.entries, e)
+		g.insert(term, len(g.entries)-1)
+	}
+	g.link()
+	return g
+}
+
+func (g *Gazetteer) insert(term string, idx int) {
+	cur := 0
+	for i := 0; i < len(term); i++ {
+		nxt, ok := g.nodes[cur].next[term[i]]
+		if !ok {
+			g.nodes = append(g.nodes, acNode{next: map[byte]int{}})
+			nxt = len(g.nodes) - 1
+			g.nodes[cur].next[term[i]] = nxt
+		}
+		cur = nxt
+	}
+	g.nodes[cur].output = append(g.nodes[cur].output, idx)
+}
+
+// link computes failure links breadth-first and folds outputs along them.
+func (g *Gazetteer) link() {
+	queue := make([]int, 0, len(g.nodes))
+	for _, child := range g.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for c, child := range g.nodes[cur].next {
+			f := g.nodes[cur].fail
+			for f != 0 {
+				if _, ok := g.nodes[f].next[c]; ok {
+					break
+				}
+				f = g.nodes[f].fail
+			}
+			if nxt, ok := g.nodes[f].next[c]; ok && nxt != child {
+				g.nodes[child].fail = nxt
+			}
+			g.nodes[child].output = append(g.nodes[child].output, g.nodes[g.nodes[child].fail].output...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Len returns the number of terms.
+func (g *Gazetteer) Len() int {
+	if g == nil {
+		return 0
+	}
+	return len(g.entries)
+}
+
+// find returns every whole-word occurrence of a known term.
+func (g *Gazetteer) find(text string) []gazetteerMatch {
+	if g.Len() == 0 {
+		return nil
+	}
+	var out []gazetteerMatch
+	cur := 0
+	for i := 0; i < len(text); i++ {
+		c := toLowerASCII(text[i])
+		for cur != 0 {
+			if _, ok := g.nodes[cur].next[c]; ok {
+				break
+			}
+			cur = g.nodes[cur].fail
+		}
+		if nxt, ok := g.nodes[cur].next[c]; ok {
+			cur = nxt
+		}
+		for _, idx := range g.nodes[cur].output {
+			e := &g.entries[idx]
+			start, end := i+1-len(e.Term), i+1
+			if isWordBoundary(text, start-1) && isWordBoundary(text, end) {
+				out = append(out, gazetteerMatch{start: start, end: end, entry: e})
+			}
+		}
+	}
+	return out
+}
+
+// LoadGazetteer builds a gazetteer from a tenant's canonical entities, with
+// each entity's name and aliases as terms. types limits the entity types
+// loaded (e.g. person, project, repo); empty loads all.
+func LoadGazetteer(ctx context.Context, registry entity.EntityRegistry, tenantID string, types []string) (*Gazetteer, error) {
+	const page = 500
+	var entries []GazetteerEntry
+	for offset := 0; ; offset += page {
+		entities, err := registry.List(ctx, tenantID, entity.EntityFilter{Types: types}, page, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list entities: %w", err)
+		}
+		for _, e := range entities {
+			typ := registryEntityType(e.Type)
+			for _, term := range append([]string{e.Name}, e.Aliases...) {
+				entries = append(entries, GazetteerEntry{
+					Term:        term,
+					Type:        typ,
+					Normalized:  e.Name,
+					CanonicalID: e.ID,
+				})
+			}
+		}
+		if len(entities) < page {
+			break
+		}
+	}
+	return NewGazetteer(entries), nil
+}
+
+// registryEntityType maps registry types onto NER types; repositories are
+// projects.
+func registryEntityType(t string) EntityType {
+	if t == "repo" || t == "repository" {
+		return EntityTypeProject
+	}
+	if typ := EntityType(t); isValidEntityType(typ) {
+		return typ
+	}
+	return EntityTypeOther
+}
+
+func toLowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+// lowerASCII lowercases ASCII letters only, so byte offsets are preserved.
+func lowerASCII(s string) string {
+	b := []byte(s)
+	for i := range b {
+		b[i] = toLowerASCII(b[i])
+	}
+	return string(b)
+}
+
+// isWordBoundary reports whether position i (just outside a span) is not a
+// letter, digit or underscore.
+func isWordBoundary(text string, i int) bool {
+	if i < 0 || i >= len(text) {
+		return true
+	}
+	c := text[i]
+	return !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c >= 0x80)
+}
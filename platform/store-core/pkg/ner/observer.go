@@ -3,9 +3,9 @@ package ner
 import (
 	"context"
 	"fmt"
-	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/nucleus/store-core/pkg/entity"
 )
 
@@ -16,17 +16,21 @@ import (
 
 // ObservedEntity represents an entity observed from a source.
 type ObservedEntity struct {
-	ID           string            `json:"id"`           // Observation ID
-	TenantID     string            `json:"tenantId"`     // Tenant
-	SourceID     string            `json:"sourceId"`     // Source document ID
-	SourceType   string            `json:"sourceType"`   // Source system
-	SourceURL    string            `json:"sourceUrl"`    // Link to source
-	Entity       ExtractedEntity   `json:"entity"`       // Extracted entity
-	ObservedAt   time.Time         `json:"observedAt"`   // When observed
-	Status       ObservationStatus `json:"status"`       // Processing status
-	CanonicalID  string            `json:"canonicalId"`  // Resolved canonical entity ID (if any)
-	MatchScore   float32           `json:"matchScore"`   // Match confidence
-	MatchedBy    string            `json:"matchedBy"`    // Match rule that fired
+	ID          string            `json:"id"`                    // Observation ID
+	TenantID    string            `json:"tenantId"`              // Tenant
+	SourceID    string            `json:"sourceId"`              // Source document ID
+	SourceType  string            `json:"sourceType"`            // Source system
+	SourceURL   string            `json:"sourceUrl"`             // Link to source
+	Entity      ExtractedEntity   `json:"entity"`                // Extracted entity
+	ObservedAt  time.Time         `json:"observedAt"`            // When observed
+	Status      ObservationStatus `json:"status"`                // Processing status
+	CanonicalID string            `json:"canonicalId"`           // Resolved canonical entity ID (if any)
+	MatchScore  float32           `json:"matchScore"`            // Match confidence
+	MatchedBy   string            `json:"matchedBy"`             // Match rule that fired
+	SuggestedID string            `json:"suggestedId,omitempty"` // Top candidate for review items
+	ReviewedBy  string            `json:"reviewedBy,omitempty"`  // Steward who approved or rejected
+	ReviewedAt  *time.Time        `json:"reviewedAt,omitempty"`  // When reviewed
+	ReviewNote  string            `json:"reviewNote,omitempty"`  // Steward's note
 }
 
 // ObservationStatus tracks the state of an observation.
@@ -42,23 +46,26 @@ const (
 )
 
 // EntityObserver observes and resolves entities across sources.
+// Observations live in an ObservationStore, so several workers can share
+// one review queue.
 type EntityObserver struct {
-	mu          sync.RWMutex
-	pending     map[string]*ObservedEntity // Pending observations by ID
-	bySource    map[string][]string        // tenantId:sourceType:sourceId -> observation IDs
-	byNormalized map[string][]string       // P0 Fix: tenantId:normalized:type -> observation IDs (tenant-scoped)
-	matcher     entity.EntityMatcher
-	threshold   float32 // Auto-merge threshold (default 0.9)
+	store     ObservationStore
+	matcher   entity.EntityMatcher
+	threshold float32 // Auto-merge threshold (default 0.9)
 }
 
-// NewEntityObserver creates a new entity observer.
+// NewEntityObserver creates a new entity observer backed by an in-memory
+// store.
 func NewEntityObserver(matcher entity.EntityMatcher) *EntityObserver {
+	return NewEntityObserverWithStore(matcher, NewMemoryObservationStore())
+}
+
+// NewEntityObserverWithStore creates an entity observer backed by store.
+func NewEntityObserverWithStore(matcher entity.EntityMatcher, store ObservationStore) *EntityObserver {
 	return &EntityObserver{
-		pending:      make(map[string]*ObservedEntity),
-		bySource:     make(map[string][]string),
-		byNormalized: make(map[string][]string),
-		matcher:      matcher,
-		threshold:    0.9,
+		store:     store,
+		matcher:   matcher,
+		threshold: 0.9,
 	}
 }
 
@@ -69,10 +76,6 @@ func (o *EntityObserver) SetAutoMergeThreshold(threshold float32) {
 
 // Observe records an entity observation.
 func (o *EntityObserver) Observe(ctx context.Context, tenantID string, extracted ExtractedEntity, sourceURL string) (*ObservedEntity, error) {
-	o.mu.Lock()
-	defer o.mu.Unlock()
-
-	// Create observation
 	obs := &ObservedEntity{
 		ID:         generateObservationID(),
 		TenantID:   tenantID,
@@ -84,30 +87,18 @@ func (o *EntityObserver) Observe(ctx context.Context, tenantID string, extracted
 		Status:     StatusPending,
 	}
 
-	// Store observation
-	o.pending[obs.ID] = obs
-
-	// P0 Fix: Include tenantID in all index keys for tenant isolation
-	// Index by source (tenant-scoped)
-	sourceKey := fmt.Sprintf("%s:%s:%s", tenantID, extracted.SourceType, extracted.SourceID)
-	o.bySource[sourceKey] = append(o.bySource[sourceKey], obs.ID)
-
-	// Index by normalized text + type (tenant-scoped)
-	normalizedKey := fmt.Sprintf("%s:%s:%s", tenantID, extracted.Normalized, extracted.Type)
-	o.byNormalized[normalizedKey] = append(o.byNormalized[normalizedKey], obs.ID)
-
+	if err := o.store.Save(ctx, obs); err != nil {
+		return nil, fmt.Errorf("failed to store observation: %w", err)
+	}
 	return obs, nil
 }
 
 // ResolveObservation attempts to resolve an observation to a canonical entity.
-func (o *EntityObserver) ResolveObservation(ctx context.Context, obsID string) (*ObservedEntity, error) {
-	o.mu.Lock()
-	obs, exists := o.pending[obsID]
-	if !exists {
-		o.mu.Unlock()
-		return nil, fmt.Errorf("observation not found: %s", obsID)
+func (o *EntityObserver) ResolveObservation(ctx context.Context, tenantID, obsID string) (*ObservedEntity, error) {
+	obs, err := o.store.Get(ctx, tenantID, obsID)
+	if err != nil {
+		return nil, err
 	}
-	o.mu.Unlock()
 
 	if obs.Status != StatusPending {
 		return obs, nil // Already resolved
@@ -129,9 +120,6 @@ func (o *EntityObserver) ResolveObservation(ctx context.Context, obsID string) (
 		return nil, fmt.Errorf("failed to find matches: %w", err)
 	}
 
-	o.mu.Lock()
-	defer o.mu.Unlock()
-
 	if len(matches) > 0 {
 		topMatch := matches[0]
 		obs.MatchScore = topMatch.Score
@@ -142,7 +130,8 @@ func (o *EntityObserver) ResolveObservation(ctx context.Context, obsID string) (
 			obs.CanonicalID = topMatch.CanonicalID
 			obs.Status = StatusMatched
 		} else {
-			// Below threshold - needs manual review, don't set canonical ID yet
+			// Below threshold - needs manual review; keep the candidate as a suggestion
+			obs.SuggestedID = topMatch.CanonicalID
 			obs.Status = StatusReview
 		}
 	} else {
@@ -157,148 +146,78 @@ func (o *EntityObserver) ResolveObservation(ctx context.Context, obsID string) (
 		obs.Status = StatusCreated
 	}
 
+	if err := o.store.Save(ctx, obs); err != nil {
+		return nil, fmt.Errorf("failed to store resolution: %w", err)
+	}
 	return obs, nil
 }
 
-// GetPendingObservations returns all pending observations.
-func (o *EntityObserver) GetPendingObservations(tenantID string) []*ObservedEntity {
-	o.mu.RLock()
-	defer o.mu.RUnlock()
-
-	var result []*ObservedEntity
-	for _, obs := range o.pending {
-		if obs.TenantID == tenantID && obs.Status == StatusPending {
-			result = append(result, obs)
-		}
-	}
-	return result
+// GetPendingObservations returns pending observations.
+func (o *EntityObserver) GetPendingObservations(ctx context.Context, tenantID string, limit, offset int) ([]*ObservedEntity, int, error) {
+	return o.store.List(ctx, ObservationQuery{
+		TenantID: tenantID,
+		Statuses: []ObservationStatus{StatusPending},
+		Limit:    limit,
+		Offset:   offset,
+	})
 }
 
-// GetReviewObservations returns observations needing review.
-func (o *EntityObserver) GetReviewObservations(tenantID string) []*ObservedEntity {
-	o.mu.RLock()
-	defer o.mu.RUnlock()
-
-	var result []*ObservedEntity
-	for _, obs := range o.pending {
-		if obs.TenantID == tenantID && obs.Status == StatusReview {
-			result = append(result, obs)
-		}
-	}
-	return result
+// GetReviewQueue returns a page of observations needing review, highest
+// match score first, and the queue length.
+func (o *EntityObserver) GetReviewQueue(ctx context.Context, q ObservationQuery) ([]*ObservedEntity, int, error) {
+	q.Statuses = []ObservationStatus{StatusReview}
+	return o.store.List(ctx, q)
 }
 
 // FindCrossSourceMatches finds observations of the same entity across sources.
 // P0 Fix: Added tenantID parameter to ensure tenant isolation.
-func (o *EntityObserver) FindCrossSourceMatches(tenantID string, normalized string, entityType EntityType) []*ObservedEntity {
-	o.mu.RLock()
-	defer o.mu.RUnlock()
-
-	// P0 Fix: Include tenantID in key for tenant isolation
-	key := fmt.Sprintf("%s:%s:%s", tenantID, normalized, entityType)
-	obsIDs := o.byNormalized[key]
-
-	var result []*ObservedEntity
-	for _, id := range obsIDs {
-		if obs, exists := o.pending[id]; exists {
-			// Double-check tenant (defense in depth)
-			if obs.TenantID == tenantID {
-				result = append(result, obs)
-			}
-		}
-	}
-	return result
+func (o *EntityObserver) FindCrossSourceMatches(ctx context.Context, tenantID string, normalized string, entityType EntityType) ([]*ObservedEntity, error) {
+	obs, _, err := o.store.List(ctx, ObservationQuery{
+		TenantID:   tenantID,
+		Normalized: normalized,
+		EntityType: entityType,
+		Limit:      maxQueueLimit,
+	})
+	return obs, err
 }
 
-// GetObservationsBySource returns all observations from a source.
+// GetObservationsBySource returns observations from a source.
 // P0 Fix: Added tenantID parameter to ensure tenant isolation.
-func (o *EntityObserver) GetObservationsBySource(tenantID, sourceType, sourceID string) []*ObservedEntity {
-	o.mu.RLock()
-	defer o.mu.RUnlock()
-
-	// P0 Fix: Include tenantID in key for tenant isolation
-	key := fmt.Sprintf("%s:%s:%s", tenantID, sourceType, sourceID)
-	obsIDs := o.bySource[key]
-
-	var result []*ObservedEntity
-	for _, id := range obsIDs {
-		if obs, exists := o.pending[id]; exists {
-			// Double-check tenant (defense in depth)
-			if obs.TenantID == tenantID {
-				result = append(result, obs)
-			}
-		}
-	}
-	return result
+func (o *EntityObserver) GetObservationsBySource(ctx context.Context, tenantID, sourceType, sourceID string) ([]*ObservedEntity, error) {
+	obs, _, err := o.store.List(ctx, ObservationQuery{
+		TenantID:   tenantID,
+		SourceType: sourceType,
+		SourceID:   sourceID,
+		Limit:      maxQueueLimit,
+	})
+	return obs, err
 }
 
-// ApproveMatch approves a match for an observation.
-// P1 Fix: Added tenantID parameter to prevent cross-tenant tampering.
-func (o *EntityObserver) ApproveMatch(tenantID, obsID, canonicalID string) error {
-	o.mu.Lock()
-	defer o.mu.Unlock()
-
-	obs, exists := o.pending[obsID]
-	if !exists {
-		return fmt.Errorf("observation not found: %s", obsID)
-	}
-
-	// P1 Fix: Validate tenant to prevent cross-tenant access
-	if obs.TenantID != tenantID {
-		return fmt.Errorf("observation not found: %s", obsID) // Don't leak existence
-	}
-
-	obs.CanonicalID = canonicalID
-	obs.Status = StatusMatched
-	return nil
+// ApproveMatches approves observations as matches. With an empty
+// canonicalID each observation takes its suggested match.
+// P1 Fix: Tenant-scoped to prevent cross-tenant tampering.
+func (o *EntityObserver) ApproveMatches(ctx context.Context, tenantID string, obsIDs []string, canonicalID, reviewer, note string) (*ReviewResult, error) {
+	return o.store.Review(ctx, tenantID, obsIDs, ReviewDecision{
+		Status:      StatusMatched,
+		CanonicalID: canonicalID,
+		Reviewer:    reviewer,
+		Note:        note,
+	})
 }
 
-// RejectObservation marks an observation as rejected.
-// P1 Fix: Added tenantID parameter to prevent cross-tenant tampering.
-func (o *EntityObserver) RejectObservation(tenantID, obsID string) error {
-	o.mu.Lock()
-	defer o.mu.Unlock()
-
-	obs, exists := o.pending[obsID]
-	if !exists {
-		return fmt.Errorf("observation not found: %s", obsID)
-	}
-
-	// P1 Fix: Validate tenant to prevent cross-tenant access
-	if obs.TenantID != tenantID {
-		return fmt.Errorf("observation not found: %s", obsID) // Don't leak existence
-	}
-
-	obs.Status = StatusRejected
-
-	return nil
+// RejectObservations marks observations as rejected.
+// P1 Fix: Tenant-scoped to prevent cross-tenant tampering.
+func (o *EntityObserver) RejectObservations(ctx context.Context, tenantID string, obsIDs []string, reviewer, note string) (*ReviewResult, error) {
+	return o.store.Review(ctx, tenantID, obsIDs, ReviewDecision{
+		Status:   StatusRejected,
+		Reviewer: reviewer,
+		Note:     note,
+	})
 }
 
 // Stats returns observation statistics.
-func (o *EntityObserver) Stats(tenantID string) ObserverStats {
-	o.mu.RLock()
-	defer o.mu.RUnlock()
-
-	stats := ObserverStats{}
-	for _, obs := range o.pending {
-		if obs.TenantID != tenantID {
-			continue
-		}
-		stats.Total++
-		switch obs.Status {
-		case StatusPending:
-			stats.Pending++
-		case StatusMatched:
-			stats.Matched++
-		case StatusCreated:
-			stats.Created++
-		case StatusReview:
-			stats.NeedsReview++
-		case StatusRejected:
-			stats.Rejected++
-		}
-	}
-	return stats
+func (o *EntityObserver) Stats(ctx context.Context, tenantID string) (ObserverStats, error) {
+	return o.store.Stats(ctx, tenantID)
 }
 
 // ObserverStats holds observation statistics.
@@ -311,9 +230,25 @@ type ObserverStats struct {
 	Rejected    int `json:"rejected"`
 }
 
+func (s *ObserverStats) add(status ObservationStatus, n int) {
+	s.Total += n
+	switch status {
+	case StatusPending:
+		s.Pending += n
+	case StatusMatched:
+		s.Matched += n
+	case StatusCreated:
+		s.Created += n
+	case StatusReview:
+		s.NeedsReview += n
+	case StatusRejected:
+		s.Rejected += n
+	}
+}
+
 // generateObservationID creates a unique observation ID.
 func generateObservationID() string {
-	return fmt.Sprintf("obs-%d", time.Now().UnixNano())
+	return "obs-" + uuid.New().String()
 }
 
 // ===================================================
@@ -335,10 +270,10 @@ type CrossSourceEntityView struct {
 
 // BuildCrossSourceView builds a view of an entity across all sources.
 // P0 Fix: Added tenantID parameter to ensure tenant isolation.
-func (o *EntityObserver) BuildCrossSourceView(tenantID string, normalized string, entityType EntityType) *CrossSourceEntityView {
-	observations := o.FindCrossSourceMatches(tenantID, normalized, entityType)
-	if len(observations) == 0 {
-		return nil
+func (o *EntityObserver) BuildCrossSourceView(ctx context.Context, tenantID string, normalized string, entityType EntityType) (*CrossSourceEntityView, error) {
+	observations, err := o.FindCrossSourceMatches(ctx, tenantID, normalized, entityType)
+	if err != nil || len(observations) == 0 {
+		return nil, err
 	}
 
 	view := &CrossSourceEntityView{
@@ -374,5 +309,5 @@ func (o *EntityObserver) BuildCrossSourceView(tenantID string, normalized string
 
 	view.Confidence = totalConfidence / float32(len(observations))
 
-	return view
+	return view, nil
 }
@@ -0,0 +1,81 @@
+package ner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nucleus/store-core/pkg/entity"
+)
+
+// scoredMatcher returns one candidate per name with a fixed score.
+type scoredMatcher struct {
+	scores map[string]float32
+}
+
+func (m scoredMatcher) FindMatches(_ context.Context, _ string, source entity.SourceEntity) ([]entity.MatchResult, error) {
+	score, ok := m.scores[source.Name]
+	if !ok {
+		return nil, nil
+	}
+	return []entity.MatchResult{{CanonicalID: "canon-" + source.Name, Score: score, MatchedBy: "test"}}, nil
+}
+
+func (m scoredMatcher) ResolveOrCreate(_ context.Context, tenantID string, source entity.SourceEntity) (*entity.CanonicalEntity, bool, error) {
+	return &entity.CanonicalEntity{ID: "new-" + source.Name, TenantID: tenantID}, true, nil
+}
+
+func TestReviewQueueOrderingAndBulkReview(t *testing.T) {
+	ctx := context.Background()
+	obsv := NewEntityObserverWithStore(scoredMatcher{scores: map[string]float32{
+		"alice": 0.95, "bob": 0.6, "carol": 0.8, "dave": 0.7,
+	}}, NewMemoryObservationStore())
+
+	ids := map[string]string{}
+	for _, name := range []string{"alice", "bob", "carol", "dave", "erin"} {
+		obs, err := obsv.Observe(ctx, "t1", ExtractedEntity{Normalized: name, Type: EntityTypePerson, SourceType: "jira"}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := obsv.ResolveObservation(ctx, "t1", obs.ID); err != nil {
+			t.Fatal(err)
+		}
+		ids[name] = obs.ID
+	}
+
+	page, total, err := obsv.GetReviewQueue(ctx, ObservationQuery{TenantID: "t1", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || len(page) != 2 || page[0].Entity.Normalized != "carol" || page[1].Entity.Normalized != "dave" {
+		t.Fatalf("unexpected first page (total %d): %+v", total, page)
+	}
+	if page[0].CanonicalID != "" || page[0].SuggestedID != "canon-carol" {
+		t.Errorf("review items keep the candidate as a suggestion only: %+v", page[0])
+	}
+	if other, _, _ := obsv.GetReviewQueue(ctx, ObservationQuery{TenantID: "t2"}); len(other) != 0 {
+		t.Errorf("queue leaked across tenants: %+v", other)
+	}
+
+	res, err := obsv.ApproveMatches(ctx, "t1", []string{ids["carol"], ids["dave"], ids["alice"], "missing"}, "", "steward@acme", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Updated) != 2 || len(res.Errors) != 2 {
+		t.Fatalf("expected 2 approved and 2 failures, got %+v", res)
+	}
+	if res.Updated[0].CanonicalID != "canon-carol" || res.Updated[0].ReviewedBy != "steward@acme" || res.Updated[0].ReviewedAt == nil {
+		t.Errorf("approval not recorded: %+v", res.Updated[0])
+	}
+
+	if _, err := obsv.RejectObservations(ctx, "t1", []string{ids["bob"]}, "steward@acme", "not a person"); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := obsv.Stats(ctx, "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ObserverStats{Total: 5, Matched: 3, Created: 1, Rejected: 1}
+	if stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+}
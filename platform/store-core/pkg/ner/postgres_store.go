@@ -0,0 +1,259 @@
+package ner
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ===================================================
+// PostgreSQL Observation Store
+// ===================================================
+
+// PostgresObservationStore persists observations in entity_observations so
+// the review queue survives restarts and is shared between workers.
+type PostgresObservationStore struct {
+	db *sql.DB
+}
+
+// NewPostgresObservationStore creates a store and ensures its schema.
+func NewPostgresObservationStore(db *sql.DB) (*PostgresObservationStore, error) {
+	store := &PostgresObservationStore{db: db}
+	if err := store.ensureSchema(); err != nil {
+		return nil, fmt.Errorf("failed to ensure schema: %w", err)
+	}
+	return store, nil
+}
+
+func (s *PostgresObservationStore) ensureSchema() error {
+	_, err := s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS entity_observations (
+		id TEXT PRIMARY KEY,
+		tenant_id TEXT NOT NULL,
+		source_id TEXT NOT NULL DEFAULT '',
+		source_type TEXT NOT NULL DEFAULT '',
+		source_url TEXT NOT NULL DEFAULT '',
+		entity_type TEXT NOT NULL DEFAULT '',
+		normalized TEXT NOT NULL DEFAULT '',
+		entity JSONB NOT NULL DEFAULT '{}',
+		status TEXT NOT NULL,
+		canonical_id TEXT NOT NULL DEFAULT '',
+		match_score REAL NOT NULL DEFAULT 0,
+		matched_by TEXT NOT NULL DEFAULT '',
+		suggested_id TEXT NOT NULL DEFAULT '',
+		reviewed_by TEXT NOT NULL DEFAULT '',
+		reviewed_at TIMESTAMPTZ,
+		review_note TEXT NOT NULL DEFAULT '',
+		observed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+
+	-- Review queue: status filter, highest score first
+	CREATE INDEX IF NOT EXISTS idx_observations_queue ON entity_observations(tenant_id, status, match_score DESC, observed_at);
+	CREATE INDEX IF NOT EXISTS idx_observations_source ON entity_observations(tenant_id, source_type, source_id);
+	CREATE INDEX IF NOT EXISTS idx_observations_normalized ON entity_observations(tenant_id, normalized, entity_type);
+	`)
+	return err
+}
+
+const observationColumns = `SELECT id, tenant_id, source_id, source_type, source_url, entity, status,
+	canonical_id, match_score, matched_by, suggested_id, reviewed_by, reviewed_at, review_note, observed_at
+	FROM entity_observations`
+
+func scanObservation(row interface{ Scan(...any) error }) (*ObservedEntity, error) {
+	obs := &ObservedEntity{}
+	var entityJSON []byte
+	var status string
+	var reviewedAt sql.NullTime
+	err := row.Scan(&obs.ID, &obs.TenantID, &obs.SourceID, &obs.SourceType, &obs.SourceURL,
+		&entityJSON, &status, &obs.CanonicalID, &obs.MatchScore, &obs.MatchedBy,
+		&obs.SuggestedID, &obs.ReviewedBy, &reviewedAt, &obs.ReviewNote, &obs.ObservedAt)
+	if err != nil {
+		return nil, err
+	}
+	obs.Status = ObservationStatus(status)
+	if reviewedAt.Valid {
+		obs.ReviewedAt = &reviewedAt.Time
+	}
+	if err := json.Unmarshal(entityJSON, &obs.Entity); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal entity: %w", err)
+	}
+	return obs, nil
+}
+
+// Save inserts or replaces an observation.
+func (s *PostgresObservationStore) Save(ctx context.Context, obs *ObservedEntity) error {
+	entityJSON, err := json.Marshal(obs.Entity)
+	if err != nil {
+		return fmt.Errorf("failed to marshal entity: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO entity_observations
+		(id, tenant_id, source_id, source_type, source_url, entity_type, normalized, entity,
+		 status, canonical_id, match_score, matched_by, suggested_id, reviewed_by, reviewed_at,
+		 review_note, observed_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, NOW())
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			canonical_id = EXCLUDED.canonical_id,
+			match_score = EXCLUDED.match_score,
+			matched_by = EXCLUDED.matched_by,
+			suggested_id = EXCLUDED.suggested_id,
+			reviewed_by = EXCLUDED.reviewed_by,
+			reviewed_at = EXCLUDED.reviewed_at,
+			review_note = EXCLUDED.review_note,
+			updated_at = NOW()
+		WHERE entity_observations.tenant_id = EXCLUDED.tenant_id
+	`, obs.ID, obs.TenantID, obs.SourceID, obs.SourceType, obs.SourceURL,
+		string(obs.Entity.Type), obs.Entity.Normalized, entityJSON,
+		string(obs.Status), obs.CanonicalID, obs.MatchScore, obs.MatchedBy,
+		obs.SuggestedID, obs.ReviewedBy, obs.ReviewedAt, obs.ReviewNote, obs.ObservedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save observation: %w", err)
+	}
+	return nil
+}
+
+// Get returns an observation, or ErrObservationNotFound.
+func (s *PostgresObservationStore) Get(ctx context.Context, tenantID, id string) (*ObservedEntity, error) {
+	obs, err := scanObservation(s.db.QueryRowContext(ctx,
+		observationColumns+` WHERE id = $1 AND tenant_id = $2`, id, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrObservationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get observation: %w", err)
+	}
+	return obs, nil
+}
+
+// List returns matching observations, highest score first.
+func (s *PostgresObservationStore) List(ctx context.Context, q ObservationQuery) ([]*ObservedEntity, int, error) {
+	conds := []string{"tenant_id = $1"}
+	args := []any{q.TenantID}
+	argNum := 2
+	add := func(cond string, val any) {
+		conds = append(conds, fmt.Sprintf(cond, argNum))
+		args = append(args, val)
+		argNum++
+	}
+	if len(q.Statuses) > 0 {
+		statuses := make([]string, len(q.Statuses))
+		for i, st := range q.Statuses {
+			statuses[i] = string(st)
+		}
+		add("status = ANY($%d)", pq.Array(statuses))
+	}
+	if q.EntityType != "" {
+		add("entity_type = $%d", string(q.EntityType))
+	}
+	if q.SourceType != "" {
+		add("source_type = $%d", q.SourceType)
+	}
+	if q.SourceID != "" {
+		add("source_id = $%d", q.SourceID)
+	}
+	if q.Normalized != "" {
+		add("normalized = $%d", q.Normalized)
+	}
+	where := strings.Join(conds, " AND ")
+
+	var total int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM entity_observations WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count observations: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`%s WHERE %s
+		ORDER BY match_score DESC, observed_at ASC, id ASC
+		LIMIT %d OFFSET %d`, observationColumns, where, clampQueueLimit(q.Limit), max(q.Offset, 0)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list observations: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*ObservedEntity
+	for rows.Next() {
+		obs, err := scanObservation(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan observation: %w", err)
+		}
+		out = append(out, obs)
+	}
+	return out, total, rows.Err()
+}
+
+// Review applies d to each observation in one transaction, locking rows so
+// concurrent stewards don't overwrite each other's decisions.
+func (s *PostgresObservationStore) Review(ctx context.Context, tenantID string, ids []string, d ReviewDecision) (*ReviewResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &ReviewResult{Errors: map[string]string{}}
+	now := time.Now()
+	for _, id := range ids {
+		obs, err := scanObservation(tx.QueryRowContext(ctx,
+			observationColumns+` WHERE id = $1 AND tenant_id = $2 FOR UPDATE`, id, tenantID))
+		if errors.Is(err, sql.ErrNoRows) {
+			result.Errors[id] = ErrObservationNotFound.Error()
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get observation: %w", err)
+		}
+		if err := applyReview(obs, d, now); err != nil {
+			result.Errors[id] = err.Error()
+			continue
+		}
+		_, err = tx.ExecContext(ctx, `
+			UPDATE entity_observations
+			SET status = $1, canonical_id = $2, reviewed_by = $3, reviewed_at = $4,
+			    review_note = $5, updated_at = NOW()
+			WHERE id = $6 AND tenant_id = $7
+		`, string(obs.Status), obs.CanonicalID, obs.ReviewedBy, obs.ReviewedAt,
+			obs.ReviewNote, id, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to review observation: %w", err)
+		}
+		result.Updated = append(result.Updated, obs)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit review: %w", err)
+	}
+	return result, nil
+}
+
+// Stats counts a tenant's observations by status.
+func (s *PostgresObservationStore) Stats(ctx context.Context, tenantID string) (ObserverStats, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT status, COUNT(*) FROM entity_observations WHERE tenant_id = $1 GROUP BY status
+	`, tenantID)
+	if err != nil {
+		return ObserverStats{}, fmt.Errorf("failed to count observations: %w", err)
+	}
+	defer rows.Close()
+
+	stats := ObserverStats{}
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return ObserverStats{}, fmt.Errorf("failed to scan stats: %w", err)
+		}
+		stats.add(ObservationStatus(status), n)
+	}
+	return stats, rows.Err()
+}
+
+// Ensure interface compliance
+var _ ObservationStore = (*PostgresObservationStore)(nil)
@@ -0,0 +1,253 @@
+package ner
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ===================================================
+// Rule-Based NER Extractor
+// Deterministic extraction from regex packs and gazetteers; no LLM
+// ===================================================
+
+// Extractor extracts entities from text. NERExtractor (LLM), RuleExtractor
+// and CombinedExtractor implement it.
+type Extractor interface {
+	Extract(ctx context.Context, req NERRequest) (*NERResponse, error)
+}
+
+// Pattern is one regex rule. Build turns a match (submatches of
+// text[loc[0]:loc[1]]) into an entity; returning false drops it.
+type Pattern struct {
+	Name       string
+	Type       EntityType
+	Regex      *regexp.Regexp
+	Confidence float32
+	Build      func(text string, loc []int) (normalized string, qualifiers map[string]string, ok bool)
+}
+
+// Model names reported in NERResponse.ModelUsed.
+const ModelRules = "rules"
+
+var (
+	emailRe  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+	urlRe    = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+	githubRe = regexp.MustCompile(`\b([A-Za-z0-9][A-Za-z0-9\-]*)/([A-Za-z0-9_.\-]+)#([0-9]+)\b`)
+	jiraRe   = regexp.MustCompile(`\b([A-Z][A-Z0-9]{1,9})-([1-9][0-9]*)\b`)
+	semverRe = regexp.MustCompile(`\bv?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(?:-[0-9A-Za-z.\-]+)?(?:\+[0-9A-Za-z.\-]+)?\b`)
+)
+
+// jiraStoplist holds uppercase prefixes that look like Jira keys but are
+// standards or algorithms (UTF-8, SHA-256, ISO-27001).
+var jiraStoplist = map[string]bool{
+	"UTF": true, "SHA": true, "ISO": true, "RFC": true, "CVE": true, "TLS": true,
+	"SSL": true, "AES": true, "RSA": true, "HTTP": true, "IPV": true, "MD": true,
+	"COVID": true, "PEP": true, "ES": true, "GPT": true,
+}
+
+// DefaultPatterns returns the built-in regex pack: emails, URLs, GitHub
+// org/repo#123 references, Jira keys and semantic versions.
+func DefaultPatterns() []Pattern {
+	return []Pattern{
+		{
+			Name: "email", Type: EntityTypePerson, Regex: emailRe, Confidence: 0.95,
+			Build: func(text string, loc []int) (string, map[string]string, bool) {
+				email := strings.ToLower(text[loc[0]:loc[1]])
+				return email, map[string]string{"email": email}, true
+			},
+		},
+		{
+			Name: "url", Type: EntityTypeDocument, Regex: urlRe, Confidence: 0.9,
+			Build: func(text string, loc []int) (string, map[string]string, bool) {
+				return text[loc[0]:loc[1]], map[string]string{"kind": "url"}, true
+			},
+		},
+		{
+			Name: "github_ref", Type: EntityTypeDocument, Regex: githubRe, Confidence: 0.9,
+			Build: func(text string, loc []int) (string, map[string]string, bool) {
+				repo := text[loc[2]:loc[3]] + "/" + text[loc[4]:loc[5]]
+				return repo + "#" + text[loc[6]:loc[7]], map[string]string{
+					"kind": "github_issue", "repo": repo, "number": text[loc[6]:loc[7]],
+				}, true
+			},
+		},
+		{
+			Name: "jira_key", Type: EntityTypeDocument, Regex: jiraRe, Confidence: 0.9,
+			Build: func(text string, loc []int) (string, map[string]string, bool) {
+				project := text[loc[2]:loc[3]]
+				if jiraStoplist[project] {
+					return "", nil, false
+				}
+				return text[loc[0]:loc[1]], map[string]string{"kind": "jira_issue", "project": project}, true
+			},
+		},
+		{
+			Name: "semver", Type: EntityTypeOther, Regex: semverRe, Confidence: 0.85,
+			Build: func(text string, loc []int) (string, map[string]string, bool) {
+				// Reject IP addresses and longer dotted numbers
+				if loc[1] < len(text)-1 && text[loc[1]] == '.' && isDigit(text[loc[1]+1]) {
+					return "", nil, false
+				}
+				if loc[0] > 1 && text[loc[0]-1] == '.' && isDigit(text[loc[0]-2]) {
+					return "", nil, false
+				}
+				return strings.TrimPrefix(text[loc[0]:loc[1]], "v"), map[string]string{"kind": "version"}, true
+			},
+		},
+	}
+}
+
+// RuleExtractor extracts entities with regex patterns and a gazetteer of
+// known names. It needs no LLM and spends no tokens.
+type RuleExtractor struct {
+	patterns []Pattern
+
+	mu        sync.RWMutex
+	gazetteer *Gazetteer
+}
+
+// NewRuleExtractor creates a rule extractor with DefaultPatterns. gazetteer
+// may be nil.
+func NewRuleExtractor(gazetteer *Gazetteer) *RuleExtractor {
+	return NewRuleExtractorWithPatterns(DefaultPatterns(), gazetteer)
+}
+
+// NewRuleExtractorWithPatterns creates a rule extractor with custom patterns.
+func NewRuleExtractorWithPatterns(patterns []Pattern, gazetteer *Gazetteer) *RuleExtractor {
+	return &RuleExtractor{patterns: patterns, gazetteer: gazetteer}
+}
+
+// SetGazetteer swaps the gazetteer, e.g. after reloading it from the
+// registry.
+func (e *RuleExtractor) SetGazetteer(g *Gazetteer) {
+	e.mu.Lock()
+	e.gazetteer = g
+	e.mu.Unlock()
+}
+
+// Extract extracts entities from the given text.
+func (e *RuleExtractor) Extract(ctx context.Context, req NERRequest) (*NERResponse, error) {
+	start := time.Now()
+	text := req.Text
+
+	var spans []ExtractedEntity
+	for _, p := range e.patterns {
+		for _, loc := range p.Regex.FindAllStringSubmatchIndex(text, -1) {
+			if p.Name == "url" {
+				loc[1] = loc[0] + len(strings.TrimRight(text[loc[0]:loc[1]], ".,;:!?)]}'\""))
+			}
+			normalized, qualifiers, ok := p.Build(text, loc)
+			if !ok {
+				continue
+			}
+			qualifiers["rule"] = p.Name
+			spans = append(spans, e.span(req, loc[0], loc[1], p.Type, normalized, p.Confidence, qualifiers))
+		}
+	}
+
+	e.mu.RLock()
+	gaz := e.gazetteer
+	e.mu.RUnlock()
+	for _, m := range gaz.find(text) {
+		qualifiers := map[string]string{"rule": "gazetteer"}
+		if m.entry.CanonicalID != "" {
+			qualifiers["canonicalId"] = m.entry.CanonicalID
+		}
+		spans = append(spans, e.span(req, m.start, m.end, m.entry.Type, m.entry.Normalized, 0.85, qualifiers))
+	}
+
+	entities := filterTypes(resolveOverlaps(spans), req.ExpectedTypes)
+	return &NERResponse{
+		Entities:     entities,
+		ProcessingMs: time.Since(start).Milliseconds(),
+		ModelUsed:    ModelRules,
+	}, nil
+}
+
+func (e *RuleExtractor) span(req NERRequest, start, end int, typ EntityType, normalized string, confidence float32, qualifiers map[string]string) ExtractedEntity {
+	return ExtractedEntity{
+		Text:       req.Text[start:end],
+		Type:       typ,
+		Normalized: normalized,
+		Confidence: confidence,
+		Offset:     start,
+		Length:     end - start,
+		Qualifiers: qualifiers,
+		Context:    snippet(req.Text, start, end, 40),
+		SourceID:   req.SourceID,
+		SourceType: req.SourceType,
+	}
+}
+
+// resolveOverlaps keeps the longest span among overlapping ones, then the
+// most confident, and returns the survivors in text order.
+func resolveOverlaps(spans []ExtractedEntity) []ExtractedEntity {
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].Length != spans[j].Length {
+			return spans[i].Length > spans[j].Length
+		}
+		if spans[i].Confidence != spans[j].Confidence {
+			return spans[i].Confidence > spans[j].Confidence
+		}
+		return spans[i].Offset < spans[j].Offset
+	})
+	var kept []ExtractedEntity
+	for _, s := range spans {
+		if s.Offset < 0 || !overlapsAny(s, kept) {
+			kept = append(kept, s)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Offset < kept[j].Offset })
+	return kept
+}
+
+func overlapsAny(s ExtractedEntity, kept []ExtractedEntity) bool {
+	for _, k := range kept {
+		if k.Offset >= 0 && s.Offset < k.Offset+k.Length && k.Offset < s.Offset+s.Length {
+			return true
+		}
+	}
+	return false
+}
+
+func filterTypes(entities []ExtractedEntity, types []EntityType) []ExtractedEntity {
+	if len(types) == 0 {
+		return entities
+	}
+	out := entities[:0]
+	for _, ent := range entities {
+		for _, t := range types {
+			if ent.Type == t {
+				out = append(out, ent)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// snippet returns up to radius bytes of text either side of [start, end),
+// trimmed to whole words.
+func snippet(text string, start, end, radius int) string {
+	from, to := max(start-radius, 0), min(end+radius, len(text))
+	if from > 0 {
+		if i := strings.IndexByte(text[from:start], ' '); i >= 0 {
+			from += i + 1
+		}
+	}
+	if to < len(text) {
+		if i := strings.LastIndexByte(text[end:to], ' '); i >= 0 {
+			to = end + i
+		}
+	}
+	return strings.Join(strings.Fields(text[from:to]), " ")
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// Ensure interface compliance
+var _ Extractor = (*RuleExtractor)(nil)
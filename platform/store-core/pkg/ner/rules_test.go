@@ -0,0 +1,88 @@
+package ner
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRuleExtractorPatternsAndGazetteer(t *testing.T) {
+	gaz := NewGazetteer([]GazetteerEntry{
+		{Term: "Jane Doe", Type: EntityTypePerson, Normalized: "Jane Doe", CanonicalID: "p1"},
+		{Term: "Atlas", Type: EntityTypeProject, Normalized: "Project Atlas", CanonicalID: "proj1"},
+		{Term: "at", Type: EntityTypeOther}, // too short, skipped
+	})
+	text := "jane doe fixed PAY-142 (see https://jira.acme.com/browse/PAY-142.) and acme/atlas#77 " +
+		"for Atlas v2.3.1; mail ops@Acme.com. Hosts 10.0.0.1 use UTF-8, not Atlassian."
+
+	resp, err := NewRuleExtractor(gaz).Extract(context.Background(), NERRequest{Text: text, SourceType: "slack"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]ExtractedEntity{}
+	for _, e := range resp.Entities {
+		if text[e.Offset:e.Offset+e.Length] != e.Text {
+			t.Errorf("offset mismatch for %q", e.Text)
+		}
+		got[e.Qualifiers["rule"]+":"+e.Normalized] = e
+	}
+	for _, key := range []string{
+		"gazetteer:Jane Doe",
+		"jira_key:PAY-142",
+		"url:https://jira.acme.com/browse/PAY-142",
+		"github_ref:acme/atlas#77",
+		"gazetteer:Project Atlas",
+		"semver:2.3.1",
+		"email:ops@acme.com",
+	} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing %s", key)
+		}
+	}
+	if len(got) != 7 {
+		t.Errorf("expected exactly 7 entities (no IP, UTF-8 or Atlassian hits), got %v", got)
+	}
+	if e := got["gazetteer:Jane Doe"]; e.Type != EntityTypePerson || e.Qualifiers["canonicalId"] != "p1" {
+		t.Errorf("gazetteer hit lost its registry link: %+v", e)
+	}
+	if resp.ModelUsed != ModelRules || resp.TokensUsed != 0 {
+		t.Errorf("rules should not report LLM usage: %+v", resp)
+	}
+}
+
+// stubExtractor returns canned entities or an error.
+type stubExtractor struct {
+	resp *NERResponse
+	err  error
+}
+
+func (s stubExtractor) Extract(context.Context, NERRequest) (*NERResponse, error) {
+	return s.resp, s.err
+}
+
+func TestCombinedExtractorMergesLLMSpans(t *testing.T) {
+	text := "Ping ops@acme.com about the Falcon rollout"
+	rules := NewRuleExtractor(nil)
+	llm := stubExtractor{resp: &NERResponse{ModelUsed: "gpt", TokensUsed: 120, Entities: []ExtractedEntity{
+		{Text: "ops@acme.com", Type: EntityTypeOrganization, Offset: 5, Length: 12, Context: "llm context", Qualifiers: map[string]string{"team": "ops"}},
+		{Text: "Falcon", Type: EntityTypeProject, Normalized: "Falcon", Offset: 28, Length: 6},
+	}}}
+
+	resp, err := NewCombinedExtractor(rules, llm).Extract(context.Background(), NERRequest{Text: text})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Entities) != 2 || resp.ModelUsed != "rules+gpt" || resp.TokensUsed != 120 {
+		t.Fatalf("unexpected merge: %+v", resp)
+	}
+	email := resp.Entities[0]
+	if email.Type != EntityTypePerson || email.Qualifiers["team"] != "ops" || email.Context != text {
+		t.Errorf("rule span should win and pick up LLM qualifiers: %+v", email)
+	}
+
+	resp, err = NewCombinedExtractor(rules, stubExtractor{err: errors.New("offline")}).Extract(context.Background(), NERRequest{Text: text})
+	if err != nil || len(resp.Entities) != 1 {
+		t.Fatalf("LLM failure should fall back to rules: %+v, %v", resp, err)
+	}
+}
@@ -0,0 +1,260 @@
+package ner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ===================================================
+// Observation Store
+// Durable observations and the steward review queue
+// ===================================================
+
+var (
+	// ErrObservationNotFound is returned for unknown (or other-tenant)
+	// observations.
+	ErrObservationNotFound = errors.New("ner: observation not found")
+	// ErrInvalidTransition is returned when a review decision is not allowed
+	// from the observation's current status.
+	ErrInvalidTransition = errors.New("ner: invalid status transition")
+)
+
+// ObservationStore persists observations. Implementations must scope every
+// lookup by tenant.
+type ObservationStore interface {
+	// Save inserts or replaces an observation.
+	Save(ctx context.Context, obs *ObservedEntity) error
+
+	// Get returns an observation, or ErrObservationNotFound.
+	Get(ctx context.Context, tenantID, id string) (*ObservedEntity, error)
+
+	// List returns observations matching q, highest match score first,
+	// with the total number of matches ignoring Limit and Offset.
+	List(ctx context.Context, q ObservationQuery) ([]*ObservedEntity, int, error)
+
+	// Review applies a steward decision to each observation. Failures are
+	// reported per ID and don't stop the rest of the batch.
+	Review(ctx context.Context, tenantID string, ids []string, d ReviewDecision) (*ReviewResult, error)
+
+	// Stats counts a tenant's observations by status.
+	Stats(ctx context.Context, tenantID string) (ObserverStats, error)
+}
+
+// ObservationQuery filters List. TenantID is required.
+type ObservationQuery struct {
+	TenantID   string              `json:"tenantId"`
+	Statuses   []ObservationStatus `json:"statuses,omitempty"`
+	EntityType EntityType          `json:"entityType,omitempty"`
+	SourceType string              `json:"sourceType,omitempty"`
+	SourceID   string              `json:"sourceId,omitempty"`
+	Normalized string              `json:"normalized,omitempty"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+}
+
+// ReviewDecision is a steward's approve or reject.
+type ReviewDecision struct {
+	Status      ObservationStatus `json:"status"`      // StatusMatched (approve) or StatusRejected
+	CanonicalID string            `json:"canonicalId"` // Defaults to the observation's suggestion when approving
+	Reviewer    string            `json:"reviewer"`
+	Note        string            `json:"note,omitempty"`
+}
+
+// ReviewResult reports a bulk review.
+type ReviewResult struct {
+	Updated []*ObservedEntity `json:"updated"`
+	Errors  map[string]string `json:"errors,omitempty"` // observation ID -> error
+}
+
+const (
+	defaultQueueLimit = 50
+	maxQueueLimit     = 500
+)
+
+// reviewableFrom lists, for each review outcome, the statuses it may be
+// applied to. Rejected observations can be approved after all.
+var reviewableFrom = map[ObservationStatus][]ObservationStatus{
+	StatusMatched:  {StatusPending, StatusReview, StatusRejected},
+	StatusRejected: {StatusPending, StatusReview, StatusMatched, StatusCreated},
+}
+
+// applyReview validates d against obs and applies it.
+func applyReview(obs *ObservedEntity, d ReviewDecision, now time.Time) error {
+	from, ok := reviewableFrom[d.Status]
+	if !ok {
+		return fmt.Errorf("%w: cannot review to %q", ErrInvalidTransition, d.Status)
+	}
+	allowed := false
+	for _, s := range from {
+		if s == obs.Status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, obs.Status, d.Status)
+	}
+	if d.Status == StatusMatched {
+		switch {
+		case d.CanonicalID != "":
+			obs.CanonicalID = d.CanonicalID
+		case obs.CanonicalID == "":
+			obs.CanonicalID = obs.SuggestedID
+		}
+		if obs.CanonicalID == "" {
+			return fmt.Errorf("%w: canonical_id is required to approve", ErrInvalidTransition)
+		}
+	}
+	obs.Status = d.Status
+	obs.ReviewedBy = d.Reviewer
+	obs.ReviewNote = d.Note
+	obs.ReviewedAt = &now
+	return nil
+}
+
+func clampQueueLimit(limit int) int {
+	if limit <= 0 {
+		return defaultQueueLimit
+	}
+	if limit > maxQueueLimit {
+		return maxQueueLimit
+	}
+	return limit
+}
+
+// ===================================================
+// In-memory store
+// ===================================================
+
+// MemoryObservationStore keeps observations in process. It is the default
+// for NewEntityObserver and suits tests and single-process tools; anything
+// stewards review should use PostgresObservationStore.
+type MemoryObservationStore struct {
+	mu  sync.RWMutex
+	obs map[string]*ObservedEntity
+}
+
+// NewMemoryObservationStore creates an empty in-memory store.
+func NewMemoryObservationStore() *MemoryObservationStore {
+	return &MemoryObservationStore{obs: make(map[string]*ObservedEntity)}
+}
+
+// Save inserts or replaces an observation.
+func (s *MemoryObservationStore) Save(_ context.Context, obs *ObservedEntity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := *obs
+	s.obs[obs.ID] = &cp
+	return nil
+}
+
+// Get returns a copy of an observation.
+func (s *MemoryObservationStore) Get(_ context.Context, tenantID, id string) (*ObservedEntity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obs, ok := s.obs[id]
+	if !ok || obs.TenantID != tenantID {
+		return nil, ErrObservationNotFound
+	}
+	cp := *obs
+	return &cp, nil
+}
+
+// List returns matching observations, highest score first.
+func (s *MemoryObservationStore) List(_ context.Context, q ObservationQuery) ([]*ObservedEntity, int, error) {
+	s.mu.RLock()
+	var matched []*ObservedEntity
+	for _, obs := range s.obs {
+		if matchesQuery(obs, q) {
+			cp := *obs
+			matched = append(matched, &cp)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].MatchScore != matched[j].MatchScore {
+			return matched[i].MatchScore > matched[j].MatchScore
+		}
+		if !matched[i].ObservedAt.Equal(matched[j].ObservedAt) {
+			return matched[i].ObservedAt.Before(matched[j].ObservedAt)
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	total := len(matched)
+	if q.Offset >= total {
+		return nil, total, nil
+	}
+	end := q.Offset + clampQueueLimit(q.Limit)
+	if end > total {
+		end = total
+	}
+	return matched[q.Offset:end], total, nil
+}
+
+func matchesQuery(obs *ObservedEntity, q ObservationQuery) bool {
+	if obs.TenantID != q.TenantID {
+		return false
+	}
+	if len(q.Statuses) > 0 {
+		found := false
+		for _, st := range q.Statuses {
+			if obs.Status == st {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return (q.EntityType == "" || obs.Entity.Type == q.EntityType) &&
+		(q.SourceType == "" || obs.SourceType == q.SourceType) &&
+		(q.SourceID == "" || obs.SourceID == q.SourceID) &&
+		(q.Normalized == "" || obs.Entity.Normalized == q.Normalized)
+}
+
+// Review applies d to each observation.
+func (s *MemoryObservationStore) Review(_ context.Context, tenantID string, ids []string, d ReviewDecision) (*ReviewResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := &ReviewResult{Errors: map[string]string{}}
+	now := time.Now()
+	for _, id := range ids {
+		obs, ok := s.obs[id]
+		if !ok || obs.TenantID != tenantID {
+			result.Errors[id] = ErrObservationNotFound.Error()
+			continue
+		}
+		cp := *obs
+		if err := applyReview(&cp, d, now); err != nil {
+			result.Errors[id] = err.Error()
+			continue
+		}
+		s.obs[id] = &cp
+		updated := cp
+		result.Updated = append(result.Updated, &updated)
+	}
+	return result, nil
+}
+
+// Stats counts a tenant's observations by status.
+func (s *MemoryObservationStore) Stats(_ context.Context, tenantID string) (ObserverStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats := ObserverStats{}
+	for _, obs := range s.obs {
+		if obs.TenantID == tenantID {
+			stats.add(obs.Status, 1)
+		}
+	}
+	return stats, nil
+}
+
+// Ensure interface compliance
+var _ ObservationStore = (*MemoryObservationStore)(nil)
@@ -0,0 +1,155 @@
+package signalnotify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Dispatcher delivers queued notifications with exponential backoff.
+type Dispatcher struct {
+	Queue Queue
+	Sinks map[string]Sink
+	// BatchSize is the number of deliveries claimed per poll (default 50).
+	BatchSize int
+	// MaxAttempts before a delivery is marked dead (default 8).
+	MaxAttempts int
+	// Backoff is the delay after the first failure, doubled per attempt and
+	// capped at one hour (default 30s).
+	Backoff time.Duration
+	// Lease hides claimed deliveries from other dispatchers (default 2m).
+	Lease time.Duration
+
+	now func() time.Time
+}
+
+// NewDispatcher returns a dispatcher with default settings.
+func NewDispatcher(q Queue, sinks map[string]Sink) *Dispatcher {
+	return &Dispatcher{Queue: q, Sinks: sinks}
+}
+
+// Stats summarises one dispatch pass.
+type Stats struct {
+	Sent        int
+	Failed      int
+	Dead        int
+	RateLimited int
+}
+
+// Run polls the outbox every interval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if stats, err := d.RunOnce(ctx); err != nil {
+			log.Printf("signal notifications: %v", err)
+		} else if stats != (Stats{}) {
+			log.Printf("signal notifications: sent=%d failed=%d dead=%d rate_limited=%d", stats.Sent, stats.Failed, stats.Dead, stats.RateLimited)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// RunOnce claims one batch of due deliveries and attempts each.
+func (d *Dispatcher) RunOnce(ctx context.Context) (Stats, error) {
+	var stats Stats
+	now := time.Now
+	if d.now != nil {
+		now = d.now
+	}
+	batch, err := d.Queue.Claim(ctx, orDefault(d.BatchSize, 50), orDefaultDuration(d.Lease, 2*time.Minute))
+	if err != nil {
+		return stats, fmt.Errorf("claim: %w", err)
+	}
+	// Sends per rule over the last hour, loaded once per pass.
+	type window struct {
+		sent   int
+		oldest time.Time
+	}
+	windows := map[string]*window{}
+	for _, del := range batch {
+		w := windows[del.Rule.ID]
+		if limit := del.Rule.RateLimitPerHour; limit > 0 {
+			if w == nil {
+				w = &window{}
+				if w.sent, w.oldest, err = d.Queue.SentSince(ctx, del.Rule.ID, now().Add(-time.Hour)); err != nil {
+					return stats, fmt.Errorf("rate limit: %w", err)
+				}
+				windows[del.Rule.ID] = w
+			}
+			if w.sent >= limit {
+				// Retry once the oldest send leaves the window.
+				stats.RateLimited++
+				if err := d.Queue.MarkRateLimited(ctx, del.ID, w.oldest.Add(time.Hour)); err != nil {
+					return stats, err
+				}
+				continue
+			}
+		}
+
+		err := d.deliver(ctx, del)
+		if err == nil {
+			stats.Sent++
+			if w != nil {
+				if w.sent == 0 {
+					w.oldest = now()
+				}
+				w.sent++
+			}
+			if err := d.Queue.MarkSent(ctx, del.ID); err != nil {
+				return stats, err
+			}
+			continue
+		}
+		attempts := del.Attempts + 1
+		dead := attempts >= orDefault(d.MaxAttempts, 8)
+		if dead {
+			stats.Dead++
+		} else {
+			stats.Failed++
+		}
+		next := now().Add(backoff(orDefaultDuration(d.Backoff, 30*time.Second), attempts))
+		if err := d.Queue.MarkFailed(ctx, del.ID, attempts, next, err.Error(), dead); err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, del Delivery) error {
+	sink, ok := d.Sinks[del.Rule.Sink]
+	if !ok {
+		return fmt.Errorf("sink %q not configured", del.Rule.Sink)
+	}
+	return sink.Send(ctx, del.Rule.Target, del.Event)
+}
+
+func backoff(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
+
+func orDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+func orDefaultDuration(v, def time.Duration) time.Duration {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,140 @@
+// Package signalnotify routes signal instance transitions to humans. Events
+// are written to an outbox in the same transaction as the instance history
+// row they describe, so each transition notifies at most once per rule; a
+// Dispatcher delivers them to webhook, Slack-compatible and SMTP sinks with
+// retries and per-rule rate limits.
+package signalnotify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventKind describes what happened to an instance.
+type EventKind string
+
+const (
+	EventOpened    EventKind = "opened"
+	EventReopened  EventKind = "reopened"
+	EventEscalated EventKind = "escalated"
+	// Manual transitions use the lowercased target status (acknowledged,
+	// snoozed, resolved, suppressed).
+)
+
+// DefaultEvents are routed when a rule does not list any.
+var DefaultEvents = []EventKind{EventOpened, EventReopened, EventEscalated}
+
+// Event is the payload delivered to sinks. ID is the instance history entry
+// the event was derived from and doubles as the dedup key.
+type Event struct {
+	ID               string    `json:"id"`
+	Kind             EventKind `json:"kind"`
+	InstanceID       string    `json:"instanceId"`
+	DefinitionID     string    `json:"definitionId"`
+	DefinitionSlug   string    `json:"definitionSlug,omitempty"`
+	DefinitionTitle  string    `json:"definitionTitle,omitempty"`
+	EntityRef        string    `json:"entityRef"`
+	EntityKind       string    `json:"entityKind,omitempty"`
+	Status           string    `json:"status"`
+	PreviousStatus   string    `json:"previousStatus,omitempty"`
+	Severity         string    `json:"severity"`
+	PreviousSeverity string    `json:"previousSeverity,omitempty"`
+	Summary          string    `json:"summary,omitempty"`
+	TenantID         string    `json:"tenantId,omitempty"`
+	ProjectID        string    `json:"projectId,omitempty"`
+	SourceRunID      string    `json:"sourceRunId,omitempty"`
+	OccurredAt       time.Time `json:"occurredAt"`
+}
+
+// Sink kinds.
+const (
+	SinkWebhook = "webhook"
+	SinkSlack   = "slack"
+	SinkSMTP    = "smtp"
+)
+
+// Rule routes events for one definition (or all when DefinitionID is empty)
+// to a sink. Empty TenantID/ProjectID match any tenant/project.
+type Rule struct {
+	ID           string
+	Name         string
+	DefinitionID string
+	MinSeverity  string
+	TenantID     string
+	ProjectID    string
+	Events       []EventKind
+	Sink         string
+	Target       string // webhook URL, or comma-separated recipients for smtp
+	// RateLimitPerHour caps deliveries per rule; 0 means unlimited. Events
+	// over the limit stay queued and are retried once the oldest delivery in
+	// the hour window ages out.
+	RateLimitPerHour int
+	Enabled          bool
+}
+
+// Matches reports whether the rule routes ev.
+func (r Rule) Matches(ev Event) bool {
+	if !r.Enabled {
+		return false
+	}
+	if r.DefinitionID != "" && r.DefinitionID != ev.DefinitionID {
+		return false
+	}
+	if r.TenantID != "" && r.TenantID != ev.TenantID {
+		return false
+	}
+	if r.ProjectID != "" && r.ProjectID != ev.ProjectID {
+		return false
+	}
+	if SeverityRank(ev.Severity) < SeverityRank(r.MinSeverity) {
+		return false
+	}
+	events := r.Events
+	if len(events) == 0 {
+		events = DefaultEvents
+	}
+	for _, k := range events {
+		if k == ev.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// SeverityRank orders INFO < WARNING < ERROR < CRITICAL; unknown values rank
+// with INFO.
+func SeverityRank(severity string) int {
+	switch strings.ToUpper(severity) {
+	case "WARNING":
+		return 1
+	case "ERROR":
+		return 2
+	case "CRITICAL":
+		return 3
+	}
+	return 0
+}
+
+// FormatText renders a one-line human summary of ev.
+func FormatText(ev Event) string {
+	title := ev.DefinitionTitle
+	if title == "" {
+		title = ev.DefinitionSlug
+	}
+	if title == "" {
+		title = ev.DefinitionID
+	}
+	var what string
+	switch ev.Kind {
+	case EventEscalated:
+		what = fmt.Sprintf("escalated %s -> %s", ev.PreviousSeverity, ev.Severity)
+	default:
+		what = fmt.Sprintf("%s [%s]", ev.Kind, ev.Severity)
+	}
+	text := fmt.Sprintf("Signal %s %s: %s", title, what, ev.EntityRef)
+	if ev.Summary != "" && ev.Summary != title {
+		text += " - " + ev.Summary
+	}
+	return text
+}
@@ -0,0 +1,226 @@
+package signalnotify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memQueue is an in-memory Queue for dispatcher tests.
+type memQueue struct {
+	items map[string]*memItem
+	order []string
+}
+
+type memItem struct {
+	del    Delivery
+	status string
+	next   time.Time
+	sentAt time.Time
+	err    string
+}
+
+func (q *memQueue) add(id string, r Rule, ev Event) {
+	if q.items == nil {
+		q.items = map[string]*memItem{}
+	}
+	q.items[id] = &memItem{del: Delivery{ID: id, Rule: r, Event: ev}, status: OutboxPending}
+	q.order = append(q.order, id)
+}
+
+func (q *memQueue) Claim(_ context.Context, limit int, _ time.Duration) ([]Delivery, error) {
+	var out []Delivery
+	for _, id := range q.order {
+		it := q.items[id]
+		if it.status == OutboxPending && len(out) < limit {
+			out = append(out, it.del)
+		}
+	}
+	return out, nil
+}
+
+func (q *memQueue) MarkSent(_ context.Context, id string) error {
+	q.items[id].status, q.items[id].sentAt = OutboxSent, time.Now()
+	return nil
+}
+
+func (q *memQueue) MarkRateLimited(_ context.Context, id string, next time.Time) error {
+	q.items[id].next, q.items[id].err = next, "rate limited"
+	return nil
+}
+
+func (q *memQueue) MarkFailed(_ context.Context, id string, attempts int, next time.Time, errMsg string, dead bool) error {
+	it := q.items[id]
+	it.del.Attempts, it.next, it.err = attempts, next, errMsg
+	if dead {
+		it.status = OutboxDead
+	}
+	return nil
+}
+
+func (q *memQueue) SentSince(_ context.Context, ruleID string, t time.Time) (int, time.Time, error) {
+	n := 0
+	var oldest time.Time
+	for _, it := range q.items {
+		if it.del.Rule.ID == ruleID && it.status == OutboxSent && !it.sentAt.Before(t) {
+			n++
+			if oldest.IsZero() || it.sentAt.Before(oldest) {
+				oldest = it.sentAt
+			}
+		}
+	}
+	return n, oldest, nil
+}
+
+func TestRuleMatches(t *testing.T) {
+	ev := Event{Kind: EventOpened, DefinitionID: "d1", Severity: "ERROR", TenantID: "acme"}
+	cases := map[string]struct {
+		rule Rule
+		want bool
+	}{
+		"error threshold":  {Rule{Enabled: true, MinSeverity: "ERROR"}, true},
+		"above threshold":  {Rule{Enabled: true, MinSeverity: "CRITICAL"}, false},
+		"disabled":         {Rule{MinSeverity: "INFO"}, false},
+		"other definition": {Rule{Enabled: true, DefinitionID: "d2"}, false},
+		"tenant":           {Rule{Enabled: true, TenantID: "acme"}, true},
+		"other project":    {Rule{Enabled: true, ProjectID: "p9"}, false},
+		"event filter":     {Rule{Enabled: true, Events: []EventKind{EventEscalated}}, false},
+		"resolved opt-in":  {Rule{Enabled: true, Events: []EventKind{"resolved", EventOpened}}, true},
+	}
+	for name, tc := range cases {
+		if got := tc.rule.Matches(ev); got != tc.want {
+			t.Errorf("%s: Matches = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestUpsertRuleValidates(t *testing.T) {
+	o := NewOutbox(nil) // rejected before touching the database
+	for name, r := range map[string]Rule{
+		"sink":       {Sink: "pager", Target: "x"},
+		"target":     {Sink: SinkWebhook},
+		"rate limit": {Sink: SinkSMTP, Target: "ops@acme.com", RateLimitPerHour: -1},
+	} {
+		if _, err := o.UpsertRule(context.Background(), r); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDispatcherDeliversToHTTPSinks(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string][]byte{}
+	var signature string
+	fail := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/flaky" && fail {
+			fail = false
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/hook" {
+			signature = r.Header.Get("X-Signal-Signature")
+		}
+		bodies[r.URL.Path] = body
+	}))
+	defer srv.Close()
+
+	ev := Event{ID: "h1", Kind: EventEscalated, DefinitionTitle: "Stale work item", EntityRef: "JIRA-7",
+		Severity: "ERROR", PreviousSeverity: "WARNING", Status: "OPEN"}
+	q := &memQueue{}
+	q.add("hook", Rule{ID: "r1", Sink: SinkWebhook, Target: srv.URL + "/hook", Enabled: true}, ev)
+	q.add("slack", Rule{ID: "r2", Sink: SinkSlack, Target: srv.URL + "/slack", Enabled: true}, ev)
+	q.add("flaky", Rule{ID: "r3", Sink: SinkSlack, Target: srv.URL + "/flaky", Enabled: true}, ev)
+	q.add("smtp", Rule{ID: "r4", Sink: SinkSMTP, Target: "lead@example.com", Enabled: true}, ev)
+
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	d := NewDispatcher(q, map[string]Sink{
+		SinkWebhook: &WebhookSink{Secret: "s3cret"},
+		SinkSlack:   &SlackSink{},
+	})
+	d.MaxAttempts = 2
+	d.now = func() time.Time { return now }
+
+	stats, err := d.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Sent != 2 || stats.Failed != 2 {
+		t.Fatalf("first pass stats = %+v", stats)
+	}
+	var got Event
+	if err := json.Unmarshal(bodies["/hook"], &got); err != nil || got.EntityRef != "JIRA-7" {
+		t.Fatalf("webhook body = %s (%v)", bodies["/hook"], err)
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(bodies["/hook"])
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Fatalf("signature = %q, want %q", signature, want)
+	}
+	var slack map[string]string
+	_ = json.Unmarshal(bodies["/slack"], &slack)
+	if text := slack["text"]; !strings.Contains(text, "escalated WARNING -> ERROR") || !strings.Contains(text, "JIRA-7") {
+		t.Fatalf("slack text = %q", text)
+	}
+	if it := q.items["flaky"]; it.status != OutboxPending || it.del.Attempts != 1 || !it.next.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("flaky delivery should be scheduled for retry: %+v", it)
+	}
+
+	// Second pass: the flaky endpoint recovers; the unconfigured SMTP sink
+	// exhausts its attempts.
+	stats, err = d.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Sent != 1 || stats.Dead != 1 {
+		t.Fatalf("second pass stats = %+v", stats)
+	}
+	if q.items["smtp"].status != OutboxDead || q.items["flaky"].status != OutboxSent {
+		t.Fatalf("unexpected statuses: smtp=%s flaky=%s", q.items["smtp"].status, q.items["flaky"].status)
+	}
+}
+
+func TestDispatcherRateLimit(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer srv.Close()
+	rule := Rule{ID: "r1", Sink: SinkWebhook, Target: srv.URL, Enabled: true, RateLimitPerHour: 2}
+	q := &memQueue{}
+	for _, id := range []string{"a", "b", "c"} {
+		q.add(id, rule, Event{ID: id, Kind: EventOpened, Severity: "ERROR"})
+	}
+	start := time.Now()
+	stats, err := NewDispatcher(q, map[string]Sink{SinkWebhook: &WebhookSink{}}).RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Sent != 2 || stats.RateLimited != 1 || hits != 2 {
+		t.Fatalf("stats = %+v hits = %d", stats, hits)
+	}
+	// The third delivery waits for the first send to leave the window.
+	c := q.items["c"]
+	if c.status != OutboxPending || c.del.Attempts != 0 || c.next.Before(start.Add(time.Hour)) || c.next.After(q.items["a"].sentAt.Add(time.Hour)) {
+		t.Fatalf("expected c to be deferred by an hour, got %+v", c)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	if got := backoff(30*time.Second, 3); got != 2*time.Minute {
+		t.Fatalf("backoff(3) = %v", got)
+	}
+	if got := backoff(30*time.Second, 20); got != time.Hour {
+		t.Fatalf("backoff(20) = %v", got)
+	}
+}
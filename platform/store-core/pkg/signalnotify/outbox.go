@@ -0,0 +1,298 @@
+package signalnotify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Outbox statuses.
+const (
+	OutboxPending = "PENDING"
+	OutboxSent    = "SENT"
+	OutboxDead    = "DEAD"
+)
+
+// rulesTTL bounds how stale the cached routing rules may be.
+const rulesTTL = 30 * time.Second
+
+// Delivery is a claimed outbox row.
+type Delivery struct {
+	ID       string
+	Rule     Rule
+	Event    Event
+	Attempts int
+}
+
+// Queue is the outbox as seen by the Dispatcher.
+type Queue interface {
+	// Claim leases up to limit due deliveries so concurrent dispatchers skip them.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error)
+	MarkSent(ctx context.Context, id string) error
+	// MarkRateLimited defers a delivery, without counting an attempt, until
+	// its rule's rate window has room again.
+	MarkRateLimited(ctx context.Context, id string, next time.Time) error
+	// MarkFailed records a failed attempt; dead deliveries are not retried.
+	MarkFailed(ctx context.Context, id string, attempts int, next time.Time, errMsg string, dead bool) error
+	// SentSince counts the rule's deliveries since t and returns the time of
+	// the oldest (zero when there are none).
+	SentSince(ctx context.Context, ruleID string, t time.Time) (int, time.Time, error)
+}
+
+// Outbox stores routing rules and pending notifications in Postgres.
+type Outbox struct {
+	db *sql.DB
+
+	mu       sync.Mutex
+	rules    []Rule
+	loadedAt time.Time
+}
+
+func NewOutbox(db *sql.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+// Enqueue writes one outbox row per matching rule inside tx, so the event is
+// only queued if the transition it describes commits. Rows are unique per
+// (rule, event), making retries of the enclosing write idempotent.
+func (o *Outbox) Enqueue(ctx context.Context, tx *sql.Tx, ev Event) error {
+	rules, err := o.cachedRules(ctx)
+	if err != nil {
+		return err
+	}
+	var payload []byte
+	for _, r := range rules {
+		if !r.Matches(ev) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(ev); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO metadata.signal_notification_outbox (id, rule_id, event_id, instance_id, payload, status, attempts, next_attempt_at, created_at)
+VALUES ($1,$2,$3,$4,$5,'PENDING',0,now(),now())
+ON CONFLICT (rule_id, event_id) DO NOTHING`, uuid.New().String(), r.ID, ev.ID, ev.InstanceID, payload); err != nil {
+			return fmt.Errorf("enqueue notification: %w", err)
+		}
+	}
+	return nil
+}
+
+func (o *Outbox) cachedRules(ctx context.Context) ([]Rule, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.rules != nil && time.Since(o.loadedAt) < rulesTTL {
+		return o.rules, nil
+	}
+	rules, err := o.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o.rules, o.loadedAt = rules, time.Now()
+	return rules, nil
+}
+
+const ruleColumns = `r.id, COALESCE(r.name, ''), COALESCE(r.definition_id::text, ''), r.min_severity, COALESCE(r.tenant_id, ''), COALESCE(r.project_id, ''),
+  r.events, r.sink, r.target, r.rate_limit_per_hour, r.enabled`
+
+func scanRule(row interface{ Scan(...any) error }, r *Rule) error {
+	var events []string
+	if err := row.Scan(&r.ID, &r.Name, &r.DefinitionID, &r.MinSeverity, &r.TenantID, &r.ProjectID,
+		pq.Array(&events), &r.Sink, &r.Target, &r.RateLimitPerHour, &r.Enabled); err != nil {
+		return err
+	}
+	r.Events = r.Events[:0]
+	for _, e := range events {
+		r.Events = append(r.Events, EventKind(e))
+	}
+	return nil
+}
+
+// ListRules returns the enabled routing rules.
+func (o *Outbox) ListRules(ctx context.Context) ([]Rule, error) {
+	rows, err := o.db.QueryContext(ctx, `SELECT `+ruleColumns+` FROM metadata.signal_notification_rules r WHERE r.enabled`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rules := []Rule{}
+	for rows.Next() {
+		var r Rule
+		if err := scanRule(rows, &r); err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// UpsertRule creates or updates a routing rule and returns its id. Rules are
+// managed through this and DeleteRule; enqueuing picks up changes from this
+// process at once and from others within rulesTTL.
+func (o *Outbox) UpsertRule(ctx context.Context, r Rule) (string, error) {
+	switch r.Sink {
+	case SinkWebhook, SinkSlack, SinkSMTP:
+	default:
+		return "", fmt.Errorf("unsupported sink %q", r.Sink)
+	}
+	if r.Target == "" {
+		return "", fmt.Errorf("target is required")
+	}
+	if r.RateLimitPerHour < 0 {
+		return "", fmt.Errorf("rate limit must not be negative")
+	}
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	if r.MinSeverity == "" {
+		r.MinSeverity = "ERROR"
+	}
+	events := make([]string, 0, len(r.Events))
+	for _, e := range r.Events {
+		events = append(events, string(e))
+	}
+	if len(events) == 0 {
+		for _, e := range DefaultEvents {
+			events = append(events, string(e))
+		}
+	}
+	var id string
+	err := o.db.QueryRowContext(ctx, `
+INSERT INTO metadata.signal_notification_rules
+  (id, name, definition_id, min_severity, tenant_id, project_id, events, sink, target, rate_limit_per_hour, enabled, created_at, updated_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,now(),now())
+ON CONFLICT (id) DO UPDATE SET
+  name=EXCLUDED.name,
+  definition_id=EXCLUDED.definition_id,
+  min_severity=EXCLUDED.min_severity,
+  tenant_id=EXCLUDED.tenant_id,
+  project_id=EXCLUDED.project_id,
+  events=EXCLUDED.events,
+  sink=EXCLUDED.sink,
+  target=EXCLUDED.target,
+  rate_limit_per_hour=EXCLUDED.rate_limit_per_hour,
+  enabled=EXCLUDED.enabled,
+  updated_at=now()
+RETURNING id`,
+		r.ID, nullString(r.Name), nullString(r.DefinitionID), r.MinSeverity, nullString(r.TenantID), nullString(r.ProjectID),
+		pq.Array(events), r.Sink, r.Target, r.RateLimitPerHour, r.Enabled,
+	).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+	o.resetRules()
+	return id, nil
+}
+
+// DeleteRule removes a routing rule and reports whether it existed. Its
+// outbox rows are deleted with it.
+func (o *Outbox) DeleteRule(ctx context.Context, id string) (bool, error) {
+	res, err := o.db.ExecContext(ctx, `DELETE FROM metadata.signal_notification_rules WHERE id=$1`, id)
+	if err != nil {
+		return false, err
+	}
+	o.resetRules()
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// resetRules makes the next Enqueue reload the routing rules.
+func (o *Outbox) resetRules() {
+	o.mu.Lock()
+	o.rules = nil
+	o.mu.Unlock()
+}
+
+func nullString(val string) any {
+	if val == "" {
+		return nil
+	}
+	return val
+}
+
+func (o *Outbox) Claim(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error) {
+	rows, err := o.db.QueryContext(ctx, `
+WITH due AS (
+  SELECT o.id FROM metadata.signal_notification_outbox o
+  JOIN metadata.signal_notification_rules r ON r.id = o.rule_id AND r.enabled
+  WHERE o.status = 'PENDING' AND o.next_attempt_at <= now()
+  ORDER BY o.next_attempt_at
+  LIMIT $1
+  FOR UPDATE OF o SKIP LOCKED
+), leased AS (
+  UPDATE metadata.signal_notification_outbox o
+  SET next_attempt_at = now() + $2 * interval '1 second'
+  FROM due WHERE o.id = due.id
+  RETURNING o.id, o.rule_id, o.payload, o.attempts
+)
+SELECT leased.id, leased.payload, leased.attempts, `+ruleColumns+`
+FROM leased JOIN metadata.signal_notification_rules r ON r.id = leased.rule_id`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Delivery
+	for rows.Next() {
+		var d Delivery
+		var payload []byte
+		var events []string
+		r := &d.Rule
+		if err := rows.Scan(&d.ID, &payload, &d.Attempts, &r.ID, &r.Name, &r.DefinitionID, &r.MinSeverity, &r.TenantID, &r.ProjectID,
+			pq.Array(&events), &r.Sink, &r.Target, &r.RateLimitPerHour, &r.Enabled); err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			r.Events = append(r.Events, EventKind(e))
+		}
+		if err := json.Unmarshal(payload, &d.Event); err != nil {
+			return nil, fmt.Errorf("decode outbox %s: %w", d.ID, err)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (o *Outbox) MarkSent(ctx context.Context, id string) error {
+	_, err := o.db.ExecContext(ctx, `
+UPDATE metadata.signal_notification_outbox
+SET status='SENT', attempts=attempts+1, sent_at=now(), last_error=NULL
+WHERE id=$1`, id)
+	return err
+}
+
+func (o *Outbox) MarkRateLimited(ctx context.Context, id string, next time.Time) error {
+	_, err := o.db.ExecContext(ctx, `
+UPDATE metadata.signal_notification_outbox
+SET status='PENDING', next_attempt_at=$2, last_error='rate limited'
+WHERE id=$1`, id, next)
+	return err
+}
+
+func (o *Outbox) MarkFailed(ctx context.Context, id string, attempts int, next time.Time, errMsg string, dead bool) error {
+	status := OutboxPending
+	if dead {
+		status = OutboxDead
+	}
+	_, err := o.db.ExecContext(ctx, `
+UPDATE metadata.signal_notification_outbox
+SET status=$2, attempts=$3, next_attempt_at=$4, last_error=$5
+WHERE id=$1`, id, status, attempts, next, errMsg)
+	return err
+}
+
+func (o *Outbox) SentSince(ctx context.Context, ruleID string, t time.Time) (int, time.Time, error) {
+	var n int
+	var oldest sql.NullTime
+	err := o.db.QueryRowContext(ctx, `
+SELECT count(*), min(sent_at) FROM metadata.signal_notification_outbox
+WHERE rule_id=$1 AND status='SENT' AND sent_at >= $2`, ruleID, t).Scan(&n, &oldest)
+	return n, oldest.Time, err
+}
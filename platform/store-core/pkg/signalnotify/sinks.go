@@ -0,0 +1,161 @@
+package signalnotify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// Sink delivers an event to target (a URL or recipient list, per sink).
+type Sink interface {
+	Send(ctx context.Context, target string, ev Event) error
+}
+
+// WebhookSink POSTs the event as JSON. When Secret is set the body is signed
+// with HMAC-SHA256 in the X-Signal-Signature header.
+type WebhookSink struct {
+	Client *http.Client
+	Secret string
+}
+
+func (s *WebhookSink) Send(ctx context.Context, target string, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{}
+	if s.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.Secret))
+		mac.Write(body)
+		headers["X-Signal-Signature"] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	return postJSON(ctx, s.Client, target, body, headers)
+}
+
+// SlackSink posts a text message to a Slack-compatible incoming webhook.
+type SlackSink struct {
+	Client *http.Client
+}
+
+func (s *SlackSink) Send(ctx context.Context, target string, ev Event) error {
+	body, err := json.Marshal(map[string]string{"text": FormatText(ev)})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.Client, target, body, nil)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %d: %s", url, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// SMTPSink emails the event to the comma-separated recipients in target.
+type SMTPSink struct {
+	Addr     string // host:port
+	From     string
+	Username string
+	Password string
+}
+
+// SMTPSinkFromEnv reads SMTP_HOST, SMTP_PORT (default 587), SMTP_FROM,
+// SMTP_USERNAME and SMTP_PASSWORD. It returns nil when SMTP_HOST is unset.
+func SMTPSinkFromEnv() *SMTPSink {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = "signals@localhost"
+	}
+	return &SMTPSink{
+		Addr:     net.JoinHostPort(host, port),
+		From:     from,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+	}
+}
+
+func (s *SMTPSink) Send(ctx context.Context, target string, ev Event) error {
+	var to []string
+	for _, addr := range strings.Split(target, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+	if len(to) == 0 {
+		return fmt.Errorf("no recipients")
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, _ := net.SplitHostPort(s.Addr)
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	// net/smtp has no context support; deliveries are bounded by the
+	// dispatcher's lease instead.
+	return smtp.SendMail(s.Addr, auth, s.From, to, buildMail(s.From, to, ev))
+}
+
+var headerSafe = strings.NewReplacer("\r", " ", "\n", " ")
+
+func buildMail(from string, to []string, ev Event) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: [%s] %s\r\n", ev.Severity, headerSafe.Replace(FormatText(ev)))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&b, "%s\r\n\r\n", FormatText(ev))
+	fmt.Fprintf(&b, "Entity: %s (%s)\r\nStatus: %s\r\nSeverity: %s\r\n", ev.EntityRef, ev.EntityKind, ev.Status, ev.Severity)
+	if ev.SourceRunID != "" {
+		fmt.Fprintf(&b, "Run: %s\r\n", ev.SourceRunID)
+	}
+	return []byte(b.String())
+}
+
+// DefaultSinks returns the webhook and Slack sinks, plus SMTP when configured.
+// SIGNAL_WEBHOOK_SECRET enables webhook signing.
+func DefaultSinks() map[string]Sink {
+	sinks := map[string]Sink{
+		SinkWebhook: &WebhookSink{Secret: os.Getenv("SIGNAL_WEBHOOK_SECRET")},
+		SinkSlack:   &SlackSink{},
+	}
+	if smtpSink := SMTPSinkFromEnv(); smtpSink != nil {
+		sinks[SinkSMTP] = smtpSink
+	}
+	return sinks
+}
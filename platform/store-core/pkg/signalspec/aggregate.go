@@ -0,0 +1,172 @@
+package signalspec
+
+import "strings"
+
+// cdm.generic.aggregate groups records, aggregates each group (optionally
+// within a time window) and flags groups meeting a condition.
+
+type AggregateFn string
+
+const (
+	AggCount    AggregateFn = "count"
+	AggSum      AggregateFn = "sum"
+	AggAvg      AggregateFn = "avg"
+	AggDistinct AggregateFn = "distinct"
+)
+
+type WindowKind string
+
+const (
+	// WindowSliding covers the size leading up to now.
+	WindowSliding WindowKind = "sliding"
+	// WindowTumbling covers the last complete window aligned to UTC (days
+	// start at midnight, weeks on Monday).
+	WindowTumbling WindowKind = "tumbling"
+)
+
+type AggregateSpec struct {
+	Name  string      `json:"name"`
+	Fn    AggregateFn `json:"fn"`
+	Field string      `json:"field,omitempty"` // optional for count (counts non-null values when set)
+}
+
+type AggregateWindow struct {
+	Kind      WindowKind     `json:"kind"`
+	Size      IntervalConfig `json:"size"`
+	TimeField string         `json:"timeField,omitempty"` // default updatedAt, then createdAt
+}
+
+// AggregateCondition compares an aggregate with Value. With Ratio set it
+// compares current/previous window instead (e.g. GTE 3 for "up 3x").
+type AggregateCondition struct {
+	Aggregate string  `json:"aggregate"`
+	Op        Op      `json:"op"`
+	Value     float64 `json:"value"`
+	Ratio     bool    `json:"ratio,omitempty"`
+}
+
+type GenericAggregateConfig struct {
+	CdmModelID      string             `json:"cdmModelId,omitempty"`
+	Where           Condition          `json:"where,omitempty"`
+	GroupBy         []string           `json:"groupBy"`
+	GroupEntityKind string             `json:"groupEntityKind,omitempty"` // default first groupBy field
+	Aggregates      []AggregateSpec    `json:"aggregates"`
+	Window          *AggregateWindow   `json:"window,omitempty"`
+	Condition       AggregateCondition `json:"condition"`
+	SeverityRules   []SeverityRule     `json:"severityRules,omitempty"` // evaluated on group keys, aggregates and ratio
+	SummaryTemplate string             `json:"summaryTemplate,omitempty"`
+}
+
+func parseAggregateConfig(cfg map[string]any, path string) (GenericAggregateConfig, error) {
+	out := GenericAggregateConfig{}
+	if v, ok := cfg["cdmModelId"]; ok && v != nil {
+		model, _ := v.(string)
+		if strings.TrimSpace(model) == "" {
+			return out, errorf(join(path, "cdmModelId"), "must be a non-empty string")
+		}
+		out.CdmModelID = model
+	}
+	if cfg["where"] != nil {
+		where, err := ParseCondition(cfg["where"], join(path, "where"))
+		if err != nil {
+			return out, err
+		}
+		out.Where = where
+	}
+	out.GroupBy = strSlice(cfg["groupBy"])
+	if len(out.GroupBy) == 0 {
+		return out, errorf(join(path, "groupBy"), "must list at least one field")
+	}
+	out.GroupEntityKind, _ = cfg["groupEntityKind"].(string)
+
+	aggsPath := join(path, "aggregates")
+	aggs, ok := cfg["aggregates"].([]any)
+	if !ok || len(aggs) == 0 {
+		return out, errorf(aggsPath, "must be a non-empty array")
+	}
+	names := map[string]bool{}
+	for i, item := range aggs {
+		itemPath := index(aggsPath, i)
+		m, ok := item.(map[string]any)
+		if !ok {
+			return out, errorf(itemPath, "must be an object")
+		}
+		spec := AggregateSpec{}
+		spec.Name, _ = m["name"].(string)
+		fn, _ := m["fn"].(string)
+		spec.Fn = AggregateFn(strings.ToLower(fn))
+		spec.Field, _ = m["field"].(string)
+		if strings.TrimSpace(spec.Name) == "" {
+			return out, errorf(join(itemPath, "name"), "is required")
+		}
+		if names[spec.Name] {
+			return out, errorf(join(itemPath, "name"), "duplicate aggregate %s", spec.Name)
+		}
+		names[spec.Name] = true
+		switch spec.Fn {
+		case AggCount:
+		case AggSum, AggAvg, AggDistinct:
+			if strings.TrimSpace(spec.Field) == "" {
+				return out, errorf(join(itemPath, "field"), "is required for %s", spec.Fn)
+			}
+		default:
+			return out, errorf(join(itemPath, "fn"), "unsupported fn %s (count|sum|avg|distinct)", fn)
+		}
+		out.Aggregates = append(out.Aggregates, spec)
+	}
+
+	windowPath := join(path, "window")
+	if raw, ok := cfg["window"].(map[string]any); ok {
+		w := &AggregateWindow{Kind: WindowSliding}
+		if kind, _ := raw["kind"].(string); kind != "" {
+			w.Kind = WindowKind(strings.ToLower(kind))
+		}
+		if w.Kind != WindowSliding && w.Kind != WindowTumbling {
+			return out, errorf(join(windowPath, "kind"), "must be sliding or tumbling")
+		}
+		size, ok := parseIntervalCfg(raw["size"])
+		if !ok {
+			return out, errorf(join(windowPath, "size"), "is required (days|hours)")
+		}
+		w.Size = size
+		w.TimeField, _ = raw["timeField"].(string)
+		out.Window = w
+	} else if cfg["window"] != nil {
+		return out, errorf(windowPath, "must be an object")
+	}
+
+	condPath := join(path, "condition")
+	condRaw, ok := cfg["condition"].(map[string]any)
+	if !ok {
+		return out, errorf(condPath, "must be an object")
+	}
+	cond := AggregateCondition{}
+	cond.Aggregate, _ = condRaw["aggregate"].(string)
+	if !names[cond.Aggregate] {
+		return out, errorf(join(condPath, "aggregate"), "must name one of the aggregates")
+	}
+	opRaw, _ := condRaw["op"].(string)
+	cond.Op = Op(opRaw)
+	if cond.Op.Symbol() == "" {
+		return out, errorf(join(condPath, "op"), "must be one of LT, LTE, GT, GTE, EQ, NEQ")
+	}
+	value, ok := toFloatMaybe(condRaw["value"])
+	if !ok {
+		return out, errorf(join(condPath, "value"), "must be a number")
+	}
+	cond.Value = value
+	cond.Ratio, _ = condRaw["ratio"].(bool)
+	if cond.Ratio && out.Window == nil {
+		return out, errorf(join(condPath, "ratio"), "requires a window")
+	}
+	out.Condition = cond
+
+	rules, err := parseSeverityRules(cfg["severityRules"], join(path, "severityRules"))
+	if err != nil {
+		return out, err
+	}
+	out.SeverityRules = rules
+	summary, _ := cfg["summaryTemplate"].(string)
+	out.SummaryTemplate = strings.TrimSpace(summary)
+	return out, nil
+}
@@ -0,0 +1,586 @@
+package signalspec
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Op string
+
+const (
+	OpLT         Op = "LT"
+	OpLTE        Op = "LTE"
+	OpGT         Op = "GT"
+	OpGTE        Op = "GTE"
+	OpEQ         Op = "EQ"
+	OpNEQ        Op = "NEQ"
+	OpIN         Op = "IN"
+	OpNOTIN      Op = "NOT_IN"
+	OpISNULL     Op = "IS_NULL"
+	OpISNOTNULL  Op = "IS_NOT_NULL"
+	OpMatches    Op = "MATCHES"  // RE2 regular expression
+	OpContains   Op = "CONTAINS" // substring, or element of an array field
+	OpStartsWith Op = "STARTS_WITH"
+	OpBetween    Op = "BETWEEN" // inclusive [low, high] numbers or times
+	OpAgeGT      Op = "AGE_GT"  // time field older than an interval
+	OpAgeLT      Op = "AGE_LT"  // time field newer than an interval
+)
+
+// Symbol returns the comparison operator for ordering ops, or "".
+func (op Op) Symbol() string {
+	switch op {
+	case OpLT:
+		return "<"
+	case OpLTE:
+		return "<="
+	case OpGT:
+		return ">"
+	case OpGTE:
+		return ">="
+	case OpEQ:
+		return "=="
+	case OpNEQ:
+		return "!="
+	}
+	return ""
+}
+
+// maxConditionDepth bounds all/any/not nesting.
+const maxConditionDepth = 16
+
+// Condition is a node of a boolean condition tree. A node is a group (All,
+// Any or Not) or a leaf comparing Field with Value. The zero Condition
+// matches every record.
+//
+// Fields are paths into the record: "fields.priority.name", "labels[0]" or
+// "comments[*].author". A leaf on a wildcard path matches when any element
+// matches; NEQ, NOT_IN and IS_NULL match when no element matches their
+// positive form. A key containing dots that exists verbatim in the record
+// takes precedence over path traversal.
+//
+// Time values may be RFC 3339 timestamps, dates, or expressions relative to
+// the evaluation time such as "now-7d" (units m, h, d, w).
+type Condition struct {
+	All []Condition `json:"all,omitempty"`
+	Any []Condition `json:"any,omitempty"`
+	Not *Condition  `json:"not,omitempty"`
+
+	Field string `json:"field,omitempty"`
+	Op    Op     `json:"op,omitempty"`
+	Value any    `json:"value,omitempty"`
+
+	path []pathSegment
+	re   *regexp.Regexp
+	age  time.Duration
+}
+
+// IsZero reports whether c matches everything.
+func (c Condition) IsZero() bool {
+	return c.All == nil && c.Any == nil && c.Not == nil && c.Field == ""
+}
+
+// ParseCondition parses a condition tree. A JSON array is shorthand for an
+// all-group, which keeps the original flat where lists valid.
+func ParseCondition(v any, path string) (Condition, error) {
+	return parseCondition(v, path, 0)
+}
+
+func parseCondition(v any, path string, depth int) (Condition, error) {
+	if depth > maxConditionDepth {
+		return Condition{}, errorf(path, "conditions nest deeper than %d levels", maxConditionDepth)
+	}
+	switch t := v.(type) {
+	case []any:
+		if len(t) == 0 {
+			return Condition{}, nil
+		}
+		all, err := parseConditionList(t, path, depth)
+		if err != nil {
+			return Condition{}, err
+		}
+		return Condition{All: all}, nil
+	case map[string]any:
+	case nil:
+		return Condition{}, errorf(path, "condition is required")
+	default:
+		return Condition{}, errorf(path, "condition must be an object or an array")
+	}
+
+	m := v.(map[string]any)
+	var kinds []string
+	for _, k := range []string{"all", "any", "not", "field"} {
+		if _, ok := m[k]; ok {
+			kinds = append(kinds, k)
+		}
+	}
+	if len(kinds) != 1 {
+		return Condition{}, errorf(path, "condition must have exactly one of all, any, not or field")
+	}
+	switch kinds[0] {
+	case "all", "any":
+		arr, ok := m[kinds[0]].([]any)
+		if !ok || len(arr) == 0 {
+			return Condition{}, errorf(join(path, kinds[0]), "must be a non-empty array of conditions")
+		}
+		list, err := parseConditionList(arr, join(path, kinds[0]), depth)
+		if err != nil {
+			return Condition{}, err
+		}
+		if kinds[0] == "all" {
+			return Condition{All: list}, nil
+		}
+		return Condition{Any: list}, nil
+	case "not":
+		inner, err := parseCondition(m["not"], join(path, "not"), depth+1)
+		if err != nil {
+			return Condition{}, err
+		}
+		return Condition{Not: &inner}, nil
+	}
+	return parseLeaf(m, path)
+}
+
+func parseConditionList(arr []any, path string, depth int) ([]Condition, error) {
+	out := make([]Condition, 0, len(arr))
+	for i, item := range arr {
+		c, err := parseCondition(item, index(path, i), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func parseLeaf(m map[string]any, path string) (Condition, error) {
+	field, _ := m["field"].(string)
+	if strings.TrimSpace(field) == "" {
+		return Condition{}, errorf(join(path, "field"), "is required")
+	}
+	segments, err := parsePath(field)
+	if err != nil {
+		return Condition{}, errorf(join(path, "field"), "%v", err)
+	}
+	opRaw, _ := m["op"].(string)
+	if strings.TrimSpace(opRaw) == "" {
+		return Condition{}, errorf(join(path, "op"), "is required")
+	}
+	c := Condition{Field: field, Op: Op(opRaw), path: segments}
+	valuePath := join(path, "value")
+	val, hasValue := m["value"]
+	if val == nil {
+		hasValue = false
+	}
+
+	switch c.Op {
+	case OpISNULL, OpISNOTNULL:
+		if hasValue {
+			return Condition{}, errorf(valuePath, "value is not allowed for op %s", opRaw)
+		}
+		return c, nil
+	case OpLT, OpLTE, OpGT, OpGTE, OpEQ, OpNEQ, OpIN, OpNOTIN, OpMatches, OpContains, OpStartsWith, OpBetween, OpAgeGT, OpAgeLT:
+	default:
+		return Condition{}, errorf(join(path, "op"), "unsupported op %s", opRaw)
+	}
+	if !hasValue {
+		return Condition{}, errorf(valuePath, "value is required for op %s", opRaw)
+	}
+
+	switch c.Op {
+	case OpIN, OpNOTIN:
+		arr, ok := val.([]any)
+		if !ok || len(arr) == 0 {
+			return Condition{}, errorf(valuePath, "value for %s must be a non-empty array", opRaw)
+		}
+		for i, item := range arr {
+			if !isPrimitive(item) {
+				return Condition{}, errorf(index(valuePath, i), "value for %s must contain primitives", opRaw)
+			}
+		}
+	case OpMatches:
+		pattern, ok := val.(string)
+		if !ok {
+			return Condition{}, errorf(valuePath, "value for MATCHES must be a regular expression string")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return Condition{}, errorf(valuePath, "invalid regular expression: %v", err)
+		}
+		c.re = re
+	case OpStartsWith:
+		if _, ok := val.(string); !ok {
+			return Condition{}, errorf(valuePath, "value for STARTS_WITH must be a string")
+		}
+	case OpBetween:
+		arr, ok := val.([]any)
+		if !ok || len(arr) != 2 {
+			return Condition{}, errorf(valuePath, "value for BETWEEN must be a [low, high] array")
+		}
+		lo, loNum := toFloatMaybe(arr[0])
+		hi, hiNum := toFloatMaybe(arr[1])
+		switch {
+		case loNum && hiNum:
+			if lo > hi {
+				return Condition{}, errorf(valuePath, "BETWEEN low bound %v exceeds high bound %v", arr[0], arr[1])
+			}
+		case loNum != hiNum:
+			return Condition{}, errorf(valuePath, "BETWEEN bounds must both be numbers or both be times")
+		default:
+			for i, b := range arr {
+				if _, ok := parseTimeExpr(b, time.Now()); !ok {
+					return Condition{}, errorf(index(valuePath, i), "invalid time %v (use RFC 3339 or now-7d)", b)
+				}
+			}
+		}
+	case OpAgeGT, OpAgeLT:
+		d, ok := parseAge(val)
+		if !ok {
+			return Condition{}, errorf(valuePath, "value for %s must be an interval such as {\"unit\":\"days\",\"value\":30} or \"30d\"", opRaw)
+		}
+		c.age = d
+	default:
+		if !isPrimitive(val) {
+			return Condition{}, errorf(valuePath, "value for %s must be a string, number, or boolean", opRaw)
+		}
+	}
+	c.Value = val
+	return c, nil
+}
+
+// Matches evaluates the condition against rec at the current time.
+func (c Condition) Matches(rec map[string]any) bool {
+	return c.MatchesAt(rec, time.Now())
+}
+
+// MatchesAt evaluates the condition with relative times resolved against now.
+func (c Condition) MatchesAt(rec map[string]any, now time.Time) bool {
+	switch {
+	case c.All != nil:
+		for _, child := range c.All {
+			if !child.MatchesAt(rec, now) {
+				return false
+			}
+		}
+		return true
+	case c.Any != nil:
+		for _, child := range c.Any {
+			if child.MatchesAt(rec, now) {
+				return true
+			}
+		}
+		return false
+	case c.Not != nil:
+		return !c.Not.MatchesAt(rec, now)
+	case c.Field == "":
+		return true
+	}
+
+	values := c.resolve(rec)
+	anyMatch := func(op Op) bool {
+		for _, v := range values {
+			if c.leafMatches(op, v, now) {
+				return true
+			}
+		}
+		return false
+	}
+	switch c.Op {
+	case OpNEQ:
+		return !anyMatch(OpEQ)
+	case OpNOTIN:
+		return !anyMatch(OpIN)
+	case OpISNULL:
+		return !anyMatch(OpISNOTNULL)
+	default:
+		return anyMatch(c.Op)
+	}
+}
+
+func (c Condition) leafMatches(op Op, v any, now time.Time) bool {
+	switch op {
+	case OpISNOTNULL:
+		return v != nil
+	case OpLT, OpLTE, OpGT, OpGTE, OpEQ:
+		return compareAt(v, c.Value, op.Symbol(), now)
+	case OpIN:
+		arr, _ := c.Value.([]any)
+		for _, item := range arr {
+			if fmt.Sprint(item) == fmt.Sprint(v) {
+				return true
+			}
+		}
+		return false
+	case OpMatches:
+		re := c.re
+		if re == nil {
+			var err error
+			if re, err = regexp.Compile(fmt.Sprint(c.Value)); err != nil {
+				return false
+			}
+		}
+		return v != nil && re.MatchString(fmt.Sprint(v))
+	case OpContains:
+		switch t := v.(type) {
+		case []any:
+			for _, item := range t {
+				if fmt.Sprint(item) == fmt.Sprint(c.Value) {
+					return true
+				}
+			}
+			return false
+		case string:
+			return strings.Contains(t, fmt.Sprint(c.Value))
+		}
+		return false
+	case OpStartsWith:
+		s, ok := v.(string)
+		return ok && strings.HasPrefix(s, fmt.Sprint(c.Value))
+	case OpBetween:
+		arr, _ := c.Value.([]any)
+		if len(arr) != 2 {
+			return false
+		}
+		return compareAt(v, arr[0], ">=", now) && compareAt(v, arr[1], "<=", now)
+	case OpAgeGT, OpAgeLT:
+		t := ToTime(v)
+		if t.IsZero() {
+			return false
+		}
+		age := c.age
+		if age == 0 {
+			age, _ = parseAge(c.Value)
+		}
+		if op == OpAgeGT {
+			return now.Sub(t) > age
+		}
+		return now.Sub(t) < age
+	}
+	return false
+}
+
+// resolve returns the values at the condition's field path. Paths without
+// wildcards yield exactly one value (nil when missing).
+func (c Condition) resolve(rec map[string]any) []any {
+	if v, ok := rec[c.Field]; ok {
+		return []any{v}
+	}
+	segments := c.path
+	if segments == nil {
+		var err error
+		if segments, err = parsePath(c.Field); err != nil {
+			return []any{nil}
+		}
+	}
+	current := []any{any(rec)}
+	wildcard := false
+	for _, seg := range segments {
+		next := make([]any, 0, len(current))
+		for _, node := range current {
+			switch {
+			case seg.key != "":
+				m, _ := node.(map[string]any)
+				next = append(next, m[seg.key])
+			case seg.wildcard:
+				wildcard = true
+				arr, _ := node.([]any)
+				next = append(next, arr...)
+			default:
+				arr, _ := node.([]any)
+				if seg.index < len(arr) {
+					next = append(next, arr[seg.index])
+				} else {
+					next = append(next, nil)
+				}
+			}
+		}
+		current = next
+	}
+	if !wildcard && len(current) == 0 {
+		return []any{nil}
+	}
+	return current
+}
+
+type pathSegment struct {
+	key      string
+	index    int
+	wildcard bool
+}
+
+// parsePath splits "a.b[0].c[*]" into segments.
+func parsePath(field string) ([]pathSegment, error) {
+	var out []pathSegment
+	for _, part := range strings.Split(field, ".") {
+		name := part
+		var brackets string
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			name, brackets = part[:i], part[i:]
+		}
+		if name == "" {
+			return nil, fmt.Errorf("invalid field path %q", field)
+		}
+		out = append(out, pathSegment{key: name})
+		for brackets != "" {
+			end := strings.IndexByte(brackets, ']')
+			if brackets[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("invalid field path %q", field)
+			}
+			inner := brackets[1:end]
+			brackets = brackets[end+1:]
+			if inner == "*" {
+				out = append(out, pathSegment{wildcard: true})
+				continue
+			}
+			n, err := strconv.Atoi(inner)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid index %q in field path %q", inner, field)
+			}
+			out = append(out, pathSegment{index: n})
+		}
+	}
+	return out, nil
+}
+
+// Compare applies a comparison symbol (<, <=, >, >=, ==, !=) to a and b.
+// Numbers compare numerically and times chronologically; anything else only
+// supports == and != on the string form.
+func Compare(a, b any, symbol string) bool {
+	return compareAt(a, b, symbol, time.Now())
+}
+
+func compareAt(a, b any, symbol string, now time.Time) bool {
+	af, aok := toFloatMaybe(a)
+	bf, bok := toFloatMaybe(b)
+	if aok && bok {
+		return compareOrdered(af, bf, symbol)
+	}
+	if bt, ok := parseTimeExpr(b, now); ok && isTimeExpr(b) {
+		if at := ToTime(a); !at.IsZero() {
+			return compareOrdered(at.UnixNano(), bt.UnixNano(), symbol)
+		}
+	}
+	as, bs := fmt.Sprint(a), fmt.Sprint(b)
+	switch symbol {
+	case "==":
+		return as == bs
+	case "!=":
+		return as != bs
+	}
+	return false
+}
+
+func compareOrdered[T float64 | int64](a, b T, symbol string) bool {
+	switch symbol {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	}
+	return false
+}
+
+// isTimeExpr reports whether a condition value should compare as a time:
+// relative expressions always do, and literal timestamps do when they parse.
+func isTimeExpr(v any) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimSpace(s), "now") || !ToTime(s).IsZero()
+}
+
+// parseTimeExpr resolves "now", "now-7d", "now+12h" or a literal timestamp.
+func parseTimeExpr(v any, now time.Time) (time.Time, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	s = strings.TrimSpace(s)
+	if rest, ok := strings.CutPrefix(s, "now"); ok {
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			return now, true
+		}
+		sign := rest[0]
+		if sign != '-' && sign != '+' {
+			return time.Time{}, false
+		}
+		d, ok := parseDuration(strings.TrimSpace(rest[1:]))
+		if !ok {
+			return time.Time{}, false
+		}
+		if sign == '-' {
+			d = -d
+		}
+		return now.Add(d), true
+	}
+	t := ToTime(s)
+	return t, !t.IsZero()
+}
+
+// parseDuration accepts "30d", "2w", "12h", "90m" and Go durations.
+func parseDuration(s string) (time.Duration, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+	unit := map[byte]time.Duration{'m': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}[s[len(s)-1]]
+	if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && unit > 0 && n >= 0 {
+		return time.Duration(n) * unit, true
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil && d >= 0
+}
+
+func parseAge(v any) (time.Duration, bool) {
+	if s, ok := v.(string); ok {
+		d, ok := parseDuration(strings.TrimSpace(s))
+		return d, ok && d > 0
+	}
+	if iv, ok := parseIntervalCfg(v); ok {
+		return iv.Duration(), true
+	}
+	return 0, false
+}
+
+// ToTime converts time.Time, RFC 3339 strings and dates to a time, or the
+// zero time.
+func ToTime(v any) time.Time {
+	switch t := v.(type) {
+	case time.Time:
+		return t
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func toFloatMaybe(v any) (float64, bool) {
+	switch t := v.(type) {
+	case float32:
+		return float64(t), true
+	case float64:
+		return t, true
+	case int:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	}
+	return 0, false
+}
@@ -0,0 +1,334 @@
+// Package signalspec parses and validates signal definitionSpec documents.
+// The signal store validates specs on UpsertDefinition and the brain-core
+// signal engine evaluates the parsed form, so both agree on the DSL.
+package signalspec
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type DefinitionType string
+
+const (
+	TypeWorkStale        DefinitionType = "cdm.work.stale_item"
+	TypeDocOrphan        DefinitionType = "cdm.doc.orphan"
+	TypeGenericFilter    DefinitionType = "cdm.generic.filter"
+	TypeGenericAggregate DefinitionType = "cdm.generic.aggregate"
+)
+
+// Error reports an invalid spec with the path of the offending element,
+// e.g. "config.where.any[1].value".
+type Error struct {
+	Path string
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return e.Msg
+	}
+	return e.Path + ": " + e.Msg
+}
+
+func errorf(path, format string, args ...any) *Error {
+	return &Error{Path: path, Msg: fmt.Sprintf(format, args...)}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func index(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+type IntervalUnit string
+
+const (
+	IntervalDays  IntervalUnit = "days"
+	IntervalHours IntervalUnit = "hours"
+)
+
+type IntervalConfig struct {
+	Unit  IntervalUnit `json:"unit"`
+	Value int          `json:"value"`
+}
+
+// Duration converts the interval to a time.Duration.
+func (iv IntervalConfig) Duration() time.Duration {
+	switch iv.Unit {
+	case IntervalHours:
+		return time.Duration(iv.Value) * time.Hour
+	case IntervalDays:
+		return time.Duration(iv.Value) * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+type WorkStaleConfig struct {
+	CdmModelID      string           `json:"cdmModelId"`
+	MaxAge          IntervalConfig   `json:"maxAge"`
+	StatusInclude   []string         `json:"statusInclude,omitempty"`
+	StatusExclude   []string         `json:"statusExclude,omitempty"`
+	ProjectInclude  []string         `json:"projectInclude,omitempty"`
+	ProjectExclude  []string         `json:"projectExclude,omitempty"`
+	SeverityMapping *SeverityMapping `json:"severityMapping,omitempty"`
+}
+
+type SeverityMapping struct {
+	WarnAfter  *IntervalConfig `json:"warnAfter,omitempty"`
+	ErrorAfter *IntervalConfig `json:"errorAfter,omitempty"`
+}
+
+type DocOrphanConfig struct {
+	CdmModelID         string         `json:"cdmModelId"`
+	MinAge             IntervalConfig `json:"minAge"`
+	MinViewCount       *int           `json:"minViewCount,omitempty"`
+	RequireProjectLink *bool          `json:"requireProjectLink,omitempty"`
+	SpaceInclude       []string       `json:"spaceInclude,omitempty"`
+	SpaceExclude       []string       `json:"spaceExclude,omitempty"`
+}
+
+type SeverityRule struct {
+	When     Condition `json:"when"`
+	Severity string    `json:"severity"`
+}
+
+type GenericFilterConfig struct {
+	CdmModelID      string         `json:"cdmModelId"`
+	Where           Condition      `json:"where"`
+	SeverityRules   []SeverityRule `json:"severityRules,omitempty"`
+	SummaryTemplate string         `json:"summaryTemplate"`
+}
+
+// Spec is a parsed definitionSpec. Config holds the config struct for Type.
+type Spec struct {
+	Version int            `json:"version"`
+	Type    DefinitionType `json:"type"`
+	Config  any            `json:"config"`
+}
+
+// Parse validates a definitionSpec (as decoded from JSON or a protobuf
+// Struct) and returns its typed form. Errors are *Error values.
+func Parse(input any) (Spec, error) {
+	m, ok := input.(map[string]any)
+	if !ok {
+		return Spec{}, errorf("", "definitionSpec must be an object")
+	}
+	version, _ := m["version"].(int)
+	if v, ok := m["version"].(float64); ok {
+		version = int(v)
+	}
+	if version != 1 {
+		return Spec{}, errorf("version", "unsupported version: %v", m["version"])
+	}
+	t, _ := m["type"].(string)
+	if t == "" {
+		return Spec{}, errorf("type", "is required")
+	}
+	cfgRaw, ok := m["config"].(map[string]any)
+	if !ok {
+		return Spec{}, errorf("config", "must be an object")
+	}
+	var (
+		cfg any
+		err error
+	)
+	switch DefinitionType(t) {
+	case TypeWorkStale:
+		cfg, err = parseWorkStaleConfig(cfgRaw, "config")
+	case TypeDocOrphan:
+		cfg, err = parseDocOrphanConfig(cfgRaw, "config")
+	case TypeGenericFilter:
+		cfg, err = parseGenericFilterConfig(cfgRaw, "config")
+	case TypeGenericAggregate:
+		cfg, err = parseAggregateConfig(cfgRaw, "config")
+	default:
+		return Spec{}, errorf("type", "unsupported spec type %s", t)
+	}
+	if err != nil {
+		return Spec{}, err
+	}
+	return Spec{Version: 1, Type: DefinitionType(t), Config: cfg}, nil
+}
+
+func parseWorkStaleConfig(cfg map[string]any, path string) (WorkStaleConfig, error) {
+	if cfg["cdmModelId"] != "cdm.work.item" {
+		return WorkStaleConfig{}, errorf(join(path, "cdmModelId"), "must be cdm.work.item")
+	}
+	maxAge, ok := parseIntervalCfg(cfg["maxAge"])
+	if !ok {
+		return WorkStaleConfig{}, errorf(join(path, "maxAge"), "is required (days|hours)")
+	}
+	var sevMap *SeverityMapping
+	if m, ok := cfg["severityMapping"].(map[string]any); ok {
+		sevMap = &SeverityMapping{}
+		if warn, ok := parseIntervalCfg(m["warnAfter"]); ok {
+			sevMap.WarnAfter = &warn
+		}
+		if errAfter, ok := parseIntervalCfg(m["errorAfter"]); ok {
+			sevMap.ErrorAfter = &errAfter
+		}
+		if sevMap.WarnAfter == nil && sevMap.ErrorAfter == nil {
+			sevMap = nil
+		}
+	}
+	return WorkStaleConfig{
+		CdmModelID:      "cdm.work.item",
+		MaxAge:          maxAge,
+		StatusInclude:   strSlice(cfg["statusInclude"]),
+		StatusExclude:   strSlice(cfg["statusExclude"]),
+		ProjectInclude:  strSlice(cfg["projectInclude"]),
+		ProjectExclude:  strSlice(cfg["projectExclude"]),
+		SeverityMapping: sevMap,
+	}, nil
+}
+
+func parseDocOrphanConfig(cfg map[string]any, path string) (DocOrphanConfig, error) {
+	if cfg["cdmModelId"] != "cdm.doc.item" {
+		return DocOrphanConfig{}, errorf(join(path, "cdmModelId"), "must be cdm.doc.item")
+	}
+	minAge, ok := parseIntervalCfg(cfg["minAge"])
+	if !ok {
+		return DocOrphanConfig{}, errorf(join(path, "minAge"), "is required (days|hours)")
+	}
+	return DocOrphanConfig{
+		CdmModelID:         "cdm.doc.item",
+		MinAge:             minAge,
+		MinViewCount:       parseInt(cfg["minViewCount"]),
+		RequireProjectLink: parseBoolPtr(cfg["requireProjectLink"]),
+		SpaceInclude:       strSlice(cfg["spaceInclude"]),
+		SpaceExclude:       strSlice(cfg["spaceExclude"]),
+	}, nil
+}
+
+func parseGenericFilterConfig(cfg map[string]any, path string) (GenericFilterConfig, error) {
+	model, _ := cfg["cdmModelId"].(string)
+	if model != "cdm.work.item" && model != "cdm.doc.item" {
+		return GenericFilterConfig{}, errorf(join(path, "cdmModelId"), "must be cdm.work.item or cdm.doc.item")
+	}
+	where, err := ParseCondition(cfg["where"], join(path, "where"))
+	if err != nil {
+		return GenericFilterConfig{}, err
+	}
+	severityRules, err := parseSeverityRules(cfg["severityRules"], join(path, "severityRules"))
+	if err != nil {
+		return GenericFilterConfig{}, err
+	}
+	summaryTemplate, _ := cfg["summaryTemplate"].(string)
+	summaryTemplate = strings.TrimSpace(summaryTemplate)
+	if summaryTemplate == "" {
+		return GenericFilterConfig{}, errorf(join(path, "summaryTemplate"), "is required")
+	}
+	return GenericFilterConfig{
+		CdmModelID:      model,
+		Where:           where,
+		SeverityRules:   severityRules,
+		SummaryTemplate: summaryTemplate,
+	}, nil
+}
+
+func parseSeverityRules(v any, path string) ([]SeverityRule, error) {
+	if v == nil {
+		return nil, nil
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, errorf(path, "must be an array")
+	}
+	var out []SeverityRule
+	for i, item := range arr {
+		itemPath := index(path, i)
+		m, ok := item.(map[string]any)
+		if !ok {
+			return nil, errorf(itemPath, "must be an object")
+		}
+		when, err := ParseCondition(m["when"], join(itemPath, "when"))
+		if err != nil {
+			return nil, err
+		}
+		sev, _ := m["severity"].(string)
+		if strings.TrimSpace(sev) == "" {
+			return nil, errorf(join(itemPath, "severity"), "is required")
+		}
+		out = append(out, SeverityRule{When: when, Severity: sev})
+	}
+	return out, nil
+}
+
+func parseIntervalCfg(v any) (IntervalConfig, bool) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return IntervalConfig{}, false
+	}
+	unit, _ := m["unit"].(string)
+	val := parseIntVal(m["value"])
+	if (unit == string(IntervalDays) || unit == string(IntervalHours)) && val > 0 {
+		return IntervalConfig{Unit: IntervalUnit(unit), Value: val}, true
+	}
+	return IntervalConfig{}, false
+}
+
+func parseInt(v any) *int {
+	val := parseIntVal(v)
+	if val < 0 {
+		return nil
+	}
+	return &val
+}
+
+func parseIntVal(v any) int {
+	switch t := v.(type) {
+	case int:
+		return t
+	case int32:
+		return int(t)
+	case int64:
+		return int(t)
+	case float64:
+		return int(t)
+	case float32:
+		return int(t)
+	case string:
+		var holder int
+		if _, err := fmt.Sscanf(t, "%d", &holder); err == nil {
+			return holder
+		}
+	}
+	return -1
+}
+
+func parseBoolPtr(v any) *bool {
+	if b, ok := v.(bool); ok {
+		return &b
+	}
+	return nil
+}
+
+func strSlice(v any) []string {
+	arr, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, item := range arr {
+		if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+			out = append(out, strings.TrimSpace(s))
+		}
+	}
+	return out
+}
+
+func isPrimitive(v any) bool {
+	switch v.(type) {
+	case string, float64, float32, int, int32, int64, bool:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package signalspec
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func decode(t *testing.T, src string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestConditionTree(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	cond, err := ParseCondition(decode(t, `{
+		"all": [
+			{"any": [
+				{"field": "priority", "op": "IN", "value": ["P0", "P1"]},
+				{"field": "labels", "op": "CONTAINS", "value": "escalated"}
+			]},
+			{"not": {"field": "status", "op": "MATCHES", "value": "(?i)^(done|closed)$"}},
+			{"field": "comments[*].author", "op": "STARTS_WITH", "value": "bot-"},
+			{"field": "fields.estimate", "op": "BETWEEN", "value": [1, 8]},
+			{"field": "updatedAt", "op": "AGE_GT", "value": {"unit": "days", "value": 7}},
+			{"field": "createdAt", "op": "GTE", "value": "now-90d"}
+		]
+	}`), "where")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := func(mut func(map[string]any)) map[string]any {
+		r := decode(t, `{
+			"priority": "P3",
+			"labels": ["escalated"],
+			"status": "In Progress",
+			"comments": [{"author": "alice"}, {"author": "bot-ci"}],
+			"fields": {"estimate": 5},
+			"updatedAt": "2024-05-01T00:00:00Z",
+			"createdAt": "2024-04-01T00:00:00Z"
+		}`).(map[string]any)
+		if mut != nil {
+			mut(r)
+		}
+		return r
+	}
+	if !cond.MatchesAt(rec(nil), now) {
+		t.Fatal("expected record to match")
+	}
+	misses := map[string]func(map[string]any){
+		"any branch":   func(r map[string]any) { r["labels"] = []any{} },
+		"not regex":    func(r map[string]any) { r["status"] = "DONE" },
+		"wildcard":     func(r map[string]any) { r["comments"] = []any{map[string]any{"author": "alice"}} },
+		"between":      func(r map[string]any) { r["fields"] = map[string]any{"estimate": 13.0} },
+		"age":          func(r map[string]any) { r["updatedAt"] = "2024-05-14T00:00:00Z" },
+		"now minus":    func(r map[string]any) { r["createdAt"] = "2023-01-01T00:00:00Z" },
+		"missing path": func(r map[string]any) { delete(r, "fields") },
+	}
+	for name, mut := range misses {
+		if cond.MatchesAt(rec(mut), now) {
+			t.Errorf("%s: expected no match", name)
+		}
+	}
+
+	// A flat list still means AND, and a verbatim dotted key wins over traversal.
+	flat, err := ParseCondition(decode(t, `[{"field": "a.b", "op": "EQ", "value": 1}, {"field": "c", "op": "IS_NULL"}]`), "where")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !flat.MatchesAt(map[string]any{"a.b": 1.0, "a": map[string]any{"b": 2.0}}, now) {
+		t.Fatal("expected verbatim key to match")
+	}
+	if (Condition{}).MatchesAt(nil, now) != true {
+		t.Fatal("zero condition must match everything")
+	}
+}
+
+func TestParseErrorPaths(t *testing.T) {
+	cases := map[string]struct {
+		spec string
+		path string
+	}{
+		"bad op": {
+			`{"version":1,"type":"cdm.generic.filter","config":{"cdmModelId":"cdm.work.item","summaryTemplate":"x",
+				"where":{"all":[{"field":"a","op":"EQ","value":1},{"any":[{"field":"b","op":"EQ","value":1},{"field":"c","op":"LIKE","value":"x"}]}]}}}`,
+			"config.where.all[1].any[1].op",
+		},
+		"bad regex": {
+			`{"version":1,"type":"cdm.generic.filter","config":{"cdmModelId":"cdm.doc.item","summaryTemplate":"x",
+				"where":[{"field":"title","op":"MATCHES","value":"("}]}}`,
+			"config.where[0].value",
+		},
+		"mixed node": {
+			`{"version":1,"type":"cdm.generic.filter","config":{"cdmModelId":"cdm.doc.item","summaryTemplate":"x",
+				"where":{"not":{"field":"a","op":"IS_NULL","all":[]}}}}`,
+			"config.where.not",
+		},
+		"bad path": {
+			`{"version":1,"type":"cdm.generic.filter","config":{"cdmModelId":"cdm.doc.item","summaryTemplate":"x",
+				"where":[{"field":"items[x]","op":"IS_NULL"}]}}`,
+			"config.where[0].field",
+		},
+		"between bounds": {
+			`{"version":1,"type":"cdm.generic.filter","config":{"cdmModelId":"cdm.doc.item","summaryTemplate":"x",
+				"where":[{"field":"n","op":"BETWEEN","value":[1,"now"]}]}}`,
+			"config.where[0].value",
+		},
+		"age interval": {
+			`{"version":1,"type":"cdm.generic.filter","config":{"cdmModelId":"cdm.doc.item","summaryTemplate":"x",
+				"where":[{"field":"t","op":"AGE_GT","value":"soon"}]}}`,
+			"config.where[0].value",
+		},
+		"severity rule": {
+			`{"version":1,"type":"cdm.generic.filter","config":{"cdmModelId":"cdm.doc.item","summaryTemplate":"x","where":[],
+				"severityRules":[{"when":[{"field":"a","op":"EQ","value":1}],"severity":"HIGH"},{"when":[{"field":"a","op":"EQ"}],"severity":"LOW"}]}}`,
+			"config.severityRules[1].when[0].value",
+		},
+		"aggregate ratio": {
+			`{"version":1,"type":"cdm.generic.aggregate","config":{"groupBy":["p"],"aggregates":[{"name":"n","fn":"count"}],
+				"condition":{"aggregate":"n","op":"GT","value":1,"ratio":true}}}`,
+			"config.condition.ratio",
+		},
+		"aggregate fn": {
+			`{"version":1,"type":"cdm.generic.aggregate","config":{"groupBy":["p"],"aggregates":[{"name":"n","fn":"count"},{"name":"s","fn":"sum"}],
+				"condition":{"aggregate":"n","op":"GT","value":1}}}`,
+			"config.aggregates[1].field",
+		},
+		"aggregate window": {
+			`{"version":1,"type":"cdm.generic.aggregate","config":{"groupBy":["p"],"aggregates":[{"name":"n","fn":"count"}],
+				"window":{"kind":"hopping","size":{"unit":"days","value":1}},"condition":{"aggregate":"n","op":"GT","value":1}}}`,
+			"config.window.kind",
+		},
+	}
+	for name, tc := range cases {
+		_, err := Parse(decode(t, tc.spec))
+		var specErr *Error
+		if !errors.As(err, &specErr) {
+			t.Errorf("%s: expected *Error, got %v", name, err)
+			continue
+		}
+		if specErr.Path != tc.path {
+			t.Errorf("%s: path = %q, want %q (%v)", name, specErr.Path, tc.path, err)
+		}
+	}
+
+	if _, err := Parse(decode(t, `{"version":1,"type":"cdm.generic.aggregate","config":{"groupBy":["p"],
+		"aggregates":[{"name":"n","fn":"count"}],"window":{"size":{"unit":"days","value":7}},
+		"condition":{"aggregate":"n","op":"GTE","value":3,"ratio":true}}}`)); err != nil {
+		t.Fatalf("valid aggregate rejected: %v", err)
+	}
+}
@@ -0,0 +1,300 @@
+package signalstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nucleus/store-core/pkg/signalnotify"
+)
+
+// Instance lifecycle states.
+const (
+	StatusOpen         = "OPEN"
+	StatusAcknowledged = "ACKNOWLEDGED"
+	StatusSnoozed      = "SNOOZED"
+	StatusResolved     = "RESOLVED"
+	StatusReopened     = "REOPENED"
+	StatusSuppressed   = "SUPPRESSED"
+)
+
+// ActorSystem attributes transitions made by signal runs rather than users.
+const ActorSystem = "system"
+
+var (
+	// ErrInstanceNotFound is returned when a transition targets a missing instance.
+	ErrInstanceNotFound = errors.New("signalstore: instance not found")
+	// ErrInvalidTransition is returned when the lifecycle does not allow a change.
+	ErrInvalidTransition = errors.New("signalstore: invalid status transition")
+)
+
+// allowedFrom lists, for each target status, the statuses a manual transition
+// may start from.
+var allowedFrom = map[string][]string{
+	StatusOpen:         {StatusAcknowledged, StatusSnoozed},
+	StatusAcknowledged: {StatusOpen, StatusReopened, StatusSnoozed},
+	StatusSnoozed:      {StatusOpen, StatusReopened, StatusAcknowledged, StatusSnoozed},
+	StatusResolved:     {StatusOpen, StatusReopened, StatusAcknowledged, StatusSnoozed, StatusSuppressed},
+	StatusReopened:     {StatusResolved, StatusSuppressed},
+	StatusSuppressed:   {StatusOpen, StatusReopened, StatusAcknowledged, StatusSnoozed, StatusResolved},
+}
+
+// Transition is a requested lifecycle change. The instance is addressed by
+// InstanceID, or by (DefinitionID, EntityRef).
+type Transition struct {
+	InstanceID   string
+	DefinitionID string
+	EntityRef    string
+	Status       string
+	SnoozedUntil *time.Time
+	Actor        string
+	Reason       string
+	SourceRunID  string
+}
+
+// HistoryEntry is one row of the append-only instance history.
+type HistoryEntry struct {
+	ID           string
+	InstanceID   string
+	DefinitionID string
+	EntityRef    string
+	FromStatus   string
+	ToStatus     string
+	Actor        string
+	Reason       string
+	SourceRunID  string
+	SnoozedUntil *time.Time
+	CreatedAt    time.Time
+}
+
+// HistoryQuery filters ListInstanceHistory; at least one of InstanceID or
+// DefinitionID is required.
+type HistoryQuery struct {
+	InstanceID   string
+	DefinitionID string
+	EntityRef    string
+	Since        time.Time
+	Limit        int
+}
+
+// detectedStatus is the status an instance takes when a run matches it again.
+// Manual states survive detection, except that resolved instances reopen and
+// expired snoozes wake up.
+func detectedStatus(current string, snoozedUntil *time.Time, now time.Time) string {
+	switch current {
+	case "":
+		return StatusOpen
+	case StatusResolved:
+		return StatusReopened
+	case StatusSnoozed:
+		if snoozedUntil == nil || !now.Before(*snoozedUntil) {
+			return StatusOpen
+		}
+	}
+	return current
+}
+
+// checkTransition validates a manual transition. It reports false when the
+// instance is already in the requested state and nothing needs recording.
+func checkTransition(current string, t Transition, now time.Time) (bool, error) {
+	from, ok := allowedFrom[t.Status]
+	if !ok {
+		return false, fmt.Errorf("%w: unknown status %q", ErrInvalidTransition, t.Status)
+	}
+	if t.Status == StatusSnoozed {
+		if t.SnoozedUntil == nil || !t.SnoozedUntil.After(now) {
+			return false, fmt.Errorf("%w: snoozedUntil must be in the future", ErrInvalidTransition)
+		}
+		if current == StatusSnoozed {
+			return true, nil // extends or shortens the snooze
+		}
+	}
+	if current == t.Status {
+		return false, nil
+	}
+	for _, s := range from {
+		if s == current {
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, current, t.Status)
+}
+
+// TransitionInstance applies a manual (or run-driven) lifecycle change and
+// appends it to the instance history. The returned bool is false when the
+// instance already had the requested status.
+func (s *Store) TransitionInstance(ctx context.Context, t Transition) (Instance, bool, error) {
+	t.Status = strings.ToUpper(strings.TrimSpace(t.Status))
+	if t.InstanceID == "" && (t.DefinitionID == "" || t.EntityRef == "") {
+		return Instance{}, false, fmt.Errorf("instanceId or definitionId and entityRef are required")
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Instance{}, false, err
+	}
+	defer tx.Rollback()
+
+	where, args := "definition_id=$1 AND entity_ref=$2", []any{t.DefinitionID, t.EntityRef}
+	if t.InstanceID != "" {
+		where, args = "id=$1", []any{t.InstanceID}
+	}
+	inst, err := scanInstance(tx.QueryRowContext(ctx, instanceColumns+` FROM metadata.signal_instances WHERE `+where+` FOR UPDATE`, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Instance{}, false, ErrInstanceNotFound
+	}
+	if err != nil {
+		return Instance{}, false, err
+	}
+	changed, err := checkTransition(inst.Status, t, time.Now())
+	if err != nil || !changed {
+		return inst, false, err
+	}
+
+	from := inst.Status
+	var snoozedUntil any
+	if t.Status == StatusSnoozed {
+		snoozedUntil = *t.SnoozedUntil
+	}
+	// The acknowledger is kept through snoozes and resolution and only
+	// cleared when the instance reopens.
+	var acknowledgedBy any
+	if t.Status == StatusAcknowledged {
+		acknowledgedBy = nullString(t.Actor)
+	}
+	if err := scanInto(&inst, tx.QueryRowContext(ctx, `
+UPDATE metadata.signal_instances SET
+  status=$2,
+  snoozed_until=$3,
+  acknowledged_by=CASE WHEN $2='ACKNOWLEDGED' THEN $4 WHEN $2='REOPENED' THEN NULL ELSE acknowledged_by END,
+  resolved_at=CASE WHEN $2='RESOLVED' THEN now() ELSE NULL END,
+  updated_at=now()
+WHERE id=$1
+RETURNING `+strings.TrimPrefix(instanceColumns, "SELECT "), inst.ID, t.Status, snoozedUntil, acknowledgedBy)); err != nil {
+		return Instance{}, false, err
+	}
+	historyID, err := appendHistory(ctx, tx, inst, from, t)
+	if err != nil {
+		return Instance{}, false, err
+	}
+	kind := signalnotify.EventKind(strings.ToLower(inst.Status))
+	if inst.Status == StatusReopened {
+		kind = signalnotify.EventReopened
+	}
+	if err := s.notify(ctx, tx, inst, historyID, kind, from, ""); err != nil {
+		return Instance{}, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return Instance{}, false, err
+	}
+	return inst, true, nil
+}
+
+// ListInstanceHistory returns transitions newest first.
+func (s *Store) ListInstanceHistory(ctx context.Context, q HistoryQuery) ([]HistoryEntry, error) {
+	if q.InstanceID == "" && q.DefinitionID == "" {
+		return nil, fmt.Errorf("instanceId or definitionId is required")
+	}
+	conds := []string{}
+	args := []any{}
+	argIdx := 1
+	add := func(cond string, val any) {
+		conds = append(conds, fmt.Sprintf(cond, argIdx))
+		args = append(args, val)
+		argIdx++
+	}
+	if q.InstanceID != "" {
+		add("instance_id = $%d", q.InstanceID)
+	}
+	if q.DefinitionID != "" {
+		add("definition_id = $%d", q.DefinitionID)
+	}
+	if q.EntityRef != "" {
+		add("entity_ref = $%d", q.EntityRef)
+	}
+	if !q.Since.IsZero() {
+		add("created_at >= $%d", q.Since)
+	}
+	limit := q.Limit
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+SELECT id, instance_id, definition_id, entity_ref, COALESCE(from_status, ''), to_status, COALESCE(actor, ''), COALESCE(reason, ''), COALESCE(source_run_id, ''), snoozed_until, created_at
+FROM metadata.signal_instance_history
+WHERE %s
+ORDER BY created_at DESC, seq DESC
+LIMIT %d`, strings.Join(conds, " AND "), limit), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []HistoryEntry
+	for rows.Next() {
+		var h HistoryEntry
+		var snoozed sql.NullTime
+		if err := rows.Scan(&h.ID, &h.InstanceID, &h.DefinitionID, &h.EntityRef, &h.FromStatus, &h.ToStatus, &h.Actor, &h.Reason, &h.SourceRunID, &snoozed, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		h.SnoozedUntil = timePtr(snoozed)
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// appendHistory records a status change (or a severity escalation, with from
+// equal to the current status) and returns the entry id. from is empty for
+// new instances.
+func appendHistory(ctx context.Context, tx *sql.Tx, inst Instance, from string, t Transition) (string, error) {
+	var snoozedUntil any
+	if inst.SnoozedUntil != nil {
+		snoozedUntil = *inst.SnoozedUntil
+	}
+	id := uuid.New().String()
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO metadata.signal_instance_history
+  (id, instance_id, definition_id, entity_ref, from_status, to_status, actor, reason, source_run_id, snoozed_until, created_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,now())`,
+		id, inst.ID, inst.DefinitionID, inst.EntityRef, nullString(from), inst.Status,
+		nullString(t.Actor), nullString(t.Reason), nullString(t.SourceRunID), snoozedUntil,
+	)
+	return id, err
+}
+
+// notify queues a notification for the history entry in the same transaction.
+func (s *Store) notify(ctx context.Context, tx *sql.Tx, inst Instance, historyID string, kind signalnotify.EventKind, from, prevSeverity string) error {
+	if s.outbox == nil {
+		return nil
+	}
+	ev := signalnotify.Event{
+		ID:               historyID,
+		Kind:             kind,
+		InstanceID:       inst.ID,
+		DefinitionID:     inst.DefinitionID,
+		EntityRef:        inst.EntityRef,
+		EntityKind:       inst.EntityKind,
+		Status:           inst.Status,
+		PreviousStatus:   from,
+		Severity:         inst.Severity,
+		PreviousSeverity: prevSeverity,
+		Summary:          inst.Summary,
+		TenantID:         inst.TenantID,
+		ProjectID:        inst.ProjectID,
+		SourceRunID:      inst.SourceRunID,
+		OccurredAt:       time.Now().UTC(),
+	}
+	_ = tx.QueryRowContext(ctx, `SELECT slug, title FROM metadata.signal_definitions WHERE id=$1`, inst.DefinitionID).
+		Scan(&ev.DefinitionSlug, &ev.DefinitionTitle)
+	return s.outbox.Enqueue(ctx, tx, ev)
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
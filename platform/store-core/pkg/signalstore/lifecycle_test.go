@@ -0,0 +1,61 @@
+package signalstore
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDetectedStatus(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+	cases := []struct {
+		current string
+		until   *time.Time
+		want    string
+	}{
+		{"", nil, StatusOpen},
+		{StatusOpen, nil, StatusOpen},
+		{StatusResolved, nil, StatusReopened},
+		{StatusReopened, nil, StatusReopened},
+		{StatusAcknowledged, nil, StatusAcknowledged},
+		{StatusSuppressed, nil, StatusSuppressed},
+		{StatusSnoozed, &later, StatusSnoozed},
+		{StatusSnoozed, &earlier, StatusOpen},
+		{StatusSnoozed, nil, StatusOpen},
+	}
+	for _, tc := range cases {
+		if got := detectedStatus(tc.current, tc.until, now); got != tc.want {
+			t.Errorf("detectedStatus(%q) = %q, want %q", tc.current, got, tc.want)
+		}
+	}
+}
+
+func TestCheckTransition(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+	cases := []struct {
+		current string
+		t       Transition
+		changed bool
+		invalid bool
+	}{
+		{StatusOpen, Transition{Status: StatusAcknowledged}, true, false},
+		{StatusOpen, Transition{Status: StatusSnoozed, SnoozedUntil: &later}, true, false},
+		{StatusOpen, Transition{Status: StatusSnoozed}, false, true},
+		{StatusOpen, Transition{Status: StatusSnoozed, SnoozedUntil: &earlier}, false, true},
+		{StatusSnoozed, Transition{Status: StatusSnoozed, SnoozedUntil: &later}, true, false},
+		{StatusAcknowledged, Transition{Status: StatusResolved}, true, false},
+		{StatusResolved, Transition{Status: StatusResolved}, false, false},
+		{StatusResolved, Transition{Status: StatusReopened}, true, false},
+		{StatusResolved, Transition{Status: StatusAcknowledged}, false, true},
+		{StatusOpen, Transition{Status: StatusReopened}, false, true},
+		{StatusOpen, Transition{Status: "DONE"}, false, true},
+	}
+	for _, tc := range cases {
+		changed, err := checkTransition(tc.current, tc.t, now)
+		if changed != tc.changed || errors.Is(err, ErrInvalidTransition) != tc.invalid {
+			t.Errorf("%s -> %s: changed=%v err=%v", tc.current, tc.t.Status, changed, err)
+		}
+	}
+}
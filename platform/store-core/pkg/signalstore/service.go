@@ -2,11 +2,15 @@ package signalstore
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	signalpb "github.com/nucleus/store-core/gen/go/signalpb"
+	"github.com/nucleus/store-core/pkg/signalspec"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // GRPCServer implements SignalService backed by Store.
@@ -23,6 +27,13 @@ func (s *GRPCServer) UpsertDefinition(ctx context.Context, req *signalpb.UpsertD
 	if req.GetDefinition() == nil {
 		return nil, status.Error(codes.InvalidArgument, "definition is required")
 	}
+	// Reject specs the signal engine could not evaluate, with the path of the
+	// offending element (e.g. config.where.any[1].op).
+	if spec := req.GetDefinition().GetDefinitionSpec(); len(spec.GetFields()) > 0 {
+		if _, err := signalspec.Parse(spec.AsMap()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid definitionSpec: %v", err)
+		}
+	}
 	def := fromProtoDef(req.GetDefinition())
 	id, err := s.store.UpsertDefinition(ctx, def)
 	if err != nil {
@@ -74,11 +85,84 @@ func (s *GRPCServer) UpdateInstanceStatus(ctx context.Context, req *signalpb.Upd
 		return nil, status.Error(codes.InvalidArgument, "definition_id and entity_ref are required")
 	}
 	if err := s.store.UpdateInstanceStatus(ctx, req.GetDefinitionId(), req.GetEntityRef(), req.GetStatus()); err != nil {
-		return nil, status.Errorf(codes.Internal, "update instance: %v", err)
+		return nil, transitionError(err)
 	}
 	return &signalpb.UpdateInstanceStatusResponse{}, nil
 }
 
+func (s *GRPCServer) TransitionInstance(ctx context.Context, req *signalpb.TransitionInstanceRequest) (*signalpb.TransitionInstanceResponse, error) {
+	if req.GetInstanceId() == "" && (req.GetDefinitionId() == "" || req.GetEntityRef() == "") {
+		return nil, status.Error(codes.InvalidArgument, "instance_id or definition_id and entity_ref are required")
+	}
+	if req.GetStatus() == "" {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+	t := Transition{
+		InstanceID:   req.GetInstanceId(),
+		DefinitionID: req.GetDefinitionId(),
+		EntityRef:    req.GetEntityRef(),
+		Status:       req.GetStatus(),
+		Actor:        req.GetActor(),
+		Reason:       req.GetReason(),
+		SourceRunID:  req.GetSourceRunId(),
+	}
+	if req.GetSnoozedUntil() != nil {
+		until := req.GetSnoozedUntil().AsTime()
+		t.SnoozedUntil = &until
+	}
+	inst, changed, err := s.store.TransitionInstance(ctx, t)
+	if err != nil {
+		return nil, transitionError(err)
+	}
+	return &signalpb.TransitionInstanceResponse{Instance: toProtoInst(inst), Changed: changed}, nil
+}
+
+func (s *GRPCServer) ListInstanceHistory(ctx context.Context, req *signalpb.ListInstanceHistoryRequest) (*signalpb.ListInstanceHistoryResponse, error) {
+	if req.GetInstanceId() == "" && req.GetDefinitionId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "instance_id or definition_id is required")
+	}
+	q := HistoryQuery{
+		InstanceID:   req.GetInstanceId(),
+		DefinitionID: req.GetDefinitionId(),
+		EntityRef:    req.GetEntityRef(),
+		Limit:        int(req.GetLimit()),
+	}
+	if req.GetSince() != nil {
+		q.Since = req.GetSince().AsTime()
+	}
+	entries, err := s.store.ListInstanceHistory(ctx, q)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list instance history: %v", err)
+	}
+	resp := &signalpb.ListInstanceHistoryResponse{}
+	for _, h := range entries {
+		resp.Transitions = append(resp.Transitions, &signalpb.InstanceTransition{
+			Id:           h.ID,
+			InstanceId:   h.InstanceID,
+			DefinitionId: h.DefinitionID,
+			EntityRef:    h.EntityRef,
+			FromStatus:   h.FromStatus,
+			ToStatus:     h.ToStatus,
+			Actor:        h.Actor,
+			Reason:       h.Reason,
+			SourceRunId:  h.SourceRunID,
+			SnoozedUntil: toTimestamp(h.SnoozedUntil),
+			CreatedAt:    timestamppb.New(h.CreatedAt),
+		})
+	}
+	return resp, nil
+}
+
+func transitionError(err error) error {
+	switch {
+	case errors.Is(err, ErrInstanceNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Errorf(codes.Internal, "update instance: %v", err)
+}
+
 func fromProtoDef(d *signalpb.Definition) Definition {
 	return Definition{
 		ID:             d.GetId(),
@@ -134,22 +218,41 @@ func fromProtoInst(i *signalpb.Instance) Instance {
 		Summary:      i.GetSummary(),
 		Details:      structToAny(i.GetDetails()),
 		SourceRunID:  i.GetSourceRunId(),
+		TenantID:     i.GetTenantId(),
+		ProjectID:    i.GetProjectId(),
 	}
 }
 
 func toProtoInst(i Instance) *signalpb.Instance {
 	details, _ := structpb.NewStruct(toMap(i.Details))
-	return &signalpb.Instance{
-		Id:           i.ID,
-		DefinitionId: i.DefinitionID,
-		Status:       i.Status,
-		EntityRef:    i.EntityRef,
-		EntityKind:   i.EntityKind,
-		Severity:     i.Severity,
-		Summary:      i.Summary,
-		Details:      details,
-		SourceRunId:  i.SourceRunID,
+	inst := &signalpb.Instance{
+		Id:             i.ID,
+		DefinitionId:   i.DefinitionID,
+		Status:         i.Status,
+		EntityRef:      i.EntityRef,
+		EntityKind:     i.EntityKind,
+		Severity:       i.Severity,
+		Summary:        i.Summary,
+		Details:        details,
+		SourceRunId:    i.SourceRunID,
+		SnoozedUntil:   toTimestamp(i.SnoozedUntil),
+		ResolvedAt:     toTimestamp(i.ResolvedAt),
+		AcknowledgedBy: i.AcknowledgedBy,
+		TenantId:       i.TenantID,
+		ProjectId:      i.ProjectID,
+	}
+	if !i.FirstSeenAt.IsZero() {
+		inst.FirstSeenAt = timestamppb.New(i.FirstSeenAt)
+		inst.LastSeenAt = timestamppb.New(i.LastSeenAt)
+	}
+	return inst
+}
+
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
 	}
+	return timestamppb.New(*t)
 }
 
 func structToAny(s *structpb.Struct) any {
@@ -7,15 +7,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"github.com/nucleus/store-core/pkg/signalnotify"
 )
 
 // Store persists signal definitions and instances.
 type Store struct {
-	db *sql.DB
+	db     *sql.DB
+	outbox *signalnotify.Outbox
 }
 
 // Definition represents a signal definition row.
@@ -40,15 +43,22 @@ type Definition struct {
 
 // Instance represents a signal instance row.
 type Instance struct {
-	ID           string
-	DefinitionID string
-	Status       string
-	EntityRef    string
-	EntityKind   string
-	Severity     string
-	Summary      string
-	Details      any
-	SourceRunID  string
+	ID             string
+	DefinitionID   string
+	Status         string
+	EntityRef      string
+	EntityKind     string
+	Severity       string
+	Summary        string
+	Details        any
+	SourceRunID    string
+	TenantID       string
+	ProjectID      string
+	SnoozedUntil   *time.Time
+	AcknowledgedBy string
+	FirstSeenAt    time.Time
+	LastSeenAt     time.Time
+	ResolvedAt     *time.Time
 }
 
 // NewFromEnv opens a store using METADATA_DATABASE_URL/DATABASE_URL.
@@ -66,7 +76,12 @@ func NewFromEnv() (*Store, error) {
 	}
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(30 * time.Minute)
-	return &Store{db: db}, nil
+	return &Store{db: db, outbox: signalnotify.NewOutbox(db)}, nil
+}
+
+// Outbox returns the notification outbox fed by instance transitions.
+func (s *Store) Outbox() *signalnotify.Outbox {
+	return s.outbox
 }
 
 func (s *Store) Close() error {
@@ -132,7 +147,12 @@ RETURNING id;`
 	return id, nil
 }
 
-// UpsertInstance inserts or updates a signal instance keyed by (definition_id, entity_ref).
+// UpsertInstance records a detection of an instance keyed by (definition_id,
+// entity_ref). The status follows the lifecycle rather than inst.Status: new
+// instances open, resolved ones reopen and expired snoozes wake up, while
+// acknowledged, snoozed and suppressed instances keep their status. Status
+// changes, and severity escalations, are appended to the instance history
+// and queued for notification.
 func (s *Store) UpsertInstance(ctx context.Context, inst Instance) error {
 	if inst.DefinitionID == "" || inst.EntityRef == "" {
 		return fmt.Errorf("definitionId and entityRef are required")
@@ -140,30 +160,83 @@ func (s *Store) UpsertInstance(ctx context.Context, inst Instance) error {
 	if inst.ID == "" {
 		inst.ID = uuid.New().String()
 	}
-	if inst.Status == "" {
-		inst.Status = "OPEN"
-	}
 	if inst.Severity == "" {
 		inst.Severity = "INFO"
 	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// FOR UPDATE cannot lock a row that does not exist yet, so concurrent
+	// runs detecting the same new instance serialize on an advisory lock
+	// instead; otherwise both would record it as opened.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1), hashtext($2))`, inst.DefinitionID, inst.EntityRef); err != nil {
+		return err
+	}
+	var from, prevSeverity string
+	var snoozedUntil *time.Time
+	current, err := scanInstance(tx.QueryRowContext(ctx, instanceColumns+`
+FROM metadata.signal_instances WHERE definition_id=$1 AND entity_ref=$2 FOR UPDATE`, inst.DefinitionID, inst.EntityRef))
+	switch {
+	case err == nil:
+		from, snoozedUntil, prevSeverity = current.Status, current.SnoozedUntil, current.Severity
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+	status := detectedStatus(from, snoozedUntil, time.Now())
+
 	const stmt = `
 INSERT INTO metadata.signal_instances
-  (id, definition_id, status, entity_ref, entity_kind, severity, summary, details, source_run_id, first_seen_at, last_seen_at, created_at, updated_at)
-VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,now(),now(),now(),now())
+  (id, definition_id, status, entity_ref, entity_kind, severity, summary, details, source_run_id, tenant_id, project_id, first_seen_at, last_seen_at, created_at, updated_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,now(),now(),now(),now())
 ON CONFLICT (definition_id, entity_ref) DO UPDATE SET
   status=EXCLUDED.status,
   severity=EXCLUDED.severity,
   summary=EXCLUDED.summary,
   details=EXCLUDED.details,
   source_run_id=EXCLUDED.source_run_id,
+  tenant_id=COALESCE(EXCLUDED.tenant_id, signal_instances.tenant_id),
+  project_id=COALESCE(EXCLUDED.project_id, signal_instances.project_id),
+  snoozed_until=CASE WHEN EXCLUDED.status='SNOOZED' THEN signal_instances.snoozed_until END,
+  acknowledged_by=CASE WHEN EXCLUDED.status='REOPENED' THEN NULL ELSE signal_instances.acknowledged_by END,
+  resolved_at=NULL,
   last_seen_at=now(),
-  updated_at=now();`
+  updated_at=now()
+RETURNING `
 	// JSON-encode details map for PostgreSQL JSONB column
 	detailsJSON, _ := json.Marshal(inst.Details)
-	_, err := s.db.ExecContext(ctx, stmt,
-		inst.ID, inst.DefinitionID, inst.Status, inst.EntityRef, inst.EntityKind, inst.Severity, inst.Summary, detailsJSON, inst.SourceRunID,
-	)
-	return err
+	var saved Instance
+	if err := scanInto(&saved, tx.QueryRowContext(ctx, stmt+strings.TrimPrefix(instanceColumns, "SELECT "),
+		inst.ID, inst.DefinitionID, status, inst.EntityRef, inst.EntityKind, inst.Severity, inst.Summary, detailsJSON, inst.SourceRunID,
+		nullString(inst.TenantID), nullString(inst.ProjectID),
+	)); err != nil {
+		return err
+	}
+	t := Transition{Actor: ActorSystem, Reason: "detected", SourceRunID: inst.SourceRunID}
+	var kind signalnotify.EventKind
+	switch {
+	case from == "":
+		kind = signalnotify.EventOpened
+	case status != from && (status == StatusOpen || status == StatusReopened):
+		kind = signalnotify.EventReopened
+	case signalnotify.SeverityRank(saved.Severity) > signalnotify.SeverityRank(prevSeverity):
+		kind = signalnotify.EventEscalated
+		t.Reason = fmt.Sprintf("escalated %s -> %s", prevSeverity, saved.Severity)
+	}
+	if status != from || kind == signalnotify.EventEscalated {
+		historyID, err := appendHistory(ctx, tx, saved, from, t)
+		if err != nil {
+			return err
+		}
+		if kind != "" {
+			if err := s.notify(ctx, tx, saved, historyID, kind, from, prevSeverity); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
 }
 
 // ListDefinitions returns definitions filtered by source family (best effort).
@@ -196,8 +269,7 @@ func (s *Store) ListDefinitions(ctx context.Context, sourceFamily string) ([]Def
 
 // ListInstancesForDefinition returns existing instances for reconciliation.
 func (s *Store) ListInstancesForDefinition(ctx context.Context, definitionID string) ([]Instance, error) {
-	rows, err := s.db.QueryContext(ctx, `
-SELECT id, definition_id, entity_ref, entity_kind, severity, status
+	rows, err := s.db.QueryContext(ctx, instanceColumns+`
 FROM metadata.signal_instances
 WHERE definition_id=$1`, definitionID)
 	if err != nil {
@@ -207,7 +279,7 @@ WHERE definition_id=$1`, definitionID)
 	var out []Instance
 	for rows.Next() {
 		var inst Instance
-		if err := rows.Scan(&inst.ID, &inst.DefinitionID, &inst.EntityRef, &inst.EntityKind, &inst.Severity, &inst.Status); err != nil {
+		if err := scanInto(&inst, rows); err != nil {
 			return nil, err
 		}
 		out = append(out, inst)
@@ -216,20 +288,47 @@ WHERE definition_id=$1`, definitionID)
 }
 
 // UpdateInstanceStatus updates status for an instance keyed by (definition_id, entity_ref).
+// It is TransitionInstance without an actor, kept for existing callers.
 func (s *Store) UpdateInstanceStatus(ctx context.Context, definitionID, entityRef, status string) error {
 	if definitionID == "" || entityRef == "" {
 		return fmt.Errorf("definitionId and entityRef are required")
 	}
 	if status == "" {
-		status = "RESOLVED"
+		status = StatusResolved
 	}
-	_, err := s.db.ExecContext(ctx, `
-UPDATE metadata.signal_instances
-SET status=$3, updated_at=now()
-WHERE definition_id=$1 AND entity_ref=$2`, definitionID, entityRef, status)
+	_, _, err := s.TransitionInstance(ctx, Transition{DefinitionID: definitionID, EntityRef: entityRef, Status: status})
 	return err
 }
 
+const instanceColumns = `SELECT id, definition_id, status, entity_ref, entity_kind, severity, summary, details, COALESCE(source_run_id, ''),
+  COALESCE(tenant_id, ''), COALESCE(project_id, ''), snoozed_until, COALESCE(acknowledged_by, ''), first_seen_at, last_seen_at, resolved_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanInstance(row rowScanner) (Instance, error) {
+	var inst Instance
+	err := scanInto(&inst, row)
+	return inst, err
+}
+
+func scanInto(inst *Instance, row rowScanner) error {
+	var details []byte
+	var snoozed, resolved sql.NullTime
+	if err := row.Scan(&inst.ID, &inst.DefinitionID, &inst.Status, &inst.EntityRef, &inst.EntityKind, &inst.Severity, &inst.Summary, &details,
+		&inst.SourceRunID, &inst.TenantID, &inst.ProjectID, &snoozed, &inst.AcknowledgedBy, &inst.FirstSeenAt, &inst.LastSeenAt, &resolved); err != nil {
+		return err
+	}
+	inst.Details = nil
+	if len(details) > 0 {
+		_ = json.Unmarshal(details, &inst.Details)
+	}
+	inst.SnoozedUntil = timePtr(snoozed)
+	inst.ResolvedAt = timePtr(resolved)
+	return nil
+}
+
 func nullString(val string) any {
 	if val == "" {
 		return nil
@@ -0,0 +1,186 @@
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestStoreConformance runs the same behavioural checks against every Store
+// implementation. The pgvector run needs VECTOR_TEST_DATABASE_URL.
+func TestStoreConformance(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testStore(t, NewMemoryStore(3))
+	})
+	t.Run("pgvector", func(t *testing.T) {
+		dsn := os.Getenv("VECTOR_TEST_DATABASE_URL")
+		if dsn == "" {
+			t.Skip("VECTOR_TEST_DATABASE_URL not set")
+		}
+		store, err := NewPgVectorStore(dsn, 3)
+		if err != nil {
+			t.Fatalf("open pgvector: %v", err)
+		}
+		defer store.Close()
+		testStore(t, store)
+	})
+}
+
+func testStore(t *testing.T, s Store) {
+	tenant := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+	entry := func(node string, emb []float32, mut func(*Entry)) Entry {
+		e := Entry{
+			TenantID: tenant, ProjectID: "p1", ProfileID: "docs", NodeID: node,
+			SourceFamily: "confluence", ArtifactID: "art-1", RunID: "run-1",
+			EntityKind: "doc.page", Labels: []string{"a"}, Tags: []string{"x"},
+			ContentText: node, Metadata: map[string]any{"space": "ENG", "rank": 1},
+			Embedding: emb,
+		}
+		if mut != nil {
+			mut(&e)
+		}
+		return e
+	}
+	entries := []Entry{
+		entry("n1", []float32{1, 0, 0}, nil),
+		entry("n2", []float32{0.9, 0.1, 0}, func(e *Entry) { e.Labels = []string{"b"}; e.Metadata = map[string]any{"space": "OPS"} }),
+		entry("n3", []float32{0, 1, 0}, func(e *Entry) { e.EntityKind = "doc.comment"; e.RunID = "run-2" }),
+		entry("n4", []float32{1, 0, 0}, func(e *Entry) { e.ProjectID = "p2"; e.SourceFamily = "jira" }),
+		entry("n5", []float32{0.7, 0.7, 0}, func(e *Entry) { e.ProfileID = "code"; e.ArtifactID = "art-2" }),
+	}
+	if err := s.UpsertEntries(entries); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = s.DeleteByArtifact(tenant, "art-1", "")
+		_ = s.DeleteByArtifact(tenant, "art-2", "")
+	})
+
+	if err := s.UpsertEntries([]Entry{entry("bad", []float32{1, 0}, nil)}); err == nil {
+		t.Fatalf("expected dimension mismatch error")
+	}
+
+	query := func(f QueryFilter, topK int) []string {
+		t.Helper()
+		f.TenantID = tenant
+		res, err := s.Query([]float32{1, 0, 0}, f, topK)
+		if err != nil {
+			t.Fatalf("query %+v: %v", f, err)
+		}
+		ids := make([]string, len(res))
+		for i, r := range res {
+			ids[i] = r.NodeID
+		}
+		return ids
+	}
+	res, err := s.Query([]float32{1, 0, 0}, QueryFilter{TenantID: tenant, ProjectID: "p1"}, 2)
+	if err != nil || len(res) != 2 || res[0].NodeID != "n1" || res[1].NodeID != "n2" {
+		t.Fatalf("cosine top-k: %+v err=%v", res, err)
+	}
+	if res[0].Score < 0.999 || res[1].Score >= res[0].Score {
+		t.Fatalf("unexpected scores: %v %v", res[0].Score, res[1].Score)
+	}
+	if res[0].Metadata["space"] != "ENG" {
+		t.Fatalf("metadata not returned: %+v", res[0].Metadata)
+	}
+
+	cases := []struct {
+		name   string
+		filter QueryFilter
+		want   string
+	}{
+		{"project", QueryFilter{ProjectID: "p2"}, "[n4]"},
+		{"profiles", QueryFilter{ProjectID: "p1", ProfileIDs: []string{"code"}}, "[n5]"},
+		{"source family", QueryFilter{SourceFamily: "jira"}, "[n4]"},
+		{"artifact", QueryFilter{ProjectID: "p1", ArtifactID: "art-2"}, "[n5]"},
+		{"run", QueryFilter{RunID: "run-2"}, "[n3]"},
+		{"entity kinds", QueryFilter{EntityKinds: []string{"doc.comment"}}, "[n3]"},
+		{"labels overlap", QueryFilter{Labels: []string{"b", "z"}}, "[n2]"},
+		{"tags", QueryFilter{ProjectID: "p2", Tags: []string{"x"}}, "[n4]"},
+		{"metadata", QueryFilter{ProjectID: "p1", MetadataEQ: map[string]any{"space": "ENG", "rank": 1}}, "[n1 n5 n3]"},
+		{"since", QueryFilter{SinceUpdatedAt: ptrTime(time.Now().Add(time.Hour))}, "[]"},
+	}
+	for _, tc := range cases {
+		if got := fmt.Sprint(query(tc.filter, 0)); got != tc.want {
+			t.Errorf("%s: got %s want %s", tc.name, got, tc.want)
+		}
+	}
+
+	// Upsert replaces by key.
+	if err := s.UpsertEntries([]Entry{entry("n3", []float32{1, 0, 0}, func(e *Entry) { e.ContentText = "updated" })}); err != nil {
+		t.Fatalf("re-upsert: %v", err)
+	}
+	if got := fmt.Sprint(query(QueryFilter{RunID: "run-2"}, 0)); got != "[]" {
+		t.Fatalf("upsert should replace fields, run-2 still matches: %s", got)
+	}
+
+	list, err := s.ListEntries(QueryFilter{TenantID: tenant, ProjectID: "p1"}, 0)
+	if err != nil || len(list) != 4 {
+		t.Fatalf("list: %d entries err=%v", len(list), err)
+	}
+	if list[0].NodeID != "n3" || list[0].ContentText != "updated" || list[0].UpdatedAt == nil {
+		t.Fatalf("list should be ordered by recency: %+v", list[0])
+	}
+	if list, _ := s.ListEntries(QueryFilter{TenantID: tenant}, 2); len(list) != 2 {
+		t.Fatalf("list limit: %d", len(list))
+	}
+
+	// Paging walks every match in key order, embeddings included.
+	if pager, ok := s.(EntryPager); ok {
+		var paged []string
+		var after *EntryKey
+		for {
+			page, err := pager.PageEntries(context.Background(), QueryFilter{TenantID: tenant, ProjectID: "p1"}, after, 2)
+			if err != nil {
+				t.Fatalf("page: %v", err)
+			}
+			for _, e := range page {
+				if len(e.Embedding) != 3 {
+					t.Fatalf("page should carry embeddings: %+v", e)
+				}
+				paged = append(paged, e.ProfileID+"/"+e.NodeID)
+			}
+			if len(page) < 2 {
+				break
+			}
+			key := KeyOf(page[len(page)-1])
+			after = &key
+		}
+		if got := fmt.Sprint(paged); got != "[code/n5 docs/n1 docs/n2 docs/n3]" {
+			t.Fatalf("paged entries: %s", got)
+		}
+	}
+
+	if err := s.DeleteByArtifact(tenant, "art-1", "run-1"); err != nil {
+		t.Fatalf("delete run: %v", err)
+	}
+	if got := fmt.Sprint(query(QueryFilter{}, 0)); got != "[n5]" {
+		t.Fatalf("after run delete: %s", got)
+	}
+	if err := s.DeleteByArtifact(tenant, "art-2", ""); err != nil {
+		t.Fatalf("delete artifact: %v", err)
+	}
+	if got := fmt.Sprint(query(QueryFilter{}, 0)); got != "[]" {
+		t.Fatalf("after artifact delete: %s", got)
+	}
+
+	// Concurrent writers and readers must not race.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = s.UpsertEntries([]Entry{entry(fmt.Sprintf("c%d", i), []float32{1, float32(i), 0}, nil)})
+			_, _ = s.Query([]float32{1, 0, 0}, QueryFilter{TenantID: tenant}, 3)
+		}(i)
+	}
+	wg.Wait()
+	if list, _ := s.ListEntries(QueryFilter{TenantID: tenant}, 0); len(list) != 8 {
+		t.Fatalf("concurrent upserts: %d entries", len(list))
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
@@ -0,0 +1,126 @@
+package vectorstore
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// IndexType selects the pgvector approximate-nearest-neighbour index.
+type IndexType string
+
+const (
+	IndexIVFFlat IndexType = "ivfflat"
+	IndexHNSW    IndexType = "hnsw"
+	IndexNone    IndexType = "none" // exact scan; fine for small tables
+)
+
+// pgvector cannot index vectors wider than this.
+const maxIndexedDimension = 2000
+
+// IndexConfig configures the embedding index and its query-time knobs.
+// Zero values pick pgvector's recommended defaults.
+type IndexConfig struct {
+	Type IndexType
+
+	// HNSW build parameters.
+	M              int // max connections per layer (default 16)
+	EfConstruction int // candidate list size while building (default max(64, 2*M))
+
+	// IVFFlat build parameter. Zero scales with the row count when the
+	// index is built (rows/1000 up to 1M rows, sqrt(rows) beyond).
+	Lists int
+
+	// Query-time knobs, applied with SET LOCAL per query.
+	EfSearch int // hnsw.ef_search; zero keeps the server default (40)
+	Probes   int // ivfflat.probes; zero uses sqrt(lists)
+}
+
+// ParseIndexType normalises a user-supplied index type. Empty means ivfflat,
+// which keeps the historical default.
+func ParseIndexType(v string) (IndexType, error) {
+	switch IndexType(strings.ToLower(strings.TrimSpace(v))) {
+	case "", IndexIVFFlat:
+		return IndexIVFFlat, nil
+	case IndexHNSW:
+		return IndexHNSW, nil
+	case IndexNone:
+		return IndexNone, nil
+	default:
+		return "", fmt.Errorf("unsupported vector index type %q", v)
+	}
+}
+
+func (c IndexConfig) withDefaults() IndexConfig {
+	if c.Type == "" {
+		c.Type = IndexIVFFlat
+	}
+	if c.M <= 0 {
+		c.M = 16
+	}
+	if c.EfConstruction <= 0 {
+		c.EfConstruction = max(64, 2*c.M)
+	}
+	return c
+}
+
+func (c IndexConfig) validate(dimension int) error {
+	if _, err := ParseIndexType(string(c.Type)); err != nil {
+		return err
+	}
+	if c.Type != IndexNone && dimension > maxIndexedDimension {
+		return fmt.Errorf("%s index supports at most %d dimensions, got %d", c.Type, maxIndexedDimension, dimension)
+	}
+	if c.Type == IndexHNSW && c.EfConstruction < 2*c.M {
+		return fmt.Errorf("hnsw ef_construction (%d) must be at least 2*m (%d)", c.EfConstruction, 2*c.M)
+	}
+	return nil
+}
+
+// ScaledLists returns the ivfflat list count pgvector recommends for rows.
+func ScaledLists(rows int64) int {
+	lists := int(rows / 1000)
+	if rows > 1_000_000 {
+		lists = int(math.Sqrt(float64(rows)))
+	}
+	if lists < 10 {
+		lists = 10
+	}
+	return lists
+}
+
+// indexDDL returns the CREATE INDEX statement for table, or "" for IndexNone.
+// lists is only used for ivfflat.
+func (c IndexConfig) indexDDL(table string, lists int) string {
+	name := table + "_embedding_idx"
+	switch c.Type {
+	case IndexHNSW:
+		return fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s USING hnsw (embedding vector_cosine_ops) WITH (m = %d, ef_construction = %d)`,
+			name, table, c.M, c.EfConstruction)
+	case IndexIVFFlat:
+		return fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s USING ivfflat (embedding vector_cosine_ops) WITH (lists = %d)`,
+			name, table, lists)
+	default:
+		return ""
+	}
+}
+
+// sessionSettings returns the SET LOCAL statements tuning a query against an
+// index built with lists.
+func (c IndexConfig) sessionSettings(lists int) []string {
+	switch c.Type {
+	case IndexHNSW:
+		if c.EfSearch > 0 {
+			return []string{fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", c.EfSearch)}
+		}
+	case IndexIVFFlat:
+		probes := c.Probes
+		if probes <= 0 && lists > 0 {
+			probes = int(math.Ceil(math.Sqrt(float64(lists))))
+		}
+		if probes > 0 {
+			return []string{fmt.Sprintf("SET LOCAL ivfflat.probes = %d", probes)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package vectorstore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScaledLists(t *testing.T) {
+	cases := map[int64]int{0: 10, 5_000: 10, 250_000: 250, 1_000_000: 1000, 4_000_000: 2000}
+	for rows, want := range cases {
+		if got := ScaledLists(rows); got != want {
+			t.Errorf("ScaledLists(%d) = %d, want %d", rows, got, want)
+		}
+	}
+}
+
+func TestIndexDDLAndSessionSettings(t *testing.T) {
+	hnsw := IndexConfig{Type: IndexHNSW, M: 24, EfSearch: 100}.withDefaults()
+	if ddl := hnsw.indexDDL("vector_entries", 0); !strings.Contains(ddl, "USING hnsw (embedding vector_cosine_ops) WITH (m = 24, ef_construction = 64)") {
+		t.Fatalf("unexpected hnsw ddl: %s", ddl)
+	}
+	if got := hnsw.sessionSettings(0); len(got) != 1 || got[0] != "SET LOCAL hnsw.ef_search = 100" {
+		t.Fatalf("unexpected hnsw settings: %v", got)
+	}
+	if err := (IndexConfig{Type: IndexHNSW, M: 40, EfConstruction: 50}).validate(1536); err == nil {
+		t.Fatalf("expected ef_construction < 2*m to be rejected")
+	}
+
+	ivf := IndexConfig{}.withDefaults()
+	if ddl := ivf.indexDDL("t", 250); !strings.Contains(ddl, "USING ivfflat (embedding vector_cosine_ops) WITH (lists = 250)") {
+		t.Fatalf("unexpected ivfflat ddl: %s", ddl)
+	}
+	if got := ivf.sessionSettings(250); len(got) != 1 || got[0] != "SET LOCAL ivfflat.probes = 16" {
+		t.Fatalf("probes should default to sqrt(lists): %v", got)
+	}
+	if err := ivf.validate(3072); err == nil {
+		t.Fatalf("expected dimensions above %d to be rejected for indexed models", maxIndexedDimension)
+	}
+	if err := (IndexConfig{Type: IndexNone}).withDefaults().validate(3072); err != nil {
+		t.Fatalf("unindexed models have no dimension limit: %v", err)
+	}
+}
+
+func TestTableForModel(t *testing.T) {
+	if got := tableForModel(DefaultModel); got != "vector_entries" {
+		t.Fatalf("default model must keep the legacy table, got %s", got)
+	}
+	if got := tableForModel("text-embedding-3-small"); got != "vector_entries__text_embedding_3_small" {
+		t.Fatalf("unexpected table: %s", got)
+	}
+	long := tableForModel("org/some-very-long-embedding-model-name-v2-multilingual")
+	if len(long) > 48 || long == tableForModel("org/some-very-long-embedding-model-name-v3-multilingual") {
+		t.Fatalf("long names must be truncated with a distinguishing hash: %s", long)
+	}
+}
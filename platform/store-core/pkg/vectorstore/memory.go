@@ -0,0 +1,234 @@
+package vectorstore
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore implements Store in process memory using exact cosine search.
+// Filter semantics match PgVectorStore; it is intended for tests and small
+// local deployments.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	dimension int
+	entries   map[memoryKey]Entry
+}
+
+type memoryKey struct {
+	tenantID  string
+	projectID string
+	profileID string
+	nodeID    string
+}
+
+// NewMemoryStore returns an empty store. A positive dimension enforces the
+// embedding length like PgVectorStore; zero accepts any length.
+func NewMemoryStore(dimension int) *MemoryStore {
+	return &MemoryStore{dimension: dimension, entries: make(map[memoryKey]Entry)}
+}
+
+func (s *MemoryStore) Close() error { return nil }
+
+// UpsertEntries inserts or replaces entries keyed by tenant/project/profile/node.
+func (s *MemoryStore) UpsertEntries(entries []Entry) error {
+	for _, e := range entries {
+		if err := s.checkEmbedding(e.Embedding); err != nil {
+			return err
+		}
+	}
+	now := time.Now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range entries {
+		e.Labels = append([]string(nil), e.Labels...)
+		e.Tags = append([]string(nil), e.Tags...)
+		e.Embedding = append([]float32(nil), e.Embedding...)
+		updated := now
+		e.UpdatedAt = &updated
+		s.entries[memoryKey{e.TenantID, e.ProjectID, e.ProfileID, e.NodeID}] = e
+	}
+	return nil
+}
+
+// Query returns the topK entries by cosine similarity.
+func (s *MemoryStore) Query(embedding []float32, filter QueryFilter, topK int) ([]SearchResult, error) {
+	if topK <= 0 {
+		topK = 10
+	}
+	if err := s.checkEmbedding(embedding); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	var results []SearchResult
+	for _, e := range s.entries {
+		if !matchesQuery(e, filter) || len(e.Embedding) != len(embedding) {
+			continue
+		}
+		results = append(results, SearchResult{
+			NodeID:      e.NodeID,
+			ProfileID:   e.ProfileID,
+			Score:       cosine(embedding, e.Embedding),
+			ContentText: e.ContentText,
+			Metadata:    e.Metadata,
+			RawMetadata: e.RawMetadata,
+			RawPayload:  e.RawPayload,
+		})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].NodeID < results[j].NodeID
+	})
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results, nil
+}
+
+// DeleteByArtifact removes entries produced by a specific artifact/run. An
+// empty runID removes every run of the artifact.
+func (s *MemoryStore) DeleteByArtifact(tenantID, artifactID, runID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, e := range s.entries {
+		if e.TenantID == tenantID && e.ArtifactID == artifactID && (runID == "" || e.RunID == runID) {
+			delete(s.entries, k)
+		}
+	}
+	return nil
+}
+
+// ListEntries returns the most recently updated entries matching the filter.
+// Like PgVectorStore it only scopes by tenant, project, profiles and source
+// family.
+func (s *MemoryStore) ListEntries(filter QueryFilter, limit int) ([]Entry, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	s.mu.RLock()
+	var list []Entry
+	for _, e := range s.entries {
+		if matchesScope(e, filter) {
+			list = append(list, e)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].UpdatedAt.Equal(*list[j].UpdatedAt) {
+			return list[i].UpdatedAt.After(*list[j].UpdatedAt)
+		}
+		return list[i].NodeID < list[j].NodeID
+	})
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (s *MemoryStore) checkEmbedding(embedding []float32) error {
+	if len(embedding) == 0 {
+		return errors.New("embedding is required")
+	}
+	if s.dimension > 0 && len(embedding) != s.dimension {
+		return fmt.Errorf("embedding length %d does not match dimension %d", len(embedding), s.dimension)
+	}
+	return nil
+}
+
+func matchesScope(e Entry, f QueryFilter) bool {
+	if e.TenantID != f.TenantID {
+		return false
+	}
+	if f.ProjectID != "" && e.ProjectID != f.ProjectID {
+		return false
+	}
+	if len(f.ProfileIDs) > 0 && !containsAny([]string{e.ProfileID}, f.ProfileIDs) {
+		return false
+	}
+	return f.SourceFamily == "" || e.SourceFamily == f.SourceFamily
+}
+
+func matchesQuery(e Entry, f QueryFilter) bool {
+	switch {
+	case !matchesScope(e, f),
+		f.ArtifactID != "" && e.ArtifactID != f.ArtifactID,
+		f.RunID != "" && e.RunID != f.RunID,
+		f.SinkEndpointID != "" && e.SinkEndpointID != f.SinkEndpointID,
+		f.DatasetSlug != "" && e.DatasetSlug != f.DatasetSlug,
+		len(f.EntityKinds) > 0 && !containsAny([]string{e.EntityKind}, f.EntityKinds),
+		len(f.Labels) > 0 && !containsAny(e.Labels, f.Labels),
+		len(f.Tags) > 0 && !containsAny(e.Tags, f.Tags),
+		f.SinceUpdatedAt != nil && e.UpdatedAt.Before(*f.SinceUpdatedAt):
+		return false
+	}
+	for k, want := range f.MetadataEQ {
+		got, ok := e.Metadata[k]
+		if !ok || !metadataEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsAny reports whether the two sets overlap (SQL "&&").
+func containsAny(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// metadataEqual compares JSON-ish values, treating all numbers as float64 so
+// an int filter matches a decoded JSON number.
+func metadataEqual(a, b any) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func cosine(a, b []float32) float32 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb)))
+}
+
+var _ Store = (*MemoryStore)(nil)
@@ -0,0 +1,309 @@
+package vectorstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+)
+
+// DefaultModel names the model stored in the original vector_entries table.
+const DefaultModel = "default"
+
+// ErrNoActiveModel is returned when no model has been activated yet.
+var ErrNoActiveModel = errors.New("vectorstore: no active embedding model")
+
+// ModelSpec describes an embedding model's storage.
+type ModelSpec struct {
+	Name      string
+	Dimension int
+	Index     IndexConfig
+}
+
+// ModelInfo is a registered model.
+type ModelInfo struct {
+	ModelSpec
+	Table       string
+	BuiltLists  int // ivfflat lists of the current index, 0 if unknown
+	Active      bool
+	CreatedAt   time.Time
+	ActivatedAt *time.Time
+}
+
+// indexParams is the jsonb shape of vector_models.index_params.
+type indexParams struct {
+	M              int `json:"m,omitempty"`
+	EfConstruction int `json:"ef_construction,omitempty"`
+	Lists          int `json:"lists,omitempty"`
+	BuiltLists     int `json:"built_lists,omitempty"`
+}
+
+// vectorTable is a resolved model table plus query-time settings.
+type vectorTable struct {
+	model     string
+	name      string
+	dimension int
+	index     IndexConfig
+	lists     int
+}
+
+func ensureRegistry(ctx context.Context, q dbtx) error {
+	const ddl = `
+CREATE TABLE IF NOT EXISTS vector_models (
+  model        text PRIMARY KEY,
+  table_name   text NOT NULL UNIQUE,
+  dimension    int NOT NULL,
+  index_type   text NOT NULL,
+  index_params jsonb NOT NULL DEFAULT '{}',
+  active       boolean NOT NULL DEFAULT false,
+  created_at   timestamptz NOT NULL DEFAULT now(),
+  activated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS vector_models_single_active_idx ON vector_models (active) WHERE active;
+`
+	_, err := q.ExecContext(ctx, ddl)
+	return err
+}
+
+// tableForModel maps a model name to a safe, stable table name.
+func tableForModel(model string) string {
+	if model == DefaultModel {
+		return "vector_entries"
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(model) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	name := "vector_entries__" + strings.Trim(b.String(), "_")
+	// Leave room for index suffixes within Postgres' 63-byte identifier limit.
+	if len(name) > 48 {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(model))
+		name = fmt.Sprintf("%s_%08x", name[:39], h.Sum32())
+	}
+	return name
+}
+
+// RegisterModel creates the table for spec and records it in the registry
+// without activating it or building its ANN index (see BuildIndex), so a
+// backfill can load rows first. Registering an existing model is a no-op as
+// long as the dimension matches.
+func (s *PgVectorStore) RegisterModel(ctx context.Context, spec ModelSpec) error {
+	if strings.TrimSpace(spec.Name) == "" {
+		return errors.New("model name is required")
+	}
+	if spec.Dimension <= 0 {
+		return fmt.Errorf("model %q: dimension must be positive", spec.Name)
+	}
+	spec.Index = spec.Index.withDefaults()
+	if err := spec.Index.validate(spec.Dimension); err != nil {
+		return fmt.Errorf("model %q: %w", spec.Name, err)
+	}
+	table := tableForModel(spec.Name)
+	if err := createModelTable(ctx, s.db, table, spec.Dimension); err != nil {
+		return err
+	}
+	params, _ := json.Marshal(indexParams{M: spec.Index.M, EfConstruction: spec.Index.EfConstruction, Lists: spec.Index.Lists})
+	if _, err := s.db.ExecContext(ctx, `
+INSERT INTO vector_models (model, table_name, dimension, index_type, index_params)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (model) DO NOTHING`, spec.Name, table, spec.Dimension, string(spec.Index.Type), params); err != nil {
+		return err
+	}
+	info, err := s.loadModel(ctx, s.db, spec.Name, false)
+	if err != nil {
+		return err
+	}
+	if info.Dimension != spec.Dimension {
+		return fmt.Errorf("model %q is registered with dimension %d, not %d", spec.Name, info.Dimension, spec.Dimension)
+	}
+	return nil
+}
+
+// BuildIndex creates the model's ANN index if it is missing. With rebuild it
+// drops and recreates it, re-scaling ivfflat lists to the current row count;
+// run it after a large backfill. Rebuilding blocks writes to that table.
+func (s *PgVectorStore) BuildIndex(ctx context.Context, model string, rebuild bool) error {
+	info, err := s.loadModel(ctx, s.db, model, false)
+	if err != nil {
+		return err
+	}
+	indexName := info.Table + "_embedding_idx"
+	if !rebuild {
+		var existing sql.NullString
+		if err := s.db.QueryRowContext(ctx, `SELECT to_regclass($1)::text`, indexName).Scan(&existing); err != nil {
+			return err
+		}
+		if existing.Valid {
+			return nil
+		}
+	} else if _, err := s.db.ExecContext(ctx, fmt.Sprintf(`DROP INDEX IF EXISTS %s`, indexName)); err != nil {
+		return err
+	}
+
+	lists := 0
+	if info.Index.Type == IndexIVFFlat {
+		lists = info.Index.Lists
+		if lists <= 0 {
+			var rows int64
+			if err := s.db.QueryRowContext(ctx, fmt.Sprintf(`SELECT count(*) FROM %s`, info.Table)).Scan(&rows); err != nil {
+				return err
+			}
+			lists = ScaledLists(rows)
+		}
+	}
+	if ddl := info.Index.indexDDL(info.Table, lists); ddl != "" {
+		if _, err := s.db.ExecContext(ctx, ddl); err != nil {
+			return fmt.Errorf("build %s index for %q: %w", info.Index.Type, model, err)
+		}
+	}
+	_, err = s.db.ExecContext(ctx, `
+UPDATE vector_models SET index_params = index_params || jsonb_build_object('built_lists', $2::int)
+WHERE model = $1`, model, lists)
+	return err
+}
+
+// ActivateModel atomically makes model the one every following store reads
+// and writes. It takes an exclusive lock on the registry rows, which waits
+// for in-flight writes (they hold a share lock) and blocks new ones until
+// commit. catchUp, if set, runs inside that transaction so the final delta
+// can be copied with writers paused.
+func (s *PgVectorStore) ActivateModel(ctx context.Context, model string, catchUp func(ctx context.Context, tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT model FROM vector_models WHERE active OR model = $1 FOR UPDATE`, model)
+	if err != nil {
+		return err
+	}
+	found := false
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		found = found || name == model
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("model %q is not registered", model)
+	}
+	if catchUp != nil {
+		if err := catchUp(ctx, tx); err != nil {
+			return fmt.Errorf("catch up %q: %w", model, err)
+		}
+	}
+	// Two statements: the single-active unique index is checked per row.
+	if _, err := tx.ExecContext(ctx, `UPDATE vector_models SET active = false WHERE active AND model <> $1`, model); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE vector_models SET active = true, activated_at = now() WHERE model = $1 AND NOT active`, model); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ActiveModel returns the active model or ErrNoActiveModel.
+func (s *PgVectorStore) ActiveModel(ctx context.Context) (ModelInfo, error) {
+	return s.loadModel(ctx, s.db, "", false)
+}
+
+// Models lists every registered model.
+func (s *PgVectorStore) Models(ctx context.Context) ([]ModelInfo, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+modelColumns+` FROM vector_models ORDER BY created_at, model`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ModelInfo
+	for rows.Next() {
+		info, err := scanModel(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, info)
+	}
+	return out, rows.Err()
+}
+
+const modelColumns = `model, table_name, dimension, index_type, index_params, active, created_at, activated_at`
+
+// loadModel loads one model; an empty name loads the active one. lock takes a
+// share lock on the row for the rest of q's transaction.
+func (s *PgVectorStore) loadModel(ctx context.Context, q dbtx, name string, lock bool) (ModelInfo, error) {
+	stmt := `SELECT ` + modelColumns + ` FROM vector_models WHERE `
+	var args []any
+	if name == "" {
+		stmt += `active`
+	} else {
+		stmt += `model = $1`
+		args = append(args, name)
+	}
+	if lock {
+		stmt += ` FOR SHARE`
+	}
+	info, err := scanModel(q.QueryRowContext(ctx, stmt, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		if name == "" {
+			return info, ErrNoActiveModel
+		}
+		return info, fmt.Errorf("model %q is not registered", name)
+	}
+	return info, err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanModel(row rowScanner) (ModelInfo, error) {
+	var info ModelInfo
+	var indexType string
+	var paramsBytes []byte
+	if err := row.Scan(&info.Name, &info.Table, &info.Dimension, &indexType, &paramsBytes, &info.Active, &info.CreatedAt, &info.ActivatedAt); err != nil {
+		return info, err
+	}
+	var params indexParams
+	_ = json.Unmarshal(paramsBytes, &params)
+	info.Index = IndexConfig{
+		Type:           IndexType(indexType),
+		M:              params.M,
+		EfConstruction: params.EfConstruction,
+		Lists:          params.Lists,
+	}
+	info.BuiltLists = params.BuiltLists
+	return info, nil
+}
+
+// resolve returns the table this store reads and writes. A following store
+// that loses a race with ActivateModel (the old row stops matching once the
+// swap commits) resolves again.
+func (s *PgVectorStore) resolve(ctx context.Context, q dbtx, lock bool) (vectorTable, error) {
+	info, err := s.loadModel(ctx, q, s.model, lock)
+	if errors.Is(err, ErrNoActiveModel) && lock {
+		info, err = s.loadModel(ctx, q, s.model, lock)
+	}
+	if err != nil {
+		return vectorTable{}, err
+	}
+	index := info.Index
+	index.EfSearch = s.index.EfSearch
+	index.Probes = s.index.Probes
+	return vectorTable{model: info.Name, name: info.Table, dimension: info.Dimension, index: index, lists: info.BuiltLists}, nil
+}
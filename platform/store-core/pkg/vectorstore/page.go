@@ -0,0 +1,186 @@
+package vectorstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// EntryPager is implemented by stores that can page through every entry
+// matching a filter, embeddings included. Unlike ListEntries it has no
+// upper bound: callers keep passing the key of the last entry returned until
+// a page comes back short.
+type EntryPager interface {
+	PageEntries(ctx context.Context, filter QueryFilter, after *EntryKey, limit int) ([]Entry, error)
+}
+
+// KeyOf returns the entry's primary key, for use as a paging cursor.
+func KeyOf(e Entry) EntryKey {
+	return EntryKey{TenantID: e.TenantID, ProjectID: e.ProjectID, ProfileID: e.ProfileID, NodeID: e.NodeID}
+}
+
+// PageEntries returns up to limit entries after the cursor in primary-key
+// order, with their embeddings.
+func (s *PgVectorStore) PageEntries(ctx context.Context, filter QueryFilter, after *EntryKey, limit int) ([]Entry, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	tgt, err := s.resolve(ctx, s.db, false)
+	if err != nil {
+		return nil, err
+	}
+	where := []string{"tenant_id = $1"}
+	args := []any{filter.TenantID}
+	add := func(cond string, v any) {
+		args = append(args, v)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.ProjectID != "" {
+		add("project_id = $%d", filter.ProjectID)
+	}
+	if len(filter.ProfileIDs) > 0 {
+		add("profile_id = ANY($%d)", pq.Array(filter.ProfileIDs))
+	}
+	if filter.SourceFamily != "" {
+		add("source_family = $%d", filter.SourceFamily)
+	}
+	if filter.ArtifactID != "" {
+		add("artifact_id = $%d", filter.ArtifactID)
+	}
+	if filter.RunID != "" {
+		add("run_id = $%d", filter.RunID)
+	}
+	if filter.SinkEndpointID != "" {
+		add("sink_endpoint_id = $%d", filter.SinkEndpointID)
+	}
+	if filter.DatasetSlug != "" {
+		add("dataset_slug = $%d", filter.DatasetSlug)
+	}
+	if len(filter.EntityKinds) > 0 {
+		add("entity_kind = ANY($%d)", pq.Array(filter.EntityKinds))
+	}
+	if len(filter.Labels) > 0 {
+		add("labels && $%d", pq.Array(filter.Labels))
+	}
+	if len(filter.Tags) > 0 {
+		add("tags && $%d", pq.Array(filter.Tags))
+	}
+	if filter.SinceUpdatedAt != nil {
+		add("updated_at >= $%d", *filter.SinceUpdatedAt)
+	}
+	if len(filter.MetadataEQ) > 0 {
+		metaEQ, err := json.Marshal(filter.MetadataEQ)
+		if err != nil {
+			return nil, fmt.Errorf("metadata filter: %w", err)
+		}
+		add("metadata @> $%d::jsonb", string(metaEQ))
+	}
+	if after != nil {
+		n := len(args)
+		args = append(args, after.TenantID, after.ProjectID, after.ProfileID, after.NodeID)
+		where = append(where, fmt.Sprintf("(tenant_id, project_id, profile_id, node_id) > ($%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4))
+	}
+	query := fmt.Sprintf(`
+SELECT tenant_id, project_id, profile_id, node_id, COALESCE(source_family, ''), COALESCE(artifact_id, ''), COALESCE(run_id, ''),
+       COALESCE(sink_endpoint_id, ''), COALESCE(dataset_slug, ''), COALESCE(entity_kind, ''),
+       COALESCE(content_text, ''), metadata, embedding::text, updated_at
+FROM %s
+WHERE %s
+ORDER BY tenant_id, project_id, profile_id, node_id
+LIMIT %d`, tgt.name, strings.Join(where, " AND "), limit)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Entry
+	for rows.Next() {
+		var e Entry
+		var metaBytes []byte
+		var embText sql.NullString
+		var updatedAt time.Time
+		if err := rows.Scan(&e.TenantID, &e.ProjectID, &e.ProfileID, &e.NodeID, &e.SourceFamily, &e.ArtifactID, &e.RunID,
+			&e.SinkEndpointID, &e.DatasetSlug, &e.EntityKind, &e.ContentText, &metaBytes, &embText, &updatedAt); err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(metaBytes, &e.Metadata)
+		if e.Embedding, err = parseVectorLiteral(embText.String); err != nil {
+			return nil, fmt.Errorf("entry %s: %w", e.NodeID, err)
+		}
+		e.UpdatedAt = &updatedAt
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// parseVectorLiteral parses pgvector's text form, "[1,2,3]".
+func parseVectorLiteral(s string) ([]float32, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("malformed vector %q", s)
+	}
+	s = s[1 : len(s)-1]
+	if s == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	out := make([]float32, len(parts))
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 32)
+		if err != nil {
+			return nil, fmt.Errorf("malformed vector component %q", p)
+		}
+		out[i] = float32(f)
+	}
+	return out, nil
+}
+
+// PageEntries returns up to limit entries after the cursor in primary-key
+// order, with their embeddings.
+func (s *MemoryStore) PageEntries(_ context.Context, filter QueryFilter, after *EntryKey, limit int) ([]Entry, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	s.mu.RLock()
+	var list []Entry
+	for _, e := range s.entries {
+		if matchesQuery(e, filter) && (after == nil || keyLess(*after, KeyOf(e))) {
+			list = append(list, e)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool { return keyLess(KeyOf(list[i]), KeyOf(list[j])) })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func keyLess(a, b EntryKey) bool {
+	if a.TenantID != b.TenantID {
+		return a.TenantID < b.TenantID
+	}
+	if a.ProjectID != b.ProjectID {
+		return a.ProjectID < b.ProjectID
+	}
+	if a.ProfileID != b.ProfileID {
+		return a.ProfileID < b.ProfileID
+	}
+	return a.NodeID < b.NodeID
+}
+
+var (
+	_ EntryPager = (*PgVectorStore)(nil)
+	_ EntryPager = (*MemoryStore)(nil)
+)
@@ -1,6 +1,7 @@
 package vectorstore
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -13,40 +14,109 @@ import (
 )
 
 // PgVectorStore implements Store backed by Postgres + pgvector.
+//
+// Each embedding model lives in its own table, tracked in vector_models. A
+// store opened without a model follows whichever model is active, so a
+// re-embedding migration (see Reembed) can swap every reader and writer over
+// in one transaction.
 type PgVectorStore struct {
 	db        *sql.DB
 	dimension int
+	model     string
+	index     IndexConfig
+}
+
+// Options configures a PgVectorStore.
+type Options struct {
+	// Dimension of the store's model. When following the active model it is
+	// only used to bootstrap the default model on an empty registry.
+	Dimension int
+	// Model pins the store to one embedding model. Empty follows the active
+	// model.
+	Model string
+	// Index configures the ANN index of newly registered models and the
+	// query-time ef_search/probes knobs.
+	Index IndexConfig
+}
+
+// dbtx is satisfied by *sql.DB and *sql.Tx.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
 // NewPgVectorStore connects to Postgres (with pgvector) and ensures the table exists.
 func NewPgVectorStore(dsn string, dimension int) (*PgVectorStore, error) {
+	return OpenPgVectorStore(dsn, Options{Dimension: dimension})
+}
+
+// OpenPgVectorStore connects to Postgres (with pgvector) using opts.
+func OpenPgVectorStore(dsn string, opts Options) (*PgVectorStore, error) {
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, err
 	}
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(30 * time.Minute)
-	return NewPgVectorStoreFromDB(db, dimension)
+	return NewPgVectorStoreWithOptions(db, opts)
 }
 
 // NewPgVectorStoreFromDB reuses an existing *sql.DB (for example via pgxpool/stdlib).
 func NewPgVectorStoreFromDB(db *sql.DB, dimension int) (*PgVectorStore, error) {
+	return NewPgVectorStoreWithOptions(db, Options{Dimension: dimension})
+}
+
+// NewPgVectorStoreWithOptions reuses an existing *sql.DB and ensures the
+// model registry and the store's model table exist.
+func NewPgVectorStoreWithOptions(db *sql.DB, opts Options) (*PgVectorStore, error) {
 	if db == nil {
 		return nil, errors.New("db is required")
 	}
-	if dimension <= 0 {
-		dimension = 1536
+	if opts.Dimension <= 0 {
+		opts.Dimension = 1536
 	}
-	store := &PgVectorStore{db: db, dimension: dimension}
-	if err := store.ensureTables(); err != nil {
+	opts.Index = opts.Index.withDefaults()
+	if err := opts.Index.validate(opts.Dimension); err != nil {
+		return nil, err
+	}
+	store := &PgVectorStore{db: db, dimension: opts.Dimension, model: opts.Model, index: opts.Index}
+	if err := store.ensureTables(context.Background()); err != nil {
 		return nil, err
 	}
 	return store, nil
 }
 
-func (s *PgVectorStore) ensureTables() error {
+func (s *PgVectorStore) ensureTables(ctx context.Context) error {
+	if err := ensureRegistry(ctx, s.db); err != nil {
+		return err
+	}
+	if s.model != "" {
+		if err := s.RegisterModel(ctx, ModelSpec{Name: s.model, Dimension: s.dimension, Index: s.index}); err != nil {
+			return err
+		}
+		return s.BuildIndex(ctx, s.model, false)
+	}
+	// Following the active model: on a fresh registry, adopt the legacy
+	// vector_entries table as the default model.
+	if _, err := s.ActiveModel(ctx); err == nil || !errors.Is(err, ErrNoActiveModel) {
+		return err
+	}
+	if err := s.RegisterModel(ctx, ModelSpec{Name: DefaultModel, Dimension: s.dimension, Index: s.index}); err != nil {
+		return err
+	}
+	if err := s.BuildIndex(ctx, DefaultModel, false); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `
+UPDATE vector_models SET active = true, activated_at = now()
+WHERE model = $1 AND NOT EXISTS (SELECT 1 FROM vector_models WHERE active)`, DefaultModel)
+	return err
+}
+
+func createModelTable(ctx context.Context, q dbtx, table string, dimension int) error {
 	ddl := fmt.Sprintf(`
-CREATE TABLE IF NOT EXISTS vector_entries (
+CREATE TABLE IF NOT EXISTS %[1]s (
   tenant_id        text NOT NULL,
   project_id       text NOT NULL,
   profile_id       text NOT NULL,
@@ -63,17 +133,17 @@ CREATE TABLE IF NOT EXISTS vector_entries (
   metadata         jsonb,
   raw_payload      jsonb,
   raw_metadata     jsonb,
-  embedding        vector(%d),
+  embedding        vector(%[2]d),
   created_at       timestamptz NOT NULL DEFAULT now(),
   updated_at       timestamptz NOT NULL DEFAULT now(),
   PRIMARY KEY (tenant_id, project_id, profile_id, node_id)
 );
-CREATE INDEX IF NOT EXISTS vector_entries_profile_idx ON vector_entries (tenant_id, project_id, profile_id);
-CREATE INDEX IF NOT EXISTS vector_entries_artifact_idx ON vector_entries (tenant_id, artifact_id, run_id);
-CREATE INDEX IF NOT EXISTS vector_entries_meta_idx ON vector_entries USING gin (metadata);
-CREATE INDEX IF NOT EXISTS vector_entries_embedding_idx ON vector_entries USING ivfflat (embedding vector_cosine_ops) WITH (lists = 100);
-`, s.dimension)
-	_, err := s.db.Exec(ddl)
+CREATE INDEX IF NOT EXISTS %[1]s_profile_idx ON %[1]s (tenant_id, project_id, profile_id);
+CREATE INDEX IF NOT EXISTS %[1]s_artifact_idx ON %[1]s (tenant_id, artifact_id, run_id);
+CREATE INDEX IF NOT EXISTS %[1]s_meta_idx ON %[1]s USING gin (metadata);
+CREATE INDEX IF NOT EXISTS %[1]s_updated_idx ON %[1]s (updated_at);
+`, table, dimension)
+	_, err := q.ExecContext(ctx, ddl)
 	return err
 }
 
@@ -89,16 +159,32 @@ func (s *PgVectorStore) UpsertEntries(entries []Entry) error {
 	if len(entries) == 0 {
 		return nil
 	}
-	tx, err := s.db.Begin()
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	stmt := `
-INSERT INTO vector_entries
+	// Share-lock the model row so an activation waits for in-flight writes.
+	tgt, err := s.resolve(ctx, tx, true)
+	if err != nil {
+		return err
+	}
+	if err := upsertInto(ctx, tx, tgt, entries, false); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// upsertInto writes entries to tgt. keepUpdatedAt preserves Entry.UpdatedAt
+// (used when copying between models); otherwise updated_at is the database's
+// now(), so re-embedding watermarks share one clock.
+func upsertInto(ctx context.Context, q dbtx, tgt vectorTable, entries []Entry, keepUpdatedAt bool) error {
+	stmt := fmt.Sprintf(`
+INSERT INTO %s
  (tenant_id, project_id, profile_id, node_id, source_family, artifact_id, run_id, sink_endpoint_id, dataset_slug, entity_kind, labels, tags, content_text, metadata, raw_payload, raw_metadata, embedding, updated_at)
- VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18)
+ VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,COALESCE($18::timestamptz, now()))
  ON CONFLICT (tenant_id, project_id, profile_id, node_id) DO UPDATE SET
    source_family=EXCLUDED.source_family,
    artifact_id=EXCLUDED.artifact_id,
@@ -113,24 +199,28 @@ INSERT INTO vector_entries
    raw_payload=EXCLUDED.raw_payload,
    raw_metadata=EXCLUDED.raw_metadata,
    embedding=EXCLUDED.embedding,
-   updated_at=now();
-`
+   updated_at=EXCLUDED.updated_at;
+`, tgt.name)
 	for _, e := range entries {
 		metaBytes, _ := json.Marshal(e.Metadata)
 		rawPayload, _ := json.Marshal(e.RawPayload)
 		rawMeta, _ := json.Marshal(e.RawMetadata)
-		embLit, err := toVectorLiteral(e.Embedding, s.dimension)
+		embLit, err := toVectorLiteral(e.Embedding, tgt.dimension)
 		if err != nil {
 			return err
 		}
-		if _, err := tx.Exec(stmt,
+		var updatedAt *time.Time
+		if keepUpdatedAt {
+			updatedAt = e.UpdatedAt
+		}
+		if _, err := q.ExecContext(ctx, stmt,
 			e.TenantID, e.ProjectID, e.ProfileID, e.NodeID, e.SourceFamily, e.ArtifactID, e.RunID, e.SinkEndpointID, e.DatasetSlug, e.EntityKind,
-			pq.Array(e.Labels), pq.Array(e.Tags), e.ContentText, metaBytes, rawPayload, rawMeta, embLit, time.Now().UTC(),
+			pq.Array(e.Labels), pq.Array(e.Tags), e.ContentText, metaBytes, rawPayload, rawMeta, embLit, updatedAt,
 		); err != nil {
 			return err
 		}
 	}
-	return tx.Commit()
+	return nil
 }
 
 // Query performs similarity search with filters.
@@ -138,7 +228,18 @@ func (s *PgVectorStore) Query(embedding []float32, filter QueryFilter, topK int)
 	if topK <= 0 {
 		topK = 10
 	}
-	embLit, err := toVectorLiteral(embedding, s.dimension)
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tgt, err := s.resolve(ctx, tx, false)
+	if err != nil {
+		return nil, err
+	}
+	embLit, err := toVectorLiteral(embedding, tgt.dimension)
 	if err != nil {
 		return nil, err
 	}
@@ -202,17 +303,32 @@ func (s *PgVectorStore) Query(embedding []float32, filter QueryFilter, topK int)
 		args = append(args, *filter.SinceUpdatedAt)
 		argIdx++
 	}
+	if len(filter.MetadataEQ) > 0 {
+		metaEQ, err := json.Marshal(filter.MetadataEQ)
+		if err != nil {
+			return nil, fmt.Errorf("metadata filter: %w", err)
+		}
+		where = append(where, fmt.Sprintf("metadata @> $%d::jsonb", argIdx))
+		args = append(args, string(metaEQ))
+		argIdx++
+	}
+
+	for _, stmt := range tgt.index.sessionSettings(tgt.lists) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return nil, err
+		}
+	}
 
 	whereSQL := strings.Join(where, " AND ")
 	query := fmt.Sprintf(`
 SELECT node_id, profile_id, 1 - (embedding <=> %s) AS score, content_text, metadata, raw_metadata, raw_payload
-FROM vector_entries
+FROM %s
 WHERE %s
 ORDER BY embedding <=> %s
 LIMIT %d;
-`, embLit, whereSQL, embLit, topK)
+`, embLit, tgt.name, whereSQL, embLit, topK)
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -233,10 +349,27 @@ LIMIT %d;
 	return results, rows.Err()
 }
 
-// DeleteByArtifact removes entries produced by a specific artifact/run.
+// DeleteByArtifact removes entries produced by a specific artifact/run. It
+// deletes from every model's table so a migration in progress cannot
+// resurrect them.
 func (s *PgVectorStore) DeleteByArtifact(tenantID, artifactID, runID string) error {
-	_, err := s.db.Exec(`DELETE FROM vector_entries WHERE tenant_id = $1 AND artifact_id = $2 AND ($3 = '' OR run_id = $3)`, tenantID, artifactID, runID)
-	return err
+	ctx := context.Background()
+	models, err := s.Models(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, m := range models {
+		stmt := fmt.Sprintf(`DELETE FROM %s WHERE tenant_id = $1 AND artifact_id = $2 AND ($3 = '' OR run_id = $3)`, m.Table)
+		if _, err := tx.ExecContext(ctx, stmt, tenantID, artifactID, runID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 // ListEntries returns recent entries matching the filter (limited).
@@ -244,6 +377,11 @@ func (s *PgVectorStore) ListEntries(filter QueryFilter, limit int) ([]Entry, err
 	if limit <= 0 {
 		limit = 100
 	}
+	ctx := context.Background()
+	tgt, err := s.resolve(ctx, s.db, false)
+	if err != nil {
+		return nil, err
+	}
 	where := []string{"tenant_id = $1"}
 	args := []any{filter.TenantID}
 	argIdx := 2
@@ -263,8 +401,8 @@ func (s *PgVectorStore) ListEntries(filter QueryFilter, limit int) ([]Entry, err
 		argIdx++
 	}
 	whereSQL := strings.Join(where, " AND ")
-	query := fmt.Sprintf(`SELECT tenant_id, project_id, profile_id, node_id, content_text, metadata, raw_payload, raw_metadata, updated_at FROM vector_entries WHERE %s ORDER BY updated_at DESC LIMIT %d`, whereSQL, limit)
-	rows, err := s.db.Query(query, args...)
+	query := fmt.Sprintf(`SELECT tenant_id, project_id, profile_id, node_id, content_text, metadata, raw_payload, raw_metadata, updated_at FROM %s WHERE %s ORDER BY updated_at DESC LIMIT %d`, tgt.name, whereSQL, limit)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
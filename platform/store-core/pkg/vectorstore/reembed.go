@@ -0,0 +1,273 @@
+package vectorstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// EmbedFunc embeds texts with a migration's target model.
+type EmbedFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// EntryKey is the primary key of a vector entry, used as a backfill cursor.
+type EntryKey struct {
+	TenantID  string `json:"tenantId"`
+	ProjectID string `json:"projectId"`
+	ProfileID string `json:"profileId"`
+	NodeID    string `json:"nodeId"`
+}
+
+// ReembedCheckpoint lets an interrupted migration resume. Watermark is the
+// database time the backfill started; rows updated since then are re-copied
+// during catch-up.
+type ReembedCheckpoint struct {
+	Target    string    `json:"target"`
+	After     *EntryKey `json:"after,omitempty"`
+	Watermark time.Time `json:"watermark"`
+	Copied    int       `json:"copied"`
+	Backfill  bool      `json:"backfillDone"`
+}
+
+// ReembedOptions drives a migration from the active model to Target.
+type ReembedOptions struct {
+	Target    ModelSpec
+	Embed     EmbedFunc
+	BatchSize int // default 128
+	// Activate swaps the active model once the target has caught up.
+	Activate bool
+	// Resume continues from a checkpoint previously passed to Progress.
+	Resume *ReembedCheckpoint
+	// Progress is called after every batch (e.g. to heartbeat).
+	Progress func(ReembedCheckpoint)
+}
+
+// ReembedResult summarises a migration.
+type ReembedResult struct {
+	Source    string
+	Target    string
+	Copied    int
+	CaughtUp  int
+	Activated bool
+}
+
+// watermarkSlack widens every catch-up window to cover writes whose
+// transaction began (and stamped updated_at) before the watermark but
+// committed after it.
+const watermarkSlack = time.Minute
+
+// maxCatchUpPasses bounds the catch-up passes run before the final locked
+// pass; each pass only copies rows written during the previous one.
+const maxCatchUpPasses = 3
+
+// Reembed backfills Target from the active model by re-embedding every
+// entry's content, builds Target's ANN index sized to the backfilled rows,
+// catches up on rows written meanwhile and, with Activate, swaps Target in
+// atomically (see ActivateModel). The source table is left in place so a
+// rollback is another ActivateModel call.
+//
+// Writers keep embedding with the old model until the swap; deploy them with
+// the new model right after activation, since the store rejects embeddings
+// whose dimension does not match the active model.
+func (s *PgVectorStore) Reembed(ctx context.Context, opts ReembedOptions) (*ReembedResult, error) {
+	if opts.Embed == nil {
+		return nil, errors.New("embed function is required")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 128
+	}
+	source, err := s.ActiveModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if source.Name == opts.Target.Name {
+		return nil, fmt.Errorf("model %q is already active", source.Name)
+	}
+	if err := s.RegisterModel(ctx, opts.Target); err != nil {
+		return nil, err
+	}
+	target, err := s.loadModel(ctx, s.db, opts.Target.Name, false)
+	if err != nil {
+		return nil, err
+	}
+	tgt := vectorTable{model: target.Name, name: target.Table, dimension: target.Dimension}
+	res := &ReembedResult{Source: source.Name, Target: target.Name}
+
+	cp := ReembedCheckpoint{Target: target.Name}
+	if opts.Resume != nil && opts.Resume.Target == target.Name {
+		cp = *opts.Resume
+	}
+	if cp.Watermark.IsZero() {
+		if cp.Watermark, err = dbNow(ctx, s.db); err != nil {
+			return nil, err
+		}
+	}
+	res.Copied = cp.Copied
+
+	if !cp.Backfill {
+		for {
+			n, last, err := s.copyBatch(ctx, s.db, source.Table, tgt, cp.After, nil, opts)
+			if err != nil {
+				return res, err
+			}
+			if n == 0 {
+				break
+			}
+			cp.After = last
+			cp.Copied += n
+			res.Copied = cp.Copied
+			if opts.Progress != nil {
+				opts.Progress(cp)
+			}
+		}
+		cp.Backfill = true
+		if opts.Progress != nil {
+			opts.Progress(cp)
+		}
+		if err := s.BuildIndex(ctx, target.Name, true); err != nil {
+			return res, err
+		}
+	}
+
+	for pass := 0; pass < maxCatchUpPasses; pass++ {
+		next, err := dbNow(ctx, s.db)
+		if err != nil {
+			return res, err
+		}
+		n, err := s.copySince(ctx, s.db, source.Table, tgt, cp.Watermark, opts)
+		if err != nil {
+			return res, err
+		}
+		res.CaughtUp += n
+		cp.Watermark = next
+		if opts.Progress != nil {
+			opts.Progress(cp)
+		}
+		if n < opts.BatchSize {
+			break
+		}
+	}
+
+	if !opts.Activate {
+		return res, nil
+	}
+	err = s.ActivateModel(ctx, target.Name, func(ctx context.Context, tx *sql.Tx) error {
+		n, err := s.copySince(ctx, tx, source.Table, tgt, cp.Watermark, opts)
+		res.CaughtUp += n
+		return err
+	})
+	if err != nil {
+		return res, err
+	}
+	res.Activated = true
+	return res, nil
+}
+
+// copySince re-copies every source row updated at or after since (less
+// watermarkSlack).
+func (s *PgVectorStore) copySince(ctx context.Context, q dbtx, sourceTable string, tgt vectorTable, since time.Time, opts ReembedOptions) (int, error) {
+	since = since.Add(-watermarkSlack)
+	total := 0
+	var after *EntryKey
+	for {
+		n, last, err := s.copyBatch(ctx, q, sourceTable, tgt, after, &since, opts)
+		if err != nil {
+			return total, err
+		}
+		if n == 0 {
+			return total, nil
+		}
+		total += n
+		after = last
+	}
+}
+
+// copyBatch re-embeds up to opts.BatchSize source rows after the cursor and
+// upserts them into tgt, preserving updated_at.
+func (s *PgVectorStore) copyBatch(ctx context.Context, q dbtx, sourceTable string, tgt vectorTable, after *EntryKey, since *time.Time, opts ReembedOptions) (int, *EntryKey, error) {
+	entries, err := scanEntries(ctx, q, sourceTable, after, since, opts.BatchSize)
+	if err != nil || len(entries) == 0 {
+		return 0, after, err
+	}
+	texts := make([]string, len(entries))
+	for i, e := range entries {
+		texts[i] = e.ContentText
+		if strings.TrimSpace(texts[i]) == "" {
+			texts[i] = e.NodeID
+		}
+	}
+	vecs, err := opts.Embed(ctx, texts)
+	if err != nil {
+		return 0, after, fmt.Errorf("embed batch: %w", err)
+	}
+	if len(vecs) != len(entries) {
+		return 0, after, fmt.Errorf("embed batch: got %d vectors for %d texts", len(vecs), len(entries))
+	}
+	for i := range entries {
+		entries[i].Embedding = vecs[i]
+	}
+	if err := upsertInto(ctx, q, tgt, entries, true); err != nil {
+		return 0, after, err
+	}
+	last := entries[len(entries)-1]
+	return len(entries), &EntryKey{TenantID: last.TenantID, ProjectID: last.ProjectID, ProfileID: last.ProfileID, NodeID: last.NodeID}, nil
+}
+
+// scanEntries pages through table in primary-key order. Embeddings are not
+// loaded.
+func scanEntries(ctx context.Context, q dbtx, table string, after *EntryKey, since *time.Time, limit int) ([]Entry, error) {
+	var where []string
+	var args []any
+	if after != nil {
+		args = append(args, after.TenantID, after.ProjectID, after.ProfileID, after.NodeID)
+		where = append(where, "(tenant_id, project_id, profile_id, node_id) > ($1, $2, $3, $4)")
+	}
+	if since != nil {
+		args = append(args, *since)
+		where = append(where, fmt.Sprintf("updated_at >= $%d", len(args)))
+	}
+	whereSQL := ""
+	if len(where) > 0 {
+		whereSQL = "WHERE " + strings.Join(where, " AND ")
+	}
+	query := fmt.Sprintf(`
+SELECT tenant_id, project_id, profile_id, node_id, COALESCE(source_family, ''), COALESCE(artifact_id, ''), COALESCE(run_id, ''),
+       COALESCE(sink_endpoint_id, ''), COALESCE(dataset_slug, ''), COALESCE(entity_kind, ''), labels, tags,
+       COALESCE(content_text, ''), metadata, raw_payload, raw_metadata, updated_at
+FROM %s %s
+ORDER BY tenant_id, project_id, profile_id, node_id
+LIMIT %d`, table, whereSQL, limit)
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Entry
+	for rows.Next() {
+		var e Entry
+		var metaBytes, rawPayloadBytes, rawMetaBytes []byte
+		var updatedAt time.Time
+		if err := rows.Scan(&e.TenantID, &e.ProjectID, &e.ProfileID, &e.NodeID, &e.SourceFamily, &e.ArtifactID, &e.RunID,
+			&e.SinkEndpointID, &e.DatasetSlug, &e.EntityKind, pq.Array(&e.Labels), pq.Array(&e.Tags),
+			&e.ContentText, &metaBytes, &rawPayloadBytes, &rawMetaBytes, &updatedAt); err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(metaBytes, &e.Metadata)
+		_ = json.Unmarshal(rawPayloadBytes, &e.RawPayload)
+		_ = json.Unmarshal(rawMetaBytes, &e.RawMetadata)
+		e.UpdatedAt = &updatedAt
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func dbNow(ctx context.Context, q dbtx) (time.Time, error) {
+	var now time.Time
+	err := q.QueryRowContext(ctx, `SELECT now()`).Scan(&now)
+	return now, err
+}
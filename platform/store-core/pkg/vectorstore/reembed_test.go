@@ -0,0 +1,70 @@
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestReembedMigration needs a dedicated pgvector database: it swaps the
+// globally active model (and restores it afterwards).
+func TestReembedMigration(t *testing.T) {
+	dsn := os.Getenv("VECTOR_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("VECTOR_TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	store, err := OpenPgVectorStore(dsn, Options{Dimension: 3})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer store.Close()
+	source, err := store.ActiveModel(ctx)
+	if err != nil {
+		t.Fatalf("active model: %v", err)
+	}
+	if source.Dimension != 3 {
+		t.Skipf("active model %q has dimension %d; test expects a fresh database", source.Name, source.Dimension)
+	}
+	defer func() { _ = store.ActivateModel(ctx, source.Name, nil) }()
+
+	tenant := fmt.Sprintf("reembed-%d", time.Now().UnixNano())
+	var entries []Entry
+	for i := 0; i < 5; i++ {
+		entries = append(entries, Entry{TenantID: tenant, ProjectID: "p1", ProfileID: "docs", NodeID: fmt.Sprintf("n%d", i),
+			ArtifactID: "art", ContentText: fmt.Sprintf("doc %d", i), Embedding: []float32{1, float32(i), 0}})
+	}
+	if err := store.UpsertEntries(entries); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	defer func() { _ = store.DeleteByArtifact(tenant, "art", "") }()
+
+	target := ModelSpec{Name: fmt.Sprintf("test-model-%d", time.Now().UnixNano()), Dimension: 4, Index: IndexConfig{Type: IndexHNSW}}
+	embed := func(_ context.Context, texts []string) ([][]float32, error) {
+		out := make([][]float32, len(texts))
+		for i := range texts {
+			out[i] = []float32{0, 0, 0, 1}
+		}
+		return out, nil
+	}
+	var checkpoints int
+	res, err := store.Reembed(ctx, ReembedOptions{Target: target, Embed: embed, BatchSize: 2, Activate: true,
+		Progress: func(ReembedCheckpoint) { checkpoints++ }})
+	if err != nil {
+		t.Fatalf("reembed: %v", err)
+	}
+	if !res.Activated || res.Copied < len(entries) || checkpoints == 0 {
+		t.Fatalf("unexpected result: %+v (checkpoints=%d)", res, checkpoints)
+	}
+
+	// The following store now reads and writes the new model.
+	hits, err := store.Query([]float32{0, 0, 0, 1}, QueryFilter{TenantID: tenant}, 10)
+	if err != nil || len(hits) != len(entries) || hits[0].Score < 0.999 {
+		t.Fatalf("query after swap: %+v err=%v", hits, err)
+	}
+	if _, err := store.Query([]float32{1, 0, 0}, QueryFilter{TenantID: tenant}, 10); err == nil {
+		t.Fatalf("old-dimension queries must be rejected after the swap")
+	}
+}
@@ -0,0 +1,228 @@
+// Package main implements kg-transfer, a CLI that exports a tenant's knowledge
+// graph to JSONL/GraphML/Cypher/CSV or imports a JSONL/GraphML file back.
+//
+// It talks to the metadata database directly (METADATA_DATABASE_URL or
+// DATABASE_URL), the same tables KgService serves.
+//
+//	kg-transfer export -tenant t1 -format graphml -out graph.graphml
+//	kg-transfer export -tenant t1 -format csv -out ./neo4j   # writes nodes.csv + edges.csv
+//	kg-transfer import -tenant t2 -in graph.jsonl
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/nucleus/ucl-core/internal/gateway"
+	"github.com/nucleus/ucl-core/pkg/kgtransfer"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kg-transfer: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: kg-transfer export|import [flags]")
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	tenant := fs.String("tenant", "", "tenant id (required)")
+	project := fs.String("project", "", "project id")
+	entityTypes := fs.String("entity-types", "", "comma-separated entity types")
+	edgeTypes := fs.String("edge-types", "", "comma-separated edge types")
+	since := fs.String("since", "", "only records updated at or after this RFC3339 time")
+	until := fs.String("until", "", "only records updated before this RFC3339 time")
+	formatName := fs.String("format", "jsonl", "jsonl, graphml, cypher or csv")
+	out := fs.String("out", "-", "output file, '-' for stdout, or a directory for csv")
+	_ = fs.Parse(args)
+
+	if *tenant == "" {
+		return fmt.Errorf("-tenant is required")
+	}
+	format, err := kgtransfer.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	filter := kgtransfer.Filter{
+		TenantID:    *tenant,
+		ProjectID:   *project,
+		EntityTypes: splitList(*entityTypes),
+		EdgeTypes:   splitList(*edgeTypes),
+	}
+	if filter.UpdatedAfter, err = parseTime(*since); err != nil {
+		return fmt.Errorf("-since: %w", err)
+	}
+	if filter.UpdatedBefore, err = parseTime(*until); err != nil {
+		return fmt.Errorf("-until: %w", err)
+	}
+
+	parts, closeAll, err := openParts(format, *out)
+	if err != nil {
+		return err
+	}
+	defer closeAll()
+
+	ctx := context.Background()
+	pool, err := openPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	stats, err := kgtransfer.Export(ctx, gateway.NewKgTransferStore(pool), filter, format, parts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d nodes, %d edges\n", stats.Nodes, stats.Edges)
+	return nil
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	tenant := fs.String("tenant", "", "target tenant id (required)")
+	project := fs.String("project", "", "target project id")
+	formatName := fs.String("format", "", "jsonl or graphml (default: from file extension)")
+	in := fs.String("in", "-", "input file, '-' for stdin")
+	_ = fs.Parse(args)
+
+	if *tenant == "" {
+		return fmt.Errorf("-tenant is required")
+	}
+	name := *formatName
+	if name == "" && *in != "-" {
+		name = strings.TrimPrefix(filepath.Ext(*in), ".")
+	}
+	format, err := kgtransfer.ParseFormat(name)
+	if err != nil {
+		return err
+	}
+	if !format.Importable() {
+		return fmt.Errorf("format %q cannot be imported", format)
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	ctx := context.Background()
+	pool, err := openPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	stats, err := kgtransfer.Import(ctx, r, format, *tenant, *project, gateway.NewKgTransferStore(pool))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "imported %d nodes, %d edges\n", stats.Nodes, stats.Edges)
+	if stats.FailedNodes+stats.FailedEdges > 0 {
+		for _, e := range stats.Errors {
+			fmt.Fprintln(os.Stderr, "  "+e)
+		}
+		return fmt.Errorf("%d nodes and %d edges failed to import", stats.FailedNodes, stats.FailedEdges)
+	}
+	return nil
+}
+
+// openParts maps export parts to files. CSV needs a directory (nodes.csv and
+// edges.csv); other formats write a single file or stdout.
+func openParts(format kgtransfer.Format, out string) (kgtransfer.PartWriter, func(), error) {
+	if format != kgtransfer.FormatCSV {
+		if out == "-" {
+			return func(string) io.Writer { return os.Stdout }, func() {}, nil
+		}
+		f, err := os.Create(out)
+		if err != nil {
+			return nil, nil, err
+		}
+		return func(string) io.Writer { return f }, func() { f.Close() }, nil
+	}
+	if out == "-" {
+		return nil, nil, fmt.Errorf("csv export needs -out <directory>")
+	}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return nil, nil, err
+	}
+	files := map[string]*os.File{}
+	for _, part := range []string{kgtransfer.PartNodes, kgtransfer.PartEdges} {
+		f, err := os.Create(filepath.Join(out, part+".csv"))
+		if err != nil {
+			for _, open := range files {
+				open.Close()
+			}
+			return nil, nil, err
+		}
+		files[part] = f
+	}
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+	return func(part string) io.Writer { return files[part] }, closeAll, nil
+}
+
+func openPool(ctx context.Context) (*pgxpool.Pool, error) {
+	dsn := os.Getenv("METADATA_DATABASE_URL")
+	if strings.TrimSpace(dsn) == "" {
+		dsn = os.Getenv("DATABASE_URL")
+	}
+	if strings.TrimSpace(dsn) == "" {
+		return nil, fmt.Errorf("METADATA_DATABASE_URL or DATABASE_URL must be set")
+	}
+	return pgxpool.New(ctx, dsn)
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func parseTime(v string) (*time.Time, error) {
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -3,8 +3,6 @@ package gateway
 import (
 	"context"
 	"fmt"
-	"strings"
-	"sync"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	kgpb "github.com/nucleus/ucl-core/pkg/kgpb"
@@ -68,6 +66,32 @@ func (s *kgService) GetNode(ctx context.Context, req *kgpb.GetNodeRequest) (*kgp
 	return &kgpb.GetNodeResponse{Node: node}, nil
 }
 
+// DeleteNode removes a node and every edge touching it.
+func (s *kgService) DeleteNode(ctx context.Context, req *kgpb.DeleteNodeRequest) (*kgpb.DeleteNodeResponse, error) {
+	if req == nil || req.NodeId == "" {
+		return nil, fmt.Errorf("node_id is required")
+	}
+	if s.repo != nil {
+		if resp, err := s.repo.deleteNode(ctx, req); err == nil {
+			return resp, nil
+		}
+	}
+	deleted, edges := s.store.deleteNode(req.TenantId, req.ProjectId, req.NodeId)
+	return &kgpb.DeleteNodeResponse{Deleted: deleted, EdgesDeleted: int32(edges)}, nil
+}
+
+func (s *kgService) DeleteEdge(ctx context.Context, req *kgpb.DeleteEdgeRequest) (*kgpb.DeleteEdgeResponse, error) {
+	if req == nil || req.EdgeId == "" {
+		return nil, fmt.Errorf("edge_id is required")
+	}
+	if s.repo != nil {
+		if resp, err := s.repo.deleteEdge(ctx, req); err == nil {
+			return resp, nil
+		}
+	}
+	return &kgpb.DeleteEdgeResponse{Deleted: s.store.deleteEdge(req.TenantId, req.ProjectId, req.EdgeId)}, nil
+}
+
 func (s *kgService) ListNeighbors(ctx context.Context, req *kgpb.ListNeighborsRequest) (*kgpb.ListNeighborsResponse, error) {
 	_ = ctx
 	if req == nil {
@@ -110,156 +134,52 @@ func (s *kgService) ListEdges(ctx context.Context, req *kgpb.ListEdgesRequest) (
 	return &kgpb.ListEdgesResponse{Edges: edges}, nil
 }
 
-// in-memory KG store (per-tenant/project). This is a placeholder.
-type kgMemoryStore struct {
-	mu     sync.RWMutex
-	nodes  map[string]*kgpb.Node
-	edges  map[string]*kgpb.Edge
-	indexE map[string][]*kgpb.Edge // nodeId -> edges touching it
-}
-
-func newKgMemoryStore() *kgMemoryStore {
-	return &kgMemoryStore{
-		nodes:  make(map[string]*kgpb.Node),
-		edges:  make(map[string]*kgpb.Edge),
-		indexE: make(map[string][]*kgpb.Edge),
-	}
-}
-
-func keyTenantProject(tenant, project string) string {
-	return tenant + "::" + project
-}
-
-func (s *kgMemoryStore) nodeKey(tenant, project, id string) string {
-	return keyTenantProject(tenant, project) + "::node::" + id
-}
-
-func (s *kgMemoryStore) edgeKey(tenant, project, id string) string {
-	return keyTenantProject(tenant, project) + "::edge::" + id
-}
-
-func (s *kgMemoryStore) upsertNode(tenant, project string, node *kgpb.Node) *kgpb.Node {
-	if node == nil {
-		return nil
-	}
-	k := s.nodeKey(tenant, project, node.Id)
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.nodes[k] = node
-	return node
-}
-
-func (s *kgMemoryStore) getNode(tenant, project, id string) *kgpb.Node {
-	if id == "" {
-		return nil
-	}
-	k := s.nodeKey(tenant, project, id)
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	if n, ok := s.nodes[k]; ok {
-		return n
+// Traverse walks up to max_hops from the seed nodes in a single call.
+func (s *kgService) Traverse(ctx context.Context, req *kgpb.TraverseRequest) (*kgpb.TraverseResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request is required")
 	}
-	return nil
-}
-
-func (s *kgMemoryStore) upsertEdge(tenant, project string, edge *kgpb.Edge) *kgpb.Edge {
-	if edge == nil {
-		return nil
+	if s.repo != nil {
+		if resp, err := s.repo.traverse(ctx, req); err == nil {
+			return resp, nil
+		}
 	}
-	k := s.edgeKey(tenant, project, edge.Id)
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.edges[k] = edge
-	s.indexE[edge.FromId] = append(s.indexE[edge.FromId], edge)
-	s.indexE[edge.ToId] = append(s.indexE[edge.ToId], edge)
-	return edge
+	return s.store.traverse(req), nil
 }
 
-func (s *kgMemoryStore) listNodes(tenant, project string, types []string, limit int) []*kgpb.Node {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	typeSet := map[string]struct{}{}
-	for _, t := range types {
-		typeSet[t] = struct{}{}
+// ShortestPath returns one shortest path between two nodes within max_hops.
+func (s *kgService) ShortestPath(ctx context.Context, req *kgpb.PathRequest) (*kgpb.ShortestPathResponse, error) {
+	if req == nil || req.FromId == "" || req.ToId == "" {
+		return nil, fmt.Errorf("from_id and to_id are required")
 	}
-	var out []*kgpb.Node
-	for k, n := range s.nodes {
-		if !strings.HasPrefix(k, keyTenantProject(tenant, project)) {
-			continue
-		}
-		if len(typeSet) > 0 {
-			if _, ok := typeSet[n.Type]; !ok {
-				continue
-			}
-		}
-		out = append(out, n)
-		if limit > 0 && len(out) >= limit {
-			break
+	if s.repo != nil {
+		if resp, err := s.repo.shortestPath(ctx, req); err == nil {
+			return resp, nil
 		}
 	}
-	return out
-}
-
-func (s *kgMemoryStore) listEdges(tenant, project string, types []string, sourceID, targetID string, limit int) []*kgpb.Edge {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	typeSet := map[string]struct{}{}
-	for _, t := range types {
-		typeSet[t] = struct{}{}
+	paths, _, err := s.store.findPaths(req, 1)
+	if err != nil {
+		return nil, err
 	}
-	var out []*kgpb.Edge
-	for k, e := range s.edges {
-		if !strings.HasPrefix(k, keyTenantProject(tenant, project)) {
-			continue
-		}
-		if len(typeSet) > 0 {
-			if _, ok := typeSet[e.Type]; !ok {
-				continue
-			}
-		}
-		if sourceID != "" && e.FromId != sourceID {
-			continue
-		}
-		if targetID != "" && e.ToId != targetID {
-			continue
-		}
-		out = append(out, e)
-		if limit > 0 && len(out) >= limit {
-			break
-		}
+	if len(paths) == 0 {
+		return &kgpb.ShortestPathResponse{}, nil
 	}
-	return out
+	return &kgpb.ShortestPathResponse{Path: paths[0]}, nil
 }
 
-func (s *kgMemoryStore) listNeighbors(tenant, project, nodeID string, edgeTypes []string, limit int) []*kgpb.Node {
-	if nodeID == "" {
-		return nil
-	}
-	typeSet := map[string]struct{}{}
-	for _, t := range edgeTypes {
-		typeSet[t] = struct{}{}
+// AllPaths returns up to max_paths simple paths between two nodes, shortest first.
+func (s *kgService) AllPaths(ctx context.Context, req *kgpb.PathRequest) (*kgpb.AllPathsResponse, error) {
+	if req == nil || req.FromId == "" || req.ToId == "" {
+		return nil, fmt.Errorf("from_id and to_id are required")
 	}
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	edges := s.indexE[nodeID]
-	var out []*kgpb.Node
-	for _, e := range edges {
-		if len(typeSet) > 0 {
-			if _, ok := typeSet[e.Type]; !ok {
-				continue
-			}
-		}
-		other := e.FromId
-		if other == nodeID {
-			other = e.ToId
-		}
-		nKey := s.nodeKey(tenant, project, other)
-		if n, ok := s.nodes[nKey]; ok {
-			out = append(out, n)
-			if limit > 0 && len(out) >= limit {
-				break
-			}
+	if s.repo != nil {
+		if resp, err := s.repo.allPaths(ctx, req); err == nil {
+			return resp, nil
 		}
 	}
-	return out
+	paths, truncated, err := s.store.findPaths(req, clampInt(int(req.MaxPaths), defaultMaxPaths, maxPathsLimit))
+	if err != nil {
+		return nil, err
+	}
+	return &kgpb.AllPathsResponse{Paths: paths, Truncated: truncated}, nil
 }
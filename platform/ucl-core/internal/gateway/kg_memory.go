@@ -0,0 +1,375 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	kgpb "github.com/nucleus/ucl-core/pkg/kgpb"
+)
+
+// kgMemoryRepo implements kgRepository in process memory with the same
+// semantics as kgPostgresRepo. It backs hermetic tests and local runs
+// without a metadata database.
+type kgMemoryRepo struct {
+	store *kgMemoryStore
+}
+
+func newKgMemoryRepo() kgRepository {
+	return &kgMemoryRepo{store: newKgMemoryStore()}
+}
+
+func (r *kgMemoryRepo) upsertNode(_ context.Context, req *kgpb.UpsertNodeRequest) (*kgpb.Node, error) {
+	if req.Node == nil || req.Node.Id == "" {
+		return nil, fmt.Errorf("node.id is required")
+	}
+	return r.store.upsertNode(req.TenantId, req.ProjectId, req.Node), nil
+}
+
+func (r *kgMemoryRepo) upsertEdge(_ context.Context, req *kgpb.UpsertEdgeRequest) (*kgpb.Edge, error) {
+	if req.Edge == nil || req.Edge.Id == "" {
+		return nil, fmt.Errorf("edge.id is required")
+	}
+	return r.store.upsertEdge(req.TenantId, req.ProjectId, req.Edge), nil
+}
+
+func (r *kgMemoryRepo) getNode(_ context.Context, req *kgpb.GetNodeRequest) (*kgpb.Node, error) {
+	return r.store.getNode(req.TenantId, req.ProjectId, req.NodeId), nil
+}
+
+func (r *kgMemoryRepo) deleteNode(_ context.Context, req *kgpb.DeleteNodeRequest) (*kgpb.DeleteNodeResponse, error) {
+	if req.NodeId == "" {
+		return nil, fmt.Errorf("node_id is required")
+	}
+	deleted, edges := r.store.deleteNode(req.TenantId, req.ProjectId, req.NodeId)
+	return &kgpb.DeleteNodeResponse{Deleted: deleted, EdgesDeleted: int32(edges)}, nil
+}
+
+func (r *kgMemoryRepo) deleteEdge(_ context.Context, req *kgpb.DeleteEdgeRequest) (*kgpb.DeleteEdgeResponse, error) {
+	if req.EdgeId == "" {
+		return nil, fmt.Errorf("edge_id is required")
+	}
+	return &kgpb.DeleteEdgeResponse{Deleted: r.store.deleteEdge(req.TenantId, req.ProjectId, req.EdgeId)}, nil
+}
+
+func (r *kgMemoryRepo) listNodes(_ context.Context, req *kgpb.ListEntitiesRequest) ([]*kgpb.Node, error) {
+	return r.store.listNodes(req.TenantId, req.ProjectId, req.EntityTypes, defaultLimit(req.Limit, 100)), nil
+}
+
+func (r *kgMemoryRepo) listEdges(_ context.Context, req *kgpb.ListEdgesRequest) ([]*kgpb.Edge, error) {
+	return r.store.listEdges(req.TenantId, req.ProjectId, req.EdgeTypes, req.SourceId, req.TargetId, defaultLimit(req.Limit, 100)), nil
+}
+
+func (r *kgMemoryRepo) listNeighbors(_ context.Context, req *kgpb.ListNeighborsRequest) ([]*kgpb.Node, error) {
+	return r.store.listNeighbors(req.TenantId, req.ProjectId, req.NodeId, req.EdgeTypes, defaultLimit(req.Limit, 25)), nil
+}
+
+func (r *kgMemoryRepo) traverse(_ context.Context, req *kgpb.TraverseRequest) (*kgpb.TraverseResponse, error) {
+	return r.store.traverse(req), nil
+}
+
+func (r *kgMemoryRepo) shortestPath(_ context.Context, req *kgpb.PathRequest) (*kgpb.ShortestPathResponse, error) {
+	paths, _, err := r.store.findPaths(req, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return &kgpb.ShortestPathResponse{}, nil
+	}
+	return &kgpb.ShortestPathResponse{Path: paths[0]}, nil
+}
+
+func (r *kgMemoryRepo) allPaths(_ context.Context, req *kgpb.PathRequest) (*kgpb.AllPathsResponse, error) {
+	paths, truncated, err := r.store.findPaths(req, clampInt(int(req.MaxPaths), defaultMaxPaths, maxPathsLimit))
+	if err != nil {
+		return nil, err
+	}
+	return &kgpb.AllPathsResponse{Paths: paths, Truncated: truncated}, nil
+}
+
+func defaultLimit(limit int32, def int) int {
+	if limit <= 0 {
+		return def
+	}
+	return int(limit)
+}
+
+// =============================================================================
+// kgMemoryStore
+// =============================================================================
+
+// kgMemoryStore holds a tenant-scoped graph. Like graph_nodes/graph_edges a
+// record without a project is visible from every project, and an empty
+// project filter matches all projects. Listings are newest first, mirroring
+// ORDER BY updated_at DESC. Limits <= 0 mean unlimited.
+type kgMemoryStore struct {
+	mu        sync.RWMutex
+	seq       uint64
+	nodes     map[string]*memoryNode         // tenant::id
+	edges     map[string]*memoryEdge         // tenant::id
+	logical   map[string]string              // tenant::from::to::type -> edge id
+	adjacency map[string]map[string]struct{} // tenant::nodeId -> ids of edges touching it
+}
+
+type memoryNode struct {
+	tenant  string
+	project string
+	seq     uint64
+	node    *kgpb.Node
+}
+
+type memoryEdge struct {
+	tenant  string
+	project string
+	seq     uint64
+	edge    *kgpb.Edge
+}
+
+func newKgMemoryStore() *kgMemoryStore {
+	return &kgMemoryStore{
+		nodes:     make(map[string]*memoryNode),
+		edges:     make(map[string]*memoryEdge),
+		logical:   make(map[string]string),
+		adjacency: make(map[string]map[string]struct{}),
+	}
+}
+
+func scopedKey(tenant string, parts ...string) string {
+	k := tenant
+	for _, p := range parts {
+		k += "::" + p
+	}
+	return k
+}
+
+func inProject(recordProject, project string) bool {
+	return project == "" || recordProject == "" || recordProject == project
+}
+
+func copyProps(props map[string]string) map[string]string {
+	out := make(map[string]string, len(props))
+	for k, v := range props {
+		out[k] = v
+	}
+	return out
+}
+
+func (s *kgMemoryStore) upsertNode(tenant, project string, node *kgpb.Node) *kgpb.Node {
+	if node == nil {
+		return nil
+	}
+	stored := &kgpb.Node{Id: node.Id, Type: node.Type, Properties: copyProps(node.Properties)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	s.nodes[scopedKey(tenant, node.Id)] = &memoryNode{tenant: tenant, project: project, seq: s.seq, node: stored}
+	return stored
+}
+
+func (s *kgMemoryStore) getNode(tenant, project, id string) *kgpb.Node {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lookupNode(tenant, project, id)
+}
+
+// lookupNode returns the node when it is visible from project. Caller must
+// hold s.mu.
+func (s *kgMemoryStore) lookupNode(tenant, project, id string) *kgpb.Node {
+	if id == "" {
+		return nil
+	}
+	n, ok := s.nodes[scopedKey(tenant, id)]
+	if !ok || !inProject(n.project, project) {
+		return nil
+	}
+	return n.node
+}
+
+// upsertEdge stores edge. As with the graph_edges unique constraint, an edge
+// with the same endpoints and type as an existing one updates that edge and
+// keeps its id.
+func (s *kgMemoryStore) upsertEdge(tenant, project string, edge *kgpb.Edge) *kgpb.Edge {
+	if edge == nil {
+		return nil
+	}
+	stored := &kgpb.Edge{Id: edge.Id, Type: edge.Type, FromId: edge.FromId, ToId: edge.ToId, Properties: copyProps(edge.Properties)}
+	logicalKey := scopedKey(tenant, edge.FromId, edge.ToId, edge.Type)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.logical[logicalKey]; ok {
+		stored.Id = existing
+	}
+	if prev, ok := s.edges[scopedKey(tenant, stored.Id)]; ok {
+		s.unindexEdge(tenant, prev.edge)
+	}
+	s.seq++
+	s.edges[scopedKey(tenant, stored.Id)] = &memoryEdge{tenant: tenant, project: project, seq: s.seq, edge: stored}
+	s.logical[logicalKey] = stored.Id
+	for _, id := range []string{stored.FromId, stored.ToId} {
+		k := scopedKey(tenant, id)
+		if s.adjacency[k] == nil {
+			s.adjacency[k] = map[string]struct{}{}
+		}
+		s.adjacency[k][stored.Id] = struct{}{}
+	}
+	return stored
+}
+
+// deleteNode removes the node when it is visible from project, together with
+// every edge of the tenant touching it, and reports how many edges went.
+func (s *kgMemoryStore) deleteNode(tenant, project, id string) (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lookupNode(tenant, project, id) == nil {
+		return false, 0
+	}
+	edges := 0
+	for edgeID := range s.adjacency[scopedKey(tenant, id)] {
+		if e, ok := s.edges[scopedKey(tenant, edgeID)]; ok {
+			s.unindexEdge(tenant, e.edge)
+			delete(s.edges, scopedKey(tenant, edgeID))
+			edges++
+		}
+	}
+	delete(s.adjacency, scopedKey(tenant, id))
+	delete(s.nodes, scopedKey(tenant, id))
+	return true, edges
+}
+
+func (s *kgMemoryStore) deleteEdge(tenant, project, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.edges[scopedKey(tenant, id)]
+	if !ok || !inProject(e.project, project) {
+		return false
+	}
+	s.unindexEdge(tenant, e.edge)
+	delete(s.edges, scopedKey(tenant, id))
+	return true
+}
+
+func (s *kgMemoryStore) unindexEdge(tenant string, e *kgpb.Edge) {
+	delete(s.logical, scopedKey(tenant, e.FromId, e.ToId, e.Type))
+	for _, id := range []string{e.FromId, e.ToId} {
+		delete(s.adjacency[scopedKey(tenant, id)], e.Id)
+	}
+}
+
+// edgesOf lists the edges touching nodeID that are visible from project,
+// ordered by id. Caller must hold s.mu.
+func (s *kgMemoryStore) edgesOf(tenant, project, nodeID string) []*memoryEdge {
+	ids := s.adjacency[scopedKey(tenant, nodeID)]
+	out := make([]*memoryEdge, 0, len(ids))
+	for id := range ids {
+		if e, ok := s.edges[scopedKey(tenant, id)]; ok && inProject(e.project, project) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].edge.Id < out[j].edge.Id })
+	return out
+}
+
+func (s *kgMemoryStore) listNodes(tenant, project string, types []string, limit int) []*kgpb.Node {
+	typeSet := toTypeSet(types)
+	s.mu.RLock()
+	var matched []*memoryNode
+	for _, n := range s.nodes {
+		if n.tenant != tenant || !inProject(n.project, project) {
+			continue
+		}
+		if len(typeSet) > 0 {
+			if _, ok := typeSet[n.node.Type]; !ok {
+				continue
+			}
+		}
+		matched = append(matched, n)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].seq > matched[j].seq })
+	out := make([]*kgpb.Node, 0, len(matched))
+	for _, n := range matched {
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		out = append(out, n.node)
+	}
+	return out
+}
+
+func (s *kgMemoryStore) listEdges(tenant, project string, types []string, sourceID, targetID string, limit int) []*kgpb.Edge {
+	typeSet := toTypeSet(types)
+	s.mu.RLock()
+	var matched []*memoryEdge
+	for _, e := range s.edges {
+		if e.tenant != tenant || !inProject(e.project, project) {
+			continue
+		}
+		if len(typeSet) > 0 {
+			if _, ok := typeSet[e.edge.Type]; !ok {
+				continue
+			}
+		}
+		if sourceID != "" && e.edge.FromId != sourceID {
+			continue
+		}
+		if targetID != "" && e.edge.ToId != targetID {
+			continue
+		}
+		matched = append(matched, e)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].seq > matched[j].seq })
+	out := make([]*kgpb.Edge, 0, len(matched))
+	for _, e := range matched {
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		out = append(out, e.edge)
+	}
+	return out
+}
+
+func (s *kgMemoryStore) listNeighbors(tenant, project, nodeID string, edgeTypes []string, limit int) []*kgpb.Node {
+	if nodeID == "" {
+		return nil
+	}
+	typeSet := toTypeSet(edgeTypes)
+	s.mu.RLock()
+	seen := map[string]bool{}
+	var matched []*memoryNode
+	for _, e := range s.edgesOf(tenant, project, nodeID) {
+		if len(typeSet) > 0 {
+			if _, ok := typeSet[e.edge.Type]; !ok {
+				continue
+			}
+		}
+		other := e.edge.FromId
+		if other == nodeID {
+			other = e.edge.ToId
+		}
+		if seen[other] {
+			continue
+		}
+		if n, ok := s.nodes[scopedKey(tenant, other)]; ok && inProject(n.project, project) {
+			seen[other] = true
+			matched = append(matched, n)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].seq > matched[j].seq })
+	out := make([]*kgpb.Node, 0, len(matched))
+	for _, n := range matched {
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		out = append(out, n.node)
+	}
+	return out
+}
+
+var _ kgRepository = (*kgMemoryRepo)(nil)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -14,9 +15,14 @@ type kgRepository interface {
 	upsertNode(ctx context.Context, req *kgpb.UpsertNodeRequest) (*kgpb.Node, error)
 	upsertEdge(ctx context.Context, req *kgpb.UpsertEdgeRequest) (*kgpb.Edge, error)
 	getNode(ctx context.Context, req *kgpb.GetNodeRequest) (*kgpb.Node, error)
+	deleteNode(ctx context.Context, req *kgpb.DeleteNodeRequest) (*kgpb.DeleteNodeResponse, error)
+	deleteEdge(ctx context.Context, req *kgpb.DeleteEdgeRequest) (*kgpb.DeleteEdgeResponse, error)
 	listNodes(ctx context.Context, req *kgpb.ListEntitiesRequest) ([]*kgpb.Node, error)
 	listEdges(ctx context.Context, req *kgpb.ListEdgesRequest) ([]*kgpb.Edge, error)
 	listNeighbors(ctx context.Context, req *kgpb.ListNeighborsRequest) ([]*kgpb.Node, error)
+	traverse(ctx context.Context, req *kgpb.TraverseRequest) (*kgpb.TraverseResponse, error)
+	shortestPath(ctx context.Context, req *kgpb.PathRequest) (*kgpb.ShortestPathResponse, error)
+	allPaths(ctx context.Context, req *kgpb.PathRequest) (*kgpb.AllPathsResponse, error)
 }
 
 type kgPostgresRepo struct {
@@ -196,6 +202,50 @@ func (r *kgPostgresRepo) getNode(ctx context.Context, req *kgpb.GetNodeRequest)
 	return &kgpb.Node{Id: id, Type: entityType, Properties: props}, nil
 }
 
+// deleteNode removes the node and, in the same statement, every edge of the
+// tenant that starts or ends at it.
+func (r *kgPostgresRepo) deleteNode(ctx context.Context, req *kgpb.DeleteNodeRequest) (*kgpb.DeleteNodeResponse, error) {
+	if req.NodeId == "" {
+		return nil, fmt.Errorf("node_id is required")
+	}
+	filter := ""
+	args := []any{req.TenantId, req.NodeId}
+	if req.ProjectId != "" {
+		filter = " AND (project_id = $3 OR project_id IS NULL)"
+		args = append(args, req.ProjectId)
+	}
+	stmt := `WITH n AS (
+  DELETE FROM graph_nodes WHERE tenant_id=$1 AND id=$2` + filter + ` RETURNING id
+), e AS (
+  DELETE FROM graph_edges
+  WHERE tenant_id=$1 AND (source_entity_id IN (SELECT id FROM n) OR target_entity_id IN (SELECT id FROM n))
+  RETURNING id
+)
+SELECT (SELECT count(*) FROM n), (SELECT count(*) FROM e)`
+	var nodes, edges int32
+	if err := r.db.QueryRow(ctx, stmt, args...).Scan(&nodes, &edges); err != nil {
+		return nil, err
+	}
+	return &kgpb.DeleteNodeResponse{Deleted: nodes > 0, EdgesDeleted: edges}, nil
+}
+
+func (r *kgPostgresRepo) deleteEdge(ctx context.Context, req *kgpb.DeleteEdgeRequest) (*kgpb.DeleteEdgeResponse, error) {
+	if req.EdgeId == "" {
+		return nil, fmt.Errorf("edge_id is required")
+	}
+	stmt := `DELETE FROM graph_edges WHERE tenant_id=$1 AND id=$2`
+	args := []any{req.TenantId, req.EdgeId}
+	if req.ProjectId != "" {
+		stmt += " AND (project_id = $3 OR project_id IS NULL)"
+		args = append(args, req.ProjectId)
+	}
+	tag, err := r.db.Exec(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &kgpb.DeleteEdgeResponse{Deleted: tag.RowsAffected() > 0}, nil
+}
+
 func (r *kgPostgresRepo) listNodes(ctx context.Context, req *kgpb.ListEntitiesRequest) ([]*kgpb.Node, error) {
 	limit := req.Limit
 	if limit <= 0 {
@@ -285,11 +335,13 @@ func (r *kgPostgresRepo) listNeighbors(ctx context.Context, req *kgpb.ListNeighb
 	if limit <= 0 {
 		limit = 25
 	}
-	whereEdges := []string{"e.tenant_id = $1"}
+	whereEdges := []string{"e.tenant_id = $1", "n.tenant_id = $1"}
 	args := []any{req.TenantId}
 	argIdx := 2
 	if req.ProjectId != "" {
-		whereEdges = append(whereEdges, fmt.Sprintf("(e.project_id = $%d OR e.project_id IS NULL)", argIdx))
+		// Both the edge and the neighbour must be visible from the project.
+		whereEdges = append(whereEdges, fmt.Sprintf("(e.project_id = $%d OR e.project_id IS NULL)", argIdx),
+			fmt.Sprintf("(n.project_id = $%d OR n.project_id IS NULL)", argIdx))
 		args = append(args, req.ProjectId)
 		argIdx++
 	}
@@ -301,7 +353,7 @@ func (r *kgPostgresRepo) listNeighbors(ctx context.Context, req *kgpb.ListNeighb
 	whereEdges = append(whereEdges, fmt.Sprintf("(e.source_entity_id = $%d OR e.target_entity_id = $%d)", argIdx, argIdx))
 	args = append(args, req.NodeId)
 	stmt := fmt.Sprintf(`
-SELECT DISTINCT n.id, n.entity_type, n.display_name, n.properties
+SELECT DISTINCT n.id, n.entity_type, n.display_name, n.properties, n.updated_at
 FROM graph_edges e
 JOIN graph_nodes n
   ON (n.id = CASE WHEN e.source_entity_id = $%d THEN e.target_entity_id ELSE e.source_entity_id END)
@@ -318,7 +370,8 @@ LIMIT %d;`, argIdx, strings.Join(whereEdges, " AND "), limit)
 	for rows.Next() {
 		var id, etype, display string
 		var props map[string]string
-		if err := rows.Scan(&id, &etype, &display, &props); err != nil {
+		var updatedAt time.Time
+		if err := rows.Scan(&id, &etype, &display, &props, &updatedAt); err != nil {
 			return nil, err
 		}
 		out = append(out, &kgpb.Node{Id: id, Type: etype, Properties: props})
@@ -0,0 +1,200 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	kgpb "github.com/nucleus/ucl-core/pkg/kgpb"
+)
+
+// TestKgRepositoryConformance runs the same checks against every
+// kgRepository. The Postgres run needs KG_TEST_DATABASE_URL pointing at a
+// migrated metadata database.
+func TestKgRepositoryConformance(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testKgRepository(t, newKgMemoryRepo())
+	})
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv("KG_TEST_DATABASE_URL")
+		if dsn == "" {
+			t.Skip("KG_TEST_DATABASE_URL not set")
+		}
+		pool, err := pgxpool.New(context.Background(), dsn)
+		if err != nil {
+			t.Fatalf("open postgres: %v", err)
+		}
+		defer pool.Close()
+		testKgRepository(t, newKgPostgresRepo(pool))
+	})
+}
+
+func testKgRepository(t *testing.T, repo kgRepository) {
+	ctx := context.Background()
+	// Node ids are globally unique in graph_nodes, so namespace them per run.
+	tenant := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+	const project = "p1"
+	id := func(name string) string { return tenant + ":" + name }
+	names := func(nodes []*kgpb.Node) string {
+		out := make([]string, len(nodes))
+		for i, n := range nodes {
+			out[i] = n.Id[len(tenant)+1:]
+		}
+		sort.Strings(out)
+		return fmt.Sprint(out)
+	}
+
+	if _, err := repo.upsertNode(ctx, &kgpb.UpsertNodeRequest{TenantId: tenant, Node: &kgpb.Node{}}); err == nil {
+		t.Fatalf("expected error for node without id")
+	}
+	for _, n := range []struct{ name, typ string }{
+		{"pr", "github.pr"}, {"commit", "github.commit"}, {"incident", "pagerduty.incident"}, {"doc", "confluence.page"},
+	} {
+		if _, err := repo.upsertNode(ctx, &kgpb.UpsertNodeRequest{TenantId: tenant, ProjectId: project,
+			Node: &kgpb.Node{Id: id(n.name), Type: n.typ, Properties: map[string]string{"displayName": n.name}}}); err != nil {
+			t.Fatalf("upsert node %s: %v", n.name, err)
+		}
+	}
+	node, err := repo.upsertNode(ctx, &kgpb.UpsertNodeRequest{TenantId: tenant, ProjectId: project,
+		Node: &kgpb.Node{Id: id("pr"), Type: "github.pr", Properties: map[string]string{"displayName": "PR 42"}}})
+	if err != nil || node.Properties["displayName"] != "PR 42" {
+		t.Fatalf("re-upsert node: %+v err=%v", node, err)
+	}
+
+	got, err := repo.getNode(ctx, &kgpb.GetNodeRequest{TenantId: tenant, NodeId: id("pr")})
+	if err != nil || got == nil || got.Type != "github.pr" || got.Properties["displayName"] != "PR 42" {
+		t.Fatalf("get node across projects: %+v err=%v", got, err)
+	}
+	if got, err := repo.getNode(ctx, &kgpb.GetNodeRequest{TenantId: tenant, ProjectId: "p2", NodeId: id("pr")}); err != nil || got != nil {
+		t.Fatalf("expected node hidden from other project, got %+v err=%v", got, err)
+	}
+	if got, err := repo.getNode(ctx, &kgpb.GetNodeRequest{TenantId: tenant, NodeId: id("missing")}); err != nil || got != nil {
+		t.Fatalf("expected nil for missing node, got %+v err=%v", got, err)
+	}
+
+	nodes, err := repo.listNodes(ctx, &kgpb.ListEntitiesRequest{TenantId: tenant, ProjectId: project, EntityTypes: []string{"github.pr", "github.commit"}})
+	if err != nil || names(nodes) != "[commit pr]" {
+		t.Fatalf("list nodes by type: %s err=%v", names(nodes), err)
+	}
+	if nodes, _ := repo.listNodes(ctx, &kgpb.ListEntitiesRequest{TenantId: tenant, Limit: 1}); names(nodes) != "[pr]" {
+		t.Fatalf("list nodes should return the most recently updated first: %s", names(nodes))
+	}
+
+	for _, e := range []struct{ name, from, to, typ string }{
+		{"e1", "pr", "commit", "CONTAINS"},
+		{"e2", "commit", "incident", "CAUSED"},
+		{"e3", "doc", "pr", "DOCUMENTS"},
+	} {
+		if _, err := repo.upsertEdge(ctx, &kgpb.UpsertEdgeRequest{TenantId: tenant, ProjectId: project,
+			Edge: &kgpb.Edge{Id: id(e.name), Type: e.typ, FromId: id(e.from), ToId: id(e.to)}}); err != nil {
+			t.Fatalf("upsert edge %s: %v", e.name, err)
+		}
+	}
+	// Same endpoints and type: the existing edge is updated and keeps its id.
+	edge, err := repo.upsertEdge(ctx, &kgpb.UpsertEdgeRequest{TenantId: tenant, ProjectId: project,
+		Edge: &kgpb.Edge{Id: id("e1-dup"), Type: "CONTAINS", FromId: id("pr"), ToId: id("commit"), Properties: map[string]string{"confidence": "0.9"}}})
+	if err != nil || edge.Id != id("e1") || edge.Properties["confidence"] != "0.9" {
+		t.Fatalf("edge dedupe: %+v err=%v", edge, err)
+	}
+
+	edges, err := repo.listEdges(ctx, &kgpb.ListEdgesRequest{TenantId: tenant, ProjectId: project})
+	if err != nil || len(edges) != 3 {
+		t.Fatalf("list edges: %d err=%v", len(edges), err)
+	}
+	if edges, _ := repo.listEdges(ctx, &kgpb.ListEdgesRequest{TenantId: tenant, SourceId: id("commit")}); len(edges) != 1 || edges[0].Id != id("e2") {
+		t.Fatalf("list edges by source: %+v", edges)
+	}
+	if edges, _ := repo.listEdges(ctx, &kgpb.ListEdgesRequest{TenantId: tenant, TargetId: id("pr"), EdgeTypes: []string{"DOCUMENTS"}}); len(edges) != 1 || edges[0].Id != id("e3") {
+		t.Fatalf("list edges by target and type: %+v", edges)
+	}
+	if edges, _ := repo.listEdges(ctx, &kgpb.ListEdgesRequest{TenantId: tenant, EdgeTypes: []string{"NOPE"}}); len(edges) != 0 {
+		t.Fatalf("expected no edges for unknown type: %+v", edges)
+	}
+
+	neighbors, err := repo.listNeighbors(ctx, &kgpb.ListNeighborsRequest{TenantId: tenant, ProjectId: project, NodeId: id("pr")})
+	if err != nil || names(neighbors) != "[commit doc]" {
+		t.Fatalf("neighbors: %s err=%v", names(neighbors), err)
+	}
+	if neighbors, _ := repo.listNeighbors(ctx, &kgpb.ListNeighborsRequest{TenantId: tenant, NodeId: id("pr"), EdgeTypes: []string{"CONTAINS"}}); names(neighbors) != "[commit]" {
+		t.Fatalf("neighbors by edge type: %s", names(neighbors))
+	}
+	if neighbors, _ := repo.listNeighbors(ctx, &kgpb.ListNeighborsRequest{TenantId: "other-tenant", NodeId: id("pr")}); len(neighbors) != 0 {
+		t.Fatalf("neighbors leaked across tenants: %s", names(neighbors))
+	}
+
+	tr, err := repo.traverse(ctx, &kgpb.TraverseRequest{TenantId: tenant, SeedIds: []string{id("doc")}, Direction: "out", MaxHops: 3})
+	if err != nil || len(tr.Nodes) != 4 || len(tr.Edges) != 3 {
+		t.Fatalf("traverse: %+v err=%v", tr, err)
+	}
+	sp, err := repo.shortestPath(ctx, &kgpb.PathRequest{TenantId: tenant, FromId: id("doc"), ToId: id("incident"), Direction: "out"})
+	if err != nil || sp.Path == nil || len(sp.Path.Edges) != 3 {
+		t.Fatalf("shortest path: %+v err=%v", sp, err)
+	}
+
+	// Concurrent writers and readers must not race.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			n := id(fmt.Sprintf("c%d", i))
+			_, _ = repo.upsertNode(ctx, &kgpb.UpsertNodeRequest{TenantId: tenant, ProjectId: project, Node: &kgpb.Node{Id: n, Type: "concurrent"}})
+			_, _ = repo.upsertEdge(ctx, &kgpb.UpsertEdgeRequest{TenantId: tenant, ProjectId: project,
+				Edge: &kgpb.Edge{Id: n + "-edge", Type: "LINKS", FromId: id("pr"), ToId: n}})
+			_, _ = repo.listNeighbors(ctx, &kgpb.ListNeighborsRequest{TenantId: tenant, NodeId: id("pr")})
+		}(i)
+	}
+	wg.Wait()
+	if nodes, _ := repo.listNodes(ctx, &kgpb.ListEntitiesRequest{TenantId: tenant, EntityTypes: []string{"concurrent"}}); len(nodes) != 8 {
+		t.Fatalf("concurrent upserts: %d nodes", len(nodes))
+	}
+
+	if res, err := repo.deleteEdge(ctx, &kgpb.DeleteEdgeRequest{TenantId: tenant, EdgeId: id("e3")}); err != nil || !res.Deleted {
+		t.Fatalf("delete edge: %+v err=%v", res, err)
+	}
+	if res, err := repo.deleteEdge(ctx, &kgpb.DeleteEdgeRequest{TenantId: tenant, EdgeId: id("e3")}); err != nil || res.Deleted {
+		t.Fatalf("expected second delete to be a no-op: %+v err=%v", res, err)
+	}
+	if res, err := repo.deleteNode(ctx, &kgpb.DeleteNodeRequest{TenantId: tenant, ProjectId: "p2", NodeId: id("commit")}); err != nil || res.Deleted {
+		t.Fatalf("expected node hidden from other project to survive: %+v err=%v", res, err)
+	}
+	res, err := repo.deleteNode(ctx, &kgpb.DeleteNodeRequest{TenantId: tenant, ProjectId: project, NodeId: id("commit")})
+	if err != nil || !res.Deleted || res.EdgesDeleted != 2 {
+		t.Fatalf("delete node: %+v err=%v", res, err)
+	}
+	if got, _ := repo.getNode(ctx, &kgpb.GetNodeRequest{TenantId: tenant, NodeId: id("commit")}); got != nil {
+		t.Fatalf("deleted node still readable: %+v", got)
+	}
+	if neighbors, _ := repo.listNeighbors(ctx, &kgpb.ListNeighborsRequest{TenantId: tenant, NodeId: id("pr"), EdgeTypes: []string{"CONTAINS", "DOCUMENTS"}}); len(neighbors) != 0 {
+		t.Fatalf("edges of deleted node survived: %s", names(neighbors))
+	}
+
+	// A project-less edge does not make a neighbour of another project visible.
+	if _, err := repo.upsertNode(ctx, &kgpb.UpsertNodeRequest{TenantId: tenant, ProjectId: "p2", Node: &kgpb.Node{Id: id("foreign"), Type: "github.pr"}}); err != nil {
+		t.Fatalf("upsert foreign node: %v", err)
+	}
+	if _, err := repo.upsertEdge(ctx, &kgpb.UpsertEdgeRequest{TenantId: tenant,
+		Edge: &kgpb.Edge{Id: id("e-foreign"), Type: "MENTIONS", FromId: id("pr"), ToId: id("foreign")}}); err != nil {
+		t.Fatalf("upsert foreign edge: %v", err)
+	}
+	if neighbors, _ := repo.listNeighbors(ctx, &kgpb.ListNeighborsRequest{TenantId: tenant, ProjectId: project, NodeId: id("pr"), EdgeTypes: []string{"MENTIONS"}}); len(neighbors) != 0 {
+		t.Fatalf("neighbour leaked across projects: %s", names(neighbors))
+	}
+	if neighbors, _ := repo.listNeighbors(ctx, &kgpb.ListNeighborsRequest{TenantId: tenant, NodeId: id("pr"), EdgeTypes: []string{"MENTIONS"}}); names(neighbors) != "[foreign]" {
+		t.Fatalf("expected the neighbour without a project filter: %s", names(neighbors))
+	}
+
+	// Tenants match exactly, even when one tenant id prefixes another.
+	if _, err := repo.upsertNode(ctx, &kgpb.UpsertNodeRequest{TenantId: tenant + "::sub", Node: &kgpb.Node{Id: id("sub"), Type: "github.pr"}}); err != nil {
+		t.Fatalf("upsert node of prefixed tenant: %v", err)
+	}
+	if nodes, _ := repo.listNodes(ctx, &kgpb.ListEntitiesRequest{TenantId: tenant, EntityTypes: []string{"github.pr"}}); names(nodes) != "[foreign pr]" {
+		t.Fatalf("nodes leaked across tenants: %s", names(nodes))
+	}
+}
@@ -0,0 +1,341 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	kgpb "github.com/nucleus/ucl-core/pkg/kgpb"
+	"github.com/nucleus/ucl-core/pkg/kgtransfer"
+)
+
+// exportChunkSize is the buffered payload size per ExportGraphChunk.
+const exportChunkSize = 256 * 1024
+
+// KgTransferStore adapts the KG repository (or the in-memory fallback when no
+// database is configured) to kgtransfer.Source and kgtransfer.Sink. It backs
+// the ExportGraph/ImportGraph RPCs and the kg-transfer CLI.
+type KgTransferStore struct {
+	svc *kgService
+}
+
+// NewKgTransferStore opens a transfer store over db. A nil db uses an empty
+// in-memory graph, which is only useful for tests.
+func NewKgTransferStore(db *pgxpool.Pool) *KgTransferStore {
+	return &KgTransferStore{svc: NewKgService(db).(*kgService)}
+}
+
+func (t *KgTransferStore) ScanNodes(ctx context.Context, f kgtransfer.Filter, fn func(*kgpb.Node) error) error {
+	if pg, ok := t.svc.repo.(*kgPostgresRepo); ok {
+		return pg.scanNodes(ctx, f, fn)
+	}
+	return t.svc.store.scanNodes(f, fn)
+}
+
+func (t *KgTransferStore) ScanEdges(ctx context.Context, f kgtransfer.Filter, fn func(*kgpb.Edge) error) error {
+	if pg, ok := t.svc.repo.(*kgPostgresRepo); ok {
+		return pg.scanEdges(ctx, f, fn)
+	}
+	return t.svc.store.scanEdges(f, fn)
+}
+
+// UpsertNode writes to the repository directly rather than through
+// kgService.UpsertNode, whose fallback to the in-memory store would report
+// success for records that never reached the database.
+func (t *KgTransferStore) UpsertNode(ctx context.Context, tenantID, projectID string, node *kgpb.Node) error {
+	if t.svc.repo != nil {
+		_, err := t.svc.repo.upsertNode(ctx, &kgpb.UpsertNodeRequest{TenantId: tenantID, ProjectId: projectID, Node: node})
+		return err
+	}
+	t.svc.store.upsertNode(tenantID, projectID, node)
+	return nil
+}
+
+func (t *KgTransferStore) UpsertEdge(ctx context.Context, tenantID, projectID string, edge *kgpb.Edge) error {
+	if t.svc.repo != nil {
+		_, err := t.svc.repo.upsertEdge(ctx, &kgpb.UpsertEdgeRequest{TenantId: tenantID, ProjectId: projectID, Edge: edge})
+		return err
+	}
+	t.svc.store.upsertEdge(tenantID, projectID, edge)
+	return nil
+}
+
+// ExportGraph streams the filtered tenant graph in the requested format.
+func (s *kgService) ExportGraph(req *kgpb.ExportGraphRequest, stream kgpb.KgService_ExportGraphServer) error {
+	if req == nil || req.TenantId == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+	format, err := kgtransfer.ParseFormat(req.Format)
+	if err != nil {
+		return err
+	}
+	filter, err := exportFilter(req)
+	if err != nil {
+		return err
+	}
+
+	parts := map[string]*chunkWriter{}
+	order := []string{}
+	partWriter := func(part string) *chunkWriter {
+		if w, ok := parts[part]; ok {
+			return w
+		}
+		w := &chunkWriter{part: part, send: stream.Send}
+		parts[part] = w
+		order = append(order, part)
+		return w
+	}
+	src := &KgTransferStore{svc: s}
+	stats, err := kgtransfer.Export(stream.Context(), src, filter, format, func(part string) io.Writer {
+		return partWriter(part)
+	})
+	if err != nil {
+		return err
+	}
+	for _, part := range order {
+		if err := parts[part].flush(); err != nil {
+			return err
+		}
+	}
+	return stream.Send(&kgpb.ExportGraphChunk{Done: true, NodeCount: int32(stats.Nodes), EdgeCount: int32(stats.Edges)})
+}
+
+// ImportGraph upserts a streamed JSONL or GraphML payload into the tenant
+// graph, decoding records as the chunks arrive.
+func (s *kgService) ImportGraph(stream kgpb.KgService_ImportGraphServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return fmt.Errorf("import stream is empty")
+	}
+	if err != nil {
+		return err
+	}
+	if first.TenantId == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+	format, err := kgtransfer.ParseFormat(first.Format)
+	if err != nil {
+		return err
+	}
+	if !format.Importable() {
+		return fmt.Errorf("format %q cannot be imported", format)
+	}
+	r := &importChunkReader{stream: stream, buf: first.Data}
+	stats, err := kgtransfer.Import(stream.Context(), r, format, first.TenantId, first.ProjectId, &KgTransferStore{svc: s})
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&kgpb.ImportGraphResponse{
+		NodeCount:   int32(stats.Nodes),
+		EdgeCount:   int32(stats.Edges),
+		FailedNodes: int32(stats.FailedNodes),
+		FailedEdges: int32(stats.FailedEdges),
+		Errors:      stats.Errors,
+	})
+}
+
+// importChunkReader reads the data of successive ImportGraph messages.
+type importChunkReader struct {
+	stream kgpb.KgService_ImportGraphServer
+	buf    []byte
+}
+
+func (r *importChunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err // io.EOF ends the payload
+		}
+		r.buf = msg.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func exportFilter(req *kgpb.ExportGraphRequest) (kgtransfer.Filter, error) {
+	f := kgtransfer.Filter{
+		TenantID:    req.TenantId,
+		ProjectID:   req.ProjectId,
+		EntityTypes: req.EntityTypes,
+		EdgeTypes:   req.EdgeTypes,
+	}
+	var err error
+	if f.UpdatedAfter, err = parseOptionalTime(req.UpdatedAfter); err != nil {
+		return f, fmt.Errorf("updated_after: %w", err)
+	}
+	if f.UpdatedBefore, err = parseOptionalTime(req.UpdatedBefore); err != nil {
+		return f, fmt.Errorf("updated_before: %w", err)
+	}
+	return f, nil
+}
+
+func parseOptionalTime(v string) (*time.Time, error) {
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// chunkWriter buffers one export part and sends it in exportChunkSize pieces.
+type chunkWriter struct {
+	part string
+	buf  bytes.Buffer
+	send func(*kgpb.ExportGraphChunk) error
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	n, _ := w.buf.Write(p)
+	if w.buf.Len() >= exportChunkSize {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+func (w *chunkWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	data := append([]byte(nil), w.buf.Bytes()...)
+	w.buf.Reset()
+	return w.send(&kgpb.ExportGraphChunk{Part: w.part, Data: data})
+}
+
+// =============================================================================
+// Postgres scans
+// =============================================================================
+
+func (r *kgPostgresRepo) scanNodes(ctx context.Context, f kgtransfer.Filter, fn func(*kgpb.Node) error) error {
+	where := []string{"n.tenant_id = $1"}
+	args := []any{f.TenantID}
+	if f.ProjectID != "" {
+		args = append(args, f.ProjectID)
+		where = append(where, fmt.Sprintf("(n.project_id = $%d OR n.project_id IS NULL)", len(args)))
+	}
+	if len(f.EntityTypes) > 0 {
+		args = append(args, f.EntityTypes)
+		where = append(where, fmt.Sprintf("n.entity_type = ANY($%d)", len(args)))
+	}
+	where, args = appendTimeScope(where, args, "n", f)
+	stmt := fmt.Sprintf(`SELECT n.id, n.entity_type, n.display_name, n.properties FROM graph_nodes n WHERE %s ORDER BY n.id`,
+		strings.Join(where, " AND "))
+	rows, err := r.db.Query(ctx, stmt, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, etype, display string
+		var props map[string]string
+		if err := rows.Scan(&id, &etype, &display, &props); err != nil {
+			return err
+		}
+		if err := fn(&kgpb.Node{Id: id, Type: etype, Properties: props}); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *kgPostgresRepo) scanEdges(ctx context.Context, f kgtransfer.Filter, fn func(*kgpb.Edge) error) error {
+	whereSQL, args := edgeScope(f.TenantID, f.ProjectID, f.EdgeTypes)
+	where := []string{whereSQL}
+	if len(f.EntityTypes) > 0 {
+		args = append(args, f.EntityTypes)
+		idx := len(args)
+		where = append(where,
+			fmt.Sprintf("EXISTS (SELECT 1 FROM graph_nodes s WHERE s.id = e.source_entity_id AND s.entity_type = ANY($%d))", idx),
+			fmt.Sprintf("EXISTS (SELECT 1 FROM graph_nodes t WHERE t.id = e.target_entity_id AND t.entity_type = ANY($%d))", idx))
+	}
+	where, args = appendTimeScope(where, args, "e", f)
+	stmt := fmt.Sprintf(`SELECT e.id, e.edge_type, e.source_entity_id, e.target_entity_id, e.metadata FROM graph_edges e WHERE %s ORDER BY e.id`,
+		strings.Join(where, " AND "))
+	rows, err := r.db.Query(ctx, stmt, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, etype, from, to string
+		var props map[string]string
+		if err := rows.Scan(&id, &etype, &from, &to, &props); err != nil {
+			return err
+		}
+		if err := fn(&kgpb.Edge{Id: id, Type: etype, FromId: from, ToId: to, Properties: props}); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func appendTimeScope(where []string, args []any, alias string, f kgtransfer.Filter) ([]string, []any) {
+	if f.UpdatedAfter != nil {
+		args = append(args, *f.UpdatedAfter)
+		where = append(where, fmt.Sprintf("%s.updated_at >= $%d", alias, len(args)))
+	}
+	if f.UpdatedBefore != nil {
+		args = append(args, *f.UpdatedBefore)
+		where = append(where, fmt.Sprintf("%s.updated_at < $%d", alias, len(args)))
+	}
+	return where, args
+}
+
+// =============================================================================
+// In-memory scans (no timestamps are tracked, so time filters are ignored)
+// =============================================================================
+
+func (s *kgMemoryStore) scanNodes(f kgtransfer.Filter, fn func(*kgpb.Node) error) error {
+	nodes := s.listNodes(f.TenantID, f.ProjectID, f.EntityTypes, 0)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Id < nodes[j].Id })
+	for _, n := range nodes {
+		if err := fn(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *kgMemoryStore) scanEdges(f kgtransfer.Filter, fn func(*kgpb.Edge) error) error {
+	edges := s.listEdges(f.TenantID, f.ProjectID, f.EdgeTypes, "", "", 0)
+	if len(f.EntityTypes) > 0 {
+		types := toTypeSet(f.EntityTypes)
+		kept := edges[:0]
+		for _, e := range edges {
+			from := s.getNode(f.TenantID, f.ProjectID, e.FromId)
+			to := s.getNode(f.TenantID, f.ProjectID, e.ToId)
+			if from == nil || to == nil {
+				continue
+			}
+			_, okFrom := types[from.Type]
+			_, okTo := types[to.Type]
+			if okFrom && okTo {
+				kept = append(kept, e)
+			}
+		}
+		edges = kept
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].Id < edges[j].Id })
+	for _, e := range edges {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	_ kgtransfer.Source = (*KgTransferStore)(nil)
+	_ kgtransfer.Sink   = (*KgTransferStore)(nil)
+)
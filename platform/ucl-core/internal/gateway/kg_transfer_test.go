@@ -0,0 +1,176 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	kgpb "github.com/nucleus/ucl-core/pkg/kgpb"
+	"github.com/nucleus/ucl-core/pkg/kgtransfer"
+)
+
+func TestKgTransferRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	for _, format := range []kgtransfer.Format{kgtransfer.FormatJSONL, kgtransfer.FormatGraphML} {
+		t.Run(string(format), func(t *testing.T) {
+			src := &KgTransferStore{svc: seedKg(t).(*kgService)}
+			var buf bytes.Buffer
+			stats, err := kgtransfer.Export(ctx, src, kgtransfer.Filter{TenantID: "t1"}, format, func(string) io.Writer { return &buf })
+			if err != nil {
+				t.Fatalf("export: %v", err)
+			}
+			if stats.Nodes != 5 || stats.Edges != 4 {
+				t.Fatalf("unexpected export stats: %+v", stats)
+			}
+
+			// Importing twice must be idempotent.
+			dst := NewKgTransferStore(nil)
+			for i := 0; i < 2; i++ {
+				if _, err := kgtransfer.Import(ctx, bytes.NewReader(buf.Bytes()), format, "t2", "", dst); err != nil {
+					t.Fatalf("import: %v", err)
+				}
+			}
+			var nodes, edges int
+			_ = dst.ScanNodes(ctx, kgtransfer.Filter{TenantID: "t2"}, func(*kgpb.Node) error { nodes++; return nil })
+			_ = dst.ScanEdges(ctx, kgtransfer.Filter{TenantID: "t2"}, func(e *kgpb.Edge) error {
+				if e.Id == "e2" && (e.FromId != "commit" || e.ToId != "incident" || e.Type != "CAUSED") {
+					t.Fatalf("edge not preserved: %+v", e)
+				}
+				edges++
+				return nil
+			})
+			if nodes != 5 || edges != 4 {
+				t.Fatalf("expected 5 nodes and 4 edges after import, got %d/%d", nodes, edges)
+			}
+		})
+	}
+}
+
+func TestKgTransferFilterAndNeo4jFormats(t *testing.T) {
+	ctx := context.Background()
+	svc := seedKg(t).(*kgService)
+	_, _ = svc.UpsertNode(ctx, &kgpb.UpsertNodeRequest{TenantId: "t1", Node: &kgpb.Node{Id: "pr", Type: "github.pr"}})
+	_, _ = svc.UpsertNode(ctx, &kgpb.UpsertNodeRequest{TenantId: "t1", Node: &kgpb.Node{Id: "commit", Type: "github.pr"}})
+	src := &KgTransferStore{svc: svc}
+
+	parts := map[string]*bytes.Buffer{}
+	stats, err := kgtransfer.Export(ctx, src, kgtransfer.Filter{TenantID: "t1", EntityTypes: []string{"github.pr"}}, kgtransfer.FormatCSV,
+		func(part string) io.Writer {
+			if parts[part] == nil {
+				parts[part] = &bytes.Buffer{}
+			}
+			return parts[part]
+		})
+	if err != nil {
+		t.Fatalf("export csv: %v", err)
+	}
+	if stats.Nodes != 2 || stats.Edges != 1 {
+		t.Fatalf("entity type filter not applied: %+v", stats)
+	}
+	if !strings.HasPrefix(parts[kgtransfer.PartNodes].String(), "id:ID,:LABEL") || !strings.Contains(parts[kgtransfer.PartEdges].String(), "e1,pr,commit,CONTAINS") {
+		t.Fatalf("unexpected csv output:\n%s\n%s", parts[kgtransfer.PartNodes], parts[kgtransfer.PartEdges])
+	}
+
+	var cypher bytes.Buffer
+	if _, err := kgtransfer.Export(ctx, src, kgtransfer.Filter{TenantID: "t1"}, kgtransfer.FormatCypher, func(string) io.Writer { return &cypher }); err != nil {
+		t.Fatalf("export cypher: %v", err)
+	}
+	if !strings.Contains(cypher.String(), "MERGE (a)-[r:`CAUSED` {id: \"e2\"}]->(b)") {
+		t.Fatalf("unexpected cypher output:\n%s", cypher.String())
+	}
+}
+
+// failingKgRepo rejects every write, like an unreachable database.
+type failingKgRepo struct{ kgRepository }
+
+func (failingKgRepo) upsertNode(context.Context, *kgpb.UpsertNodeRequest) (*kgpb.Node, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (failingKgRepo) upsertEdge(context.Context, *kgpb.UpsertEdgeRequest) (*kgpb.Edge, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestKgImportReportsRepositoryFailures(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	if _, err := kgtransfer.Export(ctx, &KgTransferStore{svc: seedKg(t).(*kgService)}, kgtransfer.Filter{TenantID: "t1"}, kgtransfer.FormatJSONL,
+		func(string) io.Writer { return &buf }); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	svc := &kgService{repo: failingKgRepo{}, store: newKgMemoryStore()}
+	stream := newImportStream(ctx, "t2", "jsonl", buf.Bytes(), 64)
+	if err := svc.ImportGraph(stream); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	resp := stream.resp
+	if resp.NodeCount != 0 || resp.EdgeCount != 0 || resp.FailedNodes != 5 || resp.FailedEdges != 4 || len(resp.Errors) != 9 {
+		t.Fatalf("expected every record to fail, got %+v", resp)
+	}
+	// Nothing may land in the in-memory fallback either.
+	if nodes := svc.store.listNodes("t2", "", nil, 0); len(nodes) != 0 {
+		t.Fatalf("expected no fallback writes, got %d nodes", len(nodes))
+	}
+}
+
+// importStream feeds an ImportGraph call from memory, chunkSize bytes per
+// message.
+type importStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	msgs []*kgpb.ImportGraphRequest
+	resp *kgpb.ImportGraphResponse
+}
+
+func newImportStream(ctx context.Context, tenant, format string, data []byte, chunkSize int) *importStream {
+	s := &importStream{ctx: ctx, msgs: []*kgpb.ImportGraphRequest{{TenantId: tenant, Format: format}}}
+	for len(data) > 0 {
+		n := min(chunkSize, len(data))
+		s.msgs = append(s.msgs, &kgpb.ImportGraphRequest{Data: data[:n]})
+		data = data[n:]
+	}
+	return s
+}
+
+func (s *importStream) Context() context.Context { return s.ctx }
+
+func (s *importStream) Recv() (*kgpb.ImportGraphRequest, error) {
+	if len(s.msgs) == 0 {
+		return nil, io.EOF
+	}
+	m := s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return m, nil
+}
+
+func (s *importStream) SendAndClose(resp *kgpb.ImportGraphResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestKgImportGraphStreamsChunks(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	if _, err := kgtransfer.Export(ctx, &KgTransferStore{svc: seedKg(t).(*kgService)}, kgtransfer.Filter{TenantID: "t1"}, kgtransfer.FormatGraphML,
+		func(string) io.Writer { return &buf }); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	// 7-byte chunks split every record across messages.
+	svc := NewKgService(nil).(*kgService)
+	stream := newImportStream(ctx, "t2", "graphml", buf.Bytes(), 7)
+	if err := svc.ImportGraph(stream); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if stream.resp.NodeCount != 5 || stream.resp.EdgeCount != 4 || stream.resp.FailedNodes+stream.resp.FailedEdges != 0 {
+		t.Fatalf("unexpected import response: %+v", stream.resp)
+	}
+	if err := svc.ImportGraph(newImportStream(ctx, "", "jsonl", nil, 1)); err == nil {
+		t.Fatal("expected a missing tenant to be rejected")
+	}
+}
@@ -0,0 +1,525 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	kgpb "github.com/nucleus/ucl-core/pkg/kgpb"
+)
+
+// Traversal bounds. Requests are clamped to these so a single call cannot walk
+// the whole tenant graph.
+const (
+	defaultTraverseHops  = 3
+	maxTraverseHops      = 6
+	defaultTraverseNodes = 100
+	maxTraverseNodes     = 5000
+	defaultPathHops      = 4
+	defaultMaxPaths      = 10
+	maxPathsLimit        = 100
+)
+
+type traversalDirection int
+
+const (
+	directionAny traversalDirection = iota
+	directionOut
+	directionIn
+)
+
+func parseDirection(v string) traversalDirection {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "out", "outgoing":
+		return directionOut
+	case "in", "incoming":
+		return directionIn
+	default:
+		return directionAny
+	}
+}
+
+func clampInt(v, def, max int) int {
+	if v <= 0 {
+		return def
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// =============================================================================
+// Postgres (recursive CTEs)
+// =============================================================================
+
+// adjacencySQL renders graph_edges as directed (edge_id, src, dst) hops for the
+// requested direction. For "any" both orientations are emitted.
+func adjacencySQL(dir traversalDirection, where string) string {
+	out := fmt.Sprintf(`SELECT e.id AS edge_id, e.source_entity_id AS src, e.target_entity_id AS dst FROM graph_edges e WHERE %s`, where)
+	in := fmt.Sprintf(`SELECT e.id AS edge_id, e.target_entity_id AS src, e.source_entity_id AS dst FROM graph_edges e WHERE %s`, where)
+	switch dir {
+	case directionOut:
+		return out
+	case directionIn:
+		return in
+	default:
+		return out + "\n  UNION ALL\n  " + in
+	}
+}
+
+// edgeScope builds the tenant/project/edge-type predicate for graph_edges
+// aliased as e. Tenant is always $1; further args start at $2.
+func edgeScope(tenantID, projectID string, edgeTypes []string) (string, []any) {
+	where := []string{"e.tenant_id = $1"}
+	args := []any{tenantID}
+	if projectID != "" {
+		args = append(args, projectID)
+		where = append(where, fmt.Sprintf("(e.project_id = $%d OR e.project_id IS NULL)", len(args)))
+	}
+	if len(edgeTypes) > 0 {
+		args = append(args, edgeTypes)
+		where = append(where, fmt.Sprintf("e.edge_type = ANY($%d)", len(args)))
+	}
+	return strings.Join(where, " AND "), args
+}
+
+func (r *kgPostgresRepo) traverse(ctx context.Context, req *kgpb.TraverseRequest) (*kgpb.TraverseResponse, error) {
+	if len(req.SeedIds) == 0 {
+		return &kgpb.TraverseResponse{}, nil
+	}
+	maxHops := clampInt(int(req.MaxHops), defaultTraverseHops, maxTraverseHops)
+	budget := clampInt(int(req.MaxNodes), defaultTraverseNodes, maxTraverseNodes)
+
+	where, args := edgeScope(req.TenantId, req.ProjectId, req.EdgeTypes)
+	args = append(args, req.SeedIds)
+	seedIdx := len(args)
+	args = append(args, maxHops)
+	hopIdx := len(args)
+
+	// UNION (not UNION ALL) dedupes (node, hop) pairs, so the walk is bounded by
+	// nodes*hops rather than by the number of distinct paths.
+	stmt := fmt.Sprintf(`
+WITH RECURSIVE adj AS (
+  %s
+),
+walk(node_id, hop) AS (
+  SELECT n.id, 0 FROM graph_nodes n WHERE n.tenant_id = $1 AND n.id = ANY($%d)
+  UNION
+  SELECT a.dst, w.hop + 1 FROM walk w JOIN adj a ON a.src = w.node_id WHERE w.hop < $%d
+)
+SELECT n.id, n.entity_type, n.display_name, n.properties, h.hop
+FROM (SELECT node_id, MIN(hop) AS hop FROM walk GROUP BY node_id) h
+JOIN graph_nodes n ON n.id = h.node_id AND n.tenant_id = $1
+ORDER BY h.hop, n.updated_at DESC
+LIMIT %d;`, adjacencySQL(parseDirection(req.Direction), where), seedIdx, hopIdx, budget+1)
+
+	rows, err := r.db.Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	resp := &kgpb.TraverseResponse{}
+	var ids []string
+	for rows.Next() {
+		var id, etype, display string
+		var props map[string]string
+		var hop int32
+		if err := rows.Scan(&id, &etype, &display, &props, &hop); err != nil {
+			return nil, err
+		}
+		if len(resp.Nodes) == budget {
+			resp.Truncated = true
+			break
+		}
+		resp.Nodes = append(resp.Nodes, &kgpb.TraversedNode{Node: &kgpb.Node{Id: id, Type: etype, Properties: props}, Hop: hop})
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	edges, err := r.edgesBetween(ctx, req.TenantId, req.ProjectId, req.EdgeTypes, ids)
+	if err != nil {
+		return nil, err
+	}
+	resp.Edges = edges
+	return resp, nil
+}
+
+func (r *kgPostgresRepo) shortestPath(ctx context.Context, req *kgpb.PathRequest) (*kgpb.ShortestPathResponse, error) {
+	paths, _, err := r.findPaths(ctx, req, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return &kgpb.ShortestPathResponse{}, nil
+	}
+	return &kgpb.ShortestPathResponse{Path: paths[0]}, nil
+}
+
+func (r *kgPostgresRepo) allPaths(ctx context.Context, req *kgpb.PathRequest) (*kgpb.AllPathsResponse, error) {
+	limit := clampInt(int(req.MaxPaths), defaultMaxPaths, maxPathsLimit)
+	paths, truncated, err := r.findPaths(ctx, req, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &kgpb.AllPathsResponse{Paths: paths, Truncated: truncated}, nil
+}
+
+// findPaths enumerates simple paths from req.FromId to req.ToId within
+// max_hops, shortest first. The recursive CTE carries each path's node array
+// so a walk never revisits a node, and works hop by hop, so its rows come out
+// in hop order without a sort; the LIMIT therefore stops the recursion as soon
+// as limit+1 paths have been found instead of enumerating every path first.
+func (r *kgPostgresRepo) findPaths(ctx context.Context, req *kgpb.PathRequest, limit int) ([]*kgpb.Path, bool, error) {
+	if req.FromId == "" || req.ToId == "" {
+		return nil, false, fmt.Errorf("from_id and to_id are required")
+	}
+	maxHops := clampInt(int(req.MaxHops), defaultPathHops, maxTraverseHops)
+
+	where, args := edgeScope(req.TenantId, req.ProjectId, req.EdgeTypes)
+	args = append(args, req.FromId)
+	fromIdx := len(args)
+	args = append(args, req.ToId)
+	toIdx := len(args)
+	args = append(args, maxHops)
+	hopIdx := len(args)
+
+	stmt := fmt.Sprintf(`
+WITH RECURSIVE adj AS (
+  %s
+),
+walk(node_id, hop, node_path, edge_path) AS (
+  SELECT $%[2]d::text, 0, ARRAY[$%[2]d::text], ARRAY[]::text[]
+  UNION ALL
+  SELECT a.dst, w.hop + 1, w.node_path || a.dst, w.edge_path || a.edge_id
+  FROM walk w JOIN adj a ON a.src = w.node_id
+  WHERE w.hop < $%[4]d AND w.node_id <> $%[3]d AND NOT a.dst = ANY(w.node_path)
+)
+SELECT node_path, edge_path FROM walk
+WHERE node_id = $%[3]d AND hop > 0
+LIMIT %[5]d;`, adjacencySQL(parseDirection(req.Direction), where), fromIdx, toIdx, hopIdx, limit+1)
+
+	rows, err := r.db.Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+	type rawPath struct{ nodes, edges []string }
+	var raw []rawPath
+	truncated := false
+	for rows.Next() {
+		var nodes, edges []string
+		if err := rows.Scan(&nodes, &edges); err != nil {
+			return nil, false, err
+		}
+		if len(raw) == limit {
+			truncated = true
+			break
+		}
+		raw = append(raw, rawPath{nodes: nodes, edges: edges})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	rows.Close()
+	if len(raw) == 0 {
+		return nil, false, nil
+	}
+	sort.SliceStable(raw, func(i, j int) bool { return len(raw[i].edges) < len(raw[j].edges) })
+
+	nodeSet := map[string]struct{}{}
+	edgeSet := map[string]struct{}{}
+	for _, rp := range raw {
+		for _, id := range rp.nodes {
+			nodeSet[id] = struct{}{}
+		}
+		for _, id := range rp.edges {
+			edgeSet[id] = struct{}{}
+		}
+	}
+	nodes, err := r.nodesByID(ctx, req.TenantId, setKeys(nodeSet))
+	if err != nil {
+		return nil, false, err
+	}
+	edges, err := r.edgesByID(ctx, req.TenantId, setKeys(edgeSet))
+	if err != nil {
+		return nil, false, err
+	}
+	out := make([]*kgpb.Path, 0, len(raw))
+	for _, rp := range raw {
+		p := &kgpb.Path{}
+		for _, id := range rp.nodes {
+			n := nodes[id]
+			if n == nil {
+				n = &kgpb.Node{Id: id}
+			}
+			p.Nodes = append(p.Nodes, n)
+		}
+		for _, id := range rp.edges {
+			if e := edges[id]; e != nil {
+				p.Edges = append(p.Edges, e)
+			}
+		}
+		out = append(out, p)
+	}
+	return out, truncated, nil
+}
+
+func (r *kgPostgresRepo) nodesByID(ctx context.Context, tenantID string, ids []string) (map[string]*kgpb.Node, error) {
+	out := make(map[string]*kgpb.Node, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Query(ctx, `SELECT id, entity_type, display_name, properties FROM graph_nodes WHERE tenant_id = $1 AND id = ANY($2)`, tenantID, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, etype, display string
+		var props map[string]string
+		if err := rows.Scan(&id, &etype, &display, &props); err != nil {
+			return nil, err
+		}
+		out[id] = &kgpb.Node{Id: id, Type: etype, Properties: props}
+	}
+	return out, rows.Err()
+}
+
+func (r *kgPostgresRepo) edgesByID(ctx context.Context, tenantID string, ids []string) (map[string]*kgpb.Edge, error) {
+	out := make(map[string]*kgpb.Edge, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Query(ctx, `SELECT id, edge_type, source_entity_id, target_entity_id, metadata FROM graph_edges WHERE tenant_id = $1 AND id = ANY($2)`, tenantID, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, etype, from, to string
+		var props map[string]string
+		if err := rows.Scan(&id, &etype, &from, &to, &props); err != nil {
+			return nil, err
+		}
+		out[id] = &kgpb.Edge{Id: id, Type: etype, FromId: from, ToId: to, Properties: props}
+	}
+	return out, rows.Err()
+}
+
+// edgesBetween returns the edges whose endpoints are both in ids.
+func (r *kgPostgresRepo) edgesBetween(ctx context.Context, tenantID, projectID string, edgeTypes, ids []string) ([]*kgpb.Edge, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	where, args := edgeScope(tenantID, projectID, edgeTypes)
+	args = append(args, ids)
+	stmt := fmt.Sprintf(`SELECT e.id, e.edge_type, e.source_entity_id, e.target_entity_id, e.metadata FROM graph_edges e WHERE %s AND e.source_entity_id = ANY($%d) AND e.target_entity_id = ANY($%d)`,
+		where, len(args), len(args))
+	rows, err := r.db.Query(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*kgpb.Edge
+	for rows.Next() {
+		var id, etype, from, to string
+		var props map[string]string
+		if err := rows.Scan(&id, &etype, &from, &to, &props); err != nil {
+			return nil, err
+		}
+		out = append(out, &kgpb.Edge{Id: id, Type: etype, FromId: from, ToId: to, Properties: props})
+	}
+	return out, rows.Err()
+}
+
+func setKeys(m map[string]struct{}) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+// =============================================================================
+// In-memory fallback
+// =============================================================================
+
+type memoryHop struct {
+	edge *kgpb.Edge
+	next string
+}
+
+// adjacent lists the hops leaving nodeID in the given direction. Caller must
+// hold s.mu.
+func (s *kgMemoryStore) adjacent(tenant, project, nodeID string, typeSet map[string]struct{}, dir traversalDirection) []memoryHop {
+	var out []memoryHop
+	for _, me := range s.edgesOf(tenant, project, nodeID) {
+		e := me.edge
+		if len(typeSet) > 0 {
+			if _, ok := typeSet[e.Type]; !ok {
+				continue
+			}
+		}
+		switch {
+		case e.FromId == nodeID && dir != directionIn:
+			out = append(out, memoryHop{edge: e, next: e.ToId})
+		case e.ToId == nodeID && dir != directionOut:
+			out = append(out, memoryHop{edge: e, next: e.FromId})
+		}
+	}
+	return out
+}
+
+func toTypeSet(types []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(types))
+	for _, t := range types {
+		set[t] = struct{}{}
+	}
+	return set
+}
+
+func (s *kgMemoryStore) traverse(req *kgpb.TraverseRequest) *kgpb.TraverseResponse {
+	maxHops := clampInt(int(req.MaxHops), defaultTraverseHops, maxTraverseHops)
+	budget := clampInt(int(req.MaxNodes), defaultTraverseNodes, maxTraverseNodes)
+	typeSet := toTypeSet(req.EdgeTypes)
+	dir := parseDirection(req.Direction)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resp := &kgpb.TraverseResponse{}
+	visited := map[string]bool{}
+	var frontier []string
+	for _, id := range req.SeedIds {
+		n := s.lookupNode(req.TenantId, req.ProjectId, id)
+		if n == nil || visited[id] {
+			continue
+		}
+		if len(resp.Nodes) == budget {
+			resp.Truncated = true
+			break
+		}
+		visited[id] = true
+		resp.Nodes = append(resp.Nodes, &kgpb.TraversedNode{Node: n, Hop: 0})
+		frontier = append(frontier, id)
+	}
+	for hop := 1; hop <= maxHops && len(frontier) > 0 && !resp.Truncated; hop++ {
+		var next []string
+		for _, id := range frontier {
+			for _, h := range s.adjacent(req.TenantId, req.ProjectId, id, typeSet, dir) {
+				if visited[h.next] {
+					continue
+				}
+				n := s.lookupNode(req.TenantId, req.ProjectId, h.next)
+				if n == nil {
+					continue
+				}
+				if len(resp.Nodes) == budget {
+					resp.Truncated = true
+					break
+				}
+				visited[h.next] = true
+				resp.Nodes = append(resp.Nodes, &kgpb.TraversedNode{Node: n, Hop: int32(hop)})
+				next = append(next, h.next)
+			}
+		}
+		frontier = next
+	}
+
+	seenEdge := map[string]bool{}
+	for _, tn := range resp.Nodes {
+		for _, h := range s.adjacent(req.TenantId, req.ProjectId, tn.Node.Id, typeSet, directionAny) {
+			if seenEdge[h.edge.Id] || !visited[h.edge.FromId] || !visited[h.edge.ToId] {
+				continue
+			}
+			seenEdge[h.edge.Id] = true
+			resp.Edges = append(resp.Edges, h.edge)
+		}
+	}
+	return resp
+}
+
+// maxPathSearchStates bounds how many partial paths one in-memory path
+// search may queue.
+const maxPathSearchStates = 10 * maxTraverseNodes
+
+// findPaths enumerates simple paths breadth-first, so paths come out shortest
+// first, as the Postgres repository's recursive CTE does.
+func (s *kgMemoryStore) findPaths(req *kgpb.PathRequest, limit int) ([]*kgpb.Path, bool, error) {
+	if req.FromId == "" || req.ToId == "" {
+		return nil, false, fmt.Errorf("from_id and to_id are required")
+	}
+	maxHops := clampInt(int(req.MaxHops), defaultPathHops, maxTraverseHops)
+	typeSet := toTypeSet(req.EdgeTypes)
+	dir := parseDirection(req.Direction)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found []memoryPath
+	queue := []memoryPath{{nodes: []string{req.FromId}}}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if len(cur.edges) >= maxHops {
+			continue
+		}
+		last := cur.nodes[len(cur.nodes)-1]
+		for _, h := range s.adjacent(req.TenantId, req.ProjectId, last, typeSet, dir) {
+			if containsString(cur.nodes, h.next) {
+				continue
+			}
+			p := memoryPath{
+				nodes: append(append([]string{}, cur.nodes...), h.next),
+				edges: append(append([]*kgpb.Edge{}, cur.edges...), h.edge),
+			}
+			if h.next == req.ToId {
+				if len(found) == limit {
+					return s.hydratePaths(req.TenantId, req.ProjectId, found), true, nil
+				}
+				found = append(found, p)
+				continue
+			}
+			if len(queue) == maxPathSearchStates {
+				return nil, false, fmt.Errorf("path search queued more than %d partial paths", maxPathSearchStates)
+			}
+			queue = append(queue, p)
+		}
+	}
+	return s.hydratePaths(req.TenantId, req.ProjectId, found), false, nil
+}
+
+type memoryPath struct {
+	nodes []string
+	edges []*kgpb.Edge
+}
+
+func (s *kgMemoryStore) hydratePaths(tenant, project string, raw []memoryPath) []*kgpb.Path {
+	out := make([]*kgpb.Path, 0, len(raw))
+	for _, rp := range raw {
+		p := &kgpb.Path{Edges: rp.edges}
+		for _, id := range rp.nodes {
+			n := s.lookupNode(tenant, project, id)
+			if n == nil {
+				n = &kgpb.Node{Id: id}
+			}
+			p.Nodes = append(p.Nodes, n)
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	kgpb "github.com/nucleus/ucl-core/pkg/kgpb"
+)
+
+// seedKg builds pr -> commit -> incident plus a pr -> doc side branch and a
+// direct pr <- incident shortcut, all in the in-memory store.
+func seedKg(t *testing.T) kgpb.KgServiceServer {
+	t.Helper()
+	svc := NewKgService(nil)
+	ctx := context.Background()
+	for _, id := range []string{"pr", "commit", "incident", "doc", "other"} {
+		if _, err := svc.UpsertNode(ctx, &kgpb.UpsertNodeRequest{TenantId: "t1", Node: &kgpb.Node{Id: id, Type: "entity"}}); err != nil {
+			t.Fatalf("upsert node %s: %v", id, err)
+		}
+	}
+	edges := []*kgpb.Edge{
+		{Id: "e1", Type: "CONTAINS", FromId: "pr", ToId: "commit"},
+		{Id: "e2", Type: "CAUSED", FromId: "commit", ToId: "incident"},
+		{Id: "e3", Type: "DOCUMENTS", FromId: "doc", ToId: "pr"},
+		{Id: "e4", Type: "MENTIONS", FromId: "incident", ToId: "pr"},
+	}
+	for _, e := range edges {
+		if _, err := svc.UpsertEdge(ctx, &kgpb.UpsertEdgeRequest{TenantId: "t1", Edge: e}); err != nil {
+			t.Fatalf("upsert edge %s: %v", e.Id, err)
+		}
+	}
+	return svc
+}
+
+func TestKgTraverseMemory(t *testing.T) {
+	svc := seedKg(t)
+	resp, err := svc.Traverse(context.Background(), &kgpb.TraverseRequest{
+		TenantId:  "t1",
+		SeedIds:   []string{"pr"},
+		MaxHops:   1,
+		Direction: "out",
+	})
+	if err != nil {
+		t.Fatalf("Traverse: %v", err)
+	}
+	hops := map[string]int32{}
+	for _, n := range resp.Nodes {
+		hops[n.Node.Id] = n.Hop
+	}
+	if len(hops) != 2 || hops["pr"] != 0 || hops["commit"] != 1 {
+		t.Fatalf("unexpected nodes: %v", hops)
+	}
+	if len(resp.Edges) != 1 || resp.Edges[0].Id != "e1" {
+		t.Fatalf("unexpected edges: %+v", resp.Edges)
+	}
+
+	resp, err = svc.Traverse(context.Background(), &kgpb.TraverseRequest{TenantId: "t1", SeedIds: []string{"pr"}, MaxHops: 2, MaxNodes: 2})
+	if err != nil {
+		t.Fatalf("Traverse: %v", err)
+	}
+	if len(resp.Nodes) != 2 || !resp.Truncated {
+		t.Fatalf("expected truncated result of 2 nodes, got %d truncated=%v", len(resp.Nodes), resp.Truncated)
+	}
+}
+
+func TestKgPathsMemory(t *testing.T) {
+	svc := seedKg(t)
+	ctx := context.Background()
+
+	sp, err := svc.ShortestPath(ctx, &kgpb.PathRequest{TenantId: "t1", FromId: "pr", ToId: "incident"})
+	if err != nil {
+		t.Fatalf("ShortestPath: %v", err)
+	}
+	if sp.Path == nil || len(sp.Path.Edges) != 1 || sp.Path.Edges[0].Id != "e4" {
+		t.Fatalf("expected direct path via e4, got %+v", sp.Path)
+	}
+
+	sp, err = svc.ShortestPath(ctx, &kgpb.PathRequest{TenantId: "t1", FromId: "pr", ToId: "incident", Direction: "out"})
+	if err != nil {
+		t.Fatalf("ShortestPath: %v", err)
+	}
+	if sp.Path == nil || len(sp.Path.Nodes) != 3 || sp.Path.Nodes[1].Id != "commit" {
+		t.Fatalf("expected pr->commit->incident, got %+v", sp.Path)
+	}
+
+	// A second two-hop route through a runbook; the three-hop route via
+	// commit is still within max_hops and comes last.
+	if _, err := svc.UpsertNode(ctx, &kgpb.UpsertNodeRequest{TenantId: "t1", Node: &kgpb.Node{Id: "runbook", Type: "entity"}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []*kgpb.Edge{
+		{Id: "e5", Type: "LINKS", FromId: "doc", ToId: "runbook"},
+		{Id: "e6", Type: "LINKS", FromId: "runbook", ToId: "incident"},
+	} {
+		if _, err := svc.UpsertEdge(ctx, &kgpb.UpsertEdgeRequest{TenantId: "t1", Edge: e}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := svc.AllPaths(ctx, &kgpb.PathRequest{TenantId: "t1", FromId: "doc", ToId: "incident"})
+	if err != nil {
+		t.Fatalf("AllPaths: %v", err)
+	}
+	if len(all.Paths) != 3 || all.Truncated || pathIDs(all.Paths[0]) != "doc pr incident" ||
+		pathIDs(all.Paths[1]) != "doc runbook incident" || pathIDs(all.Paths[2]) != "doc pr commit incident" {
+		t.Fatalf("expected every path within max_hops, shortest first, got %d", len(all.Paths))
+	}
+	two, err := svc.AllPaths(ctx, &kgpb.PathRequest{TenantId: "t1", FromId: "doc", ToId: "incident", MaxHops: 2})
+	if err != nil || len(two.Paths) != 2 || two.Truncated {
+		t.Fatalf("expected the two-hop paths only, got %+v err=%v", two, err)
+	}
+	one, err := svc.AllPaths(ctx, &kgpb.PathRequest{TenantId: "t1", FromId: "doc", ToId: "incident", MaxPaths: 1})
+	if err != nil || len(one.Paths) != 1 || !one.Truncated {
+		t.Fatalf("expected one path and truncation, got %+v err=%v", one, err)
+	}
+
+	none, err := svc.ShortestPath(ctx, &kgpb.PathRequest{TenantId: "t1", FromId: "pr", ToId: "other"})
+	if err != nil {
+		t.Fatalf("ShortestPath: %v", err)
+	}
+	if none.Path != nil {
+		t.Fatalf("expected no path, got %+v", none.Path)
+	}
+}
+
+func pathIDs(p *kgpb.Path) string {
+	ids := make([]string, 0, len(p.Nodes))
+	for _, n := range p.Nodes {
+		ids = append(ids, n.Id)
+	}
+	return strings.Join(ids, " ")
+}
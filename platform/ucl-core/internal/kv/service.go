@@ -58,11 +58,20 @@ func (s *Service) Delete(ctx context.Context, req *kvpb.DeleteRequest) (*kvpb.De
 
 func (s *Service) ListKeys(ctx context.Context, req *kvpb.ListKeysRequest) (*kvpb.ListKeysResponse, error) {
 	scope := normalizeScope(req.GetScope())
-	keys, err := s.store.ListKeys(ctx, scope.TenantID, scope.ProjectID, req.GetPrefix(), int(req.GetLimit()))
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = 100
+	}
+	// The page token is the last key of the previous page.
+	keys, err := s.store.ListKeysAfter(ctx, scope.TenantID, scope.ProjectID, req.GetPrefix(), req.GetPageToken(), limit)
 	if err != nil {
 		return nil, err
 	}
-	return &kvpb.ListKeysResponse{Keys: keys}, nil
+	resp := &kvpb.ListKeysResponse{Keys: keys}
+	if len(keys) == limit {
+		resp.NextPageToken = keys[len(keys)-1]
+	}
+	return resp, nil
 }
 
 type normalizedScope struct {
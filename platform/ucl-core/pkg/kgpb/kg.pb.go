@@ -52,6 +52,25 @@ type GetNodeResponse struct {
 	Node *Node `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
 }
 
+type DeleteNodeRequest struct {
+	TenantId  string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	ProjectId string `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	NodeId    string `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+}
+type DeleteNodeResponse struct {
+	Deleted      bool  `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	EdgesDeleted int32 `protobuf:"varint,2,opt,name=edges_deleted,json=edgesDeleted,proto3" json:"edges_deleted,omitempty"`
+}
+
+type DeleteEdgeRequest struct {
+	TenantId  string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	ProjectId string `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	EdgeId    string `protobuf:"bytes,3,opt,name=edge_id,json=edgeId,proto3" json:"edge_id,omitempty"`
+}
+type DeleteEdgeResponse struct {
+	Deleted bool `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+}
+
 type ListEntitiesRequest struct {
 	TenantId    string   `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
 	ProjectId   string   `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
@@ -85,14 +104,93 @@ type ListNeighborsResponse struct {
 	Neighbors []*Node `protobuf:"bytes,1,rep,name=neighbors,proto3" json:"neighbors,omitempty"`
 }
 
+type TraverseRequest struct {
+	TenantId  string   `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	ProjectId string   `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	SeedIds   []string `protobuf:"bytes,3,rep,name=seed_ids,json=seedIds,proto3" json:"seed_ids,omitempty"`
+	MaxHops   int32    `protobuf:"varint,4,opt,name=max_hops,json=maxHops,proto3" json:"max_hops,omitempty"`
+	EdgeTypes []string `protobuf:"bytes,5,rep,name=edge_types,json=edgeTypes,proto3" json:"edge_types,omitempty"`
+	Direction string   `protobuf:"bytes,6,opt,name=direction,proto3" json:"direction,omitempty"`
+	MaxNodes  int32    `protobuf:"varint,7,opt,name=max_nodes,json=maxNodes,proto3" json:"max_nodes,omitempty"`
+}
+type TraversedNode struct {
+	Node *Node `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Hop  int32 `protobuf:"varint,2,opt,name=hop,proto3" json:"hop,omitempty"`
+}
+type TraverseResponse struct {
+	Nodes     []*TraversedNode `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Edges     []*Edge          `protobuf:"bytes,2,rep,name=edges,proto3" json:"edges,omitempty"`
+	Truncated bool             `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"`
+}
+
+type PathRequest struct {
+	TenantId  string   `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	ProjectId string   `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	FromId    string   `protobuf:"bytes,3,opt,name=from_id,json=fromId,proto3" json:"from_id,omitempty"`
+	ToId      string   `protobuf:"bytes,4,opt,name=to_id,json=toId,proto3" json:"to_id,omitempty"`
+	MaxHops   int32    `protobuf:"varint,5,opt,name=max_hops,json=maxHops,proto3" json:"max_hops,omitempty"`
+	EdgeTypes []string `protobuf:"bytes,6,rep,name=edge_types,json=edgeTypes,proto3" json:"edge_types,omitempty"`
+	Direction string   `protobuf:"bytes,7,opt,name=direction,proto3" json:"direction,omitempty"`
+	MaxPaths  int32    `protobuf:"varint,8,opt,name=max_paths,json=maxPaths,proto3" json:"max_paths,omitempty"`
+}
+type Path struct {
+	Nodes []*Node `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Edges []*Edge `protobuf:"bytes,2,rep,name=edges,proto3" json:"edges,omitempty"`
+}
+type ShortestPathResponse struct {
+	Path *Path `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+}
+type AllPathsResponse struct {
+	Paths     []*Path `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+	Truncated bool    `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"`
+}
+
+type ExportGraphRequest struct {
+	TenantId      string   `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	ProjectId     string   `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	EntityTypes   []string `protobuf:"bytes,3,rep,name=entity_types,json=entityTypes,proto3" json:"entity_types,omitempty"`
+	EdgeTypes     []string `protobuf:"bytes,4,rep,name=edge_types,json=edgeTypes,proto3" json:"edge_types,omitempty"`
+	UpdatedAfter  string   `protobuf:"bytes,5,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`
+	UpdatedBefore string   `protobuf:"bytes,6,opt,name=updated_before,json=updatedBefore,proto3" json:"updated_before,omitempty"`
+	Format        string   `protobuf:"bytes,7,opt,name=format,proto3" json:"format,omitempty"`
+}
+type ExportGraphChunk struct {
+	Part      string `protobuf:"bytes,1,opt,name=part,proto3" json:"part,omitempty"`
+	Data      []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Done      bool   `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`
+	NodeCount int32  `protobuf:"varint,4,opt,name=node_count,json=nodeCount,proto3" json:"node_count,omitempty"`
+	EdgeCount int32  `protobuf:"varint,5,opt,name=edge_count,json=edgeCount,proto3" json:"edge_count,omitempty"`
+}
+
+type ImportGraphRequest struct {
+	TenantId  string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	ProjectId string `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Format    string `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	Data      []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+}
+type ImportGraphResponse struct {
+	NodeCount   int32    `protobuf:"varint,1,opt,name=node_count,json=nodeCount,proto3" json:"node_count,omitempty"`
+	EdgeCount   int32    `protobuf:"varint,2,opt,name=edge_count,json=edgeCount,proto3" json:"edge_count,omitempty"`
+	FailedNodes int32    `protobuf:"varint,3,opt,name=failed_nodes,json=failedNodes,proto3" json:"failed_nodes,omitempty"`
+	FailedEdges int32    `protobuf:"varint,4,opt,name=failed_edges,json=failedEdges,proto3" json:"failed_edges,omitempty"`
+	Errors      []string `protobuf:"bytes,5,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
 // Client API
 type KgServiceClient interface {
 	UpsertNode(ctx context.Context, in *UpsertNodeRequest, opts ...grpc.CallOption) (*UpsertNodeResponse, error)
 	UpsertEdge(ctx context.Context, in *UpsertEdgeRequest, opts ...grpc.CallOption) (*UpsertEdgeResponse, error)
 	GetNode(ctx context.Context, in *GetNodeRequest, opts ...grpc.CallOption) (*GetNodeResponse, error)
+	DeleteNode(ctx context.Context, in *DeleteNodeRequest, opts ...grpc.CallOption) (*DeleteNodeResponse, error)
+	DeleteEdge(ctx context.Context, in *DeleteEdgeRequest, opts ...grpc.CallOption) (*DeleteEdgeResponse, error)
 	ListEntities(ctx context.Context, in *ListEntitiesRequest, opts ...grpc.CallOption) (*ListEntitiesResponse, error)
 	ListEdges(ctx context.Context, in *ListEdgesRequest, opts ...grpc.CallOption) (*ListEdgesResponse, error)
 	ListNeighbors(ctx context.Context, in *ListNeighborsRequest, opts ...grpc.CallOption) (*ListNeighborsResponse, error)
+	Traverse(ctx context.Context, in *TraverseRequest, opts ...grpc.CallOption) (*TraverseResponse, error)
+	ShortestPath(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*ShortestPathResponse, error)
+	AllPaths(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*AllPathsResponse, error)
+	ExportGraph(ctx context.Context, in *ExportGraphRequest, opts ...grpc.CallOption) (KgService_ExportGraphClient, error)
+	ImportGraph(ctx context.Context, opts ...grpc.CallOption) (KgService_ImportGraphClient, error)
 }
 
 type kgServiceClient struct {
@@ -130,6 +228,24 @@ func (c *kgServiceClient) GetNode(ctx context.Context, in *GetNodeRequest, opts
 	return out, nil
 }
 
+func (c *kgServiceClient) DeleteNode(ctx context.Context, in *DeleteNodeRequest, opts ...grpc.CallOption) (*DeleteNodeResponse, error) {
+	out := new(DeleteNodeResponse)
+	err := c.cc.Invoke(ctx, "/kg.KgService/DeleteNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kgServiceClient) DeleteEdge(ctx context.Context, in *DeleteEdgeRequest, opts ...grpc.CallOption) (*DeleteEdgeResponse, error) {
+	out := new(DeleteEdgeResponse)
+	err := c.cc.Invoke(ctx, "/kg.KgService/DeleteEdge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *kgServiceClient) ListEntities(ctx context.Context, in *ListEntitiesRequest, opts ...grpc.CallOption) (*ListEntitiesResponse, error) {
 	out := new(ListEntitiesResponse)
 	err := c.cc.Invoke(ctx, "/kg.KgService/ListEntities", in, out, opts...)
@@ -157,14 +273,113 @@ func (c *kgServiceClient) ListNeighbors(ctx context.Context, in *ListNeighborsRe
 	return out, nil
 }
 
+func (c *kgServiceClient) Traverse(ctx context.Context, in *TraverseRequest, opts ...grpc.CallOption) (*TraverseResponse, error) {
+	out := new(TraverseResponse)
+	err := c.cc.Invoke(ctx, "/kg.KgService/Traverse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kgServiceClient) ShortestPath(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*ShortestPathResponse, error) {
+	out := new(ShortestPathResponse)
+	err := c.cc.Invoke(ctx, "/kg.KgService/ShortestPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kgServiceClient) AllPaths(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*AllPathsResponse, error) {
+	out := new(AllPathsResponse)
+	err := c.cc.Invoke(ctx, "/kg.KgService/AllPaths", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kgServiceClient) ExportGraph(ctx context.Context, in *ExportGraphRequest, opts ...grpc.CallOption) (KgService_ExportGraphClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_KgService_serviceDesc.Streams[0], "/kg.KgService/ExportGraph", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &kgServiceExportGraphClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type KgService_ExportGraphClient interface {
+	Recv() (*ExportGraphChunk, error)
+	grpc.ClientStream
+}
+
+type kgServiceExportGraphClient struct {
+	grpc.ClientStream
+}
+
+func (x *kgServiceExportGraphClient) Recv() (*ExportGraphChunk, error) {
+	m := new(ExportGraphChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *kgServiceClient) ImportGraph(ctx context.Context, opts ...grpc.CallOption) (KgService_ImportGraphClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_KgService_serviceDesc.Streams[1], "/kg.KgService/ImportGraph", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &kgServiceImportGraphClient{stream}, nil
+}
+
+type KgService_ImportGraphClient interface {
+	Send(*ImportGraphRequest) error
+	CloseAndRecv() (*ImportGraphResponse, error)
+	grpc.ClientStream
+}
+
+type kgServiceImportGraphClient struct {
+	grpc.ClientStream
+}
+
+func (x *kgServiceImportGraphClient) Send(m *ImportGraphRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *kgServiceImportGraphClient) CloseAndRecv() (*ImportGraphResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportGraphResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API
 type KgServiceServer interface {
 	UpsertNode(context.Context, *UpsertNodeRequest) (*UpsertNodeResponse, error)
 	UpsertEdge(context.Context, *UpsertEdgeRequest) (*UpsertEdgeResponse, error)
 	GetNode(context.Context, *GetNodeRequest) (*GetNodeResponse, error)
+	DeleteNode(context.Context, *DeleteNodeRequest) (*DeleteNodeResponse, error)
+	DeleteEdge(context.Context, *DeleteEdgeRequest) (*DeleteEdgeResponse, error)
 	ListEntities(context.Context, *ListEntitiesRequest) (*ListEntitiesResponse, error)
 	ListEdges(context.Context, *ListEdgesRequest) (*ListEdgesResponse, error)
 	ListNeighbors(context.Context, *ListNeighborsRequest) (*ListNeighborsResponse, error)
+	Traverse(context.Context, *TraverseRequest) (*TraverseResponse, error)
+	ShortestPath(context.Context, *PathRequest) (*ShortestPathResponse, error)
+	AllPaths(context.Context, *PathRequest) (*AllPathsResponse, error)
+	ExportGraph(*ExportGraphRequest, KgService_ExportGraphServer) error
+	ImportGraph(KgService_ImportGraphServer) error
 }
 
 type UnimplementedKgServiceServer struct{}
@@ -178,6 +393,12 @@ func (*UnimplementedKgServiceServer) UpsertEdge(context.Context, *UpsertEdgeRequ
 func (*UnimplementedKgServiceServer) GetNode(context.Context, *GetNodeRequest) (*GetNodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNode not implemented")
 }
+func (*UnimplementedKgServiceServer) DeleteNode(context.Context, *DeleteNodeRequest) (*DeleteNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteNode not implemented")
+}
+func (*UnimplementedKgServiceServer) DeleteEdge(context.Context, *DeleteEdgeRequest) (*DeleteEdgeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEdge not implemented")
+}
 func (*UnimplementedKgServiceServer) ListEntities(context.Context, *ListEntitiesRequest) (*ListEntitiesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListEntities not implemented")
 }
@@ -187,6 +408,21 @@ func (*UnimplementedKgServiceServer) ListEdges(context.Context, *ListEdgesReques
 func (*UnimplementedKgServiceServer) ListNeighbors(context.Context, *ListNeighborsRequest) (*ListNeighborsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListNeighbors not implemented")
 }
+func (*UnimplementedKgServiceServer) Traverse(context.Context, *TraverseRequest) (*TraverseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Traverse not implemented")
+}
+func (*UnimplementedKgServiceServer) ShortestPath(context.Context, *PathRequest) (*ShortestPathResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShortestPath not implemented")
+}
+func (*UnimplementedKgServiceServer) AllPaths(context.Context, *PathRequest) (*AllPathsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AllPaths not implemented")
+}
+func (*UnimplementedKgServiceServer) ExportGraph(*ExportGraphRequest, KgService_ExportGraphServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportGraph not implemented")
+}
+func (*UnimplementedKgServiceServer) ImportGraph(KgService_ImportGraphServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportGraph not implemented")
+}
 
 func RegisterKgServiceServer(s *grpc.Server, srv KgServiceServer) {
 	s.RegisterService(&_KgService_serviceDesc, srv)
@@ -246,6 +482,42 @@ func _KgService_GetNode_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KgService_DeleteNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KgServiceServer).DeleteNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kg.KgService/DeleteNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KgServiceServer).DeleteNode(ctx, req.(*DeleteNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KgService_DeleteEdge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteEdgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KgServiceServer).DeleteEdge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kg.KgService/DeleteEdge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KgServiceServer).DeleteEdge(ctx, req.(*DeleteEdgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _KgService_ListEntities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListEntitiesRequest)
 	if err := dec(in); err != nil {
@@ -300,6 +572,107 @@ func _KgService_ListNeighbors_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KgService_Traverse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraverseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KgServiceServer).Traverse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kg.KgService/Traverse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KgServiceServer).Traverse(ctx, req.(*TraverseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KgService_ShortestPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KgServiceServer).ShortestPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kg.KgService/ShortestPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KgServiceServer).ShortestPath(ctx, req.(*PathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KgService_AllPaths_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KgServiceServer).AllPaths(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kg.KgService/AllPaths",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KgServiceServer).AllPaths(ctx, req.(*PathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KgService_ExportGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportGraphRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KgServiceServer).ExportGraph(m, &kgServiceExportGraphServer{stream})
+}
+
+type KgService_ExportGraphServer interface {
+	Send(*ExportGraphChunk) error
+	grpc.ServerStream
+}
+
+type kgServiceExportGraphServer struct {
+	grpc.ServerStream
+}
+
+func (x *kgServiceExportGraphServer) Send(m *ExportGraphChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _KgService_ImportGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(KgServiceServer).ImportGraph(&kgServiceImportGraphServer{stream})
+}
+
+type KgService_ImportGraphServer interface {
+	SendAndClose(*ImportGraphResponse) error
+	Recv() (*ImportGraphRequest, error)
+	grpc.ServerStream
+}
+
+type kgServiceImportGraphServer struct {
+	grpc.ServerStream
+}
+
+func (x *kgServiceImportGraphServer) SendAndClose(m *ImportGraphResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *kgServiceImportGraphServer) Recv() (*ImportGraphRequest, error) {
+	m := new(ImportGraphRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _KgService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "kg.KgService",
 	HandlerType: (*KgServiceServer)(nil),
@@ -307,11 +680,19 @@ var _KgService_serviceDesc = grpc.ServiceDesc{
 		{MethodName: "UpsertNode", Handler: _KgService_UpsertNode_Handler},
 		{MethodName: "UpsertEdge", Handler: _KgService_UpsertEdge_Handler},
 		{MethodName: "GetNode", Handler: _KgService_GetNode_Handler},
+		{MethodName: "DeleteNode", Handler: _KgService_DeleteNode_Handler},
+		{MethodName: "DeleteEdge", Handler: _KgService_DeleteEdge_Handler},
 		{MethodName: "ListEntities", Handler: _KgService_ListEntities_Handler},
 		{MethodName: "ListEdges", Handler: _KgService_ListEdges_Handler},
 		{MethodName: "ListNeighbors", Handler: _KgService_ListNeighbors_Handler},
+		{MethodName: "Traverse", Handler: _KgService_Traverse_Handler},
+		{MethodName: "ShortestPath", Handler: _KgService_ShortestPath_Handler},
+		{MethodName: "AllPaths", Handler: _KgService_AllPaths_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "ExportGraph", Handler: _KgService_ExportGraph_Handler, ServerStreams: true},
+		{StreamName: "ImportGraph", Handler: _KgService_ImportGraph_Handler, ClientStreams: true},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "kg.proto",
 }
 
@@ -0,0 +1,177 @@
+package kgtransfer
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	kgpb "github.com/nucleus/ucl-core/pkg/kgpb"
+)
+
+// GraphML attribute keys. The full property map travels as JSON in the
+// "properties" key so imports are lossless; "label" duplicates displayName
+// so Gephi shows something useful out of the box.
+const (
+	graphMLKeyType  = "type"
+	graphMLKeyLabel = "label"
+	graphMLKeyProps = "properties"
+)
+
+const graphMLHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="type" for="all" attr.name="type" attr.type="string"/>
+  <key id="label" for="node" attr.name="label" attr.type="string"/>
+  <key id="properties" for="all" attr.name="properties" attr.type="string"/>
+  <graph id="G" edgedefault="directed">
+`
+
+const graphMLFooter = `  </graph>
+</graphml>
+`
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	XMLName xml.Name      `xml:"node"`
+	ID      string        `xml:"id,attr"`
+	Data    []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	XMLName xml.Name      `xml:"edge"`
+	ID      string        `xml:"id,attr"`
+	Source  string        `xml:"source,attr"`
+	Target  string        `xml:"target,attr"`
+	Data    []graphMLData `xml:"data"`
+}
+
+type graphMLWriter struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+func newGraphMLWriter(w io.Writer) (*graphMLWriter, error) {
+	if _, err := io.WriteString(w, graphMLHeader); err != nil {
+		return nil, err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("    ", "  ")
+	return &graphMLWriter{w: w, enc: enc}, nil
+}
+
+func (w *graphMLWriter) WriteNode(n *kgpb.Node) error {
+	props, err := propsJSON(n.Properties)
+	if err != nil {
+		return err
+	}
+	data := []graphMLData{{Key: graphMLKeyType, Value: n.Type}}
+	if label := n.Properties["displayName"]; label != "" {
+		data = append(data, graphMLData{Key: graphMLKeyLabel, Value: label})
+	}
+	data = append(data, graphMLData{Key: graphMLKeyProps, Value: props})
+	return w.encode(graphMLNode{ID: n.Id, Data: data})
+}
+
+func (w *graphMLWriter) WriteEdge(e *kgpb.Edge) error {
+	props, err := propsJSON(e.Properties)
+	if err != nil {
+		return err
+	}
+	return w.encode(graphMLEdge{
+		ID:     e.Id,
+		Source: e.FromId,
+		Target: e.ToId,
+		Data:   []graphMLData{{Key: graphMLKeyType, Value: e.Type}, {Key: graphMLKeyProps, Value: props}},
+	})
+}
+
+func (w *graphMLWriter) encode(v any) error {
+	if err := w.enc.Encode(v); err != nil {
+		return err
+	}
+	if err := w.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.w, "\n")
+	return err
+}
+
+func (w *graphMLWriter) Close() error {
+	_, err := io.WriteString(w.w, graphMLFooter)
+	return err
+}
+
+func readGraphML(r io.Reader, onNode func(*kgpb.Node) error, onEdge func(*kgpb.Edge) error) error {
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "node":
+			var gn graphMLNode
+			if err := dec.DecodeElement(&gn, &start); err != nil {
+				return err
+			}
+			node := &kgpb.Node{Id: gn.ID}
+			if err := applyGraphMLData(gn.Data, &node.Type, &node.Properties); err != nil {
+				return fmt.Errorf("node %s: %w", gn.ID, err)
+			}
+			if err := onNode(node); err != nil {
+				return err
+			}
+		case "edge":
+			var ge graphMLEdge
+			if err := dec.DecodeElement(&ge, &start); err != nil {
+				return err
+			}
+			edge := &kgpb.Edge{Id: ge.ID, FromId: ge.Source, ToId: ge.Target}
+			if err := applyGraphMLData(ge.Data, &edge.Type, &edge.Properties); err != nil {
+				return fmt.Errorf("edge %s: %w", ge.ID, err)
+			}
+			if err := onEdge(edge); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func applyGraphMLData(data []graphMLData, typ *string, props *map[string]string) error {
+	for _, d := range data {
+		switch d.Key {
+		case graphMLKeyType:
+			*typ = d.Value
+		case graphMLKeyProps:
+			if d.Value == "" {
+				continue
+			}
+			if err := json.Unmarshal([]byte(d.Value), props); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func propsJSON(m map[string]string) (string, error) {
+	if len(m) == 0 {
+		return "{}", nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,68 @@
+package kgtransfer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	kgpb "github.com/nucleus/ucl-core/pkg/kgpb"
+)
+
+// jsonlRecord is one line of the JSONL format.
+type jsonlRecord struct {
+	Kind       string            `json:"kind"`
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	FromID     string            `json:"fromId,omitempty"`
+	ToID       string            `json:"toId,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	return &jsonlWriter{enc: json.NewEncoder(w)}
+}
+
+func (w *jsonlWriter) WriteNode(n *kgpb.Node) error {
+	return w.enc.Encode(jsonlRecord{Kind: "node", ID: n.Id, Type: n.Type, Properties: n.Properties})
+}
+
+func (w *jsonlWriter) WriteEdge(e *kgpb.Edge) error {
+	return w.enc.Encode(jsonlRecord{Kind: "edge", ID: e.Id, Type: e.Type, FromID: e.FromId, ToID: e.ToId, Properties: e.Properties})
+}
+
+func (w *jsonlWriter) Close() error { return nil }
+
+func readJSONL(r io.Reader, onNode func(*kgpb.Node) error, onEdge func(*kgpb.Edge) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		raw := sc.Bytes()
+		if len(raw) == 0 {
+			continue
+		}
+		var rec jsonlRecord
+		if err := json.Unmarshal(raw, &rec); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		var err error
+		switch rec.Kind {
+		case "node":
+			err = onNode(&kgpb.Node{Id: rec.ID, Type: rec.Type, Properties: rec.Properties})
+		case "edge":
+			err = onEdge(&kgpb.Edge{Id: rec.ID, Type: rec.Type, FromId: rec.FromID, ToId: rec.ToID, Properties: rec.Properties})
+		default:
+			err = fmt.Errorf("unknown kind %q", rec.Kind)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return sc.Err()
+}
@@ -0,0 +1,203 @@
+// Package kgtransfer serialises knowledge-graph nodes and edges for moving a
+// tenant graph between environments or into external tools.
+//
+// Supported formats:
+//   - jsonl:   one {"kind":"node"|"edge",...} object per line (export + import)
+//   - graphml: GraphML XML, loadable in Gephi/yEd (export + import)
+//   - cypher:  idempotent MERGE statements for Neo4j (export only)
+//   - csv:     neo4j-admin import CSVs, written as separate node and edge parts (export only)
+//
+// Exports always write every node before any edge so an import can replay
+// the stream in order.
+package kgtransfer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	kgpb "github.com/nucleus/ucl-core/pkg/kgpb"
+)
+
+// Format identifies a serialisation format.
+type Format string
+
+const (
+	FormatJSONL   Format = "jsonl"
+	FormatGraphML Format = "graphml"
+	FormatCypher  Format = "cypher"
+	FormatCSV     Format = "csv"
+)
+
+// Stream part names. Single-stream formats only use PartGraph; CSV writes
+// nodes and edges to separate parts.
+const (
+	PartGraph = "graph"
+	PartNodes = "nodes"
+	PartEdges = "edges"
+)
+
+// ParseFormat normalises a user-supplied format name.
+func ParseFormat(v string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(v))) {
+	case "", FormatJSONL, "json", "ndjson":
+		return FormatJSONL, nil
+	case FormatGraphML, "xml":
+		return FormatGraphML, nil
+	case FormatCypher, "neo4j":
+		return FormatCypher, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", v)
+	}
+}
+
+// Importable reports whether Import supports the format.
+func (f Format) Importable() bool {
+	return f == FormatJSONL || f == FormatGraphML
+}
+
+// Filter scopes an export.
+type Filter struct {
+	TenantID      string
+	ProjectID     string
+	EntityTypes   []string   // restrict nodes (and edges to edges between matching nodes)
+	EdgeTypes     []string   // restrict edges
+	UpdatedAfter  *time.Time // inclusive
+	UpdatedBefore *time.Time // exclusive
+}
+
+// Source streams a filtered graph. Implementations must call fn for nodes
+// and edges in a stable order and stop at the first error returned by fn.
+type Source interface {
+	ScanNodes(ctx context.Context, filter Filter, fn func(*kgpb.Node) error) error
+	ScanEdges(ctx context.Context, filter Filter, fn func(*kgpb.Edge) error) error
+}
+
+// Sink receives imported records. Upserts must be idempotent so the same
+// file can be imported repeatedly. An upsert error fails that record only:
+// Import counts it and carries on.
+type Sink interface {
+	UpsertNode(ctx context.Context, tenantID, projectID string, node *kgpb.Node) error
+	UpsertEdge(ctx context.Context, tenantID, projectID string, edge *kgpb.Edge) error
+}
+
+// Writer encodes a graph stream.
+type Writer interface {
+	WriteNode(node *kgpb.Node) error
+	WriteEdge(edge *kgpb.Edge) error
+	// Close flushes trailers (e.g. closing XML tags). It does not close the
+	// underlying io.Writer.
+	Close() error
+}
+
+// PartWriter returns the destination for a named part.
+type PartWriter func(part string) io.Writer
+
+// NewWriter returns an encoder for format. CSV requests PartNodes and
+// PartEdges from parts; every other format writes to PartGraph.
+func NewWriter(format Format, parts PartWriter) (Writer, error) {
+	switch format {
+	case FormatJSONL:
+		return newJSONLWriter(parts(PartGraph)), nil
+	case FormatGraphML:
+		return newGraphMLWriter(parts(PartGraph))
+	case FormatCypher:
+		return newCypherWriter(parts(PartGraph)), nil
+	case FormatCSV:
+		return newCSVWriter(parts(PartNodes), parts(PartEdges))
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// Stats summarises a transfer. Nodes and Edges count the records written;
+// on import the sink's rejections are counted separately.
+type Stats struct {
+	Nodes       int
+	Edges       int
+	FailedNodes int
+	FailedEdges int
+	Errors      []string // the first maxImportErrors sink errors
+}
+
+// maxImportErrors caps Stats.Errors.
+const maxImportErrors = 20
+
+func (s *Stats) fail(kind, id string, err error) {
+	if len(s.Errors) < maxImportErrors {
+		s.Errors = append(s.Errors, fmt.Sprintf("%s %s: %v", kind, id, err))
+	}
+}
+
+// Export streams the filtered graph from src through a writer for format.
+func Export(ctx context.Context, src Source, filter Filter, format Format, parts PartWriter) (Stats, error) {
+	var stats Stats
+	w, err := NewWriter(format, parts)
+	if err != nil {
+		return stats, err
+	}
+	if err := src.ScanNodes(ctx, filter, func(n *kgpb.Node) error {
+		stats.Nodes++
+		return w.WriteNode(n)
+	}); err != nil {
+		return stats, fmt.Errorf("export nodes: %w", err)
+	}
+	if err := src.ScanEdges(ctx, filter, func(e *kgpb.Edge) error {
+		stats.Edges++
+		return w.WriteEdge(e)
+	}); err != nil {
+		return stats, fmt.Errorf("export edges: %w", err)
+	}
+	return stats, w.Close()
+}
+
+// Import decodes r and upserts every record into sink under tenantID and
+// projectID, in stream order. Malformed input stops the import; records the
+// sink rejects are counted in FailedNodes/FailedEdges.
+func Import(ctx context.Context, r io.Reader, format Format, tenantID, projectID string, sink Sink) (Stats, error) {
+	var stats Stats
+	onNode := func(n *kgpb.Node) error {
+		if n.Id == "" {
+			return fmt.Errorf("node without id")
+		}
+		if err := sink.UpsertNode(ctx, tenantID, projectID, n); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			stats.FailedNodes++
+			stats.fail("node", n.Id, err)
+			return nil
+		}
+		stats.Nodes++
+		return nil
+	}
+	onEdge := func(e *kgpb.Edge) error {
+		if e.Id == "" || e.FromId == "" || e.ToId == "" {
+			return fmt.Errorf("edge %q missing id or endpoints", e.Id)
+		}
+		if err := sink.UpsertEdge(ctx, tenantID, projectID, e); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			stats.FailedEdges++
+			stats.fail("edge", e.Id, err)
+			return nil
+		}
+		stats.Edges++
+		return nil
+	}
+	var err error
+	switch format {
+	case FormatJSONL:
+		err = readJSONL(r, onNode, onEdge)
+	case FormatGraphML:
+		err = readGraphML(r, onNode, onEdge)
+	default:
+		err = fmt.Errorf("format %q cannot be imported", format)
+	}
+	return stats, err
+}
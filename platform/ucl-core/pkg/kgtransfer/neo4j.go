@@ -0,0 +1,126 @@
+package kgtransfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	kgpb "github.com/nucleus/ucl-core/pkg/kgpb"
+)
+
+// neo4jBaseLabel is applied to every exported node so edges can MATCH on an
+// indexed label regardless of entity type.
+const neo4jBaseLabel = "Entity"
+
+// =============================================================================
+// Cypher
+// =============================================================================
+
+type cypherWriter struct {
+	w io.Writer
+}
+
+func newCypherWriter(w io.Writer) *cypherWriter {
+	return &cypherWriter{w: w}
+}
+
+func (w *cypherWriter) WriteNode(n *kgpb.Node) error {
+	stmt := fmt.Sprintf("MERGE (n:%s {id: %s}) SET n:%s, n.type = %s, n += %s;\n",
+		neo4jBaseLabel, cypherString(n.Id), cypherIdent(n.Type), cypherString(n.Type), cypherMap(n.Properties))
+	_, err := io.WriteString(w.w, stmt)
+	return err
+}
+
+func (w *cypherWriter) WriteEdge(e *kgpb.Edge) error {
+	stmt := fmt.Sprintf("MATCH (a:%[1]s {id: %[2]s}), (b:%[1]s {id: %[3]s}) MERGE (a)-[r:%[4]s {id: %[5]s}]->(b) SET r += %[6]s;\n",
+		neo4jBaseLabel, cypherString(e.FromId), cypherString(e.ToId), cypherIdent(e.Type), cypherString(e.Id), cypherMap(e.Properties))
+	_, err := io.WriteString(w.w, stmt)
+	return err
+}
+
+func (w *cypherWriter) Close() error { return nil }
+
+// cypherIdent backtick-quotes a label or relationship type.
+func cypherIdent(v string) string {
+	if v == "" {
+		v = "UNKNOWN"
+	}
+	return "`" + strings.ReplaceAll(v, "`", "``") + "`"
+}
+
+// cypherString quotes v as a Cypher string literal. JSON string escaping is a
+// subset of what Cypher accepts.
+func cypherString(v string) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func cypherMap(m map[string]string) string {
+	keys := sortedKeys(m)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, cypherIdent(k)+": "+cypherString(m[k]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// =============================================================================
+// neo4j-admin import CSV
+// =============================================================================
+
+type csvWriter struct {
+	nodes *csv.Writer
+	edges *csv.Writer
+}
+
+func newCSVWriter(nodes, edges io.Writer) (*csvWriter, error) {
+	w := &csvWriter{nodes: csv.NewWriter(nodes), edges: csv.NewWriter(edges)}
+	if err := w.nodes.Write([]string{"id:ID", ":LABEL", "type", "displayName", "properties"}); err != nil {
+		return nil, err
+	}
+	if err := w.edges.Write([]string{"id", ":START_ID", ":END_ID", ":TYPE", "properties"}); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *csvWriter) WriteNode(n *kgpb.Node) error {
+	props, err := propsJSON(n.Properties)
+	if err != nil {
+		return err
+	}
+	labels := neo4jBaseLabel
+	if n.Type != "" {
+		labels += ";" + n.Type
+	}
+	return w.nodes.Write([]string{n.Id, labels, n.Type, n.Properties["displayName"], props})
+}
+
+func (w *csvWriter) WriteEdge(e *kgpb.Edge) error {
+	props, err := propsJSON(e.Properties)
+	if err != nil {
+		return err
+	}
+	return w.edges.Write([]string{e.Id, e.FromId, e.ToId, e.Type, props})
+}
+
+func (w *csvWriter) Close() error {
+	w.nodes.Flush()
+	w.edges.Flush()
+	if err := w.nodes.Error(); err != nil {
+		return err
+	}
+	return w.edges.Error()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
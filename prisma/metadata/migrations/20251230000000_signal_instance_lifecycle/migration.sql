@@ -0,0 +1,44 @@
+-- Signal instance lifecycle: acknowledge, snooze, reopen and history
+
+ALTER TYPE "SignalInstanceStatus" ADD VALUE IF NOT EXISTS 'ACKNOWLEDGED';
+ALTER TYPE "SignalInstanceStatus" ADD VALUE IF NOT EXISTS 'SNOOZED';
+ALTER TYPE "SignalInstanceStatus" ADD VALUE IF NOT EXISTS 'REOPENED';
+
+ALTER TABLE "signal_instances"
+  ADD COLUMN IF NOT EXISTS "snoozed_until" TIMESTAMPTZ NULL,
+  ADD COLUMN IF NOT EXISTS "acknowledged_by" TEXT NULL;
+
+-- Append-only: rows are written by the signal store and never updated.
+CREATE TABLE IF NOT EXISTS "signal_instance_history" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "seq" BIGSERIAL NOT NULL,
+  "instance_id" UUID NOT NULL REFERENCES "signal_instances" ("id") ON DELETE CASCADE,
+  "definition_id" UUID NOT NULL,
+  "entity_ref" TEXT NOT NULL,
+  "from_status" "SignalInstanceStatus" NULL,
+  "to_status" "SignalInstanceStatus" NOT NULL,
+  "actor" TEXT NULL,
+  "reason" TEXT NULL,
+  "source_run_id" TEXT NULL,
+  "snoozed_until" TIMESTAMPTZ NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS "idx_signal_instance_history_instance" ON "signal_instance_history" ("instance_id", "created_at");
+CREATE INDEX IF NOT EXISTS "idx_signal_instance_history_definition" ON "signal_instance_history" ("definition_id", "entity_ref", "created_at");
+
+CREATE OR REPLACE FUNCTION signal_instance_history_immutable() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'signal_instance_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS "signal_instance_history_no_update" ON "signal_instance_history";
+CREATE TRIGGER "signal_instance_history_no_update"
+  BEFORE UPDATE ON "signal_instance_history"
+  FOR EACH ROW EXECUTE FUNCTION signal_instance_history_immutable();
+
+-- Backfill the creation of existing instances so every instance has a history.
+INSERT INTO "signal_instance_history" ("instance_id", "definition_id", "entity_ref", "from_status", "to_status", "actor", "reason", "source_run_id", "created_at")
+SELECT "id", "definition_id", "entity_ref", NULL, "status", 'system', 'backfill', "source_run_id", "first_seen_at"
+FROM "signal_instances";
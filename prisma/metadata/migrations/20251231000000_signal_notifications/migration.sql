@@ -0,0 +1,42 @@
+-- Signal notifications: routing rules and a delivery outbox
+
+ALTER TABLE "signal_instances"
+  ADD COLUMN IF NOT EXISTS "tenant_id" TEXT NULL,
+  ADD COLUMN IF NOT EXISTS "project_id" TEXT NULL;
+
+CREATE TABLE IF NOT EXISTS "signal_notification_rules" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "name" TEXT NULL,
+  "definition_id" UUID NULL REFERENCES "signal_definitions" ("id") ON DELETE CASCADE,
+  "min_severity" "SignalSeverity" NOT NULL DEFAULT 'ERROR',
+  "tenant_id" TEXT NULL,
+  "project_id" TEXT NULL,
+  "events" TEXT[] NOT NULL DEFAULT ARRAY['opened', 'reopened', 'escalated']::TEXT[],
+  "sink" TEXT NOT NULL,
+  "target" TEXT NOT NULL,
+  "rate_limit_per_hour" INTEGER NOT NULL DEFAULT 0,
+  "enabled" BOOLEAN NOT NULL DEFAULT TRUE,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT "signal_notification_rules_sink_check" CHECK ("sink" IN ('webhook', 'slack', 'smtp'))
+);
+
+-- One row per (rule, instance history entry): an instance notifies once per transition.
+CREATE TABLE IF NOT EXISTS "signal_notification_outbox" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "rule_id" UUID NOT NULL REFERENCES "signal_notification_rules" ("id") ON DELETE CASCADE,
+  "event_id" UUID NOT NULL REFERENCES "signal_instance_history" ("id") ON DELETE CASCADE,
+  "instance_id" UUID NOT NULL,
+  "payload" JSONB NOT NULL,
+  "status" TEXT NOT NULL DEFAULT 'PENDING',
+  "attempts" INTEGER NOT NULL DEFAULT 0,
+  "next_attempt_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  "last_error" TEXT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  "sent_at" TIMESTAMPTZ NULL,
+  CONSTRAINT "signal_notification_outbox_status_check" CHECK ("status" IN ('PENDING', 'SENT', 'RATE_LIMITED', 'DEAD'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_signal_notification_outbox_rule_event" ON "signal_notification_outbox" ("rule_id", "event_id");
+CREATE INDEX IF NOT EXISTS "idx_signal_notification_outbox_due" ON "signal_notification_outbox" ("next_attempt_at") WHERE "status" = 'PENDING';
+CREATE INDEX IF NOT EXISTS "idx_signal_notification_outbox_rule_sent" ON "signal_notification_outbox" ("rule_id", "sent_at") WHERE "status" = 'SENT';